@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"os"
@@ -85,6 +86,9 @@ func action(ctx *cli.Context) error {
 	}()
 
 	observability.Server(ctx, db)
+	observability.AddReadinessCheck("redis", func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	})
 
 	// Catch CTRL-C to ensure a graceful shutdown.
 	interrupt := make(chan os.Signal, 1)
@@ -27,7 +27,7 @@ func init() {
 	app.Name = "Scroll Bridge History API Message Fetcher"
 	app.Usage = "The Scroll Bridge History API Message Fetcher"
 	app.Flags = append(app.Flags, utils.CommonFlags...)
-	app.Commands = []*cli.Command{}
+	app.Commands = []*cli.Command{backfillCommand}
 
 	app.Before = func(ctx *cli.Context) error {
 		return utils.LogSetup(ctx)
@@ -67,6 +67,8 @@ func action(ctx *cli.Context) error {
 	}
 
 	observability.Server(ctx, db)
+	observability.AddReadinessCheck("l1geth", observability.BlockNumberCheck(l1Client))
+	observability.AddReadinessCheck("l2geth", observability.BlockNumberCheck(l2Client))
 
 	l1MessageFetcher := fetcher.NewL1MessageFetcher(subCtx, cfg.L1, db, l1Client)
 	go l1MessageFetcher.Start()
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/bridge-history-api/internal/config"
+	"scroll-tech/bridge-history-api/internal/controller/fetcher"
+)
+
+var (
+	backfillLayerFlag = cli.StringFlag{
+		Name:     "layer",
+		Usage:    "The layer to backfill, l1 or l2",
+		Required: true,
+	}
+	backfillFromFlag = cli.Uint64Flag{
+		Name:     "from",
+		Usage:    "The first block of the range to backfill",
+		Required: true,
+	}
+	backfillToFlag = cli.Uint64Flag{
+		Name:     "to",
+		Usage:    "The last block of the range to backfill",
+		Required: true,
+	}
+)
+
+var backfillCommand = &cli.Command{
+	Name:   "backfill",
+	Usage:  "Re-scan a block range for the missed deposit, withdrawal and batch events, and reconcile their statuses.",
+	Action: backfill,
+	Flags:  []cli.Flag{&backfillLayerFlag, &backfillFromFlag, &backfillToFlag},
+}
+
+func backfill(ctx *cli.Context) error {
+	from, to := ctx.Uint64(backfillFromFlag.Name), ctx.Uint64(backfillToFlag.Name)
+	if from == 0 || from > to {
+		return fmt.Errorf("invalid block range, from: %v, to: %v", from, to)
+	}
+
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config file, config file: %v, err: %w", cfgFile, err)
+	}
+
+	db, err := database.InitDB(cfg.DB)
+	if err != nil {
+		return fmt.Errorf("failed to init db, err: %w", err)
+	}
+	defer func() {
+		if deferErr := database.CloseDB(db); deferErr != nil {
+			log.Error("failed to close db", "err", deferErr)
+		}
+	}()
+
+	layer := ctx.String(backfillLayerFlag.Name)
+	switch layer {
+	case "l1":
+		l1Client, dialErr := ethclient.Dial(cfg.L1.Endpoint)
+		if dialErr != nil {
+			return fmt.Errorf("failed to connect to L1 geth, endpoint: %v, err: %w", cfg.L1.Endpoint, dialErr)
+		}
+		if err = fetcher.NewL1MessageFetcher(ctx.Context, cfg.L1, db, l1Client).Backfill(from, to); err != nil {
+			return err
+		}
+	case "l2":
+		l2Client, dialErr := ethclient.Dial(cfg.L2.Endpoint)
+		if dialErr != nil {
+			return fmt.Errorf("failed to connect to L2 geth, endpoint: %v, err: %w", cfg.L2.Endpoint, dialErr)
+		}
+		if err = fetcher.NewL2MessageFetcher(ctx.Context, cfg.L2, db, l2Client).Backfill(from, to); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported layer: %v", layer)
+	}
+
+	log.Info("backfill finished", "layer", layer, "from", from, "to", to)
+	return nil
+}
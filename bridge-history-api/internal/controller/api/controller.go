@@ -14,12 +14,21 @@ var (
 	// TxsByHashesCtl the TxsByHashesController instance
 	TxsByHashesCtl *TxsByHashesController
 
+	// L1DepositsByAddressCtl the L1DepositsByAddressController instance
+	L1DepositsByAddressCtl *L1DepositsByAddressController
+
 	// L2UnclaimedWithdrawalsByAddressCtl the L2UnclaimedWithdrawalsByAddressController instance
 	L2UnclaimedWithdrawalsByAddressCtl *L2UnclaimedWithdrawalsByAddressController
 
 	// L2WithdrawalsByAddressCtl the L2WithdrawalsByAddressController instance
 	L2WithdrawalsByAddressCtl *L2WithdrawalsByAddressController
 
+	// L2WithdrawalProofCtl the L2WithdrawalProofController instance
+	L2WithdrawalProofCtl *L2WithdrawalProofController
+
+	// MessageStatusByHashCtl the MessageStatusByHashController instance
+	MessageStatusByHashCtl *MessageStatusByHashController
+
 	initControllerOnce sync.Once
 )
 
@@ -28,7 +37,10 @@ func InitController(db *gorm.DB, redis *redis.Client) {
 	initControllerOnce.Do(func() {
 		TxsByAddressCtl = NewTxsByAddressController(db, redis)
 		TxsByHashesCtl = NewTxsByHashesController(db, redis)
+		L1DepositsByAddressCtl = NewL1DepositsByAddressController(db, redis)
 		L2UnclaimedWithdrawalsByAddressCtl = NewL2UnclaimedWithdrawalsByAddressController(db, redis)
 		L2WithdrawalsByAddressCtl = NewL2WithdrawalsByAddressController(db, redis)
+		L2WithdrawalProofCtl = NewL2WithdrawalProofController(db, redis)
+		MessageStatusByHashCtl = NewMessageStatusByHashController(db, redis)
 	})
 }
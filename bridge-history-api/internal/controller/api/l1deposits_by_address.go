@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+// L1DepositsByAddressController the controller of GetL1DepositsByAddress
+type L1DepositsByAddressController struct {
+	historyLogic *logic.HistoryLogic
+}
+
+// NewL1DepositsByAddressController create new L1DepositsByAddressController
+func NewL1DepositsByAddressController(db *gorm.DB, redisClient *redis.Client) *L1DepositsByAddressController {
+	return &L1DepositsByAddressController{
+		historyLogic: logic.NewHistoryLogic(db, redisClient),
+	}
+}
+
+// GetL1DepositsByAddress defines the http get method behavior
+func (c *L1DepositsByAddressController) GetL1DepositsByAddress(ctx *gin.Context) {
+	var req types.QueryByAddressRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	pagedTxs, total, err := c.historyLogic.GetL1DepositsByAddress(ctx, req.Address, req.Page, req.PageSize)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrGetL1DepositsError, err)
+		return
+	}
+
+	resultData := &types.ResultData{Results: pagedTxs, Total: total}
+	types.RenderSuccess(ctx, resultData)
+}
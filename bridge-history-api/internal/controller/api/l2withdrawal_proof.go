@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+// L2WithdrawalProofController the controller of GetL2WithdrawalProof
+type L2WithdrawalProofController struct {
+	historyLogic *logic.HistoryLogic
+}
+
+// NewL2WithdrawalProofController create new L2WithdrawalProofController
+func NewL2WithdrawalProofController(db *gorm.DB, redisClient *redis.Client) *L2WithdrawalProofController {
+	return &L2WithdrawalProofController{
+		historyLogic: logic.NewHistoryLogic(db, redisClient),
+	}
+}
+
+// GetL2WithdrawalProof defines the http get method behavior
+func (c *L2WithdrawalProofController) GetL2WithdrawalProof(ctx *gin.Context) {
+	var req types.QueryWithdrawalProofRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	result, err := c.historyLogic.GetL2WithdrawalProof(ctx, req.MessageHash)
+	if errors.Is(err, logic.ErrL2WithdrawalNotFound) {
+		types.RenderFailure(ctx, types.ErrWithdrawalNotFound, err)
+		return
+	}
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrGetWithdrawalProofError, err)
+		return
+	}
+
+	types.RenderSuccess(ctx, result)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+
+	"scroll-tech/bridge-history-api/internal/logic"
+	"scroll-tech/bridge-history-api/internal/types"
+)
+
+// MessageStatusByHashController the controller of GetMessageStatusByHash
+type MessageStatusByHashController struct {
+	historyLogic *logic.HistoryLogic
+}
+
+// NewMessageStatusByHashController create new MessageStatusByHashController
+func NewMessageStatusByHashController(db *gorm.DB, redisClient *redis.Client) *MessageStatusByHashController {
+	return &MessageStatusByHashController{
+		historyLogic: logic.NewHistoryLogic(db, redisClient),
+	}
+}
+
+// GetMessageStatusByHash defines the http get method behavior
+func (c *MessageStatusByHashController) GetMessageStatusByHash(ctx *gin.Context) {
+	var req types.QueryMessageStatusRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		types.RenderFailure(ctx, types.ErrParameterInvalidNo, err)
+		return
+	}
+
+	results, err := c.historyLogic.GetMessageStatusByTxHash(ctx, req.TxHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrGetMessageStatusError, err)
+		return
+	}
+
+	resultData := &types.MessageStatusResultData{Results: results, Total: uint64(len(results))}
+	types.RenderSuccess(ctx, resultData)
+}
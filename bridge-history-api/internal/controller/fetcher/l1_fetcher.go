@@ -2,6 +2,7 @@ package fetcher
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -158,6 +159,50 @@ func (c *L1MessageFetcher) fetchAndSaveEvents(confirmation uint64) {
 	}
 }
 
+// Backfill re-scans the L1 events of the closed block range and upserts them, the sync height of the fetcher is left untouched.
+func (c *L1MessageFetcher) Backfill(startHeight, endHeight uint64) error {
+	if startHeight == 0 || startHeight > endHeight {
+		return fmt.Errorf("invalid backfill range, start height: %v, end height: %v", startHeight, endHeight)
+	}
+	// the blocks not confirmed yet are left to the fetcher, a reorg of them would be saved by the backfill.
+	confirmedHeight, err := utils.GetBlockNumber(c.ctx, c.client, c.cfg.Confirmation)
+	if err != nil {
+		return fmt.Errorf("failed to get L1 block number, confirmation: %v, err: %w", c.cfg.Confirmation, err)
+	}
+	if endHeight > confirmedHeight {
+		return fmt.Errorf("invalid backfill range, end height: %v is beyond the confirmed height: %v", endHeight, confirmedHeight)
+	}
+
+	parent, err := c.client.HeaderByNumber(c.ctx, new(big.Int).SetUint64(startHeight-1))
+	if err != nil {
+		return fmt.Errorf("failed to get L1 header by number, block number: %v, err: %w", startHeight-1, err)
+	}
+	lastBlockHash := parent.Hash()
+
+	for from := startHeight; from <= endHeight; from += c.cfg.FetchLimit {
+		to := from + c.cfg.FetchLimit - 1
+		if to > endHeight {
+			to = endHeight
+		}
+
+		isReorg, _, blockHash, l1FetcherResult, fetcherErr := c.l1FetcherLogic.L1Fetcher(c.ctx, from, to, lastBlockHash)
+		if fetcherErr != nil {
+			return fmt.Errorf("failed to fetch L1 events, from: %v, to: %v, err: %w", from, to, fetcherErr)
+		}
+		if isReorg {
+			return fmt.Errorf("L1 reorg happened during backfill, from: %v, to: %v, backfill a range deeper than the confirmation", from, to)
+		}
+
+		if insertUpdateErr := c.eventUpdateLogic.L1InsertOrUpdate(c.ctx, l1FetcherResult); insertUpdateErr != nil {
+			return fmt.Errorf("failed to save L1 events, from: %v, to: %v, err: %w", from, to, insertUpdateErr)
+		}
+
+		log.Info("backfilled L1 events", "from", from, "to", to)
+		lastBlockHash = blockHash
+	}
+	return nil
+}
+
 func (c *L1MessageFetcher) updateL1SyncHeight(height uint64, blockHash common.Hash) {
 	c.l1MessageFetcherSyncHeight.Set(float64(height))
 	c.l1LastSyncBlockHash = blockHash
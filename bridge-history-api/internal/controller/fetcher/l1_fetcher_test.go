@@ -0,0 +1,118 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/bridge-history-api/internal/config"
+)
+
+// testChain serves the blocks of a chain without transactions nor events over the eth json-rpc api.
+type testChain struct {
+	mu        sync.Mutex
+	headers   []*types.Header
+	logRanges [][2]uint64
+}
+
+func newTestChain(length int) *testChain {
+	c := &testChain{}
+	for i := 0; i < length; i++ {
+		header := &types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1),
+			UncleHash:  types.EmptyUncleHash,
+			TxHash:     types.EmptyRootHash,
+		}
+		if i > 0 {
+			header.ParentHash = c.headers[i-1].Hash()
+		}
+		c.headers = append(c.headers, header)
+	}
+	return c
+}
+
+func (c *testChain) BlockNumber() hexutil.Uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return hexutil.Uint64(len(c.headers) - 1)
+}
+
+func (c *testChain) GetBlockByNumber(number rpc.BlockNumber, _ bool) (map[string]interface{}, error) {
+	c.mu.Lock()
+	header := c.headers[number]
+	c.mu.Unlock()
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	var block map[string]interface{}
+	if err = json.Unmarshal(data, &block); err != nil {
+		return nil, err
+	}
+	block["hash"] = header.Hash()
+	block["transactions"] = []interface{}{}
+	block["uncles"] = []interface{}{}
+	return block, nil
+}
+
+func (c *testChain) GetLogs(query map[string]interface{}) ([]types.Log, error) {
+	from, err := hexutil.DecodeUint64(query["fromBlock"].(string))
+	if err != nil {
+		return nil, err
+	}
+	to, err := hexutil.DecodeUint64(query["toBlock"].(string))
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logRanges = append(c.logRanges, [2]uint64{from, to})
+	return []types.Log{}, nil
+}
+
+func TestL1MessageFetcherBackfill(t *testing.T) {
+	chain := newTestChain(31)
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", chain))
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	client, err := ethclient.Dial(httpServer.URL)
+	assert.NoError(t, err)
+
+	// no event is fetched, the database is not used.
+	c := NewL1MessageFetcher(context.Background(), &config.FetcherConfig{Confirmation: 10, FetchLimit: 4}, nil, client)
+
+	// the range is checked before anything is fetched.
+	assert.ErrorContains(t, c.Backfill(0, 5), "invalid backfill range")
+	assert.ErrorContains(t, c.Backfill(6, 5), "invalid backfill range")
+	// the head is 30, so the confirmed height is 20.
+	assert.ErrorContains(t, c.Backfill(15, 21), "beyond the confirmed height: 20")
+	assert.Empty(t, chain.logRanges)
+
+	// the range is fetched by the fetch limit.
+	assert.NoError(t, c.Backfill(5, 14))
+	assert.Equal(t, [][2]uint64{{5, 8}, {9, 12}, {13, 14}}, chain.logRanges)
+	assert.NoError(t, c.Backfill(20, 20))
+	assert.Equal(t, [2]uint64{20, 20}, chain.logRanges[3])
+
+	// a reorg within the range fails the backfill, nothing of the range is saved from there.
+	fork := types.CopyHeader(chain.headers[12])
+	fork.Extra = []byte("fork")
+	chain.mu.Lock()
+	chain.headers[12] = fork
+	chain.mu.Unlock()
+	chain.logRanges = nil
+	assert.ErrorContains(t, c.Backfill(9, 16), "L1 reorg happened during backfill, from: 13, to: 16")
+	assert.Equal(t, [][2]uint64{{9, 12}}, chain.logRanges)
+}
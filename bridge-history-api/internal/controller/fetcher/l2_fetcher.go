@@ -2,6 +2,7 @@ package fetcher
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -156,6 +157,59 @@ func (c *L2MessageFetcher) fetchAndSaveEvents(confirmation uint64) {
 	}
 }
 
+// Backfill re-scans the L2 events of the closed block range and upserts them, then reconciles the rollup status
+// of the withdrawals and the bridge batch deposits, the sync height of the fetcher is left untouched.
+func (c *L2MessageFetcher) Backfill(startHeight, endHeight uint64) error {
+	if startHeight == 0 || startHeight > endHeight {
+		return fmt.Errorf("invalid backfill range, start height: %v, end height: %v", startHeight, endHeight)
+	}
+	// the blocks not confirmed yet are left to the fetcher, a reorg of them would be saved by the backfill.
+	confirmedHeight, err := utils.GetBlockNumber(c.ctx, c.client, c.cfg.Confirmation)
+	if err != nil {
+		return fmt.Errorf("failed to get L2 block number, confirmation: %v, err: %w", c.cfg.Confirmation, err)
+	}
+	if endHeight > confirmedHeight {
+		return fmt.Errorf("invalid backfill range, end height: %v is beyond the confirmed height: %v", endHeight, confirmedHeight)
+	}
+
+	parent, err := c.client.HeaderByNumber(c.ctx, new(big.Int).SetUint64(startHeight-1))
+	if err != nil {
+		return fmt.Errorf("failed to get L2 header by number, block number: %v, err: %w", startHeight-1, err)
+	}
+	lastBlockHash := parent.Hash()
+
+	for from := startHeight; from <= endHeight; from += c.cfg.FetchLimit {
+		to := from + c.cfg.FetchLimit - 1
+		if to > endHeight {
+			to = endHeight
+		}
+
+		isReorg, _, blockHash, l2FetcherResult, fetcherErr := c.l2FetcherLogic.L2Fetcher(c.ctx, from, to, lastBlockHash)
+		if fetcherErr != nil {
+			return fmt.Errorf("failed to fetch L2 events, from: %v, to: %v, err: %w", from, to, fetcherErr)
+		}
+		if isReorg {
+			return fmt.Errorf("L2 reorg happened during backfill, from: %v, to: %v, backfill a range deeper than the confirmation", from, to)
+		}
+
+		if insertUpdateErr := c.eventUpdateLogic.L2InsertOrUpdate(c.ctx, l2FetcherResult); insertUpdateErr != nil {
+			return fmt.Errorf("failed to save L2 events, from: %v, to: %v, err: %w", from, to, insertUpdateErr)
+		}
+
+		if updateErr := c.eventUpdateLogic.UpdateL1BatchIndexAndStatus(c.ctx, to); updateErr != nil {
+			return fmt.Errorf("failed to update L1 batch index and status, from: %v, to: %v, err: %w", from, to, updateErr)
+		}
+
+		if updateErr := c.eventUpdateLogic.UpdateL2BridgeBatchDepositEvent(c.ctx, l2FetcherResult.BridgeBatchDepositMessage); updateErr != nil {
+			return fmt.Errorf("failed to update L2 bridge batch deposit events, from: %v, to: %v, err: %w", from, to, updateErr)
+		}
+
+		log.Info("backfilled L2 events", "from", from, "to", to)
+		lastBlockHash = blockHash
+	}
+	return nil
+}
+
 func (c *L2MessageFetcher) updateL2SyncHeight(height uint64, blockHash common.Hash) {
 	c.l2MessageFetcherSyncHeight.Set(float64(height))
 	c.l2LastSyncBlockHash = blockHash
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
 	"github.com/scroll-tech/go-ethereum/log"
 	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
@@ -29,11 +31,15 @@ const (
 
 	cacheKeyPrefixL2ClaimableWithdrawalsByAddr = cacheKeyPrefixBridgeHistory + "l2ClaimableWithdrawalsByAddr:"
 	cacheKeyPrefixL2WithdrawalsByAddr          = cacheKeyPrefixBridgeHistory + "l2WithdrawalsByAddr:"
+	cacheKeyPrefixL1DepositsByAddr             = cacheKeyPrefixBridgeHistory + "l1DepositsByAddr:"
 	cacheKeyPrefixTxsByAddr                    = cacheKeyPrefixBridgeHistory + "txsByAddr:"
 	cacheKeyPrefixQueryTxsByHashes             = cacheKeyPrefixBridgeHistory + "queryTxsByHashes:"
 	cacheKeyExpiredTime                        = 1 * time.Minute
 )
 
+// ErrL2WithdrawalNotFound is returned when no L2 withdrawal has the message hash, e.g. it's not indexed yet.
+var ErrL2WithdrawalNotFound = errors.New("L2 withdrawal not found")
+
 // HistoryLogic services.
 type HistoryLogic struct {
 	crossMessageOrm       *orm.CrossMessage
@@ -143,6 +149,49 @@ func (h *HistoryLogic) GetL2WithdrawalsByAddress(ctx context.Context, address st
 	return h.processAndCacheTxHistoryInfo(ctx, cacheKey, txHistoryInfos, page, pageSize)
 }
 
+// GetL1DepositsByAddress gets all deposit txs under given address.
+func (h *HistoryLogic) GetL1DepositsByAddress(ctx context.Context, address string, page, pageSize uint64) ([]*types.TxHistoryInfo, uint64, error) {
+	cacheKey := cacheKeyPrefixL1DepositsByAddr + address
+	pagedTxs, total, isHit, err := h.getCachedTxsInfo(ctx, cacheKey, page, pageSize)
+	if err != nil {
+		log.Error("failed to get cached tx info", "cached key", cacheKey, "page", page, "page size", pageSize, "error", err)
+		return nil, 0, err
+	}
+
+	if isHit {
+		h.cacheMetrics.cacheHits.WithLabelValues("GetL1DepositsByAddress").Inc()
+		log.Info("cache hit", "cache key", cacheKey)
+		return pagedTxs, total, nil
+	}
+
+	h.cacheMetrics.cacheMisses.WithLabelValues("GetL1DepositsByAddress").Inc()
+	log.Info("cache miss", "cache key", cacheKey)
+
+	result, err, _ := h.singleFlight.Do(cacheKey, func() (interface{}, error) {
+		var txHistoryInfos []*types.TxHistoryInfo
+		crossMessages, getErr := h.crossMessageOrm.GetL1DepositsByAddress(ctx, address)
+		if getErr != nil {
+			return nil, getErr
+		}
+		for _, message := range crossMessages {
+			txHistoryInfos = append(txHistoryInfos, getTxHistoryInfoFromCrossMessage(message))
+		}
+		return txHistoryInfos, nil
+	})
+	if err != nil {
+		log.Error("failed to get L1 deposits by address", "address", address, "error", err)
+		return nil, 0, err
+	}
+
+	txHistoryInfos, ok := result.([]*types.TxHistoryInfo)
+	if !ok {
+		log.Error("unexpected type", "expected", "[]*types.TxHistoryInfo", "got", reflect.TypeOf(result), "address", address)
+		return nil, 0, errors.New("unexpected error")
+	}
+
+	return h.processAndCacheTxHistoryInfo(ctx, cacheKey, txHistoryInfos, page, pageSize)
+}
+
 // GetTxsByAddress gets tx infos under given address.
 func (h *HistoryLogic) GetTxsByAddress(ctx context.Context, address string, page, pageSize uint64) ([]*types.TxHistoryInfo, uint64, error) {
 	cacheKey := cacheKeyPrefixTxsByAddr + address
@@ -290,6 +339,114 @@ func (h *HistoryLogic) GetTxsByHashes(ctx context.Context, txHashes []string) ([
 	return results, nil
 }
 
+// GetL2WithdrawalProof gets the claim info and the merkle proof of the L2 withdrawal of the message hash.
+func (h *HistoryLogic) GetL2WithdrawalProof(ctx context.Context, messageHash string) (*types.WithdrawalProofInfo, error) {
+	if hash, err := hexutil.Decode(messageHash); err != nil || len(hash) != common.HashLength {
+		return nil, fmt.Errorf("invalid message hash: %v", messageHash)
+	}
+	// the message hashes are stored in lower case hex
+	messageHash = common.HexToHash(messageHash).String()
+
+	message, err := h.crossMessageOrm.GetL2WithdrawalByMessageHash(ctx, messageHash)
+	if err != nil {
+		log.Error("failed to get L2 withdrawal by message hash", "message hash", messageHash, "error", err)
+		return nil, err
+	}
+	if message == nil {
+		return nil, fmt.Errorf("%w, message hash: %v", ErrL2WithdrawalNotFound, messageHash)
+	}
+
+	result := &types.WithdrawalProofInfo{
+		MessageHash: message.MessageHash,
+		ClaimInfo: types.ClaimInfo{
+			From:    message.MessageFrom,
+			To:      message.MessageTo,
+			Value:   message.MessageValue,
+			Nonce:   strconv.FormatUint(message.MessageNonce, 10),
+			Message: message.MessageData,
+		},
+	}
+	// the merkle proof is generated from the withdraw trie when the batch of the withdrawal is finalized.
+	if btypes.RollupStatusType(message.RollupStatus) == btypes.RollupStatusTypeFinalized {
+		result.Proof = types.L2MessageProof{
+			BatchIndex:  strconv.FormatUint(message.BatchIndex, 10),
+			MerkleProof: "0x" + common.Bytes2Hex(message.MerkleProof),
+		}
+		result.Claimable = true
+	}
+	return result, nil
+}
+
+// GetMessageStatusByTxHash gets the lifecycle of the cross messages sent or relayed by the L1 or L2 tx.
+func (h *HistoryLogic) GetMessageStatusByTxHash(ctx context.Context, txHash string) ([]*types.MessageStatusInfo, error) {
+	if hash, err := hexutil.Decode(txHash); err != nil || len(hash) != common.HashLength {
+		return nil, fmt.Errorf("invalid tx hash: %v", txHash)
+	}
+	txHash = common.HexToHash(txHash).String()
+
+	messages, err := h.crossMessageOrm.GetMessagesByTxHashes(ctx, []string{txHash})
+	if err != nil {
+		log.Error("failed to get messages by tx hash", "tx hash", txHash, "error", err)
+		return nil, err
+	}
+
+	results := make([]*types.MessageStatusInfo, 0, len(messages))
+	for _, message := range messages {
+		results = append(results, getMessageStatusInfoFromCrossMessage(message))
+	}
+	return results, nil
+}
+
+func getMessageStatusInfoFromCrossMessage(message *orm.CrossMessage) *types.MessageStatusInfo {
+	info := &types.MessageStatusInfo{
+		MessageHash: message.MessageHash,
+		MessageType: btypes.MessageType(message.MessageType),
+		Status:      messageLifecycle(message),
+		TxStatus:    btypes.TxStatusType(message.TxStatus),
+	}
+	if info.MessageType == btypes.MessageTypeL1SentMessage {
+		info.Hash = message.L1TxHash
+		info.ReplayTxHash = message.L1ReplayTxHash
+		info.RefundTxHash = message.L1RefundTxHash
+		info.CounterpartChainTx = &types.CounterpartChainTx{
+			Hash:        message.L2TxHash,
+			BlockNumber: message.L2BlockNumber,
+		}
+	} else {
+		info.Hash = message.L2TxHash
+		info.BatchIndex = message.BatchIndex
+		info.CounterpartChainTx = &types.CounterpartChainTx{
+			Hash:        message.L1TxHash,
+			BlockNumber: message.L1BlockNumber,
+		}
+	}
+	return info
+}
+
+// messageLifecycle maps the tx status and the rollup status of the message to its lifecycle stage.
+func messageLifecycle(message *orm.CrossMessage) types.MessageLifecycle {
+	switch btypes.TxStatusType(message.TxStatus) {
+	case btypes.TxStatusTypeSentTxReverted:
+		return types.MessageLifecycleReverted
+	case btypes.TxStatusTypeRelayed:
+		return types.MessageLifecycleRelayed
+	case btypes.TxStatusTypeFailedRelayed, btypes.TxStatusTypeRelayTxReverted:
+		return types.MessageLifecycleRelayFailed
+	case btypes.TxStatusTypeSkipped:
+		return types.MessageLifecycleSkipped
+	case btypes.TxStatusTypeDropped:
+		return types.MessageLifecycleDropped
+	}
+
+	if btypes.MessageType(message.MessageType) == btypes.MessageTypeL1SentMessage {
+		return types.MessageLifecycleQueued
+	}
+	if btypes.RollupStatusType(message.RollupStatus) == btypes.RollupStatusTypeFinalized {
+		return types.MessageLifecycleFinalized
+	}
+	return types.MessageLifecycleSent
+}
+
 func getTxHistoryInfoFromCrossMessage(message *orm.CrossMessage) *types.TxHistoryInfo {
 	txHistory := &types.TxHistoryInfo{
 		MessageHash:    message.MessageHash,
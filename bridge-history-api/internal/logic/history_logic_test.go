@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/testcontainers"
+
+	"scroll-tech/bridge-history-api/internal/orm"
+	btypes "scroll-tech/bridge-history-api/internal/types"
+)
+
+func TestMessageLifecycle(t *testing.T) {
+	tests := []struct {
+		messageType  btypes.MessageType
+		txStatus     btypes.TxStatusType
+		rollupStatus btypes.RollupStatusType
+		expected     btypes.MessageLifecycle
+	}{
+		{btypes.MessageTypeL1SentMessage, btypes.TxStatusTypeSent, btypes.RollupStatusTypeUnknown, btypes.MessageLifecycleQueued},
+		{btypes.MessageTypeL1SentMessage, btypes.TxStatusTypeRelayed, btypes.RollupStatusTypeUnknown, btypes.MessageLifecycleRelayed},
+		{btypes.MessageTypeL1SentMessage, btypes.TxStatusTypeSkipped, btypes.RollupStatusTypeUnknown, btypes.MessageLifecycleSkipped},
+		{btypes.MessageTypeL1SentMessage, btypes.TxStatusTypeDropped, btypes.RollupStatusTypeUnknown, btypes.MessageLifecycleDropped},
+		{btypes.MessageTypeL1SentMessage, btypes.TxStatusTypeSentTxReverted, btypes.RollupStatusTypeUnknown, btypes.MessageLifecycleReverted},
+		{btypes.MessageTypeL2SentMessage, btypes.TxStatusTypeSent, btypes.RollupStatusTypeUnknown, btypes.MessageLifecycleSent},
+		{btypes.MessageTypeL2SentMessage, btypes.TxStatusTypeSent, btypes.RollupStatusTypeFinalized, btypes.MessageLifecycleFinalized},
+		{btypes.MessageTypeL2SentMessage, btypes.TxStatusTypeFailedRelayed, btypes.RollupStatusTypeFinalized, btypes.MessageLifecycleRelayFailed},
+		{btypes.MessageTypeL2SentMessage, btypes.TxStatusTypeRelayTxReverted, btypes.RollupStatusTypeFinalized, btypes.MessageLifecycleRelayFailed},
+		{btypes.MessageTypeL2SentMessage, btypes.TxStatusTypeRelayed, btypes.RollupStatusTypeFinalized, btypes.MessageLifecycleRelayed},
+	}
+
+	for _, tt := range tests {
+		message := &orm.CrossMessage{MessageType: int(tt.messageType), TxStatus: int(tt.txStatus), RollupStatus: int(tt.rollupStatus)}
+		assert.Equal(t, tt.expected, messageLifecycle(message))
+	}
+}
+
+func TestGetL2WithdrawalProof(t *testing.T) {
+	apps := testcontainers.NewTestcontainerApps()
+	defer apps.Free()
+	require.NoError(t, apps.StartPostgresContainer())
+	db, err := apps.GetGormDBClient()
+	require.NoError(t, err)
+	defer database.CloseDB(db)
+	// the migrations of the bridge history db are postgres only, the table of the model is enough here.
+	require.NoError(t, db.AutoMigrate(&orm.CrossMessage{}))
+
+	finalizedHash := common.HexToHash("0xab01").String()
+	pendingHash := common.HexToHash("0x02").String()
+	depositHash := common.HexToHash("0x03").String()
+	messages := []*orm.CrossMessage{
+		{MessageType: int(btypes.MessageTypeL2SentMessage), RollupStatus: int(btypes.RollupStatusTypeFinalized), MessageHash: finalizedHash, MessageFrom: "0xfrom", MessageTo: "0xto", MessageValue: "1", MessageNonce: 7, MessageData: "0xdata", MerkleProof: []byte{0x01, 0x02}, BatchIndex: 3},
+		{MessageType: int(btypes.MessageTypeL2SentMessage), RollupStatus: int(btypes.RollupStatusTypeUnknown), MessageHash: pendingHash, MessageNonce: 8, BatchIndex: 4},
+		{MessageType: int(btypes.MessageTypeL1SentMessage), MessageHash: depositHash},
+	}
+	for _, message := range messages {
+		require.NoError(t, db.Create(message).Error)
+	}
+	h := NewHistoryLogic(db, nil)
+
+	// the message hash is matched whatever its case.
+	proof, err := h.GetL2WithdrawalProof(context.Background(), "0x"+strings.ToUpper(finalizedHash[2:]))
+	require.NoError(t, err)
+	assert.Equal(t, finalizedHash, proof.MessageHash)
+	assert.Equal(t, "0xfrom", proof.ClaimInfo.From)
+	assert.Equal(t, "0xto", proof.ClaimInfo.To)
+	assert.Equal(t, "1", proof.ClaimInfo.Value)
+	assert.Equal(t, "7", proof.ClaimInfo.Nonce)
+	assert.Equal(t, "0xdata", proof.ClaimInfo.Message)
+	assert.True(t, proof.Claimable)
+	assert.Equal(t, "3", proof.Proof.BatchIndex)
+	assert.Equal(t, "0x0102", proof.Proof.MerkleProof)
+
+	// the withdrawal of a batch not finalized yet has no proof.
+	proof, err = h.GetL2WithdrawalProof(context.Background(), pendingHash)
+	require.NoError(t, err)
+	assert.Equal(t, "8", proof.ClaimInfo.Nonce)
+	assert.False(t, proof.Claimable)
+	assert.Empty(t, proof.Proof.BatchIndex)
+	assert.Empty(t, proof.Proof.MerkleProof)
+
+	// a deposit isn't a withdrawal.
+	_, err = h.GetL2WithdrawalProof(context.Background(), depositHash)
+	assert.ErrorIs(t, err, ErrL2WithdrawalNotFound)
+	_, err = h.GetL2WithdrawalProof(context.Background(), common.HexToHash("0x04").String())
+	assert.ErrorIs(t, err, ErrL2WithdrawalNotFound)
+
+	_, err = h.GetL2WithdrawalProof(context.Background(), "0x01")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrL2WithdrawalNotFound)
+}
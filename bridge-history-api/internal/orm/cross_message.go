@@ -116,6 +116,22 @@ func (c *CrossMessage) GetL2LatestFinalizedWithdrawal(ctx context.Context) (*Cro
 	return &message, nil
 }
 
+// GetL2WithdrawalByMessageHash returns the L2 withdrawal of the message hash, nil if it doesn't exist.
+func (c *CrossMessage) GetL2WithdrawalByMessageHash(ctx context.Context, messageHash string) (*CrossMessage, error) {
+	var message CrossMessage
+	db := c.db.WithContext(ctx)
+	db = db.Model(&CrossMessage{})
+	db = db.Where("message_hash = ?", messageHash)
+	db = db.Where("message_type = ?", btypes.MessageTypeL2SentMessage)
+	if err := db.First(&message).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get L2 withdrawal by message hash, message hash: %v, error: %w", messageHash, err)
+	}
+	return &message, nil
+}
+
 // GetL2WithdrawalsByBlockRange returns the L2 withdrawals by block range from the database.
 func (c *CrossMessage) GetL2WithdrawalsByBlockRange(ctx context.Context, startBlock, endBlock uint64) ([]*CrossMessage, error) {
 	var messages []*CrossMessage
@@ -178,6 +194,21 @@ func (c *CrossMessage) GetL2WithdrawalsByAddress(ctx context.Context, sender str
 	return messages, nil
 }
 
+// GetL1DepositsByAddress retrieves all L1 deposits for a given sender address.
+func (c *CrossMessage) GetL1DepositsByAddress(ctx context.Context, sender string) ([]*CrossMessage, error) {
+	var messages []*CrossMessage
+	db := c.db.WithContext(ctx)
+	db = db.Model(&CrossMessage{})
+	db = db.Where("message_type = ?", btypes.MessageTypeL1SentMessage)
+	db = db.Where("sender = ?", sender)
+	db = db.Order("block_timestamp desc")
+	db = db.Limit(500)
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get L1 deposit messages by sender address, sender: %v, error: %w", sender, err)
+	}
+	return messages, nil
+}
+
 // GetTxsByAddress retrieves all txs for a given sender address.
 func (c *CrossMessage) GetTxsByAddress(ctx context.Context, sender string) ([]*CrossMessage, error) {
 	var messages []*CrossMessage
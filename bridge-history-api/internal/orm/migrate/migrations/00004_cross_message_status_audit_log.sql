@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE cross_message_status_audit_log
+(
+    id                  BIGSERIAL     PRIMARY KEY,
+    message_hash        VARCHAR       NOT NULL,
+    status_column       VARCHAR       NOT NULL,
+    from_status         SMALLINT      DEFAULT NULL,
+    to_status           SMALLINT      NOT NULL,
+    actor               VARCHAR       NOT NULL,
+    changed_at          TIMESTAMP(3)  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at          TIMESTAMP(0)  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP(0)  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at          TIMESTAMP(0)  DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_cmsal_message_hash_changed_at ON cross_message_status_audit_log (message_hash, changed_at);
+
+-- from_status is NULL when the message is inserted, actor is the application_name of the db session, the db user if not set.
+CREATE OR REPLACE FUNCTION log_cross_message_status_transition() RETURNS TRIGGER AS $$
+DECLARE
+    actor VARCHAR := COALESCE(NULLIF(current_setting('application_name', true), ''), current_user);
+BEGIN
+    IF TG_OP = 'INSERT' OR OLD.tx_status IS DISTINCT FROM NEW.tx_status THEN
+        INSERT INTO cross_message_status_audit_log (message_hash, status_column, from_status, to_status, actor)
+        VALUES (NEW.message_hash, 'tx_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.tx_status END, NEW.tx_status, actor);
+    END IF;
+    IF TG_OP = 'INSERT' OR OLD.rollup_status IS DISTINCT FROM NEW.rollup_status THEN
+        INSERT INTO cross_message_status_audit_log (message_hash, status_column, from_status, to_status, actor)
+        VALUES (NEW.message_hash, 'rollup_status', CASE WHEN TG_OP = 'UPDATE' THEN OLD.rollup_status END, NEW.rollup_status, actor);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER cross_message_status_audit
+AFTER INSERT OR UPDATE OF tx_status, rollup_status ON cross_message_v2
+FOR EACH ROW EXECUTE FUNCTION log_cross_message_status_transition();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS cross_message_status_audit ON cross_message_v2;
+DROP FUNCTION IF EXISTS log_cross_message_status_transition();
+DROP TABLE IF EXISTS cross_message_status_audit_log;
+-- +goose StatementEnd
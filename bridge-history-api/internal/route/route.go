@@ -28,8 +28,11 @@ func Route(router *gin.Engine, conf *config.Config, reg prometheus.Registerer) {
 	r := router.Group("api/")
 
 	r.GET("/txs", api.TxsByAddressCtl.GetTxsByAddress)
+	r.GET("/l1/deposits", api.L1DepositsByAddressCtl.GetL1DepositsByAddress)
 	r.GET("/l2/withdrawals", api.L2WithdrawalsByAddressCtl.GetL2WithdrawalsByAddress)
 	r.GET("/l2/unclaimed/withdrawals", api.L2UnclaimedWithdrawalsByAddressCtl.GetL2UnclaimedWithdrawalsByAddress)
+	r.GET("/l2/withdrawal/proof", api.L2WithdrawalProofCtl.GetL2WithdrawalProof)
+	r.GET("/message/status", api.MessageStatusByHashCtl.GetMessageStatusByHash)
 
 	r.POST("/txsbyhashes", api.TxsByHashesCtl.PostQueryTxsByHashes)
 }
@@ -21,6 +21,14 @@ const (
 	ErrGetTxsError = 40004
 	// ErrGetTxsByHashError represents an error when trying to get transactions by hash list.
 	ErrGetTxsByHashError = 40005
+	// ErrGetWithdrawalProofError represents an error when trying to get the proof of a L2 withdrawal.
+	ErrGetWithdrawalProofError = 40006
+	// ErrGetMessageStatusError represents an error when trying to get the status of the cross messages of a tx.
+	ErrGetMessageStatusError = 40007
+	// ErrGetL1DepositsError represents an error when trying to get L1 deposit transactions by address.
+	ErrGetL1DepositsError = 40008
+	// ErrWithdrawalNotFound represents an error when the L2 withdrawal of the message hash is not found.
+	ErrWithdrawalNotFound = 40009
 )
 
 // QueryByAddressRequest the request parameter of address api
@@ -35,12 +43,28 @@ type QueryByHashRequest struct {
 	Txs []string `json:"txs" binding:"required,min=1,max=100"`
 }
 
+// QueryWithdrawalProofRequest the request parameter of withdrawal proof api
+type QueryWithdrawalProofRequest struct {
+	MessageHash string `form:"message_hash" binding:"required"`
+}
+
+// QueryMessageStatusRequest the request parameter of message status api
+type QueryMessageStatusRequest struct {
+	TxHash string `form:"tx_hash" binding:"required"`
+}
+
 // ResultData contains return txs and total
 type ResultData struct {
 	Results []*TxHistoryInfo `json:"results"`
 	Total   uint64           `json:"total"`
 }
 
+// MessageStatusResultData contains return message statuses and total
+type MessageStatusResultData struct {
+	Results []*MessageStatusInfo `json:"results"`
+	Total   uint64               `json:"total"`
+}
+
 // Response the response schema
 type Response struct {
 	ErrCode int         `json:"errcode"`
@@ -71,6 +95,48 @@ type L2MessageProof struct {
 	MerkleProof string `json:"merkle_proof"`
 }
 
+// WithdrawalProofInfo is the schema of the claim info of a L2 withdrawal, the proof is only set once the withdrawal is claimable
+type WithdrawalProofInfo struct {
+	MessageHash string `json:"message_hash"`
+	ClaimInfo
+}
+
+// MessageLifecycle is the lifecycle stage of a cross message
+type MessageLifecycle string
+
+// Constants for MessageLifecycle.
+const (
+	// MessageLifecycleSent the L2 message is sent, waiting for its batch to be finalized on L1.
+	MessageLifecycleSent MessageLifecycle = "sent"
+	// MessageLifecycleQueued the L1 message is in the L1 message queue, waiting to be relayed on L2.
+	MessageLifecycleQueued MessageLifecycle = "queued"
+	// MessageLifecycleFinalized the batch of the L2 message is finalized, the message can be claimed on L1.
+	MessageLifecycleFinalized MessageLifecycle = "finalized"
+	// MessageLifecycleRelayed the message is relayed on the counterpart chain.
+	MessageLifecycleRelayed MessageLifecycle = "relayed"
+	// MessageLifecycleRelayFailed the relay tx failed, the message can be replayed.
+	MessageLifecycleRelayFailed MessageLifecycle = "relay_failed"
+	// MessageLifecycleSkipped the L1 message is skipped on L2, it can be dropped to refund.
+	MessageLifecycleSkipped MessageLifecycle = "skipped"
+	// MessageLifecycleDropped the skipped L1 message is dropped.
+	MessageLifecycleDropped MessageLifecycle = "dropped"
+	// MessageLifecycleReverted the sending tx reverted, no message is sent.
+	MessageLifecycleReverted MessageLifecycle = "reverted"
+)
+
+// MessageStatusInfo is the schema of the lifecycle of a cross message
+type MessageStatusInfo struct {
+	MessageHash        string              `json:"message_hash"`
+	MessageType        MessageType         `json:"message_type"` // 1: layer 1 message, 2: layer 2 message
+	Status             MessageLifecycle    `json:"status"`
+	TxStatus           TxStatusType        `json:"tx_status"`
+	Hash               string              `json:"hash"`
+	ReplayTxHash       string              `json:"replay_tx_hash"`
+	RefundTxHash       string              `json:"refund_tx_hash"`
+	CounterpartChainTx *CounterpartChainTx `json:"counterpart_chain_tx"`
+	BatchIndex         uint64              `json:"batch_index"` // only for layer 2 message
+}
+
 // TxHistoryInfo the schema of tx history infos
 type TxHistoryInfo struct {
 	Hash               string              `json:"hash"`
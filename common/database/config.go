@@ -1,5 +1,7 @@
 package database
 
+import "scroll-tech/common/objectstore"
+
 // Config db config
 type Config struct {
 	// data source name
@@ -8,4 +10,32 @@ type Config struct {
 
 	MaxOpenNum int `json:"maxOpenNum"`
 	MaxIdleNum int `json:"maxIdleNum"`
+	// ConnMaxLifetimeSec closes the connections opened for longer than this, default 10 minutes.
+	ConnMaxLifetimeSec int `json:"conn_max_lifetime_sec,omitempty"`
+	// ConnMaxIdleTimeSec closes the connections idle for longer than this, default 5 minutes.
+	ConnMaxIdleTimeSec int `json:"conn_max_idle_time_sec,omitempty"`
+	// StatementTimeoutSec cancels any statement running for longer than this, on the primary and the replicas,
+	// so one slow query fails instead of blocking its caller. 0 disables it.
+	StatementTimeoutSec int `json:"statement_timeout_sec,omitempty"`
+
+	// ProofStore offloads the chunk and batch proofs to an object storage when set,
+	// only their content hash and URI are kept in the database.
+	ProofStore *objectstore.Config `json:"proof_store,omitempty"`
+
+	// Replicas the read-only replicas serving the read queries, writes and transactions stay on the primary.
+	Replicas *ReplicaConfig `json:"replicas,omitempty"`
+}
+
+// ReplicaConfig db read replicas config
+type ReplicaConfig struct {
+	DSNs []string `json:"dsns"`
+
+	MaxOpenNum int `json:"maxOpenNum"`
+	MaxIdleNum int `json:"maxIdleNum"`
+
+	// HealthCheckIntervalSec how often the replicas are pinged, an unhealthy replica is skipped until it's back.
+	HealthCheckIntervalSec int `json:"health_check_interval_sec"`
+	// ReadAll routes every read outside a transaction to the replicas,
+	// otherwise only the queries marked by ReadFromReplica are.
+	ReadAll bool `json:"read_all"`
 }
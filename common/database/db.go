@@ -46,11 +46,72 @@ func (g *gormLogger) Trace(_ context.Context, begin time.Time, fc func() (string
 
 // InitDB init the db handler
 func InitDB(config *Config) (*gorm.DB, error) {
+	dialector, err := openDialector(config.DriverName, config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db, err := gorm.Open(dialector, newGormConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, pingErr := Ping(db)
+	if pingErr != nil {
+		return nil, pingErr
+	}
+	setPool(sqlDB, config.MaxOpenNum, config.MaxIdleNum, config.ConnMaxLifetimeSec, config.ConnMaxIdleTimeSec)
+
+	if config.StatementTimeoutSec > 0 {
+		if err := db.Use(&statementTimeout{timeout: time.Duration(config.StatementTimeoutSec) * time.Second}); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Replicas != nil && len(config.Replicas.DSNs) > 0 {
+		if db.Dialector.Name() != DriverPostgres {
+			return nil, fmt.Errorf("db replicas are only supported with %s", DriverPostgres)
+		}
+		resolver, err := newReplicaResolver(config.Replicas, config.ConnMaxLifetimeSec, config.ConnMaxIdleTimeSec)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.Use(resolver); err != nil {
+			if closeErr := resolver.close(); closeErr != nil {
+				log.Warn("failed to close db replicas", "err", closeErr)
+			}
+			return nil, err
+		}
+
+		interval := defaultReplicaHealthCheckInterval
+		if config.Replicas.HealthCheckIntervalSec > 0 {
+			interval = time.Duration(config.Replicas.HealthCheckIntervalSec) * time.Second
+		}
+		go resolver.healthCheckLoop(interval)
+	}
+
+	return db, nil
+}
+
+// openDB opens and pings a connection pool of the dsn.
+func openDB(dsn string, maxOpenNum, maxIdleNum, connMaxLifetimeSec, connMaxIdleTimeSec int) (*sql.DB, error) {
+	db, err := gorm.Open(postgres.Open(dsn), newGormConfig())
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := Ping(db)
+	if err != nil {
+		return nil, err
+	}
+	setPool(sqlDB, maxOpenNum, maxIdleNum, connMaxLifetimeSec, connMaxIdleTimeSec)
+	return sqlDB, nil
+}
+
+func newGormConfig() *gorm.Config {
 	tmpGormLogger := gormLogger{
 		gethLogger: log.Root(),
 	}
 
-	db, err := gorm.Open(postgres.Open(config.DSN), &gorm.Config{
+	return &gorm.Config{
 		Logger: &tmpGormLogger,
 		NowFunc: func() time.Time {
 			// why set time to UTC.
@@ -60,23 +121,23 @@ func InitDB(config *Config) (*gorm.DB, error) {
 			// the timezone by loc=Local. but postgres's dsn don't have loc option to set timezone, so just need set the gorm option like that.
 			return cutils.NowUTC()
 		},
-	})
-	if err != nil {
-		return nil, err
 	}
+}
 
-	sqlDB, pingErr := Ping(db)
-	if pingErr != nil {
-		return nil, pingErr
+func setPool(sqlDB *sql.DB, maxOpenNum, maxIdleNum, connMaxLifetimeSec, connMaxIdleTimeSec int) {
+	connMaxLifetime := time.Minute * 10
+	if connMaxLifetimeSec > 0 {
+		connMaxLifetime = time.Duration(connMaxLifetimeSec) * time.Second
 	}
+	connMaxIdleTime := time.Minute * 5
+	if connMaxIdleTimeSec > 0 {
+		connMaxIdleTime = time.Duration(connMaxIdleTimeSec) * time.Second
+	}
+	sqlDB.SetConnMaxLifetime(connMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(connMaxIdleTime)
 
-	sqlDB.SetConnMaxLifetime(time.Minute * 10)
-	sqlDB.SetConnMaxIdleTime(time.Minute * 5)
-
-	sqlDB.SetMaxOpenConns(config.MaxOpenNum)
-	sqlDB.SetMaxIdleConns(config.MaxIdleNum)
-
-	return db, nil
+	sqlDB.SetMaxOpenConns(maxOpenNum)
+	sqlDB.SetMaxIdleConns(maxIdleNum)
 }
 
 // CloseDB close the db handler. notice the db handler only can close when then program exit.
@@ -88,6 +149,11 @@ func CloseDB(db *gorm.DB) error {
 	if err := sqlDB.Close(); err != nil {
 		return err
 	}
+	if plugin, ok := db.Config.Plugins[replicaResolverName]; ok {
+		if err := plugin.(*replicaResolver).close(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
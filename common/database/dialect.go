@@ -0,0 +1,35 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	// DriverPostgres is the production backend, used when the driver name is empty.
+	DriverPostgres = "postgres"
+	// DriverSQLite is an embedded backend for development and tests, only available in the binaries built
+	// with the `sqlite` build tag.
+	DriverSQLite = "sqlite"
+)
+
+// dialectors are the gorm dialectors of the supported driver names.
+var dialectors = map[string]func(dsn string) gorm.Dialector{
+	DriverPostgres: postgres.Open,
+}
+
+func openDialector(driverName, dsn string) (gorm.Dialector, error) {
+	if driverName == "" {
+		driverName = DriverPostgres
+	}
+	open, ok := dialectors[driverName]
+	if !ok {
+		if driverName == DriverSQLite {
+			return nil, fmt.Errorf("driver %s is not compiled in, build with the sqlite build tag", driverName)
+		}
+		return nil, fmt.Errorf("unsupported driver %s", driverName)
+	}
+	return open(dsn), nil
+}
@@ -0,0 +1,11 @@
+//go:build sqlite
+
+package database
+
+import (
+	"github.com/glebarez/sqlite"
+)
+
+func init() {
+	dialectors[DriverSQLite] = sqlite.Open
+}
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+)
+
+const (
+	replicaResolverName = "scroll:replica_resolver"
+
+	// readTargetKey is the statement setting used to pin a query to the primary or to the replicas.
+	readTargetKey = "scroll:read_target"
+
+	defaultReplicaHealthCheckInterval = 10 * time.Second
+)
+
+type readTarget int
+
+const (
+	readTargetPrimary readTarget = iota + 1
+	readTargetReplica
+)
+
+// ReadFromReplica marks the queries of the returned db as safe to be served by a replica,
+// used by the heavy read-only queries that tolerate the replication lag.
+func ReadFromReplica(db *gorm.DB) *gorm.DB {
+	return db.Set(readTargetKey, readTargetReplica)
+}
+
+// ReadFromPrimary pins the queries of the returned db to the primary, even when every read goes to the replicas.
+func ReadFromPrimary(db *gorm.DB) *gorm.DB {
+	return db.Set(readTargetKey, readTargetPrimary)
+}
+
+type replica struct {
+	dsn     string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// replicaResolver is a gorm plugin routing the read-only queries to the healthy replicas.
+// Writes, transactions and locking reads always go to the primary, and the primary serves
+// the reads when every replica is down.
+type replicaResolver struct {
+	replicas []*replica
+	readAll  bool
+	next     atomic.Uint64
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// newReplicaResolver opens the replicas, their connections share the lifetimes of the primary ones.
+func newReplicaResolver(config *ReplicaConfig, connMaxLifetimeSec, connMaxIdleTimeSec int) (*replicaResolver, error) {
+	r := &replicaResolver{
+		readAll: config.ReadAll,
+		stopCh:  make(chan struct{}),
+	}
+	for _, dsn := range config.DSNs {
+		db, err := openDB(dsn, config.MaxOpenNum, config.MaxIdleNum, connMaxLifetimeSec, connMaxIdleTimeSec)
+		if err != nil {
+			if closeErr := r.close(); closeErr != nil {
+				log.Warn("failed to close db replicas", "err", closeErr)
+			}
+			return nil, fmt.Errorf("open replica failure, err:%w", err)
+		}
+		r.addReplica(dsn, db)
+	}
+	return r, nil
+}
+
+func (r *replicaResolver) addReplica(dsn string, db *sql.DB) {
+	rep := &replica{dsn: dsn, db: db}
+	rep.healthy.Store(true)
+	r.replicas = append(r.replicas, rep)
+}
+
+// Name implements gorm.Plugin.
+func (r *replicaResolver) Name() string {
+	return replicaResolverName
+}
+
+// Initialize implements gorm.Plugin.
+func (r *replicaResolver) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register(replicaResolverName+":query", r.route); err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("gorm:row").Register(replicaResolverName+":row", r.route)
+}
+
+func (r *replicaResolver) route(db *gorm.DB) {
+	if !r.useReplica(db) {
+		return
+	}
+	if rep := r.pick(); rep != nil {
+		db.Statement.ConnPool = rep.db
+	}
+}
+
+func (r *replicaResolver) useReplica(db *gorm.DB) bool {
+	stmt := db.Statement
+	if _, inTx := stmt.ConnPool.(gorm.TxCommitter); inTx {
+		return false
+	}
+	if _, locking := stmt.Clauses["FOR"]; locking {
+		return false
+	}
+	// raw statements reach the row callback too, only plain selects can be served by a replica.
+	if stmt.SQL.Len() > 0 && !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(stmt.SQL.String())), "SELECT") {
+		return false
+	}
+
+	if target, ok := stmt.Settings.Load(readTargetKey); ok {
+		return target == readTargetReplica
+	}
+	return r.readAll
+}
+
+// pick returns the next healthy replica in turn, nil if there is none.
+func (r *replicaResolver) pick() *replica {
+	n := len(r.replicas)
+	if n == 0 {
+		return nil
+	}
+	start := r.next.Add(1)
+	for i := 0; i < n; i++ {
+		rep := r.replicas[(start+uint64(i))%uint64(n)]
+		if rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+func (r *replicaResolver) healthCheckLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.checkHealth(interval)
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *replicaResolver) checkHealth(timeout time.Duration) {
+	for _, rep := range r.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := rep.db.PingContext(ctx)
+		cancel()
+
+		healthy := err == nil
+		if rep.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Info("db replica is back", "dsn", redactDSN(rep.dsn))
+			} else {
+				log.Warn("db replica is down, reads fail over", "dsn", redactDSN(rep.dsn), "err", err)
+			}
+		}
+	}
+}
+
+func (r *replicaResolver) close() error {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+
+	var closeErr error
+	for _, rep := range r.replicas {
+		if err := rep.db.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
+// redactDSN drops the password of the dsn before it's logged.
+func redactDSN(dsn string) string {
+	if strings.Contains(dsn, "://") {
+		if u, err := url.Parse(dsn); err == nil {
+			return u.Redacted()
+		}
+	}
+	fields := strings.Fields(dsn)
+	for i, field := range fields {
+		if strings.HasPrefix(field, "password=") {
+			fields[i] = "password=***"
+		}
+	}
+	if len(fields) > 0 {
+		return strings.Join(fields, " ")
+	}
+	return dsn
+}
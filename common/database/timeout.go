@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	statementTimeoutName = "scroll:statement_timeout"
+
+	// statementContextKey is the statement instance setting holding the context the timeout replaced.
+	statementContextKey = "scroll:statement_context"
+)
+
+// statementTimeout is a gorm plugin bounding every statement by a timeout on top of its context,
+// so a slow query fails instead of blocking its caller. The drivers cancel the running query on the server.
+type statementTimeout struct {
+	timeout time.Duration
+}
+
+// Name implements gorm.Plugin.
+func (t *statementTimeout) Name() string {
+	return statementTimeoutName
+}
+
+// Initialize implements gorm.Plugin.
+func (t *statementTimeout) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register(statementTimeoutName+":before_create", t.before),
+		cb.Create().After("gorm:create").Register(statementTimeoutName+":after_create", t.after),
+		cb.Query().Before("gorm:query").Register(statementTimeoutName+":before_query", t.before),
+		cb.Query().After("gorm:query").Register(statementTimeoutName+":after_query", t.after),
+		cb.Update().Before("gorm:update").Register(statementTimeoutName+":before_update", t.before),
+		cb.Update().After("gorm:update").Register(statementTimeoutName+":after_update", t.after),
+		cb.Delete().Before("gorm:delete").Register(statementTimeoutName+":before_delete", t.before),
+		cb.Delete().After("gorm:delete").Register(statementTimeoutName+":after_delete", t.after),
+		cb.Raw().Before("gorm:raw").Register(statementTimeoutName+":before_raw", t.before),
+		cb.Raw().After("gorm:raw").Register(statementTimeoutName+":after_raw", t.after),
+		cb.Row().Before("gorm:row").Register(statementTimeoutName+":before_row", t.before),
+		cb.Row().After("gorm:row").Register(statementTimeoutName+":after_row", t.afterRow),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statementContext is the context of the statement before the timeout, restored once the statement is done
+// as the chained db may run more statements.
+type statementContext struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+func (t *statementTimeout) before(db *gorm.DB) {
+	parent := db.Statement.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, t.timeout)
+	db.Statement.Context = ctx
+	db.InstanceSet(statementContextKey, &statementContext{parent: parent, cancel: cancel})
+}
+
+func (t *statementTimeout) after(db *gorm.DB) {
+	v, ok := db.InstanceGet(statementContextKey)
+	if !ok {
+		return
+	}
+	sc := v.(*statementContext)
+	sc.cancel()
+	db.Statement.Context = sc.parent
+}
+
+// afterRow leaves the cancellation of the statement context to its timer, the rows of the row callback
+// are scanned by the caller after the callback returns.
+func (t *statementTimeout) afterRow(db *gorm.DB) {
+	v, ok := db.InstanceGet(statementContextKey)
+	if !ok {
+		return
+	}
+	db.Statement.Context = v.(*statementContext).parent
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementTimeout(t *testing.T) {
+	timeout := &statementTimeout{timeout: time.Second}
+	db := newTestStatement(&sql.DB{})
+	parent := context.Background()
+	db.Statement.Context = parent
+
+	timeout.before(db)
+	ctx := db.Statement.Context
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+	// the statement context is cancelled once done, and the chained db gets its own context back.
+	timeout.after(db)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.Equal(t, parent, db.Statement.Context)
+}
@@ -0,0 +1,83 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type filesystemStore struct {
+	dir           string
+	maxObjectSize int64
+}
+
+func newFilesystemStore(cfg *Config) (*filesystemStore, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("filesystem object store needs a dir")
+	}
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	return &filesystemStore{dir: dir, maxObjectSize: cfg.maxObjectSize()}, nil
+}
+
+// Put writes the object to a temporary file first, so a reader never sees a partial object.
+func (s *filesystemStore) Put(_ context.Context, key string, data []byte) (string, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+	if err = tmp.Close(); err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: file}).String(), nil
+}
+
+func (s *filesystemStore) Get(_ context.Context, uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("unsupported uri scheme of the filesystem object store: %s", uri)
+	}
+	file := filepath.Clean(u.Path)
+	if !strings.HasPrefix(file, s.dir+string(filepath.Separator)) {
+		return nil, fmt.Errorf("uri %s is outside of the object store dir", uri)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return readAll(f, s.maxObjectSize)
+}
+
+func (s *filesystemStore) path(key string) (string, error) {
+	file := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(file, s.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key: %s", key)
+	}
+	return file, nil
+}
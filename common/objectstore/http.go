@@ -0,0 +1,84 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultHTTPTimeout = 30 * time.Second
+
+// genericHTTPStore stores the objects with plain PUT/GET requests under a base URL, optionally with a bearer token.
+// It's not an S3 or GCS client: it doesn't sign the requests, nor speak the multipart upload or resumable APIs.
+type genericHTTPStore struct {
+	baseURL       string
+	authToken     string
+	maxObjectSize int64
+	client        *http.Client
+}
+
+func newGenericHTTPStore(cfg *Config) (*genericHTTPStore, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("http object store needs a url")
+	}
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid http object store url: %w", err)
+	}
+	timeout := defaultHTTPTimeout
+	if cfg.TimeoutSec > 0 {
+		timeout = time.Duration(cfg.TimeoutSec) * time.Second
+	}
+	return &genericHTTPStore{
+		baseURL:       strings.TrimSuffix(cfg.URL, "/"),
+		authToken:     cfg.AuthToken,
+		maxObjectSize: cfg.maxObjectSize(),
+		client:        &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (s *genericHTTPStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	uri := s.baseURL + "/" + strings.TrimPrefix(key, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uri, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if _, err = s.do(req); err != nil {
+		return "", err
+	}
+	return uri, nil
+}
+
+func (s *genericHTTPStore) Get(ctx context.Context, uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, s.baseURL+"/") {
+		return nil, fmt.Errorf("uri %s is outside of the object store url", uri)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.do(req)
+}
+
+func (s *genericHTTPStore) do(req *http.Request) ([]byte, error) {
+	if s.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.authToken)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, s.maxObjectSize))
+		return nil, fmt.Errorf("%s %s status: %s", req.Method, req.URL.String(), resp.Status)
+	}
+	return readAll(resp.Body, s.maxObjectSize)
+}
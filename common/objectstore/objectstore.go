@@ -0,0 +1,118 @@
+// Package objectstore offloads large payloads, such as proofs, from the database to an object storage.
+// Payloads are content addressed: the database only keeps the content hash and the URI of the object.
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+const (
+	// FilesystemBackend stores the objects as files under a local directory.
+	FilesystemBackend = "filesystem"
+	// HTTPBackend stores the objects with plain PUT/GET requests under a base URL, e.g. a WebDAV server. The requests
+	// are not signed, so an S3 or GCS bucket is only usable through a proxy signing them with the bucket credentials.
+	HTTPBackend = "http"
+
+	defaultMaxObjectSize = 256 << 20
+)
+
+// ErrHashMismatch is returned when a downloaded object doesn't match its recorded content hash.
+var ErrHashMismatch = errors.New("object content hash mismatch")
+
+// ErrObjectTooLarge is returned when an object read back is larger than the max object size of the store.
+var ErrObjectTooLarge = errors.New("object too large")
+
+// Config the object storage config
+type Config struct {
+	// Backend is either "filesystem" or "http".
+	Backend string `json:"backend"`
+	// Dir is the root directory of the filesystem backend.
+	Dir string `json:"dir,omitempty"`
+	// URL is the base URL of the http backend, objects are stored at URL/<key>.
+	URL string `json:"url,omitempty"`
+	// AuthToken is sent as "Authorization: Bearer <token>" by the http backend if set.
+	AuthToken string `json:"auth_token,omitempty"`
+	// TimeoutSec is the timeout of a single http request, default 30s.
+	TimeoutSec uint64 `json:"timeout_sec,omitempty"`
+	// MaxObjectSize is the max size in bytes of an object read back, default 256MiB.
+	MaxObjectSize uint64 `json:"max_object_size,omitempty"`
+}
+
+func (c *Config) maxObjectSize() int64 {
+	if c.MaxObjectSize == 0 {
+		return defaultMaxObjectSize
+	}
+	return int64(c.MaxObjectSize)
+}
+
+// Store reads and writes objects by key.
+type Store interface {
+	// Put writes the object under the key and returns the URI it can be read back from.
+	Put(ctx context.Context, key string, data []byte) (string, error)
+	// Get reads the object at the URI returned by Put.
+	Get(ctx context.Context, uri string) ([]byte, error)
+}
+
+// New creates the store of the config, it returns a nil store if cfg is nil, which keeps the payloads in the database.
+func New(cfg *Config) (Store, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	switch cfg.Backend {
+	case FilesystemBackend:
+		return newFilesystemStore(cfg)
+	case HTTPBackend:
+		return newGenericHTTPStore(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported object store backend: %s", cfg.Backend)
+	}
+}
+
+// ContentHash returns the hex encoded sha256 of the data.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Upload writes the data under prefix/<content hash> and returns the content hash and the URI of the object.
+func Upload(ctx context.Context, store Store, prefix string, data []byte) (string, string, error) {
+	hash := ContentHash(data)
+	uri, err := store.Put(ctx, path.Join(prefix, hash), data)
+	if err != nil {
+		return "", "", fmt.Errorf("upload object error: %w, hash: %v", err, hash)
+	}
+	return hash, uri, nil
+}
+
+// Download reads the object at the URI and checks it against the content hash.
+func Download(ctx context.Context, store Store, uri, hash string) ([]byte, error) {
+	if store == nil {
+		return nil, fmt.Errorf("object %s is offloaded but no object store is configured", uri)
+	}
+	data, err := store.Get(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("download object error: %w, uri: %v", err, uri)
+	}
+	if ContentHash(data) != hash {
+		return nil, fmt.Errorf("%w, uri: %v, expected hash: %v", ErrHashMismatch, uri, hash)
+	}
+	return data, nil
+}
+
+// readAll reads the object up to maxSize bytes, so a corrupted or hostile store can't exhaust the memory.
+func readAll(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w, max size: %v bytes", ErrObjectTooLarge, maxSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,115 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	store, err := New(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, store)
+
+	_, err = New(&Config{Backend: "s4"})
+	assert.Error(t, err)
+	_, err = New(&Config{Backend: FilesystemBackend})
+	assert.Error(t, err)
+	_, err = New(&Config{Backend: HTTPBackend})
+	assert.Error(t, err)
+}
+
+func TestFilesystemStore(t *testing.T) {
+	store, err := New(&Config{Backend: FilesystemBackend, Dir: t.TempDir()})
+	assert.NoError(t, err)
+
+	data := []byte("chunk proof")
+	hash, uri, err := Upload(context.Background(), store, "chunk", data)
+	assert.NoError(t, err)
+	assert.Equal(t, ContentHash(data), hash)
+	assert.True(t, strings.HasPrefix(uri, "file://"))
+
+	result, err := Download(context.Background(), store, uri, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, data, result)
+
+	_, err = Download(context.Background(), store, uri, ContentHash([]byte("batch proof")))
+	assert.True(t, errors.Is(err, ErrHashMismatch))
+
+	_, err = store.Get(context.Background(), "file:///etc/passwd")
+	assert.Error(t, err)
+	_, err = store.Put(context.Background(), "../escape", data)
+	assert.Error(t, err)
+
+	_, err = Download(context.Background(), nil, uri, hash)
+	assert.Error(t, err)
+
+	// an object larger than the max object size isn't read back.
+	small, err := New(&Config{Backend: FilesystemBackend, Dir: t.TempDir(), MaxObjectSize: 4})
+	assert.NoError(t, err)
+	hash, uri, err = Upload(context.Background(), small, "chunk", data)
+	assert.NoError(t, err)
+	_, err = Download(context.Background(), small, uri, hash)
+	assert.ErrorIs(t, err, ErrObjectTooLarge)
+}
+
+func TestHTTPStore(t *testing.T) {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(body)
+		}
+	}))
+	defer srv.Close()
+
+	store, err := New(&Config{Backend: HTTPBackend, URL: srv.URL + "/bucket/", AuthToken: "token"})
+	assert.NoError(t, err)
+
+	data := []byte("batch proof")
+	hash, uri, err := Upload(context.Background(), store, "batch", data)
+	assert.NoError(t, err)
+	assert.Equal(t, srv.URL+"/bucket/batch/"+hash, uri)
+
+	result, err := Download(context.Background(), store, uri, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, data, result)
+
+	_, err = store.Get(context.Background(), srv.URL+"/bucket/missing")
+	assert.Error(t, err)
+	_, err = store.Get(context.Background(), "http://example.com/bucket/batch/"+hash)
+	assert.Error(t, err)
+
+	unauthorized, err := New(&Config{Backend: HTTPBackend, URL: srv.URL + "/bucket"})
+	assert.NoError(t, err)
+	_, err = unauthorized.Put(context.Background(), "batch/"+hash, data)
+	assert.Error(t, err)
+
+	// the response body is read up to the max object size.
+	small, err := New(&Config{Backend: HTTPBackend, URL: srv.URL + "/bucket", AuthToken: "token", MaxObjectSize: 4})
+	assert.NoError(t, err)
+	_, err = Download(context.Background(), small, uri, hash)
+	assert.ErrorIs(t, err, ErrObjectTooLarge)
+}
@@ -0,0 +1,54 @@
+package observability
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+const dbQueryStartKey = "observability:db_query_start"
+
+// UseDBMetrics registers gorm callbacks exporting the latency of the db queries by operation and table.
+func UseDBMetrics(db *gorm.DB, reg prometheus.Registerer) error {
+	queryDuration := promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "The latency of the db queries.",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5},
+	}, []string{"operation", "table"})
+
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(dbQueryStartKey, time.Now())
+	}
+	after := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			start, ok := tx.InstanceGet(dbQueryStartKey)
+			if !ok {
+				return
+			}
+			queryDuration.WithLabelValues(operation, tx.Statement.Table).Observe(time.Since(start.(time.Time)).Seconds())
+		}
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("observability:before_create", before),
+		callbacks.Create().After("gorm:create").Register("observability:after_create", after("create")),
+		callbacks.Query().Before("gorm:query").Register("observability:before_query", before),
+		callbacks.Query().After("gorm:query").Register("observability:after_query", after("query")),
+		callbacks.Update().Before("gorm:update").Register("observability:before_update", before),
+		callbacks.Update().After("gorm:update").Register("observability:after_update", after("update")),
+		callbacks.Delete().Before("gorm:delete").Register("observability:before_delete", before),
+		callbacks.Delete().After("gorm:delete").Register("observability:after_delete", after("delete")),
+		callbacks.Row().Before("gorm:row").Register("observability:before_row", before),
+		callbacks.Row().After("gorm:row").Register("observability:after_row", after("row")),
+		callbacks.Raw().Before("gorm:raw").Register("observability:before_raw", before),
+		callbacks.Raw().After("gorm:raw").Register("observability:after_raw", after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
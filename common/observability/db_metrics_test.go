@@ -0,0 +1,67 @@
+package observability
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/testcontainers"
+)
+
+type dbMetricsRecord struct {
+	ID   uint64 `gorm:"column:id;primaryKey"`
+	Name string `gorm:"column:name"`
+}
+
+func (*dbMetricsRecord) TableName() string {
+	return "db_metrics_record"
+}
+
+func TestUseDBMetrics(t *testing.T) {
+	apps := testcontainers.NewTestcontainerApps()
+	defer apps.Free()
+	assert.NoError(t, apps.StartPostgresContainer())
+	db, err := apps.GetGormDBClient()
+	assert.NoError(t, err)
+	defer database.CloseDB(db)
+	assert.NoError(t, db.Exec("CREATE TABLE db_metrics_record (id BIGINT PRIMARY KEY, name VARCHAR NOT NULL)").Error)
+
+	reg := prometheus.NewRegistry()
+	assert.NoError(t, UseDBMetrics(db, reg))
+
+	record := &dbMetricsRecord{ID: 1, Name: "a"}
+	assert.NoError(t, db.Create(record).Error)
+	var queried dbMetricsRecord
+	assert.NoError(t, db.First(&queried, "id = ?", 1).Error)
+	assert.NoError(t, db.Model(record).Update("name", "b").Error)
+	var count int64
+	assert.NoError(t, db.Raw("SELECT COUNT(*) FROM db_metrics_record").Scan(&count).Error)
+	assert.Equal(t, int64(1), count)
+	assert.NoError(t, db.Exec("UPDATE db_metrics_record SET name = ?", "c").Error)
+	assert.NoError(t, db.Delete(record).Error)
+
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+	assert.Len(t, families, 1)
+	assert.Equal(t, "db_query_duration_seconds", families[0].GetName())
+	observations := make(map[string]uint64)
+	for _, metric := range families[0].GetMetric() {
+		labels := make(map[string]string)
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Len(t, labels, 2)
+		observations[labels["operation"]+" "+labels["table"]] += metric.GetHistogram().GetSampleCount()
+	}
+	// the table of a raw sql statement isn't known.
+	assert.Equal(t, map[string]uint64{
+		"create db_metrics_record": 1,
+		"query db_metrics_record":  1,
+		"update db_metrics_record": 1,
+		"row ":                     1,
+		"raw ":                     1,
+		"delete db_metrics_record": 1,
+	}, observations)
+}
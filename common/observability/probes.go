@@ -1,6 +1,14 @@
 package observability
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
@@ -8,28 +16,137 @@ import (
 	"scroll-tech/common/types"
 )
 
+// readinessCheckTimeout bounds every readiness check, a dependency slower than it is not ready.
+const readinessCheckTimeout = 5 * time.Second
+
+// ReadinessCheck checks a dependency of the service, e.g. an RPC endpoint or a signer, it returns an error when
+// the dependency is unavailable.
+type ReadinessCheck func(ctx context.Context) error
+
+// ReadinessChecks is the set of the named readiness checks of a service.
+type ReadinessChecks struct {
+	mu     sync.RWMutex
+	checks map[string]ReadinessCheck
+}
+
+// NewReadinessChecks returns an empty set of readiness checks.
+func NewReadinessChecks() *ReadinessChecks {
+	return &ReadinessChecks{checks: make(map[string]ReadinessCheck)}
+}
+
+// DefaultReadinessChecks is the set of readiness checks of the metrics server.
+var DefaultReadinessChecks = NewReadinessChecks()
+
+// AddReadinessCheck adds a check to the readiness checks of the metrics server, replacing the check of the same name.
+func AddReadinessCheck(name string, check ReadinessCheck) {
+	DefaultReadinessChecks.Add(name, check)
+}
+
+// Add adds a check, replacing the check of the same name.
+func (r *ReadinessChecks) Add(name string, check ReadinessCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Run runs all the checks concurrently, it returns the status of every check, "ok" or its error, and an error
+// naming the failed checks.
+func (r *ReadinessChecks) Run(ctx context.Context) (map[string]string, error) {
+	r.mu.RLock()
+	checks := make(map[string]ReadinessCheck, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	status := make(map[string]string, len(checks))
+	var failed []string
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check ReadinessCheck) {
+			defer wg.Done()
+			err := check(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				status[name] = err.Error()
+				failed = append(failed, name)
+				return
+			}
+			status[name] = "ok"
+		}(name, check)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return status, fmt.Errorf("not ready: %v", failed)
+	}
+	return status, nil
+}
+
+// BlockNumberReader is a client of a chain, e.g. an *ethclient.Client.
+type BlockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// BlockNumberCheck checks the RPC endpoint of the client is reachable by fetching its latest block number.
+func BlockNumberCheck(client BlockNumberReader) ReadinessCheck {
+	return func(ctx context.Context) error {
+		_, err := client.BlockNumber(ctx)
+		return err
+	}
+}
+
 // ProbesController probe check controller
 type ProbesController struct {
-	db *gorm.DB
+	db     *gorm.DB
+	checks *ReadinessChecks
 }
 
 // NewProbesController returns an ProbesController instance
 func NewProbesController(db *gorm.DB) *ProbesController {
 	return &ProbesController{
-		db: db,
+		db:     db,
+		checks: DefaultReadinessChecks,
 	}
 }
 
-// HealthCheck the api controller for health check
+// HealthCheck the api controller for health check, the service is alive while its db is reachable.
 func (a *ProbesController) HealthCheck(c *gin.Context) {
-	if _, err := database.Ping(a.db); err != nil {
+	if err := a.pingDB(); err != nil {
 		types.RenderFatal(c, err)
 		return
 	}
 	types.RenderSuccess(c, nil)
 }
 
-// Ready the api controller for ready check
+// Ready the api controller for ready check, the service is ready when its db is reachable and all its readiness
+// checks pass. It responds 503 with the status of every check otherwise.
 func (a *ProbesController) Ready(c *gin.Context) {
-	types.RenderSuccess(c, nil)
+	status, err := a.checks.Run(c.Request.Context())
+	if dbErr := a.pingDB(); dbErr != nil {
+		status["db"] = dbErr.Error()
+		err = errors.Join(err, dbErr)
+	} else if a.db != nil {
+		status["db"] = "ok"
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, types.Response{ErrCode: types.InternalServerError, ErrMsg: err.Error(), Data: status})
+		return
+	}
+	types.RenderSuccess(c, status)
+}
+
+func (a *ProbesController) pingDB() error {
+	if a.db == nil {
+		return nil
+	}
+	_, err := database.Ping(a.db)
+	return err
 }
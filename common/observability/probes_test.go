@@ -0,0 +1,62 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
+)
+
+type mockBlockNumberReader struct {
+	err error
+}
+
+func (m *mockBlockNumberReader) BlockNumber(context.Context) (uint64, error) {
+	return 1, m.err
+}
+
+func TestProbesController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	checks := NewReadinessChecks()
+	controller := &ProbesController{checks: checks}
+	r := gin.New()
+	r.GET("/healthz", controller.HealthCheck)
+	r.GET("/readyz", controller.Ready)
+
+	get := func(path string) (int, *types.Response) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp types.Response
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, &resp
+	}
+
+	code, _ := get("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+
+	l2Client := &mockBlockNumberReader{}
+	checks.Add("l2geth", BlockNumberCheck(l2Client))
+	checks.Add("signer", func(context.Context) error { return nil })
+	code, resp := get("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, types.Success, resp.ErrCode)
+	assert.Equal(t, map[string]interface{}{"l2geth": "ok", "signer": "ok"}, resp.Data)
+
+	l2Client.err = errors.New("connection refused")
+	code, resp = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, types.InternalServerError, resp.ErrCode)
+	assert.Equal(t, "not ready: [l2geth]", resp.ErrMsg)
+	assert.Equal(t, map[string]interface{}{"l2geth": "connection refused", "signer": "ok"}, resp.Data)
+
+	// the liveness doesn't depend on the readiness checks.
+	code, _ = get("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-contrib/pprof"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
@@ -26,6 +27,12 @@ func Server(c *cli.Context, db *gorm.DB) {
 		return
 	}
 
+	if db != nil {
+		if err := UseDBMetrics(db, prometheus.DefaultRegisterer); err != nil {
+			log.Warn("failed to register db metrics", "error", err)
+		}
+	}
+
 	r := gin.New()
 	r.Use(gin.Recovery())
 	pprof.Register(r)
@@ -33,11 +40,19 @@ func Server(c *cli.Context, db *gorm.DB) {
 		promhttp.Handler().ServeHTTP(context.Writer, context.Request)
 	})
 
+	// /healthz is the liveness probe and /readyz the readiness probe, /health and /ready are kept for the existing
+	// deployments.
 	probeController := NewProbesController(db)
 	r.GET("/health", probeController.HealthCheck)
+	r.GET("/healthz", probeController.HealthCheck)
 	r.GET("/ready", probeController.Ready)
+	r.GET("/readyz", probeController.Ready)
 
+	// listen on all interfaces unless an address is explicitly given.
 	address := fmt.Sprintf(":%s", c.String(utils.MetricsPort.Name))
+	if c.IsSet(utils.MetricsAddr.Name) {
+		address = fmt.Sprintf("%s:%s", c.String(utils.MetricsAddr.Name), c.String(utils.MetricsPort.Name))
+	}
 	server := &http.Server{
 		Addr:              address,
 		Handler:           r,
@@ -0,0 +1,335 @@
+// Package rpcpool spreads the JSON-RPC requests of a client over several http endpoints of the same chain.
+// The pool is an http.RoundTripper: every request goes to a healthy endpoint within its rate limit, and is
+// retried on the next endpoint if the node fails, so one flaky node doesn't stall the client.
+package rpcpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// FailoverStrategy sends the requests to the first healthy endpoint in the configured order.
+	FailoverStrategy = "failover"
+	// RoundRobinStrategy spreads the requests over the healthy endpoints in turn.
+	RoundRobinStrategy = "round_robin"
+
+	defaultHealthCheckIntervalSec = 10
+	defaultFailureCooldownSec     = 30
+	healthCheckTimeout            = 5 * time.Second
+)
+
+// Config the rpc endpoint pool config
+type Config struct {
+	// Endpoints are the http(s) urls of the nodes of the chain.
+	Endpoints []string `json:"endpoints"`
+	// Strategy is either "failover" (default) or "round_robin".
+	Strategy string `json:"strategy,omitempty"`
+	// RateLimitPerSec caps the requests per second sent to an endpoint, 0 means no limit.
+	RateLimitPerSec float64 `json:"rate_limit_per_sec,omitempty"`
+	// RateLimitBurst is the number of requests an endpoint can take at once, default 1.
+	RateLimitBurst int `json:"rate_limit_burst,omitempty"`
+	// HealthCheckIntervalSec is how often the head of every endpoint is polled, default 10s.
+	HealthCheckIntervalSec uint64 `json:"health_check_interval_sec,omitempty"`
+	// FailureCooldownSec is how long an endpoint failing a request is skipped, default 30s.
+	FailureCooldownSec uint64 `json:"failure_cooldown_sec,omitempty"`
+	// MaxHeadLag is how many blocks an endpoint can be behind the highest head of the pool
+	// and still be used, 0 disables the head consistency check.
+	MaxHeadLag uint64 `json:"max_head_lag,omitempty"`
+}
+
+type endpoint struct {
+	url     *url.URL
+	limiter *rate.Limiter
+
+	mu          sync.Mutex
+	head        uint64
+	lagging     bool
+	failedUntil time.Time
+}
+
+func (e *endpoint) usable(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !e.lagging && now.After(e.failedUntil)
+}
+
+func (e *endpoint) fail(cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failedUntil = time.Now().Add(cooldown)
+}
+
+// Pool the http.RoundTripper sending every request to one of the endpoints.
+type Pool struct {
+	cfg       *Config
+	endpoints []*endpoint
+	transport http.RoundTripper
+	cooldown  time.Duration
+	next      atomic.Uint64
+}
+
+// NewPool creates the pool of the config, the health checks are run by Start.
+func NewPool(cfg *Config) (*Pool, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("rpc pool has no endpoint")
+	}
+	switch cfg.Strategy {
+	case "", FailoverStrategy, RoundRobinStrategy:
+	default:
+		return nil, fmt.Errorf("unsupported rpc pool strategy: %s", cfg.Strategy)
+	}
+
+	p := &Pool{
+		cfg:       cfg,
+		transport: http.DefaultTransport,
+		cooldown:  time.Duration(cfg.FailureCooldownSec) * time.Second,
+	}
+	if p.cooldown == 0 {
+		p.cooldown = defaultFailureCooldownSec * time.Second
+	}
+	for _, rawURL := range cfg.Endpoints {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc pool endpoint %s: %w", rawURL, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("rpc pool endpoint must be http or https, got %s", rawURL)
+		}
+		e := &endpoint{url: u}
+		if cfg.RateLimitPerSec > 0 {
+			burst := cfg.RateLimitBurst
+			if burst <= 0 {
+				burst = 1
+			}
+			e.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimitPerSec), burst)
+		}
+		p.endpoints = append(p.endpoints, e)
+	}
+	return p, nil
+}
+
+// candidates returns the endpoints in the order they are tried, the usable ones first.
+func (p *Pool) candidates() []*endpoint {
+	n := len(p.endpoints)
+	start := 0
+	if p.cfg.Strategy == RoundRobinStrategy {
+		start = int(p.next.Add(1) % uint64(n))
+	}
+
+	now := time.Now()
+	usable := make([]*endpoint, 0, n)
+	var unusable []*endpoint
+	for i := 0; i < n; i++ {
+		e := p.endpoints[(start+i)%n]
+		if e.usable(now) {
+			usable = append(usable, e)
+		} else {
+			unusable = append(unusable, e)
+		}
+	}
+	// the unusable endpoints are the last resort, a failing pool is better than none.
+	return append(usable, unusable...)
+}
+
+// RoundTrip sends the request to the first candidate within its rate limit, and to the next
+// candidates if the node fails. A request rate limited on every endpoint waits for the first one.
+func (p *Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	candidates := p.candidates()
+	tried := make([]bool, len(candidates))
+	var lastErr error
+	for attempt := 0; attempt < len(candidates); attempt++ {
+		i := p.pick(candidates, tried)
+		if i < 0 {
+			// every remaining endpoint is rate limited.
+			i = firstUntried(tried)
+			if err := candidates[i].limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		tried[i] = true
+		e := candidates[i]
+
+		resp, err := p.transport.RoundTrip(rewrite(req, e.url, body))
+		if err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		if err == nil {
+			_ = resp.Body.Close()
+			err = fmt.Errorf("status %s", resp.Status)
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		log.Warn("rpc pool endpoint failed, try the next one", "endpoint", e.url.Redacted(), "err", err)
+		e.fail(p.cooldown)
+		lastErr = err
+	}
+	return nil, fmt.Errorf("all rpc pool endpoints failed, last error: %w", lastErr)
+}
+
+// pick returns the first untried candidate within its rate limit, -1 if there is none.
+func (p *Pool) pick(candidates []*endpoint, tried []bool) int {
+	for i, e := range candidates {
+		if !tried[i] && (e.limiter == nil || e.limiter.Allow()) {
+			return i
+		}
+	}
+	return -1
+}
+
+func firstUntried(tried []bool) int {
+	for i, t := range tried {
+		if !t {
+			return i
+		}
+	}
+	return -1
+}
+
+func rewrite(req *http.Request, target *url.URL, body []byte) *http.Request {
+	out := req.Clone(req.Context())
+	out.URL = target
+	out.Host = target.Host
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+	}
+	return out
+}
+
+// Start polls the head of every endpoint until ctx is done, the endpoints failing to answer are put
+// in cooldown and, if MaxHeadLag is set, the ones behind the highest head by more than it are skipped.
+func (p *Pool) Start(ctx context.Context) {
+	interval := time.Duration(p.cfg.HealthCheckIntervalSec) * time.Second
+	if interval == 0 {
+		interval = defaultHealthCheckIntervalSec * time.Second
+	}
+
+	p.checkHealth(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.checkHealth(ctx)
+			}
+		}
+	}()
+}
+
+func (p *Pool) checkHealth(ctx context.Context) {
+	var highest uint64
+	for _, e := range p.endpoints {
+		head, err := p.blockNumber(ctx, e)
+		if err != nil {
+			log.Warn("rpc pool endpoint health check failed", "endpoint", e.url.Redacted(), "err", err)
+			e.fail(p.cooldown)
+			continue
+		}
+		e.mu.Lock()
+		e.head = head
+		e.mu.Unlock()
+		if head > highest {
+			highest = head
+		}
+	}
+
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		lagging := p.cfg.MaxHeadLag > 0 && e.head+p.cfg.MaxHeadLag < highest
+		if lagging != e.lagging {
+			log.Warn("rpc pool endpoint head consistency changed", "endpoint", e.url.Redacted(), "lagging", lagging, "head", e.head, "highest head", highest)
+		}
+		e.lagging = lagging
+		e.mu.Unlock()
+	}
+}
+
+func (p *Pool) blockNumber(ctx context.Context, e *endpoint) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.transport.RoundTrip(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("status %s", resp.Status)
+	}
+
+	var result struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	if result.Error != nil {
+		return 0, errors.New(result.Error.Message)
+	}
+	var head hexutil.Uint64
+	if err = head.UnmarshalText([]byte(result.Result)); err != nil {
+		return 0, err
+	}
+	return uint64(head), nil
+}
+
+// Dial dials the endpoint alone if cfg is nil, and the endpoints of the pool otherwise,
+// the health checks of the pool run until ctx is done.
+func Dial(ctx context.Context, endpoint string, cfg *Config) (*rpc.Client, error) {
+	if cfg == nil {
+		return rpc.DialContext(ctx, endpoint)
+	}
+	pool, err := NewPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+	pool.Start(ctx)
+	return rpc.DialHTTPWithClient(cfg.Endpoints[0], &http.Client{Transport: pool})
+}
+
+// DialEthClient is Dial returning an ethclient.
+func DialEthClient(ctx context.Context, endpoint string, cfg *Config) (*ethclient.Client, error) {
+	rpcClient, err := Dial(ctx, endpoint, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rpcClient), nil
+}
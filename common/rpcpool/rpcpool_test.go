@@ -0,0 +1,106 @@
+package rpcpool
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testNode struct {
+	*httptest.Server
+	head     atomic.Uint64
+	down     atomic.Bool
+	requests atomic.Int64
+}
+
+func newTestNode(head uint64) *testNode {
+	n := &testNode{}
+	n.head.Store(head)
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.requests.Add(1)
+		if n.down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%x"}`, n.head.Load())
+	}))
+	return n
+}
+
+func TestNewPool(t *testing.T) {
+	_, err := NewPool(&Config{})
+	assert.Error(t, err)
+	_, err = NewPool(&Config{Endpoints: []string{"ws://localhost:8546"}})
+	assert.Error(t, err)
+	_, err = NewPool(&Config{Endpoints: []string{"http://localhost:8545"}, Strategy: "random"})
+	assert.Error(t, err)
+}
+
+func TestPoolFailover(t *testing.T) {
+	node1, node2 := newTestNode(100), newTestNode(100)
+	defer node1.Close()
+	defer node2.Close()
+
+	client, err := DialEthClient(context.Background(), "", &Config{Endpoints: []string{node1.URL, node2.URL}})
+	assert.NoError(t, err)
+
+	head, err := client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), head)
+
+	// the requests fail over to the second node while the first one is down.
+	node1.down.Store(true)
+	node2.head.Store(101)
+	head, err = client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(101), head)
+
+	// and the first node is skipped during its cooldown.
+	requests := node1.requests.Load()
+	node1.down.Store(false)
+	_, err = client.BlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, requests, node1.requests.Load())
+
+	node2.down.Store(true)
+	node1.down.Store(true)
+	_, err = client.BlockNumber(context.Background())
+	assert.Error(t, err)
+}
+
+func TestPoolHeadLag(t *testing.T) {
+	node1, node2 := newTestNode(90), newTestNode(100)
+	defer node1.Close()
+	defer node2.Close()
+
+	pool, err := NewPool(&Config{Endpoints: []string{node1.URL, node2.URL}, MaxHeadLag: 5})
+	assert.NoError(t, err)
+	pool.checkHealth(context.Background())
+	assert.Equal(t, node2.URL, pool.candidates()[0].url.String())
+
+	node1.head.Store(98)
+	pool.checkHealth(context.Background())
+	assert.Equal(t, node1.URL, pool.candidates()[0].url.String())
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	node1, node2 := newTestNode(100), newTestNode(100)
+	defer node1.Close()
+	defer node2.Close()
+
+	client, err := DialEthClient(context.Background(), "", &Config{Endpoints: []string{node1.URL, node2.URL}, Strategy: RoundRobinStrategy})
+	assert.NoError(t, err)
+	requests1, requests2 := node1.requests.Load(), node2.requests.Load()
+	for i := 0; i < 4; i++ {
+		_, err = client.BlockNumber(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, requests1+2, node1.requests.Load())
+	assert.Equal(t, requests2+2, node2.requests.Load())
+}
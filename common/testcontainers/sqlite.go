@@ -0,0 +1,8 @@
+//go:build sqlite
+
+package testcontainers
+
+// the tests built with the sqlite build tag run against a sqlite database file instead of a postgres container.
+func init() {
+	useSQLite = true
+}
@@ -18,9 +18,13 @@ import (
 	"scroll-tech/common/database"
 )
 
+// useSQLite is set by the sqlite build tag.
+var useSQLite bool
+
 // TestcontainerApps testcontainers struct
 type TestcontainerApps struct {
 	postgresContainer *postgres.PostgresContainer
+	sqliteFile        string
 	l2GethContainer   *testcontainers.DockerContainer
 	poSL1Container    compose.ComposeStack
 
@@ -43,6 +47,9 @@ func NewTestcontainerApps() *TestcontainerApps {
 
 // StartPostgresContainer starts a postgres container
 func (t *TestcontainerApps) StartPostgresContainer() error {
+	if useSQLite {
+		return t.createSQLiteFile()
+	}
 	if t.postgresContainer != nil && t.postgresContainer.IsRunning() {
 		return nil
 	}
@@ -61,6 +68,19 @@ func (t *TestcontainerApps) StartPostgresContainer() error {
 	return nil
 }
 
+// createSQLiteFile creates the sqlite database file standing in for the postgres container.
+func (t *TestcontainerApps) createSQLiteFile() error {
+	if t.sqliteFile != "" {
+		return nil
+	}
+	f, err := os.CreateTemp("", "scroll_test_*.db")
+	if err != nil {
+		return fmt.Errorf("failed to create sqlite database file: %w", err)
+	}
+	t.sqliteFile = f.Name()
+	return f.Close()
+}
+
 // StartL2GethContainer starts a L2Geth container
 func (t *TestcontainerApps) StartL2GethContainer() error {
 	if t.l2GethContainer != nil && t.l2GethContainer.IsRunning() {
@@ -134,6 +154,12 @@ func (t *TestcontainerApps) GetPoSL1Client() (*ethclient.Client, error) {
 
 // GetDBEndPoint returns the endpoint of the running postgres container
 func (t *TestcontainerApps) GetDBEndPoint() (string, error) {
+	if useSQLite {
+		if t.sqliteFile == "" {
+			return "", fmt.Errorf("sqlite database is not created")
+		}
+		return fmt.Sprintf("file:%s?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)", t.sqliteFile), nil
+	}
 	if t.postgresContainer == nil || !t.postgresContainer.IsRunning() {
 		return "", fmt.Errorf("postgres is not running")
 	}
@@ -160,13 +186,21 @@ func (t *TestcontainerApps) GetGormDBClient() (*gorm.DB, error) {
 	}
 	dbCfg := &database.Config{
 		DSN:        endpoint,
-		DriverName: "postgres",
+		DriverName: t.GetDBDriverName(),
 		MaxOpenNum: 200,
 		MaxIdleNum: 20,
 	}
 	return database.InitDB(dbCfg)
 }
 
+// GetDBDriverName returns the driver name of the database GetDBEndPoint points to.
+func (t *TestcontainerApps) GetDBDriverName() string {
+	if useSQLite {
+		return database.DriverSQLite
+	}
+	return database.DriverPostgres
+}
+
 // GetL2GethClient returns a ethclient by dialing running L2Geth
 func (t *TestcontainerApps) GetL2GethClient() (*ethclient.Client, error) {
 	endpoint, err := t.GetL2GethEndPoint()
@@ -183,6 +217,14 @@ func (t *TestcontainerApps) GetL2GethClient() (*ethclient.Client, error) {
 // Free stops all running containers
 func (t *TestcontainerApps) Free() {
 	ctx := context.Background()
+	if t.sqliteFile != "" {
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Remove(t.sqliteFile + suffix); err != nil && !os.IsNotExist(err) {
+				log.Printf("failed to remove sqlite database file: %s", err)
+			}
+		}
+		t.sqliteFile = ""
+	}
 	if t.postgresContainer != nil && t.postgresContainer.IsRunning() {
 		if err := t.postgresContainer.Terminate(ctx); err != nil {
 			log.Printf("failed to stop postgres container: %s", err)
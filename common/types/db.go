@@ -109,6 +109,10 @@ const (
 	ProverTaskFailureTypeVerifiedFailed
 	// ProverTaskFailureTypeServerError collect occur error
 	ProverTaskFailureTypeServerError
+	// ProverTaskFailureTypeCancelled prover task aborted by the prover after the coordinator cancelled it
+	ProverTaskFailureTypeCancelled
+	// ProverTaskFailureTypeReset prover task dropped by the admin resetting its task
+	ProverTaskFailureTypeReset
 )
 
 func (r ProverTaskFailureType) String() string {
@@ -123,6 +127,10 @@ func (r ProverTaskFailureType) String() string {
 		return "prover task failure verified failed"
 	case ProverTaskFailureTypeServerError:
 		return "prover task failure server exception"
+	case ProverTaskFailureTypeCancelled:
+		return "prover task failure cancelled"
+	case ProverTaskFailureTypeReset:
+		return "prover task failure reset"
 	default:
 		return fmt.Sprintf("illegal prover task failure type (%d)", int32(r))
 	}
@@ -144,6 +152,8 @@ const (
 	ProvingTaskVerified
 	// ProvingTaskFailed : fail to generate proof
 	ProvingTaskFailed
+	// ProvingTaskQuarantined : the provers failed the task too many times, it's left to the operator
+	ProvingTaskQuarantined
 )
 
 func (ps ProvingStatus) String() string {
@@ -158,6 +168,8 @@ func (ps ProvingStatus) String() string {
 		return "verified"
 	case ProvingTaskFailed:
 		return "failed"
+	case ProvingTaskQuarantined:
+		return "quarantined"
 	default:
 		return fmt.Sprintf("Undefined ProvingStatus (%d)", int32(ps))
 	}
@@ -208,6 +220,8 @@ const (
 	RollupCommitFailed
 	// RollupFinalizeFailed : rollup finalize transaction is confirmed but failed
 	RollupFinalizeFailed
+	// RollupFinalizeQuarantined : rollup finalize transaction failed too many times, the batch is left to the operator
+	RollupFinalizeQuarantined
 )
 
 func (s RollupStatus) String() string {
@@ -226,6 +240,8 @@ func (s RollupStatus) String() string {
 		return "RollupCommitFailed"
 	case RollupFinalizeFailed:
 		return "RollupFinalizeFailed"
+	case RollupFinalizeQuarantined:
+		return "RollupFinalizeQuarantined"
 	default:
 		return fmt.Sprintf("Undefined RollupStatus (%d)", int32(s))
 	}
@@ -72,6 +72,11 @@ func TestProvingStatus(t *testing.T) {
 			ProvingTaskFailed,
 			"failed",
 		},
+		{
+			"ProvingTaskQuarantined",
+			ProvingTaskQuarantined,
+			"quarantined",
+		},
 		{
 			"Undefined",
 			ProvingStatus(999), // Invalid value.
@@ -132,6 +137,11 @@ func TestRollupStatus(t *testing.T) {
 			RollupFinalizeFailed,
 			"RollupFinalizeFailed",
 		},
+		{
+			"RollupFinalizeQuarantined",
+			RollupFinalizeQuarantined,
+			"RollupFinalizeQuarantined",
+		},
 		{
 			"Invalid Value",
 			RollupStatus(999),
@@ -312,6 +322,16 @@ func TestProverTaskFailureType(t *testing.T) {
 			ProverTaskFailureTypeServerError,
 			"prover task failure server exception",
 		},
+		{
+			"ProverTaskFailureTypeCancelled",
+			ProverTaskFailureTypeCancelled,
+			"prover task failure cancelled",
+		},
+		{
+			"ProverTaskFailureTypeReset",
+			ProverTaskFailureTypeReset,
+			"prover task failure reset",
+		},
 		{
 			"Invalid Value",
 			ProverTaskFailureType(999),
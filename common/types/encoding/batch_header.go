@@ -0,0 +1,113 @@
+// Package encoding implements the batch header and chunk layouts committed to the ScrollChain contract,
+// shared by the batch proposer, the relayer and the batch inspector.
+package encoding
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+)
+
+const (
+	// BatchHeaderV0 is the batch header version of the batches committed with calldata (codecv0).
+	BatchHeaderV0 uint8 = 0
+	// BatchHeaderV1 is the batch header version of the batches committed with an EIP-4844 blob (codecv1).
+	BatchHeaderV1 uint8 = 1
+	// BatchHeaderV2 is the batch header version of the batches committed with a compressed blob (codecv2),
+	// the header layout is the same as BatchHeaderV1.
+	BatchHeaderV2 uint8 = 2
+)
+
+const (
+	batchHeaderV0FixedSize = 89
+	batchHeaderV1FixedSize = 121
+	// skippedL1MessageBitmapWordSize the bitmap is made of 256 bit words, one bit per popped L1 message.
+	skippedL1MessageBitmapWordSize = 32
+)
+
+// BatchHeader is the header of a committed batch, its hash is the batch hash.
+//
+//	V0: version(1) | batch index(8) | l1 message popped(8) | total l1 message popped(8) | data hash(32) | parent batch hash(32) | skipped l1 message bitmap
+//	V1: version(1) | batch index(8) | l1 message popped(8) | total l1 message popped(8) | data hash(32) | blob versioned hash(32) | parent batch hash(32) | skipped l1 message bitmap
+type BatchHeader struct {
+	Version              uint8
+	BatchIndex           uint64
+	L1MessagePopped      uint64
+	TotalL1MessagePopped uint64
+	DataHash             common.Hash
+	// BlobVersionedHash is only part of the header since BatchHeaderV1.
+	BlobVersionedHash      common.Hash
+	ParentBatchHash        common.Hash
+	SkippedL1MessageBitmap []byte
+}
+
+// HasBlob returns whether the batch data is committed in a blob.
+func (h *BatchHeader) HasBlob() bool {
+	return h.Version >= BatchHeaderV1
+}
+
+// Encode serializes the batch header.
+func (h *BatchHeader) Encode() []byte {
+	fixedSize := batchHeaderV0FixedSize
+	if h.HasBlob() {
+		fixedSize = batchHeaderV1FixedSize
+	}
+	data := make([]byte, fixedSize+len(h.SkippedL1MessageBitmap))
+	data[0] = h.Version
+	binary.BigEndian.PutUint64(data[1:], h.BatchIndex)
+	binary.BigEndian.PutUint64(data[9:], h.L1MessagePopped)
+	binary.BigEndian.PutUint64(data[17:], h.TotalL1MessagePopped)
+	copy(data[25:], h.DataHash[:])
+	if h.HasBlob() {
+		copy(data[57:], h.BlobVersionedHash[:])
+		copy(data[89:], h.ParentBatchHash[:])
+	} else {
+		copy(data[57:], h.ParentBatchHash[:])
+	}
+	copy(data[fixedSize:], h.SkippedL1MessageBitmap)
+	return data
+}
+
+// Hash returns the batch hash, the keccak256 hash of the encoded header.
+func (h *BatchHeader) Hash() common.Hash {
+	return crypto.Keccak256Hash(h.Encode())
+}
+
+// DecodeBatchHeader deserializes a batch header, the layout is picked by its version byte.
+func DecodeBatchHeader(data []byte) (*BatchHeader, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty batch header")
+	}
+
+	h := &BatchHeader{Version: data[0]}
+	fixedSize := batchHeaderV0FixedSize
+	switch {
+	case h.Version > BatchHeaderV2:
+		return nil, fmt.Errorf("unsupported batch header version: %d", h.Version)
+	case h.HasBlob():
+		fixedSize = batchHeaderV1FixedSize
+	}
+	if len(data) < fixedSize {
+		return nil, fmt.Errorf("invalid batch header v%d length, expected at least %d bytes but got %d", h.Version, fixedSize, len(data))
+	}
+	if (len(data)-fixedSize)%skippedL1MessageBitmapWordSize != 0 {
+		return nil, fmt.Errorf("invalid skipped l1 message bitmap length: %d", len(data)-fixedSize)
+	}
+
+	h.BatchIndex = binary.BigEndian.Uint64(data[1:9])
+	h.L1MessagePopped = binary.BigEndian.Uint64(data[9:17])
+	h.TotalL1MessagePopped = binary.BigEndian.Uint64(data[17:25])
+	h.DataHash = common.BytesToHash(data[25:57])
+	if h.HasBlob() {
+		h.BlobVersionedHash = common.BytesToHash(data[57:89])
+		h.ParentBatchHash = common.BytesToHash(data[89:121])
+	} else {
+		h.ParentBatchHash = common.BytesToHash(data[57:89])
+	}
+	if len(data) > fixedSize {
+		h.SkippedL1MessageBitmap = common.CopyBytes(data[fixedSize:])
+	}
+	return h, nil
+}
@@ -0,0 +1,92 @@
+package encoding
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchHeaderEncodeAndDecode(t *testing.T) {
+	headerV0 := &BatchHeader{
+		Version:                BatchHeaderV0,
+		BatchIndex:             1,
+		L1MessagePopped:        10,
+		TotalL1MessagePopped:   10,
+		DataHash:               common.HexToHash("0x01"),
+		ParentBatchHash:        common.HexToHash("0x02"),
+		SkippedL1MessageBitmap: common.FromHex("00000000000000000000000000000000000000000000000000000000000003ff"),
+	}
+	headerV1 := &BatchHeader{
+		Version:              BatchHeaderV1,
+		BatchIndex:           2,
+		TotalL1MessagePopped: 10,
+		DataHash:             common.HexToHash("0x03"),
+		BlobVersionedHash:    common.HexToHash("0x0104"),
+		ParentBatchHash:      common.HexToHash("0x206ebf4b6a899d9f9ff49072ca074963dc78d91ad69800990e4ac256513b23fd"),
+	}
+	headerV2 := *headerV1
+	headerV2.Version = BatchHeaderV2
+
+	tests := []struct {
+		name    string
+		header  *BatchHeader
+		encoded string
+		hash    string
+	}{
+		{
+			name:    "v0 calldata",
+			header:  headerV0,
+			encoded: "000000000000000001000000000000000a000000000000000a0000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000003ff",
+			hash:    "0x206ebf4b6a899d9f9ff49072ca074963dc78d91ad69800990e4ac256513b23fd",
+		},
+		{
+			name:    "v1 blob",
+			header:  headerV1,
+			encoded: "0100000000000000020000000000000000000000000000000a00000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000104206ebf4b6a899d9f9ff49072ca074963dc78d91ad69800990e4ac256513b23fd",
+			hash:    "0x716e1098334d1ab0366ce69e33fbe0f775d021904e4ff21eef339d597d7ca61d",
+		},
+		{
+			name:    "v2 compressed blob",
+			header:  &headerV2,
+			encoded: "0200000000000000020000000000000000000000000000000a00000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000104206ebf4b6a899d9f9ff49072ca074963dc78d91ad69800990e4ac256513b23fd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := tt.header.Encode()
+			assert.Equal(t, tt.encoded, common.Bytes2Hex(encoded))
+			if tt.hash != "" {
+				assert.Equal(t, tt.hash, tt.header.Hash().Hex())
+			}
+
+			decoded, err := DecodeBatchHeader(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.header, decoded)
+			assert.Equal(t, tt.header.Hash(), decoded.Hash())
+		})
+	}
+}
+
+func TestDecodeBatchHeaderInvalid(t *testing.T) {
+	validV0 := (&BatchHeader{Version: BatchHeaderV0}).Encode()
+	validV1 := (&BatchHeader{Version: BatchHeaderV1}).Encode()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unsupported version", append([]byte{3}, validV1[1:]...)},
+		{"short v0", validV0[:88]},
+		{"short v1", validV1[:120]},
+		{"v1 with v0 length", append([]byte{BatchHeaderV1}, validV0[1:]...)},
+		{"partial bitmap word", append(validV0, make([]byte, 31)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeBatchHeader(tt.data)
+			assert.Error(t, err)
+		})
+	}
+}
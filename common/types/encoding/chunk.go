@@ -0,0 +1,97 @@
+package encoding
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+)
+
+// BlockContextSize is the size of an encoded block context.
+const BlockContextSize = 60
+
+// BlockContext is the context of a L2 block in a committed chunk.
+//
+//	block number(8) | timestamp(8) | base fee(32) | gas limit(8) | num transactions(2) | num l1 messages(2)
+type BlockContext struct {
+	Number          uint64
+	Timestamp       uint64
+	BaseFee         *big.Int
+	GasLimit        uint64
+	NumTransactions uint16
+	NumL1Messages   uint16
+}
+
+// Encode serializes the block context.
+func (c *BlockContext) Encode() []byte {
+	data := make([]byte, BlockContextSize)
+	binary.BigEndian.PutUint64(data[0:], c.Number)
+	binary.BigEndian.PutUint64(data[8:], c.Timestamp)
+	if c.BaseFee != nil {
+		c.BaseFee.FillBytes(data[16:48])
+	}
+	binary.BigEndian.PutUint64(data[48:], c.GasLimit)
+	binary.BigEndian.PutUint16(data[56:], c.NumTransactions)
+	binary.BigEndian.PutUint16(data[58:], c.NumL1Messages)
+	return data
+}
+
+// DecodeBlockContext deserializes a block context.
+func DecodeBlockContext(data []byte) (*BlockContext, error) {
+	if len(data) != BlockContextSize {
+		return nil, fmt.Errorf("invalid block context length, expected %d bytes but got %d", BlockContextSize, len(data))
+	}
+	return &BlockContext{
+		Number:          binary.BigEndian.Uint64(data[0:8]),
+		Timestamp:       binary.BigEndian.Uint64(data[8:16]),
+		BaseFee:         new(big.Int).SetBytes(data[16:48]),
+		GasLimit:        binary.BigEndian.Uint64(data[48:56]),
+		NumTransactions: binary.BigEndian.Uint16(data[56:58]),
+		NumL1Messages:   binary.BigEndian.Uint16(data[58:60]),
+	}, nil
+}
+
+// EncodeChunk serializes the block contexts of a chunk, followed by its L2 transactions for the calldata (V0)
+// batches, the blob batches carry the transactions in the blob and pass nil.
+//
+//	num blocks(1) | block contexts | l2 transactions
+func EncodeChunk(blockContexts []*BlockContext, l2Transactions []byte) ([]byte, error) {
+	if len(blockContexts) == 0 || len(blockContexts) > 255 {
+		return nil, fmt.Errorf("invalid number of blocks in chunk: %d", len(blockContexts))
+	}
+	data := make([]byte, 1, 1+len(blockContexts)*BlockContextSize+len(l2Transactions))
+	data[0] = byte(len(blockContexts))
+	for _, blockContext := range blockContexts {
+		data = append(data, blockContext.Encode()...)
+	}
+	return append(data, l2Transactions...), nil
+}
+
+// DecodeChunk deserializes the block contexts of an encoded chunk and returns the L2 transactions following them,
+// which are empty for the blob batches.
+func DecodeChunk(data []byte) ([]*BlockContext, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("empty chunk")
+	}
+	numBlocks := int(data[0])
+	if numBlocks == 0 {
+		return nil, nil, fmt.Errorf("invalid number of blocks in chunk: 0")
+	}
+	if len(data) < 1+numBlocks*BlockContextSize {
+		return nil, nil, fmt.Errorf("invalid chunk length, expected at least %d bytes for %d blocks but got %d", 1+numBlocks*BlockContextSize, numBlocks, len(data))
+	}
+
+	blockContexts := make([]*BlockContext, numBlocks)
+	for i := range blockContexts {
+		start := 1 + i*BlockContextSize
+		blockContext, err := DecodeBlockContext(data[start : start+BlockContextSize])
+		if err != nil {
+			return nil, nil, err
+		}
+		blockContexts[i] = blockContext
+	}
+	var l2Transactions []byte
+	if rest := data[1+numBlocks*BlockContextSize:]; len(rest) > 0 {
+		l2Transactions = rest
+	}
+	return blockContexts, l2Transactions, nil
+}
@@ -0,0 +1,75 @@
+package encoding
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockContextEncodeAndDecode(t *testing.T) {
+	blockContext := &BlockContext{
+		Number:          100,
+		Timestamp:       1700000000,
+		BaseFee:         big.NewInt(1000000),
+		GasLimit:        10000000,
+		NumTransactions: 3,
+		NumL1Messages:   1,
+	}
+	encoded := blockContext.Encode()
+	assert.Equal(t, "0000000000000064000000006553f10000000000000000000000000000000000000000000000000000000000000f4240000000000098968000030001", common.Bytes2Hex(encoded))
+
+	decoded, err := DecodeBlockContext(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, blockContext, decoded)
+
+	// a nil base fee is encoded as zero
+	decoded, err = DecodeBlockContext((&BlockContext{Number: 1}).Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, decoded.BaseFee.Sign())
+
+	_, err = DecodeBlockContext(encoded[:BlockContextSize-1])
+	assert.Error(t, err)
+}
+
+func TestChunkEncodeAndDecode(t *testing.T) {
+	blockContexts := []*BlockContext{
+		{Number: 1, Timestamp: 10, BaseFee: big.NewInt(1), GasLimit: 100, NumTransactions: 2, NumL1Messages: 1},
+		{Number: 2, Timestamp: 12, BaseFee: big.NewInt(2), GasLimit: 100},
+	}
+	l2Transactions := common.FromHex("00000003c0c0c0")
+
+	tests := []struct {
+		name           string
+		l2Transactions []byte
+	}{
+		{"calldata chunk", l2Transactions},
+		{"blob chunk", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := EncodeChunk(blockContexts, tt.l2Transactions)
+			assert.NoError(t, err)
+			assert.Len(t, encoded, 1+2*BlockContextSize+len(tt.l2Transactions))
+			assert.Equal(t, byte(2), encoded[0])
+
+			decodedBlockContexts, decodedL2Transactions, err := DecodeChunk(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, blockContexts, decodedBlockContexts)
+			assert.Equal(t, tt.l2Transactions, decodedL2Transactions)
+		})
+	}
+
+	_, err := EncodeChunk(nil, nil)
+	assert.Error(t, err)
+	_, err = EncodeChunk(make([]*BlockContext, 256), nil)
+	assert.Error(t, err)
+
+	encoded, err := EncodeChunk(blockContexts, nil)
+	assert.NoError(t, err)
+	for _, data := range [][]byte{nil, {0}, encoded[:len(encoded)-1]} {
+		_, _, err = DecodeChunk(data)
+		assert.Error(t, err)
+	}
+}
@@ -26,4 +26,27 @@ const (
 	ErrCoordinatorHandleZkProofFailure = 20003
 	// ErrCoordinatorEmptyProofData get empty proof data
 	ErrCoordinatorEmptyProofData = 20004
+	// ErrCoordinatorAdminUnauthorized is calling the admin api without a valid secret
+	ErrCoordinatorAdminUnauthorized = 20005
+	// ErrCoordinatorAdminFailure is handling the admin request error
+	ErrCoordinatorAdminFailure = 20006
+	// ErrCoordinatorReportProgressFailure is handling the prover progress report error
+	ErrCoordinatorReportProgressFailure = 20007
+	// ErrCoordinatorRateLimited is the prover exceeding its rate limits or being temporarily banned
+	ErrCoordinatorRateLimited = 20008
+	// ErrCoordinatorShuttingDown is the coordinator refusing new provers and tasks while it shuts down
+	ErrCoordinatorShuttingDown = 20009
+	// ErrCoordinatorAckCancelTaskFailure is handling the prover acknowledgement of a task cancellation error
+	ErrCoordinatorAckCancelTaskFailure = 20010
+	// ErrCoordinatorProofUploadFailure is uploading a part of a proof, or assembling the proof of the parts, error
+	ErrCoordinatorProofUploadFailure = 20011
+
+	// ErrRollupAdminParameterInvalidNo is invalid params of the rollup admin api
+	ErrRollupAdminParameterInvalidNo = 30001
+	// ErrRollupAdminUnauthorized is calling the rollup admin api without a valid secret
+	ErrRollupAdminUnauthorized = 30002
+	// ErrRollupAdminFailure is handling the rollup admin request error
+	ErrRollupAdminFailure = 30003
+	// ErrRollupApprovalUnauthorized is submitting a batch approval not signed by one of the approvers
+	ErrRollupApprovalUnauthorized = 30004
 )
@@ -29,6 +29,26 @@ type Identity struct {
 	Challenge string `json:"challenge"`
 	// HardForkName the hard fork name
 	HardForkName string `json:"hard_fork_name"`
+	// Hardware the hardware advertised by the prover, nil if it isn't advertised
+	Hardware *HardwareInfo `json:"hardware,omitempty" rlp:"optional"`
+	// ConcurrencyLimits the concurrency limits advertised by the prover, in proof type order
+	ConcurrencyLimits []ConcurrencyLimit `json:"concurrency_limits,omitempty" rlp:"optional"`
+}
+
+// HardwareInfo the hardware a prover advertises at login, signed with its identity so the task routing can't be
+// tampered with. A nil hardware isn't encoded, so the signatures of the provers which don't advertise it are unchanged.
+type HardwareInfo struct {
+	GPUModel    string `json:"gpu_model"`
+	GPUMemoryMB uint64 `json:"gpu_memory_mb"`
+	CPUCores    uint64 `json:"cpu_cores"`
+	MemoryMB    uint64 `json:"memory_mb"`
+}
+
+// ConcurrencyLimit the max number of tasks of a proof type a prover runs at once, advertised at login and signed
+// with its identity. Only the limits which are set are listed.
+type ConcurrencyLimit struct {
+	ProofType ProofType `json:"proof_type"`
+	MaxTasks  uint32    `json:"max_tasks"`
 }
 
 // SignWithKey auth message with private key and set public key in auth message's Identity
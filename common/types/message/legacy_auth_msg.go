@@ -27,6 +27,10 @@ type LegacyIdentity struct {
 	ProverVersion string `json:"prover_version"`
 	// Challenge unique challenge generated by manager
 	Challenge string `json:"challenge"`
+	// Hardware the hardware advertised by the prover, nil if it isn't advertised
+	Hardware *HardwareInfo `json:"hardware,omitempty" rlp:"optional"`
+	// ConcurrencyLimits the concurrency limits advertised by the prover, in proof type order
+	ConcurrencyLimits []ConcurrencyLimit `json:"concurrency_limits,omitempty" rlp:"optional"`
 }
 
 // SignWithKey auth message with private key and set public key in auth message's Identity
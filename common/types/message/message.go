@@ -3,6 +3,7 @@ package message
 import (
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -25,6 +26,41 @@ const (
 	ProofFailureNoPanic
 )
 
+// ProofErrorCode classifies why a prover failed to generate a proof, for the automated triage of the failures
+type ProofErrorCode uint8
+
+const (
+	// ProofErrorUndefined the failure is not classified
+	ProofErrorUndefined ProofErrorCode = iota
+	// ProofErrorWitnessGeneration the witness of the task could not be generated
+	ProofErrorWitnessGeneration
+	// ProofErrorRowOverflow the task needs more rows than the circuits have
+	ProofErrorRowOverflow
+	// ProofErrorVkMismatch the verification key of the prover's circuits is not the expected one
+	ProofErrorVkMismatch
+	// ProofErrorOutOfMemory the prover ran out of memory
+	ProofErrorOutOfMemory
+	// ProofErrorTimeout the proving timed out, or the task deadline passed
+	ProofErrorTimeout
+)
+
+func (c ProofErrorCode) String() string {
+	switch c {
+	case ProofErrorWitnessGeneration:
+		return "witness_generation"
+	case ProofErrorRowOverflow:
+		return "row_overflow"
+	case ProofErrorVkMismatch:
+		return "vk_mismatch"
+	case ProofErrorOutOfMemory:
+		return "out_of_memory"
+	case ProofErrorTimeout:
+		return "timeout"
+	default:
+		return "undefined"
+	}
+}
+
 // RespStatus represents status code from prover to scroll
 type RespStatus uint32
 
@@ -58,7 +94,64 @@ const (
 	ProofTypeBatch
 )
 
+// TaskPriority is the scheduling priority of a prover task, stored per chunk and batch task and changed by the
+// operator with the task_priority admin api.
+type TaskPriority uint8
+
+func (p TaskPriority) String() string {
+	switch p {
+	case TaskPriorityLow:
+		return "task priority low"
+	case TaskPriorityNormal:
+		return "task priority normal"
+	case TaskPriorityHigh:
+		return "task priority high"
+	default:
+		return fmt.Sprintf("illegal task priority: %d", p)
+	}
+}
+
+const (
+	// TaskPriorityLow is the priority of the backlog work the operator delays, e.g. the re-proving of old tasks.
+	TaskPriorityLow TaskPriority = iota
+	// TaskPriorityNormal is the default priority of the chunk tasks.
+	TaskPriorityNormal
+	// TaskPriorityHigh is the priority of the tasks blocking finalization, the default of the batch tasks.
+	TaskPriorityHigh
+)
+
+// ProvingStage is the stage a prover task is in, reported by the prover.
+type ProvingStage uint8
+
+func (s ProvingStage) String() string {
+	switch s {
+	case ProvingStageUndefined:
+		return "proving stage undefined"
+	case ProvingStageWitnessGeneration:
+		return "proving stage witness generation"
+	case ProvingStageProving:
+		return "proving stage proving"
+	case ProvingStageAggregating:
+		return "proving stage aggregating"
+	default:
+		return fmt.Sprintf("illegal proving stage: %d", s)
+	}
+}
+
+const (
+	// ProvingStageUndefined the prover has not reported any progress
+	ProvingStageUndefined ProvingStage = iota
+	// ProvingStageWitnessGeneration the prover is generating the witness from the traces
+	ProvingStageWitnessGeneration
+	// ProvingStageProving the prover is generating the proof
+	ProvingStageProving
+	// ProvingStageAggregating the prover is aggregating the sub proofs
+	ProvingStageAggregating
+)
+
 // GenerateToken generates token
+//
+// Deprecated: the coordinator issues the login challenges as jwt tokens, see coordinator/internal/middleware.
 func GenerateToken() (string, error) {
 	b := make([]byte, 16)
 	if _, err := rand.Read(b); err != nil {
@@ -130,15 +223,182 @@ func (a *ProofMsg) PublicKey() (string, error) {
 	return a.publicKey, nil
 }
 
+// ProofSubmission contains the fields of a proof submission signed by the prover. The signature binds the
+// submission to the prover task of the UUID, and the nonce, which must increase with every submission of
+// the prover, makes a captured submission useless once it has been handled.
+type ProofSubmission struct {
+	UUID     string     `json:"uuid"`
+	TaskID   string     `json:"task_id"`
+	TaskType ProofType  `json:"task_type"`
+	Status   RespStatus `json:"status"`
+	// ProofHash is the keccak256 hash of the submitted proof json, the empty one for a failure.
+	ProofHash common.Hash `json:"proof_hash"`
+	Nonce     uint64      `json:"nonce"`
+}
+
+// Hash returns the hash of the submission, which is the message signed by the prover.
+func (s *ProofSubmission) Hash() ([]byte, error) {
+	return rlpHash(s)
+}
+
+// Sign signs the submission and returns the hex encoded signature.
+func (s *ProofSubmission) Sign(priv *ecdsa.PrivateKey) (string, error) {
+	return signRLP(s, priv)
+}
+
+// PublicKey recovers the compressed public key of the prover from the signature of the submission.
+func (s *ProofSubmission) PublicKey(signature string) (string, error) {
+	pk, err := recoverRLPSigner(s, signature)
+	if err != nil {
+		return "", err
+	}
+	return common.Bytes2Hex(crypto.CompressPubkey(pk)), nil
+}
+
+// TaskAssignment contains the fields of a task assigned to a prover, signed by the coordinator so the prover checks
+// the task genuinely comes from the coordinator, e.g. when it connects through a proxy, before proving it.
+type TaskAssignment struct {
+	UUID     string    `json:"uuid"`
+	TaskID   string    `json:"task_id"`
+	TaskType ProofType `json:"task_type"`
+	// TaskDataHash is the keccak256 hash of the task data.
+	TaskDataHash common.Hash `json:"task_data_hash"`
+	HardForkName string      `json:"hard_fork_name"`
+	TaskHeight   uint64      `json:"task_height"`
+	// ProverPublicKey binds the task to the prover it's assigned to.
+	ProverPublicKey string `json:"prover_public_key"`
+}
+
+// Hash returns the hash of the task assignment, which is the message signed by the coordinator.
+func (a *TaskAssignment) Hash() ([]byte, error) {
+	return rlpHash(a)
+}
+
+// Sign signs the task assignment and returns the hex encoded signature.
+func (a *TaskAssignment) Sign(priv *ecdsa.PrivateKey) (string, error) {
+	return signRLP(a, priv)
+}
+
+// Signer recovers the address of the coordinator key from the signature of the task assignment.
+func (a *TaskAssignment) Signer(signature string) (common.Address, error) {
+	pk, err := recoverRLPSigner(a, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pk), nil
+}
+
+// WorkReceipt records a proof accepted by the coordinator, signed by the coordinator so an external
+// rewards system can pay the prover for it.
+type WorkReceipt struct {
+	UUID            string    `json:"uuid"`
+	TaskID          string    `json:"task_id"`
+	TaskType        ProofType `json:"task_type"`
+	ProverPublicKey string    `json:"prover_public_key"`
+	// ProofHash is the keccak256 hash of the submitted proof json, as signed by the prover in its submission.
+	ProofHash      common.Hash `json:"proof_hash"`
+	ProvingTimeSec uint64      `json:"proving_time_sec"`
+	// AcceptedAt is the unix timestamp in seconds the proof was accepted at.
+	AcceptedAt uint64 `json:"accepted_at"`
+}
+
+// Hash returns the hash of the receipt, which is the message signed by the coordinator.
+func (r *WorkReceipt) Hash() ([]byte, error) {
+	return rlpHash(r)
+}
+
+// Sign signs the receipt and returns the hex encoded signature.
+func (r *WorkReceipt) Sign(priv *ecdsa.PrivateKey) (string, error) {
+	return signRLP(r, priv)
+}
+
+// Signer recovers the address of the coordinator key from the signature of the receipt.
+func (r *WorkReceipt) Signer(signature string) (common.Address, error) {
+	pk, err := recoverRLPSigner(r, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pk), nil
+}
+
+// rlpHash returns the keccak256 hash of the rlp encoding of the payload, which is the message signed.
+func rlpHash(payload interface{}) ([]byte, error) {
+	byt, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return nil, err
+	}
+	hash := crypto.Keccak256Hash(byt)
+	return hash[:], nil
+}
+
+// signRLP signs the rlp hash of the payload and returns the hex encoded signature.
+func signRLP(payload interface{}, priv *ecdsa.PrivateKey) (string, error) {
+	hash, err := rlpHash(payload)
+	if err != nil {
+		return "", err
+	}
+	sig, err := crypto.Sign(hash, priv)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(sig), nil
+}
+
+// recoverRLPSigner recovers the public key of the hex encoded signature of the rlp hash of the payload.
+func recoverRLPSigner(payload interface{}, signature string) (*ecdsa.PublicKey, error) {
+	hash, err := rlpHash(payload)
+	if err != nil {
+		return nil, err
+	}
+	sig := common.FromHex(signature)
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length, expected: %d, got: %d", crypto.SignatureLength, len(sig))
+	}
+	return crypto.SigToPub(hash, sig)
+}
+
 // TaskMsg is a wrapper type around db ProveTask type.
 type TaskMsg struct {
 	UUID            string           `json:"uuid"`
 	ID              string           `json:"id"`
 	Type            ProofType        `json:"type,omitempty"`
+	Priority        TaskPriority     `json:"priority,omitempty"`
+	Deadline        int64            `json:"deadline,omitempty"` // unix timestamp in seconds, 0 means no deadline
 	BatchTaskDetail *BatchTaskDetail `json:"batch_task_detail,omitempty"`
 	ChunkTaskDetail *ChunkTaskDetail `json:"chunk_task_detail,omitempty"`
 }
 
+// ProgressMsg is sent periodically by the prover to report the progress of its task.
+type ProgressMsg struct {
+	UUID    string       `json:"uuid"`
+	ID      string       `json:"id"`
+	Type    ProofType    `json:"type,omitempty"`
+	Stage   ProvingStage `json:"stage"`
+	Percent uint8        `json:"percent"` // percent complete of the whole task, 0-100
+	// Recovered is set when the prover resumes the task from its local task cache after a restart.
+	Recovered bool `json:"recovered,omitempty"`
+}
+
+// Validate checks the reported stage and percent are in range.
+func (p *ProgressMsg) Validate() error {
+	if p.Stage == ProvingStageUndefined || p.Stage > ProvingStageAggregating {
+		return fmt.Errorf("invalid proving stage: %d", p.Stage)
+	}
+	if p.Percent > 100 {
+		return fmt.Errorf("invalid percent: %d", p.Percent)
+	}
+	return nil
+}
+
+// CancelTaskMsg is sent by the coordinator to tell the prover to abort a task nobody wants the proof of anymore,
+// the prover acknowledges it once the work is stopped.
+type CancelTaskMsg struct {
+	UUID   string    `json:"uuid"`
+	ID     string    `json:"id"`
+	Type   ProofType `json:"type,omitempty"`
+	Reason string    `json:"reason"`
+}
+
 // ChunkTaskDetail is a type containing ChunkTask detail.
 type ChunkTaskDetail struct {
 	BlockHashes []common.Hash `json:"block_hashes"`
@@ -148,6 +408,45 @@ type ChunkTaskDetail struct {
 type BatchTaskDetail struct {
 	ChunkInfos  []*ChunkInfo  `json:"chunk_infos"`
 	ChunkProofs []*ChunkProof `json:"chunk_proofs"`
+	// BlobCommitment is only set for the batches committed with an EIP-4844 blob, since codecv1.
+	BlobCommitment *BlobCommitment `json:"blob_commitment,omitempty"`
+}
+
+// BlobCommitment is the KZG commitment of the blob of a batch, and the point evaluation
+// proving the blob holds the batch data, they are public inputs of the batch circuit.
+type BlobCommitment struct {
+	BlobVersionedHash common.Hash   `json:"blob_versioned_hash"`
+	Commitment        hexutil.Bytes `json:"kzg_commitment"`
+	Proof             hexutil.Bytes `json:"kzg_proof"`
+	Z                 common.Hash   `json:"z"`
+	Y                 common.Hash   `json:"y"`
+}
+
+const (
+	// blobDataProofLength is the length of z || y || kzg commitment || kzg proof.
+	blobDataProofLength = 32 + 32 + 48 + 48
+	// blobCommitmentVersionKZG is the version byte of a blob versioned hash.
+	blobCommitmentVersionKZG = 0x01
+)
+
+// NewBlobCommitment decodes the blob data proof the batch proposer stores, z || y || kzg commitment || kzg proof,
+// and derives the versioned hash from the commitment.
+func NewBlobCommitment(blobDataProof []byte) (*BlobCommitment, error) {
+	if len(blobDataProof) != blobDataProofLength {
+		return nil, fmt.Errorf("invalid blob data proof length, expected: %d, got: %d", blobDataProofLength, len(blobDataProof))
+	}
+
+	commitment := blobDataProof[64:112]
+	versionedHash := sha256.Sum256(commitment)
+	versionedHash[0] = blobCommitmentVersionKZG
+
+	return &BlobCommitment{
+		BlobVersionedHash: versionedHash,
+		Commitment:        common.CopyBytes(commitment),
+		Proof:             common.CopyBytes(blobDataProof[112:]),
+		Z:                 common.BytesToHash(blobDataProof[:32]),
+		Y:                 common.BytesToHash(blobDataProof[32:64]),
+	}, nil
 }
 
 // ProofDetail is the message received from provers that contains zk proof, the status of
@@ -159,6 +458,10 @@ type ProofDetail struct {
 	ChunkProof *ChunkProof `json:"chunk_proof,omitempty"`
 	BatchProof *BatchProof `json:"batch_proof,omitempty"`
 	Error      string      `json:"error,omitempty"`
+	// ErrorCode and ErrorDetails classify the failure of a proof with the StatusProofError status. The details
+	// are free-form key values for the triage and are not signed, an undefined code keeps the legacy hash.
+	ErrorCode    ProofErrorCode    `json:"error_code,omitempty" rlp:"optional"`
+	ErrorDetails map[string]string `json:"error_details,omitempty" rlp:"-"`
 }
 
 // Hash return proofMsg content hash.
@@ -202,13 +505,30 @@ type ChunkProof struct {
 	RowUsages  []SubCircuitRowUsage `json:"row_usages,omitempty"`
 }
 
+// SanityCheck checks whether a ChunkProof is in a legal format
+func (ap *ChunkProof) SanityCheck() error {
+	if ap == nil {
+		return errors.New("chunk_proof is nil")
+	}
+
+	if len(ap.Proof) == 0 {
+		return errors.New("proof not ready")
+	}
+	if len(ap.Instances) == 0 {
+		return errors.New("instances not ready")
+	}
+
+	return nil
+}
+
 // BatchProof includes the proof info that are required for batch verification and rollup.
 type BatchProof struct {
 	Proof     []byte `json:"proof"`
 	Instances []byte `json:"instances"`
 	Vk        []byte `json:"vk"`
 	// cross-reference between cooridinator computation and prover compution
-	GitVersion string `json:"git_version,omitempty"`
+	BlobCommitment *BlobCommitment `json:"blob_commitment,omitempty"`
+	GitVersion     string          `json:"git_version,omitempty"`
 }
 
 // SanityCheck checks whether an BatchProof is in a legal format
@@ -1,6 +1,7 @@
 package message
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"testing"
 
@@ -58,6 +59,31 @@ func TestIdentityHash(t *testing.T) {
 	assert.Equal(t, expectedHash, hex.EncodeToString(hash))
 }
 
+func TestLegacyIdentityHashWithCapabilities(t *testing.T) {
+	identity := &LegacyIdentity{Challenge: "challenge", ProverName: "test", ProverVersion: "v1.0.0"}
+
+	// the provers which don't advertise their capabilities sign the same message as before.
+	hash, err := identity.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, "b6426468272a88667c9c591b1312dc88434d8d2cb7413e116df6a56ed5bde7ff", hex.EncodeToString(hash))
+
+	// the advertised capabilities are signed, the hashes of the prover login message tests.
+	identity.Hardware = &HardwareInfo{GPUModel: "NVIDIA A100", GPUMemoryMB: 81920, CPUCores: 64, MemoryMB: 512000}
+	hash, err = identity.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, "f571e188b29a462bb2c37a3d3b59a595a1ca31c81cea6daf5002d2e671320a6c", hex.EncodeToString(hash))
+
+	identity.ConcurrencyLimits = []ConcurrencyLimit{{ProofType: ProofTypeChunk, MaxTasks: 1}, {ProofType: ProofTypeBatch, MaxTasks: 0}}
+	hash, err = identity.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, "12412e9e071334579e95f0bbac337a150e117bab9fbb2f6e725ca1378f255b48", hex.EncodeToString(hash))
+
+	identity.Hardware = nil
+	hash, err = identity.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, "631178006b007c62b700faf78f6814be21e1e7f3c7b5124ffe964e5b37148851", hex.EncodeToString(hash))
+}
+
 func TestProofMessageSignVerifyPublicKey(t *testing.T) {
 	privkey, err := crypto.GenerateKey()
 	assert.NoError(t, err)
@@ -110,6 +136,22 @@ func TestProofDetailHash(t *testing.T) {
 	assert.NoError(t, err)
 	expectedHash := "01128ea9006601146ba80dbda959c96ebaefca463e78570e473a57d821db5ec1"
 	assert.Equal(t, expectedHash, hex.EncodeToString(hash))
+
+	// the error code is signed, the details are not.
+	proofDetail.ErrorCode = ProofErrorRowOverflow
+	codeHash, err := proofDetail.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, codeHash)
+	proofDetail.ErrorDetails = map[string]string{"circuit": "evm"}
+	detailsHash, err := proofDetail.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, codeHash, detailsHash)
+}
+
+func TestProofErrorCodeString(t *testing.T) {
+	assert.Equal(t, "witness_generation", ProofErrorWitnessGeneration.String())
+	assert.Equal(t, "timeout", ProofErrorTimeout.String())
+	assert.Equal(t, "undefined", ProofErrorCode(100).String())
 }
 
 func TestProveTypeString(t *testing.T) {
@@ -123,6 +165,28 @@ func TestProveTypeString(t *testing.T) {
 	assert.Equal(t, "illegal proof type: 3", illegalProof.String())
 }
 
+func TestTaskPriorityString(t *testing.T) {
+	assert.Equal(t, "task priority low", TaskPriorityLow.String())
+	assert.Equal(t, "task priority normal", TaskPriorityNormal.String())
+	assert.Equal(t, "task priority high", TaskPriorityHigh.String())
+	assert.Equal(t, "illegal task priority: 3", TaskPriority(3).String())
+}
+
+func TestProgressMsgValidate(t *testing.T) {
+	assert.Equal(t, "proving stage proving", ProvingStageProving.String())
+	assert.Equal(t, "illegal proving stage: 4", ProvingStage(4).String())
+
+	progressMsg := &ProgressMsg{ID: "testID", Type: ProofTypeChunk, Stage: ProvingStageWitnessGeneration, Percent: 30}
+	assert.NoError(t, progressMsg.Validate())
+
+	progressMsg.Percent = 101
+	assert.Error(t, progressMsg.Validate())
+
+	progressMsg.Percent = 100
+	progressMsg.Stage = ProvingStageUndefined
+	assert.Error(t, progressMsg.Validate())
+}
+
 func TestProofMsgPublicKey(t *testing.T) {
 	privkey, err := crypto.GenerateKey()
 	assert.NoError(t, err)
@@ -156,3 +220,185 @@ func TestProofMsgPublicKey(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, common.Bytes2Hex(crypto.CompressPubkey(&privkey.PublicKey)), pk)
 }
+
+func TestProofSubmissionSignPublicKey(t *testing.T) {
+	privkey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	submission := &ProofSubmission{
+		UUID:      "c3d8ad2e-0c3c-4b48-9a3c-7c6b0bbd7d5e",
+		TaskID:    "testID",
+		TaskType:  ProofTypeChunk,
+		Status:    StatusOk,
+		ProofHash: crypto.Keccak256Hash([]byte("testProof")),
+		Nonce:     1717000000000,
+	}
+
+	hash, err := submission.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, "eed0e66f8c6ea2802ea5c5959c06da34eca8db0c79ce6bf49fea3ade568b2068", hex.EncodeToString(hash))
+
+	signature, err := submission.Sign(privkey)
+	assert.NoError(t, err)
+	pk, err := submission.PublicKey(signature)
+	assert.NoError(t, err)
+	assert.Equal(t, common.Bytes2Hex(crypto.CompressPubkey(&privkey.PublicKey)), pk)
+
+	// a replay with another nonce recovers another public key.
+	submission.Nonce++
+	pk, err = submission.PublicKey(signature)
+	assert.NoError(t, err)
+	assert.NotEqual(t, common.Bytes2Hex(crypto.CompressPubkey(&privkey.PublicKey)), pk)
+
+	_, err = submission.PublicKey("0x1234")
+	assert.Error(t, err)
+}
+
+func TestWorkReceiptSignSigner(t *testing.T) {
+	privkey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	receipt := &WorkReceipt{
+		UUID:            "c3d8ad2e-0c3c-4b48-9a3c-7c6b0bbd7d5e",
+		TaskID:          "testID",
+		TaskType:        ProofTypeBatch,
+		ProverPublicKey: common.Bytes2Hex(crypto.CompressPubkey(&privkey.PublicKey)),
+		ProofHash:       crypto.Keccak256Hash([]byte("testProof")),
+		ProvingTimeSec:  600,
+		AcceptedAt:      1717000000,
+	}
+
+	signature, err := receipt.Sign(privkey)
+	assert.NoError(t, err)
+	signer, err := receipt.Signer(signature)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(privkey.PublicKey), signer)
+
+	// a tampered receipt recovers another signer.
+	receipt.ProvingTimeSec++
+	signer, err = receipt.Signer(signature)
+	assert.NoError(t, err)
+	assert.NotEqual(t, crypto.PubkeyToAddress(privkey.PublicKey), signer)
+
+	_, err = receipt.Signer("0x1234")
+	assert.Error(t, err)
+}
+
+func TestChunkProofSanityCheck(t *testing.T) {
+	var nilProof *ChunkProof
+	assert.Error(t, nilProof.SanityCheck())
+	assert.Error(t, (&ChunkProof{Instances: []byte{1}}).SanityCheck())
+	assert.Error(t, (&ChunkProof{Proof: []byte{1}}).SanityCheck())
+	assert.NoError(t, (&ChunkProof{Proof: []byte{1}, Instances: []byte{1}}).SanityCheck())
+}
+
+func TestNewBlobCommitment(t *testing.T) {
+	blobDataProof := make([]byte, 160)
+	for i := range blobDataProof {
+		blobDataProof[i] = byte(i)
+	}
+
+	blobCommitment, err := NewBlobCommitment(blobDataProof)
+	assert.NoError(t, err)
+	assert.Equal(t, common.BytesToHash(blobDataProof[:32]), blobCommitment.Z)
+	assert.Equal(t, common.BytesToHash(blobDataProof[32:64]), blobCommitment.Y)
+	assert.Equal(t, blobDataProof[64:112], []byte(blobCommitment.Commitment))
+	assert.Equal(t, blobDataProof[112:], []byte(blobCommitment.Proof))
+
+	versionedHash := sha256.Sum256(blobDataProof[64:112])
+	versionedHash[0] = 0x01
+	assert.Equal(t, common.Hash(versionedHash), blobCommitment.BlobVersionedHash)
+
+	_, err = NewBlobCommitment(blobDataProof[:64])
+	assert.Error(t, err)
+}
+
+func TestChunkInfoPublicInputHash(t *testing.T) {
+	chunkInfo := &ChunkInfo{
+		ChainID:       534352,
+		PrevStateRoot: common.HexToHash("0x01"),
+		PostStateRoot: common.HexToHash("0x02"),
+		WithdrawRoot:  common.HexToHash("0x03"),
+		DataHash:      common.HexToHash("0x04"),
+	}
+
+	data := append(common.FromHex("0x0000000000082750"), chunkInfo.PrevStateRoot.Bytes()...)
+	data = append(data, chunkInfo.PostStateRoot.Bytes()...)
+	data = append(data, chunkInfo.WithdrawRoot.Bytes()...)
+	data = append(data, chunkInfo.DataHash.Bytes()...)
+
+	piHash, err := chunkInfo.PublicInputHash(PublicInputHashV1)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.Keccak256Hash(data), piHash)
+
+	_, err = chunkInfo.PublicInputHash(PublicInputHashUndefined)
+	assert.Error(t, err)
+}
+
+func TestGetPublicInputHashVersion(t *testing.T) {
+	version, err := GetPublicInputHashVersion("curie", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, PublicInputHashV1, version)
+
+	_, err = GetPublicInputHashVersion("darwin", nil)
+	assert.Error(t, err)
+
+	version, err = GetPublicInputHashVersion("darwin", map[string]PublicInputHashVersion{"darwin": PublicInputHashV1})
+	assert.NoError(t, err)
+	assert.Equal(t, PublicInputHashV1, version)
+
+	_, err = GetPublicInputHashVersion("darwin", map[string]PublicInputHashVersion{"darwin": 2})
+	assert.Error(t, err)
+}
+
+func TestCheckChunkInfo(t *testing.T) {
+	expected := &ChunkInfo{
+		ChainID:       534352,
+		PrevStateRoot: common.HexToHash("0x01"),
+		PostStateRoot: common.HexToHash("0x02"),
+		WithdrawRoot:  common.HexToHash("0x03"),
+		DataHash:      common.HexToHash("0x04"),
+	}
+	reported := *expected
+	reported.TxBytes = []byte{0x05}
+	assert.NoError(t, CheckChunkInfo(expected, &reported, PublicInputHashV1))
+	assert.Error(t, CheckChunkInfo(expected, &reported, PublicInputHashUndefined))
+
+	reported.PostStateRoot = common.HexToHash("0x05")
+	assert.ErrorContains(t, CheckChunkInfo(expected, &reported, PublicInputHashV1), "post state root")
+}
+
+func TestTaskAssignmentSignSigner(t *testing.T) {
+	privkey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	assignment := &TaskAssignment{
+		UUID:            "c3d8ad2e-0c3c-4b48-9a3c-7c6b0bbd7d5e",
+		TaskID:          "testID",
+		TaskType:        ProofTypeChunk,
+		TaskDataHash:    crypto.Keccak256Hash([]byte(`{"block_hashes":["0x01"]}`)),
+		HardForkName:    "darwin",
+		TaskHeight:      100,
+		ProverPublicKey: "02dd8b6d0e1ba8b0e5a4da1e5c1ea4f1a20e9bd53e0c4e0e4e1f2a42a7b4ee2b5e",
+	}
+
+	// the prover checks the same hash.
+	hash, err := assignment.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, "fe186a786a09c7532e287853c78ea19a8b1699af8b0cbbbb4715f7ec8f6525f7", hex.EncodeToString(hash))
+
+	signature, err := assignment.Sign(privkey)
+	assert.NoError(t, err)
+	signer, err := assignment.Signer(signature)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(privkey.PublicKey), signer)
+
+	// a task forwarded to another prover recovers another signer.
+	assignment.ProverPublicKey = common.Bytes2Hex(crypto.CompressPubkey(&privkey.PublicKey))
+	signer, err = assignment.Signer(signature)
+	assert.NoError(t, err)
+	assert.NotEqual(t, crypto.PubkeyToAddress(privkey.PublicKey), signer)
+
+	_, err = assignment.Signer("0x1234")
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package message
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+)
+
+// PublicInputHashVersion is the layout of the public input hash (pi_hash) a chunk proof commits to.
+type PublicInputHashVersion uint8
+
+const (
+	// PublicInputHashUndefined is an unknown layout.
+	PublicInputHashUndefined PublicInputHashVersion = iota
+	// PublicInputHashV1 hashes the chain id, the prev and post state roots, the withdraw root and the data hash of the chunk.
+	PublicInputHashV1
+)
+
+// defaultPublicInputHashVersions are the layouts of the hard forks the circuits are released for.
+var defaultPublicInputHashVersions = map[string]PublicInputHashVersion{
+	"bernoulli": PublicInputHashV1,
+	"curie":     PublicInputHashV1,
+}
+
+// GetPublicInputHashVersion returns the pi_hash layout of the hard fork, the overrides are tried
+// before the defaults so a new hard fork can be supported by configuration. An unknown hard fork
+// returns an error, the callers skip the checks relying on the layout.
+func GetPublicInputHashVersion(hardForkName string, overrides map[string]PublicInputHashVersion) (PublicInputHashVersion, error) {
+	version, ok := overrides[hardForkName]
+	if !ok {
+		version, ok = defaultPublicInputHashVersions[hardForkName]
+	}
+	if !ok {
+		return PublicInputHashUndefined, fmt.Errorf("no public input hash version for hard fork: %s", hardForkName)
+	}
+	if version != PublicInputHashV1 {
+		return PublicInputHashUndefined, fmt.Errorf("unsupported public input hash version %d for hard fork: %s", version, hardForkName)
+	}
+	return version, nil
+}
+
+// PublicInputHash computes the pi_hash of the chunk in the layout of the version.
+func (ci *ChunkInfo) PublicInputHash(version PublicInputHashVersion) (common.Hash, error) {
+	data := binary.BigEndian.AppendUint64(nil, ci.ChainID)
+	data = append(data, ci.PrevStateRoot.Bytes()...)
+	data = append(data, ci.PostStateRoot.Bytes()...)
+	data = append(data, ci.WithdrawRoot.Bytes()...)
+	data = append(data, ci.DataHash.Bytes()...)
+
+	if version != PublicInputHashV1 {
+		return common.Hash{}, fmt.Errorf("unsupported public input hash version: %d", version)
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// CheckChunkInfo checks the ChunkInfo reported by the prover matches the one computed by the coordinator,
+// the transactions bytes are only known to the prover so they are not compared.
+func CheckChunkInfo(expected, reported *ChunkInfo, version PublicInputHashVersion) error {
+	var mismatches []string
+	if expected.ChainID != reported.ChainID {
+		mismatches = append(mismatches, fmt.Sprintf("chain id %d != %d", expected.ChainID, reported.ChainID))
+	}
+	if expected.PrevStateRoot != reported.PrevStateRoot {
+		mismatches = append(mismatches, fmt.Sprintf("prev state root %s != %s", expected.PrevStateRoot.Hex(), reported.PrevStateRoot.Hex()))
+	}
+	if expected.PostStateRoot != reported.PostStateRoot {
+		mismatches = append(mismatches, fmt.Sprintf("post state root %s != %s", expected.PostStateRoot.Hex(), reported.PostStateRoot.Hex()))
+	}
+	if expected.WithdrawRoot != reported.WithdrawRoot {
+		mismatches = append(mismatches, fmt.Sprintf("withdraw root %s != %s", expected.WithdrawRoot.Hex(), reported.WithdrawRoot.Hex()))
+	}
+	if expected.DataHash != reported.DataHash {
+		mismatches = append(mismatches, fmt.Sprintf("data hash %s != %s", expected.DataHash.Hex(), reported.DataHash.Hex()))
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("chunk info mismatch: %s", strings.Join(mismatches, ", "))
+	}
+
+	// the fields match, make sure the version is one the circuits commit to.
+	_, err := reported.PublicInputHash(version)
+	return err
+}
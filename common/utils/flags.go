@@ -12,6 +12,7 @@ var (
 		&LogFileFlag,
 		&LogJSONFormat,
 		&LogDebugFlag,
+		&LogVModuleFlag,
 		&MetricsEnabled,
 		&MetricsAddr,
 		&MetricsPort,
@@ -51,6 +52,11 @@ var (
 		Name:  "log.debug",
 		Usage: "Prepends log messages with call-site location (file and line number)",
 	}
+	// LogVModuleFlag overrides the verbosity per module
+	LogVModuleFlag = cli.StringFlag{
+		Name:  "log.vmodule",
+		Usage: "Per-module verbosity: comma-separated list of <pattern>=<level> (e.g. watcher/*=5,relayer=4)",
+	}
 	// MetricsEnabled enable metrics collection and reporting
 	MetricsEnabled = cli.BoolFlag{
 		Name:     "metrics",
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -11,6 +12,26 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
+// Correlation keys shared by the proposers, the coordinator and the relayers, so that the
+// logs of one chunk/batch (and of one prover request) can be followed across services.
+const (
+	// LogKeyTaskID is the chunk/batch hash.
+	LogKeyTaskID = "task_id"
+	// LogKeyTaskUUID is the uuid of the prover task, the TaskMsg UUID.
+	LogKeyTaskUUID = "task_uuid"
+	// LogKeyRequestID is the id of the coordinator api request.
+	LogKeyRequestID = "request_id"
+)
+
+// TaskLogger returns a logger carrying the correlation ids of a chunk/batch task,
+// the uuid is left out when it is empty.
+func TaskLogger(taskID, taskUUID string) log.Logger {
+	if taskUUID == "" {
+		return log.New(LogKeyTaskID, taskID)
+	}
+	return log.New(LogKeyTaskID, taskID, LogKeyTaskUUID, taskUUID)
+}
+
 // LogSetup is for setup logger
 func LogSetup(ctx *cli.Context) error {
 	var ostream log.Handler
@@ -30,13 +51,24 @@ func LogSetup(ctx *cli.Context) error {
 		if usecolor {
 			output = colorable.NewColorableStderr()
 		}
-		ostream = log.StreamHandler(output, log.TerminalFormat(usecolor))
+		// stderr keeps the terminal format unless json is asked for explicitly.
+		if ctx.IsSet(LogJSONFormat.Name) && ctx.Bool(LogJSONFormat.Name) {
+			ostream = log.StreamHandler(output, log.JSONFormat())
+		} else {
+			ostream = log.StreamHandler(output, log.TerminalFormat(usecolor))
+		}
 	}
 	// show the call file and line number
 	log.PrintOrigins(ctx.Bool(LogDebugFlag.Name))
 	glogger := log.NewGlogHandler(ostream)
 	// Set log level
 	glogger.Verbosity(log.Lvl(ctx.Int(VerbosityFlag.Name)))
+	// Per-module level overrides
+	if vmodule := ctx.String(LogVModuleFlag.Name); len(vmodule) > 0 {
+		if err := glogger.Vmodule(vmodule); err != nil {
+			return fmt.Errorf("invalid %s: %w", LogVModuleFlag.Name, err)
+		}
+	}
 	log.Root().SetHandler(glogger)
 	return nil
 }
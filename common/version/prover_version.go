@@ -39,3 +39,33 @@ func CheckScrollRepoVersion(proverVersion, minVersion string) bool {
 
 	return c.Check(v)
 }
+
+// CheckScrollRepoMaxVersion checks if the proverVersion is at most the maximum allowed version,
+// only the major, minor and patch numbers are compared.
+func CheckScrollRepoMaxVersion(proverVersion, maxVersion string) bool {
+	maxV, err := semver.NewVersion(maxVersion)
+	if err != nil {
+		log.Error("failed to parse max version", "maxVersion", maxVersion, "error", err)
+		return false
+	}
+
+	v, err := semver.NewVersion(proverVersion)
+	if err != nil {
+		log.Error("failed to parse version", "proverVersion", proverVersion, "error", err)
+		return false
+	}
+
+	core := semver.New(v.Major(), v.Minor(), v.Patch(), "", "")
+	maxCore := semver.New(maxV.Major(), maxV.Minor(), maxV.Patch(), "", "")
+	return !core.GreaterThan(maxCore)
+}
+
+// ScrollProverVersion returns the "scroll-prover" version, i.e. the circuit version, of the prover version,
+// it returns an empty string if the prover version is not in the format of "tag-commit-scroll_prover-halo2".
+func ScrollProverVersion(proverVersion string) string {
+	remote := strings.Split(proverVersion, "-")
+	if len(remote) != 4 {
+		return ""
+	}
+	return remote[2]
+}
@@ -57,3 +57,41 @@ func TestCheckScrollRepoVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckScrollRepoMaxVersion(t *testing.T) {
+	tests := []struct {
+		proverVersion string
+		maxVersion    string
+		want          bool
+	}{
+		{"v1.2.3-commit-111111-000000", "v1.2.3", true},
+		{"v1.2.3", "v1.2.3", true},
+		{"v1.2.2", "v1.2.3", true},
+		{"v1.2.4-commit-111111-000000", "v1.2.3", false},
+		{"v2.0.0", "v1.9.9", false},
+		{"incorrect-format", "v1.0.0", false},
+		{"v1.0.0", "incorrect-format", false},
+	}
+
+	for _, tt := range tests {
+		if got := CheckScrollRepoMaxVersion(tt.proverVersion, tt.maxVersion); got != tt.want {
+			t.Errorf("CheckScrollRepoMaxVersion(%q, %q) = %v, want %v", tt.proverVersion, tt.maxVersion, got, tt.want)
+		}
+	}
+}
+
+func TestScrollProverVersion(t *testing.T) {
+	tests := []struct {
+		proverVersion string
+		want          string
+	}{
+		{"v1.2.3-commit-111111-000000", "111111"},
+		{"incorrect-format", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ScrollProverVersion(tt.proverVersion); got != tt.want {
+			t.Errorf("ScrollProverVersion(%q) = %v, want %v", tt.proverVersion, got, tt.want)
+		}
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +16,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 
 	"scroll-tech/common/database"
@@ -23,6 +26,7 @@ import (
 
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/controller/api"
+	"scroll-tech/coordinator/internal/controller/rpc"
 	"scroll-tech/coordinator/internal/route"
 )
 
@@ -70,20 +74,48 @@ func action(ctx *cli.Context) error {
 	observability.Server(ctx, db)
 
 	apiSrv := apiServer(ctx, cfg, genesis.Config, db, registry)
+	// a draining coordinator is taken out of the load balancer, the provers reconnect to the other instances.
+	observability.AddReadinessCheck("drain", func(context.Context) error {
+		if api.Drain.Draining() {
+			return errors.New("draining the in-flight prover tasks")
+		}
+		return nil
+	})
+
+	var grpcSrv *grpc.Server
+	if ctx.Bool(grpcEnabledFlag.Name) {
+		grpcSrv = grpcServer(ctx, cfg)
+	}
 
 	log.Info(
 		"Start coordinator api successfully.",
 		"version", version.Version,
 	)
 
-	// Catch CTRL-C to ensure a graceful shutdown.
+	// Catch CTRL-C and SIGTERM to ensure a graceful shutdown.
 	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	// Wait until the interrupt signal is received from an OS signal.
 	<-interrupt
 	log.Info("start shutdown coordinator server ...")
 
+	drainTasks(cfg)
+
+	if grpcSrv != nil {
+		// the GetTask and Heartbeat streams stay open until the provers close them, they're closed after a while.
+		stopped := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			grpcSrv.Stop()
+		}
+	}
+
 	closeCtx, cancelExit := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancelExit()
 	if err = apiSrv.Shutdown(closeCtx); err != nil {
@@ -96,6 +128,26 @@ func action(ctx *cli.Context) error {
 	return nil
 }
 
+// drainTasks stops accepting new provers and tasks, and waits for the proofs of the tasks in flight
+// within the shutdown grace period.
+func drainTasks(cfg *config.Config) {
+	gracePeriod := time.Duration(cfg.ProverManager.ShutdownGracePeriodSec) * time.Second
+	if gracePeriod <= 0 {
+		return
+	}
+
+	api.Drain.Start()
+	log.Info("draining the in-flight prover tasks", "tasks", api.Drain.InFlight(), "grace period", gracePeriod)
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+	if inFlight := api.Drain.Wait(drainCtx); inFlight > 0 {
+		log.Warn("shutdown grace period is over, the unsubmitted tasks stay assigned until they time out", "tasks", inFlight)
+		return
+	}
+	log.Info("all the in-flight prover tasks are submitted")
+}
+
 func apiServer(ctx *cli.Context, cfg *config.Config, chainCfg *params.ChainConfig, db *gorm.DB, reg prometheus.Registerer) *http.Server {
 	router := gin.New()
 	api.InitController(cfg, chainCfg, db, reg)
@@ -115,6 +167,30 @@ func apiServer(ctx *cli.Context, cfg *config.Config, chainCfg *params.ChainConfi
 	return srv
 }
 
+// grpcServer must be called after apiServer, which initializes the shared controllers.
+func grpcServer(ctx *cli.Context, cfg *config.Config) *grpc.Server {
+	srv, err := rpc.NewServer(cfg, &rpc.TLSConfig{
+		CertFile:     ctx.String(grpcTLSCertFlag.Name),
+		KeyFile:      ctx.String(grpcTLSKeyFlag.Name),
+		ClientCAFile: ctx.String(grpcTLSClientCAFlag.Name),
+	})
+	if err != nil {
+		log.Crit("new coordinator grpc server failure", "error", err)
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", ctx.String(grpcPortFlag.Name)))
+	if err != nil {
+		log.Crit("listen coordinator grpc port failure", "error", err)
+	}
+
+	go func() {
+		if runServerErr := srv.Serve(lis); runServerErr != nil && !errors.Is(runServerErr, grpc.ErrServerStopped) {
+			log.Crit("run coordinator grpc server failure", "error", runServerErr)
+		}
+	}()
+	return srv
+}
+
 // Run coordinator.
 func Run() {
 	// RunApp the coordinator.
@@ -8,6 +8,12 @@ var (
 		&httpEnabledFlag,
 		&httpListenAddrFlag,
 		&httpPortFlag,
+		// grpc flags
+		&grpcEnabledFlag,
+		&grpcPortFlag,
+		&grpcTLSCertFlag,
+		&grpcTLSKeyFlag,
+		&grpcTLSClientCAFlag,
 	}
 	// httpEnabledFlag enable rpc server.
 	httpEnabledFlag = cli.BoolFlag{
@@ -27,4 +33,31 @@ var (
 		Usage: "HTTP-RPC server listening port",
 		Value: 8390,
 	}
+	// grpcEnabledFlag enable grpc server.
+	grpcEnabledFlag = cli.BoolFlag{
+		Name:  "grpc",
+		Usage: "Enable the gRPC server",
+		Value: false,
+	}
+	// grpcPortFlag set grpc.port.
+	grpcPortFlag = cli.IntFlag{
+		Name:  "grpc.port",
+		Usage: "gRPC server listening port",
+		Value: 8391,
+	}
+	// grpcTLSCertFlag set the grpc server certificate.
+	grpcTLSCertFlag = cli.StringFlag{
+		Name:  "grpc.tls.cert",
+		Usage: "gRPC server TLS certificate file",
+	}
+	// grpcTLSKeyFlag set the grpc server private key.
+	grpcTLSKeyFlag = cli.StringFlag{
+		Name:  "grpc.tls.key",
+		Usage: "gRPC server TLS private key file",
+	}
+	// grpcTLSClientCAFlag set the CA used to verify prover certificates.
+	grpcTLSClientCAFlag = cli.StringFlag{
+		Name:  "grpc.tls.clientca",
+		Usage: "CA file used to verify the gRPC client certificates",
+	}
 )
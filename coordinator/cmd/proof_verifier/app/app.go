@@ -0,0 +1,175 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/urfave/cli/v2"
+
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+	"scroll-tech/common/version"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/verifier"
+)
+
+const (
+	// instanceSize is the size of a serialized instance, a big-endian field element.
+	instanceSize = 32
+	// accumulatorInstanceNum is the number of accumulator limbs leading the instances of a batch proof,
+	// they are followed by the public input hash, one byte per instance.
+	accumulatorInstanceNum = 12
+)
+
+var app *cli.App
+
+// errInvalidProof is returned when the proof fails the verification, proof_verifier then exits with verifier.InvalidProofExitCode.
+var errInvalidProof = errors.New("invalid proof")
+
+func init() {
+	// Set up proof-verifier app info.
+	app = cli.NewApp()
+	app.Action = action
+	app.Name = "proof-verifier"
+	app.Usage = "Verify a Scroll chunk or batch proof locally"
+	app.Version = version.Version
+	app.Flags = append(app.Flags, &utils.VerbosityFlag, &utils.LogFileFlag, &utils.LogJSONFormat, &utils.LogDebugFlag, &utils.LogVModuleFlag)
+	app.Flags = append(app.Flags, verifierFlags...)
+	app.Before = func(ctx *cli.Context) error {
+		return utils.LogSetup(ctx)
+	}
+}
+
+func action(ctx *cli.Context) error {
+	var proofType message.ProofType
+	switch ctx.String(proofTypeFlag.Name) {
+	case "chunk":
+		proofType = message.ProofTypeChunk
+	case "batch":
+		proofType = message.ProofTypeBatch
+	default:
+		return fmt.Errorf("unsupported proof type: %s", ctx.String(proofTypeFlag.Name))
+	}
+
+	proofFile := ctx.String(proofFileFlag.Name)
+	buf, err := os.ReadFile(filepath.Clean(proofFile))
+	if err != nil {
+		return fmt.Errorf("failed to read proof file %s: %w", proofFile, err)
+	}
+
+	forkName := ctx.String(forkNameFlag.Name)
+	v, err := verifier.NewVerifier(&config.VerifierConfig{
+		ForkName:   forkName,
+		ParamsPath: ctx.String(paramsPathFlag.Name),
+		AssetsPath: ctx.String(assetsPathFlag.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verifier: %w", err)
+	}
+
+	var (
+		verified bool
+		vk       []byte
+	)
+	switch proofType {
+	case message.ProofTypeChunk:
+		var proof message.ChunkProof
+		if err = json.Unmarshal(buf, &proof); err != nil {
+			return fmt.Errorf("failed to unmarshal chunk proof: %w", err)
+		}
+		if err = printInstances(proofType, proof.Instances); err != nil {
+			return err
+		}
+		if proof.ChunkInfo != nil {
+			fmt.Printf("chunk info: prev state root %s, post state root %s, withdraw root %s, data hash %s\n",
+				proof.ChunkInfo.PrevStateRoot.Hex(), proof.ChunkInfo.PostStateRoot.Hex(), proof.ChunkInfo.WithdrawRoot.Hex(), proof.ChunkInfo.DataHash.Hex())
+		}
+		vk = proof.Vk
+		if verified, err = v.VerifyChunkProof(&proof, forkName); err != nil {
+			return fmt.Errorf("failed to verify chunk proof: %w", err)
+		}
+	case message.ProofTypeBatch:
+		var proof message.BatchProof
+		if err = json.Unmarshal(buf, &proof); err != nil {
+			return fmt.Errorf("failed to unmarshal batch proof: %w", err)
+		}
+		if err = printInstances(proofType, proof.Instances); err != nil {
+			return err
+		}
+		vk = proof.Vk
+		if verified, err = v.VerifyBatchProof(&proof, forkName); err != nil {
+			return fmt.Errorf("failed to verify batch proof: %w", err)
+		}
+	}
+
+	if vkErr := v.CheckVK(proofType, vk, forkName); vkErr != nil {
+		fmt.Printf("vk: %v\n", vkErr)
+	} else {
+		fmt.Println("vk: matches the assets")
+	}
+	if !verified {
+		return fmt.Errorf("%s proof %s: %w", proofType.String(), proofFile, errInvalidProof)
+	}
+	fmt.Printf("%s proof %s is valid\n", proofType.String(), proofFile)
+	return nil
+}
+
+// printInstances prints the instances of the proof, and the public input hash they hold for a batch proof.
+func printInstances(proofType message.ProofType, instances []byte) error {
+	words, err := decodeInstances(instances)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("instances (%d):\n", len(words))
+	for i, word := range words {
+		fmt.Printf("  %3d %s\n", i, word.Hex())
+	}
+	if proofType == message.ProofTypeBatch {
+		piHash, err := publicInputHash(words)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("public input hash: %s\n", piHash.Hex())
+	}
+	return nil
+}
+
+// decodeInstances splits the serialized instances into big-endian field elements.
+func decodeInstances(instances []byte) ([]common.Hash, error) {
+	if len(instances)%instanceSize != 0 {
+		return nil, fmt.Errorf("invalid instances length: %d", len(instances))
+	}
+	words := make([]common.Hash, len(instances)/instanceSize)
+	for i := range words {
+		words[i] = common.BytesToHash(instances[i*instanceSize : (i+1)*instanceSize])
+	}
+	return words, nil
+}
+
+// publicInputHash assembles the public input hash from the instances of a batch proof.
+func publicInputHash(words []common.Hash) (common.Hash, error) {
+	if len(words) != accumulatorInstanceNum+common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid batch proof instances number: %d", len(words))
+	}
+	var piHash common.Hash
+	for i := range piHash {
+		piHash[i] = words[accumulatorInstanceNum+i][common.HashLength-1]
+	}
+	return piHash, nil
+}
+
+// Run proof verifier cmd instance.
+func Run() {
+	if err := app.Run(os.Args); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, errInvalidProof) {
+			os.Exit(verifier.InvalidProofExitCode)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeInstances(t *testing.T) {
+	piHash := crypto.Keccak256Hash([]byte("pi hash"))
+	instances := make([]byte, (accumulatorInstanceNum+common.HashLength)*instanceSize)
+	instances[instanceSize-1] = 0x01
+	for i, b := range piHash {
+		instances[(accumulatorInstanceNum+i+1)*instanceSize-1] = b
+	}
+
+	words, err := decodeInstances(instances)
+	assert.NoError(t, err)
+	assert.Len(t, words, accumulatorInstanceNum+common.HashLength)
+	assert.Equal(t, common.BigToHash(common.Big1), words[0])
+
+	got, err := publicInputHash(words)
+	assert.NoError(t, err)
+	assert.Equal(t, piHash, got)
+
+	_, err = publicInputHash(words[1:])
+	assert.Error(t, err)
+
+	_, err = decodeInstances(instances[1:])
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package app
+
+import "github.com/urfave/cli/v2"
+
+var (
+	verifierFlags = []cli.Flag{
+		&proofTypeFlag,
+		&proofFileFlag,
+		&paramsPathFlag,
+		&assetsPathFlag,
+		&forkNameFlag,
+	}
+	// proofTypeFlag set the type of the proof to verify.
+	proofTypeFlag = cli.StringFlag{
+		Name:  "proof.type",
+		Usage: "Type of the proof to verify: chunk or batch",
+		Value: "batch",
+	}
+	// proofFileFlag set the proof file.
+	proofFileFlag = cli.StringFlag{
+		Name:     "proof",
+		Usage:    "ChunkProof or BatchProof JSON file",
+		Required: true,
+	}
+	// paramsPathFlag set the params dir.
+	paramsPathFlag = cli.StringFlag{
+		Name:  "params",
+		Usage: "Params dir of the verifier",
+		Value: "./params",
+	}
+	// assetsPathFlag set the assets dir holding the vks.
+	assetsPathFlag = cli.StringFlag{
+		Name:  "assets",
+		Usage: "Assets dir of the verifier, holding agg_vk.vkey and chunk_vk.vkey",
+		Value: "./assets",
+	}
+	// forkNameFlag set the hard fork the proof is verified for.
+	forkNameFlag = cli.StringFlag{
+		Name:  "fork",
+		Usage: "Hard fork name the proof is verified for",
+		Value: "bernoulli",
+	}
+)
@@ -0,0 +1,7 @@
+package main
+
+import "scroll-tech/coordinator/cmd/proof_verifier/app"
+
+func main() {
+	app.Run()
+}
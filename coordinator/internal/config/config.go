@@ -1,11 +1,28 @@
 package config
 
 import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/scroll-tech/go-ethereum/crypto"
 
 	"scroll-tech/common/database"
+	"scroll-tech/common/types/message"
+)
+
+const (
+	defaultLeaseDurationSec = 30
+
+	defaultProofUploadMaxPartSize = 4 * 1024 * 1024
+	defaultProofUploadMaxParts    = 64
+	defaultProofUploadExpirySec   = 3600
 )
 
 // ProverManager loads sequencer configuration items.
@@ -25,12 +42,180 @@ type ProverManager struct {
 	MaxVerifierWorkers int `json:"max_verifier_workers"`
 	// MinProverVersion is the minimum version of the prover that is required.
 	MinProverVersion string `json:"min_prover_version"`
+	// VersionPolicy rejects the incompatible provers at login, nil disables it.
+	VersionPolicy *VersionPolicy `json:"version_policy,omitempty"`
+	// Scheduler picks the proof type for provers that don't ask for a specific one.
+	Scheduler *SchedulerConfig `json:"scheduler,omitempty"`
+	// ProverScore biases the task assignment away from flaky provers, nil disables it.
+	ProverScore *ProverScoreConfig `json:"prover_score,omitempty"`
+	// ProofFailureRetry bounds the retries of the tasks the provers failed, per type of failure, nil retries them until
+	// session_attempts.
+	ProofFailureRetry *ProofFailureRetryConfig `json:"proof_failure_retry,omitempty"`
+	// ProvingTimeEstimation estimates the proving time of the chunk tasks from the proved chunks, to set their
+	// deadlines and keep the large chunks off the slow provers, nil disables it.
+	ProvingTimeEstimation *ProvingTimeEstimationConfig `json:"proving_time_estimation,omitempty"`
+	// RequireSignedProof rejects the proof submissions without a signature and nonce, it should be set
+	// once all the provers are upgraded.
+	RequireSignedProof bool `json:"require_signed_proof,omitempty"`
+	// Marketplace records a receipt signed by the coordinator for every accepted proof, so an external rewards
+	// system can pay the provers, nil disables it. The proof submissions must be signed when it's set.
+	Marketplace *Marketplace `json:"marketplace,omitempty"`
+	// TaskSigning signs the tasks assigned to the provers, so they check the tasks come from the coordinator before
+	// proving them, nil disables it.
+	TaskSigning *TaskSigning `json:"task_signing,omitempty"`
+	// CircuitAssets are the circuit asset releases the provers download and verify, empty disables the publishing.
+	CircuitAssets []*CircuitAssetsRelease `json:"circuit_assets,omitempty"`
+	// Prefetch assigns the provers asking for it their next task once their current task reports its final
+	// proving stage, at most one task ahead.
+	Prefetch bool `json:"prefetch,omitempty"`
+	// MaxConcurrentTasks caps the concurrency limits the provers advertise at login, a prover is assigned up to
+	// its limit of tasks of a proof type at once. 0 or 1 assigns the provers one task at a time.
+	MaxConcurrentTasks uint32 `json:"max_concurrent_tasks,omitempty"`
+	// ShutdownGracePeriodSec is how long a shutdown waits for the proofs of the tasks in flight,
+	// no new prover or task is accepted meanwhile. 0 shuts down right away.
+	ShutdownGracePeriodSec int `json:"shutdown_grace_period_sec,omitempty"`
+	// ProofUpload lets the provers upload their proofs in resumable parts before submitting them, nil disables it.
+	ProofUpload *ProofUploadConfig `json:"proof_upload,omitempty"`
+}
+
+// ProofUploadConfig loads the proof upload in parts configuration items.
+type ProofUploadConfig struct {
+	// MaxPartSize is the max size of a part in bytes, 4 MiB if not set.
+	MaxPartSize int `json:"max_part_size,omitempty"`
+	// MaxParts is the max number of parts of a proof, 64 if not set.
+	MaxParts int `json:"max_parts,omitempty"`
+	// ExpirySec is how long the parts of a proof not submitted are kept, 1 hour if not set.
+	ExpirySec int `json:"expiry_sec,omitempty"`
+}
+
+// Marketplace loads the work receipts configuration items.
+type Marketplace struct {
+	// ReceiptSigningKey is the hex private key the coordinator signs the work receipts with.
+	ReceiptSigningKey string `json:"receipt_signing_key"`
+}
+
+// SigningKey returns the private key of the receipt signing key.
+func (m *Marketplace) SigningKey() (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(m.ReceiptSigningKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid marketplace receipt signing key: %w", err)
+	}
+	return key, nil
+}
+
+// TaskSigning loads the task signing configuration items.
+type TaskSigning struct {
+	// SigningKey is the hex private key the coordinator signs the tasks with, the provers are configured with
+	// its address.
+	SigningKey string `json:"signing_key"`
+}
+
+// Key returns the private key of the task signing key.
+func (t *TaskSigning) Key() (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(t.SigningKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid task signing key: %w", err)
+	}
+	return key, nil
+}
+
+// CircuitAssetsRelease loads a release of the params and vk assets of a hard fork circuit.
+type CircuitAssetsRelease struct {
+	HardForkName string `json:"hard_fork_name"`
+	// CircuitVersion names the release, the provers keep its files in a directory of this name.
+	CircuitVersion string `json:"circuit_version"`
+	// UpgradeHeight, the provers use the release for the tasks starting from this L2 block, the release
+	// of the hard fork with the highest upgrade height below the task wins.
+	UpgradeHeight uint64 `json:"upgrade_height"`
+	// BaseURL is the https url the files are downloaded from.
+	BaseURL string              `json:"base_url"`
+	Files   []*CircuitAssetFile `json:"files"`
+}
+
+// CircuitAssetFile loads a file of a circuit asset release.
+type CircuitAssetFile struct {
+	// Path is relative to the base url and to the release directory, under "params/" or "assets/".
+	Path string `json:"path"`
+	// SHA256 is the hex encoded sha256 digest of the file.
+	SHA256 string `json:"sha256"`
+}
+
+// SchedulerConfig loads the weighted task scheduler configuration items.
+type SchedulerConfig struct {
+	// BatchWeight and ChunkWeight are the relative chances of trying a batch or a chunk task first.
+	BatchWeight int `json:"batch_weight"`
+	ChunkWeight int `json:"chunk_weight"`
+	// StarvationTimeoutSec, a proof type not assigned for this long is tried first regardless of its weight, 0 disables it.
+	StarvationTimeoutSec int `json:"starvation_timeout_sec"`
+	// BatchRequirement and ChunkRequirement, only the provers advertising the required hardware at login
+	// are assigned the tasks of the proof type, nil accepts any prover.
+	BatchRequirement *ResourceRequirement `json:"batch_requirement,omitempty"`
+	ChunkRequirement *ResourceRequirement `json:"chunk_requirement,omitempty"`
+}
+
+// ResourceRequirement loads the minimum hardware of the provers for a proof type, zero values are not checked.
+type ResourceRequirement struct {
+	// GPUModels are the accepted GPU models, empty accepts any.
+	GPUModels      []string `json:"gpu_models,omitempty"`
+	MinGPUMemoryMB uint64   `json:"min_gpu_memory_mb,omitempty"`
+	MinCPUCores    uint64   `json:"min_cpu_cores,omitempty"`
+	MinMemoryMB    uint64   `json:"min_memory_mb,omitempty"`
+}
+
+// VersionPolicy loads the prover version compatibility policy.
+type VersionPolicy struct {
+	// MaxProverVersion is the maximum version of the prover that is accepted, empty means no upper bound.
+	MaxProverVersion string `json:"max_prover_version,omitempty"`
+	// CircuitVersions are the accepted "scroll-prover" versions of the circuit assets, empty accepts any.
+	CircuitVersions []string `json:"circuit_versions,omitempty"`
+	// DeprecatedProverVersion, the provers below it are warned at login until the deprecation
+	// deadline (unix timestamp in seconds), and rejected after it.
+	DeprecatedProverVersion string `json:"deprecated_prover_version,omitempty"`
+	DeprecationDeadline     int64  `json:"deprecation_deadline,omitempty"`
+}
+
+// ProverScoreConfig loads the prover reputation configuration items.
+type ProverScoreConfig struct {
+	// MinSamples is the number of finished tasks a prover needs before its score is taken into account.
+	MinSamples uint64 `json:"min_samples"`
+	// MinSuccessRate, a prover scoring below it only gets a task with probability success_rate / min_success_rate.
+	MinSuccessRate float64 `json:"min_success_rate"`
+}
+
+// ProofFailureRetryConfig loads the proof failure retry configuration items. A task failed by a prover is reassigned to
+// another prover, or to a new version of the prover, and quarantined once it fails too many times, until the operator
+// resets it with the task_reset admin api.
+type ProofFailureRetryConfig struct {
+	// MaxPanicFailures is the number of prover panics after which the task is quarantined. A panic is usually specific
+	// to the prover, e.g. out of memory, so it should be above max_no_panic_failures. 0 never quarantines on panics.
+	MaxPanicFailures uint8 `json:"max_panic_failures"`
+	// MaxNoPanicFailures is the number of failures without a prover panic, which are deterministic and fail again on
+	// any prover, after which the task is quarantined. 0 never quarantines on them.
+	MaxNoPanicFailures uint8 `json:"max_no_panic_failures"`
+}
+
+// ProvingTimeEstimationConfig loads the chunk proving time estimation configuration items.
+type ProvingTimeEstimationConfig struct {
+	// SampleSize is the number of the latest proved chunks the estimation is fitted on.
+	SampleSize int `json:"sample_size"`
+	// MinSamples is the number of proved chunks needed before the chunk tasks are estimated.
+	MinSamples int `json:"min_samples"`
+	// DeadlineFactor, the deadline of a chunk task is its proving time expected for the prover times the factor,
+	// but never earlier than chunk_collection_time_sec. 0 keeps the collection time.
+	DeadlineFactor float64 `json:"deadline_factor"`
+	// LargeChunkSec and SlowProverRatio, the chunks estimated above large_chunk_sec are not assigned to the provers
+	// whose latest chunk proofs took more than slow_prover_ratio times their estimates. 0 disables it.
+	LargeChunkSec   int     `json:"large_chunk_sec"`
+	SlowProverRatio float64 `json:"slow_prover_ratio"`
 }
 
 // L2 loads l2geth configuration items.
 type L2 struct {
 	// l2geth chain_id.
 	ChainID uint64 `json:"chain_id"`
+	// PublicInputHashVersions maps the hard forks to the pi_hash layout of their chunk proofs,
+	// on top of the built-in ones, e.g. {"darwin": 1}.
+	PublicInputHashVersions map[string]message.PublicInputHashVersion `json:"public_input_hash_versions,omitempty"`
 }
 
 // Auth provides the auth coordinator
@@ -38,6 +223,53 @@ type Auth struct {
 	Secret                     string `json:"secret"`
 	ChallengeExpireDurationSec int    `json:"challenge_expire_duration_sec"`
 	LoginExpireDurationSec     int    `json:"login_expire_duration_sec"`
+	// LoginMaxRefreshDurationSec is how long after the login a token can be refreshed, 0 disables the refresh.
+	LoginMaxRefreshDurationSec int `json:"login_max_refresh_duration_sec,omitempty"`
+	// PreviousSecrets are the rotated out secrets, the tokens signed with them are still accepted
+	// until they expire, while new tokens are signed with Secret.
+	PreviousSecrets []string `json:"previous_secrets,omitempty"`
+	// RequireProverAllowList only lets the public keys of the prover allow list, managed by the admin api,
+	// log in and get tasks.
+	RequireProverAllowList bool `json:"require_prover_allow_list,omitempty"`
+}
+
+// Admin provides the admin api of the coordinator
+type Admin struct {
+	// Secret is the bearer token required by the admin api.
+	Secret string `json:"secret"`
+	// MaxRowConsumptionPerChunk is the row capacity of the sub-circuits the row usages are reported against,
+	// 1048319 if not set.
+	MaxRowConsumptionPerChunk uint64 `json:"max_row_consumption_per_chunk,omitempty"`
+	// MaxRowConsumptionPerSubCircuit overrides the row capacity of the named sub-circuits.
+	MaxRowConsumptionPerSubCircuit map[string]uint64 `json:"max_row_consumption_per_sub_circuit,omitempty"`
+}
+
+// HA lets several coordinator replicas share the database, the prover task assignments and
+// the cron jobs are guarded by leases in the database so they are not run twice.
+type HA struct {
+	// InstanceID identifies the replica as a lease owner, a random id is generated if empty.
+	InstanceID string `json:"instance_id"`
+	// LeaseDurationSec is how long a lease stays valid if its owner dies without releasing it.
+	LeaseDurationSec int `json:"lease_duration_sec"`
+}
+
+// RateLimitRule loads the token bucket of a prover request, a prover can send burst requests at once
+// and rate_per_sec requests per second on average, nil or a zero rate disables the limit.
+type RateLimitRule struct {
+	RatePerSec float64 `json:"rate_per_sec"`
+	Burst      int     `json:"burst"`
+}
+
+// RateLimit loads the per prover public key rate limits.
+type RateLimit struct {
+	Login       *RateLimitRule `json:"login,omitempty"`
+	GetTask     *RateLimitRule `json:"get_task,omitempty"`
+	SubmitProof *RateLimitRule `json:"submit_proof,omitempty"`
+	// BanThreshold, a prover rate limited this many times within ban_window_sec is banned
+	// for ban_duration_sec, 0 disables the bans.
+	BanThreshold   int `json:"ban_threshold,omitempty"`
+	BanWindowSec   int `json:"ban_window_sec,omitempty"`
+	BanDurationSec int `json:"ban_duration_sec,omitempty"`
 }
 
 // Config load configuration items.
@@ -46,6 +278,12 @@ type Config struct {
 	DB            *database.Config `json:"db"`
 	L2            *L2              `json:"l2"`
 	Auth          *Auth            `json:"auth"`
+	// Admin enables the admin api when set.
+	Admin *Admin `json:"admin,omitempty"`
+	// HA enables running several replicas of the coordinator when set.
+	HA *HA `json:"ha,omitempty"`
+	// RateLimit enables the per prover rate limits when set.
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
 }
 
 // VerifierConfig load zk verifier config.
@@ -54,6 +292,35 @@ type VerifierConfig struct {
 	MockMode   bool   `json:"mock_mode"`
 	ParamsPath string `json:"params_path"`
 	AssetsPath string `json:"assets_path"`
+	// VerifyChunkProof runs the chunk verifier on submitted chunk proofs, otherwise they are only vk-checked.
+	VerifyChunkProof bool `json:"verify_chunk_proof,omitempty"`
+	// Backends verify the proofs of the listed hard forks with another circuit version, e.g. the previous one
+	// during a hard fork upgrade, the other hard forks are verified by the built-in verifier.
+	Backends []*VerifierBackendConfig `json:"backends,omitempty"`
+}
+
+const (
+	// VerifierBackendExec runs the proof_verifier binary built against the circuit version, out of process
+	// since the rust verifier can only load one circuit version per process.
+	VerifierBackendExec = "exec"
+	// VerifierBackendMock accepts any vk and any proof but the invalid test proof.
+	VerifierBackendMock = "mock"
+)
+
+// VerifierBackendConfig is the verifier of the hard forks proved with another circuit version.
+type VerifierBackendConfig struct {
+	// ForkNames are the hard forks whose proofs are verified by the backend.
+	ForkNames []string `json:"fork_names"`
+	// Type is the backend type, exec or mock.
+	Type string `json:"type"`
+	// Binary is the path of the proof_verifier binary of an exec backend.
+	Binary string `json:"binary,omitempty"`
+	// ParamsPath is the params dir of the circuit version.
+	ParamsPath string `json:"params_path,omitempty"`
+	// AssetsPath is the assets dir of the circuit version, the vks of the hard forks are read from it.
+	AssetsPath string `json:"assets_path,omitempty"`
+	// TimeoutSec bounds a verification of an exec backend, 0 defaults to 120.
+	TimeoutSec int `json:"timeout_sec,omitempty"`
 }
 
 // NewConfig returns a new instance of Config.
@@ -69,5 +336,62 @@ func NewConfig(file string) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.ProverManager != nil {
+		if err = validateCircuitAssets(cfg.ProverManager.CircuitAssets); err != nil {
+			return nil, err
+		}
+		if cfg.ProverManager.Marketplace != nil {
+			if _, err = cfg.ProverManager.Marketplace.SigningKey(); err != nil {
+				return nil, err
+			}
+		}
+		if cfg.ProverManager.TaskSigning != nil {
+			if _, err = cfg.ProverManager.TaskSigning.Key(); err != nil {
+				return nil, err
+			}
+		}
+		if upload := cfg.ProverManager.ProofUpload; upload != nil {
+			if upload.MaxPartSize <= 0 {
+				upload.MaxPartSize = defaultProofUploadMaxPartSize
+			}
+			if upload.MaxParts <= 0 {
+				upload.MaxParts = defaultProofUploadMaxParts
+			}
+			if upload.ExpirySec <= 0 {
+				upload.ExpirySec = defaultProofUploadExpirySec
+			}
+		}
+	}
+
+	if cfg.HA != nil {
+		if cfg.HA.InstanceID == "" {
+			hostname, _ := os.Hostname()
+			cfg.HA.InstanceID = fmt.Sprintf("%s-%s", hostname, uuid.NewString())
+		}
+		if cfg.HA.LeaseDurationSec <= 0 {
+			cfg.HA.LeaseDurationSec = defaultLeaseDurationSec
+		}
+	}
+
 	return cfg, nil
 }
+
+func validateCircuitAssets(releases []*CircuitAssetsRelease) error {
+	for _, release := range releases {
+		if release.HardForkName == "" || release.CircuitVersion == "" || strings.ContainsAny(release.CircuitVersion, `/\`) {
+			return fmt.Errorf("invalid circuit assets release, hard fork name: %q, circuit version: %q", release.HardForkName, release.CircuitVersion)
+		}
+		if !strings.HasPrefix(release.BaseURL, "https://") {
+			return fmt.Errorf("circuit assets %s base url must be https, got %q", release.CircuitVersion, release.BaseURL)
+		}
+		for _, file := range release.Files {
+			if (!strings.HasPrefix(file.Path, "params/") && !strings.HasPrefix(file.Path, "assets/")) || strings.Contains(file.Path, "..") {
+				return fmt.Errorf("circuit assets %s file path must be under params/ or assets/, got %q", release.CircuitVersion, file.Path)
+			}
+			if digest, err := hex.DecodeString(file.SHA256); err != nil || len(digest) != sha256.Size {
+				return fmt.Errorf("circuit assets %s file %s has an invalid sha256 %q", release.CircuitVersion, file.Path, file.SHA256)
+			}
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -89,4 +90,90 @@ func TestConfig(t *testing.T) {
 		_, err = NewConfig(tmpFile.Name())
 		assert.Error(t, err)
 	})
+
+	t.Run("Circuit Assets", func(t *testing.T) {
+		digest := strings.Repeat("ab", 32)
+		for name, tc := range map[string]struct {
+			release string
+			valid   bool
+		}{
+			"valid":          {`{"hard_fork_name": "curie", "circuit_version": "v0.11.4", "upgrade_height": 100, "base_url": "https://assets.example.com/v0.11.4", "files": [{"path": "assets/vk_chunk.vkey", "sha256": "` + digest + `"}]}`, true},
+			"http base url":  {`{"hard_fork_name": "curie", "circuit_version": "v0.11.4", "base_url": "http://assets.example.com", "files": []}`, false},
+			"path traversal": {`{"hard_fork_name": "curie", "circuit_version": "v0.11.4", "base_url": "https://assets.example.com", "files": [{"path": "assets/../../keystore.json", "sha256": "` + digest + `"}]}`, false},
+			"short digest":   {`{"hard_fork_name": "curie", "circuit_version": "v0.11.4", "base_url": "https://assets.example.com", "files": [{"path": "params/params20", "sha256": "abcd"}]}`, false},
+			"no version":     {`{"hard_fork_name": "curie", "base_url": "https://assets.example.com", "files": []}`, false},
+		} {
+			cfg := strings.Replace(configTemplate, `"min_prover_version": "v1.0.0"`, `"min_prover_version": "v1.0.0", "circuit_assets": [`+tc.release+`]`, 1)
+			tmpFile, err := os.CreateTemp("", "circuit_assets_config.json")
+			assert.NoError(t, err)
+			_, err = tmpFile.WriteString(cfg)
+			assert.NoError(t, err)
+
+			_, err = NewConfig(tmpFile.Name())
+			assert.Equal(t, tc.valid, err == nil, name)
+			assert.NoError(t, tmpFile.Close())
+			assert.NoError(t, os.Remove(tmpFile.Name()))
+		}
+	})
+
+	t.Run("Marketplace", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			key   string
+			valid bool
+		}{
+			"valid":       {"8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba", true},
+			"0x prefixed": {"0x8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba", true},
+			"invalid":     {"8b3a", false},
+		} {
+			cfg := strings.Replace(configTemplate, `"min_prover_version": "v1.0.0"`, `"min_prover_version": "v1.0.0", "marketplace": {"receipt_signing_key": "`+tc.key+`"}`, 1)
+			tmpFile, err := os.CreateTemp("", "marketplace_config.json")
+			assert.NoError(t, err)
+			_, err = tmpFile.WriteString(cfg)
+			assert.NoError(t, err)
+
+			_, err = NewConfig(tmpFile.Name())
+			assert.Equal(t, tc.valid, err == nil, name)
+			assert.NoError(t, tmpFile.Close())
+			assert.NoError(t, os.Remove(tmpFile.Name()))
+		}
+	})
+
+	t.Run("Task Signing", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			key   string
+			valid bool
+		}{
+			"valid":   {"0x8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba", true},
+			"invalid": {"0x8b3a", false},
+			"empty":   {"", false},
+		} {
+			cfg := strings.Replace(configTemplate, `"min_prover_version": "v1.0.0"`, `"min_prover_version": "v1.0.0", "task_signing": {"signing_key": "`+tc.key+`"}`, 1)
+			tmpFile, err := os.CreateTemp("", "task_signing_config.json")
+			assert.NoError(t, err)
+			_, err = tmpFile.WriteString(cfg)
+			assert.NoError(t, err)
+
+			_, err = NewConfig(tmpFile.Name())
+			assert.Equal(t, tc.valid, err == nil, name)
+			assert.NoError(t, tmpFile.Close())
+			assert.NoError(t, os.Remove(tmpFile.Name()))
+		}
+	})
+
+	t.Run("Proof Upload", func(t *testing.T) {
+		cfg := strings.Replace(configTemplate, `"min_prover_version": "v1.0.0"`, `"min_prover_version": "v1.0.0", "proof_upload": {"max_parts": 16}`, 1)
+		tmpFile, err := os.CreateTemp("", "proof_upload_config.json")
+		assert.NoError(t, err)
+		defer func() {
+			assert.NoError(t, tmpFile.Close())
+			assert.NoError(t, os.Remove(tmpFile.Name()))
+		}()
+		_, err = tmpFile.WriteString(cfg)
+		assert.NoError(t, err)
+
+		// the limits not set get their defaults.
+		config, err := NewConfig(tmpFile.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, &ProofUploadConfig{MaxPartSize: defaultProofUploadMaxPartSize, MaxParts: 16, ExpirySec: defaultProofUploadExpirySec}, config.ProverManager.ProofUpload)
+	})
 }
@@ -0,0 +1,951 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
+	"scroll-tech/coordinator/internal/orm"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+const (
+	// maxAdminPageSize is the upper bound of the rows returned by one admin list request.
+	maxAdminPageSize = 1000
+	// defaultMaxRowConsumptionPerChunk is the row capacity of the sub-circuits of the chunk circuit.
+	defaultMaxRowConsumptionPerChunk = 1048319
+	// adminActionTaskReset is the audit log action of a task reset.
+	adminActionTaskReset = "task_reset"
+	// adminActionTaskPriority is the audit log action of a task priority change.
+	adminActionTaskPriority = "task_priority"
+)
+
+// AdminController the admin api controller
+type AdminController struct {
+	db                 *gorm.DB
+	chunkOrm           *orm.Chunk
+	batchOrm           *orm.Batch
+	proverScoreOrm     *orm.ProverScore
+	proverTaskOrm      *orm.ProverTask
+	chunkRowUsageOrm   *orm.ChunkRowUsage
+	workReceiptOrm     *orm.WorkReceipt
+	proverAllowListOrm *orm.ProverAllowList
+	proverBlockListOrm *orm.ProverBlockList
+	proverSessionOrm   *orm.ProverSession
+	adminAuditLogOrm   *orm.AdminAuditLog
+	rateLimiter        *ratelimit.Limiter
+
+	maxRowConsumptionPerChunk      uint64
+	maxRowConsumptionPerSubCircuit map[string]uint64
+}
+
+// NewAdminController create an admin controller, cfg and rateLimiter may be nil
+func NewAdminController(cfg *config.Admin, db *gorm.DB, rateLimiter *ratelimit.Limiter) *AdminController {
+	a := &AdminController{
+		db:                        db,
+		chunkOrm:                  orm.NewChunk(db),
+		batchOrm:                  orm.NewBatch(db),
+		proverScoreOrm:            orm.NewProverScore(db),
+		proverTaskOrm:             orm.NewProverTask(db),
+		chunkRowUsageOrm:          orm.NewChunkRowUsage(db),
+		workReceiptOrm:            orm.NewWorkReceipt(db),
+		proverAllowListOrm:        orm.NewProverAllowList(db),
+		proverBlockListOrm:        orm.NewProverBlockList(db),
+		proverSessionOrm:          orm.NewProverSession(db),
+		adminAuditLogOrm:          orm.NewAdminAuditLog(db),
+		rateLimiter:               rateLimiter,
+		maxRowConsumptionPerChunk: defaultMaxRowConsumptionPerChunk,
+	}
+	if cfg != nil {
+		if cfg.MaxRowConsumptionPerChunk != 0 {
+			a.maxRowConsumptionPerChunk = cfg.MaxRowConsumptionPerChunk
+		}
+		a.maxRowConsumptionPerSubCircuit = cfg.MaxRowConsumptionPerSubCircuit
+	}
+	return a
+}
+
+// GetProverScores returns the scores of the provers, or of the single prover if public_key is given
+func (a *AdminController) GetProverScores(ctx *gin.Context) {
+	var psp coordinatorType.ProverScoresParameter
+	if err := ctx.ShouldBind(&psp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if psp.Offset < 0 || psp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, offset and limit must not be negative"))
+		return
+	}
+	if psp.Limit == 0 || psp.Limit > maxAdminPageSize {
+		psp.Limit = maxAdminPageSize
+	}
+
+	var proverScores []orm.ProverScore
+	if psp.PublicKey != "" {
+		proverScore, err := a.proverScoreOrm.GetProverScore(ctx.Copy(), psp.PublicKey)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+			return
+		}
+		if proverScore != nil {
+			proverScores = append(proverScores, *proverScore)
+		}
+	} else {
+		var err error
+		proverScores, err = a.proverScoreOrm.GetProverScores(ctx.Copy(), psp.Offset, psp.Limit)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+			return
+		}
+	}
+
+	schemas := make([]coordinatorType.ProverScoreSchema, 0, len(proverScores))
+	for i := range proverScores {
+		proverScore := &proverScores[i]
+		schemas = append(schemas, coordinatorType.ProverScoreSchema{
+			PublicKey:            proverScore.PublicKey,
+			ProverName:           proverScore.ProverName,
+			SuccessCount:         proverScore.SuccessCount,
+			PanicFailureCount:    proverScore.PanicFailureCount,
+			NoPanicFailureCount:  proverScore.NoPanicFailureCount,
+			VerifiedFailureCount: proverScore.VerifiedFailureCount,
+			TimeoutCount:         proverScore.TimeoutCount,
+			SuccessRate:          proverScore.SuccessRate(),
+			AvgProvingTimeSec:    proverScore.AvgProvingTimeSec(),
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// GetProverTasks returns the assigned prover tasks with the progress reported by their provers
+func (a *AdminController) GetProverTasks(ctx *gin.Context) {
+	var ptp coordinatorType.ProverTasksParameter
+	if err := ctx.ShouldBind(&ptp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if ptp.Offset < 0 || ptp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, offset and limit must not be negative"))
+		return
+	}
+	if ptp.Limit == 0 || ptp.Limit > maxAdminPageSize {
+		ptp.Limit = maxAdminPageSize
+	}
+
+	fields := map[string]interface{}{
+		"proving_status = ?": int(types.ProverAssigned),
+	}
+	if ptp.PublicKey != "" {
+		fields["prover_public_key = ?"] = ptp.PublicKey
+	}
+	proverTasks, err := a.proverTaskOrm.GetProverTasks(ctx.Copy(), fields, []string{"assigned_at ASC"}, ptp.Offset, ptp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schemas := make([]coordinatorType.ProverTaskProgressSchema, 0, len(proverTasks))
+	for i := range proverTasks {
+		proverTask := &proverTasks[i]
+		schema := coordinatorType.ProverTaskProgressSchema{
+			UUID:            proverTask.UUID.String(),
+			TaskID:          proverTask.TaskID,
+			TaskType:        message.ProofType(proverTask.TaskType).String(),
+			ProverPublicKey: proverTask.ProverPublicKey,
+			ProverName:      proverTask.ProverName,
+			AssignedAt:      proverTask.AssignedAt.Unix(),
+			ProgressStage:   message.ProvingStage(proverTask.ProgressStage).String(),
+			ProgressPercent: proverTask.ProgressPercent,
+		}
+		if proverTask.ProgressReportedAt != nil {
+			schema.ProgressReportedAt = proverTask.ProgressReportedAt.Unix()
+		}
+		schemas = append(schemas, schema)
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// GetProverTaskHistory returns the tasks assigned to a prover, the latest first
+func (a *AdminController) GetProverTaskHistory(ctx *gin.Context) {
+	var pthp coordinatorType.ProverTaskHistoryParameter
+	if err := ctx.ShouldBind(&pthp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	pthp.PublicKey = strings.TrimSpace(pthp.PublicKey)
+	if pthp.PublicKey == "" {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, public_key must not be empty"))
+		return
+	}
+	if pthp.Offset < 0 || pthp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, offset and limit must not be negative"))
+		return
+	}
+	if pthp.Limit == 0 || pthp.Limit > maxAdminPageSize {
+		pthp.Limit = maxAdminPageSize
+	}
+
+	fields := map[string]interface{}{
+		"prover_public_key = ?": pthp.PublicKey,
+	}
+	proverTasks, err := a.proverTaskOrm.GetProverTasks(ctx.Copy(), fields, []string{"assigned_at DESC"}, pthp.Offset, pthp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schemas := make([]coordinatorType.ProverTaskHistorySchema, 0, len(proverTasks))
+	for i := range proverTasks {
+		proverTask := &proverTasks[i]
+		schema := coordinatorType.ProverTaskHistorySchema{
+			UUID:          proverTask.UUID.String(),
+			TaskID:        proverTask.TaskID,
+			TaskType:      message.ProofType(proverTask.TaskType).String(),
+			ProverName:    proverTask.ProverName,
+			ProverVersion: proverTask.ProverVersion,
+			ProvingStatus: types.ProverProveStatus(proverTask.ProvingStatus).String(),
+			AssignedAt:    proverTask.AssignedAt.Unix(),
+			UpdatedAt:     proverTask.UpdatedAt.Unix(),
+		}
+		if failureType := types.ProverTaskFailureType(proverTask.FailureType); failureType != types.ProverTaskFailureTypeUndefined {
+			schema.FailureType = failureType.String()
+		}
+		schemas = append(schemas, schema)
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// taskStatsOrm the queries of the task statistics, implemented by the chunk and the batch orm
+type taskStatsOrm interface {
+	CountByProvingStatus(ctx context.Context) (map[types.ProvingStatus]int64, error)
+	GetOldestUnassignedCreatedAt(ctx context.Context) (*time.Time, error)
+	CountVerifiedSince(ctx context.Context, since time.Time) (int64, error)
+}
+
+// GetTaskStats returns the proving statistics of the chunk and the batch tasks
+func (a *AdminController) GetTaskStats(ctx *gin.Context) {
+	now := utils.NowUTC()
+	chunkStats, err := taskStats(ctx.Copy(), message.ProofTypeChunk, a.chunkOrm, now)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	batchStats, err := taskStats(ctx.Copy(), message.ProofTypeBatch, a.batchOrm, now)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, []*coordinatorType.TaskStatsSchema{chunkStats, batchStats})
+}
+
+func taskStats(ctx context.Context, taskType message.ProofType, statsOrm taskStatsOrm, now time.Time) (*coordinatorType.TaskStatsSchema, error) {
+	counts, err := statsOrm.CountByProvingStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	oldestUnassigned, err := statsOrm.GetOldestUnassignedCreatedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proofsLastHour, err := statsOrm.CountVerifiedSince(ctx, now.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &coordinatorType.TaskStatsSchema{
+		TaskType:       taskType.String(),
+		StatusCounts:   make(map[string]int64, len(counts)),
+		Backlog:        counts[types.ProvingTaskUnassigned] + counts[types.ProvingTaskAssigned],
+		ProofsLastHour: proofsLastHour,
+	}
+	for status, count := range counts {
+		stats.StatusCounts[status.String()] = count
+	}
+	if oldestUnassigned != nil && now.After(*oldestUnassigned) {
+		stats.OldestUnassignedAgeSec = uint64(now.Sub(*oldestUnassigned).Seconds())
+	}
+	return stats, nil
+}
+
+// GetProverBans returns the provers temporarily banned by the rate limiter
+func (a *AdminController) GetProverBans(ctx *gin.Context) {
+	bans := a.rateLimiter.Bans()
+	schemas := make([]coordinatorType.ProverBanSchema, 0, len(bans))
+	for _, ban := range bans {
+		schemas = append(schemas, coordinatorType.ProverBanSchema{
+			PublicKey: ban.PublicKey,
+			Reason:    ban.Reason,
+			BannedAt:  ban.BannedAt.Unix(),
+			ExpiresAt: ban.ExpiresAt.Unix(),
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// BanProver temporarily bans a prover for duration_sec
+func (a *AdminController) BanProver(ctx *gin.Context) {
+	var pbp coordinatorType.ProverBansParameter
+	if err := ctx.ShouldBind(&pbp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if pbp.DurationSec <= 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, duration_sec must be positive"))
+		return
+	}
+	if pbp.Reason == "" {
+		pbp.Reason = "banned by the admin"
+	}
+
+	ban := a.rateLimiter.Ban(pbp.PublicKey, pbp.Reason, time.Duration(pbp.DurationSec)*time.Second)
+	types.RenderSuccess(ctx, coordinatorType.ProverBanSchema{
+		PublicKey: ban.PublicKey,
+		Reason:    ban.Reason,
+		BannedAt:  ban.BannedAt.Unix(),
+		ExpiresAt: ban.ExpiresAt.Unix(),
+	})
+}
+
+// UnbanProver lifts the temporary ban of a prover
+func (a *AdminController) UnbanProver(ctx *gin.Context) {
+	var pbp coordinatorType.ProverBansParameter
+	if err := ctx.ShouldBind(&pbp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if !a.rateLimiter.Unban(pbp.PublicKey) {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, fmt.Errorf("prover %s is not banned", pbp.PublicKey))
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// GetProverAllowList returns the provers allowed to log in when the allow list is required, the latest first
+func (a *AdminController) GetProverAllowList(ctx *gin.Context) {
+	var plp coordinatorType.ProverListParameter
+	if err := ctx.ShouldBind(&plp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if plp.Offset < 0 || plp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, offset and limit must not be negative"))
+		return
+	}
+	if plp.Limit == 0 || plp.Limit > maxAdminPageSize {
+		plp.Limit = maxAdminPageSize
+	}
+
+	provers, err := a.proverAllowListOrm.GetAllowedProvers(ctx.Copy(), plp.Offset, plp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schemas := make([]coordinatorType.ProverAllowListSchema, 0, len(provers))
+	for i := range provers {
+		schemas = append(schemas, coordinatorType.ProverAllowListSchema{
+			PublicKey:  provers[i].PublicKey,
+			ProverName: provers[i].ProverName,
+			AllowedAt:  provers[i].CreatedAt.Unix(),
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// AllowProver adds a prover public key to the allow list
+func (a *AdminController) AllowProver(ctx *gin.Context) {
+	var palp coordinatorType.ProverAllowListParameter
+	if err := ctx.ShouldBind(&palp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+
+	if err := a.proverAllowListOrm.InsertProverPublicKey(ctx.Copy(), palp.ProverName, palp.PublicKey); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// DisallowProver removes a prover public key from the allow list and ends its session
+func (a *AdminController) DisallowProver(ctx *gin.Context) {
+	var palp coordinatorType.ProverAllowListParameter
+	if err := ctx.ShouldBind(&palp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+
+	deleted, err := a.proverAllowListOrm.DeleteProverPublicKey(ctx.Copy(), palp.PublicKey)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	if !deleted {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, fmt.Errorf("prover %s is not in the allow list", palp.PublicKey))
+		return
+	}
+	if err := a.proverSessionOrm.DeleteProverSession(ctx.Copy(), palp.PublicKey); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// GetProverBlockList returns the blocked provers, the latest first
+func (a *AdminController) GetProverBlockList(ctx *gin.Context) {
+	var plp coordinatorType.ProverListParameter
+	if err := ctx.ShouldBind(&plp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if plp.Offset < 0 || plp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, offset and limit must not be negative"))
+		return
+	}
+	if plp.Limit == 0 || plp.Limit > maxAdminPageSize {
+		plp.Limit = maxAdminPageSize
+	}
+
+	provers, err := a.proverBlockListOrm.GetBlockedProvers(ctx.Copy(), plp.Offset, plp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schemas := make([]coordinatorType.ProverBlockListSchema, 0, len(provers))
+	for i := range provers {
+		schema := coordinatorType.ProverBlockListSchema{
+			PublicKey:  provers[i].PublicKey,
+			ProverName: provers[i].ProverName,
+			Reason:     provers[i].Reason,
+			BlockedAt:  provers[i].UpdatedAt.Unix(),
+		}
+		if provers[i].ExpiresAt != nil {
+			schema.ExpiresAt = provers[i].ExpiresAt.Unix()
+		}
+		schemas = append(schemas, schema)
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// BlockProver blocks a prover public key from getting tasks for duration_sec, or until it's unblocked, and
+// ends its session. Unlike the bans of the rate limiter, the block list is shared by the coordinator replicas.
+func (a *AdminController) BlockProver(ctx *gin.Context) {
+	var pblp coordinatorType.ProverBlockListParameter
+	if err := ctx.ShouldBind(&pblp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if pblp.DurationSec < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, duration_sec must not be negative"))
+		return
+	}
+	if pblp.Reason == "" {
+		pblp.Reason = "blocked by the admin"
+	}
+
+	var expiresAt *time.Time
+	if pblp.DurationSec > 0 {
+		t := utils.NowUTC().Add(time.Duration(pblp.DurationSec) * time.Second)
+		expiresAt = &t
+	}
+	if err := a.proverBlockListOrm.BlockProverPublicKey(ctx.Copy(), pblp.ProverName, pblp.PublicKey, pblp.Reason, expiresAt); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	if err := a.proverSessionOrm.DeleteProverSession(ctx.Copy(), pblp.PublicKey); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// UnblockProver removes a prover public key from the block list
+func (a *AdminController) UnblockProver(ctx *gin.Context) {
+	var pblp coordinatorType.ProverBlockListParameter
+	if err := ctx.ShouldBind(&pblp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+
+	if err := a.proverBlockListOrm.DeleteProverPublicKey(ctx.Copy(), pblp.PublicKey); err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// GetProverSessions returns the provers logged in with a token not expired yet, the latest login first
+func (a *AdminController) GetProverSessions(ctx *gin.Context) {
+	var psp coordinatorType.ProverSessionsParameter
+	if err := ctx.ShouldBind(&psp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if psp.Offset < 0 || psp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, offset and limit must not be negative"))
+		return
+	}
+	if psp.Limit == 0 || psp.Limit > maxAdminPageSize {
+		psp.Limit = maxAdminPageSize
+	}
+
+	sessions, err := a.proverSessionOrm.GetActiveProverSessions(ctx.Copy(), psp.PublicKey, utils.NowUTC(), psp.Offset, psp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schemas := make([]coordinatorType.ProverSessionSchema, 0, len(sessions))
+	for i := range sessions {
+		schemas = append(schemas, coordinatorType.ProverSessionSchema{
+			PublicKey:     sessions[i].PublicKey,
+			ProverName:    sessions[i].ProverName,
+			ProverVersion: sessions[i].ProverVersion,
+			LoggedInAt:    sessions[i].LoggedInAt.Unix(),
+			ExpiresAt:     sessions[i].ExpiresAt.Unix(),
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// GetRowUsage returns the row usage of the sub-circuits over a range of chunks, and the chunks closest to
+// the capacity of a sub-circuit, to tune the chunk sizes and spot the blocks which nearly overflow a chunk.
+func (a *AdminController) GetRowUsage(ctx *gin.Context) {
+	var rup coordinatorType.RowUsageParameter
+	if err := ctx.ShouldBind(&rup); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if rup.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, limit must not be negative"))
+		return
+	}
+	if rup.Limit == 0 || rup.Limit > maxAdminPageSize {
+		rup.Limit = maxAdminPageSize
+	}
+
+	var endIndex uint64
+	if rup.EndIndex != nil {
+		endIndex = *rup.EndIndex
+	} else {
+		latestIndex, err := a.chunkRowUsageOrm.GetLatestChunkIndex(ctx.Copy())
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+			return
+		}
+		if latestIndex == nil {
+			types.RenderSuccess(ctx, coordinatorType.RowUsageSchema{SubCircuits: []coordinatorType.SubCircuitRowUsageSchema{}, Chunks: []coordinatorType.ChunkRowUsageSchema{}})
+			return
+		}
+		endIndex = *latestIndex
+	}
+	var startIndex uint64
+	if rup.StartIndex != nil {
+		startIndex = *rup.StartIndex
+	} else if endIndex >= maxAdminPageSize {
+		startIndex = endIndex - maxAdminPageSize + 1
+	}
+	if startIndex > endIndex || endIndex-startIndex >= maxAdminPageSize {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, the range must cover 1 to %d chunks", maxAdminPageSize))
+		return
+	}
+
+	stats, err := a.chunkRowUsageOrm.GetSubCircuitRowStats(ctx.Copy(), startIndex, endIndex)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	rowUsages, err := a.chunkRowUsageOrm.GetChunkRowUsagesInRange(ctx.Copy(), startIndex, endIndex)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schema := coordinatorType.RowUsageSchema{
+		StartIndex:  startIndex,
+		EndIndex:    endIndex,
+		SubCircuits: make([]coordinatorType.SubCircuitRowUsageSchema, 0, len(stats)),
+		Chunks:      []coordinatorType.ChunkRowUsageSchema{},
+	}
+	for _, stat := range stats {
+		capacity := a.rowCapacity(stat.SubCircuit)
+		schema.SubCircuits = append(schema.SubCircuits, coordinatorType.SubCircuitRowUsageSchema{
+			SubCircuit:     stat.SubCircuit,
+			Capacity:       capacity,
+			ChunkCount:     stat.ChunkCount,
+			MaxRowNumber:   stat.MaxRowNumber,
+			AvgRowNumber:   stat.AvgRowNumber,
+			MaxUtilization: float64(stat.MaxRowNumber) / float64(capacity),
+			AvgUtilization: stat.AvgRowNumber / float64(capacity),
+		})
+	}
+	sort.SliceStable(schema.SubCircuits, func(i, j int) bool {
+		return schema.SubCircuits[i].MaxUtilization > schema.SubCircuits[j].MaxUtilization
+	})
+
+	// the row usages are ordered by chunk, only the bottleneck sub-circuit of every chunk is kept.
+	for i := range rowUsages {
+		rowUsage := &rowUsages[i]
+		capacity := a.rowCapacity(rowUsage.SubCircuit)
+		utilization := float64(rowUsage.RowNumber) / float64(capacity)
+		if n := len(schema.Chunks); n > 0 && schema.Chunks[n-1].ChunkHash == rowUsage.ChunkHash {
+			if utilization > schema.Chunks[n-1].Utilization {
+				schema.Chunks[n-1].SubCircuit = rowUsage.SubCircuit
+				schema.Chunks[n-1].RowNumber = rowUsage.RowNumber
+				schema.Chunks[n-1].Capacity = capacity
+				schema.Chunks[n-1].Utilization = utilization
+			}
+			continue
+		}
+		schema.Chunks = append(schema.Chunks, coordinatorType.ChunkRowUsageSchema{
+			ChunkIndex:       rowUsage.ChunkIndex,
+			ChunkHash:        rowUsage.ChunkHash,
+			StartBlockNumber: rowUsage.StartBlockNumber,
+			EndBlockNumber:   rowUsage.EndBlockNumber,
+			SubCircuit:       rowUsage.SubCircuit,
+			RowNumber:        rowUsage.RowNumber,
+			Capacity:         capacity,
+			Utilization:      utilization,
+		})
+	}
+	sort.SliceStable(schema.Chunks, func(i, j int) bool {
+		return schema.Chunks[i].Utilization > schema.Chunks[j].Utilization
+	})
+	if len(schema.Chunks) > rup.Limit {
+		schema.Chunks = schema.Chunks[:rup.Limit]
+	}
+	types.RenderSuccess(ctx, schema)
+}
+
+// rowCapacity returns the row capacity of the sub-circuit.
+func (a *AdminController) rowCapacity(subCircuit string) uint64 {
+	if capacity, ok := a.maxRowConsumptionPerSubCircuit[subCircuit]; ok && capacity != 0 {
+		return capacity
+	}
+	return a.maxRowConsumptionPerChunk
+}
+
+// GetWorkReceipts exports the receipts of the accepted proofs in the order they were recorded, for an external
+// rewards system to pay the provers. The export is paged by passing the id of the last receipt as after_id.
+func (a *AdminController) GetWorkReceipts(ctx *gin.Context) {
+	var wrp coordinatorType.WorkReceiptsParameter
+	if err := ctx.ShouldBind(&wrp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if wrp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, limit must not be negative"))
+		return
+	}
+	if wrp.Limit == 0 || wrp.Limit > maxAdminPageSize {
+		wrp.Limit = maxAdminPageSize
+	}
+
+	receipts, err := a.workReceiptOrm.GetWorkReceipts(ctx.Copy(), wrp.AfterID, wrp.PublicKey, wrp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schemas := make([]coordinatorType.WorkReceiptSchema, 0, len(receipts))
+	for i := range receipts {
+		receipt := &receipts[i]
+		schema := coordinatorType.WorkReceiptSchema{
+			ID:              receipt.ID,
+			UUID:            receipt.UUID,
+			TaskID:          receipt.TaskID,
+			TaskType:        message.ProofType(receipt.TaskType).String(),
+			ProverPublicKey: receipt.ProverPublicKey,
+			ProverName:      receipt.ProverName,
+			ProofHash:       receipt.ProofHash,
+			ProvingTimeSec:  receipt.ProvingTimeSec,
+			AcceptedAt:      receipt.AcceptedAt.Unix(),
+			Signature:       receipt.Signature,
+		}
+		if pk, err := crypto.DecompressPubkey(common.FromHex(receipt.ProverPublicKey)); err == nil {
+			schema.ProverAddress = crypto.PubkeyToAddress(*pk).Hex()
+		}
+		schemas = append(schemas, schema)
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// ResetTask sets a wedged chunk or batch task back to unassigned to be proven from scratch, clearing its proof, prover
+// assignment and attempts, and fails its assigned prover tasks so that their proofs are refused. The state of the
+// task before the reset is recorded in the audit log. The tasks of a finalized batch can't be reset.
+func (a *AdminController) ResetTask(ctx *gin.Context) {
+	var trp coordinatorType.TaskResetParameter
+	if err := ctx.ShouldBind(&trp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+
+	taskType := message.ProofType(trp.TaskType)
+	before := coordinatorType.TaskResetSchema{
+		TaskType: taskType.String(),
+		TaskID:   trp.TaskID,
+	}
+	var targetType, batchHash string
+	switch taskType {
+	case message.ProofTypeChunk:
+		targetType = "chunk"
+		chunk, err := a.chunkOrm.GetChunkByHash(ctx.Copy(), trp.TaskID)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+			return
+		}
+		batchHash = chunk.BatchHash
+		before.ProvingStatus = types.ProvingStatus(chunk.ProvingStatus).String()
+		before.ProofHash = chunk.ProofHash
+		before.TotalAttempts = chunk.TotalAttempts
+		before.ActiveAttempts = chunk.ActiveAttempts
+		before.FailedAttempts = chunk.FailedAttempts
+	case message.ProofTypeBatch:
+		targetType = "batch"
+		batchHash = trp.TaskID
+	default:
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, task_type must be %d (chunk) or %d (batch)", message.ProofTypeChunk, message.ProofTypeBatch))
+		return
+	}
+
+	if batchHash != "" {
+		batch, err := a.batchOrm.GetBatchByHash(ctx.Copy(), batchHash)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+			return
+		}
+		if types.RollupStatus(batch.RollupStatus) == types.RollupFinalized {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, fmt.Errorf("batch %s of the task is finalized", batchHash))
+			return
+		}
+		if taskType == message.ProofTypeBatch {
+			before.ProvingStatus = types.ProvingStatus(batch.ProvingStatus).String()
+			before.ProofHash = batch.ProofHash
+			before.TotalAttempts = batch.TotalAttempts
+			before.ActiveAttempts = batch.ActiveAttempts
+			before.FailedAttempts = batch.FailedAttempts
+		}
+	}
+
+	fields := map[string]interface{}{
+		"task_type = ?":      int(taskType),
+		"task_id = ?":        trp.TaskID,
+		"proving_status = ?": int(types.ProverAssigned),
+	}
+	proverTasks, err := a.proverTaskOrm.GetProverTasks(ctx.Copy(), fields, []string{"assigned_at ASC"}, 0, 0)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+	before.AssignedProvers = make([]string, 0, len(proverTasks))
+	for i := range proverTasks {
+		before.AssignedProvers = append(before.AssignedProvers, proverTasks[i].ProverPublicKey)
+	}
+
+	err = a.db.Transaction(func(tx *gorm.DB) error {
+		failed, resetErr := a.proverTaskOrm.ResetAssignedProverTasks(ctx.Copy(), taskType, trp.TaskID, tx)
+		if resetErr != nil {
+			return resetErr
+		}
+		before.ProverTasksFailed = failed
+
+		if taskType == message.ProofTypeChunk {
+			if resetErr = a.chunkOrm.ResetTaskByHash(ctx.Copy(), trp.TaskID, tx); resetErr != nil {
+				return resetErr
+			}
+			// the batch waits for the proof of the chunk again.
+			if batchHash != "" {
+				if resetErr = a.batchOrm.UpdateChunkProofsStatusByBatchHash(ctx.Copy(), batchHash, types.ChunkProofsStatusPending, tx); resetErr != nil {
+					return resetErr
+				}
+			}
+		} else if resetErr = a.batchOrm.ResetTaskByHash(ctx.Copy(), trp.TaskID, tx); resetErr != nil {
+			return resetErr
+		}
+
+		details, marshalErr := json.Marshal(before)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		return a.adminAuditLogOrm.InsertAdminAuditLog(ctx.Copy(), &orm.AdminAuditLog{
+			Action:     adminActionTaskReset,
+			TargetType: targetType,
+			TargetKey:  trp.TaskID,
+			Operator:   trp.Operator,
+			Reason:     trp.Reason,
+			Details:    string(details),
+		}, tx)
+	})
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	log.Warn("task reset by the admin", "task type", taskType.String(), "task id", trp.TaskID, "operator", trp.Operator,
+		"reason", trp.Reason, "proving status", before.ProvingStatus, "prover tasks failed", before.ProverTasksFailed)
+	types.RenderSuccess(ctx, before)
+}
+
+// SetTaskPriority changes the priority and the deadline of an unproven chunk or batch task, the provers are
+// assigned the urgent tasks first. The previous priority is recorded in the audit log.
+func (a *AdminController) SetTaskPriority(ctx *gin.Context) {
+	var tpp coordinatorType.TaskPriorityParameter
+	if err := ctx.ShouldBind(&tpp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	priority := message.TaskPriority(*tpp.Priority)
+	if priority > message.TaskPriorityHigh {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, priority must be at most %d (high)", message.TaskPriorityHigh))
+		return
+	}
+	if tpp.Deadline < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, deadline must not be negative"))
+		return
+	}
+	var deadline *time.Time
+	if tpp.Deadline != 0 {
+		t := time.Unix(tpp.Deadline, 0).UTC()
+		deadline = &t
+	}
+
+	taskType := message.ProofType(tpp.TaskType)
+	change := coordinatorType.TaskPrioritySchema{
+		TaskType:   taskType.String(),
+		TaskID:     tpp.TaskID,
+		ToPriority: priority.String(),
+		ToDeadline: tpp.Deadline,
+	}
+	var targetType string
+	var fromDeadline *time.Time
+	switch taskType {
+	case message.ProofTypeChunk:
+		targetType = "chunk"
+		chunk, err := a.chunkOrm.GetChunkByHash(ctx.Copy(), tpp.TaskID)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+			return
+		}
+		change.FromPriority = message.TaskPriority(chunk.Priority).String()
+		fromDeadline = chunk.Deadline
+	case message.ProofTypeBatch:
+		targetType = "batch"
+		batch, err := a.batchOrm.GetBatchByHash(ctx.Copy(), tpp.TaskID)
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+			return
+		}
+		change.FromPriority = message.TaskPriority(batch.Priority).String()
+		fromDeadline = batch.Deadline
+	default:
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, task_type must be %d (chunk) or %d (batch)", message.ProofTypeChunk, message.ProofTypeBatch))
+		return
+	}
+	if fromDeadline != nil {
+		change.FromDeadline = fromDeadline.Unix()
+	}
+
+	err := a.db.Transaction(func(tx *gorm.DB) error {
+		var updateErr error
+		if taskType == message.ProofTypeChunk {
+			updateErr = a.chunkOrm.UpdatePriorityByHash(ctx.Copy(), tpp.TaskID, priority, deadline, tx)
+		} else {
+			updateErr = a.batchOrm.UpdatePriorityByHash(ctx.Copy(), tpp.TaskID, priority, deadline, tx)
+		}
+		if updateErr != nil {
+			return updateErr
+		}
+
+		details, marshalErr := json.Marshal(change)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		return a.adminAuditLogOrm.InsertAdminAuditLog(ctx.Copy(), &orm.AdminAuditLog{
+			Action:     adminActionTaskPriority,
+			TargetType: targetType,
+			TargetKey:  tpp.TaskID,
+			Operator:   tpp.Operator,
+			Reason:     tpp.Reason,
+			Details:    string(details),
+		}, tx)
+	})
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	log.Info("task priority changed by the admin", "task type", taskType.String(), "task id", tpp.TaskID, "operator", tpp.Operator,
+		"reason", tpp.Reason, "from priority", change.FromPriority, "to priority", change.ToPriority, "deadline", tpp.Deadline)
+	types.RenderSuccess(ctx, change)
+}
+
+// GetAdminAuditLogs returns the actions of the admin api changing the state of a task, of the single task if
+// target_key is given, the latest first
+func (a *AdminController) GetAdminAuditLogs(ctx *gin.Context) {
+	var aalp coordinatorType.AdminAuditLogParameter
+	if err := ctx.ShouldBind(&aalp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if aalp.Offset < 0 || aalp.Limit < 0 {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, offset and limit must not be negative"))
+		return
+	}
+	if aalp.Limit == 0 || aalp.Limit > maxAdminPageSize {
+		aalp.Limit = maxAdminPageSize
+	}
+
+	logs, err := a.adminAuditLogOrm.GetAdminAuditLogs(ctx.Copy(), aalp.TargetKey, aalp.Offset, aalp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorAdminFailure, err)
+		return
+	}
+
+	schemas := make([]coordinatorType.AdminAuditLogSchema, 0, len(logs))
+	for i := range logs {
+		schemas = append(schemas, coordinatorType.AdminAuditLogSchema{
+			ID:         logs[i].ID,
+			Action:     logs[i].Action,
+			TargetType: logs[i].TargetType,
+			TargetKey:  logs[i].TargetKey,
+			Operator:   logs[i].Operator,
+			Reason:     logs[i].Reason,
+			Details:    logs[i].Details,
+			CreatedAt:  logs[i].CreatedAt.Unix(),
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/testcontainers"
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+	"scroll-tech/database/migrate"
+
+	"scroll-tech/coordinator/internal/orm"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+func TestAdminTaskStatsAndHistory(t *testing.T) {
+	apps := testcontainers.NewTestcontainerApps()
+	defer apps.Free()
+	assert.NoError(t, apps.StartPostgresContainer())
+	db, err := apps.GetGormDBClient()
+	assert.NoError(t, err)
+	defer database.CloseDB(db)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	a := NewAdminController(nil, db, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/task_stats", a.GetTaskStats)
+	router.GET("/prover_task_history", a.GetProverTaskHistory)
+	request := func(path string, data interface{}) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		resp := types.Response{Data: data}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.ErrCode
+	}
+
+	now := utils.NowUTC()
+	provedAt := now.Add(-10 * time.Minute)
+	assert.NoError(t, db.Create(&orm.Chunk{Index: 0, Hash: "chunk-0", ProvingStatus: int16(types.ProvingTaskVerified), ProvedAt: &provedAt}).Error)
+	assert.NoError(t, db.Create(&orm.Chunk{Index: 1, Hash: "chunk-1", ProvingStatus: int16(types.ProvingTaskAssigned)}).Error)
+	assert.NoError(t, db.Create(&orm.Chunk{Index: 2, Hash: "chunk-2", ProvingStatus: int16(types.ProvingTaskUnassigned), CreatedAt: now.Add(-time.Hour)}).Error)
+	assert.NoError(t, db.Create(&orm.Batch{Index: 0, Hash: "batch-0", BatchHeader: []byte{0x01}, ChunkProofsStatus: int16(types.ChunkProofsStatusPending), ProvingStatus: int16(types.ProvingTaskUnassigned)}).Error)
+
+	var stats []*coordinatorType.TaskStatsSchema
+	assert.Equal(t, types.Success, request("/task_stats", &stats))
+	assert.Len(t, stats, 2)
+	assert.Equal(t, message.ProofTypeChunk.String(), stats[0].TaskType)
+	assert.Equal(t, map[string]int64{"verified": 1, "assigned": 1, "unassigned": 1}, stats[0].StatusCounts)
+	assert.Equal(t, int64(2), stats[0].Backlog)
+	assert.Equal(t, int64(1), stats[0].ProofsLastHour)
+	assert.GreaterOrEqual(t, stats[0].OldestUnassignedAgeSec, uint64(3600))
+	// the batch waiting for its chunk proofs is in the backlog, but not waiting for a prover.
+	assert.Equal(t, message.ProofTypeBatch.String(), stats[1].TaskType)
+	assert.Equal(t, map[string]int64{"unassigned": 1}, stats[1].StatusCounts)
+	assert.Equal(t, int64(1), stats[1].Backlog)
+	assert.Equal(t, uint64(0), stats[1].OldestUnassignedAgeSec)
+	assert.Equal(t, int64(0), stats[1].ProofsLastHour)
+
+	proverTaskOrm := orm.NewProverTask(db)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &orm.ProverTask{
+			TaskType:        int16(message.ProofTypeChunk),
+			TaskID:          fmt.Sprintf("chunk-%d", i),
+			ProverName:      "prover",
+			ProverPublicKey: "prover-key",
+			ProvingStatus:   int16(types.ProverAssigned),
+			Reward:          decimal.NewFromInt(0),
+			AssignedAt:      now.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &orm.ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "chunk-0",
+		ProverName:      "other",
+		ProverPublicKey: "other-key",
+		ProvingStatus:   int16(types.ProverAssigned),
+		Reward:          decimal.NewFromInt(0),
+		AssignedAt:      now,
+	}))
+
+	// the tasks of the prover are returned the latest first, one page at a time.
+	var history []*coordinatorType.ProverTaskHistorySchema
+	assert.Equal(t, types.Success, request("/prover_task_history?public_key=prover-key&limit=2", &history))
+	assert.Len(t, history, 2)
+	assert.Equal(t, "chunk-2", history[0].TaskID)
+	assert.Equal(t, "chunk-1", history[1].TaskID)
+	assert.Equal(t, "ProverAssigned", history[0].ProvingStatus)
+	history = nil
+	assert.Equal(t, types.Success, request("/prover_task_history?public_key=prover-key&offset=2&limit=2", &history))
+	assert.Len(t, history, 1)
+	assert.Equal(t, "chunk-0", history[0].TaskID)
+
+	for _, path := range []string{
+		"/prover_task_history",
+		"/prover_task_history?public_key=",
+		"/prover_task_history?public_key=%20",
+		"/prover_task_history?public_key=prover-key&offset=-1",
+		"/prover_task_history?public_key=prover-key&limit=-1",
+	} {
+		assert.Equal(t, types.ErrCoordinatorParameterInvalidNo, request(path, nil), path)
+	}
+}
@@ -1,32 +1,44 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
 	"scroll-tech/common/types/message"
 
+	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/logic/auth"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
 	"scroll-tech/coordinator/internal/types"
 )
 
 // AuthController is login API
 type AuthController struct {
-	loginLogic *auth.LoginLogic
+	loginLogic  *auth.LoginLogic
+	rateLimiter *ratelimit.Limiter
+	drain       *Drainer
 }
 
-// NewAuthController returns an LoginController instance
-func NewAuthController(db *gorm.DB) *AuthController {
+// NewAuthController returns an LoginController instance, rateLimiter may be nil
+func NewAuthController(cfg *config.Config, db *gorm.DB, rateLimiter *ratelimit.Limiter, drain *Drainer) *AuthController {
 	return &AuthController{
-		loginLogic: auth.NewLoginLogic(db),
+		loginLogic:  auth.NewLoginLogic(cfg, db),
+		rateLimiter: rateLimiter,
+		drain:       drain,
 	}
 }
 
 // Login the api controller for login
 func (a *AuthController) Login(c *gin.Context) (interface{}, error) {
+	if a.drain.Draining() {
+		return "", fmt.Errorf("login failure: coordinator is shutting down")
+	}
+
 	var login types.LoginParameter
 	if err := c.ShouldBind(&login); err != nil {
 		return "", fmt.Errorf("missing the public_key, err:%w", err)
@@ -39,13 +51,74 @@ func (a *AuthController) Login(c *gin.Context) (interface{}, error) {
 		return "", fmt.Errorf("check challenge failure for the not equal challenge string")
 	}
 
+	publicKey, err := recoverPublicKey(login)
+	if err != nil {
+		return "", fmt.Errorf("login recover public key failure:%w", err)
+	}
+	if a.rateLimiter != nil {
+		if err = a.rateLimiter.Allow(ratelimit.Login, publicKey); err != nil {
+			return "", fmt.Errorf("login failure:%w", err)
+		}
+	}
+
+	if err = a.loginLogic.CheckProverAllowed(c, publicKey); err != nil {
+		return "", fmt.Errorf("login failure:%w", err)
+	}
+
+	warning, err := a.loginLogic.CheckProverVersion(login.Message.ProverVersion)
+	if err != nil {
+		return "", fmt.Errorf("login check prover version failure:%w", err)
+	}
+	if warning != "" {
+		log.Warn("deprecated prover version login", "prover name", login.Message.ProverName, "prover version", login.Message.ProverVersion)
+		c.Set(types.VersionWarning, warning)
+	}
+
 	// check the challenge is used, if used, return failure
 	if err := a.loginLogic.InsertChallengeString(c, login.Message.Challenge); err != nil {
 		return "", fmt.Errorf("login insert challenge string failure:%w", err)
 	}
+
+	a.loginLogic.RecordSession(c, publicKey, login.Message.ProverName, login.Message.ProverVersion)
 	return login, nil
 }
 
+// CheckRefresh runs the login checks again for the prover of a token to refresh, so that the allow list and the
+// version policy changed since its login apply to the refreshed token, and records its session again.
+func (a *AuthController) CheckRefresh(c *gin.Context, claims jwt.MapClaims) error {
+	if a.drain.Draining() {
+		return fmt.Errorf("refresh failure: coordinator is shutting down")
+	}
+
+	publicKey, _ := claims[types.PublicKey].(string)
+	proverName, _ := claims[types.ProverName].(string)
+	proverVersion, _ := claims[types.ProverVersion].(string)
+	if publicKey == "" {
+		return fmt.Errorf("refresh failure: missing the public_key of the token")
+	}
+	if a.rateLimiter != nil {
+		if err := a.rateLimiter.Allow(ratelimit.Login, publicKey); err != nil {
+			return fmt.Errorf("refresh failure:%w", err)
+		}
+	}
+
+	if err := a.loginLogic.CheckProverAllowed(c, publicKey); err != nil {
+		return fmt.Errorf("refresh failure:%w", err)
+	}
+
+	warning, err := a.loginLogic.CheckProverVersion(proverVersion)
+	if err != nil {
+		return fmt.Errorf("refresh check prover version failure:%w", err)
+	}
+	if warning != "" {
+		log.Warn("deprecated prover version token refresh", "prover name", proverName, "prover version", proverVersion)
+		c.Set(types.VersionWarning, warning)
+	}
+
+	a.loginLogic.RecordSession(c, publicKey, proverName, proverVersion)
+	return nil
+}
+
 // PayloadFunc returns jwt.MapClaims with {public key, prover name}.
 func (a *AuthController) PayloadFunc(data interface{}) jwt.MapClaims {
 	v, ok := data.(types.LoginParameter)
@@ -53,31 +126,7 @@ func (a *AuthController) PayloadFunc(data interface{}) jwt.MapClaims {
 		return jwt.MapClaims{}
 	}
 
-	var publicKey string
-	var err error
-	if v.Message.HardForkName != "" {
-		authMsg := message.AuthMsg{
-			Identity: &message.Identity{
-				Challenge:     v.Message.Challenge,
-				ProverName:    v.Message.ProverName,
-				ProverVersion: v.Message.ProverVersion,
-				HardForkName:  v.Message.HardForkName,
-			},
-			Signature: v.Signature,
-		}
-		publicKey, err = authMsg.PublicKey()
-	} else {
-		authMsg := message.LegacyAuthMsg{
-			Identity: &message.LegacyIdentity{
-				Challenge:     v.Message.Challenge,
-				ProverName:    v.Message.ProverName,
-				ProverVersion: v.Message.ProverVersion,
-			},
-			Signature: v.Signature,
-		}
-		publicKey, err = authMsg.PublicKey()
-	}
-
+	publicKey, err := recoverPublicKey(v)
 	if err != nil {
 		return jwt.MapClaims{}
 	}
@@ -86,12 +135,28 @@ func (a *AuthController) PayloadFunc(data interface{}) jwt.MapClaims {
 		v.Message.HardForkName = "shanghai"
 	}
 
-	return jwt.MapClaims{
+	claims := jwt.MapClaims{
 		types.PublicKey:     publicKey,
 		types.ProverName:    v.Message.ProverName,
 		types.ProverVersion: v.Message.ProverVersion,
 		types.HardForkName:  v.Message.HardForkName,
 	}
+	if v.Message.Hardware != nil {
+		// keep the hardware as a json string, so it's passed through the context as is like the other claims.
+		hardware, err := json.Marshal(v.Message.Hardware)
+		if err != nil {
+			return jwt.MapClaims{}
+		}
+		claims[types.Hardware] = string(hardware)
+	}
+	if v.Message.MaxConcurrentTasks != nil {
+		maxConcurrentTasks, err := json.Marshal(v.Message.MaxConcurrentTasks)
+		if err != nil {
+			return jwt.MapClaims{}
+		}
+		claims[types.MaxConcurrentTasks] = string(maxConcurrentTasks)
+	}
+	return claims
 }
 
 // IdentityHandler replies to client for /login
@@ -112,5 +177,65 @@ func (a *AuthController) IdentityHandler(c *gin.Context) interface{} {
 	if hardForkName, ok := claims[types.HardForkName]; ok {
 		c.Set(types.HardForkName, hardForkName)
 	}
+
+	if hardware, ok := claims[types.Hardware]; ok {
+		c.Set(types.Hardware, hardware)
+	}
+
+	if maxConcurrentTasks, ok := claims[types.MaxConcurrentTasks]; ok {
+		c.Set(types.MaxConcurrentTasks, maxConcurrentTasks)
+	}
 	return nil
 }
+
+// recoverPublicKey recovers the public key of the prover from the signature of the login message, the advertised
+// hardware and concurrency limits are part of the signed message.
+func recoverPublicKey(login types.LoginParameter) (string, error) {
+	hardware, concurrencyLimits := signedCapabilities(login.Message)
+	if login.Message.HardForkName != "" {
+		authMsg := message.AuthMsg{
+			Identity: &message.Identity{
+				Challenge:         login.Message.Challenge,
+				ProverName:        login.Message.ProverName,
+				ProverVersion:     login.Message.ProverVersion,
+				HardForkName:      login.Message.HardForkName,
+				Hardware:          hardware,
+				ConcurrencyLimits: concurrencyLimits,
+			},
+			Signature: login.Signature,
+		}
+		return authMsg.PublicKey()
+	}
+
+	authMsg := message.LegacyAuthMsg{
+		Identity: &message.LegacyIdentity{
+			Challenge:         login.Message.Challenge,
+			ProverName:        login.Message.ProverName,
+			ProverVersion:     login.Message.ProverVersion,
+			Hardware:          hardware,
+			ConcurrencyLimits: concurrencyLimits,
+		},
+		Signature: login.Signature,
+	}
+	return authMsg.PublicKey()
+}
+
+// signedCapabilities converts the capabilities of the login message to the fields of the signed identity.
+func signedCapabilities(msg types.Message) (*message.HardwareInfo, []message.ConcurrencyLimit) {
+	var hardware *message.HardwareInfo
+	if msg.Hardware != nil {
+		hardware = &message.HardwareInfo{
+			GPUModel:    msg.Hardware.GPUModel,
+			GPUMemoryMB: msg.Hardware.GPUMemoryMB,
+			CPUCores:    msg.Hardware.CPUCores,
+			MemoryMB:    msg.Hardware.MemoryMB,
+		}
+	}
+	var concurrencyLimits []message.ConcurrencyLimit
+	for _, proofType := range []message.ProofType{message.ProofTypeChunk, message.ProofTypeBatch} {
+		if limit := msg.MaxConcurrentTasks.Limit(proofType); limit != nil {
+			concurrencyLimits = append(concurrencyLimits, message.ConcurrencyLimit{ProofType: proofType, MaxTasks: *limit})
+		}
+	}
+	return hardware, concurrencyLimits
+}
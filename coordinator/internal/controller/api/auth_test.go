@@ -0,0 +1,61 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/types"
+)
+
+func TestRecoverPublicKey(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	publicKey := common.Bytes2Hex(crypto.CompressPubkey(&privKey.PublicKey))
+
+	chunkLimit, batchLimit := uint32(2), uint32(0)
+	authMsg := message.LegacyAuthMsg{Identity: &message.LegacyIdentity{
+		Challenge:         "challenge",
+		ProverName:        "test",
+		ProverVersion:     "v4.4.0",
+		Hardware:          &message.HardwareInfo{GPUModel: "NVIDIA A100", GPUMemoryMB: 81920, CPUCores: 64, MemoryMB: 512000},
+		ConcurrencyLimits: []message.ConcurrencyLimit{{ProofType: message.ProofTypeChunk, MaxTasks: chunkLimit}, {ProofType: message.ProofTypeBatch, MaxTasks: batchLimit}},
+	}}
+	assert.NoError(t, authMsg.SignWithKey(privKey))
+
+	login := types.LoginParameter{
+		Message: types.Message{
+			Challenge:          "challenge",
+			ProverName:         "test",
+			ProverVersion:      "v4.4.0",
+			Hardware:           &types.HardwareInfo{GPUModel: "NVIDIA A100", GPUMemoryMB: 81920, CPUCores: 64, MemoryMB: 512000},
+			MaxConcurrentTasks: &types.ConcurrencyLimits{Chunk: &chunkLimit, Batch: &batchLimit},
+		},
+		Signature: authMsg.Signature,
+	}
+	recovered, err := recoverPublicKey(login)
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey, recovered)
+
+	// the capabilities are signed, a proxy changing them doesn't log in as the prover.
+	login.Message.Hardware.GPUMemoryMB = 24000
+	recovered, err = recoverPublicKey(login)
+	assert.NoError(t, err)
+	assert.NotEqual(t, publicKey, recovered)
+
+	login.Message.Hardware.GPUMemoryMB = 81920
+	otherLimit := uint32(8)
+	login.Message.MaxConcurrentTasks.Chunk = &otherLimit
+	recovered, err = recoverPublicKey(login)
+	assert.NoError(t, err)
+	assert.NotEqual(t, publicKey, recovered)
+
+	login.Message.MaxConcurrentTasks = nil
+	recovered, err = recoverPublicKey(login)
+	assert.NoError(t, err)
+	assert.NotEqual(t, publicKey, recovered)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/orm"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+const (
+	cancelReasonInvalidated = "task invalidated by a re-chunking or a reorg"
+	cancelReasonProved      = "task already proved"
+)
+
+// CancelledTasks returns the cancellations of the tasks assigned to the prover which nobody wants the proof of
+// anymore: their chunk or batch was deleted by a re-chunking or a reorg, or it has been proved meanwhile.
+func (rc *ReportProgressController) CancelledTasks(ctx context.Context, publicKey string) ([]*message.CancelTaskMsg, error) {
+	proverTasks, err := rc.proverTaskOrm.GetAssignedProverTasksByPublicKey(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var cancelTasks []*message.CancelTaskMsg
+	for i := range proverTasks {
+		reason, err := rc.cancelReason(ctx, &proverTasks[i])
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			continue
+		}
+		cancelTasks = append(cancelTasks, &message.CancelTaskMsg{
+			UUID:   proverTasks[i].UUID.String(),
+			ID:     proverTasks[i].TaskID,
+			Type:   message.ProofType(proverTasks[i].TaskType),
+			Reason: reason,
+		})
+	}
+	return cancelTasks, nil
+}
+
+// cancelReason returns why the prover task is cancelled, empty if it's still wanted.
+func (rc *ReportProgressController) cancelReason(ctx context.Context, proverTask *orm.ProverTask) (string, error) {
+	var status types.ProvingStatus
+	var err error
+	switch message.ProofType(proverTask.TaskType) {
+	case message.ProofTypeChunk:
+		status, err = rc.chunkOrm.GetProvingStatusByHash(ctx, proverTask.TaskID)
+	case message.ProofTypeBatch:
+		status, err = rc.batchOrm.GetProvingStatusByHash(ctx, proverTask.TaskID)
+	default:
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch status {
+	case types.ProvingStatusUndefined:
+		// the deleted chunks and batches are not found.
+		return cancelReasonInvalidated, nil
+	case types.ProvingTaskVerified:
+		return cancelReasonProved, nil
+	default:
+		return "", nil
+	}
+}
+
+// AckCancelTask prover acknowledges it aborted a cancelled task
+func (rc *ReportProgressController) AckCancelTask(ctx *gin.Context) {
+	var acp coordinatorType.AckCancelTaskParameter
+	if err := ctx.ShouldBind(&acp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+
+	if errCode, err := rc.HandleAckCancelTask(ctx, acp); err != nil {
+		types.RenderFailure(ctx, errCode, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// HandleAckCancelTask releases the cancelled task of the prover whose identity is stored in ctx, without counting it
+// as a failure of the prover. It is shared by the http and grpc transports, the returned int is the errno of the failure.
+func (rc *ReportProgressController) HandleAckCancelTask(ctx *gin.Context, acp coordinatorType.AckCancelTaskParameter) (int, error) {
+	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
+	if !publicKeyExist {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("get public key from context failed")
+	}
+
+	proverTask, err := rc.proverTaskOrm.GetProverTaskByUUIDAndPublicKey(ctx.Copy(), acp.UUID, publicKey.(string))
+	if err != nil {
+		return types.ErrCoordinatorAckCancelTaskFailure, fmt.Errorf("ack cancel task failure, err:%w", err)
+	}
+	if proverTask.TaskID != acp.TaskID || int(proverTask.TaskType) != acp.TaskType {
+		return types.ErrCoordinatorAckCancelTaskFailure, fmt.Errorf("ack cancel task failure, no task of uuid:%s", acp.UUID)
+	}
+
+	// only the cancelled tasks can be released, a prover can't drop the tasks it doesn't like without penalty.
+	reason, err := rc.cancelReason(ctx.Copy(), proverTask)
+	if err != nil {
+		return types.ErrCoordinatorAckCancelTaskFailure, fmt.Errorf("ack cancel task failure, err:%w", err)
+	}
+	if reason == "" {
+		return types.ErrCoordinatorAckCancelTaskFailure, fmt.Errorf("ack cancel task failure, task of uuid:%s is not cancelled", acp.UUID)
+	}
+
+	cancelled, err := rc.proverTaskOrm.CancelProverTask(ctx.Copy(), acp.UUID, publicKey.(string))
+	if err != nil {
+		return types.ErrCoordinatorAckCancelTaskFailure, fmt.Errorf("ack cancel task failure, err:%w", err)
+	}
+	if !cancelled {
+		return types.ErrCoordinatorAckCancelTaskFailure, fmt.Errorf("ack cancel task failure, no assigned task of uuid:%s", acp.UUID)
+	}
+
+	rc.drain.submitted(acp.TaskType, acp.TaskID, publicKey.(string))
+	rc.proverTaskCancelledTotal.Inc()
+	log.Info("prover aborted its cancelled task", "uuid", acp.UUID, "task_id", acp.TaskID, "public key", publicKey, "reason", reason)
+	return types.Success, nil
+}
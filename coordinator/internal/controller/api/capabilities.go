@@ -0,0 +1,91 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+
+	"scroll-tech/common/forks"
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/version"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/verifier"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// CapabilitiesController the capabilities api controller
+type CapabilitiesController struct {
+	schema coordinatorType.CapabilitiesSchema
+}
+
+// NewCapabilitiesController create the capabilities api controller instance, the capabilities only change with
+// the config so they're computed once
+func NewCapabilitiesController(cfg *config.Config, chainCfg *params.ChainConfig, vf *verifier.Verifier) *CapabilitiesController {
+	schema := coordinatorType.CapabilitiesSchema{
+		CoordinatorVersion: version.Version,
+		ProtocolVersions:   []string{"v1"},
+		ProofTypes: []coordinatorType.CapabilityProofType{
+			{Name: "chunk", TaskType: int(message.ProofTypeChunk)},
+			{Name: "batch", TaskType: int(message.ProofTypeBatch)},
+		},
+		ProverVersions:     coordinatorType.CapabilityProverRange{MinVersion: cfg.ProverManager.MinProverVersion},
+		CircuitVersions:    []string{},
+		HardForks:          []coordinatorType.CapabilityHardFork{},
+		RequireSignedProof: cfg.ProverManager.RequireSignedProof || cfg.ProverManager.Marketplace != nil,
+	}
+	if taskSigning := cfg.ProverManager.TaskSigning; taskSigning != nil {
+		// the key is checked when the config is loaded.
+		if key, err := taskSigning.Key(); err == nil {
+			schema.TaskSigner = crypto.PubkeyToAddress(key.PublicKey).Hex()
+		}
+	}
+	if upload := cfg.ProverManager.ProofUpload; upload != nil {
+		schema.ProofUpload = &coordinatorType.CapabilityProofUpload{MaxPartSize: upload.MaxPartSize, MaxParts: upload.MaxParts}
+	}
+	if policy := cfg.ProverManager.VersionPolicy; policy != nil {
+		schema.ProverVersions.MaxVersion = policy.MaxProverVersion
+		schema.ProverVersions.DeprecatedVersion = policy.DeprecatedProverVersion
+		schema.ProverVersions.DeprecationDeadline = policy.DeprecationDeadline
+		schema.CircuitVersions = append(schema.CircuitVersions, policy.CircuitVersions...)
+	}
+
+	var nameForkMap map[string]uint64
+	if chainCfg != nil {
+		_, _, nameForkMap = forks.CollectSortedForkHeights(chainCfg)
+	}
+	forkNames := make(map[string]struct{})
+	for name := range vf.ChunkVKMap {
+		forkNames[name] = struct{}{}
+	}
+	for name := range vf.BatchVKMap {
+		forkNames[name] = struct{}{}
+	}
+	for name := range forkNames {
+		hardFork := coordinatorType.CapabilityHardFork{Name: name, ChunkVK: vf.ChunkVKMap[name], BatchVK: vf.BatchVKMap[name]}
+		if height, ok := nameForkMap[name]; ok {
+			hardFork.Height = &height
+		}
+		schema.HardForks = append(schema.HardForks, hardFork)
+	}
+	// the scheduled hard forks by height, then the others by name.
+	sort.Slice(schema.HardForks, func(i, j int) bool {
+		hi, hj := schema.HardForks[i].Height, schema.HardForks[j].Height
+		if hi != nil && hj != nil && *hi != *hj {
+			return *hi < *hj
+		}
+		if (hi == nil) != (hj == nil) {
+			return hi != nil
+		}
+		return schema.HardForks[i].Name < schema.HardForks[j].Name
+	})
+	return &CapabilitiesController{schema: schema}
+}
+
+// GetCapabilities returns the proof types, versions and hard forks the coordinator supports
+func (cc *CapabilitiesController) GetCapabilities(ctx *gin.Context) {
+	types.RenderSuccess(ctx, cc.schema)
+}
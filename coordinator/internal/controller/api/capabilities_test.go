@@ -0,0 +1,57 @@
+package api
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/verifier"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+func TestCapabilitiesController(t *testing.T) {
+	cfg := &config.Config{ProverManager: &config.ProverManager{
+		MinProverVersion: "v4.4.0",
+		VersionPolicy: &config.VersionPolicy{
+			MaxProverVersion: "v5.0.0",
+			CircuitVersions:  []string{"v0.12.0"},
+		},
+		RequireSignedProof: true,
+		ProofUpload:        &config.ProofUploadConfig{MaxPartSize: 1024, MaxParts: 8},
+		TaskSigning:        &config.TaskSigning{SigningKey: "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"},
+	}}
+	chainCfg := &params.ChainConfig{BernoulliBlock: big.NewInt(100), CurieBlock: big.NewInt(200)}
+	vf := &verifier.Verifier{
+		ChunkVKMap: map[string]string{"curie": "curie chunk vk", "bernoulli": "bernoulli chunk vk", "darwin": "darwin chunk vk"},
+		BatchVKMap: map[string]string{"curie": "curie batch vk", "bernoulli": "bernoulli batch vk"},
+	}
+
+	schema := NewCapabilitiesController(cfg, chainCfg, vf).schema
+	assert.Equal(t, []string{"v1"}, schema.ProtocolVersions)
+	assert.Len(t, schema.ProofTypes, 2)
+	assert.Equal(t, int(message.ProofTypeChunk), schema.ProofTypes[0].TaskType)
+	assert.Equal(t, int(message.ProofTypeBatch), schema.ProofTypes[1].TaskType)
+	assert.Equal(t, "v4.4.0", schema.ProverVersions.MinVersion)
+	assert.Equal(t, "v5.0.0", schema.ProverVersions.MaxVersion)
+	assert.Equal(t, []string{"v0.12.0"}, schema.CircuitVersions)
+	assert.True(t, schema.RequireSignedProof)
+	assert.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", schema.TaskSigner)
+	assert.Equal(t, &coordinatorType.CapabilityProofUpload{MaxPartSize: 1024, MaxParts: 8}, schema.ProofUpload)
+
+	// the scheduled hard forks by height, then the ones the chain config doesn't schedule.
+	assert.Len(t, schema.HardForks, 3)
+	assert.Equal(t, "bernoulli", schema.HardForks[0].Name)
+	assert.Equal(t, uint64(100), *schema.HardForks[0].Height)
+	assert.Equal(t, "bernoulli batch vk", schema.HardForks[0].BatchVK)
+	assert.Equal(t, "curie", schema.HardForks[1].Name)
+	assert.Equal(t, uint64(200), *schema.HardForks[1].Height)
+	assert.Equal(t, "darwin", schema.HardForks[2].Name)
+	assert.Nil(t, schema.HardForks[2].Height)
+	assert.Equal(t, "darwin chunk vk", schema.HardForks[2].ChunkVK)
+	assert.Empty(t, schema.HardForks[2].BatchVK)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/config"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// CircuitAssetsController the circuit assets api controller
+type CircuitAssetsController struct {
+	schema coordinatorType.CircuitAssetsSchema
+}
+
+// NewCircuitAssetsController create the circuit assets api controller instance
+func NewCircuitAssetsController(cfg *config.Config) *CircuitAssetsController {
+	schema := coordinatorType.CircuitAssetsSchema{Releases: []coordinatorType.CircuitAssetsRelease{}}
+	for _, release := range cfg.ProverManager.CircuitAssets {
+		files := make([]coordinatorType.CircuitAssetFile, len(release.Files))
+		for i, file := range release.Files {
+			files[i] = coordinatorType.CircuitAssetFile{Path: file.Path, SHA256: file.SHA256}
+		}
+		schema.Releases = append(schema.Releases, coordinatorType.CircuitAssetsRelease{
+			HardForkName:   release.HardForkName,
+			CircuitVersion: release.CircuitVersion,
+			UpgradeHeight:  release.UpgradeHeight,
+			BaseURL:        release.BaseURL,
+			Files:          files,
+		})
+	}
+	return &CircuitAssetsController{schema: schema}
+}
+
+// GetCircuitAssets returns the circuit asset releases, the provers download the files and check their digests
+func (cc *CircuitAssetsController) GetCircuitAssets(ctx *gin.Context) {
+	types.RenderSuccess(ctx, cc.schema)
+}
@@ -6,7 +6,10 @@ import (
 	"github.com/scroll-tech/go-ethereum/params"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
+
 	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
 	"scroll-tech/coordinator/internal/logic/verifier"
 )
 
@@ -17,6 +20,20 @@ var (
 	SubmitProof *SubmitProofController
 	// Auth the auth controller
 	Auth *AuthController
+	// ReportProgress the prover progress report controller
+	ReportProgress *ReportProgressController
+	// Admin the admin api controller
+	Admin *AdminController
+	// CircuitAssets the circuit assets api controller
+	CircuitAssets *CircuitAssetsController
+	// Capabilities the capabilities api controller
+	Capabilities *CapabilitiesController
+	// ProofUpload the proof upload api controller, nil if the proof upload in parts is disabled
+	ProofUpload *ProofUploadController
+	// RateLimiter the per prover rate limiter, nil if the rate limits are disabled
+	RateLimiter *ratelimit.Limiter
+	// Drain tracks the in-flight tasks and stops the assignments while the coordinator shuts down
+	Drain *Drainer
 )
 
 // InitController inits Controller with database
@@ -28,7 +45,29 @@ func InitController(cfg *config.Config, chainCfg *params.ChainConfig, db *gorm.D
 
 	log.Info("verifier created", "chunkVerifier", vf.ChunkVKMap, "batchVerifier", vf.BatchVKMap)
 
-	Auth = NewAuthController(db)
-	GetTask = NewGetTaskController(cfg, chainCfg, db, vf, reg)
-	SubmitProof = NewSubmitProofController(cfg, db, vf, reg)
+	var proofStore objectstore.Store
+	if cfg.DB != nil {
+		proofStore, err = objectstore.New(cfg.DB.ProofStore)
+		if err != nil {
+			panic("new proof store failure, err:" + err.Error())
+		}
+	}
+
+	RateLimiter = nil
+	if cfg.RateLimit != nil {
+		RateLimiter = ratelimit.NewLimiter(cfg.RateLimit, reg)
+	}
+
+	Drain = NewDrainer()
+	Auth = NewAuthController(cfg, db, RateLimiter, Drain)
+	GetTask = NewGetTaskController(cfg, chainCfg, db, proofStore, vf, Drain, reg)
+	SubmitProof = NewSubmitProofController(cfg, db, proofStore, vf, Drain, reg)
+	ReportProgress = NewReportProgressController(db, Drain, reg)
+	Admin = NewAdminController(cfg.Admin, db, RateLimiter)
+	CircuitAssets = NewCircuitAssetsController(cfg)
+	Capabilities = NewCapabilitiesController(cfg, chainCfg, vf)
+	ProofUpload = nil
+	if cfg.ProverManager.ProofUpload != nil {
+		ProofUpload = NewProofUploadController(cfg.ProverManager.ProofUpload, db, reg)
+	}
 }
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// drainPollInterval is how often Wait checks whether the in-flight tasks are all submitted.
+const drainPollInterval = time.Second
+
+// Drainer tracks the tasks assigned by this coordinator until their proofs are submitted, so a shutdown
+// can stop assigning tasks and wait for the in-flight proofs instead of cutting the provers off.
+type Drainer struct {
+	draining atomic.Bool
+
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+}
+
+// NewDrainer creates a drainer with no task in flight.
+func NewDrainer() *Drainer {
+	return &Drainer{inFlight: make(map[string]struct{})}
+}
+
+// Start stops accepting new provers and assigning new tasks.
+func (d *Drainer) Start() {
+	d.draining.Store(true)
+}
+
+// Draining reports whether the coordinator is shutting down.
+func (d *Drainer) Draining() bool {
+	return d.draining.Load()
+}
+
+// InFlight returns the number of assigned tasks whose proof is not submitted yet.
+func (d *Drainer) InFlight() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.inFlight)
+}
+
+// Wait blocks until all the in-flight tasks are submitted or ctx is done, and returns the number of
+// tasks still in flight. The unsubmitted tasks stay assigned in the database, so their provers can
+// still submit them to the restarted coordinator before they time out.
+func (d *Drainer) Wait(ctx context.Context) int {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		inFlight := d.InFlight()
+		if inFlight == 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return inFlight
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Drainer) assigned(taskType int, taskID, publicKey string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.inFlight[drainKey(taskType, taskID, publicKey)] = struct{}{}
+}
+
+func (d *Drainer) submitted(taskType int, taskID, publicKey string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inFlight, drainKey(taskType, taskID, publicKey))
+}
+
+func drainKey(taskType int, taskID, publicKey string) string {
+	return fmt.Sprintf("%d:%s:%s", taskType, taskID, publicKey)
+}
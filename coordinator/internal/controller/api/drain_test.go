@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainer(t *testing.T) {
+	d := NewDrainer()
+	assert.False(t, d.Draining())
+
+	d.assigned(1, "chunk", "prover1")
+	d.assigned(2, "batch", "prover1")
+	d.assigned(1, "chunk", "prover2")
+	assert.Equal(t, 3, d.InFlight())
+
+	d.Start()
+	assert.True(t, d.Draining())
+
+	d.submitted(1, "chunk", "prover1")
+	d.submitted(1, "unknown", "prover1")
+	assert.Equal(t, 2, d.InFlight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 2, d.Wait(ctx))
+
+	d.submitted(2, "batch", "prover1")
+	d.submitted(1, "chunk", "prover2")
+	assert.Equal(t, 0, d.Wait(context.Background()))
+}
@@ -1,39 +1,86 @@
 package api
 
 import (
+	"crypto/ecdsa"
 	"fmt"
-	"math/rand"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/logic/provertask"
 	"scroll-tech/coordinator/internal/logic/verifier"
+	"scroll-tech/coordinator/internal/orm"
 	coordinatorType "scroll-tech/coordinator/internal/types"
 )
 
+// activeProverSessionWindow is how long a prover counts as active after its last get_task request.
+const activeProverSessionWindow = 5 * time.Minute
+
+// schedulerTick is how long the top priorities of the unassigned tasks are cached for, so they're read once per
+// tick instead of on every get_task request.
+const schedulerTick = time.Second
+
+// proverSessionLeasePrefix prefixes the public key of a prover in the name of its session lease.
+const proverSessionLeasePrefix = "prover_session:"
+
 // GetTaskController the get prover task api controller
 type GetTaskController struct {
 	proverTasks map[message.ProofType]provertask.ProverTask
+	scheduler   *provertask.Scheduler
+
+	// topPrioritiesCache holds the top priorities read at topPrioritiesAt, for a scheduler tick.
+	topPrioritiesMu    sync.Mutex
+	topPrioritiesCache map[message.ProofType]message.TaskPriority
+	topPrioritiesAt    time.Time
+
+	// proverLastSeen maps the public key of the provers to the time of their last get_task request.
+	proverLastSeen sync.Map
+
+	chunkOrm *orm.Chunk
+	batchOrm *orm.Batch
+	ha       *config.HA
+	leaseOrm *orm.Lease
+	drain    *Drainer
+	// taskKey signs the assigned tasks, nil unless the task signing is enabled.
+	taskKey *ecdsa.PrivateKey
 
 	getTaskAccessCounter *prometheus.CounterVec
 }
 
 // NewGetTaskController create a get prover task controller
-func NewGetTaskController(cfg *config.Config, chainCfg *params.ChainConfig, db *gorm.DB, vf *verifier.Verifier, reg prometheus.Registerer) *GetTaskController {
+func NewGetTaskController(cfg *config.Config, chainCfg *params.ChainConfig, db *gorm.DB, proofStore objectstore.Store, vf *verifier.Verifier, drain *Drainer, reg prometheus.Registerer) *GetTaskController {
 	chunkProverTask := provertask.NewChunkProverTask(cfg, chainCfg, db, vf.ChunkVKMap, reg)
-	batchProverTask := provertask.NewBatchProverTask(cfg, chainCfg, db, vf.BatchVKMap, reg)
+	batchProverTask := provertask.NewBatchProverTask(cfg, chainCfg, db, proofStore, vf.BatchVKMap, reg)
+
+	var taskKey *ecdsa.PrivateKey
+	if cfg.ProverManager.TaskSigning != nil {
+		var err error
+		if taskKey, err = cfg.ProverManager.TaskSigning.Key(); err != nil {
+			log.Crit("failed to load the task signing key", "error", err)
+		}
+	}
 
 	ptc := &GetTaskController{
 		proverTasks: make(map[message.ProofType]provertask.ProverTask),
+		scheduler:   provertask.NewScheduler(cfg.ProverManager.Scheduler),
+		chunkOrm:    orm.NewChunk(db),
+		batchOrm:    orm.NewBatch(db),
+		ha:          cfg.HA,
+		leaseOrm:    orm.NewLease(db),
+		drain:       drain,
+		taskKey:     taskKey,
 		getTaskAccessCounter: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 			Name: "coordinator_get_task_access_count",
 			Help: "Multi dimensions get task counter.",
@@ -43,9 +90,28 @@ func NewGetTaskController(cfg *config.Config, chainCfg *params.ChainConfig, db *
 	ptc.proverTasks[message.ProofTypeChunk] = chunkProverTask
 	ptc.proverTasks[message.ProofTypeBatch] = batchProverTask
 
+	promauto.With(reg).NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "coordinator_active_prover_sessions",
+		Help: "The number of provers which requested a task recently.",
+	}, ptc.activeProverSessions)
+
 	return ptc
 }
 
+// activeProverSessions counts the provers seen within the session window and forgets the others.
+func (ptc *GetTaskController) activeProverSessions() float64 {
+	var active int
+	ptc.proverLastSeen.Range(func(key, value interface{}) bool {
+		if time.Since(value.(time.Time)) > activeProverSessionWindow {
+			ptc.proverLastSeen.Delete(key)
+		} else {
+			active++
+		}
+		return true
+	})
+	return float64(active)
+}
+
 func (ptc *GetTaskController) incGetTaskAccessCounter(ctx *gin.Context) error {
 	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
 	if !publicKeyExist {
@@ -60,6 +126,7 @@ func (ptc *GetTaskController) incGetTaskAccessCounter(ctx *gin.Context) error {
 		return fmt.Errorf("get prover version from context failed")
 	}
 
+	ptc.proverLastSeen.Store(publicKey.(string), time.Now())
 	ptc.getTaskAccessCounter.With(prometheus.Labels{
 		coordinatorType.LabelProverPublicKey: publicKey.(string),
 		coordinatorType.LabelProverName:      proverName.(string),
@@ -77,47 +144,147 @@ func (ptc *GetTaskController) GetTasks(ctx *gin.Context) {
 		return
 	}
 
-	proofType := ptc.proofType(&getTaskParameter)
-	proverTask, isExist := ptc.proverTasks[proofType]
-	if !isExist {
-		nerr := fmt.Errorf("parameter wrong proof type:%v", proofType)
-		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+	result, errCode, err := ptc.AssignTask(ctx, &getTaskParameter)
+	if err != nil {
+		types.RenderFailure(ctx, errCode, err)
 		return
 	}
 
+	types.RenderSuccess(ctx, result)
+}
+
+// AssignTask assigns a chunk/batch task to the prover whose identity is stored in ctx.
+// It is shared by the http and grpc transports, the returned int is the errno of the failure.
+func (ptc *GetTaskController) AssignTask(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, int, error) {
+	if ptc.drain.Draining() {
+		return nil, types.ErrCoordinatorShuttingDown, fmt.Errorf("coordinator is shutting down, no task is assigned")
+	}
+
+	prover := provertask.NewAssignmentProver(ctx)
+	proofTypes := provertask.PrioritizeProofTypes(prover, ptc.proofTypes(ctx, getTaskParameter))
+	if len(proofTypes) == 0 {
+		return nil, types.ErrCoordinatorEmptyProofData, fmt.Errorf("no proof type is left for the prover by the assignment hooks")
+	}
+	for _, proofType := range proofTypes {
+		if _, isExist := ptc.proverTasks[proofType]; !isExist {
+			return nil, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter wrong proof type:%v", proofType)
+		}
+	}
+
 	if err := ptc.incGetTaskAccessCounter(ctx); err != nil {
 		log.Warn("get_task access counter inc failed", "error", err.Error())
 	}
 
-	result, err := proverTask.Assign(ctx, &getTaskParameter)
-	if err != nil {
-		nerr := fmt.Errorf("return prover task err:%w", err)
-		types.RenderFailure(ctx, types.ErrCoordinatorGetTaskFailure, nerr)
-		return
+	proofTypes = ptc.eligibleProofTypes(prover, proofTypes)
+	if len(proofTypes) == 0 {
+		return nil, types.ErrCoordinatorEmptyProofData, fmt.Errorf("prover hardware doesn't meet the resource requirements of the requested proof type, or the prover doesn't take it")
 	}
 
-	if result == nil {
-		nerr := fmt.Errorf("get empty prover task")
-		types.RenderFailure(ctx, types.ErrCoordinatorEmptyProofData, nerr)
-		return
+	if ptc.ha != nil {
+		// the prover session lease keeps two replicas from assigning tasks to the same prover at the same time.
+		publicKey := ctx.GetString(coordinatorType.PublicKey)
+		leaseName := proverSessionLeasePrefix + publicKey
+		acquired, err := ptc.leaseOrm.TryAcquire(ctx.Copy(), leaseName, ptc.ha.InstanceID, time.Duration(ptc.ha.LeaseDurationSec)*time.Second)
+		if err != nil {
+			return nil, types.ErrCoordinatorGetTaskFailure, fmt.Errorf("acquire prover session lease failed, err:%w", err)
+		}
+		if !acquired {
+			return nil, types.ErrCoordinatorGetTaskFailure, fmt.Errorf("prover with publicKey %s is being assigned a task by another coordinator", publicKey)
+		}
+		defer func() {
+			if err := ptc.leaseOrm.Release(ctx.Copy(), leaseName, ptc.ha.InstanceID); err != nil {
+				log.Warn("failed to release prover session lease", "public key", publicKey, "error", err)
+			}
+		}()
 	}
 
-	types.RenderSuccess(ctx, result)
+	// try the proof types in order, falling back to the next one if there is no task of this type.
+	for _, proofType := range proofTypes {
+		result, err := ptc.proverTasks[proofType].Assign(ctx, getTaskParameter)
+		if err != nil {
+			return nil, types.ErrCoordinatorGetTaskFailure, fmt.Errorf("return prover task err:%w", err)
+		}
+
+		if result != nil {
+			if err = ptc.signTask(result, ctx.GetString(coordinatorType.PublicKey)); err != nil {
+				return nil, types.ErrCoordinatorGetTaskFailure, fmt.Errorf("sign prover task err:%w", err)
+			}
+			ptc.scheduler.MarkAssigned(proofType)
+			ptc.drain.assigned(result.TaskType, result.TaskID, ctx.GetString(coordinatorType.PublicKey))
+			return result, types.Success, nil
+		}
+	}
+
+	return nil, types.ErrCoordinatorEmptyProofData, fmt.Errorf("get empty prover task")
+}
+
+// signTask signs the task assigned to the prover if the task signing is enabled, the task assigned to another
+// prover or tampered with by a proxy doesn't match the signature.
+func (ptc *GetTaskController) signTask(task *coordinatorType.GetTaskSchema, publicKey string) error {
+	if ptc.taskKey == nil {
+		return nil
+	}
+	assignment := &message.TaskAssignment{
+		UUID:            task.UUID,
+		TaskID:          task.TaskID,
+		TaskType:        message.ProofType(task.TaskType),
+		TaskDataHash:    crypto.Keccak256Hash([]byte(task.TaskData)),
+		HardForkName:    task.HardForkName,
+		TaskHeight:      task.TaskHeight,
+		ProverPublicKey: publicKey,
+	}
+	signature, err := assignment.Sign(ptc.taskKey)
+	if err != nil {
+		return err
+	}
+	task.Signature = signature
+	return nil
 }
 
-func (ptc *GetTaskController) proofType(para *coordinatorType.GetTaskParameter) message.ProofType {
+// proofTypes returns the requested proof type, or all proof types in scheduling order if none is requested.
+func (ptc *GetTaskController) proofTypes(ctx *gin.Context, para *coordinatorType.GetTaskParameter) []message.ProofType {
 	proofType := message.ProofType(para.TaskType)
+	if proofType != message.ProofTypeUndefined {
+		return []message.ProofType{proofType}
+	}
+	return ptc.scheduler.Order(ptc.topPriorities(ctx))
+}
+
+// topPriorities returns the highest priority of the unassigned tasks of every proof type, as read within the
+// current scheduler tick. A proof type whose priority can't be read is left out, it's still tried after the others.
+func (ptc *GetTaskController) topPriorities(ctx *gin.Context) map[message.ProofType]message.TaskPriority {
+	ptc.topPrioritiesMu.Lock()
+	defer ptc.topPrioritiesMu.Unlock()
+	if ptc.topPrioritiesCache != nil && time.Since(ptc.topPrioritiesAt) < schedulerTick {
+		return ptc.topPrioritiesCache
+	}
 
-	proofTypes := []message.ProofType{
-		message.ProofTypeChunk,
-		message.ProofTypeBatch,
+	topPriorities := make(map[message.ProofType]message.TaskPriority)
+	if priority, ok, err := ptc.chunkOrm.GetTopUnassignedPriority(ctx.Copy()); err != nil {
+		log.Warn("failed to get top priority of the chunk tasks", "error", err)
+	} else if ok {
+		topPriorities[message.ProofTypeChunk] = priority
 	}
+	if priority, ok, err := ptc.batchOrm.GetTopUnassignedPriority(ctx.Copy()); err != nil {
+		log.Warn("failed to get top priority of the batch tasks", "error", err)
+	} else if ok {
+		topPriorities[message.ProofTypeBatch] = priority
+	}
+	ptc.topPrioritiesCache, ptc.topPrioritiesAt = topPriorities, time.Now()
+	return topPriorities
+}
 
-	if proofType == message.ProofTypeUndefined {
-		rand.Shuffle(len(proofTypes), func(i, j int) {
-			proofTypes[i], proofTypes[j] = proofTypes[j], proofTypes[i]
-		})
-		proofType = proofTypes[0]
+// eligibleProofTypes filters out the proof types whose resource requirements the prover hardware doesn't meet,
+// and the ones the prover set a zero concurrency limit for.
+func (ptc *GetTaskController) eligibleProofTypes(prover *provertask.AssignmentProver, proofTypes []message.ProofType) []message.ProofType {
+	eligible := make([]message.ProofType, 0, len(proofTypes))
+	for _, proofType := range proofTypes {
+		if limit := prover.MaxConcurrentTasks.Limit(proofType); limit != nil && *limit == 0 {
+			continue
+		}
+		if ptc.scheduler.IsEligible(proofType, prover.Hardware) {
+			eligible = append(eligible, proofType)
+		}
 	}
-	return proofType
+	return eligible
 }
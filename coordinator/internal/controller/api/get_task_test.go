@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+func TestGetTaskControllerSignTask(t *testing.T) {
+	task := &coordinatorType.GetTaskSchema{
+		UUID:         "c3d8ad2e-0c3c-4b48-9a3c-7c6b0bbd7d5e",
+		TaskID:       "chunk-0",
+		TaskType:     int(message.ProofTypeChunk),
+		TaskData:     `{"block_hashes":["0x01"]}`,
+		HardForkName: "darwin",
+		TaskHeight:   100,
+	}
+
+	// the tasks aren't signed without a key.
+	ptc := &GetTaskController{}
+	assert.NoError(t, ptc.signTask(task, "prover-0"))
+	assert.Empty(t, task.Signature)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	ptc = &GetTaskController{taskKey: key}
+	assert.NoError(t, ptc.signTask(task, "prover-0"))
+	assert.NotEmpty(t, task.Signature)
+
+	assignment := &message.TaskAssignment{
+		UUID:            task.UUID,
+		TaskID:          task.TaskID,
+		TaskType:        message.ProofTypeChunk,
+		TaskDataHash:    crypto.Keccak256Hash([]byte(task.TaskData)),
+		HardForkName:    task.HardForkName,
+		TaskHeight:      task.TaskHeight,
+		ProverPublicKey: "prover-0",
+	}
+	signer, err := assignment.Signer(task.Signature)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+
+	// the task data tampered with by a proxy doesn't match the signature.
+	assignment.TaskDataHash = crypto.Keccak256Hash([]byte(`{"block_hashes":["0x02"]}`))
+	signer, err = assignment.Signer(task.Signature)
+	assert.NoError(t, err)
+	assert.NotEqual(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// ProofUploadController the proof upload api controller, a prover on an unreliable network uploads its proof in
+// parts addressed by their hash, resumes an interrupted upload with the parts not received yet, and submits the
+// proof with the list of its parts.
+type ProofUploadController struct {
+	cfg                *config.ProofUploadConfig
+	proverTaskOrm      *orm.ProverTask
+	proofUploadPartOrm *orm.ProofUploadPart
+
+	proofUploadPartTotal prometheus.Counter
+}
+
+// NewProofUploadController create the proof upload api controller instance
+func NewProofUploadController(cfg *config.ProofUploadConfig, db *gorm.DB, reg prometheus.Registerer) *ProofUploadController {
+	return &ProofUploadController{
+		cfg:                cfg,
+		proverTaskOrm:      orm.NewProverTask(db),
+		proofUploadPartOrm: orm.NewProofUploadPart(db),
+		proofUploadPartTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_proof_upload_part_total",
+			Help: "Total number of proof parts uploaded by the provers.",
+		}),
+	}
+}
+
+// UploadProofPart prover uploads a part of the proof of its assigned task
+func (pc *ProofUploadController) UploadProofPart(ctx *gin.Context) {
+	// the part is base64 encoded in the json body.
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, int64(pc.cfg.MaxPartSize)*2)
+
+	var upp coordinatorType.UploadProofPartParameter
+	if err := ctx.ShouldBind(&upp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+	if len(upp.Data) > pc.cfg.MaxPartSize {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, the part is larger than %d bytes", pc.cfg.MaxPartSize))
+		return
+	}
+	if partHash := crypto.Keccak256Hash(upp.Data).Hex(); !strings.EqualFold(partHash, upp.PartHash) {
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, part hash mismatch, expected:%s", partHash))
+		return
+	}
+
+	partHashes, err := pc.uploadedPartHashes(ctx, upp.UUID)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorProofUploadFailure, err)
+		return
+	}
+	partHash := strings.ToLower(upp.PartHash)
+	uploaded := false
+	for _, h := range partHashes {
+		if h == partHash {
+			uploaded = true
+			break
+		}
+	}
+	if !uploaded {
+		if len(partHashes) >= pc.cfg.MaxParts {
+			types.RenderFailure(ctx, types.ErrCoordinatorProofUploadFailure, fmt.Errorf("proof upload failure, the proof has more than %d parts", pc.cfg.MaxParts))
+			return
+		}
+		if err := pc.proofUploadPartOrm.InsertProofUploadPart(ctx.Copy(), upp.UUID, partHash, upp.Data); err != nil {
+			types.RenderFailure(ctx, types.ErrCoordinatorProofUploadFailure, fmt.Errorf("proof upload failure, err:%w", err))
+			return
+		}
+		partHashes = append(partHashes, partHash)
+		pc.proofUploadPartTotal.Inc()
+	}
+	types.RenderSuccess(ctx, coordinatorType.ProofUploadSchema{PartHashes: partHashes})
+}
+
+// GetProofUpload returns the parts of the proof of the prover task received so far, to resume an interrupted upload
+func (pc *ProofUploadController) GetProofUpload(ctx *gin.Context) {
+	var pup coordinatorType.ProofUploadParameter
+	if err := ctx.ShouldBind(&pup); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+
+	partHashes, err := pc.uploadedPartHashes(ctx, pup.UUID)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrCoordinatorProofUploadFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, coordinatorType.ProofUploadSchema{PartHashes: partHashes})
+}
+
+// uploadedPartHashes returns the parts uploaded for the prover task, which must be assigned to the prover.
+func (pc *ProofUploadController) uploadedPartHashes(ctx *gin.Context, uuid string) ([]string, error) {
+	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
+	if !publicKeyExist {
+		return nil, errors.New("get public key from context failed")
+	}
+	proverTask, err := pc.proverTaskOrm.GetProverTaskByUUIDAndPublicKey(ctx.Copy(), uuid, publicKey.(string))
+	if err != nil {
+		return nil, fmt.Errorf("proof upload failure, no task of uuid:%s, err:%w", uuid, err)
+	}
+	if types.ProverProveStatus(proverTask.ProvingStatus) != types.ProverAssigned {
+		return nil, fmt.Errorf("proof upload failure, the task of uuid:%s is not assigned, proving status:%s", uuid, types.ProverProveStatus(proverTask.ProvingStatus))
+	}
+
+	partHashes, err := pc.proofUploadPartOrm.GetProofUploadPartHashes(ctx.Copy(), uuid)
+	if err != nil {
+		return nil, fmt.Errorf("proof upload failure, err:%w", err)
+	}
+	return partHashes, nil
+}
+
+// assembleProof concatenates the uploaded parts of the proof of the prover task in the order of partHashes.
+func assembleProof(ctx *gin.Context, proofUploadPartOrm *orm.ProofUploadPart, uuid string, partHashes []string) (string, error) {
+	lowerPartHashes := make([]string, len(partHashes))
+	for i, partHash := range partHashes {
+		lowerPartHashes[i] = strings.ToLower(partHash)
+	}
+	parts, err := proofUploadPartOrm.GetProofUploadParts(ctx.Copy(), uuid, lowerPartHashes)
+	if err != nil {
+		return "", err
+	}
+
+	var proof strings.Builder
+	for _, partHash := range lowerPartHashes {
+		data, ok := parts[partHash]
+		if !ok {
+			return "", fmt.Errorf("part %s of the proof is not uploaded", partHash)
+		}
+		proof.Write(data)
+	}
+	return proof.String(), nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/orm"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// ReportProgressController the prover progress report and task cancellation api controller
+type ReportProgressController struct {
+	chunkOrm      *orm.Chunk
+	batchOrm      *orm.Batch
+	proverTaskOrm *orm.ProverTask
+	drain         *Drainer
+
+	proverTaskRecoveredTotal prometheus.Counter
+	proverTaskCancelledTotal prometheus.Counter
+}
+
+// NewReportProgressController create the report progress api controller instance
+func NewReportProgressController(db *gorm.DB, drain *Drainer, reg prometheus.Registerer) *ReportProgressController {
+	return &ReportProgressController{
+		chunkOrm:      orm.NewChunk(db),
+		batchOrm:      orm.NewBatch(db),
+		proverTaskOrm: orm.NewProverTask(db),
+		drain:         drain,
+		proverTaskRecoveredTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_prover_task_recovered_total",
+			Help: "Total number of prover tasks resumed by the provers after a restart.",
+		}),
+		proverTaskCancelledTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_prover_task_cancelled_total",
+			Help: "Total number of prover tasks aborted by the provers after they were cancelled.",
+		}),
+	}
+}
+
+// ReportProgress prover reports the progress of its assigned task
+func (rc *ReportProgressController) ReportProgress(ctx *gin.Context) {
+	var rpp coordinatorType.ReportProgressParameter
+	if err := ctx.ShouldBind(&rpp); err != nil {
+		nerr := fmt.Errorf("parameter invalid, err:%w", err)
+		types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
+		return
+	}
+
+	if errCode, err := rc.HandleReportProgress(ctx, rpp); err != nil {
+		types.RenderFailure(ctx, errCode, err)
+		return
+	}
+
+	publicKey, _ := ctx.Get(coordinatorType.PublicKey)
+	cancelTasks, err := rc.CancelledTasks(ctx.Copy(), publicKey.(string))
+	if err != nil {
+		// the progress is recorded, the cancellations are sent with the next report.
+		log.Warn("failed to get the cancelled tasks of the prover", "public key", publicKey, "err", err)
+	}
+	types.RenderSuccess(ctx, coordinatorType.ReportProgressSchema{CancelTasks: cancelTasks})
+}
+
+// HandleReportProgress validates and persists the progress reported by the prover whose identity is stored in ctx.
+// It is shared by the http and grpc transports, the returned int is the errno of the failure.
+func (rc *ReportProgressController) HandleReportProgress(ctx *gin.Context, rpp coordinatorType.ReportProgressParameter) (int, error) {
+	if rpp.Stage < 0 || rpp.Stage > 255 || rpp.Percent < 0 || rpp.Percent > 255 {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, stage:%d percent:%d", rpp.Stage, rpp.Percent)
+	}
+	progressMsg := message.ProgressMsg{
+		UUID:      rpp.UUID,
+		ID:        rpp.TaskID,
+		Type:      message.ProofType(rpp.TaskType),
+		Stage:     message.ProvingStage(rpp.Stage),
+		Percent:   uint8(rpp.Percent),
+		Recovered: rpp.Recovered,
+	}
+	if err := progressMsg.Validate(); err != nil {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err)
+	}
+
+	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
+	if !publicKeyExist {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("get public key from context failed")
+	}
+
+	updated, err := rc.proverTaskOrm.UpdateProverTaskProgress(ctx.Copy(), progressMsg.UUID, publicKey.(string), progressMsg.Stage, progressMsg.Percent)
+	if err != nil {
+		return types.ErrCoordinatorReportProgressFailure, fmt.Errorf("report progress failure, err:%w", err)
+	}
+	if !updated {
+		// a recovering prover drops the task, it has timed out or been finished meanwhile.
+		return types.ErrCoordinatorReportProgressFailure, fmt.Errorf("report progress failure, no assigned task of uuid:%s", progressMsg.UUID)
+	}
+
+	if progressMsg.Recovered {
+		rc.proverTaskRecoveredTotal.Inc()
+		log.Info("prover resumed its task after a restart", "uuid", progressMsg.UUID, "task_id", progressMsg.ID, "public key", publicKey)
+	}
+	return types.Success, nil
+}
@@ -6,27 +6,41 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/logic/submitproof"
 	"scroll-tech/coordinator/internal/logic/verifier"
+	"scroll-tech/coordinator/internal/orm"
 	coordinatorType "scroll-tech/coordinator/internal/types"
 )
 
 // SubmitProofController the submit proof api controller
 type SubmitProofController struct {
 	submitProofReceiverLogic *submitproof.ProofReceiverLogic
+	drain                    *Drainer
+
+	// proofUpload and proofUploadPartOrm are nil if the proof upload in parts is disabled.
+	proofUpload        *config.ProofUploadConfig
+	proofUploadPartOrm *orm.ProofUploadPart
 }
 
 // NewSubmitProofController create the submit proof api controller instance
-func NewSubmitProofController(cfg *config.Config, db *gorm.DB, vf *verifier.Verifier, reg prometheus.Registerer) *SubmitProofController {
-	return &SubmitProofController{
-		submitProofReceiverLogic: submitproof.NewSubmitProofReceiverLogic(cfg.ProverManager, db, vf, reg),
+func NewSubmitProofController(cfg *config.Config, db *gorm.DB, proofStore objectstore.Store, vf *verifier.Verifier, drain *Drainer, reg prometheus.Registerer) *SubmitProofController {
+	spc := &SubmitProofController{
+		submitProofReceiverLogic: submitproof.NewSubmitProofReceiverLogic(cfg, db, proofStore, vf, reg),
+		drain:                    drain,
+	}
+	if cfg.ProverManager.ProofUpload != nil {
+		spc.proofUpload = cfg.ProverManager.ProofUpload
+		spc.proofUploadPartOrm = orm.NewProofUploadPart(db)
 	}
+	return spc
 }
 
 // SubmitProof prover submit the proof to coordinator
@@ -38,6 +52,22 @@ func (spc *SubmitProofController) SubmitProof(ctx *gin.Context) {
 		return
 	}
 
+	if errCode, err := spc.HandleSubmitProof(ctx, spp); err != nil {
+		types.RenderFailure(ctx, errCode, err)
+		return
+	}
+	types.RenderSuccess(ctx, nil)
+}
+
+// HandleSubmitProof decodes and handles the proof submitted by the prover whose identity is stored in ctx.
+// It is shared by the http and grpc transports, the returned int is the errno of the failure.
+func (spc *SubmitProofController) HandleSubmitProof(ctx *gin.Context, spp coordinatorType.SubmitProofParameter) (int, error) {
+	if spp.Status == int(message.StatusOk) && len(spp.ProofParts) > 0 {
+		if errCode, err := spc.assembleUploadedProof(ctx, &spp); err != nil {
+			return errCode, err
+		}
+	}
+
 	proofMsg := message.ProofMsg{
 		ProofDetail: &message.ProofDetail{
 			ID:     spp.TaskID,
@@ -45,32 +75,61 @@ func (spc *SubmitProofController) SubmitProof(ctx *gin.Context) {
 			Status: message.RespStatus(spp.Status),
 		},
 	}
+	if spp.Status != int(message.StatusOk) {
+		proofMsg.Error = spp.FailureMsg
+		proofMsg.ErrorCode = message.ProofErrorCode(spp.ErrorCode)
+		proofMsg.ErrorDetails = spp.ErrorDetails
+	}
 
 	if spp.Status == int(message.StatusOk) {
 		switch message.ProofType(spp.TaskType) {
 		case message.ProofTypeChunk:
 			var tmpChunkProof message.ChunkProof
 			if err := json.Unmarshal([]byte(spp.Proof), &tmpChunkProof); err != nil {
-				nerr := fmt.Errorf("unmarshal parameter chunk proof invalid, err:%w", err)
-				types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
-				return
+				return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("unmarshal parameter chunk proof invalid, err:%w", err)
 			}
 			proofMsg.ChunkProof = &tmpChunkProof
 		case message.ProofTypeBatch:
 			var tmpBatchProof message.BatchProof
 			if err := json.Unmarshal([]byte(spp.Proof), &tmpBatchProof); err != nil {
-				nerr := fmt.Errorf("unmarshal parameter batch proof invalid, err:%w", err)
-				types.RenderFailure(ctx, types.ErrCoordinatorParameterInvalidNo, nerr)
-				return
+				return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("unmarshal parameter batch proof invalid, err:%w", err)
 			}
 			proofMsg.BatchProof = &tmpBatchProof
 		}
 	}
 
+	// the prover is done with the task whether its proof is accepted or not.
+	defer spc.drain.submitted(spp.TaskType, spp.TaskID, ctx.GetString(coordinatorType.PublicKey))
+
 	if err := spc.submitProofReceiverLogic.HandleZkProof(ctx, &proofMsg, spp); err != nil {
-		nerr := fmt.Errorf("handle zk proof failure, err:%w", err)
-		types.RenderFailure(ctx, types.ErrCoordinatorHandleZkProofFailure, nerr)
-		return
+		return types.ErrCoordinatorHandleZkProofFailure, fmt.Errorf("handle zk proof failure, err:%w", err)
 	}
-	types.RenderSuccess(ctx, nil)
+
+	// the parts of a failed submission are kept for a retry until they expire.
+	if len(spp.ProofParts) > 0 {
+		if err := spc.proofUploadPartOrm.DeleteProofUploadParts(ctx.Copy(), spp.UUID); err != nil {
+			log.Warn("failed to delete the uploaded proof parts", "uuid", spp.UUID, "err", err)
+		}
+	}
+	return types.Success, nil
+}
+
+// assembleUploadedProof sets the proof of a submission listing the parts of its proof uploaded beforehand.
+func (spc *SubmitProofController) assembleUploadedProof(ctx *gin.Context, spp *coordinatorType.SubmitProofParameter) (int, error) {
+	if spc.proofUploadPartOrm == nil {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, the proof upload is disabled")
+	}
+	if spp.UUID == "" {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, the uuid is required with proof_parts")
+	}
+	if len(spp.ProofParts) > spc.proofUpload.MaxParts {
+		return types.ErrCoordinatorParameterInvalidNo, fmt.Errorf("parameter invalid, the proof has more than %d parts", spc.proofUpload.MaxParts)
+	}
+
+	proof, err := assembleProof(ctx, spc.proofUploadPartOrm, spp.UUID, spp.ProofParts)
+	if err != nil {
+		return types.ErrCoordinatorProofUploadFailure, fmt.Errorf("proof upload failure, err:%w", err)
+	}
+	spp.Proof = proof
+	return types.Success, nil
 }
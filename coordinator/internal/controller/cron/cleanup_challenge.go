@@ -21,6 +21,9 @@ func (c *Collector) cleanupChallenge() {
 	for {
 		select {
 		case <-ticker.C:
+			if !c.isLeader() {
+				break
+			}
 			expiredTime := utils.NowUTC().Add(-time.Hour)
 			if err := c.challenge.DeleteExpireChallenge(c.ctx, expiredTime); err != nil {
 				log.Error("delete expired challenge failure", "error", err)
@@ -0,0 +1,42 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/utils"
+)
+
+// cleanupProofUpload deletes the parts of the proofs uploaded but never submitted once they expire.
+func (c *Collector) cleanupProofUpload() {
+	defer func() {
+		if err := recover(); err != nil {
+			nerr := fmt.Errorf("clean proof upload panic error: %v", err)
+			log.Warn(nerr.Error())
+		}
+	}()
+
+	ticker := time.NewTicker(time.Minute * 10)
+	for {
+		select {
+		case <-ticker.C:
+			if !c.isLeader() {
+				break
+			}
+			expiredTime := utils.NowUTC().Add(-time.Duration(c.cfg.ProverManager.ProofUpload.ExpirySec) * time.Second)
+			if err := c.proofUploadPartOrm.DeleteExpiredProofUploadParts(c.ctx, expiredTime); err != nil {
+				log.Error("delete expired proof upload parts failure", "error", err)
+			}
+		case <-c.ctx.Done():
+			if c.ctx.Err() != nil {
+				log.Error("manager context canceled with error", "error", c.ctx.Err())
+			}
+			return
+		case <-c.stopCleanProofUploadChan:
+			log.Info("the coordinator cleanupProofUpload run loop exit")
+			return
+		}
+	}
+}
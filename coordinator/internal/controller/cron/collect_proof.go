@@ -17,6 +17,9 @@ import (
 	"scroll-tech/coordinator/internal/orm"
 )
 
+// cronLeaseName is the lease held by the coordinator cron replica running the jobs.
+const cronLeaseName = "coordinator_cron"
+
 // Collector collect the block batch or agg task to send to prover
 type Collector struct {
 	cfg *config.Config
@@ -27,11 +30,16 @@ type Collector struct {
 	stopBatchTimeoutChan       chan struct{}
 	stopBatchAllChunkReadyChan chan struct{}
 	stopCleanChallengeChan     chan struct{}
+	stopCleanProofUploadChan   chan struct{}
+
+	proverTaskOrm  *orm.ProverTask
+	proverScoreOrm *orm.ProverScore
+	chunkOrm       *orm.Chunk
+	batchOrm       *orm.Batch
+	challenge      *orm.Challenge
+	leaseOrm       *orm.Lease
 
-	proverTaskOrm *orm.ProverTask
-	chunkOrm      *orm.Chunk
-	batchOrm      *orm.Batch
-	challenge     *orm.Challenge
+	proofUploadPartOrm *orm.ProofUploadPart
 
 	timeoutBatchCheckerRunTotal     prometheus.Counter
 	batchProverTaskTimeoutTotal     prometheus.Counter
@@ -50,10 +58,14 @@ func NewCollector(ctx context.Context, db *gorm.DB, cfg *config.Config, reg prom
 		stopBatchTimeoutChan:       make(chan struct{}),
 		stopBatchAllChunkReadyChan: make(chan struct{}),
 		stopCleanChallengeChan:     make(chan struct{}),
+		stopCleanProofUploadChan:   make(chan struct{}),
 		proverTaskOrm:              orm.NewProverTask(db),
+		proverScoreOrm:             orm.NewProverScore(db),
 		chunkOrm:                   orm.NewChunk(db),
 		batchOrm:                   orm.NewBatch(db),
 		challenge:                  orm.NewChallenge(db),
+		leaseOrm:                   orm.NewLease(db),
+		proofUploadPartOrm:         orm.NewProofUploadPart(db),
 
 		timeoutBatchCheckerRunTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_batch_timeout_checker_run_total",
@@ -81,6 +93,9 @@ func NewCollector(ctx context.Context, db *gorm.DB, cfg *config.Config, reg prom
 	go c.timeoutChunkProofTask()
 	go c.checkBatchAllChunkReady()
 	go c.cleanupChallenge()
+	if cfg.ProverManager.ProofUpload != nil {
+		go c.cleanupProofUpload()
+	}
 
 	log.Info("Start coordinator cron successfully.")
 
@@ -93,6 +108,24 @@ func (c *Collector) Stop() {
 	c.stopBatchTimeoutChan <- struct{}{}
 	c.stopBatchAllChunkReadyChan <- struct{}{}
 	c.stopCleanChallengeChan <- struct{}{}
+	if c.cfg.ProverManager.ProofUpload != nil {
+		c.stopCleanProofUploadChan <- struct{}{}
+	}
+}
+
+// isLeader tells whether this replica runs the cron jobs, with HA the replica holding the
+// cron lease does, and keeps renewing it, the others stand by until the lease expires.
+func (c *Collector) isLeader() bool {
+	if c.cfg.HA == nil {
+		return true
+	}
+
+	acquired, err := c.leaseOrm.TryAcquire(c.ctx, cronLeaseName, c.cfg.HA.InstanceID, time.Duration(c.cfg.HA.LeaseDurationSec)*time.Second)
+	if err != nil {
+		log.Error("acquire coordinator cron lease failure", "error", err)
+		return false
+	}
+	return acquired
 }
 
 // timeoutBatchProofTask cron check the send task is timeout. if timeout reached, restore the
@@ -109,6 +142,9 @@ func (c *Collector) timeoutBatchProofTask() {
 	for {
 		select {
 		case <-ticker.C:
+			if !c.isLeader() {
+				break
+			}
 			c.timeoutBatchCheckerRunTotal.Inc()
 			timeout := time.Duration(c.cfg.ProverManager.BatchCollectionTimeSec) * time.Second
 			assignedProverTasks, err := c.proverTaskOrm.GetTimeoutAssignedProverTasks(c.ctx, 10, message.ProofTypeBatch, timeout)
@@ -141,6 +177,9 @@ func (c *Collector) timeoutChunkProofTask() {
 	for {
 		select {
 		case <-ticker.C:
+			if !c.isLeader() {
+				break
+			}
 			c.timeoutChunkCheckerRunTotal.Inc()
 			timeout := time.Duration(c.cfg.ProverManager.ChunkCollectionTimeSec) * time.Second
 			assignedProverTasks, err := c.proverTaskOrm.GetTimeoutAssignedProverTasks(c.ctx, 10, message.ProofTypeChunk, timeout)
@@ -181,6 +220,11 @@ func (c *Collector) check(assignedProverTasks []orm.ProverTask, timeout promethe
 				return err
 			}
 
+			if err := c.proverScoreOrm.IncreaseFailure(c.ctx, assignedProverTask.ProverPublicKey, assignedProverTask.ProverName, types.ProverTaskFailureTypeTimeout, message.ProofFailureUndefined, tx); err != nil {
+				log.Error("update prover score failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
+				return err
+			}
+
 			switch message.ProofType(assignedProverTask.TaskType) {
 			case message.ProofTypeChunk:
 				if err := c.chunkOrm.DecreaseActiveAttemptsByHash(c.ctx, assignedProverTask.TaskID, tx); err != nil {
@@ -188,6 +232,11 @@ func (c *Collector) check(assignedProverTasks []orm.ProverTask, timeout promethe
 					return err
 				}
 
+				if err := c.chunkOrm.IncreaseFailedAttemptsByHash(c.ctx, assignedProverTask.TaskID, tx); err != nil {
+					log.Error("increase chunk failed attempts failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
+					return err
+				}
+
 				if err := c.chunkOrm.UpdateProvingStatusFailed(c.ctx, assignedProverTask.TaskID, c.cfg.ProverManager.SessionAttempts, tx); err != nil {
 					log.Error("update proving status failed failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
 					return err
@@ -198,6 +247,11 @@ func (c *Collector) check(assignedProverTasks []orm.ProverTask, timeout promethe
 					return err
 				}
 
+				if err := c.batchOrm.IncreaseFailedAttemptsByHash(c.ctx, assignedProverTask.TaskID, tx); err != nil {
+					log.Error("increase batch failed attempts failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
+					return err
+				}
+
 				if err := c.batchOrm.UpdateProvingStatusFailed(c.ctx, assignedProverTask.TaskID, c.cfg.ProverManager.SessionAttempts, tx); err != nil {
 					log.Error("update proving status failed failure", "uuid", assignedProverTask.UUID, "hash", assignedProverTask.TaskID, "pubKey", assignedProverTask.ProverPublicKey, "err", err)
 					return err
@@ -208,6 +262,32 @@ func (c *Collector) check(assignedProverTasks []orm.ProverTask, timeout promethe
 		})
 		if err != nil {
 			log.Error("check task proof is timeout failure", "error", err)
+			continue
+		}
+
+		c.releasePrefetchedTasks(assignedProverTask.ProverPublicKey)
+	}
+}
+
+// releasePrefetchedTasks times out the task prefetched by a prover whose task timed out, the prover is likely
+// gone, so the task is reassigned now instead of waiting for its own collection time.
+func (c *Collector) releasePrefetchedTasks(publicKey string) {
+	if !c.cfg.ProverManager.Prefetch {
+		return
+	}
+
+	prefetchedTasks, err := c.proverTaskOrm.GetAssignedProverTasksByPublicKey(c.ctx, publicKey)
+	if err != nil {
+		log.Error("get prefetched prover tasks failure", "pubKey", publicKey, "err", err)
+		return
+	}
+
+	for _, prefetchedTask := range prefetchedTasks {
+		switch message.ProofType(prefetchedTask.TaskType) {
+		case message.ProofTypeChunk:
+			c.check([]orm.ProverTask{prefetchedTask}, c.chunkProverTaskTimeoutTotal)
+		case message.ProofTypeBatch:
+			c.check([]orm.ProverTask{prefetchedTask}, c.batchProverTaskTimeoutTotal)
 		}
 	}
 }
@@ -224,6 +304,9 @@ func (c *Collector) checkBatchAllChunkReady() {
 	for {
 		select {
 		case <-ticker.C:
+			if !c.isLeader() {
+				break
+			}
 			c.checkBatchAllChunkReadyRunTotal.Inc()
 			page := 1
 			pageSize := 50
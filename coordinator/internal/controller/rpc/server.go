@@ -0,0 +1,333 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/scroll-tech/go-ethereum/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/controller/api"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
+	"scroll-tech/coordinator/internal/middleware"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+const (
+	// maxProofSize is the upper bound of a proof assembled from a SubmitProof upload stream.
+	maxProofSize = 64 * 1024 * 1024
+	// getTaskPollInterval is how often a GetTask stream tries to assign a task to a prover waiting for one.
+	getTaskPollInterval = 2 * time.Second
+)
+
+// TLSConfig the mutual TLS files of the grpc server
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+// coordinatorServer is the service implemented by Server, it's only used to type check the registration.
+type coordinatorServer interface {
+	GetTask(getTaskParameter *coordinatorType.GetTaskParameter, stream grpc.ServerStream) error
+	SubmitProof(stream grpc.ServerStream) error
+	Heartbeat(stream grpc.ServerStream) error
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.v1.Coordinator",
+	HandlerType: (*coordinatorServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetTask",
+			Handler:       getTaskHandler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubmitProof",
+			Handler:       submitProofHandler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Heartbeat",
+			Handler:       heartbeatHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// Server the grpc transport of the coordinator api, it shares the controllers with the http api.
+// Provers authenticate with the jwt token issued by the http /login api, passed as
+// "authorization: Bearer <token>" metadata, on top of the mutual TLS connection.
+type Server struct {
+	jwtMiddleware *jwt.GinJWTMiddleware
+}
+
+// NewServer creates a grpc server with mutual TLS, api.InitController must be called before.
+func NewServer(cfg *config.Config, tlsCfg *TLSConfig) (*grpc.Server, error) {
+	creds, err := loadTLSCredentials(tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{jwtMiddleware: middleware.LoginMiddleware(cfg)}
+	srv := grpc.NewServer(grpc.Creds(creds))
+	srv.RegisterService(&serviceDesc, s)
+	return srv, nil
+}
+
+func loadTLSCredentials(tlsCfg *TLSConfig) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Clean(tlsCfg.CertFile), filepath.Clean(tlsCfg.KeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("load grpc server key pair failure, err:%w", err)
+	}
+
+	caPem, err := os.ReadFile(filepath.Clean(tlsCfg.ClientCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("read grpc client ca failure, err:%w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPem) {
+		return nil, fmt.Errorf("no valid certificate found in grpc client ca file %s", tlsCfg.ClientCAFile)
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    caPool,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// proverContext authenticates the prover and builds the gin context expected by the controllers.
+func (s *Server) proverContext(ctx context.Context) (*gin.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	token, err := s.jwtMiddleware.ParseTokenString(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil || !token.Valid {
+		return nil, status.Error(codes.Unauthenticated, "invalid authorization token")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", nil)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	c := &gin.Context{Request: req}
+
+	requestID := uuid.NewString()
+	if values = md.Get(strings.ToLower(middleware.RequestIDHeader)); len(values) > 0 && values[0] != "" {
+		requestID = values[0]
+	}
+	c.Set(coordinatorType.RequestID, requestID)
+
+	claims := jwt.ExtractClaimsFromToken(token)
+	for _, key := range []string{coordinatorType.PublicKey, coordinatorType.ProverName, coordinatorType.ProverVersion, coordinatorType.HardForkName, coordinatorType.Hardware, coordinatorType.MaxConcurrentTasks} {
+		if value, exist := claims[key]; exist {
+			c.Set(key, value)
+		}
+	}
+	return c, nil
+}
+
+// GetTask streams the chunk/batch tasks assigned to the prover, a task is sent as soon as it's assigned. While the
+// prover can't take a task, e.g. there is no unassigned task or it's busy up to its concurrency limit, the stream
+// tries again every getTaskPollInterval. The other failures are sent once each, and the stream ends with the
+// failures the prover has to handle, e.g. the coordinator shutting down, or when the prover closes it.
+func (s *Server) GetTask(getTaskParameter *coordinatorType.GetTaskParameter, stream grpc.ServerStream) error {
+	c, err := s.proverContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	if err = allow(c, ratelimit.GetTask); err != nil {
+		return stream.SendMsg(&types.Response{ErrCode: types.ErrCoordinatorRateLimited, ErrMsg: err.Error()})
+	}
+
+	var lastErrMsg string
+	for {
+		result, errCode, assignErr := api.GetTask.AssignTask(c, getTaskParameter)
+		switch {
+		case assignErr == nil:
+			lastErrMsg = ""
+			if err = stream.SendMsg(&types.Response{ErrCode: types.Success, Data: result}); err != nil {
+				return err
+			}
+		case errCode == types.ErrCoordinatorShuttingDown || errCode == types.ErrCoordinatorParameterInvalidNo:
+			return stream.SendMsg(&types.Response{ErrCode: errCode, ErrMsg: assignErr.Error()})
+		case errCode != types.ErrCoordinatorEmptyProofData && assignErr.Error() != lastErrMsg:
+			lastErrMsg = assignErr.Error()
+			if err = stream.SendMsg(&types.Response{ErrCode: errCode, ErrMsg: lastErrMsg}); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-time.After(getTaskPollInterval):
+		}
+		// the token is checked again, the stream of an expired login ends.
+		if c, err = s.proverContext(stream.Context()); err != nil {
+			return err
+		}
+	}
+}
+
+// SubmitProof receives a proof uploaded in chunks and replies once the stream is closed by the prover.
+func (s *Server) SubmitProof(stream grpc.ServerStream) error {
+	c, err := s.proverContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	// reject before reading the upload, the rejected proof is never buffered.
+	if err = allow(c, ratelimit.SubmitProof); err != nil {
+		return stream.SendMsg(&types.Response{ErrCode: types.ErrCoordinatorRateLimited, ErrMsg: err.Error()})
+	}
+
+	var spp *coordinatorType.SubmitProofParameter
+	var proof strings.Builder
+	for {
+		var msg coordinatorType.SubmitProofStreamParameter
+		if err = stream.RecvMsg(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		if spp == nil {
+			if msg.Parameter == nil {
+				return status.Error(codes.InvalidArgument, "the first message must carry the submit proof parameter")
+			}
+			spp = msg.Parameter
+		}
+
+		if proof.Len()+len(msg.ProofChunk) > maxProofSize {
+			return status.Errorf(codes.ResourceExhausted, "proof exceeds the max size of %d bytes", maxProofSize)
+		}
+		proof.WriteString(msg.ProofChunk)
+	}
+
+	if spp == nil {
+		return status.Error(codes.InvalidArgument, "empty submit proof stream")
+	}
+	if spp.TaskID == "" || spp.TaskType == 0 {
+		return stream.SendMsg(&types.Response{ErrCode: types.ErrCoordinatorParameterInvalidNo, ErrMsg: "parameter invalid, task_id and task_type are required"})
+	}
+	spp.Proof = proof.String()
+
+	if errCode, handleErr := api.SubmitProof.HandleSubmitProof(c, *spp); handleErr != nil {
+		return stream.SendMsg(&types.Response{ErrCode: errCode, ErrMsg: handleErr.Error()})
+	}
+	return stream.SendMsg(&types.Response{ErrCode: types.Success})
+}
+
+// Heartbeat answers every heartbeat of the prover until the prover closes the stream,
+// the progress carried by a heartbeat is recorded before answering it.
+func (s *Server) Heartbeat(stream grpc.ServerStream) error {
+	c, err := s.proverContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	for {
+		var msg coordinatorType.HeartbeatParameter
+		if err := stream.RecvMsg(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if msg.Progress != nil {
+			if msg.Progress.UUID == "" || msg.Progress.TaskID == "" || msg.Progress.TaskType == 0 {
+				if err := stream.SendMsg(&types.Response{ErrCode: types.ErrCoordinatorParameterInvalidNo, ErrMsg: "parameter invalid, uuid, task_id and task_type are required"}); err != nil {
+					return err
+				}
+				continue
+			}
+			if errCode, handleErr := api.ReportProgress.HandleReportProgress(c, *msg.Progress); handleErr != nil {
+				if err := stream.SendMsg(&types.Response{ErrCode: errCode, ErrMsg: handleErr.Error()}); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if msg.AckCancelTask != nil {
+			if msg.AckCancelTask.UUID == "" || msg.AckCancelTask.TaskID == "" || msg.AckCancelTask.TaskType == 0 {
+				if err := stream.SendMsg(&types.Response{ErrCode: types.ErrCoordinatorParameterInvalidNo, ErrMsg: "parameter invalid, uuid, task_id and task_type are required"}); err != nil {
+					return err
+				}
+				continue
+			}
+			if errCode, handleErr := api.ReportProgress.HandleAckCancelTask(c, *msg.AckCancelTask); handleErr != nil {
+				if err := stream.SendMsg(&types.Response{ErrCode: errCode, ErrMsg: handleErr.Error()}); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		schema := coordinatorType.HeartbeatSchema{Timestamp: time.Now().Unix()}
+		cancelTasks, err := api.ReportProgress.CancelledTasks(c.Copy(), c.GetString(coordinatorType.PublicKey))
+		if err != nil {
+			// the cancellations are sent with the next heartbeat.
+			log.Warn("failed to get the cancelled tasks of the prover", "public key", c.GetString(coordinatorType.PublicKey), "err", err)
+		}
+		schema.CancelTasks = cancelTasks
+		if err := stream.SendMsg(&types.Response{ErrCode: types.Success, Data: schema}); err != nil {
+			return err
+		}
+	}
+}
+
+// allow applies the prover rate limits shared with the http api, if enabled.
+func allow(c *gin.Context, action ratelimit.Action) error {
+	if api.RateLimiter == nil {
+		return nil
+	}
+	return api.RateLimiter.Allow(action, c.GetString(coordinatorType.PublicKey))
+}
+
+func getTaskHandler(srv interface{}, stream grpc.ServerStream) error {
+	in := new(coordinatorType.GetTaskParameter)
+	if err := stream.RecvMsg(in); err != nil {
+		return err
+	}
+	return srv.(coordinatorServer).GetTask(in, stream)
+}
+
+func submitProofHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(coordinatorServer).SubmitProof(stream)
+}
+
+func heartbeatHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(coordinatorServer).Heartbeat(stream)
+}
@@ -1,21 +1,34 @@
 package auth
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/utils"
+
+	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/orm"
 )
 
 // LoginLogic the auth logic
 type LoginLogic struct {
-	challengeOrm *orm.Challenge
+	cfg                *config.Config
+	challengeOrm       *orm.Challenge
+	proverAllowListOrm *orm.ProverAllowList
+	proverSessionOrm   *orm.ProverSession
 }
 
 // NewLoginLogic new a LoginLogic
-func NewLoginLogic(db *gorm.DB) *LoginLogic {
+func NewLoginLogic(cfg *config.Config, db *gorm.DB) *LoginLogic {
 	return &LoginLogic{
-		challengeOrm: orm.NewChallenge(db),
+		cfg:                cfg,
+		challengeOrm:       orm.NewChallenge(db),
+		proverAllowListOrm: orm.NewProverAllowList(db),
+		proverSessionOrm:   orm.NewProverSession(db),
 	}
 }
 
@@ -23,3 +36,39 @@ func NewLoginLogic(db *gorm.DB) *LoginLogic {
 func (l *LoginLogic) InsertChallengeString(ctx *gin.Context, challenge string) error {
 	return l.challengeOrm.InsertChallenge(ctx.Copy(), challenge)
 }
+
+// CheckProverVersion checks the prover version against the version policy, see CheckProverVersion.
+func (l *LoginLogic) CheckProverVersion(proverVersion string) (string, error) {
+	return CheckProverVersion(l.cfg.ProverManager.VersionPolicy, proverVersion)
+}
+
+// CheckProverAllowed rejects the public keys not in the prover allow list, if the allow list is required.
+func (l *LoginLogic) CheckProverAllowed(ctx *gin.Context, publicKey string) error {
+	if !l.cfg.Auth.RequireProverAllowList {
+		return nil
+	}
+	allowed, err := l.proverAllowListOrm.IsPublicKeyAllowed(ctx.Copy(), publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to check whether the public key %s is allowed, err: %w", publicKey, err)
+	}
+	if !allowed {
+		return fmt.Errorf("public key %s is not in the prover allow list", publicKey)
+	}
+	return nil
+}
+
+// RecordSession records the login of the prover, listed by the admin api until its token expires.
+// It's best effort, a failure doesn't fail the login.
+func (l *LoginLogic) RecordSession(ctx *gin.Context, publicKey, proverName, proverVersion string) {
+	now := utils.NowUTC()
+	session := &orm.ProverSession{
+		PublicKey:     publicKey,
+		ProverName:    proverName,
+		ProverVersion: proverVersion,
+		LoggedInAt:    now,
+		ExpiresAt:     now.Add(time.Duration(l.cfg.Auth.LoginExpireDurationSec) * time.Second),
+	}
+	if err := l.proverSessionOrm.UpsertProverSession(ctx.Copy(), session); err != nil {
+		log.Warn("failed to record prover session", "public key", publicKey, "prover name", proverName, "error", err)
+	}
+}
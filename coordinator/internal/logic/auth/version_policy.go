@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"scroll-tech/common/version"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+// CheckProverVersion checks the prover version against the version policy. The provers below the
+// deprecated version get a warning until the deprecation deadline, and are rejected after it.
+func CheckProverVersion(policy *config.VersionPolicy, proverVersion string) (string, error) {
+	if policy == nil {
+		return "", nil
+	}
+
+	if policy.MaxProverVersion != "" && !version.CheckScrollRepoMaxVersion(proverVersion, policy.MaxProverVersion) {
+		return "", fmt.Errorf("incompatible prover version. please downgrade your prover, maximum allowed version: %s, actual version: %s", policy.MaxProverVersion, proverVersion)
+	}
+
+	if len(policy.CircuitVersions) > 0 {
+		circuitVersion := version.ScrollProverVersion(proverVersion)
+		supported := false
+		for _, v := range policy.CircuitVersions {
+			if circuitVersion == v {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return "", fmt.Errorf("incompatible circuit version. supported circuit versions: %v, actual prover version: %s", policy.CircuitVersions, proverVersion)
+		}
+	}
+
+	if policy.DeprecatedProverVersion == "" || version.CheckScrollRepoVersion(proverVersion, policy.DeprecatedProverVersion) {
+		return "", nil
+	}
+	deadline := time.Unix(policy.DeprecationDeadline, 0)
+	if !time.Now().Before(deadline) {
+		return "", fmt.Errorf("deprecated prover version. please upgrade your prover, minimum allowed version: %s, actual version: %s", policy.DeprecatedProverVersion, proverVersion)
+	}
+	return fmt.Sprintf("prover version %s is deprecated and will be rejected after %s, please upgrade to %s or later",
+		proverVersion, deadline.UTC().Format(time.RFC3339), policy.DeprecatedProverVersion), nil
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+func TestCheckProverVersion(t *testing.T) {
+	warning, err := CheckProverVersion(nil, "v1.0.0-commit-111111-000000")
+	assert.NoError(t, err)
+	assert.Empty(t, warning)
+
+	policy := &config.VersionPolicy{
+		MaxProverVersion:        "v1.2.0",
+		CircuitVersions:         []string{"111111", "222222"},
+		DeprecatedProverVersion: "v1.1.0",
+		DeprecationDeadline:     time.Now().Add(time.Hour).Unix(),
+	}
+
+	warning, err = CheckProverVersion(policy, "v1.1.5-commit-222222-000000")
+	assert.NoError(t, err)
+	assert.Empty(t, warning)
+
+	_, err = CheckProverVersion(policy, "v1.3.0-commit-111111-000000")
+	assert.ErrorContains(t, err, "maximum allowed version")
+
+	_, err = CheckProverVersion(policy, "v1.1.5-commit-333333-000000")
+	assert.ErrorContains(t, err, "incompatible circuit version")
+
+	warning, err = CheckProverVersion(policy, "v1.0.0-commit-111111-000000")
+	assert.NoError(t, err)
+	assert.Contains(t, warning, "deprecated")
+
+	policy.DeprecationDeadline = time.Now().Add(-time.Hour).Unix()
+	_, err = CheckProverVersion(policy, "v1.0.0-commit-111111-000000")
+	assert.ErrorContains(t, err, "deprecated prover version")
+}
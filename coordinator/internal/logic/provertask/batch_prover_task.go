@@ -16,6 +16,7 @@ import (
 	"gorm.io/gorm"
 
 	"scroll-tech/common/forks"
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
@@ -32,10 +33,12 @@ type BatchProverTask struct {
 	batchAttemptsExceedTotal prometheus.Counter
 	batchTaskGetTaskTotal    *prometheus.CounterVec
 	batchTaskGetTaskProver   *prometheus.CounterVec
+	batchProofReusedTotal    prometheus.Counter
+	batchTaskVetoedTotal     prometheus.Counter
 }
 
 // NewBatchProverTask new a batch collector
-func NewBatchProverTask(cfg *config.Config, chainCfg *params.ChainConfig, db *gorm.DB, vkMap map[string]string, reg prometheus.Registerer) *BatchProverTask {
+func NewBatchProverTask(cfg *config.Config, chainCfg *params.ChainConfig, db *gorm.DB, proofStore objectstore.Store, vkMap map[string]string, reg prometheus.Registerer) *BatchProverTask {
 	forkHeights, _, nameForkMap := forks.CollectSortedForkHeights(chainCfg)
 	log.Info("new batch prover task", "forkHeights", forkHeights, "nameForks", nameForkMap)
 
@@ -47,10 +50,12 @@ func NewBatchProverTask(cfg *config.Config, chainCfg *params.ChainConfig, db *go
 			cfg:                cfg,
 			nameForkMap:        nameForkMap,
 			forkHeights:        forkHeights,
-			chunkOrm:           orm.NewChunk(db),
+			chunkOrm:           orm.NewChunk(db).WithProofStore(proofStore),
 			batchOrm:           orm.NewBatch(db),
 			proverTaskOrm:      orm.NewProverTask(db),
 			proverBlockListOrm: orm.NewProverBlockList(db),
+			proverAllowListOrm: orm.NewProverAllowList(db),
+			proverScoreOrm:     orm.NewProverScore(db),
 		},
 		batchAttemptsExceedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_batch_attempts_exceed_total",
@@ -61,6 +66,14 @@ func NewBatchProverTask(cfg *config.Config, chainCfg *params.ChainConfig, db *go
 			Help: "Total number of batch get task.",
 		}, []string{"fork_name"}),
 		batchTaskGetTaskProver: newGetTaskCounterVec(promauto.With(reg), "batch"),
+		batchProofReusedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_batch_proof_reused_total",
+			Help: "Total number of batch tasks verified with the proof of an identical batch task.",
+		}),
+		batchTaskVetoedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_batch_task_vetoed_total",
+			Help: "Total number of batch task assignments vetoed by an assignment hook.",
+		}),
 	}
 	return bp
 }
@@ -70,33 +83,19 @@ type chunkIndexRange struct {
 	end   uint64
 }
 
-func (r *chunkIndexRange) merge(o chunkIndexRange) *chunkIndexRange {
-	var start, end = r.start, r.end
-	if o.start < r.start {
-		start = o.start
-	}
-	if o.end > r.end {
-		end = o.end
-	}
-	return &chunkIndexRange{start, end}
-}
-
-func (r *chunkIndexRange) contains(start, end uint64) bool {
-	return r.start <= start && r.end > end
-}
-
-type getHardForkNameByBatchFunc func(*orm.Batch) (string, error)
-
+// doAssignTaskWithinChunkRange assigns a batch of the chunk range, which is proved with the circuit of the hard fork.
 func (bp *BatchProverTask) doAssignTaskWithinChunkRange(ctx *gin.Context, taskCtx *proverTaskContext,
-	chunkRange *chunkIndexRange, getTaskParameter *coordinatorType.GetTaskParameter, getHardForkName getHardForkNameByBatchFunc) (*coordinatorType.GetTaskSchema, error) {
+	chunkRange *chunkIndexRange, getTaskParameter *coordinatorType.GetTaskParameter, hardForkName string) (*coordinatorType.GetTaskSchema, error) {
 	startChunkIndex, endChunkIndex := chunkRange.start, chunkRange.end
 	maxActiveAttempts := bp.cfg.ProverManager.ProversPerSession
 	maxTotalAttempts := bp.cfg.ProverManager.SessionAttempts
 	var batchTask *orm.Batch
+	// the batches skipped for the prover, the next batch in the assignment order is tried instead.
+	var skippedHashes []string
 	for i := 0; i < 5; i++ {
 		var getTaskError error
 		var tmpBatchTask *orm.Batch
-		tmpBatchTask, getTaskError = bp.batchOrm.GetAssignedBatch(ctx.Copy(), startChunkIndex, endChunkIndex, maxActiveAttempts, maxTotalAttempts)
+		tmpBatchTask, getTaskError = bp.batchOrm.GetAssignedBatch(ctx.Copy(), startChunkIndex, endChunkIndex, maxActiveAttempts, maxTotalAttempts, skippedHashes)
 		if getTaskError != nil {
 			log.Error("failed to get assigned batch proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
 			return nil, ErrCoordinatorInternalFailure
@@ -105,7 +104,7 @@ func (bp *BatchProverTask) doAssignTaskWithinChunkRange(ctx *gin.Context, taskCt
 		// Why here need get again? In order to support a task can assign to multiple prover, need also assign `ProvingTaskAssigned`
 		// batch to prover. But use `proving_status in (1, 2)` will not use the postgres index. So need split the sql.
 		if tmpBatchTask == nil {
-			tmpBatchTask, getTaskError = bp.batchOrm.GetUnassignedBatch(ctx.Copy(), startChunkIndex, endChunkIndex, maxActiveAttempts, maxTotalAttempts)
+			tmpBatchTask, getTaskError = bp.batchOrm.GetUnassignedBatch(ctx.Copy(), startChunkIndex, endChunkIndex, maxActiveAttempts, maxTotalAttempts, skippedHashes)
 			if getTaskError != nil {
 				log.Error("failed to get unassigned batch proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
 				return nil, ErrCoordinatorInternalFailure
@@ -117,6 +116,12 @@ func (bp *BatchProverTask) doAssignTaskWithinChunkRange(ctx *gin.Context, taskCt
 			return nil, nil
 		}
 
+		// a batch the prover failed is retried by another prover.
+		if bp.isTaskFailedByProver(ctx.Copy(), taskCtx, message.ProofTypeBatch, tmpBatchTask.Hash) {
+			skippedHashes = append(skippedHashes, tmpBatchTask.Hash)
+			continue
+		}
+
 		rowsAffected, updateAttemptsErr := bp.batchOrm.UpdateBatchAttempts(ctx.Copy(), tmpBatchTask.Index, tmpBatchTask.ActiveAttempts, tmpBatchTask.TotalAttempts)
 		if updateAttemptsErr != nil {
 			log.Error("failed to update batch attempts", "height", getTaskParameter.ProverHeight, "err", updateAttemptsErr)
@@ -137,24 +142,56 @@ func (bp *BatchProverTask) doAssignTaskWithinChunkRange(ctx *gin.Context, taskCt
 		return nil, nil
 	}
 
-	log.Info("start batch proof generation session", "task_id", batchTask.Hash, "public key", taskCtx.PublicKey, "prover name", taskCtx.ProverName)
-	var (
-		proverVersion = taskCtx.ProverVersion
-		hardForkName  = taskCtx.HardForkName
-	)
-	var err error
-	if getHardForkName != nil {
-		hardForkName, err = getHardForkName(batchTask)
-		if err != nil {
-			log.Error("failed to get hard fork name by batch", "task_id", batchTask.Hash, "error", err.Error())
+	taskData, taskHeight, err := bp.getTaskData(ctx.Copy(), batchTask.Hash)
+	if err != nil {
+		bp.recoverActiveAttempts(ctx, batchTask)
+		log.Error("get batch task data failure", "task_id", batchTask.Hash, "err", err)
+		return nil, ErrCoordinatorInternalFailure
+	}
+
+	// An identical batch, e.g. the same chunks batched again, may already be proved.
+	contentHash := taskContentHash(taskData)
+	reused, err := bp.batchOrm.ReuseProofByTaskContentHash(ctx.Copy(), batchTask.Hash, contentHash)
+	if err != nil {
+		bp.recoverActiveAttempts(ctx, batchTask)
+		log.Error("reuse batch proof failure", "task_id", batchTask.Hash, "err", err)
+		return nil, ErrCoordinatorInternalFailure
+	}
+	if reused {
+		bp.recoverActiveAttempts(ctx, batchTask)
+		bp.batchProofReusedTotal.Inc()
+		log.Info("batch proved by the proof of an identical batch", utils.LogKeyTaskID, batchTask.Hash, "task content hash", contentHash)
+		return bp.doAssignTaskWithinChunkRange(ctx, taskCtx, chunkRange, getTaskParameter, hardForkName)
+	}
+	if batchTask.TaskContentHash != contentHash {
+		if err = bp.batchOrm.UpdateTaskContentHash(ctx.Copy(), batchTask.Hash, contentHash); err != nil {
+			bp.recoverActiveAttempts(ctx, batchTask)
+			log.Error("update batch task content hash failure", "task_id", batchTask.Hash, "err", err)
 			return nil, ErrCoordinatorInternalFailure
 		}
 	}
 
+	log.Info("start batch proof generation session", utils.LogKeyTaskID, batchTask.Hash, utils.LogKeyRequestID, taskCtx.RequestID, "public key", taskCtx.PublicKey, "prover name", taskCtx.ProverName)
+	proverVersion := taskCtx.ProverVersion
+
+	if !allowAssignment(ctx.Copy(), &AssignmentCandidate{
+		Prover:       NewAssignmentProver(ctx),
+		TaskType:     message.ProofTypeBatch,
+		TaskID:       batchTask.Hash,
+		HardForkName: hardForkName,
+		Index:        batchTask.Index,
+		TaskHeight:   taskHeight,
+	}) {
+		bp.recoverActiveAttempts(ctx, batchTask)
+		bp.batchTaskVetoedTotal.Inc()
+		return nil, nil
+	}
+
 	proverTask := orm.ProverTask{
 		TaskID:          batchTask.Hash,
 		ProverPublicKey: taskCtx.PublicKey,
 		TaskType:        int16(message.ProofTypeBatch),
+		HardForkName:    hardForkName,
 		ProverName:      taskCtx.ProverName,
 		ProverVersion:   proverVersion,
 		ProvingStatus:   int16(types.ProverAssigned),
@@ -170,12 +207,8 @@ func (bp *BatchProverTask) doAssignTaskWithinChunkRange(ctx *gin.Context, taskCt
 		return nil, ErrCoordinatorInternalFailure
 	}
 
-	taskMsg, err := bp.formatProverTask(ctx.Copy(), &proverTask)
-	if err != nil {
-		bp.recoverActiveAttempts(ctx, batchTask)
-		log.Error("format prover task failure", "task_id", batchTask.Hash, "err", err)
-		return nil, ErrCoordinatorInternalFailure
-	}
+	taskMsg := bp.formatProverTask(&proverTask, taskData, message.TaskPriority(batchTask.Priority))
+	taskMsg.TaskHeight = taskHeight
 
 	bp.batchTaskGetTaskTotal.WithLabelValues(hardForkName).Inc()
 	bp.batchTaskGetTaskProver.With(prometheus.Labels{
@@ -184,6 +217,7 @@ func (bp *BatchProverTask) doAssignTaskWithinChunkRange(ctx *gin.Context, taskCt
 		coordinatorType.LabelProverVersion:   proverTask.ProverVersion,
 	}).Inc()
 
+	utils.TaskLogger(taskMsg.TaskID, taskMsg.UUID).Info("batch task assigned", utils.LogKeyRequestID, taskCtx.RequestID, "public key", taskCtx.PublicKey, "prefetch", taskCtx.Prefetch)
 	return taskMsg, nil
 }
 
@@ -233,67 +267,52 @@ func (bp *BatchProverTask) assignWithSingleCircuit(ctx *gin.Context, taskCtx *pr
 	if chunkRange == nil {
 		return nil, nil
 	}
-	return bp.doAssignTaskWithinChunkRange(ctx, taskCtx, chunkRange, getTaskParameter, nil)
+	return bp.doAssignTaskWithinChunkRange(ctx, taskCtx, chunkRange, getTaskParameter, taskCtx.HardForkName)
 }
 
-func (bp *BatchProverTask) assignWithTwoCircuits(ctx *gin.Context, taskCtx *proverTaskContext, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error) {
-	var (
-		hardForkNames [2]string
-		chunkRanges   [2]*chunkIndexRange
-		err           error
-	)
-	for i := 0; i < 2; i++ {
-		hardForkNames[i] = bp.reverseVkMap[getTaskParameter.VKs[i]]
-		chunkRanges[i], err = bp.getChunkRangeByName(ctx, hardForkNames[i])
+// assignWithMultipleCircuits assigns a batch of any hard fork the prover has the circuit of, the earliest hard fork
+// first, so the batches of the previous circuit are drained while the new one takes over.
+func (bp *BatchProverTask) assignWithMultipleCircuits(ctx *gin.Context, taskCtx *proverTaskContext, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error) {
+	for _, hardForkName := range bp.hardForkNamesOfVKs(getTaskParameter.VKs) {
+		chunkRange, err := bp.getChunkRangeByName(ctx, hardForkName)
 		if err != nil {
 			return nil, err
 		}
-		if chunkRanges[i] == nil {
-			return nil, nil
-		}
-	}
-	chunkRange := chunkRanges[0].merge(*chunkRanges[1])
-	var hardForkName string
-	getHardForkName := func(batch *orm.Batch) (string, error) {
-		for i := 0; i < 2; i++ {
-			if chunkRanges[i].contains(batch.StartChunkIndex, batch.EndChunkIndex) {
-				hardForkName = hardForkNames[i]
-				break
-			}
+		// no chunk of the hard fork yet.
+		if chunkRange == nil {
+			continue
 		}
-		if hardForkName == "" {
-			log.Warn("get batch not belongs to any hard fork name", "batch id", batch.Index)
-			return "", fmt.Errorf("get batch not belongs to any hard fork name, batch id: %d", batch.Index)
+		schema, err := bp.doAssignTaskWithinChunkRange(ctx, taskCtx, chunkRange, getTaskParameter, hardForkName)
+		if err != nil || schema != nil {
+			return schema, err
 		}
-		return hardForkName, nil
-	}
-	schema, err := bp.doAssignTaskWithinChunkRange(ctx, taskCtx, chunkRange, getTaskParameter, getHardForkName)
-	if schema != nil && err == nil {
-		schema.HardForkName = hardForkName
-		return schema, nil
 	}
-	return schema, err
+	return nil, nil
 }
 
 // Assign load and assign batch tasks
 func (bp *BatchProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error) {
-	taskCtx, err := bp.checkParameter(ctx, getTaskParameter)
+	taskCtx, err := bp.checkParameter(ctx, getTaskParameter, message.ProofTypeBatch)
 	if err != nil || taskCtx == nil {
 		return nil, fmt.Errorf("check prover task parameter failed, error:%w", err)
 	}
 
+	if bp.isFlakyProverSkipped(ctx.Copy(), taskCtx) {
+		return nil, nil
+	}
+
 	if len(getTaskParameter.VKs) > 0 {
-		return bp.assignWithTwoCircuits(ctx, taskCtx, getTaskParameter)
+		return bp.assignWithMultipleCircuits(ctx, taskCtx, getTaskParameter)
 	}
 	return bp.assignWithSingleCircuit(ctx, taskCtx, getTaskParameter)
 }
 
-func (bp *BatchProverTask) formatProverTask(ctx context.Context, task *orm.ProverTask) (*coordinatorType.GetTaskSchema, error) {
+// getTaskData returns the BatchTaskDetail of the batch encoded as the prover task data, and the start block number of the batch.
+func (bp *BatchProverTask) getTaskData(ctx context.Context, batchHash string) ([]byte, uint64, error) {
 	// get chunk from db
-	chunks, err := bp.chunkOrm.GetChunksByBatchHash(ctx, task.TaskID)
+	chunks, err := bp.chunkOrm.GetChunksByBatchHash(ctx, batchHash)
 	if err != nil {
-		err = fmt.Errorf("failed to get chunk proofs for batch task id:%s err:%w ", task.TaskID, err)
-		return nil, err
+		return nil, 0, fmt.Errorf("failed to get chunk proofs for batch task id:%s err:%w ", batchHash, err)
 	}
 
 	var chunkProofs []*message.ChunkProof
@@ -301,7 +320,7 @@ func (bp *BatchProverTask) formatProverTask(ctx context.Context, task *orm.Prove
 	for _, chunk := range chunks {
 		var proof message.ChunkProof
 		if encodeErr := json.Unmarshal(chunk.Proof, &proof); encodeErr != nil {
-			return nil, fmt.Errorf("Chunk.GetProofsByBatchHash unmarshal proof error: %w, batch hash: %v, chunk hash: %v", encodeErr, task.TaskID, chunk.Hash)
+			return nil, 0, fmt.Errorf("Chunk.GetProofsByBatchHash unmarshal proof error: %w, batch hash: %v, chunk hash: %v", encodeErr, batchHash, chunk.Hash)
 		}
 		chunkProofs = append(chunkProofs, &proof)
 
@@ -324,22 +343,42 @@ func (bp *BatchProverTask) formatProverTask(ctx context.Context, task *orm.Prove
 		ChunkProofs: chunkProofs,
 	}
 
+	blobDataProof, err := bp.batchOrm.GetBlobDataProofByHash(ctx, batchHash)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get blob data proof for batch task id:%s err:%w", batchHash, err)
+	}
+	if len(blobDataProof) > 0 {
+		if taskDetail.BlobCommitment, err = message.NewBlobCommitment(blobDataProof); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode blob data proof for batch task id:%s err:%w", batchHash, err)
+		}
+	}
+
 	chunkProofsBytes, err := json.Marshal(taskDetail)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal chunk proofs, taskID:%s err:%w", task.TaskID, err)
+		return nil, 0, fmt.Errorf("failed to marshal chunk proofs, taskID:%s err:%w", batchHash, err)
 	}
 
-	taskMsg := &coordinatorType.GetTaskSchema{
-		UUID:     task.UUID.String(),
-		TaskID:   task.TaskID,
-		TaskType: int(message.ProofTypeBatch),
-		TaskData: string(chunkProofsBytes),
+	var taskHeight uint64
+	if len(chunks) > 0 {
+		taskHeight = chunks[0].StartBlockNumber
+	}
+	return chunkProofsBytes, taskHeight, nil
+}
+
+func (bp *BatchProverTask) formatProverTask(task *orm.ProverTask, taskData []byte, priority message.TaskPriority) *coordinatorType.GetTaskSchema {
+	return &coordinatorType.GetTaskSchema{
+		UUID:         task.UUID.String(),
+		TaskID:       task.TaskID,
+		TaskType:     int(message.ProofTypeBatch),
+		TaskData:     string(taskData),
+		HardForkName: task.HardForkName,
+		Priority:     int(priority),
+		Deadline:     task.AssignedAt.Unix() + int64(bp.cfg.ProverManager.BatchCollectionTimeSec),
 	}
-	return taskMsg, nil
 }
 
 func (bp *BatchProverTask) recoverActiveAttempts(ctx *gin.Context, batchTask *orm.Batch) {
-	if err := bp.chunkOrm.DecreaseActiveAttemptsByHash(ctx.Copy(), batchTask.Hash); err != nil {
+	if err := bp.batchOrm.DecreaseActiveAttemptsByHash(ctx.Copy(), batchTask.Hash); err != nil {
 		log.Error("failed to recover batch active attempts", "hash", batchTask.Hash, "error", err)
 	}
 }
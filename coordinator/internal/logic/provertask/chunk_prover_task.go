@@ -30,6 +30,11 @@ type ChunkProverTask struct {
 	chunkAttemptsExceedTotal prometheus.Counter
 	chunkTaskGetTaskTotal    *prometheus.CounterVec
 	chunkTaskGetTaskProver   *prometheus.CounterVec
+	chunkProofReusedTotal    prometheus.Counter
+	chunkTaskVetoedTotal     prometheus.Counter
+	chunkTaskTooLargeTotal   prometheus.Counter
+
+	estimator *provingTimeEstimator
 }
 
 // NewChunkProverTask new a chunk prover task
@@ -45,9 +50,12 @@ func NewChunkProverTask(cfg *config.Config, chainCfg *params.ChainConfig, db *go
 			nameForkMap:        nameForkMap,
 			forkHeights:        forkHeights,
 			chunkOrm:           orm.NewChunk(db),
+			batchOrm:           orm.NewBatch(db),
 			blockOrm:           orm.NewL2Block(db),
 			proverTaskOrm:      orm.NewProverTask(db),
 			proverBlockListOrm: orm.NewProverBlockList(db),
+			proverAllowListOrm: orm.NewProverAllowList(db),
+			proverScoreOrm:     orm.NewProverScore(db),
 		},
 		chunkAttemptsExceedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_chunk_attempts_exceed_total",
@@ -58,14 +66,26 @@ func NewChunkProverTask(cfg *config.Config, chainCfg *params.ChainConfig, db *go
 			Help: "Total number of chunk get task.",
 		}, []string{"fork_name"}),
 		chunkTaskGetTaskProver: newGetTaskCounterVec(promauto.With(reg), "chunk"),
+		chunkProofReusedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_chunk_proof_reused_total",
+			Help: "Total number of chunk tasks verified with the proof of an identical chunk task.",
+		}),
+		chunkTaskVetoedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_chunk_task_vetoed_total",
+			Help: "Total number of chunk task assignments vetoed by an assignment hook.",
+		}),
+		chunkTaskTooLargeTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_chunk_task_too_large_total",
+			Help: "Total number of chunk tasks skipped because they are estimated too large for a slow prover.",
+		}),
+		estimator: newProvingTimeEstimator(cfg.ProverManager.ProvingTimeEstimation, db),
 	}
 	return cp
 }
 
-type getHardForkNameByChunkFunc func(*orm.Chunk) (string, error)
-
+// doAssignTaskWithinBlockRange assigns a chunk of the block range, which is proved with the circuit of the hard fork.
 func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCtx *proverTaskContext,
-	blockRange *blockRange, getTaskParameter *coordinatorType.GetTaskParameter, getHardForkName getHardForkNameByChunkFunc) (*coordinatorType.GetTaskSchema, error) {
+	blockRange *blockRange, getTaskParameter *coordinatorType.GetTaskParameter, hardForkName string) (*coordinatorType.GetTaskSchema, error) {
 	fromBlockNum, toBlockNum := blockRange.from, blockRange.to
 	if toBlockNum > getTaskParameter.ProverHeight {
 		toBlockNum = getTaskParameter.ProverHeight + 1
@@ -73,11 +93,20 @@ func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCt
 
 	maxActiveAttempts := cp.cfg.ProverManager.ProversPerSession
 	maxTotalAttempts := cp.cfg.ProverManager.SessionAttempts
-	var chunkTask *orm.Chunk
+	speedRatio := 1.0
+	if cp.estimator != nil {
+		speedRatio = cp.estimator.proverSpeedRatio(ctx.Copy(), taskCtx.PublicKey)
+	}
+	var (
+		chunkTask    *orm.Chunk
+		estimatedSec uint64
+		// the chunks skipped for the prover, the next chunk in the assignment order is tried instead.
+		skippedHashes []string
+	)
 	for i := 0; i < 5; i++ {
 		var getTaskError error
 		var tmpChunkTask *orm.Chunk
-		tmpChunkTask, getTaskError = cp.chunkOrm.GetAssignedChunk(ctx.Copy(), fromBlockNum, toBlockNum, maxActiveAttempts, maxTotalAttempts)
+		tmpChunkTask, getTaskError = cp.chunkOrm.GetAssignedChunk(ctx.Copy(), fromBlockNum, toBlockNum, maxActiveAttempts, maxTotalAttempts, skippedHashes)
 		if getTaskError != nil {
 			log.Error("failed to get assigned chunk proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
 			return nil, ErrCoordinatorInternalFailure
@@ -86,7 +115,7 @@ func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCt
 		// Why here need get again? In order to support a task can assign to multiple prover, need also assign `ProvingTaskAssigned`
 		// chunk to prover. But use `proving_status in (1, 2)` will not use the postgres index. So need split the sql.
 		if tmpChunkTask == nil {
-			tmpChunkTask, getTaskError = cp.chunkOrm.GetUnassignedChunk(ctx.Copy(), fromBlockNum, toBlockNum, maxActiveAttempts, maxTotalAttempts)
+			tmpChunkTask, getTaskError = cp.chunkOrm.GetUnassignedChunk(ctx.Copy(), fromBlockNum, toBlockNum, maxActiveAttempts, maxTotalAttempts, skippedHashes)
 			if getTaskError != nil {
 				log.Error("failed to get unassigned chunk proving tasks", "height", getTaskParameter.ProverHeight, "err", getTaskError)
 				return nil, ErrCoordinatorInternalFailure
@@ -98,6 +127,25 @@ func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCt
 			return nil, nil
 		}
 
+		// a large chunk is left to the faster provers, the slow prover is tried with another chunk.
+		var tmpEstimatedSec uint64
+		if cp.estimator != nil {
+			tmpEstimatedSec = cp.estimator.estimateChunk(ctx.Copy(), tmpChunkTask)
+			if cp.estimator.isTooLarge(tmpEstimatedSec, speedRatio) {
+				log.Debug("chunk too large for the slow prover", "task_id", tmpChunkTask.Hash, "estimated proving time", tmpEstimatedSec,
+					"speed ratio", speedRatio, "public key", taskCtx.PublicKey)
+				cp.chunkTaskTooLargeTotal.Inc()
+				skippedHashes = append(skippedHashes, tmpChunkTask.Hash)
+				continue
+			}
+		}
+
+		// a chunk the prover failed is retried by another prover.
+		if cp.isTaskFailedByProver(ctx.Copy(), taskCtx, message.ProofTypeChunk, tmpChunkTask.Hash) {
+			skippedHashes = append(skippedHashes, tmpChunkTask.Hash)
+			continue
+		}
+
 		rowsAffected, updateAttemptsErr := cp.chunkOrm.UpdateChunkAttempts(ctx.Copy(), tmpChunkTask.Index, tmpChunkTask.ActiveAttempts, tmpChunkTask.TotalAttempts)
 		if updateAttemptsErr != nil {
 			log.Error("failed to update chunk attempts", "height", getTaskParameter.ProverHeight, "err", updateAttemptsErr)
@@ -110,6 +158,7 @@ func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCt
 		}
 
 		chunkTask = tmpChunkTask
+		estimatedSec = tmpEstimatedSec
 		break
 	}
 
@@ -118,30 +167,70 @@ func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCt
 		return nil, nil
 	}
 
-	log.Info("start chunk generation session", "task_id", chunkTask.Hash, "public key", taskCtx.PublicKey, "prover name", taskCtx.ProverName)
-	var (
-		proverVersion = taskCtx.ProverVersion
-		hardForkName  = taskCtx.HardForkName
-		err           error
-	)
-	if getHardForkName != nil {
-		hardForkName, err = getHardForkName(chunkTask)
-		if err != nil {
-			log.Error("failed to get hard fork name by chunk", "task_id", chunkTask.Hash, "error", err.Error())
+	taskData, err := cp.getTaskData(ctx.Copy(), chunkTask.Hash)
+	if err != nil {
+		cp.recoverActiveAttempts(ctx, chunkTask)
+		log.Error("get chunk task data failure", "task_id", chunkTask.Hash, "err", err)
+		return nil, ErrCoordinatorInternalFailure
+	}
+
+	// An identical chunk, e.g. the same block range chunked again, may already be proved.
+	contentHash := taskContentHash(taskData)
+	reused, err := cp.chunkOrm.ReuseProofByTaskContentHash(ctx.Copy(), chunkTask.Hash, contentHash)
+	if err != nil {
+		cp.recoverActiveAttempts(ctx, chunkTask)
+		log.Error("reuse chunk proof failure", "task_id", chunkTask.Hash, "err", err)
+		return nil, ErrCoordinatorInternalFailure
+	}
+	if reused {
+		cp.recoverActiveAttempts(ctx, chunkTask)
+		cp.chunkProofReusedTotal.Inc()
+		log.Info("chunk proved by the proof of an identical chunk", utils.LogKeyTaskID, chunkTask.Hash, "task content hash", contentHash)
+		if err = cp.checkAreAllChunkProofsReady(ctx.Copy(), chunkTask.BatchHash); err != nil {
+			log.Error("failed to check batch chunk proofs ready", "task_id", chunkTask.Hash, "batch hash", chunkTask.BatchHash, "err", err)
+			return nil, ErrCoordinatorInternalFailure
+		}
+		return cp.doAssignTaskWithinBlockRange(ctx, taskCtx, blockRange, getTaskParameter, hardForkName)
+	}
+	if chunkTask.TaskContentHash != contentHash {
+		if err = cp.chunkOrm.UpdateTaskContentHash(ctx.Copy(), chunkTask.Hash, contentHash); err != nil {
+			cp.recoverActiveAttempts(ctx, chunkTask)
+			log.Error("update chunk task content hash failure", "task_id", chunkTask.Hash, "err", err)
 			return nil, ErrCoordinatorInternalFailure
 		}
 	}
 
+	log.Info("start chunk generation session", utils.LogKeyTaskID, chunkTask.Hash, utils.LogKeyRequestID, taskCtx.RequestID, "public key", taskCtx.PublicKey, "prover name", taskCtx.ProverName)
+	proverVersion := taskCtx.ProverVersion
+
+	if !allowAssignment(ctx.Copy(), &AssignmentCandidate{
+		Prover:       NewAssignmentProver(ctx),
+		TaskType:     message.ProofTypeChunk,
+		TaskID:       chunkTask.Hash,
+		HardForkName: hardForkName,
+		Index:        chunkTask.Index,
+		TaskHeight:   chunkTask.StartBlockNumber,
+	}) {
+		cp.recoverActiveAttempts(ctx, chunkTask)
+		cp.chunkTaskVetoedTotal.Inc()
+		return nil, nil
+	}
+
 	proverTask := orm.ProverTask{
 		TaskID:          chunkTask.Hash,
 		ProverPublicKey: taskCtx.PublicKey,
 		TaskType:        int16(message.ProofTypeChunk),
+		HardForkName:    hardForkName,
 		ProverName:      taskCtx.ProverName,
 		ProverVersion:   proverVersion,
 		ProvingStatus:   int16(types.ProverAssigned),
 		FailureType:     int16(types.ProverTaskFailureTypeUndefined),
 		// here why need use UTC time. see scroll/common/databased/db.go
-		AssignedAt: utils.NowUTC(),
+		AssignedAt:              utils.NowUTC(),
+		EstimatedProvingTimeSec: estimatedSec,
+	}
+	if cp.estimator != nil {
+		proverTask.Deadline = cp.estimator.deadline(proverTask.AssignedAt, estimatedSec, speedRatio, cp.cfg.ProverManager.ChunkCollectionTimeSec)
 	}
 
 	if err = cp.proverTaskOrm.InsertProverTask(ctx.Copy(), &proverTask); err != nil {
@@ -150,12 +239,8 @@ func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCt
 		return nil, ErrCoordinatorInternalFailure
 	}
 
-	taskMsg, err := cp.formatProverTask(ctx.Copy(), &proverTask)
-	if err != nil {
-		cp.recoverActiveAttempts(ctx, chunkTask)
-		log.Error("format prover task failure", "task_id", chunkTask.Hash, "err", err)
-		return nil, ErrCoordinatorInternalFailure
-	}
+	taskMsg := cp.formatProverTask(&proverTask, taskData, message.TaskPriority(chunkTask.Priority))
+	taskMsg.TaskHeight = chunkTask.StartBlockNumber
 
 	cp.chunkTaskGetTaskTotal.WithLabelValues(hardForkName).Inc()
 	cp.chunkTaskGetTaskProver.With(prometheus.Labels{
@@ -164,6 +249,7 @@ func (cp *ChunkProverTask) doAssignTaskWithinBlockRange(ctx *gin.Context, taskCt
 		coordinatorType.LabelProverVersion:   proverTask.ProverVersion,
 	}).Inc()
 
+	utils.TaskLogger(taskMsg.TaskID, taskMsg.UUID).Info("chunk task assigned", utils.LogKeyRequestID, taskCtx.RequestID, "public key", taskCtx.PublicKey, "prefetch", taskCtx.Prefetch)
 	return taskMsg, nil
 }
 
@@ -172,46 +258,23 @@ func (cp *ChunkProverTask) assignWithSingleCircuit(ctx *gin.Context, taskCtx *pr
 	if err != nil {
 		return nil, err
 	}
-	return cp.doAssignTaskWithinBlockRange(ctx, taskCtx, blockRange, getTaskParameter, nil)
+	return cp.doAssignTaskWithinBlockRange(ctx, taskCtx, blockRange, getTaskParameter, taskCtx.HardForkName)
 }
 
-func (cp *ChunkProverTask) assignWithTwoCircuits(ctx *gin.Context, taskCtx *proverTaskContext, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error) {
-	var (
-		hardForkNames [2]string
-		blockRanges   [2]*blockRange
-		err           error
-	)
-	for i := 0; i < 2; i++ {
-		hardForkNames[i] = cp.reverseVkMap[getTaskParameter.VKs[i]]
-		blockRanges[i], err = cp.getBlockRangeByName(hardForkNames[i])
+// assignWithMultipleCircuits assigns a chunk of any hard fork the prover has the circuit of, the earliest hard fork
+// first, so the chunks of the previous circuit are drained while the new one takes over.
+func (cp *ChunkProverTask) assignWithMultipleCircuits(ctx *gin.Context, taskCtx *proverTaskContext, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error) {
+	for _, hardForkName := range cp.hardForkNamesOfVKs(getTaskParameter.VKs) {
+		blockRange, err := cp.getBlockRangeByName(hardForkName)
 		if err != nil {
 			return nil, err
 		}
-	}
-	blockRange, err := blockRanges[0].merge(*blockRanges[1])
-	if err != nil {
-		return nil, err
-	}
-	var hardForkName string
-	getHardForkName := func(chunk *orm.Chunk) (string, error) {
-		for i := 0; i < 2; i++ {
-			if blockRanges[i].contains(chunk.StartBlockNumber, chunk.EndBlockNumber) {
-				hardForkName = hardForkNames[i]
-				break
-			}
-		}
-		if hardForkName == "" {
-			log.Warn("get chunk not belongs to any hard fork name", "chunk id", chunk.Index)
-			return "", fmt.Errorf("get chunk not belongs to any hard fork name, chunk id: %d", chunk.Index)
+		schema, err := cp.doAssignTaskWithinBlockRange(ctx, taskCtx, blockRange, getTaskParameter, hardForkName)
+		if err != nil || schema != nil {
+			return schema, err
 		}
-		return hardForkName, nil
-	}
-	schema, err := cp.doAssignTaskWithinBlockRange(ctx, taskCtx, blockRange, getTaskParameter, getHardForkName)
-	if schema != nil && err == nil {
-		schema.HardForkName = hardForkName
-		return schema, nil
 	}
-	return schema, err
+	return nil, nil
 }
 
 type blockRange struct {
@@ -219,19 +282,6 @@ type blockRange struct {
 	to   uint64
 }
 
-func (r *blockRange) merge(o blockRange) (*blockRange, error) {
-	if r.from == o.to {
-		return &blockRange{o.from, r.to}, nil
-	} else if r.to == o.from {
-		return &blockRange{r.from, o.to}, nil
-	}
-	return nil, fmt.Errorf("two ranges are not adjacent")
-}
-
-func (r *blockRange) contains(start, end uint64) bool {
-	return r.from <= start && r.to > end
-}
-
 func (cp *ChunkProverTask) getBlockRangeByName(hardForkName string) (*blockRange, error) {
 	hardForkNumber, err := cp.getHardForkNumberByName(hardForkName)
 	if err != nil {
@@ -245,22 +295,27 @@ func (cp *ChunkProverTask) getBlockRangeByName(hardForkName string) (*blockRange
 
 // Assign the chunk proof which need to prove
 func (cp *ChunkProverTask) Assign(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*coordinatorType.GetTaskSchema, error) {
-	taskCtx, err := cp.checkParameter(ctx, getTaskParameter)
+	taskCtx, err := cp.checkParameter(ctx, getTaskParameter, message.ProofTypeChunk)
 	if err != nil || taskCtx == nil {
 		return nil, fmt.Errorf("check prover task parameter failed, error:%w", err)
 	}
 
+	if cp.isFlakyProverSkipped(ctx.Copy(), taskCtx) {
+		return nil, nil
+	}
+
 	if len(getTaskParameter.VKs) > 0 {
-		return cp.assignWithTwoCircuits(ctx, taskCtx, getTaskParameter)
+		return cp.assignWithMultipleCircuits(ctx, taskCtx, getTaskParameter)
 	}
 	return cp.assignWithSingleCircuit(ctx, taskCtx, getTaskParameter)
 }
 
-func (cp *ChunkProverTask) formatProverTask(ctx context.Context, task *orm.ProverTask) (*coordinatorType.GetTaskSchema, error) {
+// getTaskData returns the ChunkTaskDetail of the chunk encoded as the prover task data.
+func (cp *ChunkProverTask) getTaskData(ctx context.Context, chunkHash string) ([]byte, error) {
 	// Get block hashes.
-	blockHashes, dbErr := cp.blockOrm.GetL2BlockHashesByChunkHash(ctx, task.TaskID)
+	blockHashes, dbErr := cp.blockOrm.GetL2BlockHashesByChunkHash(ctx, chunkHash)
 	if dbErr != nil || len(blockHashes) == 0 {
-		return nil, fmt.Errorf("failed to fetch block hashes of a chunk, chunk hash:%s err:%w", task.TaskID, dbErr)
+		return nil, fmt.Errorf("failed to fetch block hashes of a chunk, chunk hash:%s err:%w", chunkHash, dbErr)
 	}
 
 	taskDetail := message.ChunkTaskDetail{
@@ -268,17 +323,39 @@ func (cp *ChunkProverTask) formatProverTask(ctx context.Context, task *orm.Prove
 	}
 	blockHashesBytes, err := json.Marshal(taskDetail)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal block hashes hash:%s, err:%w", task.TaskID, err)
+		return nil, fmt.Errorf("failed to marshal block hashes hash:%s, err:%w", chunkHash, err)
 	}
+	return blockHashesBytes, nil
+}
 
-	proverTaskSchema := &coordinatorType.GetTaskSchema{
-		UUID:     task.UUID.String(),
-		TaskID:   task.TaskID,
-		TaskType: int(message.ProofTypeChunk),
-		TaskData: string(blockHashesBytes),
+func (cp *ChunkProverTask) formatProverTask(task *orm.ProverTask, taskData []byte, priority message.TaskPriority) *coordinatorType.GetTaskSchema {
+	deadline := task.AssignedAt.Unix() + int64(cp.cfg.ProverManager.ChunkCollectionTimeSec)
+	if task.Deadline != nil {
+		deadline = task.Deadline.Unix()
+	}
+	return &coordinatorType.GetTaskSchema{
+		UUID:         task.UUID.String(),
+		TaskID:       task.TaskID,
+		TaskType:     int(message.ProofTypeChunk),
+		TaskData:     string(taskData),
+		HardForkName: task.HardForkName,
+		Priority:     int(priority),
+		Deadline:     deadline,
 	}
+}
 
-	return proverTaskSchema, nil
+func (cp *ChunkProverTask) checkAreAllChunkProofsReady(ctx context.Context, batchHash string) error {
+	if batchHash == "" {
+		return nil
+	}
+	allReady, err := cp.chunkOrm.CheckIfBatchChunkProofsAreReady(ctx, batchHash)
+	if err != nil {
+		return err
+	}
+	if allReady {
+		return cp.batchOrm.UpdateChunkProofsStatusByBatchHash(ctx, batchHash, types.ChunkProofsStatusReady)
+	}
+	return nil
 }
 
 func (cp *ChunkProverTask) recoverActiveAttempts(ctx *gin.Context, chunkTask *orm.Chunk) {
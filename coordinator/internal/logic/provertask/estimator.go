@@ -0,0 +1,189 @@
+package provertask
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+const (
+	// provingTimeModelRefreshInterval is how long a fitted proving time model is used before it's fitted again.
+	provingTimeModelRefreshInterval = 5 * time.Minute
+	// proverSpeedSamples is the number of the latest estimated tasks of a prover its speed is measured on, a prover
+	// with less than minProverSpeedSamples of them proves at the estimated speed.
+	proverSpeedSamples    = 20
+	minProverSpeedSamples = 5
+)
+
+// provingTimeModel predicts the proving time of a chunk from its row usage and transaction number, it's fitted by
+// least squares on the proved chunks.
+type provingTimeModel struct {
+	intercept float64
+	rowCoef   float64
+	txCoef    float64
+	// minSec is the shortest proving time of the samples, the floor of the estimates.
+	minSec float64
+}
+
+// estimate returns the proving time of a chunk in seconds.
+func (m *provingTimeModel) estimate(crcMax, txNum uint64) float64 {
+	return math.Max(m.intercept+m.rowCoef*float64(crcMax)+m.txCoef*float64(txNum), m.minSec)
+}
+
+// fitProvingTimeModel fits the model on the proved chunks, a feature that doesn't vary among the samples is left
+// out of the fit. It returns nil without samples.
+func fitProvingTimeModel(samples []orm.Chunk) *provingTimeModel {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	n := float64(len(samples))
+	var meanRow, meanTx, meanSec float64
+	minSec := math.MaxFloat64
+	for _, sample := range samples {
+		meanRow += float64(sample.CrcMax) / n
+		meanTx += float64(sample.TotalL2TxNum) / n
+		meanSec += float64(sample.ProofTimeSec) / n
+		minSec = math.Min(minSec, float64(sample.ProofTimeSec))
+	}
+
+	// the centered sums of squares and products.
+	var rowRow, txTx, rowTx, rowSec, txSec float64
+	for _, sample := range samples {
+		row := float64(sample.CrcMax) - meanRow
+		tx := float64(sample.TotalL2TxNum) - meanTx
+		sec := float64(sample.ProofTimeSec) - meanSec
+		rowRow += row * row
+		txTx += tx * tx
+		rowTx += row * tx
+		rowSec += row * sec
+		txSec += tx * sec
+	}
+
+	model := &provingTimeModel{minSec: minSec}
+	det := rowRow*txTx - rowTx*rowTx
+	switch {
+	case det > 1e-9*rowRow*txTx:
+		model.rowCoef = (rowSec*txTx - txSec*rowTx) / det
+		model.txCoef = (txSec*rowRow - rowSec*rowTx) / det
+	case rowRow > 0:
+		model.rowCoef = rowSec / rowRow
+	case txTx > 0:
+		model.txCoef = txSec / txTx
+	}
+	model.intercept = meanSec - model.rowCoef*meanRow - model.txCoef*meanTx
+	return model
+}
+
+// provingTimeEstimator estimates the proving time of the chunk tasks, and how fast the provers prove them.
+type provingTimeEstimator struct {
+	cfg           *config.ProvingTimeEstimationConfig
+	chunkOrm      *orm.Chunk
+	proverTaskOrm *orm.ProverTask
+
+	mu          sync.Mutex
+	model       *provingTimeModel
+	refreshedAt time.Time
+}
+
+// newProvingTimeEstimator returns nil if the estimation is disabled.
+func newProvingTimeEstimator(cfg *config.ProvingTimeEstimationConfig, db *gorm.DB) *provingTimeEstimator {
+	if cfg == nil {
+		return nil
+	}
+	return &provingTimeEstimator{
+		cfg:           cfg,
+		chunkOrm:      orm.NewChunk(db),
+		proverTaskOrm: orm.NewProverTask(db),
+	}
+}
+
+// getModel returns the proving time model, fitted again on the latest proved chunks once it's outdated. It returns
+// nil until enough chunks are proved.
+func (e *provingTimeEstimator) getModel(ctx context.Context) *provingTimeModel {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if time.Since(e.refreshedAt) < provingTimeModelRefreshInterval {
+		return e.model
+	}
+	// a db failure is retried at the next refresh, the outdated model is still a good hint meanwhile.
+	e.refreshedAt = time.Now()
+
+	samples, err := e.chunkOrm.GetProvedChunkSamples(ctx, e.cfg.SampleSize)
+	if err != nil {
+		log.Error("failed to get the proved chunks of the proving time estimation", "error", err)
+		return e.model
+	}
+	if len(samples) < e.cfg.MinSamples {
+		e.model = nil
+		return nil
+	}
+	e.model = fitProvingTimeModel(samples)
+	if e.model != nil {
+		log.Info("chunk proving time model fitted", "samples", len(samples), "intercept", e.model.intercept,
+			"row coefficient", e.model.rowCoef, "tx coefficient", e.model.txCoef)
+	}
+	return e.model
+}
+
+// estimateChunk returns the proving time of the chunk in seconds, 0 if it can't be estimated yet.
+func (e *provingTimeEstimator) estimateChunk(ctx context.Context, chunk *orm.Chunk) uint64 {
+	model := e.getModel(ctx)
+	if model == nil {
+		return 0
+	}
+	return uint64(math.Ceil(model.estimate(chunk.CrcMax, chunk.TotalL2TxNum)))
+}
+
+// proverSpeedRatio returns how much longer than estimated the latest chunk proofs of the prover took, 1 if the
+// prover has too few estimated chunk proofs.
+func (e *provingTimeEstimator) proverSpeedRatio(ctx context.Context, publicKey string) float64 {
+	proverTasks, err := e.proverTaskOrm.GetEstimatedProverTasks(ctx, publicKey, message.ProofTypeChunk, proverSpeedSamples)
+	if err != nil {
+		// don't block the prover on a db failure, the speed is only a hint.
+		log.Error("failed to get the estimated prover tasks", "public key", publicKey, "error", err)
+		return 1
+	}
+	return speedRatio(proverTasks)
+}
+
+func speedRatio(proverTasks []orm.ProverTask) float64 {
+	if len(proverTasks) < minProverSpeedSamples {
+		return 1
+	}
+	var provingTimeSec, estimatedSec uint64
+	for _, proverTask := range proverTasks {
+		provingTimeSec += proverTask.ProvingTimeSec
+		estimatedSec += proverTask.EstimatedProvingTimeSec
+	}
+	return float64(provingTimeSec) / float64(estimatedSec)
+}
+
+// isTooLarge tells whether the chunk estimated to prove in estimatedSec is kept off the prover of the speed ratio.
+func (e *provingTimeEstimator) isTooLarge(estimatedSec uint64, ratio float64) bool {
+	if e.cfg.LargeChunkSec <= 0 || e.cfg.SlowProverRatio <= 0 {
+		return false
+	}
+	return estimatedSec >= uint64(e.cfg.LargeChunkSec) && ratio > e.cfg.SlowProverRatio
+}
+
+// deadline returns the deadline of the chunk task assigned at assignedAt, the estimate is scaled by the speed of
+// the prover and the deadline factor, the collection time is the minimum. It returns nil if not estimated.
+func (e *provingTimeEstimator) deadline(assignedAt time.Time, estimatedSec uint64, ratio float64, collectionTimeSec int) *time.Time {
+	if estimatedSec == 0 || e.cfg.DeadlineFactor <= 0 {
+		return nil
+	}
+	timeoutSec := math.Max(float64(estimatedSec)*ratio*e.cfg.DeadlineFactor, float64(collectionTimeSec))
+	deadline := assignedAt.Add(time.Duration(math.Ceil(timeoutSec)) * time.Second)
+	return &deadline
+}
@@ -0,0 +1,71 @@
+package provertask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/orm"
+)
+
+func TestFitProvingTimeModel(t *testing.T) {
+	assert.Nil(t, fitProvingTimeModel(nil))
+
+	// proving time = 30 + 1e-4 * rows + 0.5 * txs
+	var samples []orm.Chunk
+	for i := uint64(1); i <= 10; i++ {
+		crcMax, txNum := i*100000, (i*7)%11*10
+		samples = append(samples, orm.Chunk{CrcMax: crcMax, TotalL2TxNum: txNum, ProofTimeSec: int32(30 + crcMax/10000 + txNum/2)})
+	}
+	model := fitProvingTimeModel(samples)
+	assert.InDelta(t, 30, model.intercept, 1e-6)
+	assert.InDelta(t, 1e-4, model.rowCoef, 1e-9)
+	assert.InDelta(t, 0.5, model.txCoef, 1e-6)
+	assert.InDelta(t, 30+200+50, model.estimate(2000000, 100), 1e-6)
+
+	// the transaction number doesn't vary, it's left out of the fit.
+	samples = []orm.Chunk{
+		{CrcMax: 100000, TotalL2TxNum: 10, ProofTimeSec: 20},
+		{CrcMax: 200000, TotalL2TxNum: 10, ProofTimeSec: 30},
+		{CrcMax: 300000, TotalL2TxNum: 10, ProofTimeSec: 40},
+	}
+	model = fitProvingTimeModel(samples)
+	assert.InDelta(t, 1e-4, model.rowCoef, 1e-9)
+	assert.Zero(t, model.txCoef)
+	assert.InDelta(t, 60, model.estimate(500000, 1000), 1e-6)
+	// the estimates don't go below the fastest proof.
+	assert.InDelta(t, 20, model.estimate(0, 0), 1e-6)
+
+	// nothing varies, the mean is the estimate.
+	samples = []orm.Chunk{{CrcMax: 1, TotalL2TxNum: 1, ProofTimeSec: 10}, {CrcMax: 1, TotalL2TxNum: 1, ProofTimeSec: 20}}
+	model = fitProvingTimeModel(samples)
+	assert.InDelta(t, 15, model.estimate(1000, 1000), 1e-6)
+}
+
+func TestProverSpeedRatio(t *testing.T) {
+	proverTasks := []orm.ProverTask{{EstimatedProvingTimeSec: 100, ProvingTimeSec: 300}}
+	assert.Equal(t, 1.0, speedRatio(proverTasks))
+
+	for i := 0; i < minProverSpeedSamples; i++ {
+		proverTasks = append(proverTasks, orm.ProverTask{EstimatedProvingTimeSec: 100, ProvingTimeSec: 200})
+	}
+	assert.InDelta(t, 1300.0/600.0, speedRatio(proverTasks), 1e-9)
+}
+
+func TestProvingTimeEstimatorScheduling(t *testing.T) {
+	e := &provingTimeEstimator{cfg: &config.ProvingTimeEstimationConfig{DeadlineFactor: 2, LargeChunkSec: 600, SlowProverRatio: 1.5}}
+	assert.False(t, e.isTooLarge(599, 3))
+	assert.False(t, e.isTooLarge(600, 1.5))
+	assert.True(t, e.isTooLarge(600, 1.6))
+
+	assignedAt := time.Unix(1700000000, 0)
+	assert.Nil(t, e.deadline(assignedAt, 0, 1, 300))
+	// the collection time is the minimum.
+	assert.Equal(t, assignedAt.Add(300*time.Second), *e.deadline(assignedAt, 100, 1, 300))
+	assert.Equal(t, assignedAt.Add(1200*time.Second), *e.deadline(assignedAt, 400, 1.5, 300))
+
+	e.cfg.DeadlineFactor = 0
+	assert.Nil(t, e.deadline(assignedAt, 400, 1.5, 300))
+}
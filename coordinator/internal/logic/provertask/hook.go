@@ -0,0 +1,118 @@
+package provertask
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/common/types/message"
+
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// AssignmentProver is the prover asking for a task.
+type AssignmentProver struct {
+	PublicKey string
+	Name      string
+	Version   string
+	// Hardware is nil if the prover didn't advertise it.
+	Hardware *coordinatorType.HardwareInfo
+	// MaxConcurrentTasks is nil if the prover didn't advertise it.
+	MaxConcurrentTasks *coordinatorType.ConcurrencyLimits
+}
+
+// NewAssignmentProver reads the prover identity, hardware and concurrency limits stored in the context by the login.
+func NewAssignmentProver(ctx *gin.Context) *AssignmentProver {
+	prover := &AssignmentProver{
+		PublicKey: ctx.GetString(coordinatorType.PublicKey),
+		Name:      ctx.GetString(coordinatorType.ProverName),
+		Version:   ctx.GetString(coordinatorType.ProverVersion),
+	}
+	if encoded := ctx.GetString(coordinatorType.Hardware); encoded != "" {
+		prover.Hardware = new(coordinatorType.HardwareInfo)
+		if err := json.Unmarshal([]byte(encoded), prover.Hardware); err != nil {
+			log.Warn("failed to decode prover hardware", "public key", prover.PublicKey, "error", err)
+			prover.Hardware = nil
+		}
+	}
+	if encoded := ctx.GetString(coordinatorType.MaxConcurrentTasks); encoded != "" {
+		prover.MaxConcurrentTasks = new(coordinatorType.ConcurrencyLimits)
+		if err := json.Unmarshal([]byte(encoded), prover.MaxConcurrentTasks); err != nil {
+			log.Warn("failed to decode prover concurrency limits", "public key", prover.PublicKey, "error", err)
+			prover.MaxConcurrentTasks = nil
+		}
+	}
+	return prover
+}
+
+// AssignmentCandidate is the task about to be assigned to the prover.
+type AssignmentCandidate struct {
+	Prover       *AssignmentProver
+	TaskType     message.ProofType
+	TaskID       string
+	HardForkName string
+	// Index is the chunk or batch index of the task.
+	Index uint64
+	// TaskHeight is the first L2 block of the task.
+	TaskHeight uint64
+}
+
+// AssignmentHook is a plugin point of the task assignment, e.g. to route some batch indexes to an internal prover
+// fleet, without changing the scheduler. The hooks are registered at startup by RegisterAssignmentHook.
+type AssignmentHook interface {
+	// PrioritizeProofTypes returns the proof types tried for the prover in order, it can reorder or drop the
+	// given ones, which are in scheduling order or the single proof type asked for.
+	PrioritizeProofTypes(prover *AssignmentProver, proofTypes []message.ProofType) []message.ProofType
+	// AllowAssignment decides whether the task is assigned to the prover. A vetoed task is left to the other
+	// provers and the prover is tried with its next proof type, a failure vetoes the task.
+	AllowAssignment(ctx context.Context, candidate *AssignmentCandidate) (bool, error)
+}
+
+var (
+	assignmentHooksMu sync.RWMutex
+	assignmentHooks   []AssignmentHook
+)
+
+// RegisterAssignmentHook registers a hook consulted by every task assignment, the hooks are run in registration
+// order. It's meant to be called before the coordinator api serves the provers, e.g. from the init function of a
+// plugin package imported by cmd/api.
+func RegisterAssignmentHook(hook AssignmentHook) {
+	assignmentHooksMu.Lock()
+	defer assignmentHooksMu.Unlock()
+	assignmentHooks = append(assignmentHooks, hook)
+}
+
+func registeredAssignmentHooks() []AssignmentHook {
+	assignmentHooksMu.RLock()
+	defer assignmentHooksMu.RUnlock()
+	return assignmentHooks
+}
+
+// PrioritizeProofTypes passes the proof types tried for the prover through the registered hooks.
+func PrioritizeProofTypes(prover *AssignmentProver, proofTypes []message.ProofType) []message.ProofType {
+	for _, hook := range registeredAssignmentHooks() {
+		proofTypes = hook.PrioritizeProofTypes(prover, proofTypes)
+	}
+	return proofTypes
+}
+
+// allowAssignment asks the registered hooks whether the task is assigned to the prover, the first veto wins.
+func allowAssignment(ctx context.Context, candidate *AssignmentCandidate) bool {
+	for _, hook := range registeredAssignmentHooks() {
+		allowed, err := hook.AllowAssignment(ctx, candidate)
+		if err != nil {
+			log.Error("assignment hook failed, the task is not assigned", "task type", candidate.TaskType.String(), "task id", candidate.TaskID,
+				"public key", candidate.Prover.PublicKey, "error", err)
+			return false
+		}
+		if !allowed {
+			log.Info("task assignment vetoed by hook", "task type", candidate.TaskType.String(), "task id", candidate.TaskID,
+				"index", candidate.Index, "public key", candidate.Prover.PublicKey, "prover name", candidate.Prover.Name)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package provertask
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+)
+
+// fleetHook routes the batches from index 100 to the internal prover fleet, and asks the fleet provers for batches first.
+type fleetHook struct {
+	fleet map[string]bool
+	err   error
+}
+
+func (h *fleetHook) PrioritizeProofTypes(prover *AssignmentProver, proofTypes []message.ProofType) []message.ProofType {
+	if h.fleet[prover.PublicKey] {
+		return []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}
+	}
+	return proofTypes
+}
+
+func (h *fleetHook) AllowAssignment(_ context.Context, candidate *AssignmentCandidate) (bool, error) {
+	if h.err != nil {
+		return false, h.err
+	}
+	if candidate.TaskType != message.ProofTypeBatch || candidate.Index < 100 {
+		return true, nil
+	}
+	return h.fleet[candidate.Prover.PublicKey], nil
+}
+
+func TestAssignmentHooks(t *testing.T) {
+	defer func() {
+		assignmentHooks = nil
+	}()
+
+	fleetProver := &AssignmentProver{PublicKey: "fleet"}
+	otherProver := &AssignmentProver{PublicKey: "other"}
+	scheduled := []message.ProofType{message.ProofTypeChunk, message.ProofTypeBatch}
+
+	// without hooks the assignment is left to the scheduler
+	assert.Equal(t, scheduled, PrioritizeProofTypes(fleetProver, scheduled))
+	assert.True(t, allowAssignment(context.Background(), &AssignmentCandidate{Prover: otherProver, TaskType: message.ProofTypeBatch, Index: 100}))
+
+	hook := &fleetHook{fleet: map[string]bool{"fleet": true}}
+	RegisterAssignmentHook(hook)
+
+	assert.Equal(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}, PrioritizeProofTypes(fleetProver, scheduled))
+	assert.Equal(t, scheduled, PrioritizeProofTypes(otherProver, scheduled))
+
+	assert.True(t, allowAssignment(context.Background(), &AssignmentCandidate{Prover: fleetProver, TaskType: message.ProofTypeBatch, Index: 100}))
+	assert.False(t, allowAssignment(context.Background(), &AssignmentCandidate{Prover: otherProver, TaskType: message.ProofTypeBatch, Index: 100}))
+	assert.True(t, allowAssignment(context.Background(), &AssignmentCandidate{Prover: otherProver, TaskType: message.ProofTypeBatch, Index: 99}))
+	assert.True(t, allowAssignment(context.Background(), &AssignmentCandidate{Prover: otherProver, TaskType: message.ProofTypeChunk, Index: 100}))
+
+	// a failing hook vetoes the task
+	hook.err = errors.New("fleet registry unavailable")
+	assert.False(t, allowAssignment(context.Background(), &AssignmentCandidate{Prover: fleetProver, TaskType: message.ProofTypeChunk}))
+}
@@ -1,7 +1,13 @@
 package provertask
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -10,9 +16,11 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/types/message"
 	"scroll-tech/common/version"
 
 	"scroll-tech/coordinator/internal/config"
+	"scroll-tech/coordinator/internal/logic/auth"
 	"scroll-tech/coordinator/internal/orm"
 	coordinatorType "scroll-tech/coordinator/internal/types"
 )
@@ -56,6 +64,8 @@ type BaseProverTask struct {
 	blockOrm           *orm.L2Block
 	proverTaskOrm      *orm.ProverTask
 	proverBlockListOrm *orm.ProverBlockList
+	proverAllowListOrm *orm.ProverAllowList
+	proverScoreOrm     *orm.ProverScore
 }
 
 type proverTaskContext struct {
@@ -63,10 +73,13 @@ type proverTaskContext struct {
 	ProverName    string
 	ProverVersion string
 	HardForkName  string
+	RequestID     string
+	// Prefetch is set when the prover is assigned the task ahead of finishing its current one.
+	Prefetch bool
 }
 
 // checkParameter check the prover task parameter illegal
-func (b *BaseProverTask) checkParameter(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter) (*proverTaskContext, error) {
+func (b *BaseProverTask) checkParameter(ctx *gin.Context, getTaskParameter *coordinatorType.GetTaskParameter, taskType message.ProofType) (*proverTaskContext, error) {
 	var ptc proverTaskContext
 
 	publicKey, publicKeyExist := ctx.Get(coordinatorType.PublicKey)
@@ -86,16 +99,19 @@ func (b *BaseProverTask) checkParameter(ctx *gin.Context, getTaskParameter *coor
 		return nil, fmt.Errorf("get prover version from context failed")
 	}
 	ptc.ProverVersion = proverVersion.(string)
+	ptc.RequestID = ctx.GetString(coordinatorType.RequestID)
 
 	if !version.CheckScrollRepoVersion(proverVersion.(string), b.cfg.ProverManager.MinProverVersion) {
 		return nil, fmt.Errorf("incompatible prover version. please upgrade your prover, minimum allowed version: %s, actual version: %s", b.cfg.ProverManager.MinProverVersion, proverVersion.(string))
 	}
 
-	// signals that the prover is multi-circuits version
+	// the tokens issued before a version policy change or a deprecation deadline are still valid
+	if _, err := auth.CheckProverVersion(b.cfg.ProverManager.VersionPolicy, ptc.ProverVersion); err != nil {
+		return nil, err
+	}
+
+	// signals that the prover is multi-circuits version, it's assigned the tasks of the hard forks of all its vks.
 	if len(getTaskParameter.VKs) > 0 {
-		if len(getTaskParameter.VKs) != 2 {
-			return nil, fmt.Errorf("parameter vks length must be 2")
-		}
 		for _, vk := range getTaskParameter.VKs {
 			if _, exists := b.reverseVkMap[vk]; !exists {
 				return nil, fmt.Errorf("incompatible vk. vk %s is invalid", vk)
@@ -133,17 +149,156 @@ func (b *BaseProverTask) checkParameter(ctx *gin.Context, getTaskParameter *coor
 		return nil, fmt.Errorf("public key %s is blocked from fetching tasks. ProverName: %s, ProverVersion: %s", publicKey, proverName, proverVersion)
 	}
 
+	// a public key removed from the allow list is rejected before its token expires.
+	if b.cfg.Auth != nil && b.cfg.Auth.RequireProverAllowList {
+		isAllowed, err := b.proverAllowListOrm.IsPublicKeyAllowed(ctx.Copy(), publicKey.(string))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check whether the public key %s is allowed, err: %w, proverName: %s, proverVersion: %s", publicKey, err, proverName, proverVersion)
+		}
+		if !isAllowed {
+			return nil, fmt.Errorf("public key %s is not in the prover allow list. ProverName: %s, ProverVersion: %s", publicKey, proverName, proverVersion)
+		}
+	}
+
+	// the prover is assigned tasks of the proof type up to its concurrency limit, without prefetching.
+	if maxTasks := b.maxConcurrentTasks(NewAssignmentProver(ctx).MaxConcurrentTasks, taskType); maxTasks > 1 {
+		assignedTasks, err := b.proverTaskOrm.GetAssignedProverTasksByPublicKey(ctx.Copy(), ptc.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the tasks assigned to prover %s, err: %w", ptc.PublicKey, err)
+		}
+		var assigned uint32
+		for _, task := range assignedTasks {
+			if message.ProofType(task.TaskType) == taskType {
+				assigned++
+			}
+		}
+		if assigned >= maxTasks {
+			return nil, fmt.Errorf("prover with publicKey %s is already assigned %d tasks of %s, its concurrency limit. ProverName: %s, ProverVersion: %s", publicKey, assigned, taskType, proverName, proverVersion)
+		}
+		return &ptc, nil
+	}
+
 	isAssigned, err := b.proverTaskOrm.IsProverAssigned(ctx.Copy(), publicKey.(string))
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if prover %s is assigned a task, err: %w", publicKey.(string), err)
 	}
 
 	if isAssigned {
-		return nil, fmt.Errorf("prover with publicKey %s is already assigned a task. ProverName: %s, ProverVersion: %s", publicKey, proverName, proverVersion)
+		canPrefetch, prefetchErr := b.canPrefetch(ctx.Copy(), getTaskParameter, ptc.PublicKey)
+		if prefetchErr != nil {
+			return nil, fmt.Errorf("failed to check if prover %s can prefetch a task, err: %w", publicKey.(string), prefetchErr)
+		}
+		if !canPrefetch {
+			return nil, fmt.Errorf("prover with publicKey %s is already assigned a task. ProverName: %s, ProverVersion: %s", publicKey, proverName, proverVersion)
+		}
+		ptc.Prefetch = true
 	}
 	return &ptc, nil
 }
 
+// maxConcurrentTasks is how many tasks of the proof type the prover is assigned at once, its advertised limit
+// capped by prover_manager.max_concurrent_tasks, one if either is not set.
+func (b *BaseProverTask) maxConcurrentTasks(limits *coordinatorType.ConcurrencyLimits, taskType message.ProofType) uint32 {
+	limit := limits.Limit(taskType)
+	if limit == nil || *limit <= 1 || b.cfg.ProverManager.MaxConcurrentTasks <= 1 {
+		return 1
+	}
+	return min(*limit, b.cfg.ProverManager.MaxConcurrentTasks)
+}
+
+// finalProvingStage is the last stage a task of the proof type reports before its proof is submitted.
+func finalProvingStage(taskType message.ProofType) message.ProvingStage {
+	if taskType == message.ProofTypeBatch {
+		return message.ProvingStageAggregating
+	}
+	return message.ProvingStageProving
+}
+
+// canPrefetch checks whether an assigned prover gets its next task ahead, only if prefetch is enabled and
+// asked for, and the single task it is assigned has reached its final proving stage. Being one task ahead
+// at most bounds what a dead prover holds until the collector times its tasks out.
+func (b *BaseProverTask) canPrefetch(ctx context.Context, getTaskParameter *coordinatorType.GetTaskParameter, publicKey string) (bool, error) {
+	if !b.cfg.ProverManager.Prefetch || !getTaskParameter.Prefetch {
+		return false, nil
+	}
+
+	assignedTasks, err := b.proverTaskOrm.GetAssignedProverTasksByPublicKey(ctx, publicKey)
+	if err != nil {
+		return false, err
+	}
+	if len(assignedTasks) != 1 {
+		return false, nil
+	}
+
+	currentTask := assignedTasks[0]
+	return message.ProvingStage(currentTask.ProgressStage) >= finalProvingStage(message.ProofType(currentTask.TaskType)), nil
+}
+
+// isFlakyProverSkipped decides whether the prover is left without a task this time because of its low score,
+// so flaky provers still get some tasks and are able to recover their score.
+func (b *BaseProverTask) isFlakyProverSkipped(ctx context.Context, taskCtx *proverTaskContext) bool {
+	scoreCfg := b.cfg.ProverManager.ProverScore
+	if scoreCfg == nil || scoreCfg.MinSuccessRate <= 0 {
+		return false
+	}
+
+	proverScore, err := b.proverScoreOrm.GetProverScore(ctx, taskCtx.PublicKey)
+	if err != nil {
+		// don't block the prover on a db failure, the score is only a hint.
+		log.Error("failed to get prover score", "public key", taskCtx.PublicKey, "prover name", taskCtx.ProverName, "error", err)
+		return false
+	}
+	if proverScore == nil || proverScore.SuccessCount+proverScore.FailureCount() < scoreCfg.MinSamples {
+		return false
+	}
+
+	successRate := proverScore.SuccessRate()
+	if successRate >= scoreCfg.MinSuccessRate {
+		return false
+	}
+
+	if rand.Float64() < successRate/scoreCfg.MinSuccessRate {
+		return false
+	}
+	log.Info("skip assigning task to flaky prover", "public key", taskCtx.PublicKey, "prover name", taskCtx.ProverName, "success rate", successRate)
+	return true
+}
+
+// isTaskFailedByProver decides whether the task is left to the other provers because the prover already failed it,
+// a prover panic likely doesn't happen on another prover, and the other failures are counted towards the quarantine
+// of the task anyway.
+func (b *BaseProverTask) isTaskFailedByProver(ctx context.Context, taskCtx *proverTaskContext, taskType message.ProofType, taskID string) bool {
+	if b.cfg.ProverManager.ProofFailureRetry == nil {
+		return false
+	}
+
+	failed, err := b.proverTaskOrm.IsTaskFailedByProver(ctx, taskType, taskID, taskCtx.PublicKey, taskCtx.ProverVersion)
+	if err != nil {
+		// don't block the prover on a db failure, the task is quarantined if it fails again.
+		log.Error("failed to check the task failed by the prover", "task_id", taskID, "public key", taskCtx.PublicKey, "error", err)
+		return false
+	}
+	if failed {
+		log.Debug("skip the task failed by the prover", "task_id", taskID, "public key", taskCtx.PublicKey, "prover version", taskCtx.ProverVersion)
+	}
+	return failed
+}
+
+// hardForkNamesOfVKs returns the hard forks whose circuits have the vks, the earliest hard fork first.
+func (b *BaseProverTask) hardForkNamesOfVKs(vks []string) []string {
+	hardForkNames := make([]string, 0, len(vks))
+	for _, vk := range vks {
+		hardForkName := b.reverseVkMap[vk]
+		if !slices.Contains(hardForkNames, hardForkName) {
+			hardForkNames = append(hardForkNames, hardForkName)
+		}
+	}
+	sort.SliceStable(hardForkNames, func(i, j int) bool {
+		return b.nameForkMap[hardForkNames[i]] < b.nameForkMap[hardForkNames[j]]
+	})
+	return hardForkNames
+}
+
 func (b *BaseProverTask) getHardForkNumberByName(forkName string) (uint64, error) {
 	// when the first hard fork upgrade, the prover don't pass the fork_name to coordinator.
 	// so coordinator need to be compatible.
@@ -177,3 +332,9 @@ func newGetTaskCounterVec(factory promauto.Factory, taskType string) *prometheus
 
 	return getTaskCounterVec.MustCurryWith(prometheus.Labels{"task_type": taskType})
 }
+
+// taskContentHash returns the hex sha256 of the task data, the identical tasks share the same proof.
+func taskContentHash(taskData []byte) string {
+	sum := sha256.Sum256(taskData)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,62 @@
+package provertask
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+func TestFinalProvingStage(t *testing.T) {
+	assert.Equal(t, message.ProvingStageProving, finalProvingStage(message.ProofTypeChunk))
+	assert.Equal(t, message.ProvingStageAggregating, finalProvingStage(message.ProofTypeBatch))
+}
+
+func TestCanPrefetchDisabled(t *testing.T) {
+	b := &BaseProverTask{cfg: &config.Config{ProverManager: &config.ProverManager{}}}
+
+	// the assigned tasks are not looked up unless prefetch is both enabled and asked for.
+	canPrefetch, err := b.canPrefetch(context.Background(), &coordinatorType.GetTaskParameter{Prefetch: true}, "key")
+	assert.NoError(t, err)
+	assert.False(t, canPrefetch)
+
+	b.cfg.ProverManager.Prefetch = true
+	canPrefetch, err = b.canPrefetch(context.Background(), &coordinatorType.GetTaskParameter{}, "key")
+	assert.NoError(t, err)
+	assert.False(t, canPrefetch)
+}
+
+func TestMaxConcurrentTasks(t *testing.T) {
+	b := &BaseProverTask{cfg: &config.Config{ProverManager: &config.ProverManager{MaxConcurrentTasks: 3}}}
+
+	zero, two, five := uint32(0), uint32(2), uint32(5)
+	limits := &coordinatorType.ConcurrencyLimits{Chunk: &five, Batch: &two}
+	assert.Equal(t, uint32(3), b.maxConcurrentTasks(limits, message.ProofTypeChunk))
+	assert.Equal(t, uint32(2), b.maxConcurrentTasks(limits, message.ProofTypeBatch))
+
+	// one task at a time without a limit.
+	assert.Equal(t, uint32(1), b.maxConcurrentTasks(nil, message.ProofTypeChunk))
+	assert.Equal(t, uint32(1), b.maxConcurrentTasks(&coordinatorType.ConcurrencyLimits{Batch: &two}, message.ProofTypeChunk))
+	assert.Equal(t, uint32(1), b.maxConcurrentTasks(&coordinatorType.ConcurrencyLimits{Chunk: &zero}, message.ProofTypeChunk))
+
+	b.cfg.ProverManager.MaxConcurrentTasks = 0
+	assert.Equal(t, uint32(1), b.maxConcurrentTasks(limits, message.ProofTypeChunk))
+}
+
+func TestHardForkNamesOfVKs(t *testing.T) {
+	vkMap := map[string]string{"bernoulli": "vk1", "curie": "vk2", "darwin": "vk3"}
+	b := &BaseProverTask{
+		vkMap:        vkMap,
+		reverseVkMap: reverseMap(vkMap),
+		nameForkMap:  map[string]uint64{"bernoulli": 100, "curie": 200, "darwin": 300},
+	}
+
+	// the earliest hard fork first, whatever the order of the vks.
+	assert.Equal(t, []string{"bernoulli", "curie", "darwin"}, b.hardForkNamesOfVKs([]string{"vk3", "vk1", "vk2"}))
+	assert.Equal(t, []string{"curie"}, b.hardForkNamesOfVKs([]string{"vk2", "vk2"}))
+}
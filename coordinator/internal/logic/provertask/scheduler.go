@@ -0,0 +1,159 @@
+package provertask
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// Scheduler decides which proof type is tried first for provers that don't ask for a specific one.
+// The proof type of the most urgent task goes first, the proof types of the same priority are picked by weight,
+// and a proof type with unassigned tasks that hasn't been assigned for longer than the starvation timeout is always
+// tried first, so the low-priority work is not starved by urgent tasks.
+// The proof types with resource requirements are only assigned to the provers with the required hardware.
+type Scheduler struct {
+	mu sync.Mutex
+
+	weights           map[message.ProofType]int
+	starvationTimeout time.Duration
+	lastAssignedAt    map[message.ProofType]time.Time
+	requirements      map[message.ProofType]*config.ResourceRequirement
+}
+
+// NewScheduler creates a scheduler, a nil config weights chunk and batch tasks equally.
+func NewScheduler(cfg *config.SchedulerConfig) *Scheduler {
+	weights := map[message.ProofType]int{
+		message.ProofTypeChunk: 1,
+		message.ProofTypeBatch: 1,
+	}
+	var starvationTimeout time.Duration
+	requirements := make(map[message.ProofType]*config.ResourceRequirement)
+	if cfg != nil {
+		weights[message.ProofTypeChunk] = cfg.ChunkWeight
+		weights[message.ProofTypeBatch] = cfg.BatchWeight
+		starvationTimeout = time.Duration(cfg.StarvationTimeoutSec) * time.Second
+		requirements[message.ProofTypeChunk] = cfg.ChunkRequirement
+		requirements[message.ProofTypeBatch] = cfg.BatchRequirement
+	}
+
+	now := time.Now()
+	return &Scheduler{
+		weights:           weights,
+		starvationTimeout: starvationTimeout,
+		lastAssignedAt: map[message.ProofType]time.Time{
+			message.ProofTypeChunk: now,
+			message.ProofTypeBatch: now,
+		},
+		requirements: requirements,
+	}
+}
+
+// Order returns all the proof types in the order they should be tried, given the highest priority of the
+// unassigned tasks of every proof type, a proof type without unassigned tasks is missing from topPriorities.
+// The proof types of the most urgent tasks are picked by weight, so an urgent task of either type jumps ahead of
+// the backlog of the other.
+func (s *Scheduler) Order(topPriorities map[message.ProofType]message.TaskPriority) []message.ProofType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	proofTypes := []message.ProofType{message.ProofTypeChunk, message.ProofTypeBatch}
+
+	// the starved proof type, if any, goes first. A proof type is only starved while it has unassigned tasks,
+	// its starvation clock restarts when it has none.
+	if s.starvationTimeout > 0 {
+		now := time.Now()
+		for _, proofType := range proofTypes {
+			if _, pending := topPriorities[proofType]; !pending {
+				s.lastAssignedAt[proofType] = now
+			}
+		}
+		for i, proofType := range proofTypes {
+			if now.Sub(s.lastAssignedAt[proofType]) > s.starvationTimeout {
+				return append([]message.ProofType{proofType}, append(proofTypes[:i:i], proofTypes[i+1:]...)...)
+			}
+		}
+	}
+
+	// the proof types without unassigned tasks go last, then by their top priority.
+	rank := func(proofType message.ProofType) int {
+		priority, ok := topPriorities[proofType]
+		if !ok {
+			return -1
+		}
+		return int(priority)
+	}
+	sort.SliceStable(proofTypes, func(i, j int) bool {
+		return rank(proofTypes[i]) > rank(proofTypes[j])
+	})
+	n := 1
+	for n < len(proofTypes) && rank(proofTypes[n]) == rank(proofTypes[0]) {
+		n++
+	}
+	return append(s.weightedOrder(proofTypes[:n]), proofTypes[n:]...)
+}
+
+// weightedOrder picks the first proof type by weight.
+func (s *Scheduler) weightedOrder(proofTypes []message.ProofType) []message.ProofType {
+	totalWeight := 0
+	for _, proofType := range proofTypes {
+		totalWeight += s.weights[proofType]
+	}
+	if totalWeight <= 0 {
+		rand.Shuffle(len(proofTypes), func(i, j int) {
+			proofTypes[i], proofTypes[j] = proofTypes[j], proofTypes[i]
+		})
+		return proofTypes
+	}
+
+	pick := rand.Intn(totalWeight)
+	for i, proofType := range proofTypes {
+		if pick < s.weights[proofType] {
+			return append([]message.ProofType{proofType}, append(proofTypes[:i:i], proofTypes[i+1:]...)...)
+		}
+		pick -= s.weights[proofType]
+	}
+	return proofTypes
+}
+
+// MarkAssigned records that a task of the proof type has just been assigned.
+func (s *Scheduler) MarkAssigned(proofType message.ProofType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAssignedAt[proofType] = time.Now()
+}
+
+// IsEligible checks whether the prover hardware meets the resource requirement of the proof type.
+// A prover which didn't advertise its hardware is only eligible for the proof types without requirement.
+func (s *Scheduler) IsEligible(proofType message.ProofType, hardware *coordinatorType.HardwareInfo) bool {
+	requirement := s.requirements[proofType]
+	if requirement == nil {
+		return true
+	}
+	if hardware == nil {
+		return false
+	}
+
+	if len(requirement.GPUModels) > 0 {
+		var modelAccepted bool
+		for _, model := range requirement.GPUModels {
+			if strings.EqualFold(model, hardware.GPUModel) {
+				modelAccepted = true
+				break
+			}
+		}
+		if !modelAccepted {
+			return false
+		}
+	}
+
+	return hardware.GPUMemoryMB >= requirement.MinGPUMemoryMB &&
+		hardware.CPUCores >= requirement.MinCPUCores &&
+		hardware.MemoryMB >= requirement.MinMemoryMB
+}
@@ -0,0 +1,89 @@
+package provertask
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+func TestSchedulerOrder(t *testing.T) {
+	s := NewScheduler(&config.SchedulerConfig{BatchWeight: 1, ChunkWeight: 0})
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}, s.Order(nil))
+	}
+
+	s = NewScheduler(nil)
+	assert.ElementsMatch(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}, s.Order(nil))
+}
+
+func TestSchedulerStarvation(t *testing.T) {
+	s := NewScheduler(&config.SchedulerConfig{BatchWeight: 1, ChunkWeight: 0, StarvationTimeoutSec: 1})
+	pending := map[message.ProofType]message.TaskPriority{
+		message.ProofTypeChunk: message.TaskPriorityNormal,
+		message.ProofTypeBatch: message.TaskPriorityNormal,
+	}
+	s.lastAssignedAt[message.ProofTypeChunk] = time.Now().Add(-2 * time.Second)
+	assert.Equal(t, []message.ProofType{message.ProofTypeChunk, message.ProofTypeBatch}, s.Order(pending))
+
+	s.MarkAssigned(message.ProofTypeChunk)
+	assert.Equal(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}, s.Order(pending))
+
+	// a proof type without unassigned tasks isn't starved, and its starvation clock restarts.
+	s.lastAssignedAt[message.ProofTypeChunk] = time.Now().Add(-2 * time.Second)
+	assert.Equal(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk},
+		s.Order(map[message.ProofType]message.TaskPriority{message.ProofTypeBatch: message.TaskPriorityNormal}))
+	assert.Equal(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}, s.Order(pending))
+}
+
+func TestSchedulerOrderByPriority(t *testing.T) {
+	s := NewScheduler(&config.SchedulerConfig{BatchWeight: 1, ChunkWeight: 0})
+
+	// an urgent chunk task jumps ahead of the batch tasks despite the weights.
+	topPriorities := map[message.ProofType]message.TaskPriority{
+		message.ProofTypeChunk: message.TaskPriorityHigh,
+		message.ProofTypeBatch: message.TaskPriorityNormal,
+	}
+	assert.Equal(t, []message.ProofType{message.ProofTypeChunk, message.ProofTypeBatch}, s.Order(topPriorities))
+
+	// the proof types of the same top priority are picked by weight.
+	topPriorities[message.ProofTypeBatch] = message.TaskPriorityHigh
+	assert.Equal(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}, s.Order(topPriorities))
+
+	// a proof type without unassigned tasks goes last.
+	assert.Equal(t, []message.ProofType{message.ProofTypeChunk, message.ProofTypeBatch},
+		s.Order(map[message.ProofType]message.TaskPriority{message.ProofTypeChunk: message.TaskPriorityLow}))
+
+	// the starved proof type still goes first.
+	s = NewScheduler(&config.SchedulerConfig{BatchWeight: 1, ChunkWeight: 1, StarvationTimeoutSec: 1})
+	s.lastAssignedAt[message.ProofTypeBatch] = time.Now().Add(-2 * time.Second)
+	topPriorities[message.ProofTypeBatch] = message.TaskPriorityLow
+	assert.Equal(t, []message.ProofType{message.ProofTypeBatch, message.ProofTypeChunk}, s.Order(topPriorities))
+}
+
+func TestSchedulerIsEligible(t *testing.T) {
+	s := NewScheduler(&config.SchedulerConfig{
+		BatchRequirement: &config.ResourceRequirement{
+			GPUModels:      []string{"NVIDIA A100", "NVIDIA H100"},
+			MinGPUMemoryMB: 40000,
+			MinMemoryMB:    256000,
+		},
+	})
+
+	hardware := &coordinatorType.HardwareInfo{GPUModel: "nvidia a100", GPUMemoryMB: 81920, CPUCores: 64, MemoryMB: 512000}
+	assert.True(t, s.IsEligible(message.ProofTypeBatch, hardware))
+	assert.True(t, s.IsEligible(message.ProofTypeChunk, hardware))
+
+	// a prover without hardware info is only eligible for the proof types without requirement.
+	assert.False(t, s.IsEligible(message.ProofTypeBatch, nil))
+	assert.True(t, s.IsEligible(message.ProofTypeChunk, nil))
+
+	assert.False(t, s.IsEligible(message.ProofTypeBatch, &coordinatorType.HardwareInfo{GPUModel: "NVIDIA A10", GPUMemoryMB: 81920, MemoryMB: 512000}))
+	assert.False(t, s.IsEligible(message.ProofTypeBatch, &coordinatorType.HardwareInfo{GPUModel: "NVIDIA H100", GPUMemoryMB: 24000, MemoryMB: 512000}))
+	assert.False(t, s.IsEligible(message.ProofTypeBatch, &coordinatorType.HardwareInfo{GPUModel: "NVIDIA H100", GPUMemoryMB: 81920, MemoryMB: 128000}))
+}
@@ -0,0 +1,254 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+// Action is a prover request limited per public key.
+type Action string
+
+const (
+	// Login is the prover login, i.e. the reconnections
+	Login Action = "login"
+	// GetTask is the task request of the prover
+	GetTask Action = "get_task"
+	// SubmitProof is the proof submission of the prover
+	SubmitProof Action = "submit_proof"
+)
+
+// sweepInterval is how often the idle buckets and the expired bans are dropped.
+const sweepInterval = time.Minute
+
+var (
+	// ErrRateLimited is returned when the prover exceeds the rate of an action
+	ErrRateLimited = errors.New("rate limited")
+	// ErrBanned is returned when the prover is temporarily banned
+	ErrBanned = errors.New("temporarily banned")
+)
+
+// Ban is a temporary ban of a prover public key.
+type Ban struct {
+	PublicKey string
+	Reason    string
+	BannedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type bucketKey struct {
+	action    Action
+	publicKey string
+}
+
+// bucket is a token bucket refilled at the rule's rate up to its burst.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type violations struct {
+	count       int
+	windowStart time.Time
+}
+
+// Limiter limits the login, get task and submit proof requests per prover public key with
+// token buckets, and temporarily bans the provers that keep exceeding the limits.
+// The state is kept in memory, so each coordinator replica enforces the limits on its own.
+type Limiter struct {
+	cfg   *config.RateLimit
+	rules map[Action]*config.RateLimitRule
+
+	mu         sync.Mutex
+	buckets    map[bucketKey]*bucket
+	violations map[string]*violations
+	bans       map[string]*Ban
+	lastSweep  time.Time
+	now        func() time.Time
+
+	rateLimitedTotal *prometheus.CounterVec
+	bannedTotal      prometheus.Counter
+}
+
+// NewLimiter creates a prover rate limiter.
+func NewLimiter(cfg *config.RateLimit, reg prometheus.Registerer) *Limiter {
+	return &Limiter{
+		cfg: cfg,
+		rules: map[Action]*config.RateLimitRule{
+			Login:       cfg.Login,
+			GetTask:     cfg.GetTask,
+			SubmitProof: cfg.SubmitProof,
+		},
+		buckets:    make(map[bucketKey]*bucket),
+		violations: make(map[string]*violations),
+		bans:       make(map[string]*Ban),
+		now:        time.Now,
+
+		rateLimitedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "coordinator_rate_limited_total",
+			Help: "Total number of prover requests rejected by the rate limiter.",
+		}, []string{"action"}),
+		bannedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_prover_banned_total",
+			Help: "Total number of provers temporarily banned for exceeding the rate limits.",
+		}),
+	}
+}
+
+// Allow takes a token of the action for the prover, it returns ErrBanned if the prover is banned
+// and ErrRateLimited if the prover has no token left.
+func (l *Limiter) Allow(action Action, publicKey string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	if ban, ok := l.bans[publicKey]; ok {
+		if now.Before(ban.ExpiresAt) {
+			l.rateLimitedTotal.WithLabelValues(string(action)).Inc()
+			return fmt.Errorf("%w until %s", ErrBanned, ban.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		delete(l.bans, publicKey)
+	}
+
+	rule := l.rules[action]
+	if rule == nil || rule.RatePerSec <= 0 {
+		return nil
+	}
+
+	key := bucketKey{action: action, publicKey: publicKey}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst(rule), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = refill(rule, b, now)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return nil
+	}
+
+	l.rateLimitedTotal.WithLabelValues(string(action)).Inc()
+	l.recordViolation(action, publicKey, now)
+	return fmt.Errorf("%w, too many %s requests", ErrRateLimited, action)
+}
+
+// Ban bans the prover for duration, replacing its current ban if any.
+func (l *Limiter) Ban(publicKey, reason string, duration time.Duration) *Ban {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ban(publicKey, reason, duration, l.now())
+}
+
+// Unban lifts the ban of the prover, it returns false if the prover is not banned.
+func (l *Limiter) Unban(publicKey string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ban, ok := l.bans[publicKey]
+	delete(l.bans, publicKey)
+	delete(l.violations, publicKey)
+	return ok && l.now().Before(ban.ExpiresAt)
+}
+
+// Bans returns the active bans ordered by expiry.
+func (l *Limiter) Bans() []Ban {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bans := make([]Ban, 0, len(l.bans))
+	for _, ban := range l.bans {
+		if now.Before(ban.ExpiresAt) {
+			bans = append(bans, *ban)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].ExpiresAt.Before(bans[j].ExpiresAt)
+	})
+	return bans
+}
+
+// recordViolation counts the rejected requests of the prover in the ban window,
+// and bans the prover once they reach the ban threshold.
+func (l *Limiter) recordViolation(action Action, publicKey string, now time.Time) {
+	if l.cfg.BanThreshold <= 0 || l.cfg.BanDurationSec <= 0 {
+		return
+	}
+
+	v, ok := l.violations[publicKey]
+	if !ok || now.Sub(v.windowStart) >= time.Duration(l.cfg.BanWindowSec)*time.Second {
+		v = &violations{windowStart: now}
+		l.violations[publicKey] = v
+	}
+	v.count++
+	if v.count < l.cfg.BanThreshold {
+		return
+	}
+
+	reason := fmt.Sprintf("exceeded the %s rate limit %d times", action, v.count)
+	l.ban(publicKey, reason, time.Duration(l.cfg.BanDurationSec)*time.Second, now)
+	l.bannedTotal.Inc()
+	log.Warn("prover temporarily banned", "public key", publicKey, "reason", reason, "duration sec", l.cfg.BanDurationSec)
+}
+
+func (l *Limiter) ban(publicKey, reason string, duration time.Duration, now time.Time) *Ban {
+	ban := &Ban{
+		PublicKey: publicKey,
+		Reason:    reason,
+		BannedAt:  now,
+		ExpiresAt: now.Add(duration),
+	}
+	l.bans[publicKey] = ban
+	delete(l.violations, publicKey)
+	return ban
+}
+
+// sweep drops the buckets refilled to their burst, the stale violation windows and the expired bans,
+// so the memory doesn't grow with the provers that are gone.
+func (l *Limiter) sweep(now time.Time) {
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		rule := l.rules[key.action]
+		if rule == nil || refill(rule, b, now) >= burst(rule) {
+			delete(l.buckets, key)
+		}
+	}
+	for publicKey, v := range l.violations {
+		if now.Sub(v.windowStart) >= time.Duration(l.cfg.BanWindowSec)*time.Second {
+			delete(l.violations, publicKey)
+		}
+	}
+	for publicKey, ban := range l.bans {
+		if !now.Before(ban.ExpiresAt) {
+			delete(l.bans, publicKey)
+		}
+	}
+}
+
+func burst(rule *config.RateLimitRule) float64 {
+	if rule.Burst < 1 {
+		return 1
+	}
+	return float64(rule.Burst)
+}
+
+func refill(rule *config.RateLimitRule, b *bucket, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.last).Seconds()*rule.RatePerSec
+	if maxTokens := burst(rule); tokens > maxTokens {
+		return maxTokens
+	}
+	return tokens
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewLimiter(&config.RateLimit{
+		GetTask:        &config.RateLimitRule{RatePerSec: 1, Burst: 2},
+		BanThreshold:   3,
+		BanWindowSec:   60,
+		BanDurationSec: 600,
+	}, nil)
+	limiter.now = func() time.Time { return now }
+
+	// the burst is allowed at once, then one request per second.
+	assert.NoError(t, limiter.Allow(GetTask, "prover1"))
+	assert.NoError(t, limiter.Allow(GetTask, "prover1"))
+	assert.ErrorIs(t, limiter.Allow(GetTask, "prover1"), ErrRateLimited)
+	now = now.Add(time.Second)
+	assert.NoError(t, limiter.Allow(GetTask, "prover1"))
+
+	// the limits are per public key and per action, the actions without a rule are not limited.
+	assert.NoError(t, limiter.Allow(GetTask, "prover2"))
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, limiter.Allow(SubmitProof, "prover1"))
+	}
+
+	// the third rejection within the window bans the prover from every action.
+	assert.ErrorIs(t, limiter.Allow(GetTask, "prover1"), ErrRateLimited)
+	assert.ErrorIs(t, limiter.Allow(GetTask, "prover1"), ErrRateLimited)
+	assert.ErrorIs(t, limiter.Allow(GetTask, "prover1"), ErrBanned)
+	assert.ErrorIs(t, limiter.Allow(SubmitProof, "prover1"), ErrBanned)
+	bans := limiter.Bans()
+	assert.Len(t, bans, 1)
+	assert.Equal(t, "prover1", bans[0].PublicKey)
+	assert.Equal(t, now.Add(600*time.Second), bans[0].ExpiresAt)
+
+	// the ban expires.
+	now = now.Add(600 * time.Second)
+	assert.NoError(t, limiter.Allow(GetTask, "prover1"))
+	assert.Empty(t, limiter.Bans())
+
+	// the admin bans and unbans a prover.
+	limiter.Ban("prover2", "manual", time.Hour)
+	assert.ErrorIs(t, limiter.Allow(GetTask, "prover2"), ErrBanned)
+	assert.True(t, limiter.Unban("prover2"))
+	assert.False(t, limiter.Unban("prover2"))
+	assert.NoError(t, limiter.Allow(GetTask, "prover2"))
+}
+
+func TestLimiterSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewLimiter(&config.RateLimit{
+		Login:          &config.RateLimitRule{RatePerSec: 0.1, Burst: 1},
+		BanThreshold:   10,
+		BanWindowSec:   60,
+		BanDurationSec: 600,
+	}, nil)
+	limiter.now = func() time.Time { return now }
+
+	assert.NoError(t, limiter.Allow(Login, "prover1"))
+	assert.ErrorIs(t, limiter.Allow(Login, "prover1"), ErrRateLimited)
+	assert.Len(t, limiter.buckets, 1)
+	assert.Len(t, limiter.violations, 1)
+
+	// the bucket is refilled and the violation window is over, both are dropped.
+	now = now.Add(time.Minute)
+	assert.NoError(t, limiter.Allow(Login, "prover2"))
+	assert.Len(t, limiter.buckets, 1)
+	assert.Empty(t, limiter.violations)
+}
@@ -2,6 +2,7 @@ package submitproof
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,11 +12,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
 
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/logic/verifier"
@@ -34,22 +39,43 @@ var (
 	ErrValidatorFailureProofTimeout = errors.New("validator failure submit proof timeout")
 	// ErrValidatorFailureTaskHaveVerifiedSuccess have proved success and verified success
 	ErrValidatorFailureTaskHaveVerifiedSuccess = errors.New("validator failure chunk/batch have proved and verified success")
+	// ErrValidatorFailureSubmissionUnsigned the submission is not signed while signed submissions are required
+	ErrValidatorFailureSubmissionUnsigned = errors.New("validator failure submission is not signed")
+	// ErrValidatorFailureSubmissionSignature the submission is not signed by the prover of the prover task
+	ErrValidatorFailureSubmissionSignature = errors.New("validator failure submission signature invalid")
+	// ErrValidatorFailureSubmissionReplayed the nonce of the submission is not higher than the prover's last one
+	ErrValidatorFailureSubmissionReplayed = errors.New("validator failure submission nonce is duplicate or stale")
 	// ErrValidatorFailureVerifiedFailed failed to verify and the verifier returns error
 	ErrValidatorFailureVerifiedFailed = fmt.Errorf("verification failed, verifier returns error")
 	// ErrValidatorSuccessInvalidProof successful verified and the proof is invalid
 	ErrValidatorSuccessInvalidProof = fmt.Errorf("verification succeeded, it's an invalid proof")
 	// ErrCoordinatorInternalFailure coordinator internal db failure
 	ErrCoordinatorInternalFailure = fmt.Errorf("coordinator internal error")
+
+	// submitResultErrors are the errors a submission settling its prover task can return, its duplicates
+	// get the same error back.
+	submitResultErrors = map[string]error{
+		ErrValidatorFailureProofMsgStatusNotOk.Error(): ErrValidatorFailureProofMsgStatusNotOk,
+		ErrValidatorFailureVerifiedFailed.Error():      ErrValidatorFailureVerifiedFailed,
+		ErrValidatorSuccessInvalidProof.Error():        ErrValidatorSuccessInvalidProof,
+		ErrCoordinatorInternalFailure.Error():          ErrCoordinatorInternalFailure,
+	}
 )
 
 // ProofReceiverLogic the proof receiver logic
 type ProofReceiverLogic struct {
-	chunkOrm      *orm.Chunk
-	batchOrm      *orm.Batch
-	proverTaskOrm *orm.ProverTask
+	chunkOrm         *orm.Chunk
+	batchOrm         *orm.Batch
+	proverTaskOrm    *orm.ProverTask
+	proverScoreOrm   *orm.ProverScore
+	chunkRowUsageOrm *orm.ChunkRowUsage
+	workReceiptOrm   *orm.WorkReceipt
 
 	db  *gorm.DB
-	cfg *config.ProverManager
+	cfg *config.Config
+
+	// receiptKey signs the work receipts of the accepted proofs, nil unless the marketplace is enabled.
+	receiptKey *ecdsa.PrivateKey
 
 	verifier *verifier.Verifier
 
@@ -57,24 +83,42 @@ type ProofReceiverLogic struct {
 	proofSubmitFailure                    prometheus.Counter
 	verifierTotal                         *prometheus.CounterVec
 	verifierFailureTotal                  *prometheus.CounterVec
-	proverTaskProveDuration               prometheus.Histogram
+	proverTaskProveDuration               *prometheus.HistogramVec
 	validateFailureTotal                  prometheus.Counter
 	validateFailureProverTaskSubmitTwice  prometheus.Counter
 	validateFailureProverTaskStatusNotOk  prometheus.Counter
+	proofErrorTotal                       *prometheus.CounterVec
+	proofTaskQuarantinedTotal             *prometheus.CounterVec
 	validateFailureProverTaskTimeout      prometheus.Counter
 	validateFailureProverTaskHaveVerifier prometheus.Counter
+	validateFailureSubmissionSignature    prometheus.Counter
+	validateFailureSubmissionReplayed     prometheus.Counter
+	duplicateSubmissionTotal              prometheus.Counter
 }
 
 // NewSubmitProofReceiverLogic create a proof receiver logic
-func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *verifier.Verifier, reg prometheus.Registerer) *ProofReceiverLogic {
+func NewSubmitProofReceiverLogic(cfg *config.Config, db *gorm.DB, proofStore objectstore.Store, vf *verifier.Verifier, reg prometheus.Registerer) *ProofReceiverLogic {
+	var receiptKey *ecdsa.PrivateKey
+	if cfg.ProverManager.Marketplace != nil {
+		var err error
+		if receiptKey, err = cfg.ProverManager.Marketplace.SigningKey(); err != nil {
+			log.Crit("failed to load the work receipt signing key", "error", err)
+		}
+	}
+
 	return &ProofReceiverLogic{
-		chunkOrm:      orm.NewChunk(db),
-		batchOrm:      orm.NewBatch(db),
-		proverTaskOrm: orm.NewProverTask(db),
+		chunkOrm:         orm.NewChunk(db).WithProofStore(proofStore),
+		batchOrm:         orm.NewBatch(db).WithProofStore(proofStore),
+		proverTaskOrm:    orm.NewProverTask(db),
+		proverScoreOrm:   orm.NewProverScore(db),
+		chunkRowUsageOrm: orm.NewChunkRowUsage(db),
+		workReceiptOrm:   orm.NewWorkReceipt(db),
 
 		cfg: cfg,
 		db:  db,
 
+		receiptKey: receiptKey,
+
 		verifier: vf,
 
 		proofReceivedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
@@ -93,11 +137,11 @@ func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *ver
 			Name: "coordinator_verifier_failure_total",
 			Help: "Total number of verifier failure.",
 		}, []string{"version"}),
-		proverTaskProveDuration: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+		proverTaskProveDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "coordinator_task_prove_duration_seconds",
 			Help:    "Time spend by prover prove task.",
 			Buckets: []float64{180, 300, 480, 600, 900, 1200, 1800},
-		}),
+		}, []string{"proof_type"}),
 		validateFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_validate_failure_total",
 			Help: "Total number of submit proof validate failure.",
@@ -110,6 +154,14 @@ func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *ver
 			Name: "coordinator_validate_failure_submit_status_not_ok",
 			Help: "Total number of submit proof validate failure proof status not ok.",
 		}),
+		proofErrorTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "coordinator_proof_error_total",
+			Help: "Total number of proofs the provers failed to generate, by proof type and error code.",
+		}, []string{"proof_type", "error_code"}),
+		proofTaskQuarantinedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "coordinator_proof_task_quarantined_total",
+			Help: "Total number of chunk/batch tasks quarantined because the provers failed them too many times.",
+		}, []string{"proof_type"}),
 		validateFailureProverTaskTimeout: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "coordinator_validate_failure_submit_timeout",
 			Help: "Total number of submit proof validate failure timeout.",
@@ -118,6 +170,18 @@ func NewSubmitProofReceiverLogic(cfg *config.ProverManager, db *gorm.DB, vf *ver
 			Name: "coordinator_validate_failure_submit_have_been_verifier",
 			Help: "Total number of submit proof validate failure proof have been verifier.",
 		}),
+		validateFailureSubmissionSignature: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_validate_failure_submission_signature_total",
+			Help: "Total number of submit proof validate failure unsigned or invalid signature.",
+		}),
+		validateFailureSubmissionReplayed: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_validate_failure_submission_replayed_total",
+			Help: "Total number of submit proof validate failure duplicate or stale nonce.",
+		}),
+		duplicateSubmissionTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "coordinator_submit_proof_duplicate_total",
+			Help: "Total number of duplicate submit proof answered with the original result.",
+		}),
 	}
 }
 
@@ -149,6 +213,14 @@ func (m *ProofReceiverLogic) HandleZkProof(ctx *gin.Context, proofMsg *message.P
 			log.Error("get none prover task for the proof", "uuid", proofParameter.UUID, "key", pk, "taskID", proofMsg.ID, "error", err)
 			return ErrValidatorFailureProverTaskEmpty
 		}
+		// A duplicate of the submission that settled the prover task, e.g. retried after a network failure,
+		// gets the original result back and is not handled again.
+		if proverTask.SubmitResult != nil {
+			m.duplicateSubmissionTotal.Inc()
+			log.Info("duplicate submission, return the original result", "uuid", proofParameter.UUID, "taskID", proofMsg.ID,
+				"proverName", proverTask.ProverName, "proverPublicKey", pk, "result", *proverTask.SubmitResult)
+			return submitResultError(*proverTask.SubmitResult)
+		}
 	} else {
 		// TODO When prover all have upgrade, need delete this logic
 		proverTask, err = m.proverTaskOrm.GetAssignedProverTaskByTaskIDAndProver(ctx.Copy(), proofMsg.Type, proofMsg.ID, pk, pv)
@@ -158,48 +230,67 @@ func (m *ProofReceiverLogic) HandleZkProof(ctx *gin.Context, proofMsg *message.P
 		}
 	}
 
+	// the proof is verified with the circuit of the hard fork the task was assigned for, whatever the prover claims.
+	if proverTask.HardForkName != "" {
+		if hardForkName != proverTask.HardForkName {
+			log.Warn("proof submitted for another hard fork than the task's", "uuid", proverTask.UUID, "taskID", proofMsg.ID,
+				"proverPublicKey", pk, "hardForkName", hardForkName, "taskHardForkName", proverTask.HardForkName)
+		}
+		hardForkName = proverTask.HardForkName
+	}
+
+	submitNonce, err := m.verifySubmission(ctx.Copy(), proverTask, pk, proofParameter)
+	if err != nil {
+		m.validateFailureTotal.Inc()
+		return err
+	}
+
 	proofTime := time.Since(proverTask.CreatedAt)
 	proofTimeSec := uint64(proofTime.Seconds())
 
-	log.Info("handling zk proof", "proofID", proofMsg.ID, "proverName", proverTask.ProverName,
+	logger := utils.TaskLogger(proverTask.TaskID, proverTask.UUID.String()).New(utils.LogKeyRequestID, ctx.GetString(coordinatorType.RequestID))
+	logger.Info("handling zk proof", "proofID", proofMsg.ID, "proverName", proverTask.ProverName,
 		"proverPublicKey", pk, "proveType", proverTask.TaskType, "proofTime", proofTimeSec, "hardForkName", hardForkName)
 
-	if err = m.validator(ctx.Copy(), proverTask, pk, proofMsg, proofParameter, hardForkName); err != nil {
+	if err = m.validator(ctx.Copy(), proverTask, pk, proofMsg, proofParameter, submitNonce, hardForkName); err != nil {
 		return err
 	}
 
 	m.verifierTotal.WithLabelValues(pv).Inc()
 
-	success := true
-	var verifyErr error
-	// only verify batch proof. chunk proof verifier have been disabled after Bernoulli
-	if proofMsg.Type == message.ProofTypeBatch {
-		success, verifyErr = m.verifier.VerifyBatchProof(proofMsg.BatchProof, hardForkName)
-	}
+	success, verifyErr := m.verifyProof(ctx.Copy(), proofMsg, hardForkName)
 
 	if verifyErr != nil || !success {
 		m.verifierFailureTotal.WithLabelValues(pv).Inc()
 
-		m.proofRecover(ctx.Copy(), proverTask, types.ProverTaskFailureTypeVerifiedFailed, proofMsg)
+		result := ErrValidatorSuccessInvalidProof
+		if verifyErr != nil {
+			result = ErrValidatorFailureVerifiedFailed
+		}
+		m.proofRecover(ctx.Copy(), proverTask, types.ProverTaskFailureTypeVerifiedFailed, message.ProofFailureUndefined, proofMsg, submitNonce, result)
 
-		log.Info("proof verified by coordinator failed", "proof id", proofMsg.ID, "prover name", proverTask.ProverName,
+		logger.Info("proof verified by coordinator failed", "proof id", proofMsg.ID, "prover name", proverTask.ProverName,
 			"prover pk", pk, "forkName", hardForkName, "prove type", proofMsg.Type, "proof time", proofTimeSec, "error", verifyErr)
 
-		if verifyErr != nil {
-			return ErrValidatorFailureVerifiedFailed
-		}
-		return ErrValidatorSuccessInvalidProof
+		return result
 	}
 
-	m.proverTaskProveDuration.Observe(time.Since(proverTask.CreatedAt).Seconds())
+	m.proverTaskProveDuration.WithLabelValues(proofMsg.Type.String()).Observe(time.Since(proverTask.CreatedAt).Seconds())
 
-	log.Info("proof verified and valid", "proof id", proofMsg.ID, "prover name", proverTask.ProverName,
+	logger.Info("proof verified and valid", "proof id", proofMsg.ID, "prover name", proverTask.ProverName,
 		"prover pk", pk, "prove type", proofMsg.Type, "proof time", proofTimeSec, "forkName", hardForkName)
 
-	if err := m.closeProofTask(ctx.Copy(), proverTask, proofMsg, proofTimeSec); err != nil {
+	proofHash := crypto.Keccak256Hash([]byte(proofParameter.Proof))
+	if err := m.closeProofTask(ctx.Copy(), proverTask, proofMsg, proofHash, proofTimeSec, submitNonce); err != nil {
+		// a concurrent submission with the same nonce has settled the prover task first.
+		if errors.Is(err, ErrValidatorFailureSubmissionReplayed) {
+			m.validateFailureSubmissionReplayed.Inc()
+			return err
+		}
+
 		m.proofSubmitFailure.Inc()
 
-		m.proofRecover(ctx.Copy(), proverTask, types.ProverTaskFailureTypeServerError, proofMsg)
+		m.proofRecover(ctx.Copy(), proverTask, types.ProverTaskFailureTypeServerError, message.ProofFailureUndefined, proofMsg, submitNonce, ErrCoordinatorInternalFailure)
 
 		return ErrCoordinatorInternalFailure
 	}
@@ -226,13 +317,93 @@ func (m *ProofReceiverLogic) checkAreAllChunkProofsReady(ctx context.Context, ch
 	return nil
 }
 
-func (m *ProofReceiverLogic) validator(ctx context.Context, proverTask *orm.ProverTask, pk string, proofMsg *message.ProofMsg, proofParameter coordinatorType.SubmitProofParameter, forkName string) (err error) {
+// verifyProof checks the format and the vk of the proof before running the verifier, a malformed
+// proof or a proof of an unregistered vk is an invalid proof. The chunk verifier has been disabled
+// after Bernoulli, so chunk proofs are only verified when enabled in the verifier config, but the
+// chunk info the prover computed the pi_hash from must match the chunk.
+func (m *ProofReceiverLogic) verifyProof(ctx context.Context, proofMsg *message.ProofMsg, hardForkName string) (bool, error) {
+	switch proofMsg.Type {
+	case message.ProofTypeChunk:
+		if err := proofMsg.ChunkProof.SanityCheck(); err != nil {
+			log.Info("chunk proof sanity check failed", "proof id", proofMsg.ID, "error", err)
+			return false, nil
+		}
+		if err := m.verifier.CheckVK(proofMsg.Type, proofMsg.ChunkProof.Vk, hardForkName); err != nil {
+			log.Info("chunk proof vk check failed", "proof id", proofMsg.ID, "error", err)
+			return false, nil
+		}
+		if valid, err := m.checkChunkInfo(ctx, proofMsg, hardForkName); !valid || err != nil {
+			return valid, err
+		}
+		if !m.verifier.VerifyChunkProofEnabled() {
+			return true, nil
+		}
+		return m.verifier.VerifyChunkProof(proofMsg.ChunkProof, hardForkName)
+	case message.ProofTypeBatch:
+		if err := proofMsg.BatchProof.SanityCheck(); err != nil {
+			log.Info("batch proof sanity check failed", "proof id", proofMsg.ID, "error", err)
+			return false, nil
+		}
+		if err := m.verifier.CheckVK(proofMsg.Type, proofMsg.BatchProof.Vk, hardForkName); err != nil {
+			log.Info("batch proof vk check failed", "proof id", proofMsg.ID, "error", err)
+			return false, nil
+		}
+		return m.verifier.VerifyBatchProof(proofMsg.BatchProof, hardForkName)
+	default:
+		return false, fmt.Errorf("unsupported proof type: %v", proofMsg.Type)
+	}
+}
+
+// checkChunkInfo compares the chunk info of the chunk proof with the one computed from the chunk,
+// the provers not reporting the chunk info and the hard forks without a known pi_hash layout are not checked.
+func (m *ProofReceiverLogic) checkChunkInfo(ctx context.Context, proofMsg *message.ProofMsg, hardForkName string) (bool, error) {
+	if proofMsg.ChunkProof.ChunkInfo == nil {
+		return true, nil
+	}
+
+	chunk, err := m.chunkOrm.GetChunkByHash(ctx, proofMsg.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chunk of the proof, err:%w", err)
+	}
+	version, err := message.GetPublicInputHashVersion(hardForkName, m.cfg.L2.PublicInputHashVersions)
+	if err != nil {
+		log.Warn("skip the chunk info check of the chunk proof", "proof id", proofMsg.ID, "forkName", hardForkName, "error", err)
+		return true, nil
+	}
+
+	expected := &message.ChunkInfo{
+		ChainID:       m.cfg.L2.ChainID,
+		PrevStateRoot: common.HexToHash(chunk.ParentChunkStateRoot),
+		PostStateRoot: common.HexToHash(chunk.StateRoot),
+		WithdrawRoot:  common.HexToHash(chunk.WithdrawRoot),
+		DataHash:      common.HexToHash(chunk.Hash),
+	}
+	if err := message.CheckChunkInfo(expected, proofMsg.ChunkProof.ChunkInfo, version); err != nil {
+		log.Info("chunk proof chunk info check failed", "proof id", proofMsg.ID, "forkName", hardForkName, "error", err)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *ProofReceiverLogic) validator(ctx context.Context, proverTask *orm.ProverTask, pk string, proofMsg *message.ProofMsg, proofParameter coordinatorType.SubmitProofParameter, submitNonce uint64, forkName string) (err error) {
 	defer func() {
 		if err != nil {
 			m.validateFailureTotal.Inc()
 		}
 	}()
 
+	proofTime := time.Since(proverTask.CreatedAt)
+	proofTimeSec := uint64(proofTime.Seconds())
+
+	// The late proof of a timed out prover task is rejected before anything else and without touching
+	// any state, so the original prover gets the same answer however many times it submits.
+	if m.isProverTaskTimeout(proverTask) {
+		m.validateFailureProverTaskTimeout.Inc()
+		log.Info("proof submit proof have timeout, skip this submit proof", "hash", proofMsg.ID, "taskType", proverTask.TaskType,
+			"proverName", proverTask.ProverName, "proverPublicKey", pk, "proofTime", proofTimeSec, "forkName", forkName)
+		return ErrValidatorFailureProofTimeout
+	}
+
 	// Ensure this prover is eligible to participate in the prover task.
 	if types.ProverProveStatus(proverTask.ProvingStatus) == types.ProverProofValid ||
 		types.ProverProveStatus(proverTask.ProvingStatus) == types.ProverProofInvalid {
@@ -250,32 +421,24 @@ func (m *ProofReceiverLogic) validator(ctx context.Context, proverTask *orm.Prov
 		return ErrValidatorFailureProverTaskCannotSubmitTwice
 	}
 
-	proofTime := time.Since(proverTask.CreatedAt)
-	proofTimeSec := uint64(proofTime.Seconds())
-
 	if proofMsg.Status != message.StatusOk {
 		// Temporarily replace "panic" with "pa-nic" to prevent triggering the alert based on logs.
 		failureMsg := strings.Replace(proofParameter.FailureMsg, "panic", "pa-nic", -1)
 
-		m.proofRecover(ctx, proverTask, types.ProverTaskFailureTypeSubmitStatusNotOk, proofMsg)
+		m.proofRecover(ctx, proverTask, types.ProverTaskFailureTypeSubmitStatusNotOk, message.ProofFailureType(proofParameter.FailureType), proofMsg, submitNonce, ErrValidatorFailureProofMsgStatusNotOk)
 
 		m.validateFailureProverTaskStatusNotOk.Inc()
+		// the unknown codes are counted as undefined, so the provers can't blow up the label cardinality.
+		m.proofErrorTotal.WithLabelValues(proofMsg.Type.String(), proofMsg.ErrorCode.String()).Inc()
 
 		log.Info("proof generated by prover failed",
 			"taskType", proofMsg.Type, "hash", proofMsg.ID, "proverName", proverTask.ProverName,
 			"proverVersion", proverTask.ProverVersion, "proverPublicKey", pk, "failureType", proofParameter.FailureType,
+			"errorCode", proofMsg.ErrorCode.String(), "errorDetails", proofMsg.ErrorDetails,
 			"failureMessage", failureMsg, "forkName", forkName)
 		return ErrValidatorFailureProofMsgStatusNotOk
 	}
 
-	// if prover task FailureType is SessionInfoFailureTimeout, the submit proof is timeout, need skip it
-	if types.ProverTaskFailureType(proverTask.FailureType) == types.ProverTaskFailureTypeTimeout {
-		m.validateFailureProverTaskTimeout.Inc()
-		log.Info("proof submit proof have timeout, skip this submit proof", "hash", proofMsg.ID, "taskType", proverTask.TaskType,
-			"proverName", proverTask.ProverName, "proverPublicKey", pk, "proofTime", proofTimeSec, "forkName", forkName)
-		return ErrValidatorFailureProofTimeout
-	}
-
 	// store the proof to prover task
 	if updateTaskProofErr := m.updateProverTaskProof(ctx, proverTask, proofMsg); updateTaskProofErr != nil {
 		log.Warn("update prover task proof failure", "hash", proofMsg.ID, "proverPublicKey", pk, "forkName", forkName,
@@ -292,46 +455,186 @@ func (m *ProofReceiverLogic) validator(ctx context.Context, proverTask *orm.Prov
 	return nil
 }
 
-func (m *ProofReceiverLogic) proofRecover(ctx context.Context, proverTask *orm.ProverTask, failureType types.ProverTaskFailureType, proofMsg *message.ProofMsg) {
+// verifySubmission checks the submission is signed by the prover of the prover task with a nonce higher than
+// the prover's last one, and returns the nonce. The nonce is recorded in the transaction settling the submission,
+// so the same submission, or an older one of the prover, is rejected when replayed. The unsigned submissions
+// of the provers not upgraded yet are accepted with a zero nonce unless require_signed_proof is set.
+func (m *ProofReceiverLogic) verifySubmission(ctx context.Context, proverTask *orm.ProverTask, pk string, proofParameter coordinatorType.SubmitProofParameter) (uint64, error) {
+	if proofParameter.Signature == "" {
+		if m.cfg.ProverManager.RequireSignedProof || m.receiptKey != nil {
+			m.validateFailureSubmissionSignature.Inc()
+			log.Info("unsigned submission rejected", "uuid", proverTask.UUID.String(), "proverName", proverTask.ProverName, "proverPublicKey", pk)
+			return 0, ErrValidatorFailureSubmissionUnsigned
+		}
+		return 0, nil
+	}
+
+	submission := message.ProofSubmission{
+		UUID:      proofParameter.UUID,
+		TaskID:    proofParameter.TaskID,
+		TaskType:  message.ProofType(proofParameter.TaskType),
+		Status:    message.RespStatus(proofParameter.Status),
+		ProofHash: crypto.Keccak256Hash([]byte(proofParameter.Proof)),
+		Nonce:     proofParameter.Nonce,
+	}
+	signer, err := submission.PublicKey(proofParameter.Signature)
+	if err != nil || signer != pk || proofParameter.UUID != proverTask.UUID.String() {
+		m.validateFailureSubmissionSignature.Inc()
+		log.Info("submission signature invalid", "uuid", proofParameter.UUID, "taskID", proofParameter.TaskID,
+			"proverName", proverTask.ProverName, "proverPublicKey", pk, "signer", signer, "error", err)
+		return 0, ErrValidatorFailureSubmissionSignature
+	}
+
+	maxNonce, err := m.proverTaskOrm.GetMaxSubmitNonceByPublicKey(ctx, pk)
+	if err != nil {
+		return 0, ErrCoordinatorInternalFailure
+	}
+	if proofParameter.Nonce <= maxNonce {
+		m.validateFailureSubmissionReplayed.Inc()
+		log.Warn("submission nonce is duplicate or stale", "uuid", proofParameter.UUID, "taskID", proofParameter.TaskID,
+			"proverName", proverTask.ProverName, "proverPublicKey", pk, "nonce", proofParameter.Nonce, "maxNonce", maxNonce)
+		return 0, ErrValidatorFailureSubmissionReplayed
+	}
+	return proofParameter.Nonce, nil
+}
+
+// isProverTaskTimeout checks whether the prover task has been timed out by the cron, or has passed its
+// deadline and is waiting for the cron to reassign it.
+func (m *ProofReceiverLogic) isProverTaskTimeout(proverTask *orm.ProverTask) bool {
+	if types.ProverTaskFailureType(proverTask.FailureType) == types.ProverTaskFailureTypeTimeout {
+		return true
+	}
+
+	if types.ProverProveStatus(proverTask.ProvingStatus) != types.ProverAssigned {
+		return false
+	}
+
+	if proverTask.Deadline != nil {
+		return time.Now().After(*proverTask.Deadline)
+	}
+
+	var collectionTimeSec int
+	switch message.ProofType(proverTask.TaskType) {
+	case message.ProofTypeChunk:
+		collectionTimeSec = m.cfg.ProverManager.ChunkCollectionTimeSec
+	case message.ProofTypeBatch:
+		collectionTimeSec = m.cfg.ProverManager.BatchCollectionTimeSec
+	default:
+		return false
+	}
+	return time.Since(proverTask.AssignedAt) > time.Duration(collectionTimeSec)*time.Second
+}
+
+func (m *ProofReceiverLogic) proofRecover(ctx context.Context, proverTask *orm.ProverTask, failureType types.ProverTaskFailureType, proofFailureType message.ProofFailureType, proofMsg *message.ProofMsg, submitNonce uint64, result error) {
 	log.Info("proof recover update proof status", "hash", proverTask.TaskID, "proverPublicKey", proverTask.ProverPublicKey,
 		"taskType", message.ProofType(proverTask.TaskType).String(), "status", types.ProvingTaskUnassigned.String())
 
-	if err := m.updateProofStatus(ctx, proverTask, proofMsg, types.ProverProofInvalid, failureType, 0); err != nil {
+	if err := m.updateProofStatus(ctx, proverTask, proofMsg, common.Hash{}, types.ProverProofInvalid, failureType, proofFailureType, 0, submitNonce, result); err != nil {
 		log.Error("failed to updated proof status ProvingTaskUnassigned", "hash", proverTask.TaskID, "pubKey", proverTask.ProverPublicKey, "error", err)
 	}
 }
 
-func (m *ProofReceiverLogic) closeProofTask(ctx context.Context, proverTask *orm.ProverTask, proofMsg *message.ProofMsg, proofTimeSec uint64) error {
+func (m *ProofReceiverLogic) closeProofTask(ctx context.Context, proverTask *orm.ProverTask, proofMsg *message.ProofMsg, proofHash common.Hash, proofTimeSec uint64, submitNonce uint64) error {
 	log.Info("proof close task update proof status", "hash", proverTask.TaskID, "proverPublicKey", proverTask.ProverPublicKey,
 		"taskType", message.ProofType(proverTask.TaskType).String(), "status", types.ProvingTaskVerified.String())
 
-	if err := m.updateProofStatus(ctx, proverTask, proofMsg, types.ProverProofValid, types.ProverTaskFailureTypeUndefined, proofTimeSec); err != nil {
+	if err := m.updateProofStatus(ctx, proverTask, proofMsg, proofHash, types.ProverProofValid, types.ProverTaskFailureTypeUndefined, message.ProofFailureUndefined, proofTimeSec, submitNonce, nil); err != nil {
 		log.Error("failed to updated proof status ProvingTaskVerified", "hash", proverTask.TaskID, "proverPublicKey", proverTask.ProverPublicKey, "error", err)
 		return err
 	}
 	return nil
 }
 
-// UpdateProofStatus update the chunk/batch task and session info status
+// UpdateProofStatus update the chunk/batch task and session info status, and records the result returned to the submission
+// along with the nonce of a signed submission. The work receipt of an accepted proof is recorded with the proof hash if the
+// marketplace is enabled.
 func (m *ProofReceiverLogic) updateProofStatus(ctx context.Context, proverTask *orm.ProverTask,
-	proofMsg *message.ProofMsg, status types.ProverProveStatus, failureType types.ProverTaskFailureType, proofTimeSec uint64) error {
+	proofMsg *message.ProofMsg, proofHash common.Hash, status types.ProverProveStatus, failureType types.ProverTaskFailureType, proofFailureType message.ProofFailureType, proofTimeSec uint64, submitNonce uint64, result error) error {
+	var quarantined bool
 	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if submitNonce != 0 {
+			// the conditional update settles concurrent submissions of the same task.
+			updated, updateErr := m.proverTaskOrm.UpdateProverTaskSubmitNonce(ctx, proverTask.UUID, submitNonce, tx)
+			if updateErr != nil {
+				log.Error("failed to update prover task submit nonce", "uuid", proverTask.UUID, "error", updateErr)
+				return updateErr
+			}
+			if !updated {
+				return ErrValidatorFailureSubmissionReplayed
+			}
+		}
+
 		if updateErr := m.proverTaskOrm.UpdateProverTaskProvingStatusAndFailureType(ctx, proverTask.UUID, status, failureType, tx); updateErr != nil {
 			log.Error("failed to update prover task proving status and failure type", "uuid", proverTask.UUID, "error", updateErr)
 			return updateErr
 		}
 
+		var submitResult string
+		if result != nil {
+			submitResult = result.Error()
+		}
+		if updateErr := m.proverTaskOrm.UpdateProverTaskSubmitResult(ctx, proverTask.UUID, submitResult, tx); updateErr != nil {
+			log.Error("failed to update prover task submit result", "uuid", proverTask.UUID, "error", updateErr)
+			return updateErr
+		}
+
+		if status == types.ProverProofValid {
+			if updateErr := m.proverTaskOrm.UpdateProverTaskProvingTime(ctx, proverTask.UUID, proofTimeSec, tx); updateErr != nil {
+				log.Error("failed to update prover task proving time", "uuid", proverTask.UUID, "error", updateErr)
+				return updateErr
+			}
+		}
+
+		var scoreErr error
+		if status == types.ProverProofValid {
+			scoreErr = m.proverScoreOrm.IncreaseSuccess(ctx, proverTask.ProverPublicKey, proverTask.ProverName, proofTimeSec, tx)
+		} else {
+			scoreErr = m.proverScoreOrm.IncreaseFailure(ctx, proverTask.ProverPublicKey, proverTask.ProverName, failureType, proofFailureType, tx)
+		}
+		if scoreErr != nil {
+			log.Error("failed to update prover score", "uuid", proverTask.UUID, "public key", proverTask.ProverPublicKey, "error", scoreErr)
+			return scoreErr
+		}
+
 		switch proofMsg.Type {
 		case message.ProofTypeChunk:
 			if err := m.chunkOrm.DecreaseActiveAttemptsByHash(ctx, proverTask.TaskID, tx); err != nil {
 				log.Error("failed to update chunk proving_status as failed", "hash", proverTask.TaskID, "error", err)
 				return err
 			}
+			if status != types.ProverProofValid {
+				if err := m.chunkOrm.IncreaseFailedAttemptsByHash(ctx, proverTask.TaskID, tx); err != nil {
+					log.Error("failed to increase chunk failed attempts", "hash", proverTask.TaskID, "error", err)
+					return err
+				}
+				if failureType == types.ProverTaskFailureTypeSubmitStatusNotOk {
+					var quarantineErr error
+					quarantined, quarantineErr = m.recordProofFailureChunk(ctx, proverTask.TaskID, proofFailureType, tx)
+					if quarantineErr != nil {
+						log.Error("failed to record chunk proof failure", "hash", proverTask.TaskID, "proof failure type", proofFailureType, "error", quarantineErr)
+						return quarantineErr
+					}
+				}
+			}
 		case message.ProofTypeBatch:
 			if err := m.batchOrm.DecreaseActiveAttemptsByHash(ctx, proverTask.TaskID, tx); err != nil {
 				log.Error("failed to update batch proving_status as failed", "hash", proverTask.TaskID, "error", err)
 				return err
 			}
+			if status != types.ProverProofValid {
+				if err := m.batchOrm.IncreaseFailedAttemptsByHash(ctx, proverTask.TaskID, tx); err != nil {
+					log.Error("failed to increase batch failed attempts", "hash", proverTask.TaskID, "error", err)
+					return err
+				}
+				if failureType == types.ProverTaskFailureTypeSubmitStatusNotOk {
+					var quarantineErr error
+					quarantined, quarantineErr = m.recordProofFailureBatch(ctx, proverTask.TaskID, proofFailureType, tx)
+					if quarantineErr != nil {
+						log.Error("failed to record batch proof failure", "hash", proverTask.TaskID, "proof failure type", proofFailureType, "error", quarantineErr)
+						return quarantineErr
+					}
+				}
+			}
 		}
 
 		// if the block batch has proof verified, so the failed status not update block batch proving status
@@ -352,6 +655,20 @@ func (m *ProofReceiverLogic) updateProofStatus(ctx context.Context, proverTask *
 				log.Error("failed to store chunk/batch proof and proving status", "hash", proverTask.TaskID, "public key", proverTask.ProverPublicKey, "error", storeProofErr)
 				return storeProofErr
 			}
+
+			if proofMsg.Type == message.ProofTypeChunk && len(proofMsg.ChunkProof.RowUsages) > 0 {
+				if err := m.storeChunkRowUsages(ctx, proofMsg, tx); err != nil {
+					log.Error("failed to store chunk row usages", "hash", proverTask.TaskID, "public key", proverTask.ProverPublicKey, "error", err)
+					return err
+				}
+			}
+
+			if m.receiptKey != nil {
+				if err := m.storeWorkReceipt(ctx, proverTask, proofHash, proofTimeSec, tx); err != nil {
+					log.Error("failed to store work receipt", "hash", proverTask.TaskID, "public key", proverTask.ProverPublicKey, "error", err)
+					return err
+				}
+			}
 		}
 		return nil
 	})
@@ -360,6 +677,12 @@ func (m *ProofReceiverLogic) updateProofStatus(ctx context.Context, proverTask *
 		return err
 	}
 
+	if quarantined {
+		m.proofTaskQuarantinedTotal.WithLabelValues(proofMsg.Type.String()).Inc()
+		log.Error("proof task quarantined, the provers failed it too many times, reset it with the task_reset admin api once fixed",
+			"taskType", proofMsg.Type, "hash", proverTask.TaskID, "proofFailureType", proofFailureType)
+	}
+
 	if status == types.ProverProofValid && proofMsg.Type == message.ProofTypeChunk {
 		if checkReadyErr := m.checkAreAllChunkProofsReady(ctx, proverTask.TaskID); checkReadyErr != nil {
 			log.Error("failed to check are all chunk proofs ready", "error", checkReadyErr)
@@ -370,6 +693,61 @@ func (m *ProofReceiverLogic) updateProofStatus(ctx context.Context, proverTask *
 	return nil
 }
 
+// recordProofFailureChunk counts the proof failure of the chunk by its type, and quarantines the chunk once the
+// failures of the type reach their max. It returns whether the chunk is quarantined.
+func (m *ProofReceiverLogic) recordProofFailureChunk(ctx context.Context, hash string, proofFailureType message.ProofFailureType, tx *gorm.DB) (bool, error) {
+	if err := m.chunkOrm.IncreaseProofFailedAttemptsByHash(ctx, hash, proofFailureType, tx); err != nil {
+		return false, err
+	}
+	retryCfg := m.cfg.ProverManager.ProofFailureRetry
+	if retryCfg == nil {
+		return false, nil
+	}
+	return m.chunkOrm.QuarantineByHash(ctx, hash, retryCfg.MaxPanicFailures, retryCfg.MaxNoPanicFailures, tx)
+}
+
+// recordProofFailureBatch counts the proof failure of the batch by its type, and quarantines the batch once the
+// failures of the type reach their max. It returns whether the batch is quarantined.
+func (m *ProofReceiverLogic) recordProofFailureBatch(ctx context.Context, hash string, proofFailureType message.ProofFailureType, tx *gorm.DB) (bool, error) {
+	if err := m.batchOrm.IncreaseProofFailedAttemptsByHash(ctx, hash, proofFailureType, tx); err != nil {
+		return false, err
+	}
+	retryCfg := m.cfg.ProverManager.ProofFailureRetry
+	if retryCfg == nil {
+		return false, nil
+	}
+	return m.batchOrm.QuarantineByHash(ctx, hash, retryCfg.MaxPanicFailures, retryCfg.MaxNoPanicFailures, tx)
+}
+
+// storeChunkRowUsages records the sub-circuit row usages of the accepted chunk proof for the capacity reports.
+func (m *ProofReceiverLogic) storeChunkRowUsages(ctx context.Context, proofMsg *message.ProofMsg, tx *gorm.DB) error {
+	chunk, err := m.chunkOrm.GetChunkByHash(ctx, proofMsg.ID)
+	if err != nil {
+		return err
+	}
+	return m.chunkRowUsageOrm.InsertChunkRowUsages(ctx, chunk, proofMsg.ChunkProof.RowUsages, tx)
+}
+
+// storeWorkReceipt signs and records the receipt of the accepted proof, which the provers are paid for.
+func (m *ProofReceiverLogic) storeWorkReceipt(ctx context.Context, proverTask *orm.ProverTask, proofHash common.Hash, proofTimeSec uint64, tx *gorm.DB) error {
+	receipt := &orm.WorkReceipt{
+		UUID:            proverTask.UUID.String(),
+		TaskID:          proverTask.TaskID,
+		TaskType:        proverTask.TaskType,
+		ProverPublicKey: proverTask.ProverPublicKey,
+		ProverName:      proverTask.ProverName,
+		ProofHash:       proofHash.String(),
+		ProvingTimeSec:  proofTimeSec,
+		AcceptedAt:      utils.NowUTC().Truncate(time.Second),
+	}
+	signature, err := receipt.Message().Sign(m.receiptKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign work receipt: %w", err)
+	}
+	receipt.Signature = signature
+	return m.workReceiptOrm.InsertWorkReceipt(ctx, receipt, tx)
+}
+
 func (m *ProofReceiverLogic) checkIsTaskSuccess(ctx context.Context, hash string, proofType message.ProofType) bool {
 	var provingStatus types.ProvingStatus
 	var err error
@@ -406,3 +784,14 @@ func (m *ProofReceiverLogic) updateProverTaskProof(ctx context.Context, proverTa
 	}
 	return m.proverTaskOrm.UpdateProverTaskProof(ctx, proverTask.UUID, proofBytes)
 }
+
+// submitResultError returns the error recorded as the result of a submission, nil for a success.
+func submitResultError(result string) error {
+	if result == "" {
+		return nil
+	}
+	if err, ok := submitResultErrors[result]; ok {
+		return err
+	}
+	return errors.New(result)
+}
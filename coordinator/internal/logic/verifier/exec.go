@@ -0,0 +1,73 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+// InvalidProofExitCode is the exit code of proof_verifier for a proof failing the verification,
+// other non-zero exit codes are failures to verify the proof.
+const InvalidProofExitCode = 2
+
+// defaultExecTimeout bounds a verification of an exec backend.
+const defaultExecTimeout = 2 * time.Minute
+
+// execBackend verifies the proofs by running the proof_verifier binary built against another circuit version.
+type execBackend struct {
+	cfg     *config.VerifierBackendConfig
+	timeout time.Duration
+}
+
+// VerifyChunkProof verifies the chunk proof with the proof_verifier binary.
+func (b *execBackend) VerifyChunkProof(proof *message.ChunkProof, forkName string) (bool, error) {
+	return b.verify("chunk", proof, forkName)
+}
+
+// VerifyBatchProof verifies the batch proof with the proof_verifier binary.
+func (b *execBackend) VerifyBatchProof(proof *message.BatchProof, forkName string) (bool, error) {
+	return b.verify("batch", proof, forkName)
+}
+
+func (b *execBackend) verify(proofType string, proof interface{}, forkName string) (bool, error) {
+	buf, err := json.Marshal(proof)
+	if err != nil {
+		return false, err
+	}
+	f, err := os.CreateTemp("", "proof-*.json")
+	if err != nil {
+		return false, fmt.Errorf("failed to create proof file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if _, err = f.Write(buf); err != nil {
+		_ = f.Close()
+		return false, fmt.Errorf("failed to write proof file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return false, fmt.Errorf("failed to write proof file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, b.cfg.Binary, "--proof.type", proofType, "--proof", f.Name(),
+		"--params", b.cfg.ParamsPath, "--assets", b.cfg.AssetsPath, "--fork", forkName)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == InvalidProofExitCode {
+		return false, nil
+	}
+	return false, fmt.Errorf("%s failed to verify %s proof: %w, output: %s", b.cfg.Binary, proofType, err, output)
+}
@@ -1,15 +1,177 @@
 package verifier
 
 import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"scroll-tech/common/types/message"
+
 	"scroll-tech/coordinator/internal/config"
 )
 
 // InvalidTestProof invalid proof used in tests
 const InvalidTestProof = "this is a invalid proof"
 
-// Verifier represents a rust ffi to a halo2 verifier.
+// mockForkNames are the hard forks a mock verifier registers an empty vk for, besides the configured one.
+var mockForkNames = []string{"shanghai", "bernoulli", "london", "istanbul", "homestead", "eip155"}
+
+// Backend verifies the proofs of one circuit version.
+type Backend interface {
+	VerifyChunkProof(proof *message.ChunkProof, forkName string) (bool, error)
+	VerifyBatchProof(proof *message.BatchProof, forkName string) (bool, error)
+}
+
+// Verifier verifies the proofs with the backend of their hard fork, so two circuit versions can be
+// verified side by side during a hard fork upgrade.
 type Verifier struct {
 	cfg        *config.VerifierConfig
 	ChunkVKMap map[string]string
 	BatchVKMap map[string]string
+
+	// backend verifies the proofs of the hard forks without a backend of their own.
+	backend Backend
+	// forkBackends are the backends of the other circuit versions, by hard fork name.
+	forkBackends map[string]Backend
+}
+
+// newMockVerifier creates a verifier accepting any vk and any proof but InvalidTestProof.
+func newMockVerifier(cfg *config.VerifierConfig) *Verifier {
+	v := &Verifier{
+		cfg:        cfg,
+		ChunkVKMap: make(map[string]string),
+		BatchVKMap: make(map[string]string),
+		backend:    mockBackend{},
+	}
+	for _, forkName := range append(mockForkNames, cfg.ForkName) {
+		v.ChunkVKMap[forkName] = ""
+		v.BatchVKMap[forkName] = ""
+	}
+	return v
+}
+
+// setupBackends creates the backends configured for the other circuit versions, and registers the vks of their hard forks.
+func (v *Verifier) setupBackends() error {
+	v.forkBackends = make(map[string]Backend)
+	for _, backendCfg := range v.cfg.Backends {
+		var (
+			backend          Backend
+			chunkVK, batchVK string
+		)
+		switch backendCfg.Type {
+		case config.VerifierBackendMock:
+			backend = mockBackend{}
+		case config.VerifierBackendExec:
+			if backendCfg.Binary == "" {
+				return fmt.Errorf("verifier backend of hard forks %v has no binary", backendCfg.ForkNames)
+			}
+			var err error
+			if batchVK, err = readVK(path.Join(backendCfg.AssetsPath, "agg_vk.vkey")); err != nil {
+				return err
+			}
+			if chunkVK, err = readVK(path.Join(backendCfg.AssetsPath, "chunk_vk.vkey")); err != nil {
+				return err
+			}
+			timeout := defaultExecTimeout
+			if backendCfg.TimeoutSec > 0 {
+				timeout = time.Duration(backendCfg.TimeoutSec) * time.Second
+			}
+			backend = &execBackend{cfg: backendCfg, timeout: timeout}
+		default:
+			return fmt.Errorf("unsupported verifier backend type: %q", backendCfg.Type)
+		}
+
+		for _, forkName := range backendCfg.ForkNames {
+			if forkName == v.cfg.ForkName {
+				return fmt.Errorf("hard fork %s is verified by the built-in verifier, it can't have a verifier backend", forkName)
+			}
+			if _, ok := v.forkBackends[forkName]; ok {
+				return fmt.Errorf("hard fork %s has more than one verifier backend", forkName)
+			}
+			v.forkBackends[forkName] = backend
+			v.ChunkVKMap[forkName] = chunkVK
+			v.BatchVKMap[forkName] = batchVK
+		}
+	}
+	return nil
+}
+
+// backendOf returns the backend verifying the proofs of the hard fork.
+func (v *Verifier) backendOf(forkName string) Backend {
+	if backend, ok := v.forkBackends[forkName]; ok {
+		return backend
+	}
+	return v.backend
+}
+
+// VerifyChunkProof verifies the chunk proof with the backend of the hard fork.
+func (v *Verifier) VerifyChunkProof(proof *message.ChunkProof, forkName string) (bool, error) {
+	return v.backendOf(forkName).VerifyChunkProof(proof, forkName)
+}
+
+// VerifyBatchProof verifies the batch proof with the backend of the hard fork.
+func (v *Verifier) VerifyBatchProof(proof *message.BatchProof, forkName string) (bool, error) {
+	return v.backendOf(forkName).VerifyBatchProof(proof, forkName)
+}
+
+// CheckVK checks the vk carried by a proof against the vk registered for the hard fork.
+// An empty registered vk (e.g. in mock mode) accepts any vk.
+func (v *Verifier) CheckVK(proofType message.ProofType, vk []byte, forkName string) error {
+	var vkMap map[string]string
+	switch proofType {
+	case message.ProofTypeChunk:
+		vkMap = v.ChunkVKMap
+	case message.ProofTypeBatch:
+		vkMap = v.BatchVKMap
+	default:
+		return fmt.Errorf("unsupported proof type: %v", proofType)
+	}
+
+	expectedVK, ok := vkMap[forkName]
+	if !ok {
+		return fmt.Errorf("no %s vk registered for hard fork %s", proofType.String(), forkName)
+	}
+	if expectedVK == "" {
+		return nil
+	}
+	if base64.StdEncoding.EncodeToString(vk) != expectedVK {
+		return fmt.Errorf("%s vk mismatch for hard fork %s", proofType.String(), forkName)
+	}
+	return nil
+}
+
+// VerifyChunkProofEnabled returns whether chunk proofs are verified by the coordinator, not only vk-checked.
+func (v *Verifier) VerifyChunkProofEnabled() bool {
+	return v.cfg != nil && v.cfg.VerifyChunkProof
+}
+
+// mockBackend accepts any proof but InvalidTestProof.
+type mockBackend struct{}
+
+// VerifyChunkProof return a mock verification result for a ChunkProof.
+func (mockBackend) VerifyChunkProof(proof *message.ChunkProof, _ string) (bool, error) {
+	return string(proof.Proof) != InvalidTestProof, nil
+}
+
+// VerifyBatchProof return a mock verification result for a BatchProof.
+func (mockBackend) VerifyBatchProof(proof *message.BatchProof, _ string) (bool, error) {
+	return string(proof.Proof) != InvalidTestProof, nil
+}
+
+func readVK(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	byt, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(byt), nil
 }
@@ -0,0 +1,72 @@
+package verifier
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types/message"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+func TestCheckVK(t *testing.T) {
+	v := &Verifier{
+		ChunkVKMap: map[string]string{"bernoulli": "", "curie": base64.StdEncoding.EncodeToString([]byte("chunk vk"))},
+		BatchVKMap: map[string]string{"curie": base64.StdEncoding.EncodeToString([]byte("batch vk"))},
+	}
+
+	assert.NoError(t, v.CheckVK(message.ProofTypeChunk, []byte("any vk"), "bernoulli"))
+	assert.NoError(t, v.CheckVK(message.ProofTypeChunk, []byte("chunk vk"), "curie"))
+	assert.Error(t, v.CheckVK(message.ProofTypeChunk, []byte("batch vk"), "curie"))
+	assert.NoError(t, v.CheckVK(message.ProofTypeBatch, []byte("batch vk"), "curie"))
+	assert.Error(t, v.CheckVK(message.ProofTypeBatch, []byte("batch vk"), "bernoulli"))
+	assert.Error(t, v.CheckVK(message.ProofTypeUndefined, nil, "curie"))
+}
+
+func TestVerifierBackends(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "agg_vk.vkey"), []byte("batch vk"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "chunk_vk.vkey"), []byte("chunk vk"), 0600))
+	invalidBinary := filepath.Join(dir, "invalid_verifier")
+	assert.NoError(t, os.WriteFile(invalidBinary, []byte("#!/bin/sh\nexit 2\n"), 0700))
+	failingBinary := filepath.Join(dir, "failing_verifier")
+	assert.NoError(t, os.WriteFile(failingBinary, []byte("#!/bin/sh\nexit 1\n"), 0700))
+
+	v := newMockVerifier(&config.VerifierConfig{
+		ForkName: "darwin",
+		Backends: []*config.VerifierBackendConfig{
+			{ForkNames: []string{"curie"}, Type: config.VerifierBackendExec, Binary: invalidBinary, AssetsPath: dir},
+			{ForkNames: []string{"bernoulli"}, Type: config.VerifierBackendExec, Binary: failingBinary, AssetsPath: dir},
+		},
+	})
+	assert.NoError(t, v.setupBackends())
+
+	// the vks of the backend hard forks are read from their assets
+	assert.NoError(t, v.CheckVK(message.ProofTypeBatch, []byte("batch vk"), "curie"))
+	assert.Error(t, v.CheckVK(message.ProofTypeBatch, []byte("other vk"), "curie"))
+	assert.NoError(t, v.CheckVK(message.ProofTypeChunk, []byte("any vk"), "darwin"))
+
+	proof := &message.BatchProof{Proof: []byte("proof")}
+	verified, err := v.VerifyBatchProof(proof, "darwin")
+	assert.NoError(t, err)
+	assert.True(t, verified)
+	verified, err = v.VerifyBatchProof(proof, "curie")
+	assert.NoError(t, err)
+	assert.False(t, verified)
+	_, err = v.VerifyChunkProof(&message.ChunkProof{Proof: []byte("proof")}, "bernoulli")
+	assert.Error(t, err)
+
+	for _, backends := range [][]*config.VerifierBackendConfig{
+		{{ForkNames: []string{"curie"}, Type: "http"}},
+		{{ForkNames: []string{"curie"}, Type: config.VerifierBackendExec, AssetsPath: dir}},
+		{{ForkNames: []string{"darwin"}, Type: config.VerifierBackendMock}},
+		{{ForkNames: []string{"curie"}, Type: config.VerifierBackendMock}, {ForkNames: []string{"curie"}, Type: config.VerifierBackendMock}},
+	} {
+		v = newMockVerifier(&config.VerifierConfig{ForkName: "darwin", Backends: backends})
+		assert.Error(t, v.setupBackends())
+	}
+}
@@ -14,9 +14,7 @@ import (
 	"embed"
 	"encoding/base64"
 	"encoding/json"
-	"io"
 	"io/fs"
-	"os"
 	"path"
 	"unsafe"
 
@@ -30,26 +28,11 @@ import (
 // NewVerifier Sets up a rust ffi to call verify.
 func NewVerifier(cfg *config.VerifierConfig) (*Verifier, error) {
 	if cfg.MockMode {
-		batchVKMap := map[string]string{
-			"shanghai":  "",
-			"bernoulli": "",
-			"london":    "",
-			"istanbul":  "",
-			"homestead": "",
-			"eip155":    "",
+		v := newMockVerifier(cfg)
+		if err := v.setupBackends(); err != nil {
+			return nil, err
 		}
-		chunkVKMap := map[string]string{
-			"shanghai":  "",
-			"bernoulli": "",
-			"london":    "",
-			"istanbul":  "",
-			"homestead": "",
-			"eip155":    "",
-		}
-
-		batchVKMap[cfg.ForkName] = ""
-		chunkVKMap[cfg.ForkName] = ""
-		return &Verifier{cfg: cfg, ChunkVKMap: chunkVKMap, BatchVKMap: batchVKMap}, nil
+		return v, nil
 	}
 	paramsPathStr := C.CString(cfg.ParamsPath)
 	assetsPathStr := C.CString(cfg.AssetsPath)
@@ -65,13 +48,14 @@ func NewVerifier(cfg *config.VerifierConfig) (*Verifier, error) {
 		cfg:        cfg,
 		ChunkVKMap: make(map[string]string),
 		BatchVKMap: make(map[string]string),
+		backend:    ffiBackend{},
 	}
 
-	batchVK, err := v.readVK(path.Join(cfg.AssetsPath, "agg_vk.vkey"))
+	batchVK, err := readVK(path.Join(cfg.AssetsPath, "agg_vk.vkey"))
 	if err != nil {
 		return nil, err
 	}
-	chunkVK, err := v.readVK(path.Join(cfg.AssetsPath, "chunk_vk.vkey"))
+	chunkVK, err := readVK(path.Join(cfg.AssetsPath, "chunk_vk.vkey"))
 	if err != nil {
 		return nil, err
 	}
@@ -81,19 +65,18 @@ func NewVerifier(cfg *config.VerifierConfig) (*Verifier, error) {
 	if err := v.loadEmbedVK(); err != nil {
 		return nil, err
 	}
+	if err := v.setupBackends(); err != nil {
+		return nil, err
+	}
 	return v, nil
 }
 
-// VerifyBatchProof Verify a ZkProof by marshaling it and sending it to the Halo2 Verifier.
-func (v *Verifier) VerifyBatchProof(proof *message.BatchProof, forkName string) (bool, error) {
-	if v.cfg.MockMode {
-		log.Info("Mock mode, batch verifier disabled")
-		if string(proof.Proof) == InvalidTestProof {
-			return false, nil
-		}
-		return true, nil
+// ffiBackend verifies the proofs with the halo2 verifier linked into the coordinator, only one circuit
+// version can be loaded per process.
+type ffiBackend struct{}
 
-	}
+// VerifyBatchProof Verify a ZkProof by marshaling it and sending it to the Halo2 Verifier.
+func (ffiBackend) VerifyBatchProof(proof *message.BatchProof, forkName string) (bool, error) {
 	buf, err := json.Marshal(proof)
 	if err != nil {
 		return false, err
@@ -112,15 +95,7 @@ func (v *Verifier) VerifyBatchProof(proof *message.BatchProof, forkName string)
 }
 
 // VerifyChunkProof Verify a ZkProof by marshaling it and sending it to the Halo2 Verifier.
-func (v *Verifier) VerifyChunkProof(proof *message.ChunkProof) (bool, error) {
-	if v.cfg.MockMode {
-		log.Info("Mock mode, verifier disabled")
-		if string(proof.Proof) == InvalidTestProof {
-			return false, nil
-		}
-		return true, nil
-
-	}
+func (ffiBackend) VerifyChunkProof(proof *message.ChunkProof, _ string) (bool, error) {
 	buf, err := json.Marshal(proof)
 	if err != nil {
 		return false, err
@@ -136,18 +111,6 @@ func (v *Verifier) VerifyChunkProof(proof *message.ChunkProof) (bool, error) {
 	return verified != 0, nil
 }
 
-func (v *Verifier) readVK(filePat string) (string, error) {
-	f, err := os.Open(filePat)
-	if err != nil {
-		return "", err
-	}
-	byt, err := io.ReadAll(f)
-	if err != nil {
-		return "", err
-	}
-	return base64.StdEncoding.EncodeToString(byt), nil
-}
-
 //go:embed legacy_vk/*
 var legacyVKFS embed.FS
 
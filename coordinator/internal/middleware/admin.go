@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+// AdminMiddleware checks the "Authorization: Bearer <secret>" header of the admin api
+func AdminMiddleware(conf *config.Config) gin.HandlerFunc {
+	expected := []byte("Bearer " + conf.Admin.Secret)
+	return func(c *gin.Context) {
+		if conf.Admin.Secret == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			types.RenderFailure(c, types.ErrCoordinatorAdminUnauthorized, errors.New("invalid admin secret"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -31,6 +31,7 @@ func ChallengeMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
 		},
 		Unauthorized:  unauthorized,
 		Key:           []byte(conf.Auth.Secret),
+		KeyFunc:       keyFunc(conf.Auth),
 		Timeout:       time.Second * time.Duration(conf.Auth.ChallengeExpireDurationSec),
 		TokenLookup:   "header: Authorization, query: token, cookie: jwt",
 		TokenHeadName: "Bearer",
@@ -7,12 +7,39 @@ import (
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v4"
 
 	"scroll-tech/common/types"
 
+	"scroll-tech/coordinator/internal/config"
 	coordinatorType "scroll-tech/coordinator/internal/types"
 )
 
+// keyFunc returns the secret a token has been signed with, tokens are signed with the current
+// secret and the previous secrets are kept so the rotation doesn't log out the provers.
+func keyFunc(conf *config.Auth) func(token *gojwt.Token) (interface{}, error) {
+	keys := [][]byte{[]byte(conf.Secret)}
+	for _, secret := range conf.PreviousSecrets {
+		keys = append(keys, []byte(secret))
+	}
+
+	return func(token *gojwt.Token) (interface{}, error) {
+		if token.Method != gojwt.SigningMethodHS256 {
+			return nil, jwt.ErrInvalidSigningAlgorithm
+		}
+		parts := strings.Split(token.Raw, ".")
+		if len(parts) != 3 {
+			return nil, gojwt.ErrTokenMalformed
+		}
+		for _, key := range keys {
+			if token.Method.Verify(strings.Join(parts[:2], "."), parts[2], key) == nil {
+				return key, nil
+			}
+		}
+		return nil, gojwt.ErrSignatureInvalid
+	}
+}
+
 func unauthorized(c *gin.Context, _ int, message string) {
 	lower := strings.ToLower(message)
 	var errCode int
@@ -27,8 +54,9 @@ func unauthorized(c *gin.Context, _ int, message string) {
 
 func loginResponse(c *gin.Context, code int, message string, time time.Time) {
 	resp := coordinatorType.LoginSchema{
-		Time:  time,
-		Token: message,
+		Time:    time,
+		Token:   message,
+		Warning: c.GetString(coordinatorType.VersionWarning),
 	}
 	types.RenderSuccess(c, resp)
 }
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/coordinator/internal/config"
+)
+
+func TestKeyRotation(t *testing.T) {
+	newConf := func(secret string, previousSecrets ...string) *config.Config {
+		return &config.Config{Auth: &config.Auth{
+			Secret:                     secret,
+			ChallengeExpireDurationSec: 10,
+			PreviousSecrets:            previousSecrets,
+		}}
+	}
+
+	oldToken, _, err := ChallengeMiddleware(newConf("old secret")).TokenGenerator(nil)
+	assert.NoError(t, err)
+
+	rotated := ChallengeMiddleware(newConf("new secret", "old secret"))
+	token, err := rotated.ParseTokenString(oldToken)
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	newToken, _, err := rotated.TokenGenerator(nil)
+	assert.NoError(t, err)
+	token, err = ChallengeMiddleware(newConf("new secret")).ParseTokenString(newToken)
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	_, err = ChallengeMiddleware(newConf("new secret")).ParseTokenString(oldToken)
+	assert.Error(t, err)
+	_, err = ChallengeMiddleware(newConf("old secret")).ParseTokenString(newToken)
+	assert.Error(t, err)
+}
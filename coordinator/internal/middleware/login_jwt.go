@@ -1,14 +1,18 @@
 package middleware
 
 import (
+	"net/http"
 	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
 	"github.com/scroll-tech/go-ethereum/log"
 
+	"scroll-tech/common/types"
+
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/controller/api"
-	"scroll-tech/coordinator/internal/types"
+	coordinatorType "scroll-tech/coordinator/internal/types"
 )
 
 // LoginMiddleware jwt auth middleware
@@ -16,15 +20,18 @@ func LoginMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
 	jwtMiddleware, err := jwt.New(&jwt.GinJWTMiddleware{
 		PayloadFunc:     api.Auth.PayloadFunc,
 		IdentityHandler: api.Auth.IdentityHandler,
-		IdentityKey:     types.PublicKey,
+		IdentityKey:     coordinatorType.PublicKey,
 		Key:             []byte(conf.Auth.Secret),
+		KeyFunc:         keyFunc(conf.Auth),
 		Timeout:         time.Second * time.Duration(conf.Auth.LoginExpireDurationSec),
+		MaxRefresh:      time.Second * time.Duration(conf.Auth.LoginMaxRefreshDurationSec),
 		Authenticator:   api.Auth.Login,
 		Unauthorized:    unauthorized,
 		TokenLookup:     "header: Authorization, query: token, cookie: jwt",
 		TokenHeadName:   "Bearer",
 		TimeFunc:        time.Now,
 		LoginResponse:   loginResponse,
+		RefreshResponse: loginResponse,
 	})
 
 	if err != nil {
@@ -37,3 +44,20 @@ func LoginMiddleware(conf *config.Config) *jwt.GinJWTMiddleware {
 
 	return jwtMiddleware
 }
+
+// RefreshHandler refreshes the login token once the prover of the token passes the login checks again, a prover
+// removed from the allow list or rejected by the version policy can't keep its session alive by refreshing it.
+func RefreshHandler(jwtMiddleware *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := jwtMiddleware.CheckIfTokenExpire(c)
+		if err != nil {
+			unauthorized(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if err = api.Auth.CheckRefresh(c, jwt.MapClaims(claims)); err != nil {
+			types.RenderFailure(c, types.ErrJWTCommonErr, err)
+			return
+		}
+		jwtMiddleware.RefreshHandler(c)
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/coordinator/internal/logic/ratelimit"
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// RateLimitMiddleware limits the requests of the action per prover public key, it must run after the login middleware
+func RateLimitMiddleware(limiter *ratelimit.Limiter, action ratelimit.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := limiter.Allow(action, c.GetString(coordinatorType.PublicKey)); err != nil {
+			types.RenderFailure(c, types.ErrCoordinatorRateLimited, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	coordinatorType "scroll-tech/coordinator/internal/types"
+)
+
+// RequestIDHeader the header carrying the request id, it's echoed back in the response
+const RequestIDHeader = "X-Request-Id"
+
+// RequestIDMiddleware tags every request with the id sent by the prover, or a fresh one, so that the
+// logs of the request can be correlated
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Set(coordinatorType.RequestID, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AdminAuditLog is an action of the admin api changing the state of a task, with the state before the action.
+type AdminAuditLog struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID         uint64 `json:"id" gorm:"column:id;primaryKey"`
+	Action     string `json:"action" gorm:"column:action"`
+	TargetType string `json:"target_type" gorm:"column:target_type"`
+	TargetKey  string `json:"target_key" gorm:"column:target_key"`
+	Operator   string `json:"operator" gorm:"column:operator"`
+	Reason     string `json:"reason" gorm:"column:reason"`
+	// Details is the json of the state of the target before the action.
+	Details string `json:"details" gorm:"column:details"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewAdminAuditLog creates a new AdminAuditLog instance.
+func NewAdminAuditLog(db *gorm.DB) *AdminAuditLog {
+	return &AdminAuditLog{db: db}
+}
+
+// TableName returns the name of the "admin_audit_log" table.
+func (*AdminAuditLog) TableName() string {
+	return "admin_audit_log"
+}
+
+// InsertAdminAuditLog records an admin action.
+func (o *AdminAuditLog) InsertAdminAuditLog(ctx context.Context, entry *AdminAuditLog, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&AdminAuditLog{})
+	if err := db.Create(entry).Error; err != nil {
+		return fmt.Errorf("AdminAuditLog.InsertAdminAuditLog error: %w, action: %v, target type: %v, target key: %v", err, entry.Action, entry.TargetType, entry.TargetKey)
+	}
+	return nil
+}
+
+// GetAdminAuditLogs retrieves the admin actions, of the target if the target key is given, the latest first.
+func (o *AdminAuditLog) GetAdminAuditLogs(ctx context.Context, targetKey string, offset, limit int) ([]AdminAuditLog, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&AdminAuditLog{})
+	if targetKey != "" {
+		db = db.Where("target_key = ?", targetKey)
+	}
+	db = db.Order("id DESC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var logs []AdminAuditLog
+	if err := db.Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("AdminAuditLog.GetAdminAuditLogs error: %w, target key: %v", err, targetKey)
+	}
+	return logs, nil
+}
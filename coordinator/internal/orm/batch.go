@@ -12,6 +12,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
@@ -19,7 +20,8 @@ import (
 
 // Batch represents a batch of chunks.
 type Batch struct {
-	db *gorm.DB `gorm:"column:-"`
+	db         *gorm.DB          `gorm:"column:-"`
+	proofStore objectstore.Store `gorm:"-"`
 
 	// batch
 	Index           uint64 `json:"index" gorm:"column:index"`
@@ -35,14 +37,22 @@ type Batch struct {
 	BatchHeader     []byte `json:"batch_header" gorm:"column:batch_header"`
 
 	// proof
-	ChunkProofsStatus int16      `json:"chunk_proofs_status" gorm:"column:chunk_proofs_status;default:1"`
-	ProvingStatus     int16      `json:"proving_status" gorm:"column:proving_status;default:1"`
-	Proof             []byte     `json:"proof" gorm:"column:proof;default:NULL"`
-	ProverAssignedAt  *time.Time `json:"prover_assigned_at" gorm:"column:prover_assigned_at;default:NULL"`
-	ProvedAt          *time.Time `json:"proved_at" gorm:"column:proved_at;default:NULL"`
-	ProofTimeSec      int32      `json:"proof_time_sec" gorm:"column:proof_time_sec;default:NULL"`
-	TotalAttempts     int16      `json:"total_attempts" gorm:"column:total_attempts;default:0"`
-	ActiveAttempts    int16      `json:"active_attempts" gorm:"column:active_attempts;default:0"`
+	ChunkProofsStatus     int16      `json:"chunk_proofs_status" gorm:"column:chunk_proofs_status;default:1"`
+	ProvingStatus         int16      `json:"proving_status" gorm:"column:proving_status;default:1"`
+	Proof                 []byte     `json:"proof" gorm:"column:proof;default:NULL"`
+	ProofHash             string     `json:"proof_hash" gorm:"column:proof_hash;default:NULL"`
+	ProofURI              string     `json:"proof_uri" gorm:"column:proof_uri;default:NULL"`
+	ProverAssignedAt      *time.Time `json:"prover_assigned_at" gorm:"column:prover_assigned_at;default:NULL"`
+	ProvedAt              *time.Time `json:"proved_at" gorm:"column:proved_at;default:NULL"`
+	ProofTimeSec          int32      `json:"proof_time_sec" gorm:"column:proof_time_sec;default:NULL"`
+	TotalAttempts         int16      `json:"total_attempts" gorm:"column:total_attempts;default:0"`
+	ActiveAttempts        int16      `json:"active_attempts" gorm:"column:active_attempts;default:0"`
+	FailedAttempts        int16      `json:"failed_attempts" gorm:"column:failed_attempts;default:0"`
+	PanicFailedAttempts   int16      `json:"panic_failed_attempts" gorm:"column:panic_failed_attempts;default:0"`
+	NoPanicFailedAttempts int16      `json:"no_panic_failed_attempts" gorm:"column:no_panic_failed_attempts;default:0"`
+	TaskContentHash       string     `json:"task_content_hash" gorm:"column:task_content_hash;default:NULL"`
+	Priority              int16      `json:"priority" gorm:"column:priority;default:2"`
+	Deadline              *time.Time `json:"deadline" gorm:"column:deadline;default:NULL"`
 
 	// rollup
 	RollupStatus   int16      `json:"rollup_status" gorm:"column:rollup_status;default:1"`
@@ -70,19 +80,34 @@ func NewBatch(db *gorm.DB) *Batch {
 	return &Batch{db: db}
 }
 
+// WithProofStore offloads the proofs written and read by the instance to the proof store, a nil store keeps them in the database.
+func (o *Batch) WithProofStore(store objectstore.Store) *Batch {
+	o.proofStore = store
+	return o
+}
+
 // TableName returns the table name for the Batch model.
 func (*Batch) TableName() string {
 	return "batch"
 }
 
-// GetUnassignedBatch retrieves unassigned batch based on the specified limit.
-// The returned batch are sorted in ascending order by their index.
-func (o *Batch) GetUnassignedBatch(ctx context.Context, startChunkIndex, endChunkIndex uint64, maxActiveAttempts, maxTotalAttempts uint8) (*Batch, error) {
+// GetUnassignedBatch retrieves the next unassigned batch to assign in the task assignment order, skipping the excluded hashes.
+func (o *Batch) GetUnassignedBatch(ctx context.Context, startChunkIndex, endChunkIndex uint64, maxActiveAttempts, maxTotalAttempts uint8, excludedHashes []string) (*Batch, error) {
 	var batch Batch
 	db := o.db.WithContext(ctx)
-	sql := fmt.Sprintf("SELECT * FROM batch WHERE proving_status = %d AND total_attempts < %d AND active_attempts < %d AND chunk_proofs_status = %d AND start_chunk_index >= %d AND end_chunk_index < %d AND batch.deleted_at IS NULL ORDER BY batch.index LIMIT 1;",
-		int(types.ProvingTaskUnassigned), maxTotalAttempts, maxActiveAttempts, int(types.ChunkProofsStatusReady), startChunkIndex, endChunkIndex)
-	err := db.Raw(sql).Scan(&batch).Error
+	db = db.Model(&Batch{})
+	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
+	db = db.Where("total_attempts < ?", maxTotalAttempts)
+	db = db.Where("active_attempts < ?", maxActiveAttempts)
+	db = db.Where("chunk_proofs_status = ?", int(types.ChunkProofsStatusReady))
+	db = db.Where("start_chunk_index >= ?", startChunkIndex)
+	db = db.Where("end_chunk_index < ?", endChunkIndex)
+	if len(excludedHashes) > 0 {
+		db = db.Where("hash NOT IN ?", excludedHashes)
+	}
+	db = db.Order(taskAssignmentOrder)
+	db = db.Limit(1)
+	err := db.Find(&batch).Error
 	if err != nil {
 		return nil, fmt.Errorf("Batch.GetUnassignedBatch error: %w", err)
 	}
@@ -92,14 +117,23 @@ func (o *Batch) GetUnassignedBatch(ctx context.Context, startChunkIndex, endChun
 	return &batch, nil
 }
 
-// GetAssignedBatch retrieves assigned batch based on the specified limit.
-// The returned batch are sorted in ascending order by their index.
-func (o *Batch) GetAssignedBatch(ctx context.Context, startChunkIndex, endChunkIndex uint64, maxActiveAttempts, maxTotalAttempts uint8) (*Batch, error) {
+// GetAssignedBatch retrieves the next assigned batch to assign in the task assignment order, skipping the excluded hashes.
+func (o *Batch) GetAssignedBatch(ctx context.Context, startChunkIndex, endChunkIndex uint64, maxActiveAttempts, maxTotalAttempts uint8, excludedHashes []string) (*Batch, error) {
 	var batch Batch
 	db := o.db.WithContext(ctx)
-	sql := fmt.Sprintf("SELECT * FROM batch WHERE proving_status = %d AND total_attempts < %d AND active_attempts < %d AND chunk_proofs_status = %d AND start_chunk_index >= %d AND end_chunk_index < %d AND batch.deleted_at IS NULL ORDER BY batch.index LIMIT 1;",
-		int(types.ProvingTaskAssigned), maxTotalAttempts, maxActiveAttempts, int(types.ChunkProofsStatusReady), startChunkIndex, endChunkIndex)
-	err := db.Raw(sql).Scan(&batch).Error
+	db = db.Model(&Batch{})
+	db = db.Where("proving_status = ?", int(types.ProvingTaskAssigned))
+	db = db.Where("total_attempts < ?", maxTotalAttempts)
+	db = db.Where("active_attempts < ?", maxActiveAttempts)
+	db = db.Where("chunk_proofs_status = ?", int(types.ChunkProofsStatusReady))
+	db = db.Where("start_chunk_index >= ?", startChunkIndex)
+	db = db.Where("end_chunk_index < ?", endChunkIndex)
+	if len(excludedHashes) > 0 {
+		db = db.Where("hash NOT IN ?", excludedHashes)
+	}
+	db = db.Order(taskAssignmentOrder)
+	db = db.Limit(1)
+	err := db.Find(&batch).Error
 	if err != nil {
 		return nil, fmt.Errorf("Batch.GetAssignedBatch error: %w", err)
 	}
@@ -109,6 +143,25 @@ func (o *Batch) GetAssignedBatch(ctx context.Context, startChunkIndex, endChunkI
 	return &batch, nil
 }
 
+// GetTopUnassignedPriority returns the highest priority of the unassigned batch tasks, false if there is none.
+func (o *Batch) GetTopUnassignedPriority(ctx context.Context) (message.TaskPriority, bool, error) {
+	var priorities []int16
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Select("priority")
+	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
+	db = db.Where("chunk_proofs_status = ?", int(types.ChunkProofsStatusReady))
+	db = db.Order("priority DESC")
+	db = db.Limit(1)
+	if err := db.Pluck("priority", &priorities).Error; err != nil {
+		return 0, false, fmt.Errorf("Batch.GetTopUnassignedPriority error: %w", err)
+	}
+	if len(priorities) == 0 {
+		return 0, false, nil
+	}
+	return message.TaskPriority(priorities[0]), true, nil
+}
+
 // GetUnassignedAndChunksUnreadyBatches get the batches which is unassigned and chunks is not ready
 func (o *Batch) GetUnassignedAndChunksUnreadyBatches(ctx context.Context, offset, limit int) ([]*Batch, error) {
 	if offset < 0 || limit < 0 {
@@ -118,7 +171,7 @@ func (o *Batch) GetUnassignedAndChunksUnreadyBatches(ctx context.Context, offset
 	db := o.db.WithContext(ctx)
 	db = db.Where("proving_status = ?", types.ProvingTaskUnassigned)
 	db = db.Where("chunk_proofs_status = ?", types.ChunkProofsStatusPending)
-	db = db.Order("index ASC")
+	db = db.Order(`"index" ASC`)
 	db = db.Offset(offset)
 	db = db.Limit(limit)
 
@@ -156,11 +209,77 @@ func (o *Batch) GetProvingStatusByHash(ctx context.Context, hash string) (types.
 	return types.ProvingStatus(batch.ProvingStatus), nil
 }
 
+// CountByProvingStatus returns the number of batches of every proving status.
+func (o *Batch) CountByProvingStatus(ctx context.Context) (map[types.ProvingStatus]int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Select("proving_status, COUNT(*) AS count")
+	db = db.Group("proving_status")
+
+	var rows []struct {
+		ProvingStatus int16
+		Count         int64
+	}
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("Batch.CountByProvingStatus error: %w", err)
+	}
+	counts := make(map[types.ProvingStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[types.ProvingStatus(row.ProvingStatus)] = row.Count
+	}
+	return counts, nil
+}
+
+// GetOldestUnassignedCreatedAt returns the creation time of the oldest batch waiting for a prover with all its chunk proofs ready, nil if there is none.
+func (o *Batch) GetOldestUnassignedCreatedAt(ctx context.Context) (*time.Time, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
+	db = db.Where("chunk_proofs_status = ?", int(types.ChunkProofsStatusReady))
+	db = db.Order("created_at ASC")
+
+	var batch Batch
+	if err := db.First(&batch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Batch.GetOldestUnassignedCreatedAt error: %w", err)
+	}
+	return &batch.CreatedAt, nil
+}
+
+// CountVerifiedSince returns the number of batches whose proof was verified after the given time.
+func (o *Batch) CountVerifiedSince(ctx context.Context, since time.Time) (int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("proving_status = ? AND proved_at >= ?", int(types.ProvingTaskVerified), since)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Batch.CountVerifiedSince error: %w, since: %v", err, since)
+	}
+	return count, nil
+}
+
+// GetBlobDataProofByHash retrieves the blob data proof of a batch given its hash, it's empty for the codecv0 batches.
+func (o *Batch) GetBlobDataProofByHash(ctx context.Context, hash string) ([]byte, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Select("blob_data_proof")
+	db = db.Where("hash = ?", hash)
+
+	var batch Batch
+	if err := db.Find(&batch).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetBlobDataProofByHash error: %w, batch hash: %v", err, hash)
+	}
+	return batch.BlobDataProof, nil
+}
+
 // GetLatestBatch retrieves the latest batch from the database.
 func (o *Batch) GetLatestBatch(ctx context.Context) (*Batch, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Batch{})
-	db = db.Order("index desc")
+	db = db.Order(`"index" desc`)
 
 	var latestBatch Batch
 	if err := db.First(&latestBatch).Error; err != nil {
@@ -172,6 +291,19 @@ func (o *Batch) GetLatestBatch(ctx context.Context) (*Batch, error) {
 	return &latestBatch, nil
 }
 
+// GetBatchByHash retrieves the batch by the given hash.
+func (o *Batch) GetBatchByHash(ctx context.Context, hash string) (*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", hash)
+
+	var batch Batch
+	if err := db.First(&batch).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetBatchByHash error: %w, batch hash: %v", err, hash)
+	}
+	return &batch, nil
+}
+
 // GetAttemptsByHash get batch attempts by hash. Used by unit test
 func (o *Batch) GetAttemptsByHash(ctx context.Context, hash string) (int16, int16, error) {
 	db := o.db.WithContext(ctx)
@@ -288,8 +420,12 @@ func (o *Batch) InsertBatch(ctx context.Context, batch *encoding.Batch, dbTX ...
 
 // UpdateChunkProofsStatusByBatchHash updates the status of chunk_proofs_status field for a given batch hash.
 // The function will set the chunk_proofs_status to the status provided.
-func (o *Batch) UpdateChunkProofsStatusByBatchHash(ctx context.Context, batchHash string, status types.ChunkProofsStatus) error {
-	db := o.db.WithContext(ctx)
+func (o *Batch) UpdateChunkProofsStatusByBatchHash(ctx context.Context, batchHash string, status types.ChunkProofsStatus, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Batch{})
 	db = db.Where("hash = ?", batchHash)
 
@@ -299,6 +435,22 @@ func (o *Batch) UpdateChunkProofsStatusByBatchHash(ctx context.Context, batchHas
 	return nil
 }
 
+// ResetTaskByHash sets the batch back to unassigned to be proven from scratch, clearing its proof, prover assignment
+// and attempts.
+func (o *Batch) ResetTaskByHash(ctx context.Context, hash string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", hash)
+	if err := db.Updates(resetTaskFields()).Error; err != nil {
+		return fmt.Errorf("Batch.ResetTaskByHash error: %w, batch hash: %v", err, hash)
+	}
+	return nil
+}
+
 // UpdateProvingStatusFailed updates the proving status failed of a batch.
 func (o *Batch) UpdateProvingStatusFailed(ctx context.Context, hash string, maxAttempts uint8, dbTX ...*gorm.DB) error {
 	db := o.db
@@ -327,8 +479,10 @@ func (o *Batch) UpdateProofAndProvingStatusByHash(ctx context.Context, hash stri
 		return err
 	}
 
-	updateFields := make(map[string]interface{})
-	updateFields["proof"] = proofBytes
+	updateFields, err := proofFields(ctx, o.proofStore, "batch", proofBytes)
+	if err != nil {
+		return fmt.Errorf("Batch.UpdateProofByHash error: %w, batch hash: %v", err, hash)
+	}
 	updateFields["proving_status"] = provingStatus
 	updateFields["proof_time_sec"] = proofTimeSec
 	updateFields["proved_at"] = utils.NowUTC()
@@ -343,11 +497,81 @@ func (o *Batch) UpdateProofAndProvingStatusByHash(ctx context.Context, hash stri
 	return nil
 }
 
+// UpdatePriorityByHash updates the priority and the deadline of the batch task, a nil deadline clears it.
+func (o *Batch) UpdatePriorityByHash(ctx context.Context, hash string, priority message.TaskPriority, deadline *time.Time, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", hash)
+	result := db.Updates(map[string]interface{}{
+		"priority": int16(priority),
+		"deadline": deadline,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("Batch.UpdatePriorityByHash error: %w, batch hash: %v", result.Error, hash)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("Batch.UpdatePriorityByHash error: %w, batch hash: %v", gorm.ErrRecordNotFound, hash)
+	}
+	return nil
+}
+
+// UpdateTaskContentHash updates the content hash of the task data of the batch.
+func (o *Batch) UpdateTaskContentHash(ctx context.Context, hash string, contentHash string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Update("task_content_hash", contentHash).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateTaskContentHash error: %w, batch hash: %v", err, hash)
+	}
+	return nil
+}
+
+// ReuseProofByTaskContentHash copies the proof of a verified batch with the same task content hash to the batch
+// and marks it verified, the batchs deleted by a re-batching are searched too. It returns false if there is no such proof.
+func (o *Batch) ReuseProofByTaskContentHash(ctx context.Context, hash string, contentHash string) (bool, error) {
+	db := o.db.WithContext(ctx).Unscoped()
+	db = db.Model(&Batch{})
+	db = db.Select("proof, proof_hash, proof_uri")
+	db = db.Where("task_content_hash = ? AND proving_status = ?", contentHash, int(types.ProvingTaskVerified))
+	db = db.Order("proved_at DESC")
+
+	var proved Batch
+	if err := db.First(&proved).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("Batch.ReuseProofByTaskContentHash error: %w, batch hash: %v", err, hash)
+	}
+
+	db = o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	updateFields := map[string]interface{}{
+		"proof":             proved.Proof,
+		"proof_hash":        proved.ProofHash,
+		"proof_uri":         proved.ProofURI,
+		"proving_status":    int(types.ProvingTaskVerified),
+		"proof_time_sec":    0,
+		"proved_at":         utils.NowUTC(),
+		"task_content_hash": contentHash,
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		return false, fmt.Errorf("Batch.ReuseProofByTaskContentHash error: %w, batch hash: %v", err, hash)
+	}
+	return true, nil
+}
+
 // UpdateBatchAttempts atomically increments the attempts count for the earliest available batch that meets the conditions.
 func (o *Batch) UpdateBatchAttempts(ctx context.Context, index uint64, curActiveAttempts, curTotalAttempts int16) (int64, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Batch{})
-	db = db.Where("index = ?", index)
+	db = db.Where(`"index" = ?`, index)
 	db = db.Where("active_attempts = ?", curActiveAttempts)
 	db = db.Where("total_attempts = ?", curTotalAttempts)
 	result := db.Updates(map[string]interface{}{
@@ -382,3 +606,75 @@ func (o *Batch) DecreaseActiveAttemptsByHash(ctx context.Context, batchHash stri
 	}
 	return nil
 }
+
+// IncreaseFailedAttemptsByHash increments the failed_attempts of a batch given its hash.
+func (o *Batch) IncreaseFailedAttemptsByHash(ctx context.Context, batchHash string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", batchHash)
+	db = db.Where("proving_status != ?", int(types.ProvingTaskVerified))
+	if err := db.UpdateColumn("failed_attempts", gorm.Expr("failed_attempts + 1")).Error; err != nil {
+		return fmt.Errorf("Batch.IncreaseFailedAttemptsByHash error: %w, batch hash: %v", err, batchHash)
+	}
+	return nil
+}
+
+// IncreaseProofFailedAttemptsByHash increments the panic_failed_attempts or the no_panic_failed_attempts of a batch
+// given its hash, by the type of the proof failure reported by the prover.
+func (o *Batch) IncreaseProofFailedAttemptsByHash(ctx context.Context, batchHash string, proofFailureType message.ProofFailureType, dbTX ...*gorm.DB) error {
+	var column string
+	switch proofFailureType {
+	case message.ProofFailurePanic:
+		column = "panic_failed_attempts"
+	case message.ProofFailureNoPanic:
+		column = "no_panic_failed_attempts"
+	default:
+		return nil
+	}
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", batchHash)
+	db = db.Where("proving_status != ?", int(types.ProvingTaskVerified))
+	if err := db.UpdateColumn(column, gorm.Expr(column+" + 1")).Error; err != nil {
+		return fmt.Errorf("Batch.IncreaseProofFailedAttemptsByHash error: %w, batch hash: %v, proof failure type: %v", err, batchHash, proofFailureType)
+	}
+	return nil
+}
+
+// QuarantineByHash sets the batch quarantined once its panic or no panic proof failures reach their max, so it's
+// no longer assigned until the operator resets it. A max of 0 doesn't quarantine the batch for the failure type.
+// It returns whether the batch is quarantined.
+func (o *Batch) QuarantineByHash(ctx context.Context, batchHash string, maxPanicFailures, maxNoPanicFailures uint8, dbTX ...*gorm.DB) (bool, error) {
+	if maxPanicFailures == 0 && maxNoPanicFailures == 0 {
+		return false, nil
+	}
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", batchHash)
+	db = db.Where("proving_status IN (?)", []int{int(types.ProvingTaskUnassigned), int(types.ProvingTaskAssigned)})
+	switch {
+	case maxPanicFailures == 0:
+		db = db.Where("no_panic_failed_attempts >= ?", maxNoPanicFailures)
+	case maxNoPanicFailures == 0:
+		db = db.Where("panic_failed_attempts >= ?", maxPanicFailures)
+	default:
+		db = db.Where("panic_failed_attempts >= ? OR no_panic_failed_attempts >= ?", maxPanicFailures, maxNoPanicFailures)
+	}
+	result := db.Update("proving_status", int(types.ProvingTaskQuarantined))
+	if result.Error != nil {
+		return false, fmt.Errorf("Batch.QuarantineByHash error: %w, batch hash: %v", result.Error, batchHash)
+	}
+	return result.RowsAffected > 0, nil
+}
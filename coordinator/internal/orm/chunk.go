@@ -12,6 +12,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
@@ -19,7 +20,8 @@ import (
 
 // Chunk represents a chunk of blocks in the database.
 type Chunk struct {
-	db *gorm.DB `gorm:"-"`
+	db         *gorm.DB          `gorm:"-"`
+	proofStore objectstore.Store `gorm:"-"`
 
 	// chunk
 	Index                        uint64 `json:"index" gorm:"column:index"`
@@ -37,13 +39,21 @@ type Chunk struct {
 	WithdrawRoot                 string `json:"withdraw_root" gorm:"column:withdraw_root"`
 
 	// proof
-	ProvingStatus    int16      `json:"proving_status" gorm:"column:proving_status;default:1"`
-	Proof            []byte     `json:"proof" gorm:"column:proof;default:NULL"`
-	ProverAssignedAt *time.Time `json:"prover_assigned_at" gorm:"column:prover_assigned_at;default:NULL"`
-	ProvedAt         *time.Time `json:"proved_at" gorm:"column:proved_at;default:NULL"`
-	ProofTimeSec     int32      `json:"proof_time_sec" gorm:"column:proof_time_sec;default:NULL"`
-	TotalAttempts    int16      `json:"total_attempts" gorm:"column:total_attempts;default:0"`
-	ActiveAttempts   int16      `json:"active_attempts" gorm:"column:active_attempts;default:0"`
+	ProvingStatus         int16      `json:"proving_status" gorm:"column:proving_status;default:1"`
+	Proof                 []byte     `json:"proof" gorm:"column:proof;default:NULL"`
+	ProofHash             string     `json:"proof_hash" gorm:"column:proof_hash;default:NULL"`
+	ProofURI              string     `json:"proof_uri" gorm:"column:proof_uri;default:NULL"`
+	ProverAssignedAt      *time.Time `json:"prover_assigned_at" gorm:"column:prover_assigned_at;default:NULL"`
+	ProvedAt              *time.Time `json:"proved_at" gorm:"column:proved_at;default:NULL"`
+	ProofTimeSec          int32      `json:"proof_time_sec" gorm:"column:proof_time_sec;default:NULL"`
+	TotalAttempts         int16      `json:"total_attempts" gorm:"column:total_attempts;default:0"`
+	ActiveAttempts        int16      `json:"active_attempts" gorm:"column:active_attempts;default:0"`
+	FailedAttempts        int16      `json:"failed_attempts" gorm:"column:failed_attempts;default:0"`
+	PanicFailedAttempts   int16      `json:"panic_failed_attempts" gorm:"column:panic_failed_attempts;default:0"`
+	NoPanicFailedAttempts int16      `json:"no_panic_failed_attempts" gorm:"column:no_panic_failed_attempts;default:0"`
+	TaskContentHash       string     `json:"task_content_hash" gorm:"column:task_content_hash;default:NULL"`
+	Priority              int16      `json:"priority" gorm:"column:priority;default:1"`
+	Deadline              *time.Time `json:"deadline" gorm:"column:deadline;default:NULL"`
 
 	// batch
 	BatchHash string `json:"batch_hash" gorm:"column:batch_hash;default:NULL"`
@@ -67,19 +77,37 @@ func NewChunk(db *gorm.DB) *Chunk {
 	return &Chunk{db: db}
 }
 
+// WithProofStore offloads the proofs written and read by the instance to the proof store, a nil store keeps them in the database.
+func (o *Chunk) WithProofStore(store objectstore.Store) *Chunk {
+	o.proofStore = store
+	return o
+}
+
 // TableName returns the table name for the chunk model.
 func (*Chunk) TableName() string {
 	return "chunk"
 }
 
-// GetUnassignedChunk retrieves unassigned chunk based on the specified limit.
-// The returned chunks are sorted in ascending order by their index.
-func (o *Chunk) GetUnassignedChunk(ctx context.Context, fromBlockNum, toBlockNum uint64, maxActiveAttempts, maxTotalAttempts uint8) (*Chunk, error) {
+// taskAssignmentOrder is the order the chunk and batch tasks are assigned in: the urgent tasks first, then the
+// tasks of the earliest deadline, then the oldest tasks.
+const taskAssignmentOrder = `priority DESC, deadline ASC NULLS LAST, "index" ASC`
+
+// GetUnassignedChunk retrieves the next unassigned chunk to assign in the task assignment order, skipping the excluded hashes.
+func (o *Chunk) GetUnassignedChunk(ctx context.Context, fromBlockNum, toBlockNum uint64, maxActiveAttempts, maxTotalAttempts uint8, excludedHashes []string) (*Chunk, error) {
 	var chunk Chunk
 	db := o.db.WithContext(ctx)
-	sql := fmt.Sprintf("SELECT * FROM chunk WHERE proving_status = %d AND total_attempts < %d AND active_attempts < %d AND start_block_number >= %d AND end_block_number < %d AND chunk.deleted_at IS NULL ORDER BY chunk.index LIMIT 1;",
-		int(types.ProvingTaskUnassigned), maxTotalAttempts, maxActiveAttempts, fromBlockNum, toBlockNum)
-	err := db.Raw(sql).Scan(&chunk).Error
+	db = db.Model(&Chunk{})
+	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
+	db = db.Where("total_attempts < ?", maxTotalAttempts)
+	db = db.Where("active_attempts < ?", maxActiveAttempts)
+	db = db.Where("start_block_number >= ?", fromBlockNum)
+	db = db.Where("end_block_number < ?", toBlockNum)
+	if len(excludedHashes) > 0 {
+		db = db.Where("hash NOT IN ?", excludedHashes)
+	}
+	db = db.Order(taskAssignmentOrder)
+	db = db.Limit(1)
+	err := db.Find(&chunk).Error
 	if err != nil {
 		return nil, fmt.Errorf("Chunk.GetUnassignedChunk error: %w", err)
 	}
@@ -89,14 +117,22 @@ func (o *Chunk) GetUnassignedChunk(ctx context.Context, fromBlockNum, toBlockNum
 	return &chunk, nil
 }
 
-// GetAssignedChunk retrieves assigned chunk based on the specified limit.
-// The returned chunks are sorted in ascending order by their index.
-func (o *Chunk) GetAssignedChunk(ctx context.Context, fromBlockNum, toBlockNum uint64, maxActiveAttempts, maxTotalAttempts uint8) (*Chunk, error) {
+// GetAssignedChunk retrieves the next assigned chunk to assign in the task assignment order, skipping the excluded hashes.
+func (o *Chunk) GetAssignedChunk(ctx context.Context, fromBlockNum, toBlockNum uint64, maxActiveAttempts, maxTotalAttempts uint8, excludedHashes []string) (*Chunk, error) {
 	var chunk Chunk
 	db := o.db.WithContext(ctx)
-	sql := fmt.Sprintf("SELECT * FROM chunk WHERE proving_status = %d AND total_attempts < %d AND active_attempts < %d AND start_block_number >= %d AND end_block_number < %d AND chunk.deleted_at IS NULL ORDER BY chunk.index LIMIT 1;",
-		int(types.ProvingTaskAssigned), maxTotalAttempts, maxActiveAttempts, fromBlockNum, toBlockNum)
-	err := db.Raw(sql).Scan(&chunk).Error
+	db = db.Model(&Chunk{})
+	db = db.Where("proving_status = ?", int(types.ProvingTaskAssigned))
+	db = db.Where("total_attempts < ?", maxTotalAttempts)
+	db = db.Where("active_attempts < ?", maxActiveAttempts)
+	db = db.Where("start_block_number >= ?", fromBlockNum)
+	db = db.Where("end_block_number < ?", toBlockNum)
+	if len(excludedHashes) > 0 {
+		db = db.Where("hash NOT IN ?", excludedHashes)
+	}
+	db = db.Order(taskAssignmentOrder)
+	db = db.Limit(1)
+	err := db.Find(&chunk).Error
 	if err != nil {
 		return nil, fmt.Errorf("Chunk.GetAssignedChunk error: %w", err)
 	}
@@ -106,13 +142,31 @@ func (o *Chunk) GetAssignedChunk(ctx context.Context, fromBlockNum, toBlockNum u
 	return &chunk, nil
 }
 
+// GetTopUnassignedPriority returns the highest priority of the unassigned chunk tasks, false if there is none.
+func (o *Chunk) GetTopUnassignedPriority(ctx context.Context) (message.TaskPriority, bool, error) {
+	var priorities []int16
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Select("priority")
+	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
+	db = db.Order("priority DESC")
+	db = db.Limit(1)
+	if err := db.Pluck("priority", &priorities).Error; err != nil {
+		return 0, false, fmt.Errorf("Chunk.GetTopUnassignedPriority error: %w", err)
+	}
+	if len(priorities) == 0 {
+		return 0, false, nil
+	}
+	return message.TaskPriority(priorities[0]), true, nil
+}
+
 // GetChunksByBatchHash retrieves the chunks associated with a specific batch hash.
 // The returned chunks are sorted in ascending order by their associated chunk index.
 func (o *Chunk) GetChunksByBatchHash(ctx context.Context, batchHash string) ([]*Chunk, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
 	db = db.Where("batch_hash", batchHash)
-	db = db.Order("index ASC")
+	db = db.Order(`"index" ASC`)
 
 	var chunks []*Chunk
 	if err := db.Find(&chunks).Error; err != nil {
@@ -128,7 +182,7 @@ func (o *Chunk) GetProofsByBatchHash(ctx context.Context, batchHash string) ([]*
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
 	db = db.Where("batch_hash", batchHash)
-	db = db.Order("index ASC")
+	db = db.Order(`"index" ASC`)
 
 	var chunks []*Chunk
 	if err := db.Find(&chunks).Error; err != nil {
@@ -137,8 +191,12 @@ func (o *Chunk) GetProofsByBatchHash(ctx context.Context, batchHash string) ([]*
 
 	var proofs []*message.ChunkProof
 	for _, chunk := range chunks {
+		proofBytes, err := loadProof(ctx, o.proofStore, chunk.Proof, chunk.ProofURI, chunk.ProofHash)
+		if err != nil {
+			return nil, fmt.Errorf("Chunk.GetProofsByBatchHash load proof error: %w, batch hash: %v, chunk hash: %v", err, batchHash, chunk.Hash)
+		}
 		var proof message.ChunkProof
-		if err := json.Unmarshal(chunk.Proof, &proof); err != nil {
+		if err := json.Unmarshal(proofBytes, &proof); err != nil {
 			return nil, fmt.Errorf("Chunk.GetProofsByBatchHash unmarshal proof error: %w, batch hash: %v, chunk hash: %v", err, batchHash, chunk.Hash)
 		}
 		proofs = append(proofs, &proof)
@@ -151,7 +209,7 @@ func (o *Chunk) GetProofsByBatchHash(ctx context.Context, batchHash string) ([]*
 func (o *Chunk) getLatestChunk(ctx context.Context) (*Chunk, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Order("index desc")
+	db = db.Order(`"index" desc`)
 
 	var latestChunk Chunk
 	if err := db.First(&latestChunk).Error; err != nil {
@@ -177,6 +235,73 @@ func (o *Chunk) GetProvingStatusByHash(ctx context.Context, hash string) (types.
 	return types.ProvingStatus(chunk.ProvingStatus), nil
 }
 
+// CountByProvingStatus returns the number of chunks of every proving status.
+func (o *Chunk) CountByProvingStatus(ctx context.Context) (map[types.ProvingStatus]int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Select("proving_status, COUNT(*) AS count")
+	db = db.Group("proving_status")
+
+	var rows []struct {
+		ProvingStatus int16
+		Count         int64
+	}
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("Chunk.CountByProvingStatus error: %w", err)
+	}
+	counts := make(map[types.ProvingStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[types.ProvingStatus(row.ProvingStatus)] = row.Count
+	}
+	return counts, nil
+}
+
+// GetOldestUnassignedCreatedAt returns the creation time of the oldest chunk waiting for a prover, nil if there is none.
+func (o *Chunk) GetOldestUnassignedCreatedAt(ctx context.Context) (*time.Time, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("proving_status = ?", int(types.ProvingTaskUnassigned))
+	db = db.Order("created_at ASC")
+
+	var chunk Chunk
+	if err := db.First(&chunk).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Chunk.GetOldestUnassignedCreatedAt error: %w", err)
+	}
+	return &chunk.CreatedAt, nil
+}
+
+// CountVerifiedSince returns the number of chunks whose proof was verified after the given time.
+func (o *Chunk) CountVerifiedSince(ctx context.Context, since time.Time) (int64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("proving_status = ? AND proved_at >= ?", int(types.ProvingTaskVerified), since)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Chunk.CountVerifiedSince error: %w, since: %v", err, since)
+	}
+	return count, nil
+}
+
+// GetProvedChunkSamples retrieves the proving time, row usage and transaction number of the latest verified chunks.
+func (o *Chunk) GetProvedChunkSamples(ctx context.Context, limit int) ([]Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Select("proof_time_sec, crc_max, total_l2_tx_num")
+	db = db.Where("proving_status = ? AND proof_time_sec > 0", int(types.ProvingTaskVerified))
+	db = db.Order(`"index" DESC`)
+	db = db.Limit(limit)
+
+	var chunks []Chunk
+	if err := db.Find(&chunks).Error; err != nil {
+		return nil, fmt.Errorf("Chunk.GetProvedChunkSamples error: %w, limit: %v", err, limit)
+	}
+	return chunks, nil
+}
+
 // CheckIfBatchChunkProofsAreReady checks if all proofs for all chunks of a given batchHash are collected.
 func (o *Chunk) CheckIfBatchChunkProofsAreReady(ctx context.Context, batchHash string) (bool, error) {
 	db := o.db.WithContext(ctx)
@@ -350,8 +475,10 @@ func (o *Chunk) UpdateProofAndProvingStatusByHash(ctx context.Context, hash stri
 		return err
 	}
 
-	updateFields := make(map[string]interface{})
-	updateFields["proof"] = proofBytes
+	updateFields, err := proofFields(ctx, o.proofStore, "chunk", proofBytes)
+	if err != nil {
+		return fmt.Errorf("Chunk.UpdateProofByHash error: %w, chunk hash: %v", err, hash)
+	}
 	updateFields["proving_status"] = int(status)
 	updateFields["proof_time_sec"] = proofTimeSec
 	updateFields["proved_at"] = utils.NowUTC()
@@ -366,13 +493,117 @@ func (o *Chunk) UpdateProofAndProvingStatusByHash(ctx context.Context, hash stri
 	return nil
 }
 
+// ResetTaskByHash sets the chunk back to unassigned to be proven from scratch, clearing its proof, prover assignment
+// and attempts.
+func (o *Chunk) ResetTaskByHash(ctx context.Context, hash string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash = ?", hash)
+	if err := db.Updates(resetTaskFields()).Error; err != nil {
+		return fmt.Errorf("Chunk.ResetTaskByHash error: %w, chunk hash: %v", err, hash)
+	}
+	return nil
+}
+
+// resetTaskFields are the chunk or batch fields cleared by a task reset.
+func resetTaskFields() map[string]interface{} {
+	return map[string]interface{}{
+		"proving_status":           int(types.ProvingTaskUnassigned),
+		"proof":                    nil,
+		"proof_hash":               nil,
+		"proof_uri":                nil,
+		"prover_assigned_at":       nil,
+		"proved_at":                nil,
+		"proof_time_sec":           nil,
+		"total_attempts":           0,
+		"active_attempts":          0,
+		"failed_attempts":          0,
+		"panic_failed_attempts":    0,
+		"no_panic_failed_attempts": 0,
+	}
+}
+
+// UpdatePriorityByHash updates the priority and the deadline of the chunk task, a nil deadline clears it.
+func (o *Chunk) UpdatePriorityByHash(ctx context.Context, hash string, priority message.TaskPriority, deadline *time.Time, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash = ?", hash)
+	result := db.Updates(map[string]interface{}{
+		"priority": int16(priority),
+		"deadline": deadline,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("Chunk.UpdatePriorityByHash error: %w, chunk hash: %v", result.Error, hash)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("Chunk.UpdatePriorityByHash error: %w, chunk hash: %v", gorm.ErrRecordNotFound, hash)
+	}
+	return nil
+}
+
+// UpdateTaskContentHash updates the content hash of the task data of the chunk.
+func (o *Chunk) UpdateTaskContentHash(ctx context.Context, hash string, contentHash string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash", hash)
+
+	if err := db.Update("task_content_hash", contentHash).Error; err != nil {
+		return fmt.Errorf("Chunk.UpdateTaskContentHash error: %w, chunk hash: %v", err, hash)
+	}
+	return nil
+}
+
+// ReuseProofByTaskContentHash copies the proof of a verified chunk with the same task content hash to the chunk
+// and marks it verified, the chunks deleted by a re-chunking are searched too. It returns false if there is no such proof.
+func (o *Chunk) ReuseProofByTaskContentHash(ctx context.Context, hash string, contentHash string) (bool, error) {
+	db := o.db.WithContext(ctx).Unscoped()
+	db = db.Model(&Chunk{})
+	db = db.Select("proof, proof_hash, proof_uri")
+	db = db.Where("task_content_hash = ? AND proving_status = ?", contentHash, int(types.ProvingTaskVerified))
+	db = db.Order("proved_at DESC")
+
+	var proved Chunk
+	if err := db.First(&proved).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("Chunk.ReuseProofByTaskContentHash error: %w, chunk hash: %v", err, hash)
+	}
+
+	db = o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash", hash)
+
+	updateFields := map[string]interface{}{
+		"proof":             proved.Proof,
+		"proof_hash":        proved.ProofHash,
+		"proof_uri":         proved.ProofURI,
+		"proving_status":    int(types.ProvingTaskVerified),
+		"proof_time_sec":    0,
+		"proved_at":         utils.NowUTC(),
+		"task_content_hash": contentHash,
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		return false, fmt.Errorf("Chunk.ReuseProofByTaskContentHash error: %w, chunk hash: %v", err, hash)
+	}
+	return true, nil
+}
+
 // UpdateBatchHashInRange updates the batch_hash for chunks within the specified range (inclusive).
 // The range is closed, i.e., it includes both start and end indices.
 // for unit test
 func (o *Chunk) UpdateBatchHashInRange(ctx context.Context, startIndex uint64, endIndex uint64, batchHash string) error {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Where("index >= ? AND index <= ?", startIndex, endIndex)
+	db = db.Where(`"index" >= ? AND "index" <= ?`, startIndex, endIndex)
 
 	if err := db.Update("batch_hash", batchHash).Error; err != nil {
 		return fmt.Errorf("Chunk.UpdateBatchHashInRange error: %w, start index: %v, end index: %v, batch hash: %v", err, startIndex, endIndex, batchHash)
@@ -384,7 +615,7 @@ func (o *Chunk) UpdateBatchHashInRange(ctx context.Context, startIndex uint64, e
 func (o *Chunk) UpdateChunkAttempts(ctx context.Context, index uint64, curActiveAttempts, curTotalAttempts int16) (int64, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Where("index = ?", index)
+	db = db.Where(`"index" = ?`, index)
 	db = db.Where("active_attempts = ?", curActiveAttempts)
 	db = db.Where("total_attempts = ?", curTotalAttempts)
 	result := db.Updates(map[string]interface{}{
@@ -419,3 +650,75 @@ func (o *Chunk) DecreaseActiveAttemptsByHash(ctx context.Context, chunkHash stri
 	}
 	return nil
 }
+
+// IncreaseFailedAttemptsByHash increments the failed_attempts of a chunk given its hash.
+func (o *Chunk) IncreaseFailedAttemptsByHash(ctx context.Context, chunkHash string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash = ?", chunkHash)
+	db = db.Where("proving_status != ?", int(types.ProvingTaskVerified))
+	if err := db.UpdateColumn("failed_attempts", gorm.Expr("failed_attempts + 1")).Error; err != nil {
+		return fmt.Errorf("Chunk.IncreaseFailedAttemptsByHash error: %w, chunk hash: %v", err, chunkHash)
+	}
+	return nil
+}
+
+// IncreaseProofFailedAttemptsByHash increments the panic_failed_attempts or the no_panic_failed_attempts of a chunk
+// given its hash, by the type of the proof failure reported by the prover.
+func (o *Chunk) IncreaseProofFailedAttemptsByHash(ctx context.Context, chunkHash string, proofFailureType message.ProofFailureType, dbTX ...*gorm.DB) error {
+	var column string
+	switch proofFailureType {
+	case message.ProofFailurePanic:
+		column = "panic_failed_attempts"
+	case message.ProofFailureNoPanic:
+		column = "no_panic_failed_attempts"
+	default:
+		return nil
+	}
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash = ?", chunkHash)
+	db = db.Where("proving_status != ?", int(types.ProvingTaskVerified))
+	if err := db.UpdateColumn(column, gorm.Expr(column+" + 1")).Error; err != nil {
+		return fmt.Errorf("Chunk.IncreaseProofFailedAttemptsByHash error: %w, chunk hash: %v, proof failure type: %v", err, chunkHash, proofFailureType)
+	}
+	return nil
+}
+
+// QuarantineByHash sets the chunk quarantined once its panic or no panic proof failures reach their max, so it's
+// no longer assigned until the operator resets it. A max of 0 doesn't quarantine the chunk for the failure type.
+// It returns whether the chunk is quarantined.
+func (o *Chunk) QuarantineByHash(ctx context.Context, chunkHash string, maxPanicFailures, maxNoPanicFailures uint8, dbTX ...*gorm.DB) (bool, error) {
+	if maxPanicFailures == 0 && maxNoPanicFailures == 0 {
+		return false, nil
+	}
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash = ?", chunkHash)
+	db = db.Where("proving_status IN (?)", []int{int(types.ProvingTaskUnassigned), int(types.ProvingTaskAssigned)})
+	switch {
+	case maxPanicFailures == 0:
+		db = db.Where("no_panic_failed_attempts >= ?", maxNoPanicFailures)
+	case maxNoPanicFailures == 0:
+		db = db.Where("panic_failed_attempts >= ?", maxPanicFailures)
+	default:
+		db = db.Where("panic_failed_attempts >= ? OR no_panic_failed_attempts >= ?", maxPanicFailures, maxNoPanicFailures)
+	}
+	result := db.Update("proving_status", int(types.ProvingTaskQuarantined))
+	if result.Error != nil {
+		return false, fmt.Errorf("Chunk.QuarantineByHash error: %w, chunk hash: %v", result.Error, chunkHash)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,133 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types/message"
+)
+
+// ChunkRowUsage represents the rows of a sub-circuit used by a chunk, as reported by its accepted chunk proof.
+type ChunkRowUsage struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID               uint   `json:"id" gorm:"column:id;primaryKey"`
+	ChunkIndex       uint64 `json:"chunk_index" gorm:"column:chunk_index"`
+	ChunkHash        string `json:"chunk_hash" gorm:"column:chunk_hash"`
+	StartBlockNumber uint64 `json:"start_block_number" gorm:"column:start_block_number"`
+	EndBlockNumber   uint64 `json:"end_block_number" gorm:"column:end_block_number"`
+	SubCircuit       string `json:"sub_circuit" gorm:"column:sub_circuit"`
+	RowNumber        uint64 `json:"row_number" gorm:"column:row_number"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// SubCircuitRowStats is the aggregated row usage of a sub-circuit over a range of chunks.
+type SubCircuitRowStats struct {
+	SubCircuit   string  `gorm:"column:sub_circuit"`
+	ChunkCount   uint64  `gorm:"column:chunk_count"`
+	MaxRowNumber uint64  `gorm:"column:max_row_number"`
+	AvgRowNumber float64 `gorm:"column:avg_row_number"`
+}
+
+// NewChunkRowUsage creates a new ChunkRowUsage instance.
+func NewChunkRowUsage(db *gorm.DB) *ChunkRowUsage {
+	return &ChunkRowUsage{db: db}
+}
+
+// TableName returns the name of the "chunk_row_usage" table.
+func (*ChunkRowUsage) TableName() string {
+	return "chunk_row_usage"
+}
+
+// InsertChunkRowUsages records the row usages of the chunk, the ones already recorded are overwritten.
+func (o *ChunkRowUsage) InsertChunkRowUsages(ctx context.Context, chunk *Chunk, rowUsages []message.SubCircuitRowUsage, dbTX ...*gorm.DB) error {
+	if len(rowUsages) == 0 {
+		return nil
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ChunkRowUsage{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "chunk_hash"}, {Name: "sub_circuit"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"row_number": gorm.Expr("excluded.row_number"),
+			"updated_at": time.Now(),
+		}),
+	})
+
+	chunkRowUsages := make([]ChunkRowUsage, 0, len(rowUsages))
+	for _, rowUsage := range rowUsages {
+		chunkRowUsages = append(chunkRowUsages, ChunkRowUsage{
+			ChunkIndex:       chunk.Index,
+			ChunkHash:        chunk.Hash,
+			StartBlockNumber: chunk.StartBlockNumber,
+			EndBlockNumber:   chunk.EndBlockNumber,
+			SubCircuit:       rowUsage.Name,
+			RowNumber:        rowUsage.RowNumber,
+		})
+	}
+	if err := db.Create(&chunkRowUsages).Error; err != nil {
+		return fmt.Errorf("ChunkRowUsage.InsertChunkRowUsages error: %w, chunk hash: %v", err, chunk.Hash)
+	}
+	return nil
+}
+
+// GetLatestChunkIndex returns the index of the latest chunk with recorded row usages, nil if there is none.
+func (o *ChunkRowUsage) GetLatestChunkIndex(ctx context.Context) (*uint64, error) {
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
+	db = db.Model(&ChunkRowUsage{})
+	db = db.Order("chunk_index DESC")
+
+	var chunkRowUsages []ChunkRowUsage
+	if err := db.Limit(1).Find(&chunkRowUsages).Error; err != nil {
+		return nil, fmt.Errorf("ChunkRowUsage.GetLatestChunkIndex error: %w", err)
+	}
+	if len(chunkRowUsages) == 0 {
+		return nil, nil
+	}
+	return &chunkRowUsages[0].ChunkIndex, nil
+}
+
+// GetChunkRowUsagesInRange retrieves the row usages of the chunks of index in [startIndex, endIndex], ordered by chunk index.
+func (o *ChunkRowUsage) GetChunkRowUsagesInRange(ctx context.Context, startIndex, endIndex uint64) ([]ChunkRowUsage, error) {
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
+	db = db.Model(&ChunkRowUsage{})
+	db = db.Where("chunk_index >= ? AND chunk_index <= ?", startIndex, endIndex)
+	db = db.Order("chunk_index ASC, sub_circuit ASC")
+
+	var chunkRowUsages []ChunkRowUsage
+	if err := db.Find(&chunkRowUsages).Error; err != nil {
+		return nil, fmt.Errorf("ChunkRowUsage.GetChunkRowUsagesInRange error: %w, start index: %v, end index: %v", err, startIndex, endIndex)
+	}
+	return chunkRowUsages, nil
+}
+
+// GetSubCircuitRowStats aggregates the row usages of every sub-circuit over the chunks of index in [startIndex, endIndex].
+func (o *ChunkRowUsage) GetSubCircuitRowStats(ctx context.Context, startIndex, endIndex uint64) ([]SubCircuitRowStats, error) {
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
+	db = db.Model(&ChunkRowUsage{})
+	db = db.Select("sub_circuit, COUNT(*) AS chunk_count, MAX(row_number) AS max_row_number, AVG(row_number) AS avg_row_number")
+	db = db.Where("chunk_index >= ? AND chunk_index <= ?", startIndex, endIndex)
+	db = db.Group("sub_circuit")
+	db = db.Order("sub_circuit ASC")
+
+	var stats []SubCircuitRowStats
+	if err := db.Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("ChunkRowUsage.GetSubCircuitRowStats error: %w, start index: %v, end index: %v", err, startIndex, endIndex)
+	}
+	return stats, nil
+}
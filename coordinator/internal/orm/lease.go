@@ -0,0 +1,78 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/utils"
+)
+
+// Lease is a named lock shared by the coordinator replicas, it's owned by one replica until it expires or is released.
+type Lease struct {
+	db *gorm.DB `gorm:"-"`
+
+	Name      string    `json:"name" gorm:"column:name;primaryKey"`
+	Owner     string    `json:"owner" gorm:"column:owner"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"column:expires_at"`
+
+	// metadata
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+// NewLease creates a new Lease instance.
+func NewLease(db *gorm.DB) *Lease {
+	return &Lease{db: db}
+}
+
+// TableName returns the name of the "coordinator_lease" table.
+func (*Lease) TableName() string {
+	return "coordinator_lease"
+}
+
+// TryAcquire acquires the lease for the owner, or renews it if the owner already holds it.
+// It returns false if the lease is held by another owner and has not expired yet.
+func (o *Lease) TryAcquire(ctx context.Context, name, owner string, duration time.Duration) (bool, error) {
+	// here why need use UTC time. see scroll/common/databased/db.go
+	now := utils.NowUTC()
+	lease := Lease{
+		Name:      name,
+		Owner:     owner,
+		ExpiresAt: now.Add(duration),
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Lease{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Or(
+				clause.Expr{SQL: "coordinator_lease.owner = ?", Vars: []interface{}{owner}},
+				clause.Expr{SQL: "coordinator_lease.expires_at < ?", Vars: []interface{}{now}},
+			),
+		}},
+		DoUpdates: clause.AssignmentColumns([]string{"owner", "expires_at", "updated_at"}),
+	})
+
+	result := db.Create(&lease)
+	if result.Error != nil {
+		return false, fmt.Errorf("Lease.TryAcquire error: %w, name: %v, owner: %v", result.Error, name, owner)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release releases the lease if it's held by the owner.
+func (o *Lease) Release(ctx context.Context, name, owner string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Lease{})
+	db = db.Where("name = ?", name)
+	db = db.Where("owner = ?", owner)
+	if err := db.Delete(&Lease{}).Error; err != nil {
+		return fmt.Errorf("Lease.Release error: %w, name: %v, owner: %v", err, name, owner)
+	}
+	return nil
+}
@@ -2,9 +2,13 @@ package orm
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -17,9 +21,11 @@ import (
 )
 
 var (
-	testApps      *testcontainers.TestcontainerApps
-	db            *gorm.DB
-	proverTaskOrm *ProverTask
+	testApps       *testcontainers.TestcontainerApps
+	db             *gorm.DB
+	proverTaskOrm  *ProverTask
+	proverScoreOrm *ProverScore
+	leaseOrm       *Lease
 )
 
 func TestMain(m *testing.M) {
@@ -45,6 +51,8 @@ func setupEnv(t *testing.T) {
 	assert.NoError(t, migrate.ResetDB(sqlDB))
 
 	proverTaskOrm = NewProverTask(db)
+	proverScoreOrm = NewProverScore(db)
+	leaseOrm = NewLease(db)
 }
 
 func tearDownEnv(t *testing.T) {
@@ -120,3 +128,768 @@ func TestProverTaskOrmUint256(t *testing.T) {
 	assert.Equal(t, resultRewardUint256, rewardUint256)
 	assert.Equal(t, resultRewardUint256.String(), "115792089237316195423570985008687907853269984665640564039457584007913129639935")
 }
+
+func TestProverScoreOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverScore, err := proverScoreOrm.GetProverScore(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.Nil(t, proverScore)
+
+	assert.NoError(t, proverScoreOrm.IncreaseSuccess(context.Background(), "0", "prover-0", 10))
+	assert.NoError(t, proverScoreOrm.IncreaseSuccess(context.Background(), "0", "prover-0", 20))
+	assert.NoError(t, proverScoreOrm.IncreaseFailure(context.Background(), "0", "prover-0", types.ProverTaskFailureTypeTimeout, message.ProofFailureUndefined))
+	assert.NoError(t, proverScoreOrm.IncreaseFailure(context.Background(), "0", "prover-0", types.ProverTaskFailureTypeSubmitStatusNotOk, message.ProofFailurePanic))
+	assert.NoError(t, proverScoreOrm.IncreaseFailure(context.Background(), "0", "prover-0", types.ProverTaskFailureTypeSubmitStatusNotOk, message.ProofFailureNoPanic))
+	assert.NoError(t, proverScoreOrm.IncreaseFailure(context.Background(), "0", "prover-0", types.ProverTaskFailureTypeVerifiedFailed, message.ProofFailureUndefined))
+	// server errors don't count against the prover
+	assert.NoError(t, proverScoreOrm.IncreaseFailure(context.Background(), "0", "prover-0", types.ProverTaskFailureTypeServerError, message.ProofFailureUndefined))
+
+	proverScore, err = proverScoreOrm.GetProverScore(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.NotNil(t, proverScore)
+	assert.Equal(t, uint64(2), proverScore.SuccessCount)
+	assert.Equal(t, uint64(1), proverScore.TimeoutCount)
+	assert.Equal(t, uint64(1), proverScore.PanicFailureCount)
+	assert.Equal(t, uint64(1), proverScore.NoPanicFailureCount)
+	assert.Equal(t, uint64(1), proverScore.VerifiedFailureCount)
+	assert.Equal(t, uint64(4), proverScore.FailureCount())
+	assert.Equal(t, uint64(15), proverScore.AvgProvingTimeSec())
+	assert.InDelta(t, 0.375, proverScore.SuccessRate(), 1e-9)
+
+	assert.NoError(t, proverScoreOrm.IncreaseSuccess(context.Background(), "1", "prover-1", 5))
+	proverScores, err := proverScoreOrm.GetProverScores(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(proverScores))
+	assert.Equal(t, "0", proverScores[0].PublicKey)
+	assert.Equal(t, "1", proverScores[1].PublicKey)
+}
+
+func TestLeaseOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	acquired, err := leaseOrm.TryAcquire(context.Background(), "lease", "coordinator-0", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// the owner renews its lease, the other replicas can't take it before it expires.
+	acquired, err = leaseOrm.TryAcquire(context.Background(), "lease", "coordinator-0", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = leaseOrm.TryAcquire(context.Background(), "lease", "coordinator-1", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// a released lease is free, and an expired lease is taken over.
+	assert.NoError(t, leaseOrm.Release(context.Background(), "lease", "coordinator-1"))
+	acquired, err = leaseOrm.TryAcquire(context.Background(), "lease", "coordinator-1", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+	assert.NoError(t, leaseOrm.Release(context.Background(), "lease", "coordinator-0"))
+	acquired, err = leaseOrm.TryAcquire(context.Background(), "lease", "coordinator-1", -time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = leaseOrm.TryAcquire(context.Background(), "lease", "coordinator-0", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestProverTaskOrmProgress(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "test-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		Reward:          decimal.NewFromInt(0),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+
+	// another prover can't report the progress of the task
+	updated, err := proverTaskOrm.UpdateProverTaskProgress(context.Background(), proverTask.UUID.String(), "1", message.ProvingStageProving, 50)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	updated, err = proverTaskOrm.UpdateProverTaskProgress(context.Background(), proverTask.UUID.String(), "0", message.ProvingStageProving, 50)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	result, err := proverTaskOrm.GetProverTaskByUUIDAndPublicKey(context.Background(), proverTask.UUID.String(), "0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(message.ProvingStageProving), result.ProgressStage)
+	assert.Equal(t, int16(50), result.ProgressPercent)
+	assert.NotNil(t, result.ProgressReportedAt)
+}
+
+func TestProverTaskOrmCancel(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "test-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		Reward:          decimal.NewFromInt(0),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+
+	// another prover can't cancel the task
+	cancelled, err := proverTaskOrm.CancelProverTask(context.Background(), proverTask.UUID.String(), "1")
+	assert.NoError(t, err)
+	assert.False(t, cancelled)
+
+	cancelled, err = proverTaskOrm.CancelProverTask(context.Background(), proverTask.UUID.String(), "0")
+	assert.NoError(t, err)
+	assert.True(t, cancelled)
+
+	result, err := proverTaskOrm.GetProverTaskByUUIDAndPublicKey(context.Background(), proverTask.UUID.String(), "0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ProverProofInvalid), result.ProvingStatus)
+	assert.Equal(t, int16(types.ProverTaskFailureTypeCancelled), result.FailureType)
+
+	// a task is cancelled once
+	cancelled, err = proverTaskOrm.CancelProverTask(context.Background(), proverTask.UUID.String(), "0")
+	assert.NoError(t, err)
+	assert.False(t, cancelled)
+}
+
+func TestProverTaskOrmDeadline(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	assignedAt := utils.NowUTC().Add(-2 * time.Minute)
+	passed, extended := utils.NowUTC().Add(-time.Second), utils.NowUTC().Add(time.Hour)
+	for i, deadline := range []*time.Time{nil, &passed, &extended} {
+		proverTask := ProverTask{
+			TaskType:                int16(message.ProofTypeChunk),
+			TaskID:                  fmt.Sprintf("test-hash-%d", i),
+			ProverName:              "prover-0",
+			ProverPublicKey:         "0",
+			ProvingStatus:           int16(types.ProverAssigned),
+			Reward:                  decimal.NewFromInt(0),
+			AssignedAt:              assignedAt,
+			EstimatedProvingTimeSec: 100,
+			Deadline:                deadline,
+		}
+		assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+	}
+
+	// the task without a deadline times out after the timeout, the extended one is kept.
+	proverTasks, err := proverTaskOrm.GetTimeoutAssignedProverTasks(context.Background(), 10, message.ProofTypeChunk, time.Minute)
+	assert.NoError(t, err)
+	var taskIDs []string
+	for _, proverTask := range proverTasks {
+		taskIDs = append(taskIDs, proverTask.TaskID)
+	}
+	assert.ElementsMatch(t, []string{"test-hash-0", "test-hash-1"}, taskIDs)
+
+	assert.NoError(t, proverTaskOrm.UpdateProverTaskProvingStatusAndFailureType(context.Background(), proverTasks[0].UUID, types.ProverProofValid, types.ProverTaskFailureTypeUndefined))
+	assert.NoError(t, proverTaskOrm.UpdateProverTaskProvingTime(context.Background(), proverTasks[0].UUID, 150))
+	estimated, err := proverTaskOrm.GetEstimatedProverTasks(context.Background(), "0", message.ProofTypeChunk, 10)
+	assert.NoError(t, err)
+	assert.Len(t, estimated, 1)
+	assert.Equal(t, uint64(100), estimated[0].EstimatedProvingTimeSec)
+	assert.Equal(t, uint64(150), estimated[0].ProvingTimeSec)
+}
+
+func TestProverTaskOrmSubmitNonce(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	maxNonce, err := proverTaskOrm.GetMaxSubmitNonceByPublicKey(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), maxNonce)
+
+	proverTask := ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "test-hash",
+		ProverName:      "prover-0",
+		ProverPublicKey: "0",
+		ProvingStatus:   int16(types.ProverAssigned),
+		Reward:          decimal.NewFromInt(0),
+		AssignedAt:      utils.NowUTC(),
+	}
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &proverTask))
+
+	updated, err := proverTaskOrm.UpdateProverTaskSubmitNonce(context.Background(), proverTask.UUID, 10)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	// a duplicate or an older nonce is rejected
+	updated, err = proverTaskOrm.UpdateProverTaskSubmitNonce(context.Background(), proverTask.UUID, 10)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	updated, err = proverTaskOrm.UpdateProverTaskSubmitNonce(context.Background(), proverTask.UUID, 9)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	maxNonce, err = proverTaskOrm.GetMaxSubmitNonceByPublicKey(context.Background(), "0")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), maxNonce)
+}
+
+func TestChunkOrmReuseProof(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	chunkOrm := NewChunk(db)
+	provedAt := utils.NowUTC()
+	proved := &Chunk{Index: 0, Hash: "chunk-0", ProvingStatus: int16(types.ProvingTaskVerified), Proof: []byte(`{"proof":"0x01"}`), ProvedAt: &provedAt, TaskContentHash: "content-0"}
+	assert.NoError(t, db.Create(proved).Error)
+	// the chunks deleted by a re-chunking still share their proofs.
+	assert.NoError(t, db.Delete(&Chunk{}, "hash = ?", "chunk-0").Error)
+	assert.NoError(t, db.Create(&Chunk{Index: 1, Hash: "chunk-1", ProvingStatus: int16(types.ProvingTaskUnassigned)}).Error)
+	assert.NoError(t, db.Create(&Chunk{Index: 2, Hash: "chunk-2", ProvingStatus: int16(types.ProvingTaskUnassigned)}).Error)
+
+	reused, err := chunkOrm.ReuseProofByTaskContentHash(context.Background(), "chunk-1", "content-1")
+	assert.NoError(t, err)
+	assert.False(t, reused)
+	assert.NoError(t, chunkOrm.UpdateTaskContentHash(context.Background(), "chunk-1", "content-1"))
+
+	reused, err = chunkOrm.ReuseProofByTaskContentHash(context.Background(), "chunk-2", "content-0")
+	assert.NoError(t, err)
+	assert.True(t, reused)
+
+	chunk, err := chunkOrm.GetChunkByHash(context.Background(), "chunk-2")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ProvingTaskVerified), chunk.ProvingStatus)
+	assert.Equal(t, proved.Proof, chunk.Proof)
+	assert.Equal(t, "content-0", chunk.TaskContentHash)
+	assert.NotNil(t, chunk.ProvedAt)
+
+	chunk, err = chunkOrm.GetChunkByHash(context.Background(), "chunk-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ProvingTaskUnassigned), chunk.ProvingStatus)
+	assert.Equal(t, "content-1", chunk.TaskContentHash)
+}
+
+func TestTaskResetOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	chunkOrm := NewChunk(db)
+	batchOrm := NewBatch(db)
+	auditLogOrm := NewAdminAuditLog(db)
+	provedAt := utils.NowUTC()
+	assert.NoError(t, db.Create(&Batch{Index: 0, Hash: "batch-0", BatchHeader: []byte{0x01}, ChunkProofsStatus: int16(types.ChunkProofsStatusReady), ProvingStatus: int16(types.ProvingTaskVerified), Proof: []byte(`{"proof":"0x02"}`), ProofHash: "0x02", ProvedAt: &provedAt, TotalAttempts: 2, FailedAttempts: 1}).Error)
+	assert.NoError(t, db.Create(&Chunk{Index: 0, Hash: "chunk-0", BatchHash: "batch-0", ProvingStatus: int16(types.ProvingTaskAssigned), ProverAssignedAt: &provedAt, TotalAttempts: 3, ActiveAttempts: 1, FailedAttempts: 2}).Error)
+	for i, status := range []types.ProverProveStatus{types.ProverAssigned, types.ProverProofValid} {
+		assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &ProverTask{
+			TaskType:        int16(message.ProofTypeChunk),
+			TaskID:          "chunk-0",
+			ProverName:      fmt.Sprintf("prover-%d", i),
+			ProverPublicKey: fmt.Sprintf("%d", i),
+			ProvingStatus:   int16(status),
+			Reward:          decimal.NewFromInt(0),
+			AssignedAt:      utils.NowUTC(),
+		}))
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// only the assigned prover tasks are failed.
+		failed, resetErr := proverTaskOrm.ResetAssignedProverTasks(context.Background(), message.ProofTypeChunk, "chunk-0", tx)
+		assert.Equal(t, int64(1), failed)
+		if resetErr != nil {
+			return resetErr
+		}
+		if resetErr = chunkOrm.ResetTaskByHash(context.Background(), "chunk-0", tx); resetErr != nil {
+			return resetErr
+		}
+		return batchOrm.UpdateChunkProofsStatusByBatchHash(context.Background(), "batch-0", types.ChunkProofsStatusPending, tx)
+	})
+	assert.NoError(t, err)
+
+	chunk, err := chunkOrm.GetChunkByHash(context.Background(), "chunk-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ProvingTaskUnassigned), chunk.ProvingStatus)
+	assert.Nil(t, chunk.ProverAssignedAt)
+	assert.Equal(t, int16(0), chunk.TotalAttempts)
+	assert.Equal(t, int16(0), chunk.ActiveAttempts)
+	assert.Equal(t, int16(0), chunk.FailedAttempts)
+
+	proverTask, err := proverTaskOrm.GetProverTasks(context.Background(), map[string]interface{}{"prover_public_key = ?": "0"}, nil, 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, proverTask, 1)
+	assert.Equal(t, int16(types.ProverProofInvalid), proverTask[0].ProvingStatus)
+	assert.Equal(t, int16(types.ProverTaskFailureTypeReset), proverTask[0].FailureType)
+	proverTask, err = proverTaskOrm.GetProverTasks(context.Background(), map[string]interface{}{"prover_public_key = ?": "1"}, nil, 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, proverTask, 1)
+	assert.Equal(t, int16(types.ProverProofValid), proverTask[0].ProvingStatus)
+
+	assert.NoError(t, batchOrm.ResetTaskByHash(context.Background(), "batch-0"))
+	batch, err := batchOrm.GetBatchByHash(context.Background(), "batch-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ChunkProofsStatusPending), batch.ChunkProofsStatus)
+	assert.Equal(t, int16(types.ProvingTaskUnassigned), batch.ProvingStatus)
+	assert.Empty(t, batch.Proof)
+	assert.Empty(t, batch.ProofHash)
+	assert.Nil(t, batch.ProvedAt)
+	assert.Equal(t, int16(0), batch.TotalAttempts)
+	assert.Equal(t, int16(0), batch.FailedAttempts)
+
+	assert.NoError(t, auditLogOrm.InsertAdminAuditLog(context.Background(), &AdminAuditLog{Action: "task_reset", TargetType: "chunk", TargetKey: "chunk-0", Reason: "wedged"}))
+	assert.NoError(t, auditLogOrm.InsertAdminAuditLog(context.Background(), &AdminAuditLog{Action: "task_reset", TargetType: "batch", TargetKey: "batch-0", Operator: "ops", Reason: "bad proof", Details: `{"proving_status":"ProvingTaskVerified"}`}))
+	logs, err := auditLogOrm.GetAdminAuditLogs(context.Background(), "", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "batch-0", logs[0].TargetKey)
+	assert.Equal(t, "ops", logs[0].Operator)
+	logs, err = auditLogOrm.GetAdminAuditLogs(context.Background(), "chunk-0", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "wedged", logs[0].Reason)
+}
+
+func TestProofFailureRetryOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	chunkOrm := NewChunk(db)
+	batchOrm := NewBatch(db)
+	assert.NoError(t, db.Create(&Chunk{Index: 0, Hash: "chunk-0", ProvingStatus: int16(types.ProvingTaskUnassigned)}).Error)
+	assert.NoError(t, db.Create(&Batch{Index: 0, Hash: "batch-0", BatchHeader: []byte{0x01}, ProvingStatus: int16(types.ProvingTaskAssigned)}).Error)
+
+	// the undefined failures of the old provers aren't counted.
+	for _, failureType := range []message.ProofFailureType{message.ProofFailurePanic, message.ProofFailureNoPanic, message.ProofFailureNoPanic, message.ProofFailureUndefined} {
+		assert.NoError(t, chunkOrm.IncreaseProofFailedAttemptsByHash(context.Background(), "chunk-0", failureType))
+	}
+	chunk, err := chunkOrm.GetChunkByHash(context.Background(), "chunk-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(1), chunk.PanicFailedAttempts)
+	assert.Equal(t, int16(2), chunk.NoPanicFailedAttempts)
+
+	quarantined, err := chunkOrm.QuarantineByHash(context.Background(), "chunk-0", 2, 3)
+	assert.NoError(t, err)
+	assert.False(t, quarantined)
+	quarantined, err = chunkOrm.QuarantineByHash(context.Background(), "chunk-0", 0, 0)
+	assert.NoError(t, err)
+	assert.False(t, quarantined)
+	quarantined, err = chunkOrm.QuarantineByHash(context.Background(), "chunk-0", 0, 2)
+	assert.NoError(t, err)
+	assert.True(t, quarantined)
+	chunk, err = chunkOrm.GetChunkByHash(context.Background(), "chunk-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ProvingTaskQuarantined), chunk.ProvingStatus)
+
+	// the operator reset clears the failures.
+	assert.NoError(t, chunkOrm.ResetTaskByHash(context.Background(), "chunk-0"))
+	chunk, err = chunkOrm.GetChunkByHash(context.Background(), "chunk-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ProvingTaskUnassigned), chunk.ProvingStatus)
+	assert.Equal(t, int16(0), chunk.PanicFailedAttempts)
+	assert.Equal(t, int16(0), chunk.NoPanicFailedAttempts)
+
+	assert.NoError(t, batchOrm.IncreaseProofFailedAttemptsByHash(context.Background(), "batch-0", message.ProofFailurePanic))
+	quarantined, err = batchOrm.QuarantineByHash(context.Background(), "batch-0", 1, 0)
+	assert.NoError(t, err)
+	assert.True(t, quarantined)
+	// a quarantined batch isn't quarantined again.
+	quarantined, err = batchOrm.QuarantineByHash(context.Background(), "batch-0", 1, 0)
+	assert.NoError(t, err)
+	assert.False(t, quarantined)
+	batch, err := batchOrm.GetBatchByHash(context.Background(), "batch-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int16(types.ProvingTaskQuarantined), batch.ProvingStatus)
+	assert.Equal(t, int16(1), batch.PanicFailedAttempts)
+
+	// a prover failed the task until it's upgraded.
+	assert.NoError(t, proverTaskOrm.InsertProverTask(context.Background(), &ProverTask{
+		TaskType:        int16(message.ProofTypeChunk),
+		TaskID:          "chunk-0",
+		ProverName:      "prover-0",
+		ProverPublicKey: "0",
+		ProverVersion:   "v1",
+		ProvingStatus:   int16(types.ProverProofInvalid),
+		FailureType:     int16(types.ProverTaskFailureTypeSubmitStatusNotOk),
+		Reward:          decimal.NewFromInt(0),
+		AssignedAt:      utils.NowUTC(),
+	}))
+	failed, err := proverTaskOrm.IsTaskFailedByProver(context.Background(), message.ProofTypeChunk, "chunk-0", "0", "v1")
+	assert.NoError(t, err)
+	assert.True(t, failed)
+	failed, err = proverTaskOrm.IsTaskFailedByProver(context.Background(), message.ProofTypeChunk, "chunk-0", "0", "v2")
+	assert.NoError(t, err)
+	assert.False(t, failed)
+	failed, err = proverTaskOrm.IsTaskFailedByProver(context.Background(), message.ProofTypeChunk, "chunk-0", "1", "v1")
+	assert.NoError(t, err)
+	assert.False(t, failed)
+}
+
+func TestTaskPriorityOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	chunkOrm := NewChunk(db)
+	batchOrm := NewBatch(db)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, db.Create(&Chunk{Index: uint64(i), Hash: fmt.Sprintf("chunk-%d", i), StartBlockNumber: uint64(i), EndBlockNumber: uint64(i), ProvingStatus: int16(types.ProvingTaskUnassigned)}).Error)
+	}
+	chunk, err := chunkOrm.GetUnassignedChunk(context.Background(), 0, 100, 1, 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk-0", chunk.Hash)
+	assert.Equal(t, int16(message.TaskPriorityNormal), chunk.Priority)
+
+	// an urgent task jumps the queue.
+	assert.NoError(t, chunkOrm.UpdatePriorityByHash(context.Background(), "chunk-3", message.TaskPriorityHigh, nil))
+	chunk, err = chunkOrm.GetUnassignedChunk(context.Background(), 0, 100, 1, 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk-3", chunk.Hash)
+	priority, ok, err := chunkOrm.GetTopUnassignedPriority(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, message.TaskPriorityHigh, priority)
+
+	// the earliest deadline goes first within a priority.
+	deadline := utils.NowUTC().Add(time.Hour)
+	assert.NoError(t, chunkOrm.UpdatePriorityByHash(context.Background(), "chunk-2", message.TaskPriorityHigh, &deadline))
+	chunk, err = chunkOrm.GetUnassignedChunk(context.Background(), 0, 100, 1, 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk-2", chunk.Hash)
+	// the skipped tasks are excluded.
+	chunk, err = chunkOrm.GetUnassignedChunk(context.Background(), 0, 100, 1, 10, []string{"chunk-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk-3", chunk.Hash)
+
+	// the backlog goes last.
+	assert.NoError(t, chunkOrm.UpdatePriorityByHash(context.Background(), "chunk-0", message.TaskPriorityLow, nil))
+	chunk, err = chunkOrm.GetUnassignedChunk(context.Background(), 0, 100, 1, 10, []string{"chunk-2", "chunk-3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk-1", chunk.Hash)
+
+	err = chunkOrm.UpdatePriorityByHash(context.Background(), "chunk-4", message.TaskPriorityHigh, nil)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// the batches are urgent by default, only the batches of ready chunk proofs are counted.
+	_, ok, err = batchOrm.GetTopUnassignedPriority(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, db.Create(&Batch{Index: 0, Hash: "batch-0", BatchHeader: []byte{0x01}, ChunkProofsStatus: int16(types.ChunkProofsStatusReady), ProvingStatus: int16(types.ProvingTaskUnassigned)}).Error)
+	priority, ok, err = batchOrm.GetTopUnassignedPriority(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, message.TaskPriorityHigh, priority)
+}
+
+func TestProofUploadPartOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	proofUploadPartOrm := NewProofUploadPart(db)
+	taskUUID := uuid.NewString()
+	parts := [][]byte{[]byte(`{"proof":`), []byte(`"0x01"}`)}
+	partHashes := make([]string, len(parts))
+	for i, part := range parts {
+		partHashes[i] = crypto.Keccak256Hash(part).Hex()
+		assert.NoError(t, proofUploadPartOrm.InsertProofUploadPart(context.Background(), taskUUID, partHashes[i], part))
+	}
+	// uploading a part again is a no-op.
+	assert.NoError(t, proofUploadPartOrm.InsertProofUploadPart(context.Background(), taskUUID, partHashes[0], parts[0]))
+	assert.NoError(t, proofUploadPartOrm.InsertProofUploadPart(context.Background(), uuid.NewString(), partHashes[0], parts[0]))
+
+	uploaded, err := proofUploadPartOrm.GetProofUploadPartHashes(context.Background(), taskUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, partHashes, uploaded)
+
+	data, err := proofUploadPartOrm.GetProofUploadParts(context.Background(), taskUUID, []string{partHashes[1], "0x00"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{partHashes[1]: parts[1]}, data)
+
+	assert.NoError(t, proofUploadPartOrm.DeleteProofUploadParts(context.Background(), taskUUID))
+	uploaded, err = proofUploadPartOrm.GetProofUploadPartHashes(context.Background(), taskUUID)
+	assert.NoError(t, err)
+	assert.Empty(t, uploaded)
+
+	// only the parts of the other task are left, until they expire.
+	assert.NoError(t, proofUploadPartOrm.DeleteExpiredProofUploadParts(context.Background(), utils.NowUTC().Add(-time.Hour)))
+	var count int64
+	assert.NoError(t, db.Model(&ProofUploadPart{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	assert.NoError(t, proofUploadPartOrm.DeleteExpiredProofUploadParts(context.Background(), utils.NowUTC().Add(time.Hour)))
+	assert.NoError(t, db.Model(&ProofUploadPart{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestChunkRowUsageOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	chunkRowUsageOrm := NewChunkRowUsage(db)
+	latestIndex, err := chunkRowUsageOrm.GetLatestChunkIndex(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, latestIndex)
+
+	chunk0 := &Chunk{Index: 0, Hash: "chunk-0", StartBlockNumber: 1, EndBlockNumber: 10}
+	chunk1 := &Chunk{Index: 1, Hash: "chunk-1", StartBlockNumber: 11, EndBlockNumber: 20}
+	assert.NoError(t, chunkRowUsageOrm.InsertChunkRowUsages(context.Background(), chunk0, []message.SubCircuitRowUsage{
+		{Name: "evm", RowNumber: 100},
+		{Name: "keccak", RowNumber: 50},
+	}))
+	assert.NoError(t, chunkRowUsageOrm.InsertChunkRowUsages(context.Background(), chunk1, []message.SubCircuitRowUsage{
+		{Name: "evm", RowNumber: 300},
+		{Name: "keccak", RowNumber: 10},
+	}))
+	// the row usages of a chunk proved again are overwritten.
+	assert.NoError(t, chunkRowUsageOrm.InsertChunkRowUsages(context.Background(), chunk0, []message.SubCircuitRowUsage{{Name: "keccak", RowNumber: 70}}))
+
+	latestIndex, err = chunkRowUsageOrm.GetLatestChunkIndex(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, latestIndex)
+	assert.Equal(t, uint64(1), *latestIndex)
+
+	rowUsages, err := chunkRowUsageOrm.GetChunkRowUsagesInRange(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(rowUsages))
+	assert.Equal(t, "evm", rowUsages[0].SubCircuit)
+	assert.Equal(t, "keccak", rowUsages[1].SubCircuit)
+	assert.Equal(t, uint64(70), rowUsages[1].RowNumber)
+	assert.Equal(t, uint64(10), rowUsages[1].EndBlockNumber)
+
+	stats, err := chunkRowUsageOrm.GetSubCircuitRowStats(context.Background(), 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(stats))
+	assert.Equal(t, "evm", stats[0].SubCircuit)
+	assert.Equal(t, uint64(2), stats[0].ChunkCount)
+	assert.Equal(t, uint64(300), stats[0].MaxRowNumber)
+	assert.InDelta(t, 200, stats[0].AvgRowNumber, 1e-9)
+	assert.Equal(t, "keccak", stats[1].SubCircuit)
+	assert.Equal(t, uint64(70), stats[1].MaxRowNumber)
+}
+
+func TestWorkReceiptOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	privKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	workReceiptOrm := NewWorkReceipt(db)
+	for i, publicKey := range []string{"prover-0", "prover-1", "prover-0"} {
+		receipt := &WorkReceipt{
+			UUID:            uuid.NewString(),
+			TaskID:          fmt.Sprintf("task-%d", i),
+			TaskType:        int16(message.ProofTypeChunk),
+			ProverPublicKey: publicKey,
+			ProverName:      publicKey,
+			ProofHash:       crypto.Keccak256Hash([]byte{byte(i)}).String(),
+			ProvingTimeSec:  uint64(100 + i),
+			AcceptedAt:      time.Unix(1717000000+int64(i), 0).UTC(),
+		}
+		receipt.Signature, err = receipt.Message().Sign(privKey)
+		assert.NoError(t, err)
+		assert.NoError(t, workReceiptOrm.InsertWorkReceipt(context.Background(), receipt))
+		// the receipt of a prover task is only recorded once.
+		assert.NoError(t, workReceiptOrm.InsertWorkReceipt(context.Background(), &WorkReceipt{UUID: receipt.UUID, TaskID: "other", AcceptedAt: receipt.AcceptedAt}))
+	}
+
+	receipts, err := workReceiptOrm.GetWorkReceipts(context.Background(), 0, "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(receipts))
+	assert.Equal(t, "task-0", receipts[0].TaskID)
+	assert.Equal(t, "task-1", receipts[1].TaskID)
+
+	receipts, err = workReceiptOrm.GetWorkReceipts(context.Background(), receipts[1].ID, "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(receipts))
+	assert.Equal(t, "task-2", receipts[0].TaskID)
+
+	receipts, err = workReceiptOrm.GetWorkReceipts(context.Background(), 0, "prover-0", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(receipts))
+	for _, receipt := range receipts {
+		signer, err := receipt.Message().Signer(receipt.Signature)
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(privKey.PublicKey), signer)
+	}
+}
+
+func TestProverAccessControlOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	allowListOrm := NewProverAllowList(db)
+	assert.NoError(t, allowListOrm.InsertProverPublicKey(context.Background(), "prover-0", "key-0"))
+	assert.NoError(t, allowListOrm.InsertProverPublicKey(context.Background(), "prover-1", "key-1"))
+	// allowing a key again renames the prover.
+	assert.NoError(t, allowListOrm.InsertProverPublicKey(context.Background(), "prover-0-renamed", "key-0"))
+	allowed, err := allowListOrm.GetAllowedProvers(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(allowed))
+	isAllowed, err := allowListOrm.IsPublicKeyAllowed(context.Background(), "key-0")
+	assert.NoError(t, err)
+	assert.True(t, isAllowed)
+
+	deleted, err := allowListOrm.DeleteProverPublicKey(context.Background(), "key-0")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = allowListOrm.DeleteProverPublicKey(context.Background(), "key-0")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	isAllowed, err = allowListOrm.IsPublicKeyAllowed(context.Background(), "key-0")
+	assert.NoError(t, err)
+	assert.False(t, isAllowed)
+	// a removed key can be allowed again.
+	assert.NoError(t, allowListOrm.InsertProverPublicKey(context.Background(), "prover-0", "key-0"))
+
+	blockListOrm := NewProverBlockList(db)
+	expired := utils.NowUTC().Add(-time.Minute)
+	assert.NoError(t, blockListOrm.BlockProverPublicKey(context.Background(), "prover-0", "key-0", "spam", &expired))
+	assert.NoError(t, blockListOrm.BlockProverPublicKey(context.Background(), "prover-1", "key-1", "invalid proofs", nil))
+	isBlocked, err := blockListOrm.IsPublicKeyBlocked(context.Background(), "key-0")
+	assert.NoError(t, err)
+	assert.False(t, isBlocked)
+	blocked, err := blockListOrm.GetBlockedProvers(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(blocked))
+	assert.Equal(t, "key-1", blocked[0].PublicKey)
+	assert.Equal(t, "invalid proofs", blocked[0].Reason)
+	assert.Nil(t, blocked[0].ExpiresAt)
+
+	// blocking a key again extends the ban.
+	expiresAt := utils.NowUTC().Add(time.Hour)
+	assert.NoError(t, blockListOrm.BlockProverPublicKey(context.Background(), "prover-0", "key-0", "spam", &expiresAt))
+	isBlocked, err = blockListOrm.IsPublicKeyBlocked(context.Background(), "key-0")
+	assert.NoError(t, err)
+	assert.True(t, isBlocked)
+	assert.NoError(t, blockListOrm.DeleteProverPublicKey(context.Background(), "key-0"))
+	isBlocked, err = blockListOrm.IsPublicKeyBlocked(context.Background(), "key-0")
+	assert.NoError(t, err)
+	assert.False(t, isBlocked)
+
+	sessionOrm := NewProverSession(db)
+	now := utils.NowUTC()
+	for i, expiresAt := range []time.Time{now.Add(time.Hour), now.Add(-time.Second)} {
+		assert.NoError(t, sessionOrm.UpsertProverSession(context.Background(), &ProverSession{
+			PublicKey:     fmt.Sprintf("key-%d", i),
+			ProverName:    fmt.Sprintf("prover-%d", i),
+			ProverVersion: "v4.4.0",
+			LoggedInAt:    now.Add(-time.Minute),
+			ExpiresAt:     expiresAt,
+		}))
+	}
+	sessions, err := sessionOrm.GetActiveProverSessions(context.Background(), "", now, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, "key-0", sessions[0].PublicKey)
+
+	// a login again refreshes the session.
+	assert.NoError(t, sessionOrm.UpsertProverSession(context.Background(), &ProverSession{
+		PublicKey:     "key-1",
+		ProverName:    "prover-1",
+		ProverVersion: "v4.4.1",
+		LoggedInAt:    now,
+		ExpiresAt:     now.Add(time.Hour),
+	}))
+	sessions, err = sessionOrm.GetActiveProverSessions(context.Background(), "key-1", now, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, "v4.4.1", sessions[0].ProverVersion)
+
+	assert.NoError(t, sessionOrm.DeleteProverSession(context.Background(), "key-1"))
+	sessions, err = sessionOrm.GetActiveProverSessions(context.Background(), "", now, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sessions))
+}
+
+func TestTaskStatsOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	chunkOrm := NewChunk(db)
+	batchOrm := NewBatch(db)
+	oldest, err := chunkOrm.GetOldestUnassignedCreatedAt(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, oldest)
+	counts, err := chunkOrm.CountByProvingStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+
+	now := utils.NowUTC().Truncate(time.Second)
+	recentlyProved, longAgoProved := now.Add(-10*time.Minute), now.Add(-2*time.Hour)
+	chunks := []*Chunk{
+		{Index: 0, Hash: "chunk-0", ProvingStatus: int16(types.ProvingTaskVerified), ProvedAt: &longAgoProved, CreatedAt: now.Add(-3 * time.Hour)},
+		{Index: 1, Hash: "chunk-1", ProvingStatus: int16(types.ProvingTaskVerified), ProvedAt: &recentlyProved, CreatedAt: now.Add(-time.Hour)},
+		{Index: 2, Hash: "chunk-2", ProvingStatus: int16(types.ProvingTaskAssigned), CreatedAt: now.Add(-50 * time.Minute)},
+		{Index: 3, Hash: "chunk-3", ProvingStatus: int16(types.ProvingTaskUnassigned), CreatedAt: now.Add(-40 * time.Minute)},
+		{Index: 4, Hash: "chunk-4", ProvingStatus: int16(types.ProvingTaskUnassigned), CreatedAt: now.Add(-30 * time.Minute)},
+		{Index: 5, Hash: "chunk-5", ProvingStatus: int16(types.ProvingTaskFailed), CreatedAt: now.Add(-20 * time.Minute)},
+	}
+	for _, chunk := range chunks {
+		assert.NoError(t, db.Create(chunk).Error)
+	}
+
+	counts, err = chunkOrm.CountByProvingStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[types.ProvingStatus]int64{
+		types.ProvingTaskVerified:   2,
+		types.ProvingTaskAssigned:   1,
+		types.ProvingTaskUnassigned: 2,
+		types.ProvingTaskFailed:     1,
+	}, counts)
+
+	oldest, err = chunkOrm.GetOldestUnassignedCreatedAt(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, oldest)
+	assert.True(t, now.Add(-40*time.Minute).Equal(*oldest))
+
+	// only the chunks verified since the given time are counted.
+	verified, err := chunkOrm.CountVerifiedSince(context.Background(), now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), verified)
+	verified, err = chunkOrm.CountVerifiedSince(context.Background(), now.Add(-3*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), verified)
+
+	// the deleted chunks aren't counted.
+	assert.NoError(t, db.Delete(&Chunk{}, "hash = ?", "chunk-3").Error)
+	counts, err = chunkOrm.CountByProvingStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), counts[types.ProvingTaskUnassigned])
+	oldest, err = chunkOrm.GetOldestUnassignedCreatedAt(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, oldest)
+	assert.True(t, now.Add(-30*time.Minute).Equal(*oldest))
+
+	assert.NoError(t, db.Create(&Batch{Index: 0, Hash: "batch-0", BatchHeader: []byte{0x01}, ChunkProofsStatus: int16(types.ChunkProofsStatusReady), ProvingStatus: int16(types.ProvingTaskVerified), ProvedAt: &recentlyProved, CreatedAt: now.Add(-2 * time.Hour)}).Error)
+	assert.NoError(t, db.Create(&Batch{Index: 1, Hash: "batch-1", BatchHeader: []byte{0x01}, ChunkProofsStatus: int16(types.ChunkProofsStatusPending), ProvingStatus: int16(types.ProvingTaskUnassigned), CreatedAt: now.Add(-time.Hour)}).Error)
+	assert.NoError(t, db.Create(&Batch{Index: 2, Hash: "batch-2", BatchHeader: []byte{0x01}, ChunkProofsStatus: int16(types.ChunkProofsStatusReady), ProvingStatus: int16(types.ProvingTaskUnassigned), CreatedAt: now.Add(-30 * time.Minute)}).Error)
+
+	counts, err = batchOrm.CountByProvingStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[types.ProvingStatus]int64{
+		types.ProvingTaskVerified:   1,
+		types.ProvingTaskUnassigned: 2,
+	}, counts)
+	// a batch waiting for its chunk proofs isn't waiting for a prover.
+	oldest, err = batchOrm.GetOldestUnassignedCreatedAt(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, oldest)
+	assert.True(t, now.Add(-30*time.Minute).Equal(*oldest))
+	verified, err = batchOrm.CountVerifiedSince(context.Background(), now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), verified)
+}
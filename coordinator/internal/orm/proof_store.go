@@ -0,0 +1,32 @@
+package orm
+
+import (
+	"context"
+
+	"scroll-tech/common/objectstore"
+)
+
+// proofFields returns the columns storing the encoded proof, the proof is offloaded to the
+// proof store if one is configured and only its content hash and uri are kept in the database.
+func proofFields(ctx context.Context, store objectstore.Store, prefix string, proofBytes []byte) (map[string]interface{}, error) {
+	if store == nil {
+		return map[string]interface{}{"proof": proofBytes}, nil
+	}
+	hash, uri, err := objectstore.Upload(ctx, store, prefix, proofBytes)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"proof":      nil,
+		"proof_hash": hash,
+		"proof_uri":  uri,
+	}, nil
+}
+
+// loadProof returns the encoded proof, downloading it from the proof store if it was offloaded.
+func loadProof(ctx context.Context, store objectstore.Store, proofBytes []byte, uri, hash string) ([]byte, error) {
+	if uri == "" {
+		return proofBytes, nil
+	}
+	return objectstore.Download(ctx, store, uri, hash)
+}
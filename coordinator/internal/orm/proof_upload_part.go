@@ -0,0 +1,114 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProofUploadPart is a part of a proof uploaded in parts for a prover task, the proof is the concatenation of
+// the parts listed by the submission.
+type ProofUploadPart struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID   uint64 `json:"id" gorm:"column:id;primaryKey"`
+	UUID string `json:"uuid" gorm:"column:uuid"`
+	// PartHash is the hex keccak256 hash of the data, the part is addressed by it.
+	PartHash string `json:"part_hash" gorm:"column:part_hash"`
+	Data     []byte `json:"data" gorm:"column:data"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProofUploadPart creates a new ProofUploadPart instance.
+func NewProofUploadPart(db *gorm.DB) *ProofUploadPart {
+	return &ProofUploadPart{db: db}
+}
+
+// TableName returns the name of the "proof_upload_part" table.
+func (*ProofUploadPart) TableName() string {
+	return "proof_upload_part"
+}
+
+// InsertProofUploadPart stores a part of the proof of the prover task, uploading a part again is a no-op.
+func (o *ProofUploadPart) InsertProofUploadPart(ctx context.Context, uuid, partHash string, data []byte) error {
+	part := ProofUploadPart{
+		UUID:     uuid,
+		PartHash: partHash,
+		Data:     data,
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProofUploadPart{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "uuid"}, {Name: "part_hash"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	})
+	if err := db.Create(&part).Error; err != nil {
+		return fmt.Errorf("ProofUploadPart.InsertProofUploadPart error: %w, uuid: %v, part hash: %v", err, uuid, partHash)
+	}
+	return nil
+}
+
+// GetProofUploadPartHashes returns the hashes of the parts uploaded for the prover task.
+func (o *ProofUploadPart) GetProofUploadPartHashes(ctx context.Context, uuid string) ([]string, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProofUploadPart{})
+	db = db.Where("uuid = ?", uuid)
+	db = db.Order("id ASC")
+
+	var partHashes []string
+	if err := db.Pluck("part_hash", &partHashes).Error; err != nil {
+		return nil, fmt.Errorf("ProofUploadPart.GetProofUploadPartHashes error: %w, uuid: %v", err, uuid)
+	}
+	return partHashes, nil
+}
+
+// GetProofUploadParts returns the parts of the given hashes uploaded for the prover task, keyed by their hash.
+func (o *ProofUploadPart) GetProofUploadParts(ctx context.Context, uuid string, partHashes []string) (map[string][]byte, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProofUploadPart{})
+	db = db.Select("part_hash, data")
+	db = db.Where("uuid = ?", uuid)
+	db = db.Where("part_hash IN (?)", partHashes)
+
+	var parts []ProofUploadPart
+	if err := db.Find(&parts).Error; err != nil {
+		return nil, fmt.Errorf("ProofUploadPart.GetProofUploadParts error: %w, uuid: %v", err, uuid)
+	}
+
+	data := make(map[string][]byte, len(parts))
+	for i := range parts {
+		data[parts[i].PartHash] = parts[i].Data
+	}
+	return data, nil
+}
+
+// DeleteProofUploadParts deletes the parts uploaded for the prover task.
+func (o *ProofUploadPart) DeleteProofUploadParts(ctx context.Context, uuid string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProofUploadPart{})
+	db = db.Where("uuid = ?", uuid)
+	if err := db.Unscoped().Delete(&ProofUploadPart{}).Error; err != nil {
+		return fmt.Errorf("ProofUploadPart.DeleteProofUploadParts error: %w, uuid: %v", err, uuid)
+	}
+	return nil
+}
+
+// DeleteExpiredProofUploadParts deletes the parts uploaded before expiredTime, of the uploads never completed.
+func (o *ProofUploadPart) DeleteExpiredProofUploadParts(ctx context.Context, expiredTime time.Time) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProofUploadPart{})
+	db = db.Where("created_at < ?", expiredTime)
+	if err := db.Unscoped().Delete(&ProofUploadPart{}).Error; err != nil {
+		return fmt.Errorf("ProofUploadPart.DeleteExpiredProofUploadParts error: %w, expired time: %v", err, expiredTime)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProverAllowList represents a prover public key allowed to log in when the allow list is required.
+type ProverAllowList struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID         uint   `json:"id" gorm:"column:id;primaryKey"`
+	PublicKey  string `json:"public_key" gorm:"column:public_key"`
+	ProverName string `json:"prover_name" gorm:"column:prover_name"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverAllowList creates a new ProverAllowList instance.
+func NewProverAllowList(db *gorm.DB) *ProverAllowList {
+	return &ProverAllowList{db: db}
+}
+
+// TableName returns the name of the "prover_allow_list" table.
+func (*ProverAllowList) TableName() string {
+	return "prover_allow_list"
+}
+
+// InsertProverPublicKey adds a prover public key to the allow list, the prover name of an allowed public key
+// is updated.
+func (p *ProverAllowList) InsertProverPublicKey(ctx context.Context, proverName, publicKey string) error {
+	prover := ProverAllowList{
+		ProverName: proverName,
+		PublicKey:  publicKey,
+	}
+
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverAllowList{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "public_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"prover_name", "updated_at"}),
+	})
+	if err := db.Create(&prover).Error; err != nil {
+		return fmt.Errorf("ProverAllowList.InsertProverPublicKey error: %w, prover name: %v, public key: %v", err, proverName, publicKey)
+	}
+	return nil
+}
+
+// DeleteProverPublicKey removes a prover public key from the allow list, it returns false if it's not allowed.
+func (p *ProverAllowList) DeleteProverPublicKey(ctx context.Context, publicKey string) (bool, error) {
+	db := p.db.WithContext(ctx)
+	db = db.Where("public_key = ?", publicKey)
+	result := db.Delete(&ProverAllowList{})
+	if result.Error != nil {
+		return false, fmt.Errorf("ProverAllowList.DeleteProverPublicKey error: %w, public key: %v", result.Error, publicKey)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsPublicKeyAllowed checks if the given public key is in the allow list.
+func (p *ProverAllowList) IsPublicKeyAllowed(ctx context.Context, publicKey string) (bool, error) {
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverAllowList{})
+	db = db.Where("public_key = ?", publicKey)
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("ProverAllowList.IsPublicKeyAllowed error: %w, public key: %v", err, publicKey)
+	}
+	return count > 0, nil
+}
+
+// GetAllowedProvers returns the public keys of the allow list, the latest first.
+func (p *ProverAllowList) GetAllowedProvers(ctx context.Context, offset, limit int) ([]ProverAllowList, error) {
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverAllowList{})
+	db = db.Order("id DESC")
+	db = db.Offset(offset).Limit(limit)
+
+	var provers []ProverAllowList
+	if err := db.Find(&provers).Error; err != nil {
+		return nil, fmt.Errorf("ProverAllowList.GetAllowedProvers error: %w, offset: %v, limit: %v", err, offset, limit)
+	}
+	return provers, nil
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/utils"
 )
 
 // ProverBlockList represents the prover's block entry in the database.
@@ -15,6 +18,9 @@ type ProverBlockList struct {
 	ID         uint   `json:"id" gorm:"column:id;primaryKey"`
 	ProverName string `json:"prover_name" gorm:"column:prover_name"`
 	PublicKey  string `json:"public_key" gorm:"column:public_key"`
+	Reason     string `json:"reason" gorm:"column:reason"`
+	// ExpiresAt is the end of a temporary ban, nil blocks the public key until it's removed.
+	ExpiresAt *time.Time `json:"expires_at" gorm:"column:expires_at;default:NULL"`
 
 	// metadata
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
@@ -48,8 +54,45 @@ func (p *ProverBlockList) InsertProverPublicKey(ctx context.Context, proverName,
 	return nil
 }
 
+// BlockProverPublicKey adds a prover public key to the block list until expiresAt, or until it's removed if
+// expiresAt is nil. Blocking a blocked public key again replaces its entry.
+func (p *ProverBlockList) BlockProverPublicKey(ctx context.Context, proverName, publicKey, reason string, expiresAt *time.Time) error {
+	prover := ProverBlockList{
+		ProverName: proverName,
+		PublicKey:  publicKey,
+		Reason:     reason,
+		ExpiresAt:  expiresAt,
+	}
+
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverBlockList{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "public_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"prover_name", "reason", "expires_at", "updated_at"}),
+	})
+	if err := db.Create(&prover).Error; err != nil {
+		return fmt.Errorf("ProverBlockList.BlockProverPublicKey error: %w, prover name: %v, public key: %v", err, proverName, publicKey)
+	}
+	return nil
+}
+
+// GetBlockedProvers returns the entries of the block list not expired yet, the latest first.
+func (p *ProverBlockList) GetBlockedProvers(ctx context.Context, offset, limit int) ([]ProverBlockList, error) {
+	db := p.db.WithContext(ctx)
+	db = db.Model(&ProverBlockList{})
+	db = db.Where("expires_at IS NULL OR expires_at > ?", utils.NowUTC())
+	db = db.Order("id DESC")
+	db = db.Offset(offset).Limit(limit)
+
+	var provers []ProverBlockList
+	if err := db.Find(&provers).Error; err != nil {
+		return nil, fmt.Errorf("ProverBlockList.GetBlockedProvers error: %w, offset: %v, limit: %v", err, offset, limit)
+	}
+	return provers, nil
+}
+
 // DeleteProverPublicKey marks a Prover public key as deleted in the block list.
-// for unit test only.
 func (p *ProverBlockList) DeleteProverPublicKey(ctx context.Context, publicKey string) error {
 	db := p.db.WithContext(ctx)
 	db = db.Where("public_key = ?", publicKey)
@@ -59,11 +102,12 @@ func (p *ProverBlockList) DeleteProverPublicKey(ctx context.Context, publicKey s
 	return nil
 }
 
-// IsPublicKeyBlocked checks if the given public key is blocked.
+// IsPublicKeyBlocked checks if the given public key is blocked, a temporary ban past its expiry doesn't block it.
 func (p *ProverBlockList) IsPublicKeyBlocked(ctx context.Context, publicKey string) (bool, error) {
 	db := p.db.WithContext(ctx)
 	db = db.Model(&ProverBlockList{})
 	db = db.Where("public_key = ?", publicKey)
+	db = db.Where("expires_at IS NULL OR expires_at > ?", utils.NowUTC())
 	if err := db.First(&ProverBlockList{}).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return false, nil // Public key not found, hence it's not blocked.
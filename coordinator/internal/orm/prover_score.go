@@ -0,0 +1,169 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+)
+
+// ProverScore represents the reputation stats of a prover in the database.
+type ProverScore struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID         uint   `json:"id" gorm:"column:id;primaryKey"`
+	PublicKey  string `json:"public_key" gorm:"column:public_key"`
+	ProverName string `json:"prover_name" gorm:"column:prover_name"`
+
+	// stats
+	SuccessCount         uint64 `json:"success_count" gorm:"column:success_count"`
+	PanicFailureCount    uint64 `json:"panic_failure_count" gorm:"column:panic_failure_count"`
+	NoPanicFailureCount  uint64 `json:"no_panic_failure_count" gorm:"column:no_panic_failure_count"`
+	VerifiedFailureCount uint64 `json:"verified_failure_count" gorm:"column:verified_failure_count"`
+	TimeoutCount         uint64 `json:"timeout_count" gorm:"column:timeout_count"`
+	TotalProvingTimeSec  uint64 `json:"total_proving_time_sec" gorm:"column:total_proving_time_sec"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverScore creates a new ProverScore instance.
+func NewProverScore(db *gorm.DB) *ProverScore {
+	return &ProverScore{db: db}
+}
+
+// TableName returns the name of the "prover_score" table.
+func (*ProverScore) TableName() string {
+	return "prover_score"
+}
+
+// FailureCount returns the total number of failed prover tasks.
+func (o *ProverScore) FailureCount() uint64 {
+	return o.PanicFailureCount + o.NoPanicFailureCount + o.VerifiedFailureCount + o.TimeoutCount
+}
+
+// SuccessRate returns the Laplace-smoothed success rate in (0, 1), a prover without history scores 0.5.
+func (o *ProverScore) SuccessRate() float64 {
+	return float64(o.SuccessCount+1) / float64(o.SuccessCount+o.FailureCount()+2)
+}
+
+// AvgProvingTimeSec returns the average proving time of the successful prover tasks.
+func (o *ProverScore) AvgProvingTimeSec() uint64 {
+	if o.SuccessCount == 0 {
+		return 0
+	}
+	return o.TotalProvingTimeSec / o.SuccessCount
+}
+
+// GetProverScore retrieves the score of the prover, it returns nil if the prover has no history.
+func (o *ProverScore) GetProverScore(ctx context.Context, publicKey string) (*ProverScore, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverScore{})
+	db = db.Where("public_key = ?", publicKey)
+
+	var proverScore ProverScore
+	if err := db.First(&proverScore).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ProverScore.GetProverScore error: %w, public key: %v", err, publicKey)
+	}
+	return &proverScore, nil
+}
+
+// GetProverScores retrieves the scores of the provers ordered by id.
+func (o *ProverScore) GetProverScores(ctx context.Context, offset, limit int) ([]ProverScore, error) {
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
+	db = db.Model(&ProverScore{})
+	db = db.Order("id ASC")
+	db = db.Offset(offset)
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var proverScores []ProverScore
+	if err := db.Find(&proverScores).Error; err != nil {
+		return nil, fmt.Errorf("ProverScore.GetProverScores error: %w, offset: %v, limit: %v", err, offset, limit)
+	}
+	return proverScores, nil
+}
+
+// IncreaseSuccess records a successful prover task and its proving time.
+func (o *ProverScore) IncreaseSuccess(ctx context.Context, publicKey, proverName string, provingTimeSec uint64, dbTX ...*gorm.DB) error {
+	proverScore := ProverScore{
+		PublicKey:           publicKey,
+		ProverName:          proverName,
+		SuccessCount:        1,
+		TotalProvingTimeSec: provingTimeSec,
+	}
+	if err := o.upsert(ctx, &proverScore, map[string]uint64{"success_count": 1, "total_proving_time_sec": provingTimeSec}, dbTX...); err != nil {
+		return fmt.Errorf("ProverScore.IncreaseSuccess error: %w, public key: %v", err, publicKey)
+	}
+	return nil
+}
+
+// IncreaseFailure records a failed prover task, the proof failure type is only used by SubmitStatusNotOk failures.
+func (o *ProverScore) IncreaseFailure(ctx context.Context, publicKey, proverName string, failureType types.ProverTaskFailureType, proofFailureType message.ProofFailureType, dbTX ...*gorm.DB) error {
+	proverScore := ProverScore{
+		PublicKey:  publicKey,
+		ProverName: proverName,
+	}
+
+	var column string
+	switch failureType {
+	case types.ProverTaskFailureTypeTimeout:
+		column = "timeout_count"
+		proverScore.TimeoutCount = 1
+	case types.ProverTaskFailureTypeVerifiedFailed:
+		column = "verified_failure_count"
+		proverScore.VerifiedFailureCount = 1
+	case types.ProverTaskFailureTypeSubmitStatusNotOk:
+		if proofFailureType == message.ProofFailurePanic {
+			column = "panic_failure_count"
+			proverScore.PanicFailureCount = 1
+		} else {
+			column = "no_panic_failure_count"
+			proverScore.NoPanicFailureCount = 1
+		}
+	default:
+		// server errors are not the prover's fault.
+		return nil
+	}
+
+	if err := o.upsert(ctx, &proverScore, map[string]uint64{column: 1}, dbTX...); err != nil {
+		return fmt.Errorf("ProverScore.IncreaseFailure error: %w, public key: %v, failure type: %v", err, publicKey, failureType.String())
+	}
+	return nil
+}
+
+func (o *ProverScore) upsert(ctx context.Context, proverScore *ProverScore, increments map[string]uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverScore{})
+
+	updates := map[string]interface{}{
+		"prover_name": proverScore.ProverName,
+		"updated_at":  time.Now(),
+	}
+	for column, increment := range increments {
+		updates[column] = gorm.Expr(fmt.Sprintf("prover_score.%s + ?", column), increment)
+	}
+
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "public_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.Assignments(updates),
+	})
+	return db.Create(proverScore).Error
+}
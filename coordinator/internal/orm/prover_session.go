@@ -0,0 +1,81 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProverSession represents the last login of a prover, the session is active until its token expires.
+type ProverSession struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID            uint      `json:"id" gorm:"column:id;primaryKey"`
+	PublicKey     string    `json:"public_key" gorm:"column:public_key"`
+	ProverName    string    `json:"prover_name" gorm:"column:prover_name"`
+	ProverVersion string    `json:"prover_version" gorm:"column:prover_version"`
+	LoggedInAt    time.Time `json:"logged_in_at" gorm:"column:logged_in_at"`
+	ExpiresAt     time.Time `json:"expires_at" gorm:"column:expires_at"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewProverSession creates a new ProverSession instance.
+func NewProverSession(db *gorm.DB) *ProverSession {
+	return &ProverSession{db: db}
+}
+
+// TableName returns the name of the "prover_session" table.
+func (*ProverSession) TableName() string {
+	return "prover_session"
+}
+
+// UpsertProverSession records the login of the prover, replacing its previous session.
+func (o *ProverSession) UpsertProverSession(ctx context.Context, session *ProverSession) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverSession{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "public_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"prover_name", "prover_version", "logged_in_at", "expires_at", "updated_at"}),
+	})
+	if err := db.Create(session).Error; err != nil {
+		return fmt.Errorf("ProverSession.UpsertProverSession error: %w, public key: %v", err, session.PublicKey)
+	}
+	return nil
+}
+
+// GetActiveProverSessions returns the sessions not expired at now, or the session of the single prover if
+// publicKey is given, the latest login first.
+func (o *ProverSession) GetActiveProverSessions(ctx context.Context, publicKey string, now time.Time, offset, limit int) ([]ProverSession, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverSession{})
+	db = db.Where("expires_at > ?", now)
+	if publicKey != "" {
+		db = db.Where("public_key = ?", publicKey)
+	}
+	db = db.Order("logged_in_at DESC")
+	db = db.Offset(offset).Limit(limit)
+
+	var sessions []ProverSession
+	if err := db.Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("ProverSession.GetActiveProverSessions error: %w, public key: %v, offset: %v, limit: %v", err, publicKey, offset, limit)
+	}
+	return sessions, nil
+}
+
+// DeleteProverSession ends the session of the prover, e.g. when it's blocked.
+func (o *ProverSession) DeleteProverSession(ctx context.Context, publicKey string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Where("public_key = ?", publicKey)
+	if err := db.Delete(&ProverSession{}).Error; err != nil {
+		return fmt.Errorf("ProverSession.DeleteProverSession error: %w, public key: %v", err, publicKey)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
+	"scroll-tech/common/database"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
@@ -30,6 +31,9 @@ type ProverTask struct {
 	// task
 	TaskID   string `json:"task_id" gorm:"column:task_id"`
 	TaskType int16  `json:"task_type" gorm:"column:task_type;default:0"`
+	// HardForkName is the hard fork whose circuit the task is proved with, empty for the tasks assigned before
+	// it was recorded.
+	HardForkName string `json:"hard_fork_name" gorm:"column:hard_fork_name;default:''"`
 
 	// status
 	ProvingStatus int16           `json:"proving_status" gorm:"column:proving_status;default:0"`
@@ -37,6 +41,22 @@ type ProverTask struct {
 	Reward        decimal.Decimal `json:"reward" gorm:"column:reward;default:0;type:decimal(78)"`
 	Proof         []byte          `json:"proof" gorm:"column:proof;default:NULL"`
 	AssignedAt    time.Time       `json:"assigned_at" gorm:"assigned_at"`
+	// SubmitNonce is the nonce of the last signed submission of the prover for this task.
+	SubmitNonce uint64 `json:"submit_nonce" gorm:"column:submit_nonce;default:0"`
+	// SubmitResult is the error returned to the submission that settled the task, empty on success, nil if no
+	// submission settled it. The duplicate submissions get it back.
+	SubmitResult *string `json:"submit_result" gorm:"column:submit_result;default:NULL"`
+
+	// proving time
+	EstimatedProvingTimeSec uint64 `json:"estimated_proving_time_sec" gorm:"column:estimated_proving_time_sec;default:0"`
+	ProvingTimeSec          uint64 `json:"proving_time_sec" gorm:"column:proving_time_sec;default:0"`
+	// Deadline is when the task times out, the collection time of the task type applies if nil.
+	Deadline *time.Time `json:"deadline" gorm:"column:deadline;default:NULL"`
+
+	// progress reported by the prover
+	ProgressStage      int16      `json:"progress_stage" gorm:"column:progress_stage;default:0"`
+	ProgressPercent    int16      `json:"progress_percent" gorm:"column:progress_percent;default:0"`
+	ProgressReportedAt *time.Time `json:"progress_reported_at" gorm:"column:progress_reported_at;default:NULL"`
 
 	// metadata
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
@@ -68,9 +88,24 @@ func (o *ProverTask) IsProverAssigned(ctx context.Context, publicKey string) (bo
 	return true, nil
 }
 
+// GetAssignedProverTasksByPublicKey returns the tasks assigned to the prover of the public key, oldest first.
+func (o *ProverTask) GetAssignedProverTasksByPublicKey(ctx context.Context, publicKey string) ([]ProverTask, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("prover_public_key = ?", publicKey)
+	db = db.Where("proving_status = ?", int(types.ProverAssigned))
+	db = db.Order("assigned_at ASC")
+
+	var proverTasks []ProverTask
+	if err := db.Find(&proverTasks).Error; err != nil {
+		return nil, fmt.Errorf("ProverTask.GetAssignedProverTasksByPublicKey error: %w, publicKey: %v", err, publicKey)
+	}
+	return proverTasks, nil
+}
+
 // GetProverTasks get prover tasks
 func (o *ProverTask) GetProverTasks(ctx context.Context, fields map[string]interface{}, orderByList []string, offset, limit int) ([]ProverTask, error) {
-	db := o.db.WithContext(ctx)
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
 	db = db.Model(&ProverTask{})
 
 	for k, v := range fields {
@@ -166,6 +201,23 @@ func (o *ProverTask) GetAssignedTaskOfOtherProvers(ctx context.Context, taskType
 	return proverTasks, nil
 }
 
+// IsTaskFailedByProver checks whether the prover, at the version, has submitted a failed proof of the chunk/batch task.
+func (o *ProverTask) IsTaskFailedByProver(ctx context.Context, taskType message.ProofType, taskID, proverPublicKey, proverVersion string) (bool, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("task_type", int(taskType))
+	db = db.Where("task_id", taskID)
+	db = db.Where("prover_public_key", proverPublicKey)
+	db = db.Where("prover_version", proverVersion)
+	db = db.Where("failure_type", int(types.ProverTaskFailureTypeSubmitStatusNotOk))
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("ProverTask.IsTaskFailedByProver error: %w, taskID: %v, public key: %v", err, taskID, proverPublicKey)
+	}
+	return count > 0, nil
+}
+
 // GetProvingStatusByTaskID retrieves the proving status of a prover task
 func (o *ProverTask) GetProvingStatusByTaskID(ctx context.Context, taskType message.ProofType, taskID string) (types.ProverProveStatus, error) {
 	db := o.db.WithContext(ctx)
@@ -181,13 +233,15 @@ func (o *ProverTask) GetProvingStatusByTaskID(ctx context.Context, taskType mess
 	return types.ProverProveStatus(proverTask.ProvingStatus), nil
 }
 
-// GetTimeoutAssignedProverTasks get the timeout and assigned proving_status prover task
+// GetTimeoutAssignedProverTasks get the timeout and assigned proving_status prover task, a task with a deadline
+// times out at its deadline instead of the timeout.
 func (o *ProverTask) GetTimeoutAssignedProverTasks(ctx context.Context, limit int, taskType message.ProofType, timeout time.Duration) ([]ProverTask, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&ProverTask{})
 	db = db.Where("proving_status", int(types.ProverAssigned))
 	db = db.Where("task_type", int(taskType))
-	db = db.Where("assigned_at < ?", utils.NowUTC().Add(-timeout))
+	now := utils.NowUTC()
+	db = db.Where("(deadline IS NULL AND assigned_at < ?) OR deadline < ?", now.Add(-timeout), now)
 	db = db.Limit(limit)
 
 	var proverTasks []ProverTask
@@ -265,6 +319,111 @@ func (o *ProverTask) UpdateProverTaskProvingStatusAndFailureType(ctx context.Con
 	return nil
 }
 
+// UpdateProverTaskSubmitResult records the result returned to the submission that settled the prover task.
+func (o *ProverTask) UpdateProverTaskSubmitResult(ctx context.Context, uuid uuid.UUID, result string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("uuid = ?", uuid)
+
+	if err := db.Update("submit_result", result).Error; err != nil {
+		return fmt.Errorf("ProverTask.UpdateProverTaskSubmitResult error: %w, uuid:%s", err, uuid)
+	}
+	return nil
+}
+
+// UpdateProverTaskProvingTime records the proving time of the accepted proof of the prover task.
+func (o *ProverTask) UpdateProverTaskProvingTime(ctx context.Context, uuid uuid.UUID, provingTimeSec uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("uuid = ?", uuid)
+
+	if err := db.Update("proving_time_sec", provingTimeSec).Error; err != nil {
+		return fmt.Errorf("ProverTask.UpdateProverTaskProvingTime error: %w, uuid:%s", err, uuid)
+	}
+	return nil
+}
+
+// GetEstimatedProverTasks retrieves the latest prover tasks of the prover with an accepted proof and an estimated
+// proving time, the latest first.
+func (o *ProverTask) GetEstimatedProverTasks(ctx context.Context, publicKey string, taskType message.ProofType, limit int) ([]ProverTask, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Select("estimated_proving_time_sec, proving_time_sec")
+	db = db.Where("prover_public_key = ?", publicKey)
+	db = db.Where("task_type = ?", int(taskType))
+	db = db.Where("proving_status = ?", int(types.ProverProofValid))
+	db = db.Where("estimated_proving_time_sec > 0 AND proving_time_sec > 0")
+	db = db.Order("id DESC")
+	db = db.Limit(limit)
+
+	var proverTasks []ProverTask
+	if err := db.Find(&proverTasks).Error; err != nil {
+		return nil, fmt.Errorf("ProverTask.GetEstimatedProverTasks error: %w, public key: %v, task type: %v", err, publicKey, taskType.String())
+	}
+	return proverTasks, nil
+}
+
+// UpdateProverTaskProgress updates the progress of an assigned prover task, it returns false if the prover has no such assigned task.
+func (o *ProverTask) UpdateProverTaskProgress(ctx context.Context, uuid, publicKey string, stage message.ProvingStage, percent uint8) (bool, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("uuid = ?", uuid)
+	db = db.Where("prover_public_key = ?", publicKey)
+	db = db.Where("proving_status = ?", int(types.ProverAssigned))
+
+	updates := map[string]interface{}{
+		"progress_stage":       int(stage),
+		"progress_percent":     int(percent),
+		"progress_reported_at": utils.NowUTC(),
+	}
+	result := db.Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("ProverTask.UpdateProverTaskProgress error: %w, uuid: %v, stage: %v, percent: %v", result.Error, uuid, stage.String(), percent)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetMaxSubmitNonceByPublicKey returns the highest nonce the prover of the public key has submitted with, 0 if none.
+func (o *ProverTask) GetMaxSubmitNonceByPublicKey(ctx context.Context, publicKey string) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Select("COALESCE(MAX(submit_nonce), 0)")
+	db = db.Where("prover_public_key = ?", publicKey)
+
+	var maxNonce uint64
+	if err := db.Scan(&maxNonce).Error; err != nil {
+		return 0, fmt.Errorf("ProverTask.GetMaxSubmitNonceByPublicKey error: %w, public key: %v", err, publicKey)
+	}
+	return maxNonce, nil
+}
+
+// UpdateProverTaskSubmitNonce records the nonce of a signed submission, it returns false if the prover task
+// has already been submitted with this nonce or a higher one.
+func (o *ProverTask) UpdateProverTaskSubmitNonce(ctx context.Context, uuid uuid.UUID, nonce uint64, dbTX ...*gorm.DB) (bool, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("uuid = ?", uuid)
+	db = db.Where("submit_nonce < ?", nonce)
+
+	result := db.Update("submit_nonce", nonce)
+	if result.Error != nil {
+		return false, fmt.Errorf("ProverTask.UpdateProverTaskSubmitNonce error: %w, uuid: %v, nonce: %v", result.Error, uuid.String(), nonce)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // UpdateProverTaskFailureType update the prover task failure type
 func (o *ProverTask) UpdateProverTaskFailureType(ctx context.Context, uuid uuid.UUID, failureType types.ProverTaskFailureType, dbTX ...*gorm.DB) error {
 	db := o.db
@@ -279,3 +438,46 @@ func (o *ProverTask) UpdateProverTaskFailureType(ctx context.Context, uuid uuid.
 	}
 	return nil
 }
+
+// ResetAssignedProverTasks fails the assigned prover tasks of a task reset by the admin, the proofs submitted for
+// them are refused. It returns the number of prover tasks failed.
+func (o *ProverTask) ResetAssignedProverTasks(ctx context.Context, taskType message.ProofType, taskID string, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("task_type = ?", int(taskType))
+	db = db.Where("task_id = ?", taskID)
+	db = db.Where("proving_status = ?", int(types.ProverAssigned))
+
+	updates := map[string]interface{}{
+		"proving_status": int(types.ProverProofInvalid),
+		"failure_type":   int(types.ProverTaskFailureTypeReset),
+	}
+	result := db.Updates(updates)
+	if result.Error != nil {
+		return 0, fmt.Errorf("ProverTask.ResetAssignedProverTasks error: %w, task type: %v, task id: %v", result.Error, taskType.String(), taskID)
+	}
+	return result.RowsAffected, nil
+}
+
+// CancelProverTask fails an assigned prover task of the prover as cancelled, it returns false if the prover has no such assigned task.
+func (o *ProverTask) CancelProverTask(ctx context.Context, uuid, publicKey string) (bool, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ProverTask{})
+	db = db.Where("uuid = ?", uuid)
+	db = db.Where("prover_public_key = ?", publicKey)
+	db = db.Where("proving_status = ?", int(types.ProverAssigned))
+
+	updates := map[string]interface{}{
+		"proving_status": int(types.ProverProofInvalid),
+		"failure_type":   int(types.ProverTaskFailureTypeCancelled),
+	}
+	result := db.Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("ProverTask.CancelProverTask error: %w, uuid: %v", result.Error, uuid)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,97 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types/message"
+)
+
+// WorkReceipt represents the receipt of a proof accepted from a prover, signed by the coordinator.
+type WorkReceipt struct {
+	db *gorm.DB `gorm:"-"`
+
+	ID              uint64    `json:"id" gorm:"column:id;primaryKey"`
+	UUID            string    `json:"uuid" gorm:"column:uuid"`
+	TaskID          string    `json:"task_id" gorm:"column:task_id"`
+	TaskType        int16     `json:"task_type" gorm:"column:task_type"`
+	ProverPublicKey string    `json:"prover_public_key" gorm:"column:prover_public_key"`
+	ProverName      string    `json:"prover_name" gorm:"column:prover_name"`
+	ProofHash       string    `json:"proof_hash" gorm:"column:proof_hash"`
+	ProvingTimeSec  uint64    `json:"proving_time_sec" gorm:"column:proving_time_sec"`
+	AcceptedAt      time.Time `json:"accepted_at" gorm:"column:accepted_at"`
+	Signature       string    `json:"signature" gorm:"column:signature"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewWorkReceipt creates a new WorkReceipt instance.
+func NewWorkReceipt(db *gorm.DB) *WorkReceipt {
+	return &WorkReceipt{db: db}
+}
+
+// TableName returns the name of the "work_receipt" table.
+func (*WorkReceipt) TableName() string {
+	return "work_receipt"
+}
+
+// Message returns the signed content of the receipt.
+func (o *WorkReceipt) Message() *message.WorkReceipt {
+	return &message.WorkReceipt{
+		UUID:            o.UUID,
+		TaskID:          o.TaskID,
+		TaskType:        message.ProofType(o.TaskType),
+		ProverPublicKey: o.ProverPublicKey,
+		ProofHash:       common.HexToHash(o.ProofHash),
+		ProvingTimeSec:  o.ProvingTimeSec,
+		AcceptedAt:      uint64(o.AcceptedAt.Unix()),
+	}
+}
+
+// InsertWorkReceipt records the receipt of an accepted proof, a receipt already recorded for the prover task is left untouched.
+func (o *WorkReceipt) InsertWorkReceipt(ctx context.Context, receipt *WorkReceipt, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&WorkReceipt{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "uuid"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	})
+
+	if err := db.Create(receipt).Error; err != nil {
+		return fmt.Errorf("WorkReceipt.InsertWorkReceipt error: %w, uuid: %v", err, receipt.UUID)
+	}
+	return nil
+}
+
+// GetWorkReceipts retrieves the receipts recorded after the afterID in id order, of the prover if publicKey is given.
+// The id of the last receipt is the afterID of the next page.
+func (o *WorkReceipt) GetWorkReceipts(ctx context.Context, afterID uint64, publicKey string, limit int) ([]WorkReceipt, error) {
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
+	db = db.Model(&WorkReceipt{})
+	db = db.Where("id > ?", afterID)
+	if publicKey != "" {
+		db = db.Where("prover_public_key = ?", publicKey)
+	}
+	db = db.Order("id ASC")
+	db = db.Limit(limit)
+
+	var receipts []WorkReceipt
+	if err := db.Find(&receipts).Error; err != nil {
+		return nil, fmt.Errorf("WorkReceipt.GetWorkReceipts error: %w, after id: %v, public key: %v", err, afterID, publicKey)
+	}
+	return receipts, nil
+}
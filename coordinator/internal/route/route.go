@@ -8,12 +8,14 @@ import (
 
 	"scroll-tech/coordinator/internal/config"
 	"scroll-tech/coordinator/internal/controller/api"
+	"scroll-tech/coordinator/internal/logic/ratelimit"
 	"scroll-tech/coordinator/internal/middleware"
 )
 
 // Route register route for coordinator
 func Route(router *gin.Engine, cfg *config.Config, reg prometheus.Registerer) {
 	router.Use(gin.Recovery())
+	router.Use(middleware.RequestIDMiddleware())
 
 	observability.Use(router, "coordinator", reg)
 
@@ -30,11 +32,58 @@ func v1(router *gin.RouterGroup, conf *config.Config) {
 
 	loginMiddleware := middleware.LoginMiddleware(conf)
 	r.POST("/login", challengeMiddleware.MiddlewareFunc(), loginMiddleware.LoginHandler)
+	if conf.Auth.LoginMaxRefreshDurationSec > 0 {
+		r.POST("/refresh_token", middleware.RefreshHandler(loginMiddleware))
+	}
+	r.GET("/capabilities", api.Capabilities.GetCapabilities)
+	if len(conf.ProverManager.CircuitAssets) > 0 {
+		r.GET("/circuit_assets", api.CircuitAssets.GetCircuitAssets)
+	}
+
+	if conf.Admin != nil {
+		admin := r.Group("/admin", middleware.AdminMiddleware(conf))
+		admin.GET("/prover_scores", api.Admin.GetProverScores)
+		admin.GET("/prover_tasks", api.Admin.GetProverTasks)
+		admin.GET("/prover_task_history", api.Admin.GetProverTaskHistory)
+		admin.GET("/task_stats", api.Admin.GetTaskStats)
+		admin.GET("/row_usage", api.Admin.GetRowUsage)
+		admin.GET("/prover_allow_list", api.Admin.GetProverAllowList)
+		admin.POST("/prover_allow_list", api.Admin.AllowProver)
+		admin.DELETE("/prover_allow_list", api.Admin.DisallowProver)
+		admin.GET("/prover_block_list", api.Admin.GetProverBlockList)
+		admin.POST("/prover_block_list", api.Admin.BlockProver)
+		admin.DELETE("/prover_block_list", api.Admin.UnblockProver)
+		admin.GET("/prover_sessions", api.Admin.GetProverSessions)
+		admin.POST("/task_reset", api.Admin.ResetTask)
+		admin.POST("/task_priority", api.Admin.SetTaskPriority)
+		admin.GET("/audit_log", api.Admin.GetAdminAuditLogs)
+		if conf.ProverManager.Marketplace != nil {
+			admin.GET("/work_receipts", api.Admin.GetWorkReceipts)
+		}
+		if api.RateLimiter != nil {
+			admin.GET("/prover_bans", api.Admin.GetProverBans)
+			admin.POST("/prover_bans", api.Admin.BanProver)
+			admin.DELETE("/prover_bans", api.Admin.UnbanProver)
+		}
+	}
+
+	getTaskHandlers := []gin.HandlerFunc{api.GetTask.GetTasks}
+	submitProofHandlers := []gin.HandlerFunc{api.SubmitProof.SubmitProof}
+	if api.RateLimiter != nil {
+		getTaskHandlers = append([]gin.HandlerFunc{middleware.RateLimitMiddleware(api.RateLimiter, ratelimit.GetTask)}, getTaskHandlers...)
+		submitProofHandlers = append([]gin.HandlerFunc{middleware.RateLimitMiddleware(api.RateLimiter, ratelimit.SubmitProof)}, submitProofHandlers...)
+	}
 
 	// need jwt token api
 	r.Use(loginMiddleware.MiddlewareFunc())
 	{
-		r.POST("/get_task", api.GetTask.GetTasks)
-		r.POST("/submit_proof", api.SubmitProof.SubmitProof)
+		r.POST("/get_task", getTaskHandlers...)
+		r.POST("/submit_proof", submitProofHandlers...)
+		r.POST("/report_progress", api.ReportProgress.ReportProgress)
+		r.POST("/ack_cancel_task", api.ReportProgress.AckCancelTask)
+		if api.ProofUpload != nil {
+			r.POST("/upload_proof_part", api.ProofUpload.UploadProofPart)
+			r.POST("/get_proof_upload", api.ProofUpload.GetProofUpload)
+		}
 	}
 }
@@ -0,0 +1,276 @@
+package types
+
+// ProverScoresParameter for the admin prover scores request parameter
+type ProverScoresParameter struct {
+	PublicKey string `form:"public_key" json:"public_key"`
+	Offset    int    `form:"offset" json:"offset"`
+	Limit     int    `form:"limit" json:"limit"`
+}
+
+// ProverScoreSchema the schema data of a prover score returned to the admin
+type ProverScoreSchema struct {
+	PublicKey            string  `json:"public_key"`
+	ProverName           string  `json:"prover_name"`
+	SuccessCount         uint64  `json:"success_count"`
+	PanicFailureCount    uint64  `json:"panic_failure_count"`
+	NoPanicFailureCount  uint64  `json:"no_panic_failure_count"`
+	VerifiedFailureCount uint64  `json:"verified_failure_count"`
+	TimeoutCount         uint64  `json:"timeout_count"`
+	SuccessRate          float64 `json:"success_rate"`
+	AvgProvingTimeSec    uint64  `json:"avg_proving_time_sec"`
+}
+
+// ProverTasksParameter for the admin assigned prover tasks request parameter
+type ProverTasksParameter struct {
+	PublicKey string `form:"public_key" json:"public_key"`
+	Offset    int    `form:"offset" json:"offset"`
+	Limit     int    `form:"limit" json:"limit"`
+}
+
+// ProverTaskProgressSchema the schema data of an assigned prover task and its reported progress
+type ProverTaskProgressSchema struct {
+	UUID               string `json:"uuid"`
+	TaskID             string `json:"task_id"`
+	TaskType           string `json:"task_type"`
+	ProverPublicKey    string `json:"prover_public_key"`
+	ProverName         string `json:"prover_name"`
+	AssignedAt         int64  `json:"assigned_at"`
+	ProgressStage      string `json:"progress_stage"`
+	ProgressPercent    int16  `json:"progress_percent"`
+	ProgressReportedAt int64  `json:"progress_reported_at,omitempty"`
+}
+
+// ProverBansParameter for the admin prover ban request parameter
+type ProverBansParameter struct {
+	PublicKey string `form:"public_key" json:"public_key" binding:"required"`
+	// DurationSec is how long the prover is banned, only used when banning a prover.
+	DurationSec int    `form:"duration_sec" json:"duration_sec"`
+	Reason      string `form:"reason" json:"reason"`
+}
+
+// ProverBanSchema the schema data of a temporary prover ban returned to the admin
+type ProverBanSchema struct {
+	PublicKey string `json:"public_key"`
+	Reason    string `json:"reason"`
+	BannedAt  int64  `json:"banned_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// ProverListParameter for the admin prover allow list and block list request parameter
+type ProverListParameter struct {
+	Offset int `form:"offset" json:"offset"`
+	Limit  int `form:"limit" json:"limit"`
+}
+
+// ProverAllowListParameter for the admin prover allow list update request parameter
+type ProverAllowListParameter struct {
+	PublicKey string `form:"public_key" json:"public_key" binding:"required"`
+	// ProverName is only used when allowing a prover.
+	ProverName string `form:"prover_name" json:"prover_name"`
+}
+
+// ProverAllowListSchema the schema data of an allowed prover returned to the admin
+type ProverAllowListSchema struct {
+	PublicKey  string `json:"public_key"`
+	ProverName string `json:"prover_name"`
+	AllowedAt  int64  `json:"allowed_at"`
+}
+
+// ProverBlockListParameter for the admin prover block list update request parameter
+type ProverBlockListParameter struct {
+	PublicKey string `form:"public_key" json:"public_key" binding:"required"`
+	// ProverName, Reason and DurationSec are only used when blocking a prover, a zero duration_sec blocks
+	// the prover until it's unblocked.
+	ProverName  string `form:"prover_name" json:"prover_name"`
+	Reason      string `form:"reason" json:"reason"`
+	DurationSec int    `form:"duration_sec" json:"duration_sec"`
+}
+
+// ProverBlockListSchema the schema data of a blocked prover returned to the admin
+type ProverBlockListSchema struct {
+	PublicKey  string `json:"public_key"`
+	ProverName string `json:"prover_name"`
+	Reason     string `json:"reason"`
+	BlockedAt  int64  `json:"blocked_at"`
+	// ExpiresAt is the end of a temporary ban, omitted if the prover is blocked until it's unblocked.
+	ExpiresAt int64 `json:"expires_at,omitempty"`
+}
+
+// ProverSessionsParameter for the admin prover sessions request parameter
+type ProverSessionsParameter struct {
+	PublicKey string `form:"public_key" json:"public_key"`
+	Offset    int    `form:"offset" json:"offset"`
+	Limit     int    `form:"limit" json:"limit"`
+}
+
+// ProverSessionSchema the schema data of a logged in prover returned to the admin
+type ProverSessionSchema struct {
+	PublicKey     string `json:"public_key"`
+	ProverName    string `json:"prover_name"`
+	ProverVersion string `json:"prover_version"`
+	LoggedInAt    int64  `json:"logged_in_at"`
+	ExpiresAt     int64  `json:"expires_at"`
+}
+
+// TaskStatsSchema the proving statistics of the chunk or the batch tasks returned to the admin
+type TaskStatsSchema struct {
+	TaskType string `json:"task_type"`
+	// StatusCounts is the number of tasks of every proving status.
+	StatusCounts map[string]int64 `json:"status_counts"`
+	// Backlog is the number of tasks not proven yet, unassigned or assigned.
+	Backlog                int64  `json:"backlog"`
+	OldestUnassignedAgeSec uint64 `json:"oldest_unassigned_age_sec"`
+	ProofsLastHour         int64  `json:"proofs_last_hour"`
+}
+
+// ProverTaskHistoryParameter for the admin prover task history request parameter
+type ProverTaskHistoryParameter struct {
+	PublicKey string `form:"public_key" json:"public_key" binding:"required"`
+	Offset    int    `form:"offset" json:"offset"`
+	Limit     int    `form:"limit" json:"limit"`
+}
+
+// ProverTaskHistorySchema the schema data of a task assigned to a prover and its outcome
+type ProverTaskHistorySchema struct {
+	UUID          string `json:"uuid"`
+	TaskID        string `json:"task_id"`
+	TaskType      string `json:"task_type"`
+	ProverName    string `json:"prover_name"`
+	ProverVersion string `json:"prover_version"`
+	ProvingStatus string `json:"proving_status"`
+	FailureType   string `json:"failure_type,omitempty"`
+	AssignedAt    int64  `json:"assigned_at"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+// RowUsageParameter for the admin row usage request parameter, the range defaults to the latest chunks
+type RowUsageParameter struct {
+	StartIndex *uint64 `form:"start_index" json:"start_index"`
+	EndIndex   *uint64 `form:"end_index" json:"end_index"`
+	// Limit is the number of chunks closest to their capacity returned.
+	Limit int `form:"limit" json:"limit"`
+}
+
+// RowUsageSchema the row usage of the sub-circuits over a range of chunks returned to the admin
+type RowUsageSchema struct {
+	StartIndex uint64 `json:"start_index"`
+	EndIndex   uint64 `json:"end_index"`
+	// SubCircuits are ordered by their max utilization, the closest to their capacity first.
+	SubCircuits []SubCircuitRowUsageSchema `json:"sub_circuits"`
+	// Chunks are ordered by the utilization of their bottleneck sub-circuit, the closest to their capacity first.
+	Chunks []ChunkRowUsageSchema `json:"chunks"`
+}
+
+// SubCircuitRowUsageSchema the schema data of the aggregated row usage of a sub-circuit
+type SubCircuitRowUsageSchema struct {
+	SubCircuit     string  `json:"sub_circuit"`
+	Capacity       uint64  `json:"capacity"`
+	ChunkCount     uint64  `json:"chunk_count"`
+	MaxRowNumber   uint64  `json:"max_row_number"`
+	AvgRowNumber   float64 `json:"avg_row_number"`
+	MaxUtilization float64 `json:"max_utilization"`
+	AvgUtilization float64 `json:"avg_utilization"`
+}
+
+// ChunkRowUsageSchema the schema data of the sub-circuit of a chunk closest to its capacity
+type ChunkRowUsageSchema struct {
+	ChunkIndex       uint64  `json:"chunk_index"`
+	ChunkHash        string  `json:"chunk_hash"`
+	StartBlockNumber uint64  `json:"start_block_number"`
+	EndBlockNumber   uint64  `json:"end_block_number"`
+	SubCircuit       string  `json:"sub_circuit"`
+	RowNumber        uint64  `json:"row_number"`
+	Capacity         uint64  `json:"capacity"`
+	Utilization      float64 `json:"utilization"`
+}
+
+// WorkReceiptsParameter for the admin work receipts export request parameter
+type WorkReceiptsParameter struct {
+	// AfterID is the id of the last receipt of the previous page, the export starts from the first receipt if 0.
+	AfterID   uint64 `form:"after_id" json:"after_id"`
+	PublicKey string `form:"public_key" json:"public_key"`
+	Limit     int    `form:"limit" json:"limit"`
+}
+
+// WorkReceiptSchema the schema data of the receipt of an accepted proof, the signature is the coordinator's
+// signature of the message.WorkReceipt of the signed fields
+type WorkReceiptSchema struct {
+	ID              uint64 `json:"id"`
+	UUID            string `json:"uuid"`
+	TaskID          string `json:"task_id"`
+	TaskType        string `json:"task_type"`
+	ProverPublicKey string `json:"prover_public_key"`
+	ProverAddress   string `json:"prover_address"`
+	ProverName      string `json:"prover_name"`
+	ProofHash       string `json:"proof_hash"`
+	ProvingTimeSec  uint64 `json:"proving_time_sec"`
+	AcceptedAt      int64  `json:"accepted_at"`
+	Signature       string `json:"signature"`
+}
+
+// TaskResetParameter for the admin task reset request parameter
+type TaskResetParameter struct {
+	// TaskType is the message.ProofType of the task, chunk or batch.
+	TaskType int    `form:"task_type" json:"task_type" binding:"required"`
+	TaskID   string `form:"task_id" json:"task_id" binding:"required"`
+	Reason   string `form:"reason" json:"reason" binding:"required"`
+	// Operator is who resets the task, recorded in the audit log.
+	Operator string `form:"operator" json:"operator"`
+}
+
+// TaskResetSchema the state of a task before it's reset, recorded as the details of the audit log
+type TaskResetSchema struct {
+	TaskType        string   `json:"task_type"`
+	TaskID          string   `json:"task_id"`
+	ProvingStatus   string   `json:"proving_status"`
+	ProofHash       string   `json:"proof_hash,omitempty"`
+	TotalAttempts   int16    `json:"total_attempts"`
+	ActiveAttempts  int16    `json:"active_attempts"`
+	FailedAttempts  int16    `json:"failed_attempts"`
+	AssignedProvers []string `json:"assigned_provers"`
+	// ProverTasksFailed is the number of assigned prover tasks failed by the reset.
+	ProverTasksFailed int64 `json:"prover_tasks_failed"`
+}
+
+// TaskPriorityParameter for the admin task priority request parameter
+type TaskPriorityParameter struct {
+	// TaskType is the message.ProofType of the task, chunk or batch.
+	TaskType int    `form:"task_type" json:"task_type" binding:"required"`
+	TaskID   string `form:"task_id" json:"task_id" binding:"required"`
+	// Priority is the message.TaskPriority of the task, 0 (low) is a valid priority.
+	Priority *uint8 `form:"priority" json:"priority" binding:"required"`
+	// Deadline is the unix time the task should be proven by, 0 clears it.
+	Deadline int64  `form:"deadline" json:"deadline"`
+	Reason   string `form:"reason" json:"reason" binding:"required"`
+	// Operator is who changes the priority, recorded in the audit log.
+	Operator string `form:"operator" json:"operator"`
+}
+
+// TaskPrioritySchema the priority of a task before and after it's changed, recorded as the details of the audit log
+type TaskPrioritySchema struct {
+	TaskType     string `json:"task_type"`
+	TaskID       string `json:"task_id"`
+	FromPriority string `json:"from_priority"`
+	FromDeadline int64  `json:"from_deadline,omitempty"`
+	ToPriority   string `json:"to_priority"`
+	ToDeadline   int64  `json:"to_deadline,omitempty"`
+}
+
+// AdminAuditLogParameter for the admin audit log request parameter
+type AdminAuditLogParameter struct {
+	TargetKey string `form:"target_key" json:"target_key"`
+	Offset    int    `form:"offset" json:"offset"`
+	Limit     int    `form:"limit" json:"limit"`
+}
+
+// AdminAuditLogSchema the schema data of an admin action returned to the admin
+type AdminAuditLogSchema struct {
+	ID         uint64 `json:"id"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetKey  string `json:"target_key"`
+	Operator   string `json:"operator"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details"`
+	CreatedAt  int64  `json:"created_at"`
+}
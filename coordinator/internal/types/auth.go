@@ -1,6 +1,10 @@
 package types
 
-import "time"
+import (
+	"time"
+
+	"scroll-tech/common/types/message"
+)
 
 const (
 	// PublicKey the public key for context
@@ -11,14 +15,56 @@ const (
 	ProverVersion = "prover_version"
 	// HardForkName the fork name for context
 	HardForkName = "hard_fork_name"
+	// VersionWarning the prover version deprecation warning for context
+	VersionWarning = "version_warning"
+	// Hardware the json encoded hardware advertised by the prover for context
+	Hardware = "hardware"
+	// MaxConcurrentTasks the json encoded concurrency limits advertised by the prover for context
+	MaxConcurrentTasks = "max_concurrent_tasks"
+	// RequestID the api request id for context
+	RequestID = "request_id"
 )
 
+// HardwareInfo the hardware capabilities advertised by the prover at login
+type HardwareInfo struct {
+	GPUModel    string `json:"gpu_model"`
+	GPUMemoryMB uint64 `json:"gpu_memory_mb"`
+	CPUCores    uint64 `json:"cpu_cores"`
+	MemoryMB    uint64 `json:"memory_mb"`
+}
+
+// ConcurrencyLimits the max number of tasks of each proof type the prover runs at once, advertised at login.
+// A nil limit is not set, a zero limit means the prover doesn't take the tasks of the proof type.
+type ConcurrencyLimits struct {
+	Chunk *uint32 `json:"chunk,omitempty"`
+	Batch *uint32 `json:"batch,omitempty"`
+}
+
+// Limit returns the limit of the proof type, nil if it's not set.
+func (l *ConcurrencyLimits) Limit(proofType message.ProofType) *uint32 {
+	if l == nil {
+		return nil
+	}
+	switch proofType {
+	case message.ProofTypeChunk:
+		return l.Chunk
+	case message.ProofTypeBatch:
+		return l.Batch
+	default:
+		return nil
+	}
+}
+
 // Message the login message struct
 type Message struct {
 	Challenge     string `form:"challenge" json:"challenge" binding:"required"`
 	ProverVersion string `form:"prover_version" json:"prover_version" binding:"required"`
 	ProverName    string `form:"prover_name" json:"prover_name" binding:"required"`
 	HardForkName  string `form:"hard_fork_name" json:"hard_fork_name"`
+	// Hardware is signed with the identity, it's used to route the tasks.
+	Hardware *HardwareInfo `form:"hardware" json:"hardware,omitempty"`
+	// MaxConcurrentTasks is signed with the identity too, it's used to schedule the tasks.
+	MaxConcurrentTasks *ConcurrencyLimits `form:"max_concurrent_tasks" json:"max_concurrent_tasks,omitempty"`
 }
 
 // LoginParameter for /login api
@@ -31,4 +77,6 @@ type LoginParameter struct {
 type LoginSchema struct {
 	Time  time.Time `json:"time"`
 	Token string    `json:"token"`
+	// Warning tells the prover that its version is deprecated.
+	Warning string `json:"warning,omitempty"`
 }
@@ -0,0 +1,51 @@
+package types
+
+// CapabilitiesSchema what the coordinator supports, for the provers and the monitoring tools to check their
+// compatibility before logging in
+type CapabilitiesSchema struct {
+	CoordinatorVersion string `json:"coordinator_version"`
+	// ProtocolVersions the versions of the prover api served, under /coordinator/<version>
+	ProtocolVersions []string              `json:"protocol_versions"`
+	ProofTypes       []CapabilityProofType `json:"proof_types"`
+	ProverVersions   CapabilityProverRange `json:"prover_versions"`
+	// CircuitVersions the accepted circuit versions, empty accepts any
+	CircuitVersions    []string             `json:"circuit_versions"`
+	HardForks          []CapabilityHardFork `json:"hard_forks"`
+	RequireSignedProof bool                 `json:"require_signed_proof"`
+	// TaskSigner the address of the key the tasks are signed with, absent if they aren't signed. The provers pin
+	// it in their config, as a proxy could forge this one too
+	TaskSigner string `json:"task_signer,omitempty"`
+	// ProofUpload the limits of the proof upload in parts, absent if it's disabled
+	ProofUpload *CapabilityProofUpload `json:"proof_upload,omitempty"`
+}
+
+// CapabilityProofUpload the limits of the parts a proof is uploaded in
+type CapabilityProofUpload struct {
+	MaxPartSize int `json:"max_part_size"`
+	MaxParts    int `json:"max_parts"`
+}
+
+// CapabilityProofType a proof type the tasks are assigned for
+type CapabilityProofType struct {
+	Name     string `json:"name"`
+	TaskType int    `json:"task_type"`
+}
+
+// CapabilityProverRange the prover versions accepted at login
+type CapabilityProverRange struct {
+	MinVersion string `json:"min_version"`
+	MaxVersion string `json:"max_version,omitempty"`
+	// DeprecatedVersion the provers below it are rejected after the deprecation deadline, a unix timestamp
+	DeprecatedVersion   string `json:"deprecated_version,omitempty"`
+	DeprecationDeadline int64  `json:"deprecation_deadline,omitempty"`
+}
+
+// CapabilityHardFork a hard fork the proofs are verified for, with its vks
+type CapabilityHardFork struct {
+	Name string `json:"name"`
+	// Height the first L2 block of the hard fork, absent if the chain config doesn't schedule it, its tasks
+	// aren't assigned then
+	Height  *uint64 `json:"height,omitempty"`
+	ChunkVK string  `json:"chunk_vk,omitempty"`
+	BatchVK string  `json:"batch_vk,omitempty"`
+}
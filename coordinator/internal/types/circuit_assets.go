@@ -0,0 +1,21 @@
+package types
+
+// CircuitAssetsSchema the circuit asset releases published to the provers
+type CircuitAssetsSchema struct {
+	Releases []CircuitAssetsRelease `json:"releases"`
+}
+
+// CircuitAssetsRelease a release of the params and vk assets of a hard fork circuit
+type CircuitAssetsRelease struct {
+	HardForkName   string             `json:"hard_fork_name"`
+	CircuitVersion string             `json:"circuit_version"`
+	UpgradeHeight  uint64             `json:"upgrade_height"`
+	BaseURL        string             `json:"base_url"`
+	Files          []CircuitAssetFile `json:"files"`
+}
+
+// CircuitAssetFile a file of a circuit asset release and its sha256 digest
+type CircuitAssetFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
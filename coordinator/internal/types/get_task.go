@@ -6,6 +6,8 @@ type GetTaskParameter struct {
 	TaskType     int      `form:"task_type" json:"task_type"`
 	VK           string   `form:"vk" json:"vk"`   // will be deprecated after all go_prover offline
 	VKs          []string `form:"vks" json:"vks"` // for rust_prover that supporting multi-circuits
+	// Prefetch asks for the next task while the assigned one is in its final proving stage.
+	Prefetch bool `form:"prefetch" json:"prefetch"`
 }
 
 // GetTaskSchema the schema data return to prover for get prover task
@@ -15,4 +17,11 @@ type GetTaskSchema struct {
 	TaskType     int    `json:"task_type"`
 	TaskData     string `json:"task_data"`
 	HardForkName string `json:"hard_fork_name"`
+	Priority     int    `json:"priority"`
+	Deadline     int64  `json:"deadline"`
+	// TaskHeight is the first L2 block of the task, the provers pick the circuit asset release by it.
+	TaskHeight uint64 `json:"task_height"`
+	// Signature is the coordinator's signature of the message.TaskAssignment of the task, empty if the tasks
+	// aren't signed.
+	Signature string `json:"signature,omitempty"`
 }
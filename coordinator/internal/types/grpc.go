@@ -0,0 +1,26 @@
+package types
+
+import "scroll-tech/common/types/message"
+
+// SubmitProofStreamParameter is a single message of the grpc SubmitProof upload stream.
+// The first message must carry the parameter, the proof is assembled by appending the
+// ProofChunk of every message in order, Parameter.Proof itself is ignored.
+type SubmitProofStreamParameter struct {
+	Parameter  *SubmitProofParameter `json:"parameter,omitempty"`
+	ProofChunk string                `json:"proof_chunk"`
+}
+
+// HeartbeatParameter the grpc Heartbeat stream request parameter,
+// the prover can piggyback the progress of its current task and the acknowledgement of a cancelled task on the heartbeat.
+type HeartbeatParameter struct {
+	ProverHeight  uint64                   `json:"prover_height"`
+	Progress      *ReportProgressParameter `json:"progress,omitempty"`
+	AckCancelTask *AckCancelTaskParameter  `json:"ack_cancel_task,omitempty"`
+}
+
+// HeartbeatSchema the grpc Heartbeat stream response
+type HeartbeatSchema struct {
+	Timestamp int64 `json:"timestamp"`
+	// CancelTasks are the tasks assigned to the prover which it should abort and acknowledge.
+	CancelTasks []*message.CancelTaskMsg `json:"cancel_tasks,omitempty"`
+}
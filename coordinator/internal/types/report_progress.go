@@ -0,0 +1,27 @@
+package types
+
+import "scroll-tech/common/types/message"
+
+// ReportProgressParameter the ReportProgress api request parameter
+type ReportProgressParameter struct {
+	UUID     string `form:"uuid" json:"uuid" binding:"required"`
+	TaskID   string `form:"task_id" json:"task_id" binding:"required"`
+	TaskType int    `form:"task_type" json:"task_type" binding:"required"`
+	Stage    int    `form:"stage" json:"stage" binding:"required"`
+	Percent  int    `form:"percent" json:"percent"`
+	// Recovered reports that the prover resumed the task after a restart.
+	Recovered bool `form:"recovered" json:"recovered"`
+}
+
+// ReportProgressSchema the ReportProgress api response
+type ReportProgressSchema struct {
+	// CancelTasks are the tasks assigned to the prover which it should abort and acknowledge.
+	CancelTasks []*message.CancelTaskMsg `json:"cancel_tasks,omitempty"`
+}
+
+// AckCancelTaskParameter the AckCancelTask api request parameter
+type AckCancelTaskParameter struct {
+	UUID     string `form:"uuid" json:"uuid" binding:"required"`
+	TaskID   string `form:"task_id" json:"task_id" binding:"required"`
+	TaskType int    `form:"task_type" json:"task_type" binding:"required"`
+}
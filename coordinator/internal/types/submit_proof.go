@@ -3,12 +3,41 @@ package types
 // SubmitProofParameter the SubmitProof api request parameter
 type SubmitProofParameter struct {
 	// TODO when prover have upgrade, need change this field to required
-	UUID         string `form:"uuid" json:"uuid"`
-	TaskID       string `form:"task_id" json:"task_id" binding:"required"`
-	TaskType     int    `form:"task_type" json:"task_type" binding:"required"`
-	Status       int    `form:"status" json:"status"`
-	Proof        string `form:"proof" json:"proof"`
-	FailureType  int    `form:"failure_type" json:"failure_type"`
-	FailureMsg   string `form:"failure_msg" json:"failure_msg"`
-	HardForkName string `form:"hard_fork_name" json:"hard_fork_name"`
+	UUID     string `form:"uuid" json:"uuid"`
+	TaskID   string `form:"task_id" json:"task_id" binding:"required"`
+	TaskType int    `form:"task_type" json:"task_type" binding:"required"`
+	Status   int    `form:"status" json:"status"`
+	Proof    string `form:"proof" json:"proof"`
+	// ProofParts are the hashes of the parts of a proof uploaded in parts, in order, the proof is their
+	// concatenation and Proof is ignored.
+	ProofParts   []string `form:"proof_parts" json:"proof_parts,omitempty"`
+	FailureType  int      `form:"failure_type" json:"failure_type"`
+	FailureMsg   string   `form:"failure_msg" json:"failure_msg"`
+	HardForkName string   `form:"hard_fork_name" json:"hard_fork_name"`
+	// ErrorCode and ErrorDetails classify the failure of a proof with a failed status, see message.ProofErrorCode.
+	ErrorCode    int               `form:"error_code" json:"error_code"`
+	ErrorDetails map[string]string `form:"-" json:"error_details,omitempty"`
+	// Nonce and Signature, the prover signs the message.ProofSubmission of the fields above with
+	// an increasing nonce, the coordinator rejects the submissions replaying a nonce.
+	Nonce     uint64 `form:"nonce" json:"nonce"`
+	Signature string `form:"signature" json:"signature"`
+}
+
+// UploadProofPartParameter the UploadProofPart api request parameter, a part of the proof of the prover task
+type UploadProofPartParameter struct {
+	UUID string `form:"uuid" json:"uuid" binding:"required"`
+	// PartHash is the hex keccak256 hash of the data.
+	PartHash string `form:"part_hash" json:"part_hash" binding:"required"`
+	// Data is the base64 encoded data of the part.
+	Data []byte `form:"-" json:"data" binding:"required"`
+}
+
+// ProofUploadParameter the GetProofUpload api request parameter
+type ProofUploadParameter struct {
+	UUID string `form:"uuid" json:"uuid" binding:"required"`
+}
+
+// ProofUploadSchema the parts of the proof of the prover task uploaded so far
+type ProofUploadSchema struct {
+	PartHashes []string `json:"part_hashes"`
 }
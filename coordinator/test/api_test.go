@@ -15,6 +15,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/stretchr/testify/assert"
@@ -51,6 +53,10 @@ var (
 	batchOrm           *orm.Batch
 	proverTaskOrm      *orm.ProverTask
 	proverBlockListOrm *orm.ProverBlockList
+	workReceiptOrm     *orm.WorkReceipt
+
+	// receiptKeyHex signs the work receipts of the accepted proofs.
+	receiptKeyHex = "8b3a350cf5c34c9194ca85829a2df0ec3153be0318b5e2d3348e872092edffba"
 
 	block1 *encoding.Block
 	block2 *encoding.Block
@@ -104,10 +110,12 @@ func setupCoordinator(t *testing.T, proversPerSession uint8, coordinatorURL stri
 			MaxVerifierWorkers:     10,
 			SessionAttempts:        5,
 			MinProverVersion:       version.Version,
+			Marketplace:            &config.Marketplace{ReceiptSigningKey: receiptKeyHex},
 		},
 		Auth: &config.Auth{
 			ChallengeExpireDurationSec: tokenTimeout,
 			LoginExpireDurationSec:     tokenTimeout,
+			LoginMaxRefreshDurationSec: tokenTimeout * 10,
 		},
 	}
 
@@ -172,6 +180,7 @@ func setEnv(t *testing.T) {
 	l2BlockOrm = orm.NewL2Block(db)
 	proverTaskOrm = orm.NewProverTask(db)
 	proverBlockListOrm = orm.NewProverBlockList(db)
+	workReceiptOrm = orm.NewWorkReceipt(db)
 
 	templateBlockTrace, err := os.ReadFile("../../common/testdata/blockTrace_02.json")
 	assert.NoError(t, err)
@@ -204,11 +213,14 @@ func TestApis(t *testing.T) {
 	t.Run("TestFailedHandshake", testFailedHandshake)
 	t.Run("TestGetTaskBlocked", testGetTaskBlocked)
 	t.Run("TestOutdatedProverVersion", testOutdatedProverVersion)
+	t.Run("TestRefreshToken", testRefreshToken)
 	t.Run("TestValidProof", testValidProof)
 	t.Run("TestInvalidProof", testInvalidProof)
 	t.Run("TestProofGeneratedFailed", testProofGeneratedFailed)
 	t.Run("TestTimeoutProof", testTimeoutProof)
 	t.Run("TestHardFork", testHardForkAssignTask)
+	t.Run("TestChaosProvers", testChaosProvers)
+	t.Run("TestGRPC", testGRPC)
 }
 
 func testHandshake(t *testing.T) {
@@ -312,6 +324,57 @@ func testOutdatedProverVersion(t *testing.T) {
 	assert.Equal(t, expectedErr, fmt.Errorf(errMsg))
 }
 
+func testRefreshToken(t *testing.T) {
+	coordinatorURL := randomURL()
+	collector, httpHandler := setupCoordinator(t, 1, coordinatorURL, map[string]int64{"homestead": forkNumberOne})
+	defer func() {
+		collector.Stop()
+		assert.NoError(t, httpHandler.Shutdown(context.Background()))
+	}()
+
+	prover := newMockProver(t, "prover_refresh_test", coordinatorURL, message.ProofTypeChunk, version.Version)
+	token := prover.connectToCoordinator(t, "homestead")
+	assert.NotEmpty(t, token)
+
+	login, code, errMsg := prover.refreshToken(t, token)
+	assert.Equal(t, types.Success, code)
+	assert.Empty(t, errMsg)
+	assert.NotEmpty(t, login.Token)
+	assert.Empty(t, login.Warning)
+
+	// the version policy changed since the login applies to the refresh.
+	conf.ProverManager.VersionPolicy = &config.VersionPolicy{
+		DeprecatedProverVersion: "v4.1.99",
+		DeprecationDeadline:     time.Now().Add(time.Hour).Unix(),
+	}
+	defer func() {
+		conf.ProverManager.VersionPolicy = nil
+	}()
+	login, code, errMsg = prover.refreshToken(t, login.Token)
+	assert.Equal(t, types.Success, code)
+	assert.Empty(t, errMsg)
+	assert.Contains(t, login.Warning, "is deprecated")
+
+	conf.ProverManager.VersionPolicy.DeprecationDeadline = time.Now().Add(-time.Hour).Unix()
+	_, code, errMsg = prover.refreshToken(t, login.Token)
+	assert.Equal(t, types.ErrJWTCommonErr, code)
+	assert.Contains(t, errMsg, "deprecated prover version")
+	conf.ProverManager.VersionPolicy = nil
+
+	// so does the allow list.
+	conf.Auth.RequireProverAllowList = true
+	defer func() {
+		conf.Auth.RequireProverAllowList = false
+	}()
+	_, code, errMsg = prover.refreshToken(t, login.Token)
+	assert.Equal(t, types.ErrJWTCommonErr, code)
+	assert.Contains(t, errMsg, "not in the prover allow list")
+	conf.Auth.RequireProverAllowList = false
+
+	_, code, _ = prover.refreshToken(t, "invalid")
+	assert.Equal(t, types.ErrJWTCommonErr, code)
+}
+
 func testHardForkAssignTask(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -581,6 +644,8 @@ func testValidProof(t *testing.T) {
 		assert.Equal(t, errMsg, "")
 		assert.NotNil(t, proverTask)
 		provers[i].submitProof(t, proverTask, proofStatus, types.Success, "istanbul")
+		// a duplicate of the submission gets the original result back.
+		provers[i].submitProof(t, proverTask, proofStatus, types.Success, "istanbul")
 	}
 
 	// verify proof status
@@ -606,6 +671,7 @@ func testValidProof(t *testing.T) {
 			batchProofStatus, err = batchOrm.GetProvingStatusByHash(context.Background(), batch.Hash)
 			assert.NoError(t, err)
 			if chunkProofStatus == types.ProvingTaskVerified && batchProofStatus == types.ProvingTaskVerified {
+				checkWorkReceipts(t, provers)
 				return
 			}
 
@@ -626,6 +692,24 @@ func testValidProof(t *testing.T) {
 	}
 }
 
+// checkWorkReceipts checks every prover got a receipt signed by the coordinator for its accepted proof.
+func checkWorkReceipts(t *testing.T, provers []*mockProver) {
+	receiptKey, err := crypto.HexToECDSA(receiptKeyHex)
+	assert.NoError(t, err)
+
+	for _, prover := range provers {
+		receipts, err := workReceiptOrm.GetWorkReceipts(context.Background(), 0, prover.publicKey(), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(receipts))
+		for _, receipt := range receipts {
+			assert.NotEqual(t, common.Hash{}, common.HexToHash(receipt.ProofHash))
+			signer, err := receipt.Message().Signer(receipt.Signature)
+			assert.NoError(t, err)
+			assert.Equal(t, crypto.PubkeyToAddress(receiptKey.PublicKey), signer)
+		}
+	}
+}
+
 func testInvalidProof(t *testing.T) {
 	// Setup coordinator and ws server.
 	coordinatorURL := randomURL()
@@ -655,6 +739,8 @@ func testInvalidProof(t *testing.T) {
 	assert.Equal(t, errCode, types.Success)
 	assert.Equal(t, errMsg, "")
 	prover.submitProof(t, proverTask, provingStatus, expectErrCode, "istanbul")
+	// a duplicate of the submission gets the original result back.
+	prover.submitProof(t, proverTask, provingStatus, expectErrCode, "istanbul")
 
 	// verify proof status
 	var (
@@ -868,4 +954,18 @@ func testTimeoutProof(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 2, int(batchMaxAttempts))
 	assert.Equal(t, 0, int(batchActiveAttempts))
+
+	// the late proofs of the first provers are rejected, however many times they are submitted.
+	for i := 0; i < 2; i++ {
+		chunkProver1.submitProof(t, proverChunkTask, verifiedSuccess, types.ErrCoordinatorHandleZkProofFailure, "istanbul")
+		batchProver1.submitProof(t, proverBatchTask, verifiedSuccess, types.ErrCoordinatorHandleZkProofFailure, "istanbul")
+	}
+
+	chunkProofStatus3, err := chunkOrm.GetProvingStatusByHash(context.Background(), dbChunk.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, chunkProofStatus3, types.ProvingTaskVerified)
+
+	batchProofStatus3, err := batchOrm.GetProvingStatusByHash(context.Background(), batch.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, batchProofStatus3, types.ProvingTaskVerified)
 }
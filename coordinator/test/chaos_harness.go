@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	ctypes "scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/version"
+
+	"scroll-tech/coordinator/internal/types"
+)
+
+// chaosPollInterval is how often the simulated provers ask the coordinator for a task.
+const chaosPollInterval = 500 * time.Millisecond
+
+// proverBehavior is how a simulated prover of the chaos harness handles the tasks it gets.
+type proverBehavior int
+
+const (
+	// behaviorHonest provers submit a valid proof right away.
+	behaviorHonest proverBehavior = iota
+	// behaviorSlow provers submit a valid proof after their delay, past the task deadline if it's long enough.
+	behaviorSlow
+	// behaviorCrash provers take a task and never come back, as if they crashed.
+	behaviorCrash
+	// behaviorInvalidProof provers submit proofs the verifier rejects.
+	behaviorInvalidProof
+	// behaviorReplay provers replay the signed submissions of the other provers, and sign their own with a stale nonce.
+	behaviorReplay
+)
+
+func (b proverBehavior) String() string {
+	switch b {
+	case behaviorHonest:
+		return "honest"
+	case behaviorSlow:
+		return "slow"
+	case behaviorCrash:
+		return "crash"
+	case behaviorInvalidProof:
+		return "invalid_proof"
+	case behaviorReplay:
+		return "replay"
+	default:
+		return "unknown"
+	}
+}
+
+// chaosProverSpec describes a group of simulated provers with the same behavior.
+type chaosProverSpec struct {
+	behavior  proverBehavior
+	proofType message.ProofType
+	count     int
+	// startAfter delays the first task request, e.g. to let the faulty provers take the tasks first.
+	startAfter time.Duration
+	// delay is how long a slow prover takes to submit.
+	delay time.Duration
+	// maxTasks is how many tasks a prover takes before leaving, 0 for no limit.
+	maxTasks int
+}
+
+// chaosStats counts what the provers of a behavior did.
+type chaosStats struct {
+	// Tasks is the number of tasks assigned to the provers.
+	Tasks int
+	// Accepted and Rejected are the numbers of submissions the coordinator accepted and rejected.
+	Accepted int
+	Rejected int
+}
+
+type chaosProver struct {
+	*mockProver
+	spec chaosProverSpec
+}
+
+// chaosHarness runs simulated provers against a coordinator, to check the scheduler and the timeouts keep
+// the tasks moving when some provers misbehave.
+type chaosHarness struct {
+	t        *testing.T
+	forkName string
+	provers  []*chaosProver
+
+	mu    sync.Mutex
+	stats map[proverBehavior]*chaosStats
+	// captured are the signed submissions of the honest and slow provers, replayed by the replay provers.
+	captured []*types.SubmitProofParameter
+}
+
+func newChaosHarness(t *testing.T, coordinatorURL string, forkName string, specs []chaosProverSpec) *chaosHarness {
+	h := &chaosHarness{
+		t:        t,
+		forkName: forkName,
+		stats:    make(map[proverBehavior]*chaosStats),
+	}
+	for _, spec := range specs {
+		h.stats[spec.behavior] = &chaosStats{}
+		for i := 0; i < spec.count; i++ {
+			proverName := "prover_chaos_" + spec.behavior.String() + "_" + strconv.Itoa(int(spec.proofType)) + "_" + strconv.Itoa(i)
+			h.provers = append(h.provers, &chaosProver{
+				mockProver: newMockProver(t, proverName, coordinatorURL, spec.proofType, version.Version),
+				spec:       spec,
+			})
+		}
+	}
+	return h
+}
+
+// run runs the provers until the context is done, or they all left.
+func (h *chaosHarness) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, p := range h.provers {
+		wg.Add(1)
+		go func(p *chaosProver) {
+			defer wg.Done()
+			h.runProver(ctx, p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// statsOf returns what the provers of the behavior did so far.
+func (h *chaosHarness) statsOf(behavior proverBehavior) chaosStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if stats, ok := h.stats[behavior]; ok {
+		return *stats
+	}
+	return chaosStats{}
+}
+
+func (h *chaosHarness) runProver(ctx context.Context, p *chaosProver) {
+	if !sleepContext(ctx, p.spec.startAfter) {
+		return
+	}
+	for tasks := 0; p.spec.maxTasks == 0 || tasks < p.spec.maxTasks; {
+		task, errCode, _ := p.getProverTask(h.t, p.spec.proofType, h.forkName)
+		if errCode == ctypes.Success {
+			tasks++
+			h.record(p.spec.behavior, func(stats *chaosStats) { stats.Tasks++ })
+			if !h.handleTask(ctx, p, task) {
+				return
+			}
+		}
+		if !sleepContext(ctx, chaosPollInterval) {
+			return
+		}
+	}
+}
+
+// handleTask acts on the task as the behavior of the prover says, it returns false if the prover is gone.
+func (h *chaosHarness) handleTask(ctx context.Context, p *chaosProver, task *types.GetTaskSchema) bool {
+	switch p.spec.behavior {
+	case behaviorCrash:
+		return false
+	case behaviorSlow:
+		if !sleepContext(ctx, p.spec.delay) {
+			return false
+		}
+		h.submit(p, task, verifiedSuccess)
+	case behaviorInvalidProof:
+		h.submit(p, task, verifiedFailed)
+	case behaviorReplay:
+		if replayed := h.capturedSubmission(); replayed != nil {
+			h.recordResult(p.spec.behavior, p.postProof(h.t, replayed, h.forkName))
+		}
+		// the nonce of the last submission of the prover, it's not increased.
+		stale := p.signProof(h.t, task, verifiedSuccess, p.submitNonce)
+		h.recordResult(p.spec.behavior, p.postProof(h.t, stale, h.forkName))
+	default:
+		h.submit(p, task, verifiedSuccess)
+	}
+	return true
+}
+
+func (h *chaosHarness) submit(p *chaosProver, task *types.GetTaskSchema, proofStatus proofStatus) {
+	p.submitNonce++
+	submitProof := p.signProof(h.t, task, proofStatus, p.submitNonce)
+	errCode := p.postProof(h.t, submitProof, h.forkName)
+	h.recordResult(p.spec.behavior, errCode)
+	if proofStatus == verifiedSuccess {
+		h.mu.Lock()
+		h.captured = append(h.captured, submitProof)
+		h.mu.Unlock()
+	}
+}
+
+func (h *chaosHarness) capturedSubmission() *types.SubmitProofParameter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.captured) == 0 {
+		return nil
+	}
+	return h.captured[len(h.captured)-1]
+}
+
+func (h *chaosHarness) recordResult(behavior proverBehavior, errCode int) {
+	h.record(behavior, func(stats *chaosStats) {
+		if errCode == ctypes.Success {
+			stats.Accepted++
+		} else {
+			stats.Rejected++
+		}
+	})
+}
+
+func (h *chaosHarness) record(behavior proverBehavior, update func(stats *chaosStats)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	update(h.stats[behavior])
+}
+
+// sleepContext waits for the duration, it returns false if the context is done first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
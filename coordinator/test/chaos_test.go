@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+)
+
+func testChaosProvers(t *testing.T) {
+	coordinatorURL := randomURL()
+	collector, httpHandler := setupCoordinator(t, 1, coordinatorURL, map[string]int64{"istanbul": forkNumberTwo})
+	defer func() {
+		collector.Stop()
+		assert.NoError(t, httpHandler.Shutdown(context.Background()))
+	}()
+
+	err := l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+	assert.NoError(t, err)
+	dbChunk, err := chunkOrm.InsertChunk(context.Background(), chunk)
+	assert.NoError(t, err)
+	err = l2BlockOrm.UpdateChunkHashInRange(context.Background(), 0, 100, dbChunk.Hash)
+	assert.NoError(t, err)
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch)
+	assert.NoError(t, err)
+	err = chunkOrm.UpdateBatchHashInRange(context.Background(), 0, 0, dbBatch.Hash)
+	assert.NoError(t, err)
+
+	// the faulty provers take one task each and get the tasks first, the honest provers join once the
+	// scheduler has to recover the tasks from them. They're fewer than the session attempts of a task.
+	collectionTime := time.Duration(conf.ProverManager.ChunkCollectionTimeSec) * time.Second
+	harness := newChaosHarness(t, coordinatorURL, "istanbul", []chaosProverSpec{
+		{behavior: behaviorCrash, proofType: message.ProofTypeChunk, count: 1, maxTasks: 1},
+		{behavior: behaviorInvalidProof, proofType: message.ProofTypeChunk, count: 1, maxTasks: 1},
+		{behavior: behaviorReplay, proofType: message.ProofTypeChunk, count: 1, maxTasks: 1},
+		{behavior: behaviorSlow, proofType: message.ProofTypeChunk, count: 1, maxTasks: 1, delay: 2 * collectionTime},
+		{behavior: behaviorCrash, proofType: message.ProofTypeBatch, count: 1, maxTasks: 1},
+		{behavior: behaviorInvalidProof, proofType: message.ProofTypeBatch, count: 1, maxTasks: 1},
+		{behavior: behaviorHonest, proofType: message.ProofTypeChunk, count: 1, startAfter: 3 * time.Second},
+		{behavior: behaviorHonest, proofType: message.ProofTypeBatch, count: 1, startAfter: 3 * time.Second},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		harness.run(ctx)
+		close(done)
+	}()
+
+	var (
+		tick     = time.Tick(1500 * time.Millisecond)
+		tickStop = time.Tick(3 * time.Minute)
+
+		chunkProofStatus types.ProvingStatus
+		batchProofStatus types.ProvingStatus
+	)
+
+loop:
+	for {
+		select {
+		case <-tick:
+			chunkProofStatus, err = chunkOrm.GetProvingStatusByHash(context.Background(), dbChunk.Hash)
+			assert.NoError(t, err)
+			batchProofStatus, err = batchOrm.GetProvingStatusByHash(context.Background(), dbBatch.Hash)
+			assert.NoError(t, err)
+			if chunkProofStatus == types.ProvingTaskVerified && batchProofStatus == types.ProvingTaskVerified {
+				break loop
+			}
+		case <-tickStop:
+			t.Error("failed to check proof status", "chunkProofStatus", chunkProofStatus.String(), "batchProofStatus", batchProofStatus.String())
+			break loop
+		}
+	}
+	cancel()
+	<-done
+
+	// the faulty provers got tasks before the honest ones, the tasks were recovered and assigned again.
+	chunkActiveAttempts, chunkMaxAttempts, err := chunkOrm.GetAttemptsByHash(context.Background(), dbChunk.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, int(chunkActiveAttempts))
+	assert.GreaterOrEqual(t, int(chunkMaxAttempts), 2)
+	assert.LessOrEqual(t, int(chunkMaxAttempts), int(conf.ProverManager.SessionAttempts))
+
+	_, batchMaxAttempts, err := batchOrm.GetAttemptsByHash(context.Background(), dbBatch.Hash)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, int(batchMaxAttempts), int(conf.ProverManager.SessionAttempts))
+
+	honest := harness.statsOf(behaviorHonest)
+	assert.GreaterOrEqual(t, honest.Accepted, 1)
+	assert.Equal(t, 0, honest.Rejected)
+
+	// the invalid proofs and the replayed submissions are never accepted.
+	invalidProof := harness.statsOf(behaviorInvalidProof)
+	assert.Equal(t, 0, invalidProof.Accepted)
+	assert.Equal(t, invalidProof.Tasks, invalidProof.Rejected)
+	replay := harness.statsOf(behaviorReplay)
+	assert.Equal(t, 0, replay.Accepted)
+	assert.GreaterOrEqual(t, replay.Rejected, replay.Tasks)
+
+	crash := harness.statsOf(behaviorCrash)
+	assert.Equal(t, 0, crash.Accepted+crash.Rejected)
+
+	// the slow prover submitted past the deadline, its task was assigned again meanwhile.
+	slow := harness.statsOf(behaviorSlow)
+	assert.Equal(t, 0, slow.Accepted)
+	assert.Equal(t, slow.Tasks, slow.Rejected)
+}
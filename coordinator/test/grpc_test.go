@@ -0,0 +1,249 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	ctypes "scroll-tech/common/types"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/version"
+
+	"scroll-tech/coordinator/internal/controller/rpc"
+	"scroll-tech/coordinator/internal/types"
+)
+
+const grpcServiceName = "/coordinator.v1.Coordinator/"
+
+// grpcResponse is the types.Response of a grpc stream with the schema of the method as the data.
+type grpcResponse[T any] struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	Data    T      `json:"data"`
+}
+
+// testCerts is a certificate authority signing the certificates of the grpc server and of a prover.
+type testCerts struct {
+	caPool     *x509.CertPool
+	clientCert tls.Certificate
+	tlsConfig  *rpc.TLSConfig
+}
+
+func newTestCerts(t *testing.T) *testCerts {
+	dir := t.TempDir()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "provers-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+
+	issue := func(serial int64, commonName string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+		key, keyErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, keyErr)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: commonName},
+			DNSNames:     []string{commonName},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, certErr := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		assert.NoError(t, certErr)
+		keyDER, keyErr := x509.MarshalECPrivateKey(key)
+		assert.NoError(t, keyErr)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	certs := &testCerts{
+		caPool: x509.NewCertPool(),
+		tlsConfig: &rpc.TLSConfig{
+			CertFile:     filepath.Join(dir, "server.crt"),
+			KeyFile:      filepath.Join(dir, "server.key"),
+			ClientCAFile: filepath.Join(dir, "provers-ca.crt"),
+		},
+	}
+	certs.caPool.AddCert(caCert)
+	serverCert, serverKey := issue(2, "coordinator", x509.ExtKeyUsageServerAuth)
+	assert.NoError(t, os.WriteFile(certs.tlsConfig.CertFile, serverCert, 0600))
+	assert.NoError(t, os.WriteFile(certs.tlsConfig.KeyFile, serverKey, 0600))
+	assert.NoError(t, os.WriteFile(certs.tlsConfig.ClientCAFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+	clientCert, clientKey := issue(3, "prover", x509.ExtKeyUsageClientAuth)
+	certs.clientCert, err = tls.X509KeyPair(clientCert, clientKey)
+	assert.NoError(t, err)
+	return certs
+}
+
+// dialGRPC connects to the grpc server on the in-memory listener, with the prover certificate if withClientCert.
+func dialGRPC(t *testing.T, lis *bufconn.Listener, certs *testCerts, withClientCert bool) *grpc.ClientConn {
+	tlsConfig := &tls.Config{RootCAs: certs.caPool, ServerName: "coordinator", MinVersion: tls.VersionTLS12}
+	if withClientCert {
+		tlsConfig.Certificates = []tls.Certificate{certs.clientCert}
+	}
+	conn, err := grpc.NewClient("passthrough:///coordinator",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype("json")),
+	)
+	assert.NoError(t, err)
+	return conn
+}
+
+func testGRPC(t *testing.T) {
+	coordinatorURL := randomURL()
+	collector, httpHandler := setupCoordinator(t, 1, coordinatorURL, map[string]int64{"istanbul": forkNumberTwo})
+	defer func() {
+		collector.Stop()
+		assert.NoError(t, httpHandler.Shutdown(context.Background()))
+	}()
+
+	certs := newTestCerts(t)
+	srv, err := rpc.NewServer(conf, certs.tlsConfig)
+	assert.NoError(t, err)
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		assert.NoError(t, srv.Serve(lis))
+	}()
+	defer srv.Stop()
+
+	getTaskDesc := &grpc.StreamDesc{ServerStreams: true}
+	getTaskParameter := &types.GetTaskParameter{ProverHeight: 100, TaskType: int(message.ProofTypeChunk)}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// a prover without a certificate signed by the provers ca is refused by the mutual tls.
+	conn := dialGRPC(t, lis, certs, false)
+	stream, err := conn.NewStream(ctx, getTaskDesc, grpcServiceName+"GetTask")
+	if err == nil {
+		err = stream.SendMsg(getTaskParameter)
+		if err == nil {
+			err = stream.RecvMsg(&grpcResponse[types.GetTaskSchema]{})
+		}
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.NoError(t, conn.Close())
+
+	conn = dialGRPC(t, lis, certs, true)
+	defer conn.Close()
+
+	// the jwt token of the http login is required in the metadata.
+	for _, md := range []metadata.MD{nil, metadata.Pairs("authorization", "Bearer invalid")} {
+		streamCtx := ctx
+		if md != nil {
+			streamCtx = metadata.NewOutgoingContext(ctx, md)
+		}
+		stream, err = conn.NewStream(streamCtx, getTaskDesc, grpcServiceName+"GetTask")
+		assert.NoError(t, err)
+		assert.NoError(t, stream.SendMsg(getTaskParameter))
+		err = stream.RecvMsg(&grpcResponse[types.GetTaskSchema]{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	prover := newMockProver(t, "prover_grpc_test", coordinatorURL, message.ProofTypeChunk, version.Version)
+	token := prover.connectToCoordinator(t, "istanbul")
+	proverCtx := metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+
+	// the task is pushed to the waiting prover once it's ready.
+	getTaskCtx, cancelGetTask := context.WithCancel(proverCtx)
+	defer cancelGetTask()
+	stream, err = conn.NewStream(getTaskCtx, getTaskDesc, grpcServiceName+"GetTask")
+	assert.NoError(t, err)
+	assert.NoError(t, stream.SendMsg(getTaskParameter))
+	assert.NoError(t, stream.CloseSend())
+
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2}))
+	dbChunk, err := chunkOrm.InsertChunk(context.Background(), chunk)
+	assert.NoError(t, err)
+	assert.NoError(t, l2BlockOrm.UpdateChunkHashInRange(context.Background(), 0, 100, dbChunk.Hash))
+
+	var taskResp grpcResponse[types.GetTaskSchema]
+	assert.NoError(t, stream.RecvMsg(&taskResp))
+	assert.Equal(t, ctypes.Success, taskResp.ErrCode)
+	assert.Empty(t, taskResp.ErrMsg)
+	task := taskResp.Data
+	assert.Equal(t, dbChunk.Hash, task.TaskID)
+	assert.Equal(t, int(message.ProofTypeChunk), task.TaskType)
+	cancelGetTask()
+
+	// the heartbeats are answered one by one, carrying the progress of the task.
+	heartbeat, err := conn.NewStream(proverCtx, &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}, grpcServiceName+"Heartbeat")
+	assert.NoError(t, err)
+	for _, progress := range []*types.ReportProgressParameter{
+		nil,
+		{UUID: task.UUID, TaskID: task.TaskID, TaskType: task.TaskType, Stage: int(message.ProvingStageProving), Percent: 50},
+		{UUID: task.UUID},
+	} {
+		assert.NoError(t, heartbeat.SendMsg(&types.HeartbeatParameter{ProverHeight: 100, Progress: progress}))
+		var heartbeatResp grpcResponse[types.HeartbeatSchema]
+		assert.NoError(t, heartbeat.RecvMsg(&heartbeatResp))
+		if progress != nil && progress.TaskID == "" {
+			assert.Equal(t, ctypes.ErrCoordinatorParameterInvalidNo, heartbeatResp.ErrCode)
+			assert.Contains(t, heartbeatResp.ErrMsg, "parameter invalid")
+			continue
+		}
+		assert.Equal(t, ctypes.Success, heartbeatResp.ErrCode)
+		assert.NotZero(t, heartbeatResp.Data.Timestamp)
+	}
+	assert.NoError(t, heartbeat.CloseSend())
+	assert.Error(t, heartbeat.RecvMsg(&grpcResponse[types.HeartbeatSchema]{}))
+
+	proverTasks, err := proverTaskOrm.GetProverTasksByHashes(context.Background(), message.ProofTypeChunk, []string{task.TaskID})
+	assert.NoError(t, err)
+	assert.Len(t, proverTasks, 1)
+	assert.Equal(t, int16(message.ProvingStageProving), proverTasks[0].ProgressStage)
+	assert.Equal(t, int16(50), proverTasks[0].ProgressPercent)
+
+	// the proof is uploaded in chunks, the response is sent once the prover closes the stream.
+	submission := prover.signProof(t, &task, verifiedSuccess, 1)
+	proof := submission.Proof
+	submission.Proof = ""
+	upload, err := conn.NewStream(proverCtx, &grpc.StreamDesc{ClientStreams: true}, grpcServiceName+"SubmitProof")
+	assert.NoError(t, err)
+	assert.NoError(t, upload.SendMsg(&types.SubmitProofStreamParameter{Parameter: submission, ProofChunk: proof[:len(proof)/2]}))
+	assert.NoError(t, upload.SendMsg(&types.SubmitProofStreamParameter{ProofChunk: proof[len(proof)/2:]}))
+	assert.NoError(t, upload.CloseSend())
+	var submitResp grpcResponse[json.RawMessage]
+	assert.NoError(t, upload.RecvMsg(&submitResp))
+	assert.Equal(t, ctypes.Success, submitResp.ErrCode)
+	assert.Empty(t, submitResp.ErrMsg)
+
+	// a stream without the submit proof parameter is rejected.
+	upload, err = conn.NewStream(proverCtx, &grpc.StreamDesc{ClientStreams: true}, grpcServiceName+"SubmitProof")
+	assert.NoError(t, err)
+	assert.NoError(t, upload.SendMsg(&types.SubmitProofStreamParameter{ProofChunk: proof}))
+	assert.NoError(t, upload.CloseSend())
+	err = upload.RecvMsg(&submitResp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
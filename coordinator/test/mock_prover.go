@@ -34,6 +34,7 @@ type mockProver struct {
 	privKey        *ecdsa.PrivateKey
 	proofType      message.ProofType
 	coordinatorURL string
+	submitNonce    uint64
 }
 
 func newMockProver(t *testing.T, proverName string, coordinatorURL string, proofType message.ProofType, version string) *mockProver {
@@ -125,6 +126,25 @@ func (r *mockProver) login(t *testing.T, challengeString string, forkName string
 	return loginData.Token
 }
 
+// refreshToken exchanges the login token for a new one, and returns the response of the coordinator.
+func (r *mockProver) refreshToken(t *testing.T, token string) (*types.LoginSchema, int, string) {
+	type response struct {
+		ErrCode int               `json:"errcode"`
+		ErrMsg  string            `json:"errmsg"`
+		Data    types.LoginSchema `json:"data"`
+	}
+
+	var result response
+	client := resty.New()
+	resp, err := client.R().
+		SetHeader("Authorization", fmt.Sprintf("Bearer %s", token)).
+		SetResult(&result).
+		Post("http://" + r.coordinatorURL + "/coordinator/v1/refresh_token")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	return &result.Data, result.ErrCode, result.ErrMsg
+}
+
 func (r *mockProver) healthCheckSuccess(t *testing.T) bool {
 	var result ctypes.Response
 	client := resty.New()
@@ -202,6 +222,13 @@ func (r *mockProver) tryGetProverTask(t *testing.T, proofType message.ProofType,
 }
 
 func (r *mockProver) submitProof(t *testing.T, proverTaskSchema *types.GetTaskSchema, proofStatus proofStatus, errCode int, forkName string) {
+	r.submitNonce++
+	submitProof := r.signProof(t, proverTaskSchema, proofStatus, r.submitNonce)
+	assert.Equal(t, errCode, r.postProof(t, submitProof, forkName))
+}
+
+// signProof builds the submission of the proof for the prover task, signed with the nonce.
+func (r *mockProver) signProof(t *testing.T, proverTaskSchema *types.GetTaskSchema, proofStatus proofStatus, nonce uint64) *types.SubmitProofParameter {
 	proofMsgStatus := message.StatusOk
 	if proofStatus == generatedFailed {
 		proofMsgStatus = message.StatusProofError
@@ -209,11 +236,17 @@ func (r *mockProver) submitProof(t *testing.T, proverTaskSchema *types.GetTaskSc
 
 	proof := &message.ProofMsg{
 		ProofDetail: &message.ProofDetail{
-			ID:         proverTaskSchema.TaskID,
-			Type:       message.ProofType(proverTaskSchema.TaskType),
-			Status:     proofMsgStatus,
-			ChunkProof: &message.ChunkProof{},
-			BatchProof: &message.BatchProof{},
+			ID:     proverTaskSchema.TaskID,
+			Type:   message.ProofType(proverTaskSchema.TaskType),
+			Status: proofMsgStatus,
+			ChunkProof: &message.ChunkProof{
+				Proof:     make([]byte, 32),
+				Instances: make([]byte, 32),
+			},
+			BatchProof: &message.BatchProof{
+				Proof:     make([]byte, 32),
+				Instances: make([]byte, 32),
+			},
 		},
 	}
 
@@ -226,6 +259,7 @@ func (r *mockProver) submitProof(t *testing.T, proverTaskSchema *types.GetTaskSc
 
 	assert.NoError(t, proof.Sign(r.privKey))
 	submitProof := types.SubmitProofParameter{
+		UUID:     proverTaskSchema.UUID,
 		TaskID:   proof.ID,
 		TaskType: int(proof.Type),
 		Status:   int(proof.Status),
@@ -244,6 +278,23 @@ func (r *mockProver) submitProof(t *testing.T, proverTaskSchema *types.GetTaskSc
 		submitProof.Proof = string(encodeData)
 	}
 
+	submission := message.ProofSubmission{
+		UUID:      submitProof.UUID,
+		TaskID:    submitProof.TaskID,
+		TaskType:  message.ProofType(submitProof.TaskType),
+		Status:    message.RespStatus(submitProof.Status),
+		ProofHash: crypto.Keccak256Hash([]byte(submitProof.Proof)),
+		Nonce:     nonce,
+	}
+	signature, err := submission.Sign(r.privKey)
+	assert.NoError(t, err)
+	submitProof.Nonce = submission.Nonce
+	submitProof.Signature = signature
+	return &submitProof
+}
+
+// postProof sends the submission to the coordinator as this prover, and returns the errcode of the response.
+func (r *mockProver) postProof(t *testing.T, submitProof *types.SubmitProofParameter, forkName string) int {
 	token := r.connectToCoordinator(t, forkName)
 	assert.NotEmpty(t, token)
 
@@ -261,7 +312,7 @@ func (r *mockProver) submitProof(t *testing.T, proverTaskSchema *types.GetTaskSc
 		Post("http://" + r.coordinatorURL + "/coordinator/v1/submit_proof")
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode())
-	assert.Equal(t, errCode, result.ErrCode)
+	return result.ErrCode
 }
 
 func (r *mockProver) publicKey() string {
@@ -3,18 +3,24 @@ package migrate
 import (
 	"database/sql"
 	"embed"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/pressly/goose/v3"
 )
 
-//go:embed migrations/*.sql
+//go:embed migrations/*.sql migrations_sqlite/*.sql
 var embedMigrations embed.FS
 
 // MigrationsDir migration dir
 const MigrationsDir string = "migrations"
 
+// SQLiteMigrationsDir the migration dir of the sqlite backend used for development and tests,
+// it keeps the same versions as MigrationsDir.
+const SQLiteMigrationsDir string = "migrations_sqlite"
+
 func init() {
 	goose.SetBaseFS(embedMigrations)
 	goose.SetSequential(true)
@@ -24,18 +30,34 @@ func init() {
 	goose.SetVerbose(verbose)
 }
 
+// migrationsDir sets the goose dialect of the db driver and returns the migration dir of the dialect.
+func migrationsDir(db *sql.DB) (string, error) {
+	if strings.Contains(strings.ToLower(fmt.Sprintf("%T", db.Driver())), "sqlite") {
+		return SQLiteMigrationsDir, goose.SetDialect("sqlite3")
+	}
+	return MigrationsDir, goose.SetDialect("postgres")
+}
+
 // Migrate migrate db
 func Migrate(db *sql.DB) error {
+	dir, err := migrationsDir(db)
+	if err != nil {
+		return err
+	}
 	//return goose.Up(db, MIGRATIONS_DIR, goose.WithAllowMissing())
-	return goose.Up(db, MigrationsDir, goose.WithAllowMissing())
+	return goose.Up(db, dir, goose.WithAllowMissing())
 }
 
 // Rollback rollback to the given version
 func Rollback(db *sql.DB, version *int64) error {
+	dir, err := migrationsDir(db)
+	if err != nil {
+		return err
+	}
 	if version != nil {
-		return goose.DownTo(db, MigrationsDir, *version)
+		return goose.DownTo(db, dir, *version)
 	}
-	return goose.Down(db, MigrationsDir)
+	return goose.Down(db, dir)
 }
 
 // ResetDB clean and migrate db.
@@ -48,12 +70,19 @@ func ResetDB(db *sql.DB) error {
 
 // Current get current version
 func Current(db *sql.DB) (int64, error) {
+	if _, err := migrationsDir(db); err != nil {
+		return 0, err
+	}
 	return goose.GetDBVersion(db)
 }
 
 // Status is normal or not
 func Status(db *sql.DB) error {
-	return goose.Version(db, MigrationsDir)
+	dir, err := migrationsDir(db)
+	if err != nil {
+		return err
+	}
+	return goose.Version(db, dir)
 }
 
 // Create a new migration folder
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	_ "github.com/lib/pq"
+	"github.com/pressly/goose/v3"
 	"github.com/stretchr/testify/assert"
 
 	"scroll-tech/common/testcontainers"
@@ -59,26 +60,36 @@ func testResetDB(t *testing.T) {
 	cur, err := Current(pgDB)
 	assert.NoError(t, err)
 	// total number of tables.
-	assert.Equal(t, int64(20), cur)
+	assert.Equal(t, int64(50), cur)
 }
 
 func testMigrate(t *testing.T) {
 	assert.NoError(t, Migrate(pgDB))
 	cur, err := Current(pgDB)
 	assert.NoError(t, err)
-	assert.Equal(t, int64(20), cur)
+	assert.Equal(t, int64(50), cur)
 }
 
 func testRollback(t *testing.T) {
 	version, err := Current(pgDB)
 	assert.NoError(t, err)
-	assert.Equal(t, int64(20), version)
+	assert.Equal(t, int64(50), version)
+
+	// a single rollback steps back to the previous migration, the sqlite schema starts at the current version.
+	dir, err := migrationsDir(pgDB)
+	assert.NoError(t, err)
+	migrations, err := goose.CollectMigrations(dir, 0, version)
+	assert.NoError(t, err)
+	previous := int64(0)
+	if len(migrations) > 1 {
+		previous = migrations[len(migrations)-2].Version
+	}
 
 	assert.NoError(t, Rollback(pgDB, nil))
 
 	cur, err := Current(pgDB)
 	assert.NoError(t, err)
-	assert.Equal(t, version, cur+1)
+	assert.Equal(t, previous, cur)
 
 	targetVersion := int64(0)
 	assert.NoError(t, Rollback(pgDB, &targetVersion))
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l2_block
+ADD COLUMN skip_reason VARCHAR DEFAULT NULL;
+
+create index l2_block_skip_reason_index
+on l2_block (number) where skip_reason IS NOT NULL and deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+drop index if exists l2_block_skip_reason_index;
+
+ALTER TABLE IF EXISTS l2_block
+DROP COLUMN skip_reason;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE prover_score
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    public_key                VARCHAR      NOT NULL,
+
+-- debug info
+    prover_name               VARCHAR      NOT NULL,
+
+-- stats
+    success_count             BIGINT       NOT NULL DEFAULT 0,
+    panic_failure_count       BIGINT       NOT NULL DEFAULT 0,
+    no_panic_failure_count    BIGINT       NOT NULL DEFAULT 0,
+    verified_failure_count    BIGINT       NOT NULL DEFAULT 0,
+    timeout_count             BIGINT       NOT NULL DEFAULT 0,
+    total_proving_time_sec    BIGINT       NOT NULL DEFAULT 0,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_prover_score_on_public_key ON prover_score(public_key) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prover_score;
+-- +goose StatementEnd
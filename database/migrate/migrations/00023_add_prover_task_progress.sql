@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_task
+ADD COLUMN progress_stage SMALLINT NOT NULL DEFAULT 0,
+ADD COLUMN progress_percent SMALLINT NOT NULL DEFAULT 0,
+ADD COLUMN progress_reported_at TIMESTAMP(0) DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS prover_task
+DROP COLUMN progress_stage,
+DROP COLUMN progress_percent,
+DROP COLUMN progress_reported_at;
+
+-- +goose StatementEnd
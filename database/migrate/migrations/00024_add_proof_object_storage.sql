@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk
+ADD COLUMN proof_hash VARCHAR DEFAULT NULL,
+ADD COLUMN proof_uri VARCHAR DEFAULT NULL;
+
+ALTER TABLE batch
+ADD COLUMN proof_hash VARCHAR DEFAULT NULL,
+ADD COLUMN proof_uri VARCHAR DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS chunk
+DROP COLUMN proof_hash,
+DROP COLUMN proof_uri;
+
+ALTER TABLE IF EXISTS batch
+DROP COLUMN proof_hash,
+DROP COLUMN proof_uri;
+
+-- +goose StatementEnd
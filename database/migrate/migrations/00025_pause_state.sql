@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE pause_state
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    component                 VARCHAR      NOT NULL,
+    paused                    BOOLEAN      NOT NULL DEFAULT FALSE,
+    reason                    VARCHAR      NOT NULL DEFAULT '',
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_pause_state_on_component ON pause_state(component) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pause_state;
+-- +goose StatementEnd
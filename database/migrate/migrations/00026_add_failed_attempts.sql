@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk
+ADD COLUMN failed_attempts SMALLINT NOT NULL DEFAULT 0;
+
+ALTER TABLE batch
+ADD COLUMN failed_attempts SMALLINT NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS chunk
+DROP COLUMN failed_attempts;
+
+ALTER TABLE IF EXISTS batch
+DROP COLUMN failed_attempts;
+
+-- +goose StatementEnd
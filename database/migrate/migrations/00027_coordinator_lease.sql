@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE coordinator_lease
+(
+    name                      VARCHAR      PRIMARY KEY,
+    owner                     VARCHAR      NOT NULL,
+    expires_at                TIMESTAMP(0) NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS coordinator_lease;
+-- +goose StatementEnd
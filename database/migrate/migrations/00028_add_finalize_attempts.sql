@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE batch
+ADD COLUMN finalize_attempts SMALLINT NOT NULL DEFAULT 0,
+ADD COLUMN next_finalize_at TIMESTAMP(0) DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS batch
+DROP COLUMN finalize_attempts,
+DROP COLUMN next_finalize_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_task
+ADD COLUMN submit_nonce BIGINT NOT NULL DEFAULT 0;
+
+create index if not exists idx_prover_task_public_key_submit_nonce on prover_task(prover_public_key, submit_nonce) where deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+drop index if exists idx_prover_task_public_key_submit_nonce;
+
+ALTER TABLE IF EXISTS prover_task
+DROP COLUMN submit_nonce;
+
+-- +goose StatementEnd
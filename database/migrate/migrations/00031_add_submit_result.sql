@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_task
+ADD COLUMN submit_result TEXT DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS prover_task
+DROP COLUMN submit_result;
+
+-- +goose StatementEnd
@@ -0,0 +1,78 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE status_audit_log
+(
+    id              BIGSERIAL       PRIMARY KEY,
+
+    table_name      VARCHAR         NOT NULL,
+    record_key      VARCHAR         NOT NULL,
+    status_column   VARCHAR         NOT NULL,
+    from_status     INTEGER         DEFAULT NULL,
+    to_status       INTEGER         NOT NULL,
+    actor           VARCHAR         NOT NULL,
+    changed_at      TIMESTAMP(3)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    created_at      TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at      TIMESTAMP(0)    DEFAULT NULL
+);
+
+comment
+on column status_audit_log.record_key is 'hash of the batch or chunk, msg_hash of the l1 message';
+comment
+on column status_audit_log.from_status is 'NULL when the record is inserted';
+comment
+on column status_audit_log.actor is 'application_name of the db session, the db user if not set';
+
+CREATE INDEX idx_status_audit_log_table_name_record_key_changed_at
+ON status_audit_log (table_name, record_key, changed_at) WHERE deleted_at IS NULL;
+
+-- the first trigger argument is the key column of the record, the others are its status columns.
+CREATE OR REPLACE FUNCTION log_status_transition() RETURNS TRIGGER AS $$
+DECLARE
+    old_row         JSONB;
+    new_row         JSONB := to_jsonb(NEW);
+    status_column   TEXT;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+
+    FOR i IN 1 .. TG_NARGS - 1 LOOP
+        status_column := TG_ARGV[i];
+        IF old_row IS NULL OR old_row -> status_column IS DISTINCT FROM new_row -> status_column THEN
+            INSERT INTO status_audit_log (table_name, record_key, status_column, from_status, to_status, actor)
+            VALUES (TG_TABLE_NAME, new_row ->> TG_ARGV[0], status_column,
+                    (old_row ->> status_column)::INTEGER, (new_row ->> status_column)::INTEGER,
+                    COALESCE(NULLIF(current_setting('application_name', true), ''), current_user));
+        END IF;
+    END LOOP;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER batch_status_audit
+AFTER INSERT OR UPDATE OF chunk_proofs_status, proving_status, rollup_status ON batch
+FOR EACH ROW EXECUTE FUNCTION log_status_transition('hash', 'chunk_proofs_status', 'proving_status', 'rollup_status');
+
+CREATE TRIGGER chunk_status_audit
+AFTER INSERT OR UPDATE OF proving_status ON chunk
+FOR EACH ROW EXECUTE FUNCTION log_status_transition('hash', 'proving_status');
+
+CREATE TRIGGER l1_message_status_audit
+AFTER INSERT OR UPDATE OF status ON l1_message
+FOR EACH ROW EXECUTE FUNCTION log_status_transition('msg_hash', 'status');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS l1_message_status_audit ON l1_message;
+DROP TRIGGER IF EXISTS chunk_status_audit ON chunk;
+DROP TRIGGER IF EXISTS batch_status_audit ON batch;
+DROP FUNCTION IF EXISTS log_status_transition();
+DROP TABLE IF EXISTS status_audit_log;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE notifier_cursor
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    name                      VARCHAR      NOT NULL,
+    last_id                   BIGINT       NOT NULL DEFAULT 0,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on column notifier_cursor.last_id is 'id of the last status_audit_log record the notifier delivered';
+
+CREATE UNIQUE INDEX uniq_notifier_cursor_on_name ON notifier_cursor(name) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifier_cursor;
+-- +goose StatementEnd
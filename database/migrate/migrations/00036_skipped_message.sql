@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE skipped_message
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    queue_index               BIGINT       NOT NULL,
+    msg_hash                  VARCHAR      DEFAULT NULL,
+    l2_block_number           BIGINT       NOT NULL,
+    layer2_hash               VARCHAR      DEFAULT NULL,
+    reason                    VARCHAR      NOT NULL,
+
+    replay_tx_hash            VARCHAR      DEFAULT NULL,
+    replay_gas_limit          BIGINT       DEFAULT NULL,
+    replayed_at               TIMESTAMP(0) DEFAULT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on column skipped_message.l2_block_number is 'the L2 block which skipped the message, or whose transaction failed relaying it';
+comment
+on column skipped_message.layer2_hash is 'the L2 transaction which failed relaying the message, NULL if it was skipped';
+comment
+on column skipped_message.replay_tx_hash is 'the last L1 replayMessage transaction sent for the message';
+
+CREATE UNIQUE INDEX uniq_skipped_message_on_queue_index ON skipped_message(queue_index) WHERE deleted_at IS NULL;
+CREATE INDEX idx_skipped_message_on_l2_block_number ON skipped_message(l2_block_number) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS skipped_message;
+-- +goose StatementEnd
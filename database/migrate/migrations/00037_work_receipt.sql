@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE work_receipt
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    uuid                      VARCHAR      NOT NULL,
+    task_id                   VARCHAR      NOT NULL,
+    task_type                 SMALLINT     NOT NULL,
+    prover_public_key         VARCHAR      NOT NULL,
+    prover_name               VARCHAR      NOT NULL,
+    proof_hash                VARCHAR      NOT NULL,
+    proving_time_sec          BIGINT       NOT NULL,
+    accepted_at               TIMESTAMP(0) NOT NULL,
+    signature                 VARCHAR      NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on column work_receipt.uuid is 'uuid of the prover task whose proof was accepted';
+comment
+on column work_receipt.signature is 'coordinator signature of the receipt, see message.WorkReceipt';
+
+CREATE UNIQUE INDEX uniq_work_receipt_on_uuid ON work_receipt(uuid) WHERE deleted_at IS NULL;
+CREATE INDEX idx_work_receipt_on_prover_public_key_id ON work_receipt(prover_public_key, id) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS work_receipt;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l2_block
+ADD COLUMN transactions_compressed BYTEA DEFAULT NULL;
+
+comment
+on column l2_block.transactions_compressed is 'gzip compressed json of the transactions, the transactions column is left empty when set';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS l2_block
+DROP COLUMN transactions_compressed;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE batch_approval
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    batch_index               BIGINT       NOT NULL,
+    batch_hash                VARCHAR      NOT NULL,
+    approver                  VARCHAR      NOT NULL,
+    signature                 VARCHAR      NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on column batch_approval.signature is 'signature of the approver over the batch approval digest of the batch hash, state root and withdraw root';
+
+CREATE UNIQUE INDEX uniq_batch_approval_on_batch_hash_approver ON batch_approval(batch_hash, approver) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS batch_approval;
+-- +goose StatementEnd
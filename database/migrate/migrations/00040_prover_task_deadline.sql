@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_task
+ADD COLUMN estimated_proving_time_sec INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN proving_time_sec           INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN deadline                   TIMESTAMP(0) DEFAULT NULL;
+
+comment
+on column prover_task.estimated_proving_time_sec is 'proving time estimated from the proved chunks at the assignment, 0 if not estimated';
+comment
+on column prover_task.proving_time_sec is 'proving time of the accepted proof';
+comment
+on column prover_task.deadline is 'the task times out after it, the collection time of the task type applies when NULL';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS prover_task
+DROP COLUMN estimated_proving_time_sec,
+DROP COLUMN proving_time_sec,
+DROP COLUMN deadline;
+
+-- +goose StatementEnd
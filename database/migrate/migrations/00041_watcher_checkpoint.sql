@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE watcher_checkpoint
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    name                      VARCHAR      NOT NULL,
+    block_number              BIGINT       NOT NULL,
+    block_hash                VARCHAR      NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on column watcher_checkpoint.block_number is 'the events up to this block are processed, updated in the transaction storing them';
+
+CREATE UNIQUE INDEX uniq_watcher_checkpoint_on_name ON watcher_checkpoint(name) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watcher_checkpoint;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE archived_object
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    kind                      VARCHAR      NOT NULL,
+    object_key                VARCHAR      NOT NULL,
+    uri                       VARCHAR      NOT NULL,
+    content_hash              VARCHAR      NOT NULL,
+    size                      BIGINT       NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on column archived_object.kind is 'l2_block, chunk_proof or batch_proof';
+comment
+on column archived_object.object_key is 'the l2 block number, or the chunk or batch hash';
+comment
+on column archived_object.content_hash is 'the hex encoded sha256 of the archived object, checked when it is read back';
+
+CREATE UNIQUE INDEX uniq_archived_object_on_kind_object_key ON archived_object(kind, object_key) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS archived_object;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_block_list
+ADD COLUMN reason     VARCHAR      NOT NULL DEFAULT '',
+ADD COLUMN expires_at TIMESTAMP(0) DEFAULT NULL;
+
+comment
+on column prover_block_list.expires_at is 'the end of a temporary ban, the public key is blocked until it is removed when NULL';
+
+CREATE TABLE prover_allow_list
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    public_key                VARCHAR      NOT NULL,
+    prover_name               VARCHAR      NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_prover_allow_list_on_public_key ON prover_allow_list(public_key) WHERE deleted_at IS NULL;
+
+CREATE TABLE prover_session
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    public_key                VARCHAR      NOT NULL,
+    prover_name               VARCHAR      NOT NULL,
+    prover_version            VARCHAR      NOT NULL,
+    logged_in_at              TIMESTAMP(0) NOT NULL,
+    expires_at                TIMESTAMP(0) NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on column prover_session.expires_at is 'the expiry of the jwt token issued at the last login of the prover';
+
+CREATE UNIQUE INDEX uniq_prover_session_on_public_key ON prover_session(public_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_prover_session_on_expires_at ON prover_session(expires_at) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS prover_session;
+DROP TABLE IF EXISTS prover_allow_list;
+
+ALTER TABLE IF EXISTS prover_block_list
+DROP COLUMN reason,
+DROP COLUMN expires_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l1_message
+ADD COLUMN gas_used BIGINT DEFAULT NULL;
+
+comment
+on column l1_message.gas_used is 'the l2 gas the transaction including the message used, from its receipt';
+
+CREATE INDEX l1_message_sender_index ON l1_message (sender) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS l1_message_sender_index;
+
+ALTER TABLE IF EXISTS l1_message
+DROP COLUMN gas_used;
+
+-- +goose StatementEnd
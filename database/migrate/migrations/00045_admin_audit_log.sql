@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE admin_audit_log
+(
+    id              BIGSERIAL       PRIMARY KEY,
+
+    action          VARCHAR         NOT NULL,
+    target_type     VARCHAR         NOT NULL,
+    target_key      VARCHAR         NOT NULL,
+    operator        VARCHAR         NOT NULL DEFAULT '',
+    reason          VARCHAR         NOT NULL,
+    details         TEXT            NOT NULL DEFAULT '',
+
+    created_at      TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at      TIMESTAMP(0)    DEFAULT NULL
+);
+
+comment
+on column admin_audit_log.target_key is 'hash of the chunk or batch';
+comment
+on column admin_audit_log.operator is 'the operator given with the admin request, the admin secret is shared';
+comment
+on column admin_audit_log.details is 'json of the state of the target before the action';
+
+CREATE INDEX idx_admin_audit_log_target_key ON admin_audit_log (target_key) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS admin_audit_log;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE proof_upload_part
+(
+    id              BIGSERIAL       PRIMARY KEY,
+
+    uuid            UUID            NOT NULL,
+    part_hash       VARCHAR         NOT NULL,
+    data            BYTEA           NOT NULL,
+
+    created_at      TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at      TIMESTAMP(0)    DEFAULT NULL
+);
+
+comment
+on column proof_upload_part.uuid is 'uuid of the prover task the proof is uploaded for';
+comment
+on column proof_upload_part.part_hash is 'keccak256 hash of the data of the part';
+
+CREATE UNIQUE INDEX uk_proof_upload_part_uuid_part_hash ON proof_upload_part (uuid, part_hash) WHERE deleted_at IS NULL;
+CREATE INDEX idx_proof_upload_part_created_at ON proof_upload_part (created_at) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS proof_upload_part;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_task
+ADD COLUMN hard_fork_name VARCHAR NOT NULL DEFAULT '';
+
+comment
+on column prover_task.hard_fork_name is 'the hard fork whose circuit the task is proved with, the proof is verified with its verifier';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS prover_task
+DROP COLUMN hard_fork_name;
+
+-- +goose StatementEnd
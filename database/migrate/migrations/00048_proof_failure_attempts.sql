@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk
+ADD COLUMN panic_failed_attempts SMALLINT NOT NULL DEFAULT 0,
+ADD COLUMN no_panic_failed_attempts SMALLINT NOT NULL DEFAULT 0;
+
+ALTER TABLE batch
+ADD COLUMN panic_failed_attempts SMALLINT NOT NULL DEFAULT 0,
+ADD COLUMN no_panic_failed_attempts SMALLINT NOT NULL DEFAULT 0;
+
+comment
+on column chunk.panic_failed_attempts is 'number of proofs of the chunk failed by a prover panic';
+
+comment
+on column chunk.no_panic_failed_attempts is 'number of proofs of the chunk failed without a prover panic, i.e. deterministically';
+
+comment
+on column batch.panic_failed_attempts is 'number of proofs of the batch failed by a prover panic';
+
+comment
+on column batch.no_panic_failed_attempts is 'number of proofs of the batch failed without a prover panic, i.e. deterministically';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE IF EXISTS chunk
+DROP COLUMN panic_failed_attempts,
+DROP COLUMN no_panic_failed_attempts;
+
+ALTER TABLE IF EXISTS batch
+DROP COLUMN panic_failed_attempts,
+DROP COLUMN no_panic_failed_attempts;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk
+ADD COLUMN priority SMALLINT NOT NULL DEFAULT 1,
+ADD COLUMN deadline TIMESTAMP(0) DEFAULT NULL;
+
+ALTER TABLE batch
+ADD COLUMN priority SMALLINT NOT NULL DEFAULT 2,
+ADD COLUMN deadline TIMESTAMP(0) DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chunk_proving_status_priority ON chunk (proving_status, priority DESC, deadline, "index") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_batch_proving_status_priority ON batch (proving_status, priority DESC, deadline, "index") WHERE deleted_at IS NULL;
+
+comment
+on column chunk.priority is 'low, normal, high, the prover tasks are assigned by priority, then by deadline, then by index';
+
+comment
+on column chunk.deadline is 'when the chunk should be proven, the tasks of the same priority are assigned by earliest deadline';
+
+comment
+on column batch.priority is 'low, normal, high, the prover tasks are assigned by priority, then by deadline, then by index';
+
+comment
+on column batch.deadline is 'when the batch should be proven, the tasks of the same priority are assigned by earliest deadline';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_chunk_proving_status_priority;
+DROP INDEX IF EXISTS idx_batch_proving_status_priority;
+
+ALTER TABLE IF EXISTS chunk
+DROP COLUMN priority,
+DROP COLUMN deadline;
+
+ALTER TABLE IF EXISTS batch
+DROP COLUMN priority,
+DROP COLUMN deadline;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE l1_watcher_block
+(
+    id                        BIGSERIAL    PRIMARY KEY,
+
+    number                    BIGINT       NOT NULL,
+    hash                      VARCHAR      NOT NULL,
+
+    created_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                TIMESTAMP(0) DEFAULT NULL
+);
+
+comment
+on table l1_watcher_block is 'the latest l1 blocks processed by the l1 watcher, checked against the canonical chain to detect the reorgs';
+
+CREATE UNIQUE INDEX uniq_l1_watcher_block_on_number ON l1_watcher_block(number) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS l1_watcher_block;
+-- +goose StatementEnd
@@ -0,0 +1,382 @@
+-- +goose Up
+-- the sqlite schema mirrors the postgres schema as of the migration of the same version,
+-- a postgres migration changing the schema gets a sqlite migration of its version too.
+
+CREATE TABLE l1_message
+(
+    queue_index      BIGINT           NOT NULL,
+    msg_hash         VARCHAR          NOT NULL,
+    height           BIGINT           NOT NULL,
+    gas_limit        BIGINT           NOT NULL,
+    sender           VARCHAR          NOT NULL,
+    target           VARCHAR          NOT NULL,
+    value            VARCHAR          NOT NULL,
+    calldata         TEXT             NOT NULL,
+    layer1_hash      VARCHAR          NOT NULL,
+    layer2_hash      VARCHAR          DEFAULT NULL,
+    status           INTEGER          NOT NULL DEFAULT 1,
+    created_at       TIMESTAMP        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at       TIMESTAMP        DEFAULT NULL
+);
+
+CREATE INDEX l1_message_hash_index ON l1_message (msg_hash) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX l1_message_nonce_uindex ON l1_message (queue_index) WHERE deleted_at IS NULL;
+CREATE INDEX l1_message_height_index ON l1_message (height) WHERE deleted_at IS NULL;
+
+CREATE TABLE l1_block
+(
+    number                  BIGINT          NOT NULL,
+    hash                    VARCHAR         NOT NULL,
+    base_fee                BIGINT          NOT NULL,
+    blob_base_fee           BIGINT          DEFAULT 0,
+    oracle_status           SMALLINT        NOT NULL DEFAULT 1,
+    oracle_tx_hash          VARCHAR         DEFAULT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX l1_block_hash_uindex ON l1_block (hash) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX l1_block_number_uindex ON l1_block (number) WHERE deleted_at IS NULL;
+
+CREATE TABLE l2_block
+(
+    number                  BIGINT          NOT NULL,
+    hash                    VARCHAR         NOT NULL,
+    parent_hash             VARCHAR         NOT NULL,
+    header                  TEXT            NOT NULL,
+    transactions            TEXT            NOT NULL,
+    withdraw_root           VARCHAR         NOT NULL,
+    state_root              VARCHAR         NOT NULL,
+    tx_num                  INTEGER         NOT NULL,
+    gas_used                BIGINT          NOT NULL,
+    block_timestamp         NUMERIC         NOT NULL,
+    row_consumption         TEXT            NOT NULL,
+    skip_reason             VARCHAR         DEFAULT NULL,
+    chunk_hash              VARCHAR         DEFAULT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX l2_block_hash_uindex ON l2_block (hash) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX l2_block_number_uindex ON l2_block (number) WHERE deleted_at IS NULL;
+CREATE INDEX l2_block_chunk_hash_index ON l2_block (chunk_hash) WHERE deleted_at IS NULL;
+CREATE INDEX l2_block_skip_reason_index ON l2_block (number) WHERE skip_reason IS NOT NULL AND deleted_at IS NULL;
+
+CREATE TABLE chunk
+(
+    "index"                           BIGINT          NOT NULL,
+    hash                              VARCHAR         NOT NULL,
+    start_block_number                BIGINT          NOT NULL,
+    start_block_hash                  VARCHAR         NOT NULL,
+    end_block_number                  BIGINT          NOT NULL,
+    end_block_hash                    VARCHAR         NOT NULL,
+    total_l1_messages_popped_before   BIGINT          NOT NULL,
+    total_l1_messages_popped_in_chunk INTEGER         NOT NULL,
+    start_block_time                  BIGINT          NOT NULL,
+    parent_chunk_hash                 VARCHAR         NOT NULL,
+    state_root                        VARCHAR         NOT NULL,
+    parent_chunk_state_root           VARCHAR         NOT NULL,
+    withdraw_root                     VARCHAR         NOT NULL,
+    crc_max                           INTEGER         DEFAULT 0,
+    blob_size                         INTEGER         DEFAULT 0,
+    proving_status                    SMALLINT        NOT NULL DEFAULT 1,
+    proof                             BLOB            DEFAULT NULL,
+    proof_hash                        VARCHAR         DEFAULT NULL,
+    proof_uri                         VARCHAR         DEFAULT NULL,
+    prover_assigned_at                TIMESTAMP       DEFAULT NULL,
+    proved_at                         TIMESTAMP       DEFAULT NULL,
+    proof_time_sec                    INTEGER         DEFAULT NULL,
+    total_attempts                    SMALLINT        NOT NULL DEFAULT 0,
+    active_attempts                   SMALLINT        NOT NULL DEFAULT 0,
+    failed_attempts                   SMALLINT        NOT NULL DEFAULT 0,
+    batch_hash                        VARCHAR         DEFAULT NULL,
+    total_l2_tx_gas                   BIGINT          NOT NULL,
+    total_l2_tx_num                   INTEGER         NOT NULL,
+    total_l1_commit_calldata_size     INTEGER         NOT NULL,
+    total_l1_commit_gas               BIGINT          NOT NULL,
+    created_at                        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                        TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                        TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX chunk_index_uindex ON chunk ("index") WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX chunk_hash_uindex ON chunk (hash) WHERE deleted_at IS NULL;
+CREATE INDEX batch_hash_index ON chunk (batch_hash) WHERE deleted_at IS NULL;
+CREATE INDEX idx_chunk_proving_status_index ON chunk (proving_status, "index") WHERE deleted_at IS NULL;
+
+CREATE TABLE batch
+(
+    "index"                         BIGINT          NOT NULL,
+    hash                            VARCHAR         NOT NULL,
+    data_hash                       VARCHAR         DEFAULT '',
+    start_chunk_index               BIGINT          NOT NULL,
+    start_chunk_hash                VARCHAR         NOT NULL,
+    end_chunk_index                 BIGINT          NOT NULL,
+    end_chunk_hash                  VARCHAR         NOT NULL,
+    state_root                      VARCHAR         NOT NULL,
+    withdraw_root                   VARCHAR         NOT NULL,
+    parent_batch_hash               VARCHAR         NOT NULL,
+    batch_header                    BLOB            NOT NULL,
+    blob_data_proof                 BLOB            DEFAULT NULL,
+    blob_size                       INTEGER         DEFAULT 0,
+    chunk_proofs_status             SMALLINT        NOT NULL DEFAULT 1,
+    proving_status                  SMALLINT        NOT NULL DEFAULT 1,
+    proof                           BLOB            DEFAULT NULL,
+    proof_hash                      VARCHAR         DEFAULT NULL,
+    proof_uri                       VARCHAR         DEFAULT NULL,
+    prover_assigned_at              TIMESTAMP       DEFAULT NULL,
+    proved_at                       TIMESTAMP       DEFAULT NULL,
+    proof_time_sec                  INTEGER         DEFAULT NULL,
+    total_attempts                  SMALLINT        NOT NULL DEFAULT 0,
+    active_attempts                 SMALLINT        NOT NULL DEFAULT 0,
+    failed_attempts                 SMALLINT        NOT NULL DEFAULT 0,
+    rollup_status                   SMALLINT        NOT NULL DEFAULT 1,
+    commit_tx_hash                  VARCHAR         DEFAULT NULL,
+    committed_at                    TIMESTAMP       DEFAULT NULL,
+    finalize_tx_hash                VARCHAR         DEFAULT NULL,
+    finalized_at                    TIMESTAMP       DEFAULT NULL,
+    finalize_attempts               SMALLINT        NOT NULL DEFAULT 0,
+    next_finalize_at                TIMESTAMP       DEFAULT NULL,
+    revert_reason                   VARCHAR         DEFAULT NULL,
+    oracle_status                   SMALLINT        NOT NULL DEFAULT 1,
+    oracle_tx_hash                  VARCHAR         DEFAULT NULL,
+    total_l1_commit_gas             BIGINT          NOT NULL DEFAULT 0,
+    total_l1_commit_calldata_size   INTEGER         NOT NULL DEFAULT 0,
+    created_at                      TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                      TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                      TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX batch_index_uindex ON batch ("index") WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX batch_hash_uindex ON batch (hash) WHERE deleted_at IS NULL;
+CREATE INDEX idx_batch_proving_status_index ON batch (proving_status, chunk_proofs_status, "index") WHERE deleted_at IS NULL;
+
+-- DECIMAL has a numeric affinity in sqlite and loses the precision of the rewards, TEXT keeps them exact.
+CREATE TABLE prover_task
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    uuid                    VARCHAR         NOT NULL UNIQUE DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' ||
+                                substr(hex(randomblob(2)), 2) || '-' || substr('89ab', 1 + abs(random()) % 4, 1) ||
+                                substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6)))),
+    prover_public_key       VARCHAR         NOT NULL,
+    prover_name             VARCHAR         NOT NULL,
+    prover_version          VARCHAR         NOT NULL,
+    task_id                 VARCHAR         NOT NULL,
+    task_type               SMALLINT        NOT NULL DEFAULT 0,
+    proving_status          SMALLINT        NOT NULL DEFAULT 0,
+    failure_type            SMALLINT        NOT NULL DEFAULT 0,
+    reward                  TEXT            NOT NULL DEFAULT '0',
+    proof                   BLOB            DEFAULT NULL,
+    assigned_at             TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    submit_nonce            BIGINT          NOT NULL DEFAULT 0,
+    submit_result           TEXT            DEFAULT NULL,
+    progress_stage          SMALLINT        NOT NULL DEFAULT 0,
+    progress_percent        SMALLINT        NOT NULL DEFAULT 0,
+    progress_reported_at    TIMESTAMP       DEFAULT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE INDEX idx_uuid ON prover_task (uuid) WHERE deleted_at IS NULL;
+CREATE INDEX idx_publickey_proving_status ON prover_task (prover_public_key, proving_status, deleted_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_prover_task_created_at ON prover_task (created_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_prover_task_task_id ON prover_task (task_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_prover_task_public_key_submit_nonce ON prover_task (prover_public_key, submit_nonce) WHERE deleted_at IS NULL;
+
+CREATE TABLE challenge
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    challenge               VARCHAR         NOT NULL UNIQUE,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE TABLE pending_transaction
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    context_id              VARCHAR         NOT NULL,
+    hash                    VARCHAR         NOT NULL,
+    status                  SMALLINT        NOT NULL,
+    rlp_encoding            BLOB            NOT NULL,
+    chain_id                BIGINT          NOT NULL,
+    type                    SMALLINT        NOT NULL,
+    gas_tip_cap             BIGINT          NOT NULL,
+    gas_fee_cap             BIGINT          NOT NULL,
+    gas_limit               BIGINT          NOT NULL,
+    nonce                   BIGINT          NOT NULL,
+    submit_block_number     BIGINT          NOT NULL,
+    sender_name             VARCHAR         NOT NULL,
+    sender_service          VARCHAR         NOT NULL,
+    sender_address          VARCHAR         NOT NULL,
+    sender_type             SMALLINT        NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX unique_idx_pending_transaction_on_hash ON pending_transaction (hash);
+CREATE INDEX idx_pending_transaction_on_sender_type_status_nonce_gas_fee_cap ON pending_transaction (sender_type, status, nonce, gas_fee_cap);
+CREATE INDEX idx_pending_transaction_on_sender_address_nonce ON pending_transaction (sender_address, nonce);
+
+CREATE TABLE prover_block_list
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    public_key              VARCHAR         NOT NULL,
+    prover_name             VARCHAR         NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_prover_block_list_on_public_key ON prover_block_list (public_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_prover_block_list_on_prover_name ON prover_block_list (prover_name);
+
+CREATE TABLE prover_score
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    public_key              VARCHAR         NOT NULL,
+    prover_name             VARCHAR         NOT NULL,
+    success_count           BIGINT          NOT NULL DEFAULT 0,
+    panic_failure_count     BIGINT          NOT NULL DEFAULT 0,
+    no_panic_failure_count  BIGINT          NOT NULL DEFAULT 0,
+    verified_failure_count  BIGINT          NOT NULL DEFAULT 0,
+    timeout_count           BIGINT          NOT NULL DEFAULT 0,
+    total_proving_time_sec  BIGINT          NOT NULL DEFAULT 0,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_prover_score_on_public_key ON prover_score (public_key) WHERE deleted_at IS NULL;
+
+CREATE TABLE pause_state
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    component               VARCHAR         NOT NULL,
+    paused                  BOOLEAN         NOT NULL DEFAULT FALSE,
+    reason                  VARCHAR         NOT NULL DEFAULT '',
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_pause_state_on_component ON pause_state (component) WHERE deleted_at IS NULL;
+
+CREATE TABLE coordinator_lease
+(
+    name                    VARCHAR         PRIMARY KEY,
+    owner                   VARCHAR         NOT NULL,
+    expires_at              TIMESTAMP       NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE status_audit_log
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    table_name              VARCHAR         NOT NULL,
+    record_key              VARCHAR         NOT NULL,
+    status_column           VARCHAR         NOT NULL,
+    from_status             INTEGER         DEFAULT NULL,
+    to_status               INTEGER         NOT NULL,
+    actor                   VARCHAR         NOT NULL,
+    changed_at              TIMESTAMP       NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE INDEX idx_status_audit_log_table_name_record_key_changed_at
+ON status_audit_log (table_name, record_key, changed_at) WHERE deleted_at IS NULL;
+
+-- sqlite has no session name, the transitions are logged with the sqlite actor.
+-- +goose StatementBegin
+CREATE TRIGGER batch_status_audit_insert AFTER INSERT ON batch
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, to_status, actor) VALUES
+        ('batch', NEW.hash, 'chunk_proofs_status', NEW.chunk_proofs_status, 'sqlite'),
+        ('batch', NEW.hash, 'proving_status', NEW.proving_status, 'sqlite'),
+        ('batch', NEW.hash, 'rollup_status', NEW.rollup_status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER batch_chunk_proofs_status_audit AFTER UPDATE OF chunk_proofs_status ON batch
+WHEN OLD.chunk_proofs_status IS NOT NEW.chunk_proofs_status
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, from_status, to_status, actor)
+    VALUES ('batch', NEW.hash, 'chunk_proofs_status', OLD.chunk_proofs_status, NEW.chunk_proofs_status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER batch_proving_status_audit AFTER UPDATE OF proving_status ON batch
+WHEN OLD.proving_status IS NOT NEW.proving_status
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, from_status, to_status, actor)
+    VALUES ('batch', NEW.hash, 'proving_status', OLD.proving_status, NEW.proving_status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER batch_rollup_status_audit AFTER UPDATE OF rollup_status ON batch
+WHEN OLD.rollup_status IS NOT NEW.rollup_status
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, from_status, to_status, actor)
+    VALUES ('batch', NEW.hash, 'rollup_status', OLD.rollup_status, NEW.rollup_status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER chunk_status_audit_insert AFTER INSERT ON chunk
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, to_status, actor)
+    VALUES ('chunk', NEW.hash, 'proving_status', NEW.proving_status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER chunk_proving_status_audit AFTER UPDATE OF proving_status ON chunk
+WHEN OLD.proving_status IS NOT NEW.proving_status
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, from_status, to_status, actor)
+    VALUES ('chunk', NEW.hash, 'proving_status', OLD.proving_status, NEW.proving_status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER l1_message_status_audit_insert AFTER INSERT ON l1_message
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, to_status, actor)
+    VALUES ('l1_message', NEW.msg_hash, 'status', NEW.status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER l1_message_status_audit AFTER UPDATE OF status ON l1_message
+WHEN OLD.status IS NOT NEW.status
+BEGIN
+    INSERT INTO status_audit_log (table_name, record_key, status_column, from_status, to_status, actor)
+    VALUES ('l1_message', NEW.msg_hash, 'status', OLD.status, NEW.status, 'sqlite');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS status_audit_log;
+DROP TABLE IF EXISTS coordinator_lease;
+DROP TABLE IF EXISTS pause_state;
+DROP TABLE IF EXISTS prover_score;
+DROP TABLE IF EXISTS prover_block_list;
+DROP TABLE IF EXISTS pending_transaction;
+DROP TABLE IF EXISTS challenge;
+DROP TABLE IF EXISTS prover_task;
+DROP TABLE IF EXISTS batch;
+DROP TABLE IF EXISTS chunk;
+DROP TABLE IF EXISTS l2_block;
+DROP TABLE IF EXISTS l1_block;
+DROP TABLE IF EXISTS l1_message;
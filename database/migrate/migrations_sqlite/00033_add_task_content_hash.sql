@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk ADD COLUMN task_content_hash VARCHAR DEFAULT NULL;
+
+ALTER TABLE batch ADD COLUMN task_content_hash VARCHAR DEFAULT NULL;
+
+CREATE INDEX idx_chunk_task_content_hash ON chunk (task_content_hash) WHERE task_content_hash IS NOT NULL;
+CREATE INDEX idx_batch_task_content_hash ON batch (task_content_hash) WHERE task_content_hash IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_chunk_task_content_hash;
+DROP INDEX IF EXISTS idx_batch_task_content_hash;
+
+ALTER TABLE chunk DROP COLUMN task_content_hash;
+
+ALTER TABLE batch DROP COLUMN task_content_hash;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE notifier_cursor
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    name                    VARCHAR         NOT NULL,
+    last_id                 BIGINT          NOT NULL DEFAULT 0,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_notifier_cursor_on_name ON notifier_cursor (name) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifier_cursor;
+-- +goose StatementEnd
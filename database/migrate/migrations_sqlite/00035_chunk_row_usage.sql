@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE chunk_row_usage
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    chunk_index             BIGINT          NOT NULL,
+    chunk_hash              VARCHAR         NOT NULL,
+    start_block_number      BIGINT          NOT NULL,
+    end_block_number        BIGINT          NOT NULL,
+    sub_circuit             VARCHAR         NOT NULL,
+    row_number              BIGINT          NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_chunk_row_usage_on_chunk_hash_sub_circuit ON chunk_row_usage (chunk_hash, sub_circuit) WHERE deleted_at IS NULL;
+CREATE INDEX idx_chunk_row_usage_on_chunk_index ON chunk_row_usage (chunk_index) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS chunk_row_usage;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE skipped_message
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    queue_index             BIGINT          NOT NULL,
+    msg_hash                VARCHAR         DEFAULT NULL,
+    l2_block_number         BIGINT          NOT NULL,
+    layer2_hash             VARCHAR         DEFAULT NULL,
+    reason                  VARCHAR         NOT NULL,
+    replay_tx_hash          VARCHAR         DEFAULT NULL,
+    replay_gas_limit        BIGINT          DEFAULT NULL,
+    replayed_at             TIMESTAMP       DEFAULT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_skipped_message_on_queue_index ON skipped_message (queue_index) WHERE deleted_at IS NULL;
+CREATE INDEX idx_skipped_message_on_l2_block_number ON skipped_message (l2_block_number) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS skipped_message;
+-- +goose StatementEnd
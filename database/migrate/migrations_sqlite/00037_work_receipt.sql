@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE work_receipt
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    uuid                    VARCHAR         NOT NULL,
+    task_id                 VARCHAR         NOT NULL,
+    task_type               SMALLINT        NOT NULL,
+    prover_public_key       VARCHAR         NOT NULL,
+    prover_name             VARCHAR         NOT NULL,
+    proof_hash              VARCHAR         NOT NULL,
+    proving_time_sec        BIGINT          NOT NULL,
+    accepted_at             TIMESTAMP       NOT NULL,
+    signature               VARCHAR         NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_work_receipt_on_uuid ON work_receipt (uuid) WHERE deleted_at IS NULL;
+CREATE INDEX idx_work_receipt_on_prover_public_key_id ON work_receipt (prover_public_key, id) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS work_receipt;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE l2_block ADD COLUMN transactions_compressed BLOB DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE l2_block DROP COLUMN transactions_compressed;
+
+-- +goose StatementEnd
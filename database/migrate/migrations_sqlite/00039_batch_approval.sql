@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE batch_approval
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    batch_index             BIGINT          NOT NULL,
+    batch_hash              VARCHAR         NOT NULL,
+    approver                VARCHAR         NOT NULL,
+    signature               VARCHAR         NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_batch_approval_on_batch_hash_approver ON batch_approval (batch_hash, approver) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS batch_approval;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_task ADD COLUMN estimated_proving_time_sec INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE prover_task ADD COLUMN proving_time_sec INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE prover_task ADD COLUMN deadline TIMESTAMP DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE prover_task DROP COLUMN estimated_proving_time_sec;
+ALTER TABLE prover_task DROP COLUMN proving_time_sec;
+ALTER TABLE prover_task DROP COLUMN deadline;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE watcher_checkpoint
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    name                    VARCHAR         NOT NULL,
+    block_number            BIGINT          NOT NULL,
+    block_hash              VARCHAR         NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_watcher_checkpoint_on_name ON watcher_checkpoint (name) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS watcher_checkpoint;
+-- +goose StatementEnd
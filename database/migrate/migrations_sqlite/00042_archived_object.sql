@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE archived_object
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    kind                    VARCHAR         NOT NULL,
+    object_key              VARCHAR         NOT NULL,
+    uri                     VARCHAR         NOT NULL,
+    content_hash            VARCHAR         NOT NULL,
+    size                    BIGINT          NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_archived_object_on_kind_object_key ON archived_object (kind, object_key) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS archived_object;
+-- +goose StatementEnd
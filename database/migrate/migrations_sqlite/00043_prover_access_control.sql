@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_block_list ADD COLUMN reason VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE prover_block_list ADD COLUMN expires_at TIMESTAMP DEFAULT NULL;
+
+CREATE TABLE prover_allow_list
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    public_key              VARCHAR         NOT NULL,
+    prover_name             VARCHAR         NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_prover_allow_list_on_public_key ON prover_allow_list (public_key) WHERE deleted_at IS NULL;
+
+CREATE TABLE prover_session
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    public_key              VARCHAR         NOT NULL,
+    prover_name             VARCHAR         NOT NULL,
+    prover_version          VARCHAR         NOT NULL,
+    logged_in_at            TIMESTAMP       NOT NULL,
+    expires_at              TIMESTAMP       NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uniq_prover_session_on_public_key ON prover_session (public_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_prover_session_on_expires_at ON prover_session (expires_at) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS prover_session;
+DROP TABLE IF EXISTS prover_allow_list;
+
+ALTER TABLE prover_block_list DROP COLUMN reason;
+ALTER TABLE prover_block_list DROP COLUMN expires_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE admin_audit_log
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    action                  VARCHAR         NOT NULL,
+    target_type             VARCHAR         NOT NULL,
+    target_key              VARCHAR         NOT NULL,
+    operator                VARCHAR         NOT NULL DEFAULT '',
+    reason                  VARCHAR         NOT NULL,
+    details                 TEXT            NOT NULL DEFAULT '',
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE INDEX idx_admin_audit_log_target_key ON admin_audit_log (target_key) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS admin_audit_log;
+
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE proof_upload_part
+(
+    id                      INTEGER         PRIMARY KEY AUTOINCREMENT,
+    uuid                    VARCHAR         NOT NULL,
+    part_hash               VARCHAR         NOT NULL,
+    data                    BLOB            NOT NULL,
+    created_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP       DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX uk_proof_upload_part_uuid_part_hash ON proof_upload_part (uuid, part_hash) WHERE deleted_at IS NULL;
+CREATE INDEX idx_proof_upload_part_created_at ON proof_upload_part (created_at) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS proof_upload_part;
+
+-- +goose StatementEnd
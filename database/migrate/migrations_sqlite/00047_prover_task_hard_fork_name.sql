@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE prover_task ADD COLUMN hard_fork_name VARCHAR NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE prover_task DROP COLUMN hard_fork_name;
+
+-- +goose StatementEnd
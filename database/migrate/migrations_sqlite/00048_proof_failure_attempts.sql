@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk ADD COLUMN panic_failed_attempts SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE chunk ADD COLUMN no_panic_failed_attempts SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE batch ADD COLUMN panic_failed_attempts SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE batch ADD COLUMN no_panic_failed_attempts SMALLINT NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE batch DROP COLUMN no_panic_failed_attempts;
+ALTER TABLE batch DROP COLUMN panic_failed_attempts;
+ALTER TABLE chunk DROP COLUMN no_panic_failed_attempts;
+ALTER TABLE chunk DROP COLUMN panic_failed_attempts;
+
+-- +goose StatementEnd
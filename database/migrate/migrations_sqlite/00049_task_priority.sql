@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chunk ADD COLUMN priority SMALLINT NOT NULL DEFAULT 1;
+ALTER TABLE chunk ADD COLUMN deadline TIMESTAMP DEFAULT NULL;
+ALTER TABLE batch ADD COLUMN priority SMALLINT NOT NULL DEFAULT 2;
+ALTER TABLE batch ADD COLUMN deadline TIMESTAMP DEFAULT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE batch DROP COLUMN deadline;
+ALTER TABLE batch DROP COLUMN priority;
+ALTER TABLE chunk DROP COLUMN deadline;
+ALTER TABLE chunk DROP COLUMN priority;
+
+-- +goose StatementEnd
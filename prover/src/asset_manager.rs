@@ -0,0 +1,237 @@
+use anyhow::{bail, Context, Result};
+use reqwest::Url;
+use sha2::{Digest, Sha256};
+use std::{
+    fs::{self, File},
+    io::{self, Write},
+    path::{Path, PathBuf},
+    time::{Duration, Instant},
+};
+use tokio::runtime::Runtime;
+
+use crate::{
+    config::CircuitAssetsConfig,
+    coordinator_client::types::{CircuitAssetFile, CircuitAssetsRelease},
+};
+
+// a circuit assets release whose files are all downloaded and verified.
+#[derive(Debug, Clone)]
+pub struct AssetsRelease {
+    pub hard_fork_name: String,
+    pub circuit_version: String,
+    pub upgrade_height: u64,
+    dir: PathBuf,
+}
+
+impl AssetsRelease {
+    pub fn params_path(&self) -> String {
+        self.dir.join("params").to_string_lossy().into_owned()
+    }
+
+    pub fn assets_path(&self) -> String {
+        self.dir.join("assets").to_string_lossy().into_owned()
+    }
+}
+
+pub struct AssetManager {
+    assets_dir: PathBuf,
+    refresh_interval: Duration,
+    last_sync: Option<Instant>,
+    releases: Vec<AssetsRelease>,
+    client: reqwest::Client,
+    rt: Runtime,
+}
+
+impl AssetManager {
+    pub fn new(config: &CircuitAssetsConfig) -> Result<Self> {
+        let rt = tokio::runtime::Builder::new_current_thread()
+            .enable_all()
+            .build()?;
+
+        Ok(Self {
+            assets_dir: PathBuf::from(&config.assets_dir),
+            refresh_interval: Duration::from_secs(config.refresh_interval_sec),
+            last_sync: None,
+            releases: vec![],
+            client: reqwest::Client::new(),
+            rt,
+        })
+    }
+
+    pub fn sync_due(&self) -> bool {
+        self.last_sync
+            .map_or(true, |t| t.elapsed() >= self.refresh_interval)
+    }
+
+    // sync downloads the missing files of the published releases and verifies their digests, a
+    // release is only used once all its files are verified. a failed release is retried at the
+    // next sync.
+    pub fn sync(&mut self, published: Vec<CircuitAssetsRelease>) {
+        self.last_sync = Some(Instant::now());
+        for release in published {
+            if self
+                .releases
+                .iter()
+                .any(|r| r.circuit_version == release.circuit_version)
+            {
+                continue;
+            }
+            match self.fetch_release(&release) {
+                Ok(r) => {
+                    log::info!(
+                        "circuit assets {} of {} ready, upgrade height: {}",
+                        r.circuit_version,
+                        r.hard_fork_name,
+                        r.upgrade_height
+                    );
+                    self.releases.push(r);
+                }
+                Err(e) => log::error!(
+                    "failed to fetch circuit assets {}: {:#}",
+                    release.circuit_version,
+                    e
+                ),
+            }
+        }
+    }
+
+    // release_for returns the release of the hard fork with the highest upgrade height not above
+    // the task height, None if the task is below every upgrade height.
+    pub fn release_for(&self, hard_fork_name: &str, task_height: u64) -> Option<&AssetsRelease> {
+        self.releases
+            .iter()
+            .filter(|r| r.hard_fork_name == hard_fork_name && r.upgrade_height <= task_height)
+            .max_by_key(|r| r.upgrade_height)
+    }
+
+    fn fetch_release(&self, release: &CircuitAssetsRelease) -> Result<AssetsRelease> {
+        let base_url = Url::parse(&format!("{}/", release.base_url.trim_end_matches('/')))?;
+        if base_url.scheme() != "https" {
+            bail!("base url must be https, got {}", release.base_url)
+        }
+        if release.circuit_version.is_empty() || release.circuit_version.contains(['/', '\\']) {
+            bail!("invalid circuit version {:?}", release.circuit_version)
+        }
+
+        let dir = self.assets_dir.join(&release.circuit_version);
+        for file in &release.files {
+            if file.path.split('/').any(|p| p == ".." || p.is_empty()) {
+                bail!("invalid file path {:?}", file.path)
+            }
+            let path = dir.join(&file.path);
+            if path.exists() && sha256_file(&path)? == file.sha256.to_lowercase() {
+                continue;
+            }
+            self.download(&base_url.join(&file.path)?, &path, file)
+                .with_context(|| format!("failed to download {}", file.path))?;
+        }
+
+        Ok(AssetsRelease {
+            hard_fork_name: release.hard_fork_name.clone(),
+            circuit_version: release.circuit_version.clone(),
+            upgrade_height: release.upgrade_height,
+            dir,
+        })
+    }
+
+    // download streams the file to a temporary path and only moves it in place once its digest
+    // matches.
+    fn download(&self, url: &Url, path: &Path, file: &CircuitAssetFile) -> Result<()> {
+        log::info!("downloading circuit asset {url}");
+        if let Some(parent) = path.parent() {
+            fs::create_dir_all(parent)?;
+        }
+        let tmp_path = PathBuf::from(format!("{}.download", path.display()));
+
+        let digest = self.rt.block_on(async {
+            let mut response = self.client.get(url.clone()).send().await?;
+            if !response.status().is_success() {
+                bail!("status not ok: {}", response.status())
+            }
+            let mut out = File::create(&tmp_path)?;
+            let mut hasher = Sha256::new();
+            while let Some(chunk) = response.chunk().await? {
+                hasher.update(&chunk);
+                out.write_all(&chunk)?;
+            }
+            out.sync_all()?;
+            Ok::<_, anyhow::Error>(hex::encode(hasher.finalize()))
+        })?;
+
+        if digest != file.sha256.to_lowercase() {
+            fs::remove_file(&tmp_path)?;
+            bail!("sha256 mismatch, expected {}, got {digest}", file.sha256)
+        }
+        fs::rename(&tmp_path, path)?;
+        Ok(())
+    }
+}
+
+fn sha256_file(path: &Path) -> Result<String> {
+    let mut hasher = Sha256::new();
+    io::copy(&mut File::open(path)?, &mut hasher)?;
+    Ok(hex::encode(hasher.finalize()))
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn release(hard_fork_name: &str, circuit_version: &str, upgrade_height: u64) -> AssetsRelease {
+        AssetsRelease {
+            hard_fork_name: hard_fork_name.to_string(),
+            circuit_version: circuit_version.to_string(),
+            upgrade_height,
+            dir: PathBuf::from(circuit_version),
+        }
+    }
+
+    #[test]
+    fn test_release_for() {
+        let mut manager = AssetManager::new(&CircuitAssetsConfig {
+            assets_dir: "assets".to_string(),
+            refresh_interval_sec: 600,
+        })
+        .unwrap();
+        manager.releases = vec![
+            release("curie", "v0.11.4", 100),
+            release("curie", "v0.11.5", 200),
+            release("bernoulli", "v0.10.4", 50),
+        ];
+
+        assert!(manager.release_for("curie", 99).is_none());
+        assert_eq!(
+            manager.release_for("curie", 100).unwrap().circuit_version,
+            "v0.11.4"
+        );
+        assert_eq!(
+            manager.release_for("curie", 300).unwrap().circuit_version,
+            "v0.11.5"
+        );
+        assert_eq!(
+            manager
+                .release_for("bernoulli", 300)
+                .unwrap()
+                .circuit_version,
+            "v0.10.4"
+        );
+        assert!(manager.release_for("darwin", 300).is_none());
+    }
+
+    #[test]
+    fn test_fetch_release_rejects_http() {
+        let manager = AssetManager::new(&CircuitAssetsConfig {
+            assets_dir: "assets".to_string(),
+            refresh_interval_sec: 600,
+        })
+        .unwrap();
+        let published = CircuitAssetsRelease {
+            hard_fork_name: "curie".to_string(),
+            circuit_version: "v0.11.4".to_string(),
+            upgrade_height: 100,
+            base_url: "http://assets.example.com".to_string(),
+            files: vec![],
+        };
+        assert!(manager.fetch_release(&published).is_err());
+    }
+}
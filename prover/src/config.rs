@@ -2,7 +2,10 @@ use anyhow::{bail, Result};
 use serde::{Deserialize, Serialize};
 use std::fs::File;
 
-use crate::types::ProofType;
+use crate::{
+    coordinator_client::types::{HardwareInfo, MaxConcurrentTasks},
+    types::ProofType,
+};
 
 #[derive(Debug, Serialize, Deserialize)]
 pub struct CircuitConfig {
@@ -17,6 +20,50 @@ pub struct CoordinatorConfig {
     pub retry_count: u32,
     pub retry_wait_time_sec: u64,
     pub connection_timeout_sec: u64,
+    // the proofs larger than it are uploaded in parts of this size before they're submitted, so an
+    // interrupted upload resumes with the parts not received yet. it must not exceed the
+    // max_part_size of the coordinator, 0 submits the proofs at once.
+    #[serde(default)]
+    pub proof_upload_part_size: usize,
+    // the address of the task signing key of the coordinator, the tasks not signed by it are
+    // rejected before they're proved, e.g. forged by a proxy. unset accepts any task.
+    #[serde(default)]
+    pub task_signer: Option<String>,
+}
+
+#[derive(Debug, Serialize, Deserialize)]
+pub struct CircuitAssetsConfig {
+    // the releases published by the coordinator are downloaded under
+    // <assets_dir>/<circuit_version>.
+    pub assets_dir: String,
+    #[serde(default = "default_assets_refresh_interval_sec")]
+    pub refresh_interval_sec: u64,
+}
+
+fn default_assets_refresh_interval_sec() -> u64 {
+    600
+}
+
+#[derive(Debug, Serialize, Deserialize)]
+pub struct ProofCacheConfig {
+    #[serde(default = "default_proof_cache_max_entries")]
+    pub max_entries: usize,
+    #[serde(default = "default_proof_cache_ttl_sec")]
+    pub ttl_sec: u64,
+}
+
+fn default_proof_cache_max_entries() -> usize {
+    16
+}
+
+fn default_proof_cache_ttl_sec() -> u64 {
+    86400
+}
+
+#[derive(Debug, Serialize, Deserialize)]
+pub struct HealthConfig {
+    // e.g. "0.0.0.0:8090", serves /healthz and /readyz for the kubernetes probes.
+    pub listen_addr: String,
 }
 
 #[derive(Debug, Serialize, Deserialize)]
@@ -36,6 +83,22 @@ pub struct Config {
     pub high_version_circuit: CircuitConfig,
     pub coordinator: CoordinatorConfig,
     pub l2geth: Option<L2GethConfig>,
+    pub hardware: Option<HardwareInfo>,
+    // the max number of tasks of its proof type the prover runs at once. The prover proves one task
+    // at a time, so a higher limit is capped to one, and it doesn't start if the limit is zero. The
+    // capped limit is advertised to the coordinator, which assigns up to that many tasks at once.
+    pub max_concurrent_tasks: Option<MaxConcurrentTasks>,
+    // skips the real proving and submits deterministic dummy proofs, for e2e tests against a mock
+    // verifier coordinator.
+    #[serde(default)]
+    pub mock_mode: bool,
+    // downloads the circuit assets releases published by the coordinator and switches to them at
+    // their upgrade height, the circuits above are used for the tasks below every upgrade height.
+    pub circuit_assets: Option<CircuitAssetsConfig>,
+    // keeps the recent proofs in the db, a task reassigned after it was proved, e.g. after a
+    // reconnect, is submitted with its cached proof instead of being proved again.
+    pub proof_cache: Option<ProofCacheConfig>,
+    pub health: Option<HealthConfig>,
 }
 
 impl Config {
@@ -50,6 +113,27 @@ impl Config {
         let file = File::open(file_name)?;
         Config::from_reader(&file)
     }
+
+    // the limit advertised at login: the one of the proof type of the prover, capped to the one
+    // task the task processor proves at a time.
+    pub fn advertised_max_concurrent_tasks(&self) -> Option<MaxConcurrentTasks> {
+        let limit = self
+            .max_concurrent_tasks
+            .as_ref()?
+            .limit(self.proof_type)?
+            .min(1);
+        match self.proof_type {
+            ProofType::Chunk => Some(MaxConcurrentTasks {
+                chunk: Some(limit),
+                batch: None,
+            }),
+            ProofType::Batch => Some(MaxConcurrentTasks {
+                chunk: None,
+                batch: Some(limit),
+            }),
+            ProofType::Undefined => None,
+        }
+    }
 }
 
 static SCROLL_PROVER_ASSETS_DIR_ENV_NAME: &str = "SCROLL_PROVER_ASSETS_DIR";
@@ -88,6 +172,11 @@ impl AssetsDirEnvConfig {
         }
     }
 
+    pub fn enable_dir(dir: &str) {
+        log::info!("set env {SCROLL_PROVER_ASSETS_DIR_ENV_NAME} to {dir}");
+        std::env::set_var(SCROLL_PROVER_ASSETS_DIR_ENV_NAME, dir);
+    }
+
     pub fn enable_second() {
         unsafe {
             log::info!(
@@ -4,7 +4,7 @@ pub mod listener;
 pub mod types;
 
 use anyhow::{bail, Context, Ok, Result};
-use std::rc::Rc;
+use std::{rc::Rc, sync::Arc};
 
 use api::Api;
 use errors::*;
@@ -12,7 +12,7 @@ use listener::Listener;
 use tokio::runtime::Runtime;
 use types::*;
 
-use crate::{config::Config, key_signer::KeySigner};
+use crate::{config::Config, health::HealthState, key_signer::KeySigner};
 
 pub struct CoordinatorClient<'a> {
     api: Api,
@@ -21,6 +21,7 @@ pub struct CoordinatorClient<'a> {
     key_signer: Rc<KeySigner>,
     rt: Runtime,
     listener: Box<dyn Listener>,
+    health: Arc<HealthState>,
 }
 
 impl<'a> CoordinatorClient<'a> {
@@ -28,6 +29,7 @@ impl<'a> CoordinatorClient<'a> {
         config: &'a Config,
         key_signer: Rc<KeySigner>,
         listener: Box<dyn Listener>,
+        health: Arc<HealthState>,
     ) -> Result<Self> {
         let rt = tokio::runtime::Builder::new_current_thread()
             .enable_all()
@@ -46,6 +48,7 @@ impl<'a> CoordinatorClient<'a> {
             key_signer,
             rt,
             listener,
+            health,
         };
         client.login()?;
         Ok(client)
@@ -68,6 +71,8 @@ impl<'a> CoordinatorClient<'a> {
             challenge: token.clone(),
             prover_name: self.config.prover_name.clone(),
             prover_version: crate::version::get_version(),
+            hardware: self.config.hardware.clone(),
+            max_concurrent_tasks: self.config.advertised_max_concurrent_tasks(),
         };
 
         let buffer = login_message.rlp();
@@ -81,11 +86,32 @@ impl<'a> CoordinatorClient<'a> {
             bail!("login failed: {}", login_response.errmsg)
         }
         if let Some(r) = login_response.data {
+            if let Some(warning) = r.warning {
+                log::warn!("login warning: {warning}");
+            }
             token = r.token;
         } else {
             bail!("login failed: got empty token")
         }
         self.token = Some(token);
+        self.health.set_coordinator_reachable(true);
+        Ok(())
+    }
+
+    // refresh_token exchanges the current token for a new one, without signing a new challenge.
+    fn refresh_token(&mut self) -> Result<()> {
+        let token = self
+            .token
+            .as_ref()
+            .context("refresh failed: not logged in")?;
+        let refresh_response = self.rt.block_on(self.api.refresh_token(token))?;
+        if refresh_response.errcode != ErrorCode::Success {
+            bail!("refresh failed: {}", refresh_response.errmsg)
+        }
+        match refresh_response.data {
+            Some(r) => self.token = Some(r.token),
+            None => bail!("refresh failed: got empty token"),
+        }
         Ok(())
     }
 
@@ -93,11 +119,17 @@ impl<'a> CoordinatorClient<'a> {
     where
         F: FnMut(&mut Self, &R) -> Result<Response<T>>,
     {
-        let response = f(self, req)?;
+        // a response, even an error one, tells the coordinator is reachable.
+        let response = f(self, req);
+        self.health.set_coordinator_reachable(response.is_ok());
+        let response = response?;
         if response.errcode == ErrorCode::ErrJWTTokenExpired {
-            log::info!("JWT expired, attempting to re-login");
-            self.login().context("JWT expired, re-login failed")?;
-            log::info!("re-login success");
+            log::info!("JWT expired, attempting to refresh it");
+            if let Err(e) = self.refresh_token() {
+                log::info!("JWT refresh failed, attempting to re-login: {e}");
+                self.login().context("JWT expired, re-login failed")?;
+                log::info!("re-login success");
+            }
             return self.action_with_re_login(req, f);
         } else if response.errcode != ErrorCode::Success {
             bail!("action failed: {}", response.errmsg)
@@ -105,6 +137,15 @@ impl<'a> CoordinatorClient<'a> {
         Ok(response)
     }
 
+    // the circuit assets are public, no token is needed.
+    pub fn get_circuit_assets(&self) -> Result<Vec<CircuitAssetsRelease>> {
+        let response = self.rt.block_on(self.api.circuit_assets())?;
+        if response.errcode != ErrorCode::Success {
+            bail!("get circuit assets failed: {}", response.errmsg)
+        }
+        Ok(response.data.map(|d| d.releases).unwrap_or_default())
+    }
+
     fn do_get_task(&mut self, req: &GetTaskRequest) -> Result<Response<GetTaskResponseData>> {
         self.rt
             .block_on(self.api.get_task(req, self.token.as_ref().unwrap()))
@@ -131,4 +172,66 @@ impl<'a> CoordinatorClient<'a> {
     ) -> Result<Response<SubmitProofResponseData>> {
         self.action_with_re_login(req, |s, req| s.do_submit_proof(req))
     }
+
+    fn do_upload_proof_part(
+        &mut self,
+        req: &UploadProofPartRequest,
+    ) -> Result<Response<ProofUploadResponseData>> {
+        self.rt.block_on(
+            self.api
+                .upload_proof_part(req, self.token.as_ref().unwrap()),
+        )
+    }
+
+    pub fn upload_proof_part(
+        &mut self,
+        req: &UploadProofPartRequest,
+    ) -> Result<Response<ProofUploadResponseData>> {
+        self.action_with_re_login(req, |s, req| s.do_upload_proof_part(req))
+    }
+
+    fn do_get_proof_upload(
+        &mut self,
+        req: &ProofUploadRequest,
+    ) -> Result<Response<ProofUploadResponseData>> {
+        self.rt
+            .block_on(self.api.get_proof_upload(req, self.token.as_ref().unwrap()))
+    }
+
+    pub fn get_proof_upload(
+        &mut self,
+        req: &ProofUploadRequest,
+    ) -> Result<Response<ProofUploadResponseData>> {
+        self.action_with_re_login(req, |s, req| s.do_get_proof_upload(req))
+    }
+
+    fn do_report_progress(
+        &mut self,
+        req: &ReportProgressRequest,
+    ) -> Result<Response<ReportProgressResponseData>> {
+        self.rt
+            .block_on(self.api.report_progress(req, self.token.as_ref().unwrap()))
+    }
+
+    pub fn report_progress(
+        &mut self,
+        req: &ReportProgressRequest,
+    ) -> Result<Response<ReportProgressResponseData>> {
+        self.action_with_re_login(req, |s, req| s.do_report_progress(req))
+    }
+
+    fn do_ack_cancel_task(
+        &mut self,
+        req: &AckCancelTaskRequest,
+    ) -> Result<Response<AckCancelTaskResponseData>> {
+        self.rt
+            .block_on(self.api.ack_cancel_task(req, self.token.as_ref().unwrap()))
+    }
+
+    pub fn ack_cancel_task(
+        &mut self,
+        req: &AckCancelTaskRequest,
+    ) -> Result<Response<AckCancelTaskResponseData>> {
+        self.action_with_re_login(req, |s, req| s.do_ack_cancel_task(req))
+    }
 }
@@ -52,6 +52,30 @@ impl Api {
         serde_json::from_str(&response_body).map_err(|e| anyhow::anyhow!(e))
     }
 
+    pub async fn circuit_assets(&self) -> Result<Response<CircuitAssetsResponseData>> {
+        let method = "/coordinator/v1/circuit_assets";
+        let url = self.build_url(method)?;
+
+        let response = self
+            .client
+            .get(url)
+            .header(CONTENT_TYPE, "application/json")
+            .timeout(self.send_timeout)
+            .send()
+            .await?;
+
+        if response.status() != http::status::StatusCode::OK {
+            bail!(
+                "[coordinator client], {method}, status not ok: {}",
+                response.status()
+            )
+        }
+
+        let response_body = response.text().await?;
+
+        serde_json::from_str(&response_body).map_err(|e| anyhow::anyhow!(e))
+    }
+
     pub async fn login(
         &self,
         req: &LoginRequest,
@@ -61,6 +85,11 @@ impl Api {
         self.post_with_token(method, req, token).await
     }
 
+    pub async fn refresh_token(&self, token: &String) -> Result<Response<LoginResponseData>> {
+        let method = "/coordinator/v1/refresh_token";
+        self.post_with_token(method, &(), token).await
+    }
+
     pub async fn get_task(
         &self,
         req: &GetTaskRequest,
@@ -79,6 +108,42 @@ impl Api {
         self.post_with_token(method, req, token).await
     }
 
+    pub async fn upload_proof_part(
+        &self,
+        req: &UploadProofPartRequest,
+        token: &String,
+    ) -> Result<Response<ProofUploadResponseData>> {
+        let method = "/coordinator/v1/upload_proof_part";
+        self.post_with_token(method, req, token).await
+    }
+
+    pub async fn get_proof_upload(
+        &self,
+        req: &ProofUploadRequest,
+        token: &String,
+    ) -> Result<Response<ProofUploadResponseData>> {
+        let method = "/coordinator/v1/get_proof_upload";
+        self.post_with_token(method, req, token).await
+    }
+
+    pub async fn report_progress(
+        &self,
+        req: &ReportProgressRequest,
+        token: &String,
+    ) -> Result<Response<ReportProgressResponseData>> {
+        let method = "/coordinator/v1/report_progress";
+        self.post_with_token(method, req, token).await
+    }
+
+    pub async fn ack_cancel_task(
+        &self,
+        req: &AckCancelTaskRequest,
+        token: &String,
+    ) -> Result<Response<AckCancelTaskResponseData>> {
+        let method = "/coordinator/v1/ack_cancel_task";
+        self.post_with_token(method, req, token).await
+    }
+
     async fn post_with_token<Req, Resp>(
         &self,
         method: &str,
@@ -1,7 +1,11 @@
 use super::errors::ErrorCode;
-use crate::types::{ProofFailureType, ProofStatus};
+use crate::{
+    key_signer::keccak256,
+    types::{ProofErrorCode, ProofFailureType, ProofStatus, ProofType},
+};
 use rlp::RlpStream;
 use serde::{Deserialize, Serialize};
+use std::collections::HashMap;
 
 #[derive(Deserialize)]
 pub struct Response<T> {
@@ -10,21 +14,110 @@ pub struct Response<T> {
     pub data: Option<T>,
 }
 
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct HardwareInfo {
+    #[serde(default)]
+    pub gpu_model: String,
+    #[serde(default)]
+    pub gpu_memory_mb: u64,
+    #[serde(default)]
+    pub cpu_cores: u64,
+    #[serde(default)]
+    pub memory_mb: u64,
+}
+
+// the max number of tasks of each proof type the prover runs at once, None is not set and zero means
+// the prover doesn't take the tasks of the proof type.
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct MaxConcurrentTasks {
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub chunk: Option<u32>,
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub batch: Option<u32>,
+}
+
+impl MaxConcurrentTasks {
+    pub fn limit(&self, proof_type: ProofType) -> Option<u32> {
+        match proof_type {
+            ProofType::Chunk => self.chunk,
+            ProofType::Batch => self.batch,
+            ProofType::Undefined => None,
+        }
+    }
+
+    // the (proof type, limit) pairs of the limits which are set, in proof type order, as signed at
+    // login.
+    fn signed_limits(&self) -> Vec<(u8, u32)> {
+        let mut limits = vec![];
+        if let Some(chunk) = self.chunk {
+            limits.push((1u8, chunk));
+        }
+        if let Some(batch) = self.batch {
+            limits.push((2u8, batch));
+        }
+        limits
+    }
+}
+
 #[derive(Serialize, Deserialize)]
 pub struct LoginMessage {
     pub challenge: String,
     pub prover_name: String,
     pub prover_version: String,
+    // signed, used by the coordinator to route the tasks.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub hardware: Option<HardwareInfo>,
+    // signed too, used by the coordinator to schedule the tasks.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub max_concurrent_tasks: Option<MaxConcurrentTasks>,
 }
 
 impl LoginMessage {
+    // rlp encodes the coordinator message.LegacyIdentity. The hardware and the concurrency limits
+    // are optional trailing fields, left out when they aren't advertised, so the message signed by
+    // a prover which doesn't advertise them is unchanged.
     pub fn rlp(&self) -> Vec<u8> {
+        let limits = self
+            .max_concurrent_tasks
+            .as_ref()
+            .map(|m| m.signed_limits())
+            .unwrap_or_default();
+        let num_fields = if !limits.is_empty() {
+            5
+        } else if self.hardware.is_some() {
+            4
+        } else {
+            3
+        };
+
         let mut rlp = RlpStream::new();
-        let num_fields = 3;
         rlp.begin_list(num_fields);
         rlp.append(&self.prover_name);
         rlp.append(&self.prover_version);
         rlp.append(&self.challenge);
+        if num_fields > 3 {
+            match &self.hardware {
+                Some(hardware) => {
+                    rlp.begin_list(4);
+                    rlp.append(&hardware.gpu_model);
+                    rlp.append(&hardware.gpu_memory_mb);
+                    rlp.append(&hardware.cpu_cores);
+                    rlp.append(&hardware.memory_mb);
+                }
+                // a missing hardware is encoded as an empty list, like a nil struct pointer.
+                None => {
+                    rlp.begin_list(0);
+                }
+            }
+        }
+        if num_fields > 4 {
+            rlp.begin_list(limits.len());
+            for (proof_type, max_tasks) in limits {
+                rlp.begin_list(2);
+                rlp.append(&proof_type);
+                rlp.append(&max_tasks);
+            }
+        }
         rlp.out().freeze().into()
     }
 }
@@ -39,6 +132,7 @@ pub struct LoginRequest {
 pub struct LoginResponseData {
     pub time: String,
     pub token: String,
+    pub warning: Option<String>,
 }
 
 pub type ChallengeResponseData = LoginResponseData;
@@ -57,6 +151,61 @@ pub struct GetTaskResponseData {
     pub task_type: crate::types::ProofType,
     pub task_data: String,
     pub hard_fork_name: String,
+    #[serde(default)]
+    pub priority: u8,
+    #[serde(default)]
+    pub deadline: i64,
+    // the first L2 block of the task, picks the circuit assets release.
+    #[serde(default)]
+    pub task_height: u64,
+    // the signature of rlp() by the task signing key of the coordinator, empty if it doesn't sign
+    // the tasks.
+    #[serde(default)]
+    pub signature: String,
+}
+
+impl GetTaskResponseData {
+    // rlp encodes the coordinator message.TaskAssignment of the task assigned to the prover.
+    pub fn rlp(&self, prover_public_key: &str) -> Vec<u8> {
+        let task_type = match self.task_type {
+            ProofType::Undefined => 0u8,
+            ProofType::Chunk => 1u8,
+            ProofType::Batch => 2u8,
+        };
+        let task_data_hash = keccak256(&self.task_data);
+
+        let mut rlp = RlpStream::new();
+        let num_fields = 7;
+        rlp.begin_list(num_fields);
+        rlp.append(&self.uuid);
+        rlp.append(&self.task_id);
+        rlp.append(&task_type);
+        rlp.append(&task_data_hash.to_vec());
+        rlp.append(&self.hard_fork_name);
+        rlp.append(&self.task_height);
+        rlp.append(&prover_public_key.to_string());
+        rlp.out().freeze().into()
+    }
+}
+
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CircuitAssetFile {
+    pub path: String,
+    pub sha256: String,
+}
+
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CircuitAssetsRelease {
+    pub hard_fork_name: String,
+    pub circuit_version: String,
+    pub upgrade_height: u64,
+    pub base_url: String,
+    pub files: Vec<CircuitAssetFile>,
+}
+
+#[derive(Serialize, Deserialize)]
+pub struct CircuitAssetsResponseData {
+    pub releases: Vec<CircuitAssetsRelease>,
 }
 
 #[derive(Serialize, Deserialize, Default)]
@@ -66,10 +215,195 @@ pub struct SubmitProofRequest {
     pub task_type: crate::types::ProofType,
     pub status: ProofStatus,
     pub proof: String,
+    // the hashes of the parts of the proof uploaded beforehand in order, the proof is left empty.
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub proof_parts: Vec<String>,
     pub failure_type: Option<ProofFailureType>,
     pub failure_msg: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub error_code: Option<ProofErrorCode>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub error_details: Option<HashMap<String, String>>,
     pub hard_fork_name: String,
+    // the signature of rlp() by the key of the prover, the nonce must increase with every submission.
+    pub nonce: u64,
+    pub signature: String,
+}
+
+impl SubmitProofRequest {
+    // rlp encodes the coordinator message.ProofSubmission of the request.
+    pub fn rlp(&self) -> Vec<u8> {
+        let task_type = match self.task_type {
+            ProofType::Undefined => 0u8,
+            ProofType::Chunk => 1u8,
+            ProofType::Batch => 2u8,
+        };
+        let status = match self.status {
+            ProofStatus::Ok => 0u8,
+            ProofStatus::Error => 1u8,
+        };
+        let proof_hash = keccak256(&self.proof);
+
+        let mut rlp = RlpStream::new();
+        let num_fields = 6;
+        rlp.begin_list(num_fields);
+        rlp.append(&self.uuid);
+        rlp.append(&self.task_id);
+        rlp.append(&task_type);
+        rlp.append(&status);
+        rlp.append(&proof_hash.to_vec());
+        rlp.append(&self.nonce);
+        rlp.out().freeze().into()
+    }
 }
 
 #[derive(Serialize, Deserialize)]
 pub struct SubmitProofResponseData {}
+
+#[derive(Serialize, Deserialize, Default)]
+pub struct UploadProofPartRequest {
+    pub uuid: String,
+    // the hex keccak256 hash of the part, the part is addressed by it.
+    pub part_hash: String,
+    // the base64 encoded part.
+    pub data: String,
+}
+
+#[derive(Serialize, Deserialize, Default)]
+pub struct ProofUploadRequest {
+    pub uuid: String,
+}
+
+#[derive(Serialize, Deserialize, Default)]
+pub struct ProofUploadResponseData {
+    #[serde(default)]
+    pub part_hashes: Vec<String>,
+}
+
+// stage values of the coordinator message.ProvingStage
+pub const PROVING_STAGE_WITNESS_GENERATION: u8 = 1;
+pub const PROVING_STAGE_PROVING: u8 = 2;
+pub const PROVING_STAGE_AGGREGATING: u8 = 3;
+
+#[derive(Serialize, Deserialize, Default)]
+pub struct ReportProgressRequest {
+    pub uuid: String,
+    pub task_id: String,
+    pub task_type: crate::types::ProofType,
+    pub stage: u8,
+    pub percent: u8,
+    // set when the task is resumed from the task cache after a restart
+    #[serde(default, skip_serializing_if = "std::ops::Not::not")]
+    pub recovered: bool,
+}
+
+// a task assigned to the prover whose proof is no longer wanted, by a re-chunking, a reorg or a proof reuse.
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CancelTask {
+    pub uuid: String,
+    pub id: String,
+    #[serde(rename = "type", default)]
+    pub task_type: crate::types::ProofType,
+    pub reason: String,
+}
+
+#[derive(Serialize, Deserialize, Default)]
+pub struct ReportProgressResponseData {
+    #[serde(default)]
+    pub cancel_tasks: Vec<CancelTask>,
+}
+
+#[derive(Serialize, Deserialize, Default)]
+pub struct AckCancelTaskRequest {
+    pub uuid: String,
+    pub task_id: String,
+    pub task_type: crate::types::ProofType,
+}
+
+#[derive(Serialize, Deserialize)]
+pub struct AckCancelTaskResponseData {}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::key_signer::recover_address;
+    use ethers_core::types::Address;
+    use std::str::FromStr;
+
+    #[test]
+    fn test_task_assignment_signature() {
+        let task = GetTaskResponseData {
+            uuid: "c3d8ad2e-0c3c-4b48-9a3c-7c6b0bbd7d5e".to_string(),
+            task_id: "testID".to_string(),
+            task_type: ProofType::Chunk,
+            task_data: r#"{"block_hashes":["0x01"]}"#.to_string(),
+            hard_fork_name: "darwin".to_string(),
+            priority: 0,
+            deadline: 0,
+            task_height: 100,
+            signature: "0x483a3abb01516d24edc6d875b32ce2817f5f0996049e98283298d6019c9de6432483a8058a39d41b1b975fa49aae95b00a1976e219b59b1703af7968d9835f8200".to_string(),
+        };
+        let public_key = "02dd8b6d0e1ba8b0e5a4da1e5c1ea4f1a20e9bd53e0c4e0e4e1f2a42a7b4ee2b5e";
+
+        // the hash and the signature of the coordinator message.TaskAssignment test.
+        assert_eq!(
+            hex::encode(keccak256(task.rlp(public_key))),
+            "fe186a786a09c7532e287853c78ea19a8b1699af8b0cbbbb4715f7ec8f6525f7"
+        );
+        let coordinator = Address::from_str("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23").unwrap();
+        assert_eq!(
+            recover_address(&task.rlp(public_key), &task.signature).unwrap(),
+            coordinator
+        );
+
+        // a task forwarded to another prover recovers another signer.
+        let other = "03dd8b6d0e1ba8b0e5a4da1e5c1ea4f1a20e9bd53e0c4e0e4e1f2a42a7b4ee2b5e";
+        assert_ne!(
+            recover_address(&task.rlp(other), &task.signature).unwrap(),
+            coordinator
+        );
+        assert!(recover_address(&task.rlp(public_key), "0x1234").is_err());
+    }
+
+    #[test]
+    fn test_login_message_rlp() {
+        let mut message = LoginMessage {
+            challenge: "challenge".to_string(),
+            prover_name: "test".to_string(),
+            prover_version: "v1.0.0".to_string(),
+            hardware: None,
+            max_concurrent_tasks: None,
+        };
+        // the hashes of the coordinator message.LegacyIdentity test.
+        assert_eq!(
+            hex::encode(keccak256(message.rlp())),
+            "b6426468272a88667c9c591b1312dc88434d8d2cb7413e116df6a56ed5bde7ff"
+        );
+
+        message.hardware = Some(HardwareInfo {
+            gpu_model: "NVIDIA A100".to_string(),
+            gpu_memory_mb: 81920,
+            cpu_cores: 64,
+            memory_mb: 512000,
+        });
+        assert_eq!(
+            hex::encode(keccak256(message.rlp())),
+            "f571e188b29a462bb2c37a3d3b59a595a1ca31c81cea6daf5002d2e671320a6c"
+        );
+
+        message.max_concurrent_tasks = Some(MaxConcurrentTasks {
+            chunk: Some(1),
+            batch: Some(0),
+        });
+        assert_eq!(
+            hex::encode(keccak256(message.rlp())),
+            "12412e9e071334579e95f0bbac337a150e117bab9fbb2f6e725ca1378f255b48"
+        );
+
+        message.hardware = None;
+        assert_eq!(
+            hex::encode(keccak256(message.rlp())),
+            "631178006b007c62b700faf78f6814be21e1e7f3c7b5124ffe964e5b37148851"
+        );
+    }
+}
@@ -0,0 +1,155 @@
+use anyhow::Result;
+use std::{
+    io::{BufRead, BufReader, Write},
+    net::{TcpListener, TcpStream},
+    sync::{
+        atomic::{AtomicBool, Ordering},
+        Arc,
+    },
+    time::Duration,
+};
+
+// HealthState is the status of the dependencies of the prover, updated as the prover uses them
+// and read by the health server thread.
+#[derive(Default)]
+pub struct HealthState {
+    signer_loaded: AtomicBool,
+    circuits_loaded: AtomicBool,
+    coordinator_reachable: AtomicBool,
+    // only the chunk provers fetch the block traces from l2geth.
+    l2geth_required: bool,
+    l2geth_reachable: AtomicBool,
+}
+
+impl HealthState {
+    pub fn new(l2geth_required: bool) -> Self {
+        Self {
+            l2geth_required,
+            ..Default::default()
+        }
+    }
+
+    pub fn set_signer_loaded(&self) {
+        self.signer_loaded.store(true, Ordering::Relaxed);
+    }
+
+    pub fn set_circuits_loaded(&self) {
+        self.circuits_loaded.store(true, Ordering::Relaxed);
+    }
+
+    pub fn set_coordinator_reachable(&self, reachable: bool) {
+        self.coordinator_reachable
+            .store(reachable, Ordering::Relaxed);
+    }
+
+    pub fn set_l2geth_reachable(&self, reachable: bool) {
+        self.l2geth_reachable.store(reachable, Ordering::Relaxed);
+    }
+
+    // readiness returns whether the prover is ready, and the status of every dependency in json.
+    fn readiness(&self) -> (bool, String) {
+        let mut checks = vec![
+            ("signer", self.signer_loaded.load(Ordering::Relaxed)),
+            ("circuits", self.circuits_loaded.load(Ordering::Relaxed)),
+            (
+                "coordinator",
+                self.coordinator_reachable.load(Ordering::Relaxed),
+            ),
+        ];
+        if self.l2geth_required {
+            checks.push(("l2geth", self.l2geth_reachable.load(Ordering::Relaxed)));
+        }
+        let ready = checks.iter().all(|(_, ok)| *ok);
+        let status: Vec<String> = checks
+            .iter()
+            .map(|(name, ok)| format!("\"{name}\":{ok}"))
+            .collect();
+        (ready, format!("{{{}}}", status.join(",")))
+    }
+}
+
+// start serves /healthz, answered as long as the process runs, and /readyz, answered 503 until
+// the signer and the circuits are loaded and while the coordinator or l2geth is unreachable. The
+// prover proves on its main thread, so the probes are served by their own thread.
+pub fn start(listen_addr: &str, state: Arc<HealthState>) -> Result<()> {
+    let listener = TcpListener::bind(listen_addr)?;
+    log::info!("[health] serving the probes on {listen_addr}");
+    std::thread::spawn(move || {
+        for stream in listener.incoming() {
+            let result = stream
+                .map_err(anyhow::Error::from)
+                .and_then(|s| handle(s, &state));
+            if let Err(e) = result {
+                log::warn!("[health] failed to serve the probe: {:#}", e);
+            }
+        }
+    });
+    Ok(())
+}
+
+fn handle(mut stream: TcpStream, state: &HealthState) -> Result<()> {
+    stream.set_read_timeout(Some(Duration::from_secs(5)))?;
+    let mut reader = BufReader::new(&stream);
+    let mut request_line = String::new();
+    reader.read_line(&mut request_line)?;
+    // the headers are drained so the connection is not reset with unread data.
+    let mut header = String::new();
+    while reader.read_line(&mut header)? > 0 && !header.trim_end().is_empty() {
+        header.clear();
+    }
+
+    let path = request_line.split_whitespace().nth(1).unwrap_or_default();
+    let (status, body) = route(path, state);
+    write!(
+        stream,
+        "HTTP/1.1 {status}\r\nContent-Type: application/json\r\nContent-Length: {}\r\nConnection: close\r\n\r\n{body}",
+        body.len()
+    )?;
+    Ok(())
+}
+
+fn route(path: &str, state: &HealthState) -> (&'static str, String) {
+    match path {
+        "/healthz" => ("200 OK", "{}".to_string()),
+        "/readyz" => match state.readiness() {
+            (true, status) => ("200 OK", status),
+            (false, status) => ("503 Service Unavailable", status),
+        },
+        _ => ("404 Not Found", "{}".to_string()),
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_readyz() {
+        let state = HealthState::new(true);
+        assert_eq!(route("/healthz", &state).0, "200 OK");
+        assert_eq!(route("/readyz", &state).0, "503 Service Unavailable");
+        assert_eq!(route("/metrics", &state).0, "404 Not Found");
+
+        state.set_signer_loaded();
+        state.set_circuits_loaded();
+        state.set_coordinator_reachable(true);
+        assert_eq!(route("/readyz", &state).0, "503 Service Unavailable");
+        state.set_l2geth_reachable(true);
+        let (status, body) = route("/readyz", &state);
+        assert_eq!(status, "200 OK");
+        assert_eq!(
+            body,
+            r#"{"signer":true,"circuits":true,"coordinator":true,"l2geth":true}"#
+        );
+
+        state.set_coordinator_reachable(false);
+        assert_eq!(route("/readyz", &state).0, "503 Service Unavailable");
+
+        // the batch provers don't depend on l2geth.
+        let state = HealthState::new(false);
+        state.set_signer_loaded();
+        state.set_circuits_loaded();
+        state.set_coordinator_reachable(true);
+        assert_eq!(route("/readyz", &state).0, "200 OK");
+    }
+}
@@ -1,4 +1,4 @@
-use std::path::Path;
+use std::{path::Path, str::FromStr};
 
 use anyhow::Result;
 use ethers_core::{
@@ -10,7 +10,7 @@ use ethers_core::{
     types::Signature as EthSignature,
 };
 
-use ethers_core::types::{H256, U256};
+use ethers_core::types::{Address, H256, U256};
 use hex::ToHex;
 use tiny_keccak::{Hasher, Keccak};
 
@@ -78,6 +78,16 @@ impl KeySigner {
     }
 }
 
+/// Recovers the address of the key which signed the keccak256 hash of the buffer.
+pub fn recover_address<T>(buffer: &T, signature: &str) -> Result<Address>
+where
+    T: AsRef<[u8]>,
+{
+    let signature = EthSignature::from_str(signature)?;
+    let hash = H256::from(keccak256(buffer));
+    Ok(signature.recover(hash)?)
+}
+
 fn buffer_to_hex<T>(buffer: &T, has_prefix: bool) -> String
 where
     T: AsRef<[u8]>,
@@ -1,9 +1,12 @@
 #![feature(lazy_cell)]
 
+mod asset_manager;
 mod config;
 mod coordinator_client;
 mod geth_client;
+mod health;
 mod key_signer;
+mod proof_cache;
 mod prover;
 mod task_cache;
 mod task_processor;
@@ -15,10 +18,13 @@ mod zk_circuits_handler;
 use anyhow::Result;
 use clap::{ArgAction, Parser};
 use config::{AssetsDirEnvConfig, Config};
+use health::HealthState;
+use proof_cache::ProofCache;
 use prover::Prover;
-use std::rc::Rc;
+use std::{rc::Rc, sync::Arc};
 use task_cache::{ClearCacheCoordinatorListener, TaskCache};
 use task_processor::TaskProcessor;
+use types::ProofType;
 
 /// Simple program to greet a person
 #[derive(Parser, Debug)]
@@ -49,18 +55,26 @@ fn main() -> Result<(), Box<dyn std::error::Error>> {
 
     let config: Config = Config::from_file(args.config_file)?;
 
-    if let Err(e) = AssetsDirEnvConfig::init() {
+    if config.mock_mode {
+        log::warn!("prover runs in mock mode, it submits dummy proofs");
+    } else if let Err(e) = AssetsDirEnvConfig::init() {
         log::error!("AssetsDirEnvConfig init failed: {:#}", e);
         std::process::exit(-2);
     }
 
+    // the probes are served while the circuits are loading, the prover is not ready until they're loaded.
+    let health_state = Arc::new(HealthState::new(config.proof_type == ProofType::Chunk));
+    if let Some(c) = &config.health {
+        health::start(&c.listen_addr, health_state.clone())?;
+    }
+
     let task_cache = Rc::new(TaskCache::new(&config.db_path)?);
 
     let coordinator_listener = Box::new(ClearCacheCoordinatorListener {
         task_cache: task_cache.clone(),
     });
 
-    let prover = Prover::new(&config, coordinator_listener)?;
+    let prover = Prover::new(&config, coordinator_listener, health_state)?;
 
     log::info!(
         "prover start successfully. name: {}, type: {:?}, publickey: {}, version: {}",
@@ -70,7 +84,12 @@ fn main() -> Result<(), Box<dyn std::error::Error>> {
         version::get_version(),
     );
 
-    let task_processor = TaskProcessor::new(&prover, task_cache);
+    let proof_cache = match &config.proof_cache {
+        Some(c) => Some(ProofCache::new(task_cache.open_tree("proof_cache")?, c)),
+        None => None,
+    };
+
+    let task_processor = TaskProcessor::new(&prover, task_cache, proof_cache);
 
     task_processor.start();
 
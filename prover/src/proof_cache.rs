@@ -0,0 +1,198 @@
+use anyhow::Result;
+use serde::{Deserialize, Serialize};
+use sled::Tree;
+use std::time::{SystemTime, UNIX_EPOCH};
+
+use crate::{config::ProofCacheConfig, key_signer::keccak256, types::Task};
+
+#[derive(Serialize, Deserialize)]
+struct CachedProof {
+    proof_data: String,
+    // unix timestamp in seconds
+    cached_at: u64,
+}
+
+// ProofCache keeps the recent proofs keyed by the hash of the content of their task, so a task
+// reassigned after it was proved, e.g. after a reconnect, is submitted again without re-proving it.
+pub struct ProofCache {
+    tree: Tree,
+    max_entries: usize,
+    ttl_sec: u64,
+}
+
+impl ProofCache {
+    pub fn new(tree: Tree, config: &ProofCacheConfig) -> Self {
+        log::info!(
+            "[proof_cache] initiate successfully, max_entries: {}, ttl_sec: {}",
+            config.max_entries,
+            config.ttl_sec
+        );
+        Self {
+            tree,
+            max_entries: config.max_entries,
+            ttl_sec: config.ttl_sec,
+        }
+    }
+
+    pub fn get_proof(&self, task: &Task) -> Result<Option<String>> {
+        self.get_proof_at(task, now()?)
+    }
+
+    pub fn put_proof(&self, task: &Task, proof_data: &str) -> Result<()> {
+        self.put_proof_at(task, proof_data, now()?)
+    }
+
+    fn get_proof_at(&self, task: &Task, now: u64) -> Result<Option<String>> {
+        let key = task_content_hash(task);
+        let Some(v) = self.tree.get(key)? else {
+            return Ok(None);
+        };
+        let cached: CachedProof = serde_json::from_slice(v.as_ref())?;
+        if self.expired(&cached, now) {
+            self.tree.remove(key)?;
+            return Ok(None);
+        }
+        Ok(Some(cached.proof_data))
+    }
+
+    fn put_proof_at(&self, task: &Task, proof_data: &str, now: u64) -> Result<()> {
+        let cached = CachedProof {
+            proof_data: proof_data.to_string(),
+            cached_at: now,
+        };
+        self.tree
+            .insert(task_content_hash(task), serde_json::to_vec(&cached)?)?;
+        log::info!("[proof_cache] put_proof with task_id: {}", task.id);
+        self.evict(now)
+    }
+
+    // evict drops the expired proofs, and the oldest ones above max_entries.
+    fn evict(&self, now: u64) -> Result<()> {
+        let mut entries = vec![];
+        for entry in self.tree.iter() {
+            let (k, v) = entry?;
+            let cached: CachedProof = serde_json::from_slice(v.as_ref())?;
+            if self.expired(&cached, now) {
+                self.tree.remove(k)?;
+            } else {
+                entries.push((cached.cached_at, k));
+            }
+        }
+        if entries.len() > self.max_entries {
+            entries.sort_by_key(|(cached_at, _)| *cached_at);
+            for (_, k) in &entries[..entries.len() - self.max_entries] {
+                self.tree.remove(k)?;
+            }
+        }
+        Ok(())
+    }
+
+    fn expired(&self, cached: &CachedProof, now: u64) -> bool {
+        now.saturating_sub(cached.cached_at) > self.ttl_sec
+    }
+}
+
+// task_content_hash hashes what the proof depends on, a reassigned task keeps it while its uuid changes.
+fn task_content_hash(task: &Task) -> [u8; 32] {
+    let mut content = vec![];
+    content.extend_from_slice(&serde_json::to_vec(&task.task_type).unwrap_or_default());
+    content.push(0);
+    content.extend_from_slice(task.hard_fork_name.as_bytes());
+    content.push(0);
+    content.extend_from_slice(task.task_data.as_bytes());
+    keccak256(content)
+}
+
+fn now() -> Result<u64> {
+    Ok(SystemTime::now().duration_since(UNIX_EPOCH)?.as_secs())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::types::ProofType;
+
+    fn task(id: &str, task_data: &str) -> Task {
+        Task {
+            uuid: format!("uuid-{id}"),
+            id: id.to_string(),
+            task_type: ProofType::Chunk,
+            task_data: task_data.to_string(),
+            hard_fork_name: "curie".to_string(),
+            ..Default::default()
+        }
+    }
+
+    fn proof_cache(max_entries: usize, ttl_sec: u64) -> ProofCache {
+        let db = sled::Config::new().temporary(true).open().unwrap();
+        ProofCache::new(
+            db.open_tree("proof_cache").unwrap(),
+            &ProofCacheConfig {
+                max_entries,
+                ttl_sec,
+            },
+        )
+    }
+
+    #[test]
+    fn test_reassigned_task_hits() {
+        let cache = proof_cache(4, 100);
+        cache
+            .put_proof_at(&task("1", "data-1"), "proof-1", 1000)
+            .unwrap();
+
+        // the reassignment has a new uuid.
+        let mut reassigned = task("1", "data-1");
+        reassigned.uuid = "uuid-reassigned".to_string();
+        assert_eq!(
+            cache.get_proof_at(&reassigned, 1050).unwrap().as_deref(),
+            Some("proof-1")
+        );
+
+        assert!(cache
+            .get_proof_at(&task("1", "data-2"), 1050)
+            .unwrap()
+            .is_none());
+        let mut other_fork = task("1", "data-1");
+        other_fork.hard_fork_name = "darwin".to_string();
+        assert!(cache.get_proof_at(&other_fork, 1050).unwrap().is_none());
+
+        // expired.
+        assert!(cache.get_proof_at(&reassigned, 1101).unwrap().is_none());
+        assert!(cache.tree.is_empty());
+    }
+
+    #[test]
+    fn test_evict_oldest() {
+        let cache = proof_cache(2, 100);
+        cache
+            .put_proof_at(&task("1", "data-1"), "proof-1", 1000)
+            .unwrap();
+        cache
+            .put_proof_at(&task("2", "data-2"), "proof-2", 1001)
+            .unwrap();
+        cache
+            .put_proof_at(&task("3", "data-3"), "proof-3", 1002)
+            .unwrap();
+
+        assert_eq!(cache.tree.len(), 2);
+        assert!(cache
+            .get_proof_at(&task("1", "data-1"), 1003)
+            .unwrap()
+            .is_none());
+        assert!(cache
+            .get_proof_at(&task("3", "data-3"), 1003)
+            .unwrap()
+            .is_some());
+
+        // the expired proofs go first.
+        cache
+            .put_proof_at(&task("4", "data-4"), "proof-4", 1102)
+            .unwrap();
+        assert_eq!(cache.tree.len(), 2);
+        assert!(cache
+            .get_proof_at(&task("2", "data-2"), 1102)
+            .unwrap()
+            .is_none());
+    }
+}
@@ -1,14 +1,23 @@
 use anyhow::{bail, Context, Error, Ok, Result};
-use ethers_core::types::U64;
+use ethers_core::types::{Address, U64};
 
-use std::{cell::RefCell, rc::Rc};
+use std::{
+    cell::{Cell, RefCell},
+    collections::{HashMap, HashSet},
+    rc::Rc,
+    str::FromStr,
+    sync::Arc,
+    time::{Duration, SystemTime, UNIX_EPOCH},
+};
 
 use crate::{
+    asset_manager::AssetManager,
     config::Config,
     coordinator_client::{listener::Listener, types::*, CoordinatorClient},
     geth_client::GethClient,
-    key_signer::KeySigner,
-    types::{ProofFailureType, ProofStatus, ProofType},
+    health::HealthState,
+    key_signer::{keccak256, recover_address, KeySigner},
+    types::{ProofErrorCode, ProofFailureType, ProofStatus, ProofType},
     zk_circuits_handler::{CircuitsHandler, CircuitsHandlerProvider},
 };
 
@@ -20,18 +29,50 @@ pub struct Prover<'a> {
     circuits_handler_provider: RefCell<CircuitsHandlerProvider<'a>>,
     coordinator_client: RefCell<CoordinatorClient<'a>>,
     geth_client: Option<Rc<RefCell<GethClient>>>,
+    asset_manager: Option<RefCell<AssetManager>>,
+    submit_nonce: Cell<u64>,
+    health: Arc<HealthState>,
+    task_signer: Option<Address>,
 }
 
 impl<'a> Prover<'a> {
-    pub fn new(config: &'a Config, coordinator_listener: Box<dyn Listener>) -> Result<Self> {
+    pub fn new(
+        config: &'a Config,
+        coordinator_listener: Box<dyn Listener>,
+        health: Arc<HealthState>,
+    ) -> Result<Self> {
         let proof_type = config.proof_type;
+        match config
+            .max_concurrent_tasks
+            .as_ref()
+            .and_then(|m| m.limit(proof_type))
+        {
+            Some(0) => bail!("max_concurrent_tasks is zero for the proof type {proof_type:?}"),
+            Some(limit) if limit > 1 => log::warn!(
+                "max_concurrent_tasks {limit} of the proof type {proof_type:?} is capped to 1, \
+                 the prover proves one task at a time"
+            ),
+            _ => {}
+        }
+        let task_signer = config
+            .coordinator
+            .task_signer
+            .as_deref()
+            .map(Address::from_str)
+            .transpose()
+            .context("invalid coordinator task_signer")?;
         let keystore_path = &config.keystore_path;
         let keystore_password = &config.keystore_password;
 
         let key_signer = Rc::new(KeySigner::new(keystore_path, keystore_password)?);
-        let coordinator_client =
-            CoordinatorClient::new(config, Rc::clone(&key_signer), coordinator_listener)
-                .context("failed to create coordinator_client")?;
+        health.set_signer_loaded();
+        let coordinator_client = CoordinatorClient::new(
+            config,
+            Rc::clone(&key_signer),
+            coordinator_listener,
+            health.clone(),
+        )
+        .context("failed to create coordinator_client")?;
 
         let geth_client = if config.proof_type == ProofType::Chunk {
             Some(Rc::new(RefCell::new(
@@ -45,8 +86,17 @@ impl<'a> Prover<'a> {
             None
         };
 
+        // the provider loads the circuits of every hard fork to report their vks.
         let provider = CircuitsHandlerProvider::new(proof_type, config, geth_client.clone())
             .context("failed to create circuits handler provider")?;
+        health.set_circuits_loaded();
+
+        let asset_manager = match &config.circuit_assets {
+            Some(c) => Some(RefCell::new(
+                AssetManager::new(c).context("failed to create asset manager")?,
+            )),
+            None => None,
+        };
 
         let prover = Prover {
             config,
@@ -54,11 +104,31 @@ impl<'a> Prover<'a> {
             circuits_handler_provider: RefCell::new(provider),
             coordinator_client: RefCell::new(coordinator_client),
             geth_client,
+            asset_manager,
+            submit_nonce: Cell::new(0),
+            health,
+            task_signer,
         };
+        prover.sync_circuit_assets();
 
         Ok(prover)
     }
 
+    // sync_circuit_assets downloads the circuit assets releases newly published by the coordinator,
+    // a failure only delays the switch to them until the next sync.
+    fn sync_circuit_assets(&self) {
+        let Some(asset_manager) = &self.asset_manager else {
+            return;
+        };
+        if !asset_manager.borrow().sync_due() {
+            return;
+        }
+        match self.coordinator_client.borrow().get_circuit_assets() {
+            Ok(releases) => asset_manager.borrow_mut().sync(releases),
+            Err(e) => log::warn!("[prover] failed to get circuit assets: {:#}", e),
+        }
+    }
+
     pub fn get_proof_type(&self) -> ProofType {
         self.config.proof_type
     }
@@ -68,6 +138,8 @@ impl<'a> Prover<'a> {
     }
 
     pub fn fetch_task(&self) -> Result<Task> {
+        self.sync_circuit_assets();
+
         log::info!("[prover] start to fetch_task");
         let mut req = GetTaskRequest {
             task_type: self.get_proof_type(),
@@ -90,32 +162,141 @@ impl<'a> Prover<'a> {
         let resp = self.coordinator_client.borrow_mut().get_task(&req)?;
 
         match resp.data {
-            Some(d) => Ok(Task::from(d)),
+            Some(d) => {
+                self.verify_task(&d)?;
+                Ok(Task::from(d))
+            }
             None => {
                 bail!("data of get_task empty, while error_code is success. there may be something wrong in response data or inner logic.")
             }
         }
     }
 
-    pub fn prove_task(&self, task: &Task) -> Result<ProofDetail> {
+    // verify_task checks the task is signed by the task signing key of the coordinator for this
+    // prover, so a task forged or tampered with by a proxy isn't proved.
+    fn verify_task(&self, task: &GetTaskResponseData) -> Result<()> {
+        let Some(task_signer) = self.task_signer else {
+            return Ok(());
+        };
+        if task.signature.is_empty() {
+            bail!("task {} is not signed by the coordinator", task.task_id);
+        }
+        let signer = recover_address(&task.rlp(&self.get_public_key()), &task.signature)
+            .context("invalid task signature")?;
+        if signer != task_signer {
+            bail!(
+                "task {} is signed by {:?} instead of the coordinator {:?}",
+                task.task_id,
+                signer,
+                task_signer
+            );
+        }
+        Ok(())
+    }
+
+    // prove_task returns None if the coordinator cancelled the task, the cancellation is acknowledged then.
+    pub fn prove_task(&self, task: &Task) -> Result<Option<ProofDetail>> {
         log::info!("[prover] start to prove_task, task id: {}", task.id);
+        let release = self.asset_manager.as_ref().and_then(|m| {
+            m.borrow()
+                .release_for(&task.hard_fork_name, task.task_height)
+                .cloned()
+        });
         let handler: Rc<Box<dyn CircuitsHandler>> = self
             .circuits_handler_provider
             .borrow_mut()
-            .get_circuits_handler(&task.hard_fork_name)
+            .get_circuits_handler(&task.hard_fork_name, release.as_ref())
             .context("failed to get circuit handler")?;
         self.do_prove(task, handler)
     }
 
-    fn do_prove(&self, task: &Task, handler: Rc<Box<dyn CircuitsHandler>>) -> Result<ProofDetail> {
+    // the proving can't be interrupted, the cancellations are checked at the progress reports around it.
+    fn do_prove(
+        &self,
+        task: &Task,
+        handler: Rc<Box<dyn CircuitsHandler>>,
+    ) -> Result<Option<ProofDetail>> {
         let mut proof_detail = ProofDetail {
             id: task.id.clone(),
             proof_type: task.task_type,
             ..Default::default()
         };
 
+        let stage = proving_stage(task.task_type);
+        if let Some(cancel_task) = self.report_progress(task, stage, 0) {
+            self.ack_cancel_task(task, &cancel_task)?;
+            return Ok(None);
+        }
         proof_detail.proof_data = handler.get_proof_data(task.task_type, task)?;
-        Ok(proof_detail)
+        if let Some(cancel_task) = self.report_progress(task, stage, 100) {
+            self.ack_cancel_task(task, &cancel_task)?;
+            return Ok(None);
+        }
+        Ok(Some(proof_detail))
+    }
+
+    // tells the coordinator the task is resumed after a restart, the coordinator rejects it if the task is no longer assigned to us.
+    pub fn report_recovery(&self, task: &Task) -> Result<()> {
+        log::info!("[prover] start to report_recovery, task id: {}", task.id);
+        let request = ReportProgressRequest {
+            uuid: task.uuid.clone(),
+            task_id: task.id.clone(),
+            task_type: task.task_type,
+            stage: proving_stage(task.task_type),
+            percent: 0,
+            recovered: true,
+        };
+        self.coordinator_client
+            .borrow_mut()
+            .report_progress(&request)?;
+        Ok(())
+    }
+
+    // progress reports are best effort, a failed report must not fail the task.
+    // returns the cancellation of the task if the coordinator sent one with the response.
+    fn report_progress(&self, task: &Task, stage: u8, percent: u8) -> Option<CancelTask> {
+        let request = ReportProgressRequest {
+            uuid: task.uuid.clone(),
+            task_id: task.id.clone(),
+            task_type: task.task_type,
+            stage,
+            percent,
+            ..Default::default()
+        };
+        match self
+            .coordinator_client
+            .borrow_mut()
+            .report_progress(&request)
+        {
+            Ok(response) => response
+                .data
+                .and_then(|d| d.cancel_tasks.into_iter().find(|t| t.uuid == task.uuid)),
+            Err(e) => {
+                log::warn!(
+                    "[prover] failed to report progress, task id: {}, err: {:#}",
+                    task.id,
+                    e
+                );
+                None
+            }
+        }
+    }
+
+    fn ack_cancel_task(&self, task: &Task, cancel_task: &CancelTask) -> Result<()> {
+        log::info!(
+            "[prover] abort the cancelled task, task id: {}, reason: {}",
+            task.id,
+            cancel_task.reason
+        );
+        let request = AckCancelTaskRequest {
+            uuid: task.uuid.clone(),
+            task_id: task.id.clone(),
+            task_type: task.task_type,
+        };
+        self.coordinator_client
+            .borrow_mut()
+            .ack_cancel_task(&request)?;
+        Ok(())
     }
 
     pub fn submit_proof(&self, proof_detail: ProofDetail, task: &Task) -> Result<()> {
@@ -134,7 +315,7 @@ impl<'a> Prover<'a> {
             ..Default::default()
         };
 
-        self.do_submit(&request)
+        self.do_submit(request)
     }
 
     pub fn submit_error(
@@ -143,7 +324,16 @@ impl<'a> Prover<'a> {
         failure_type: ProofFailureType,
         error: Error,
     ) -> Result<()> {
-        log::info!("[prover] start to submit_error, task id: {}", task.id);
+        let error_code = ProofErrorCode::classify(&error);
+        log::info!(
+            "[prover] start to submit_error, task id: {}, error code: {:?}",
+            task.id,
+            error_code
+        );
+        let error_details = HashMap::from([
+            ("root_cause".to_string(), error.root_cause().to_string()),
+            ("hard_fork_name".to_string(), task.hard_fork_name.clone()),
+        ]);
         let request = SubmitProofRequest {
             uuid: task.uuid.clone(),
             task_id: task.id.clone(),
@@ -151,14 +341,89 @@ impl<'a> Prover<'a> {
             status: ProofStatus::Error,
             failure_type: Some(failure_type),
             failure_msg: Some(error.to_string()),
+            error_code: Some(error_code),
+            error_details: Some(error_details),
             hard_fork_name: task.hard_fork_name.clone(),
             ..Default::default()
         };
-        self.do_submit(&request)
+        self.do_submit(request)
     }
 
-    fn do_submit(&self, request: &SubmitProofRequest) -> Result<()> {
-        self.coordinator_client.borrow_mut().submit_proof(request)?;
+    // do_submit signs the submission with a nonce above the previous one, the unix time in
+    // milliseconds keeps it increasing across restarts.
+    fn do_submit(&self, mut request: SubmitProofRequest) -> Result<()> {
+        let now = SystemTime::now().duration_since(UNIX_EPOCH)?.as_millis() as u64;
+        let nonce = now.max(self.submit_nonce.get() + 1);
+        self.submit_nonce.set(nonce);
+
+        request.nonce = nonce;
+        request.signature = self.key_signer.sign_buffer(&request.rlp())?;
+        let part_size = self.config.coordinator.proof_upload_part_size;
+        if part_size > 0 && request.proof.len() > part_size {
+            self.upload_proof(&mut request, part_size)?;
+        }
+        self.coordinator_client
+            .borrow_mut()
+            .submit_proof(&request)?;
+        Ok(())
+    }
+
+    // upload_proof uploads the signed proof in parts and submits the list of its parts instead. A
+    // failed attempt is resumed with the parts the coordinator hasn't received yet.
+    fn upload_proof(&self, request: &mut SubmitProofRequest, part_size: usize) -> Result<()> {
+        let parts: Vec<&[u8]> = request.proof.as_bytes().chunks(part_size).collect();
+        let part_hashes: Vec<String> = parts
+            .iter()
+            .map(|part| format!("0x{}", hex::encode(keccak256(part))))
+            .collect();
+
+        let mut attempt = 1;
+        while let Err(e) = self.upload_missing_parts(&request.uuid, &parts, &part_hashes) {
+            if attempt > self.config.coordinator.retry_count {
+                return Err(e).context("failed to upload the proof");
+            }
+            log::warn!(
+                "[prover] proof upload interrupted, task id: {}, attempt: {attempt}, error: {e:#}",
+                request.task_id
+            );
+            std::thread::sleep(Duration::from_secs(
+                self.config.coordinator.retry_wait_time_sec,
+            ));
+            attempt += 1;
+        }
+
+        request.proof_parts = part_hashes;
+        request.proof = String::new();
+        Ok(())
+    }
+
+    fn upload_missing_parts(
+        &self,
+        uuid: &str,
+        parts: &[&[u8]],
+        part_hashes: &[String],
+    ) -> Result<()> {
+        let mut coordinator_client = self.coordinator_client.borrow_mut();
+        let uploaded: HashSet<String> = coordinator_client
+            .get_proof_upload(&ProofUploadRequest {
+                uuid: uuid.to_string(),
+            })?
+            .data
+            .map(|d| d.part_hashes)
+            .unwrap_or_default()
+            .into_iter()
+            .collect();
+
+        for (part, part_hash) in parts.iter().zip(part_hashes) {
+            if uploaded.contains(part_hash) {
+                continue;
+            }
+            coordinator_client.upload_proof_part(&UploadProofPartRequest {
+                uuid: uuid.to_string(),
+                part_hash: part_hash.clone(),
+                data: base64::encode(part),
+            })?;
+        }
         Ok(())
     }
 
@@ -168,7 +433,15 @@ impl<'a> Prover<'a> {
             .as_ref()
             .unwrap()
             .borrow_mut()
-            .block_number()?;
-        Ok(number.as_number())
+            .block_number();
+        self.health.set_l2geth_reachable(number.is_ok());
+        Ok(number?.as_number())
+    }
+}
+
+fn proving_stage(task_type: ProofType) -> u8 {
+    match task_type {
+        ProofType::Batch => PROVING_STAGE_AGGREGATING,
+        _ => PROVING_STAGE_PROVING,
     }
 }
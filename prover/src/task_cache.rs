@@ -2,7 +2,7 @@ use anyhow::{Ok, Result};
 
 use super::coordinator_client::{listener::Listener, types::SubmitProofRequest};
 use crate::types::TaskWrapper;
-use sled::{Config, Db};
+use sled::{Config, Db, Tree};
 use std::rc::Rc;
 
 pub struct TaskCache {
@@ -42,6 +42,11 @@ impl TaskCache {
         Ok(None)
     }
 
+    // open_tree opens a tree of the db for the other caches, the db can't be opened twice.
+    pub fn open_tree(&self, name: &str) -> Result<Tree> {
+        Ok(self.db.open_tree(name)?)
+    }
+
     pub fn delete_task(&self, task_id: String) -> Result<()> {
         let k = task_id.clone().into_bytes();
         self.db.remove(k)?;
@@ -1,15 +1,38 @@
-use super::{prover::Prover, task_cache::TaskCache};
+use super::{
+    proof_cache::ProofCache,
+    prover::Prover,
+    task_cache::TaskCache,
+    types::{ProofDetail, Task},
+};
 use anyhow::{Context, Result};
-use std::rc::Rc;
+use std::{
+    cell::Cell,
+    rc::Rc,
+    time::{SystemTime, UNIX_EPOCH},
+};
 
+// TaskProcessor proves one task at a time: the next task is only fetched once the previous one is
+// submitted, which enforces the max_concurrent_tasks of one advertised at login.
 pub struct TaskProcessor<'a> {
     prover: &'a Prover<'a>,
     task_cache: Rc<TaskCache>,
+    proof_cache: Option<ProofCache>,
+    // true until the first round, when a cached task is one accepted before a restart
+    recovering: Cell<bool>,
 }
 
 impl<'a> TaskProcessor<'a> {
-    pub fn new(prover: &'a Prover<'a>, task_cache: Rc<TaskCache>) -> Self {
-        TaskProcessor { prover, task_cache }
+    pub fn new(
+        prover: &'a Prover<'a>,
+        task_cache: Rc<TaskCache>,
+        proof_cache: Option<ProofCache>,
+    ) -> Self {
+        TaskProcessor {
+            prover,
+            task_cache,
+            proof_cache,
+            recovering: Cell::new(true),
+        }
     }
 
     pub fn start(&self) {
@@ -29,7 +52,14 @@ impl<'a> TaskProcessor<'a> {
             .get_last_task()
             .context("failed to peek from stack")?;
 
+        let recovering = self.recovering.replace(false);
         let mut task_wrapper = match task_from_cache {
+            Some(t) if recovering => {
+                if !self.recover_task(&t.task)? {
+                    return Ok(());
+                }
+                t
+            }
             Some(t) => t,
             None => {
                 let fetch_result = self.prover.fetch_task();
@@ -52,8 +82,12 @@ impl<'a> TaskProcessor<'a> {
                 task_wrapper.task.task_type,
                 task_wrapper.task.id
             );
-            let result = match self.prover.prove_task(&task_wrapper.task) {
-                Ok(proof_detail) => self.prover.submit_proof(proof_detail, &task_wrapper.task),
+            let result = match self.prove_task(&task_wrapper.task) {
+                Ok(Some(proof_detail)) => {
+                    self.prover.submit_proof(proof_detail, &task_wrapper.task)
+                }
+                // the cancelled task is not submitted, nobody wants its proof anymore.
+                Ok(None) => self.task_cache.delete_task(task_wrapper.task.id.clone()),
                 Err(error) => self.prover.submit_error(
                     &task_wrapper.task,
                     super::types::ProofFailureType::NoPanic,
@@ -75,4 +109,79 @@ impl<'a> TaskProcessor<'a> {
             anyhow::anyhow!("zk proving panic for task"),
         )
     }
+
+    // prove_task returns the cached proof of a task with the same content proved before, e.g. a task
+    // reassigned after a reconnect, and caches the new proofs.
+    fn prove_task(&self, task: &Task) -> Result<Option<ProofDetail>> {
+        let Some(proof_cache) = &self.proof_cache else {
+            return self.prover.prove_task(task);
+        };
+
+        match proof_cache.get_proof(task) {
+            Ok(Some(proof_data)) => {
+                log::info!(
+                    "submit the cached proof of the task, task_type: {:?}, task_id: {}",
+                    task.task_type,
+                    task.id
+                );
+                return Ok(Some(ProofDetail {
+                    id: task.id.clone(),
+                    proof_type: task.task_type,
+                    proof_data,
+                    ..Default::default()
+                }));
+            }
+            Ok(None) => {}
+            Err(e) => log::warn!("failed to get the cached proof: {:#}", e),
+        }
+
+        let proof_detail = self.prover.prove_task(task)?;
+        if let Some(detail) = &proof_detail {
+            if let Err(e) = proof_cache.put_proof(task, &detail.proof_data) {
+                log::warn!("failed to cache the proof: {:#}", e);
+            }
+        }
+        Ok(proof_detail)
+    }
+
+    // recover_task decides whether the task accepted before a restart is resumed, the expired tasks and the tasks
+    // the coordinator no longer assigns to us are failed and dropped from the cache.
+    fn recover_task(&self, task: &Task) -> Result<bool> {
+        let now = SystemTime::now().duration_since(UNIX_EPOCH)?.as_secs() as i64;
+        if task.deadline > 0 && now > task.deadline {
+            log::warn!(
+                "drop the cached task whose deadline has passed, task_type: {:?}, task_id: {}",
+                task.task_type,
+                task.id
+            );
+            // the submission removes the task from the cache, even if the coordinator rejects it.
+            if let Err(e) = self.prover.submit_error(
+                task,
+                super::types::ProofFailureType::NoPanic,
+                anyhow::anyhow!("task deadline passed before the prover restarted"),
+            ) {
+                log::warn!("failed to submit the expired task error: {:#}", e);
+                self.task_cache.delete_task(task.id.clone())?;
+            }
+            return Ok(false);
+        }
+
+        if let Err(e) = self.prover.report_recovery(task) {
+            log::warn!(
+                "coordinator rejected the recovered task, drop it, task_type: {:?}, task_id: {}, err: {:#}",
+                task.task_type,
+                task.id,
+                e
+            );
+            self.task_cache.delete_task(task.id.clone())?;
+            return Ok(false);
+        }
+
+        log::info!(
+            "resume the task accepted before the restart, task_type: {:?}, task_id: {}",
+            task.task_type,
+            task.id
+        );
+        Ok(true)
+    }
 }
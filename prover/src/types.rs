@@ -60,6 +60,14 @@ pub struct Task {
     pub task_data: String,
     #[serde(default)]
     pub hard_fork_name: String,
+    #[serde(default)]
+    pub priority: u8,
+    // unix timestamp in seconds, 0 means no deadline
+    #[serde(default)]
+    pub deadline: i64,
+    // the first L2 block of the task, 0 if the coordinator doesn't send it
+    #[serde(default)]
+    pub task_height: u64,
 }
 
 impl From<GetTaskResponseData> for Task {
@@ -70,6 +78,9 @@ impl From<GetTaskResponseData> for Task {
             task_type: value.task_type,
             task_data: value.task_data,
             hard_fork_name: value.hard_fork_name,
+            priority: value.priority,
+            deadline: value.deadline,
+            task_height: value.task_height,
         }
     }
 }
@@ -151,6 +162,83 @@ impl Default for ProofFailureType {
     }
 }
 
+// ProofErrorCode classifies a proving failure for the triage on the coordinator, the codes are
+// the ones of the coordinator message.ProofErrorCode.
+#[derive(Debug, Clone, Copy, PartialEq)]
+pub enum ProofErrorCode {
+    Undefined,
+    WitnessGeneration,
+    RowOverflow,
+    VkMismatch,
+    OutOfMemory,
+    Timeout,
+}
+
+impl ProofErrorCode {
+    fn from_u8(v: u8) -> Self {
+        match v {
+            1 => ProofErrorCode::WitnessGeneration,
+            2 => ProofErrorCode::RowOverflow,
+            3 => ProofErrorCode::VkMismatch,
+            4 => ProofErrorCode::OutOfMemory,
+            5 => ProofErrorCode::Timeout,
+            _ => ProofErrorCode::Undefined,
+        }
+    }
+
+    // classify guesses the code from the messages of the error chain, the circuits don't return
+    // typed errors.
+    pub fn classify(error: &anyhow::Error) -> Self {
+        let msg = format!("{:#}", error).to_lowercase();
+        let has = |pattern: &str| msg.contains(pattern);
+        if has("out of memory") || has("memory allocation") || has("cannot allocate memory") {
+            ProofErrorCode::OutOfMemory
+        } else if has("timeout") || has("timed out") || has("deadline") {
+            ProofErrorCode::Timeout
+        } else if has("vk mismatch") || has("verification key") || has("vk is not") {
+            ProofErrorCode::VkMismatch
+        } else if has("row") && (has("overflow") || has("exceed")) {
+            ProofErrorCode::RowOverflow
+        } else if has("witness") {
+            ProofErrorCode::WitnessGeneration
+        } else {
+            ProofErrorCode::Undefined
+        }
+    }
+}
+
+impl Serialize for ProofErrorCode {
+    fn serialize<S>(&self, serializer: S) -> Result<S::Ok, S::Error>
+    where
+        S: Serializer,
+    {
+        match *self {
+            ProofErrorCode::Undefined => serializer.serialize_u8(0),
+            ProofErrorCode::WitnessGeneration => serializer.serialize_u8(1),
+            ProofErrorCode::RowOverflow => serializer.serialize_u8(2),
+            ProofErrorCode::VkMismatch => serializer.serialize_u8(3),
+            ProofErrorCode::OutOfMemory => serializer.serialize_u8(4),
+            ProofErrorCode::Timeout => serializer.serialize_u8(5),
+        }
+    }
+}
+
+impl<'de> Deserialize<'de> for ProofErrorCode {
+    fn deserialize<D>(deserializer: D) -> Result<Self, D::Error>
+    where
+        D: Deserializer<'de>,
+    {
+        let v: u8 = u8::deserialize(deserializer)?;
+        Ok(ProofErrorCode::from_u8(v))
+    }
+}
+
+impl Default for ProofErrorCode {
+    fn default() -> Self {
+        Self::Undefined
+    }
+}
+
 #[derive(Debug, Clone, Copy, PartialEq)]
 pub enum ProofStatus {
     Ok,
@@ -193,3 +281,35 @@ impl Default for ProofStatus {
         Self::Ok
     }
 }
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use anyhow::{anyhow, Context};
+
+    #[test]
+    fn test_classify_proof_error() {
+        let oom: anyhow::Result<()> = Err(anyhow!("memory allocation of 1024 bytes failed"));
+        let oom = oom.context("failed to prove chunk").unwrap_err();
+        assert_eq!(ProofErrorCode::classify(&oom), ProofErrorCode::OutOfMemory);
+
+        let cases = [
+            (
+                "row usage overflow in the keccak circuit",
+                ProofErrorCode::RowOverflow,
+            ),
+            (
+                "failed to generate the witness",
+                ProofErrorCode::WitnessGeneration,
+            ),
+            (
+                "task deadline passed before the prover restarted",
+                ProofErrorCode::Timeout,
+            ),
+            ("zk proving panic for task", ProofErrorCode::Undefined),
+        ];
+        for (msg, code) in cases {
+            assert_eq!(ProofErrorCode::classify(&anyhow!(msg)), code, "{}", msg);
+        }
+    }
+}
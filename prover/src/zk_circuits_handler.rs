@@ -1,14 +1,17 @@
 mod bernoulli;
 mod curie;
+mod mock;
 
 use super::geth_client::GethClient;
 use crate::{
-    config::{AssetsDirEnvConfig, Config},
+    asset_manager::AssetsRelease,
+    config::{AssetsDirEnvConfig, CircuitConfig, Config},
     types::{ProofType, Task},
 };
 use anyhow::{bail, Result};
 use bernoulli::BaseCircuitsHandler;
 use curie::NextCircuitsHandler;
+use mock::MockCircuitsHandler;
 use std::{cell::RefCell, collections::HashMap, rc::Rc};
 
 type HardForkName = String;
@@ -25,12 +28,33 @@ pub trait CircuitsHandler {
     fn get_proof_data(&self, task_type: ProofType, task: &Task) -> Result<String>;
 }
 
+// a downloaded release replaces the configured circuit of the hard fork.
 type CircuitsHandlerBuilder = fn(
     proof_type: ProofType,
     config: &Config,
+    release: Option<&AssetsRelease>,
     geth_client: Option<Rc<RefCell<GethClient>>>,
 ) -> Result<Box<dyn CircuitsHandler>>;
 
+// circuit_paths returns the params and assets paths of the release if any, of the configured
+// circuit otherwise, and points the assets dir env at them.
+fn circuit_paths(
+    circuit: &CircuitConfig,
+    release: Option<&AssetsRelease>,
+    enable_configured_assets_dir: fn(),
+) -> (String, String) {
+    match release {
+        Some(release) => {
+            AssetsDirEnvConfig::enable_dir(&release.assets_path());
+            (release.params_path(), release.assets_path())
+        }
+        None => {
+            enable_configured_assets_dir();
+            (circuit.params_path.clone(), circuit.assets_path.clone())
+        }
+    }
+}
+
 pub struct CircuitsHandlerProvider<'a> {
     proof_type: ProofType,
     config: &'a Config,
@@ -38,8 +62,10 @@ pub struct CircuitsHandlerProvider<'a> {
     circuits_handler_builder_map: HashMap<HardForkName, CircuitsHandlerBuilder>,
 
     current_hard_fork_name: Option<HardForkName>,
+    // the circuit version of the cached handler's release, None for a configured circuit.
+    current_circuit_version: Option<String>,
     current_circuit: Option<Rc<Box<dyn CircuitsHandler>>>,
-    vks: Vec<String>,
+    vks: HashMap<HardForkName, String>,
 }
 
 impl<'a> CircuitsHandlerProvider<'a> {
@@ -53,20 +79,20 @@ impl<'a> CircuitsHandlerProvider<'a> {
         fn handler_builder(
             proof_type: ProofType,
             config: &Config,
+            release: Option<&AssetsRelease>,
             geth_client: Option<Rc<RefCell<GethClient>>>,
         ) -> Result<Box<dyn CircuitsHandler>> {
             log::info!(
                 "now init zk circuits handler, hard_fork_name: {}",
                 &config.low_version_circuit.hard_fork_name
             );
-            AssetsDirEnvConfig::enable_first();
-            BaseCircuitsHandler::new(
-                proof_type,
-                &config.low_version_circuit.params_path,
-                &config.low_version_circuit.assets_path,
-                geth_client,
-            )
-            .map(|handler| Box::new(handler) as Box<dyn CircuitsHandler>)
+            let (params_path, assets_path) = circuit_paths(
+                &config.low_version_circuit,
+                release,
+                AssetsDirEnvConfig::enable_first,
+            );
+            BaseCircuitsHandler::new(proof_type, &params_path, &assets_path, geth_client)
+                .map(|handler| Box::new(handler) as Box<dyn CircuitsHandler>)
         }
         m.insert(
             config.low_version_circuit.hard_fork_name.clone(),
@@ -76,20 +102,20 @@ impl<'a> CircuitsHandlerProvider<'a> {
         fn next_handler_builder(
             proof_type: ProofType,
             config: &Config,
+            release: Option<&AssetsRelease>,
             geth_client: Option<Rc<RefCell<GethClient>>>,
         ) -> Result<Box<dyn CircuitsHandler>> {
             log::info!(
                 "now init zk circuits handler, hard_fork_name: {}",
                 &config.high_version_circuit.hard_fork_name
             );
-            AssetsDirEnvConfig::enable_second();
-            NextCircuitsHandler::new(
-                proof_type,
-                &config.high_version_circuit.params_path,
-                &config.high_version_circuit.assets_path,
-                geth_client,
-            )
-            .map(|handler| Box::new(handler) as Box<dyn CircuitsHandler>)
+            let (params_path, assets_path) = circuit_paths(
+                &config.high_version_circuit,
+                release,
+                AssetsDirEnvConfig::enable_second,
+            );
+            NextCircuitsHandler::new(proof_type, &params_path, &assets_path, geth_client)
+                .map(|handler| Box::new(handler) as Box<dyn CircuitsHandler>)
         }
 
         m.insert(
@@ -97,6 +123,23 @@ impl<'a> CircuitsHandlerProvider<'a> {
             next_handler_builder,
         );
 
+        // in mock mode both hard forks get the mock handler, no circuit is loaded.
+        if config.mock_mode {
+            fn mock_handler_builder(
+                proof_type: ProofType,
+                _config: &Config,
+                _release: Option<&AssetsRelease>,
+                _geth_client: Option<Rc<RefCell<GethClient>>>,
+            ) -> Result<Box<dyn CircuitsHandler>> {
+                log::info!("now init mock circuits handler, proofs are dummy");
+                MockCircuitsHandler::new(proof_type)
+                    .map(|handler| Box::new(handler) as Box<dyn CircuitsHandler>)
+            }
+            for builder in m.values_mut() {
+                *builder = mock_handler_builder;
+            }
+        }
+
         let vks = CircuitsHandlerProvider::init_vks(proof_type, config, &m, geth_client.clone());
 
         let provider = CircuitsHandlerProvider {
@@ -105,6 +148,7 @@ impl<'a> CircuitsHandlerProvider<'a> {
             geth_client,
             circuits_handler_builder_map: m,
             current_hard_fork_name: None,
+            current_circuit_version: None,
             current_circuit: None,
             vks,
         };
@@ -115,9 +159,13 @@ impl<'a> CircuitsHandlerProvider<'a> {
     pub fn get_circuits_handler(
         &mut self,
         hard_fork_name: &String,
+        release: Option<&AssetsRelease>,
     ) -> Result<Rc<Box<dyn CircuitsHandler>>> {
+        let circuit_version = release.map(|r| r.circuit_version.clone());
         match &self.current_hard_fork_name {
-            Some(name) if name == hard_fork_name => {
+            Some(name)
+                if name == hard_fork_name && self.current_circuit_version == circuit_version =>
+            {
                 log::info!("get circuits handler from cache");
                 if let Some(handler) = &self.current_circuit {
                     Ok(handler.clone())
@@ -131,10 +179,26 @@ impl<'a> CircuitsHandlerProvider<'a> {
                     "failed to get circuits handler from cache, create a new one: {hard_fork_name}"
                 );
                 if let Some(builder) = self.circuits_handler_builder_map.get(hard_fork_name) {
-                    log::info!("building circuits handler for {hard_fork_name}");
-                    let handler = builder(self.proof_type, self.config, self.geth_client.clone())
-                        .expect("failed to build circuits handler");
+                    log::info!(
+                        "building circuits handler for {hard_fork_name}, circuit version: {:?}",
+                        circuit_version
+                    );
+                    let handler = builder(
+                        self.proof_type,
+                        self.config,
+                        release,
+                        self.geth_client.clone(),
+                    )
+                    .expect("failed to build circuits handler");
+                    // after a switch to a release, its vk is reported to get the tasks it proves.
+                    if release.is_some() {
+                        if let Some(vk) = handler.get_vk(self.proof_type) {
+                            self.vks
+                                .insert(hard_fork_name.clone(), utils::encode_vk(vk));
+                        }
+                    }
                     self.current_hard_fork_name = Some(hard_fork_name.clone());
+                    self.current_circuit_version = circuit_version;
                     let rc_handler = Rc::new(handler);
                     self.current_circuit = Some(rc_handler.clone());
                     Ok(rc_handler)
@@ -151,22 +215,22 @@ impl<'a> CircuitsHandlerProvider<'a> {
         config: &'a Config,
         circuits_handler_builder_map: &HashMap<HardForkName, CircuitsHandlerBuilder>,
         geth_client: Option<Rc<RefCell<GethClient>>>,
-    ) -> Vec<String> {
+    ) -> HashMap<HardForkName, String> {
         circuits_handler_builder_map
             .iter()
             .map(|(hard_fork_name, build)| {
-                let handler = build(proof_type, config, geth_client.clone())
+                let handler = build(proof_type, config, None, geth_client.clone())
                     .expect("failed to build circuits handler");
                 let vk = handler
                     .get_vk(proof_type)
                     .map_or("".to_string(), utils::encode_vk);
                 log::info!("vk for {hard_fork_name} is {vk}");
-                vk
+                (hard_fork_name.clone(), vk)
             })
-            .collect::<Vec<String>>()
+            .collect()
     }
 
     pub fn get_vks(&self) -> Vec<String> {
-        self.vks.clone()
+        self.vks.values().cloned().collect()
     }
 }
@@ -0,0 +1,91 @@
+use super::CircuitsHandler;
+use crate::{
+    key_signer::keccak256,
+    types::{ProofType, Task},
+};
+use anyhow::{bail, Result};
+use serde_json::json;
+
+// MockCircuitsHandler skips the real proving and returns deterministic dummy proofs,
+// which are only accepted by a coordinator running in mock verifier mode.
+pub struct MockCircuitsHandler {
+    proof_type: ProofType,
+}
+
+impl MockCircuitsHandler {
+    pub fn new(proof_type: ProofType) -> Result<Self> {
+        match proof_type {
+            ProofType::Chunk | ProofType::Batch => Ok(Self { proof_type }),
+            _ => bail!("proof type invalid"),
+        }
+    }
+
+    // the same task always gets the same dummy proof.
+    fn dummy_bytes(task: &Task, field: &str) -> String {
+        let seed = format!("mock {:?} {} of task {}", task.task_type, field, task.id);
+        base64::encode(keccak256(seed))
+    }
+}
+
+impl CircuitsHandler for MockCircuitsHandler {
+    fn get_vk(&self, _task_type: ProofType) -> Option<Vec<u8>> {
+        // the mock verifier registers empty vks, which accept any vk.
+        None
+    }
+
+    fn get_proof_data(&self, task_type: ProofType, task: &Task) -> Result<String> {
+        if task_type != self.proof_type {
+            bail!(
+                "mock handler of {:?} can't prove a {:?} task",
+                self.proof_type,
+                task_type
+            )
+        }
+        log::info!("[circuit] mock {:?} proof for task {}", task_type, task.id);
+
+        let proof = match task_type {
+            ProofType::Chunk => json!({
+                "protocol": Self::dummy_bytes(task, "protocol"),
+                "proof": Self::dummy_bytes(task, "proof"),
+                "instances": Self::dummy_bytes(task, "instances"),
+                "vk": "",
+            }),
+            ProofType::Batch => json!({
+                "proof": Self::dummy_bytes(task, "proof"),
+                "instances": Self::dummy_bytes(task, "instances"),
+                "vk": "",
+            }),
+            _ => bail!("proof type invalid"),
+        };
+        Ok(proof.to_string())
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_mock_proof_is_deterministic() -> Result<()> {
+        let handler = MockCircuitsHandler::new(ProofType::Batch)?;
+        let task = Task {
+            id: "0x01".to_string(),
+            task_type: ProofType::Batch,
+            ..Default::default()
+        };
+
+        let proof = handler.get_proof_data(ProofType::Batch, &task)?;
+        assert_eq!(proof, handler.get_proof_data(ProofType::Batch, &task)?);
+        assert!(handler.get_proof_data(ProofType::Chunk, &task).is_err());
+
+        let other_task = Task {
+            id: "0x02".to_string(),
+            ..task
+        };
+        assert_ne!(
+            proof,
+            handler.get_proof_data(ProofType::Batch, &other_task)?
+        );
+        Ok(())
+    }
+}
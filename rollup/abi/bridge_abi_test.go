@@ -65,6 +65,24 @@ func TestPackFinalizeBatchWithProof(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestPackFinalizeBundleWithProof4844(t *testing.T) {
+	assert := assert.New(t)
+
+	l1RollupABI, err := ScrollChainMetaData.GetAbi()
+	assert.NoError(err)
+
+	batchHeaders := [][]byte{{}, {}}
+	prevStateRoot := common.Hash{}
+	postStateRoots := []common.Hash{{}, {}}
+	withdrawRoots := []common.Hash{{}, {}}
+	blobDataProofs := [][]byte{{}, {}}
+	aggrProofs := [][]byte{{}, {}}
+
+	_, err = l1RollupABI.Pack("finalizeBundleWithProof4844", batchHeaders, prevStateRoot, postStateRoots, withdrawRoots, blobDataProofs, aggrProofs)
+	assert.NoError(err)
+	assert.Equal("finalizeBundleWithProof4844(bytes[],bytes32,bytes32[],bytes32[],bytes[],bytes[])", l1RollupABI.Methods["finalizeBundleWithProof4844"].Sig)
+}
+
 func TestPackImportGenesisBatch(t *testing.T) {
 	assert := assert.New(t)
 
@@ -97,6 +115,12 @@ func TestPackSetL1BaseFee(t *testing.T) {
 	baseFee := big.NewInt(2333)
 	_, err = l1GasOracleABI.Pack("setL1BaseFee", baseFee)
 	assert.NoError(err)
+
+	scalar := big.NewInt(1000000000)
+	for _, method := range []string{"setOverhead", "setScalar", "setCommitScalar", "setBlobScalar"} {
+		_, err = l1GasOracleABI.Pack(method, scalar)
+		assert.NoError(err)
+	}
 }
 
 func TestPackSetL2BaseFee(t *testing.T) {
@@ -109,3 +133,11 @@ func TestPackSetL2BaseFee(t *testing.T) {
 	_, err = l2GasOracleABI.Pack("setL2BaseFee", baseFee)
 	assert.NoError(err)
 }
+
+func TestScrollChainErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	scrollChainError, ok := ScrollChainABI.Errors["ErrorBatchIsAlreadyVerified"]
+	assert.True(ok)
+	assert.Equal(common.FromHex("0x92d31550"), scrollChainError.ID.Bytes()[:4])
+}
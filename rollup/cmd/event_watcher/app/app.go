@@ -8,12 +8,12 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/urfave/cli/v2"
 
 	"scroll-tech/common/database"
 	"scroll-tech/common/observability"
+	"scroll-tech/common/rpcpool"
 	"scroll-tech/common/utils"
 	"scroll-tech/common/version"
 
@@ -62,12 +62,13 @@ func action(ctx *cli.Context) error {
 
 	registry := prometheus.DefaultRegisterer
 	observability.Server(ctx, db)
-	l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
+	l1client, err := rpcpool.DialEthClient(subCtx, cfg.L1Config.Endpoint, cfg.L1Config.RPCPool)
 	if err != nil {
 		log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
 	}
+	observability.AddReadinessCheck("l1geth", observability.BlockNumberCheck(l1client))
 
-	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations,
+	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations, cfg.L1Config.EventConfirmations,
 		cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
 
 	go utils.Loop(subCtx, 10*time.Second, func() {
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rpc"
@@ -16,6 +15,7 @@ import (
 
 	"scroll-tech/common/database"
 	"scroll-tech/common/observability"
+	"scroll-tech/common/rpcpool"
 	"scroll-tech/common/utils"
 	"scroll-tech/common/version"
 
@@ -67,13 +67,13 @@ func action(ctx *cli.Context) error {
 	registry := prometheus.DefaultRegisterer
 	observability.Server(ctx, db)
 
-	l1client, err := ethclient.Dial(cfg.L1Config.Endpoint)
+	l1client, err := rpcpool.DialEthClient(subCtx, cfg.L1Config.Endpoint, cfg.L1Config.RPCPool)
 	if err != nil {
 		log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", err)
 	}
 
 	// Init l2geth connection
-	l2client, err := ethclient.Dial(cfg.L2Config.Endpoint)
+	l2client, err := rpcpool.DialEthClient(subCtx, cfg.L2Config.Endpoint, cfg.L2Config.RPCPool)
 	if err != nil {
 		log.Crit("failed to connect l2 geth", "config file", cfgFile, "error", err)
 	}
@@ -84,16 +84,20 @@ func action(ctx *cli.Context) error {
 		log.Crit("failed to read genesis", "genesis file", genesisPath, "error", err)
 	}
 
-	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations, cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
+	l1watcher := watcher.NewL1WatcherClient(ctx.Context, l1client, cfg.L1Config.StartHeight, cfg.L1Config.Confirmations, cfg.L1Config.EventConfirmations, cfg.L1Config.L1MessageQueueAddress, cfg.L1Config.ScrollChainContractAddress, db, registry)
 
 	l1relayer, err := relayer.NewLayer1Relayer(ctx.Context, db, cfg.L1Config.RelayerConfig, genesis.Config, relayer.ServiceTypeL1GasOracle, registry)
 	if err != nil {
 		log.Crit("failed to create new l1 relayer", "config file", cfgFile, "error", err)
 	}
-	l2relayer, err := relayer.NewLayer2Relayer(ctx.Context, l2client, db, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, false /* initGenesis */, relayer.ServiceTypeL2GasOracle, registry)
+	l2relayer, err := relayer.NewLayer2Relayer(ctx.Context, l2client, db, nil, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, false /* initGenesis */, relayer.ServiceTypeL2GasOracle, registry)
 	if err != nil {
 		log.Crit("failed to create new l2 relayer", "config file", cfgFile, "error", err)
 	}
+	observability.AddReadinessCheck("l1geth", observability.BlockNumberCheck(l1client))
+	observability.AddReadinessCheck("l2geth", observability.BlockNumberCheck(l2client))
+	observability.AddReadinessCheck("l1_relayer_senders", l1relayer.CheckSenders)
+	observability.AddReadinessCheck("l2_relayer_senders", l2relayer.CheckSenders)
 	// Start l1 watcher process
 	go utils.LoopWithContext(subCtx, 10*time.Second, func(ctx context.Context) {
 		// Fetch the latest block number to decrease the delay when fetching gas prices
@@ -114,6 +118,15 @@ func action(ctx *cli.Context) error {
 	go utils.Loop(subCtx, 10*time.Second, l1relayer.ProcessGasPriceOracle)
 	go utils.Loop(subCtx, 2*time.Second, l2relayer.ProcessGasPriceOracle)
 
+	if feeScalarCfg := cfg.L1Config.RelayerConfig.GasOracleConfig.FeeScalar; feeScalarCfg != nil {
+		l1relayer.WithFeeScalarOracle(relayer.NewFeeScalarOracle(subCtx, feeScalarCfg, l1client, db))
+		interval := feeScalarCfg.UpdateIntervalSec
+		if interval == 0 {
+			interval = relayer.DefaultFeeScalarUpdateIntervalSec
+		}
+		go utils.Loop(subCtx, time.Duration(interval)*time.Second, l1relayer.ProcessFeeScalarOracle)
+	}
+
 	// Finish start all message relayer functions
 	log.Info("Start gas-oracle successfully", "version", version.Version)
 
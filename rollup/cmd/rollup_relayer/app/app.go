@@ -13,11 +13,14 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"scroll-tech/common/database"
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/observability"
+	"scroll-tech/common/rpcpool"
 	"scroll-tech/common/utils"
 	"scroll-tech/common/version"
 
 	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/admin"
 	"scroll-tech/rollup/internal/controller/relayer"
 	"scroll-tech/rollup/internal/controller/watcher"
 	butils "scroll-tech/rollup/internal/utils"
@@ -66,11 +69,32 @@ func action(ctx *cli.Context) error {
 	registry := prometheus.DefaultRegisterer
 	observability.Server(ctx, db)
 
+	finalityTracker := watcher.NewFinalityTracker(subCtx, db, registry)
+	go utils.Loop(subCtx, 5*time.Second, finalityTracker.TryUpdate)
+
 	// Init l2geth connection
-	l2client, err := ethclient.Dial(cfg.L2Config.Endpoint)
+	l2RPCClient, err := rpcpool.Dial(subCtx, cfg.L2Config.Endpoint, cfg.L2Config.RPCPool)
 	if err != nil {
 		log.Crit("failed to connect l2 geth", "config file", cfgFile, "error", err)
 	}
+	l2client := ethclient.NewClient(l2RPCClient)
+
+	if cfg.Admin != nil {
+		var archiveStore objectstore.Store
+		if cfg.L2Config.PrunerConfig != nil {
+			if archiveStore, err = objectstore.New(cfg.L2Config.PrunerConfig.Archive); err != nil {
+				log.Crit("failed to create pruner archive store", "config file", cfgFile, "error", err)
+			}
+		}
+		var gasEstimator *relayer.L1MessageGasEstimator
+		if cfg.L2Config.L1MessageGasEstimatorConfig != nil {
+			gasEstimator = relayer.NewL1MessageGasEstimator(cfg.L2Config.L1MessageGasEstimatorConfig, l2RPCClient,
+				cfg.L1Config.L1ScrollMessengerAddress, cfg.L2Config.L2ScrollMessengerAddress, registry)
+		}
+		if err = admin.Server(subCtx, cfg.Admin, cfg.L2Config.RelayerConfig.FinalizeApproval, gasEstimator, finalityTracker, archiveStore, db); err != nil {
+			log.Crit("failed to start admin server", "config file", cfgFile, "error", err)
+		}
+	}
 
 	genesisPath := ctx.String(utils.Genesis.Name)
 	genesis, err := utils.ReadGenesis(genesisPath)
@@ -78,11 +102,18 @@ func action(ctx *cli.Context) error {
 		log.Crit("failed to read genesis", "genesis file", genesisPath, "error", err)
 	}
 
+	proofStore, err := objectstore.New(cfg.DBConfig.ProofStore)
+	if err != nil {
+		log.Crit("failed to create proof store", "config file", cfgFile, "error", err)
+	}
+
 	initGenesis := ctx.Bool(utils.ImportGenesisFlag.Name)
-	l2relayer, err := relayer.NewLayer2Relayer(ctx.Context, l2client, db, cfg.L2Config.RelayerConfig, genesis.Config, initGenesis, relayer.ServiceTypeL2RollupRelayer, registry)
+	l2relayer, err := relayer.NewLayer2Relayer(ctx.Context, l2client, db, proofStore, cfg.L2Config.RelayerConfig, genesis.Config, initGenesis, relayer.ServiceTypeL2RollupRelayer, registry)
 	if err != nil {
 		log.Crit("failed to create l2 relayer", "config file", cfgFile, "error", err)
 	}
+	observability.AddReadinessCheck("l2geth", observability.BlockNumberCheck(l2client))
+	observability.AddReadinessCheck("senders", l2relayer.CheckSenders)
 
 	chunkProposer := watcher.NewChunkProposer(subCtx, cfg.L2Config.ChunkProposerConfig, genesis.Config, db, registry)
 	if err != nil {
@@ -94,7 +125,7 @@ func action(ctx *cli.Context) error {
 		log.Crit("failed to create batchProposer", "config file", cfgFile, "error", err)
 	}
 
-	l2watcher := watcher.NewL2WatcherClient(subCtx, l2client, cfg.L2Config.Confirmations, cfg.L2Config.L2MessageQueueAddress, cfg.L2Config.WithdrawTrieRootSlot, db, registry)
+	l2watcher := watcher.NewL2WatcherClient(subCtx, l2client, cfg.L2Config.Confirmations, cfg.L2Config.L2MessageQueueAddress, cfg.L2Config.WithdrawTrieRootSlot, cfg.L2Config.L2ScrollMessengerAddress, cfg.L2Config.UnsupportedOpcodes, cfg.L2Config.TraceSchema, cfg.L2Config.BlockFetcherConfig, db, registry)
 
 	// Watcher loop to fetch missing blocks
 	go utils.LoopWithContext(subCtx, 2*time.Second, func(ctx context.Context) {
@@ -114,6 +145,55 @@ func action(ctx *cli.Context) error {
 
 	go utils.Loop(subCtx, 15*time.Second, l2relayer.ProcessCommittedBatches)
 
+	if cfg.L2Config.PrunerConfig != nil {
+		pruner, prunerErr := watcher.NewPruner(subCtx, cfg.L2Config.PrunerConfig, db, registry)
+		if prunerErr != nil {
+			log.Crit("failed to create pruner", "config file", cfgFile, "error", prunerErr)
+		}
+		go utils.Loop(subCtx, time.Minute, pruner.TryPrune)
+	}
+
+	if cfg.L2Config.BatchNotifierConfig != nil {
+		batchNotifier, notifierErr := watcher.NewBatchNotifier(subCtx, cfg.L2Config.BatchNotifierConfig, db, registry)
+		if notifierErr != nil {
+			log.Crit("failed to create batch notifier", "config file", cfgFile, "error", notifierErr)
+		}
+		go utils.Loop(subCtx, 5*time.Second, batchNotifier.TryNotify)
+	}
+
+	if cfg.L2Config.EventExporterConfig != nil {
+		eventExporter, exporterErr := watcher.NewEventExporter(subCtx, cfg.L2Config.EventExporterConfig, db, registry)
+		if exporterErr != nil {
+			log.Crit("failed to create event exporter", "config file", cfgFile, "error", exporterErr)
+		}
+		go utils.Loop(subCtx, 2*time.Second, eventExporter.TryExport)
+	}
+
+	if cfg.L2Config.BatchDACheckerConfig != nil {
+		l1client, dialErr := rpcpool.DialEthClient(subCtx, cfg.L1Config.Endpoint, cfg.L1Config.RPCPool)
+		if dialErr != nil {
+			log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", dialErr)
+		}
+		batchDAChecker := relayer.NewBatchDAChecker(subCtx, cfg.L2Config.BatchDACheckerConfig, l1client, db, genesis.Config, registry)
+		go utils.Loop(subCtx, 15*time.Second, batchDAChecker.TryCheckBatches)
+	}
+
+	if cfg.FundChecker != nil {
+		l1client, dialErr := rpcpool.DialEthClient(subCtx, cfg.L1Config.Endpoint, cfg.L1Config.RPCPool)
+		if dialErr != nil {
+			log.Crit("failed to connect l1 geth", "config file", cfgFile, "error", dialErr)
+		}
+		fundChecker, fundCheckerErr := watcher.NewFundChecker(subCtx, cfg.FundChecker, l1client, l2client, registry)
+		if fundCheckerErr != nil {
+			log.Crit("failed to create fund checker", "config file", cfgFile, "error", fundCheckerErr)
+		}
+		interval := cfg.FundChecker.CheckIntervalSec
+		if interval == 0 {
+			interval = watcher.DefaultFundCheckIntervalSec
+		}
+		go utils.Loop(subCtx, time.Duration(interval)*time.Second, fundChecker.TryCheckFunds)
+	}
+
 	// Finish start all rollup relayer functions.
 	log.Info("Start rollup-relayer successfully", "version", version.Version)
 
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"scroll-tech/common/utils"
+	"scroll-tech/common/version"
+)
+
+var app *cli.App
+
+func init() {
+	// Set up scroll-cli app info.
+	app = cli.NewApp()
+	app.Name = "scroll-cli"
+	app.Usage = "The Scroll Rollup debugging CLI"
+	app.Version = version.Version
+	app.Flags = append(app.Flags, utils.CommonFlags...)
+	app.Before = func(ctx *cli.Context) error {
+		return utils.LogSetup(ctx)
+	}
+
+	app.Commands = []*cli.Command{
+		{
+			Name:  "batch",
+			Usage: "Inspect the batches of the rollup.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "inspect",
+					Usage:     "Recompute the hashes of a batch from its blocks and diff them against the database and the proofs.",
+					ArgsUsage: "<batch-index>",
+					Action:    inspectBatch,
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "from-l1",
+							Usage: "Also diff the chunks against the calldata of the batch's commit transaction on L1.",
+						},
+					},
+				},
+				{
+					Name:      "history",
+					Usage:     "Print the status transitions of a batch and its chunks, when and by whom they happened.",
+					ArgsUsage: "<batch-index>",
+					Action:    batchHistory,
+				},
+			},
+		},
+		{
+			Name:  "message",
+			Usage: "Manage the l1 messages skipped or failed relaying on l2.",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "replay",
+					Usage:     "Replay a skipped l1 message through the L1ScrollMessenger with a new gas limit.",
+					ArgsUsage: "<queue-index>",
+					Action:    replayMessage,
+					Flags: []cli.Flag{
+						&cli.Uint64Flag{
+							Name:  "gas-limit",
+							Usage: "The new l2 gas limit of the message, estimated by simulating the message on l2geth if unset.",
+						},
+						&cli.StringFlag{
+							Name:     "private-key",
+							Usage:    "The hex private key of the l1 account paying the replay fee.",
+							EnvVars:  []string{"SCROLL_CLI_PRIVATE_KEY"},
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:  "refund-address",
+							Usage: "The address refunded the excess fee, the paying account by default.",
+						},
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Replay the message even if it was already replayed.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Run the scroll-cli.
+func Run() {
+	if err := app.Run(os.Args); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
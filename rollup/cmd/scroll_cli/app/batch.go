@@ -0,0 +1,339 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/scroll-tech/da-codec/encoding/codecv0"
+	"github.com/scroll-tech/da-codec/encoding/codecv1"
+	"github.com/scroll-tech/da-codec/encoding/codecv2"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/objectstore"
+	cencoding "scroll-tech/common/types/encoding"
+	"scroll-tech/common/types/message"
+	"scroll-tech/common/utils"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+	rutils "scroll-tech/rollup/internal/utils"
+)
+
+// batchInspector recomputes the hashes of a batch from the blocks kept in the database,
+// and prints them next to what the database, the provers and L1 hold.
+type batchInspector struct {
+	ctx        context.Context
+	chainID    uint64
+	proofStore objectstore.Store
+
+	batchOrm   *orm.Batch
+	chunkOrm   *orm.Chunk
+	l2BlockOrm *orm.L2Block
+
+	mismatches int
+}
+
+func inspectBatch(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("expected exactly one argument: <batch-index>")
+	}
+	index, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid batch index %s: %w", ctx.Args().First(), err)
+	}
+	if index == 0 {
+		return fmt.Errorf("the genesis batch has no chunks to inspect")
+	}
+
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config file %s: %w", cfgFile, err)
+	}
+	genesisPath := ctx.String(utils.Genesis.Name)
+	genesis, err := utils.ReadGenesis(genesisPath)
+	if err != nil {
+		return fmt.Errorf("failed to read genesis %s: %w", genesisPath, err)
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to init db connection: %w", err)
+	}
+	defer func() {
+		_ = database.CloseDB(db)
+	}()
+
+	proofStore, err := objectstore.New(cfg.DBConfig.ProofStore)
+	if err != nil {
+		return fmt.Errorf("failed to create proof store: %w", err)
+	}
+
+	i := newBatchInspector(ctx.Context, genesis.Config.ChainID.Uint64(), db, proofStore)
+	var l1Client *ethclient.Client
+	if ctx.Bool("from-l1") {
+		if l1Client, err = ethclient.Dial(cfg.L1Config.Endpoint); err != nil {
+			return fmt.Errorf("failed to connect l1 geth: %w", err)
+		}
+		defer l1Client.Close()
+	}
+	if err = i.inspect(index, l1Client); err != nil {
+		return err
+	}
+
+	if i.mismatches > 0 {
+		return fmt.Errorf("batch %d: found %d mismatches", index, i.mismatches)
+	}
+	fmt.Printf("batch %d: no mismatch found\n", index)
+	return nil
+}
+
+func newBatchInspector(ctx context.Context, chainID uint64, db *gorm.DB, proofStore objectstore.Store) *batchInspector {
+	return &batchInspector{
+		ctx:        ctx,
+		chainID:    chainID,
+		proofStore: proofStore,
+		batchOrm:   orm.NewBatch(db),
+		chunkOrm:   orm.NewChunk(db),
+		l2BlockOrm: orm.NewL2Block(db),
+	}
+}
+
+func (i *batchInspector) inspect(index uint64, l1Client *ethclient.Client) error {
+	dbBatch, err := i.batchOrm.GetBatchByIndex(i.ctx, index)
+	if err != nil {
+		return fmt.Errorf("failed to get batch %d: %w", index, err)
+	}
+	dbParentBatch, err := i.batchOrm.GetBatchByIndex(i.ctx, index-1)
+	if err != nil {
+		return fmt.Errorf("failed to get parent batch %d: %w", index-1, err)
+	}
+	if len(dbBatch.BatchHeader) == 0 {
+		return fmt.Errorf("batch %d has no batch header", index)
+	}
+	// The first byte of the batch header is always the codec version.
+	codecVersion := encoding.CodecVersion(dbBatch.BatchHeader[0])
+
+	dbChunks, err := i.chunkOrm.GetChunksInRange(i.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	if err != nil {
+		return fmt.Errorf("failed to get chunks of batch %d: %w", index, err)
+	}
+	chunks := make([]*encoding.Chunk, len(dbChunks))
+	for j, dbChunk := range dbChunks {
+		blocks, getErr := i.l2BlockOrm.GetL2BlocksInRange(i.ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber)
+		if getErr != nil {
+			return fmt.Errorf("failed to get blocks of chunk %d: %w", dbChunk.Index, getErr)
+		}
+		chunks[j] = &encoding.Chunk{Blocks: blocks}
+	}
+
+	fmt.Printf("batch %d: codec v%d, chunks %d-%d\n", index, codecVersion, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	batch := &encoding.Batch{
+		Index:                      dbBatch.Index,
+		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
+		ParentBatchHash:            common.HexToHash(dbParentBatch.Hash),
+		Chunks:                     chunks,
+	}
+	batchMeta, err := rutils.GetBatchMetadata(batch, codecVersion)
+	if err != nil {
+		return fmt.Errorf("failed to recompute batch %d: %w", index, err)
+	}
+	i.compare("batch hash", batchMeta.BatchHash.Hex(), dbBatch.Hash)
+	i.compare("data hash", batchMeta.BatchDataHash.Hex(), dbBatch.DataHash)
+	i.compare("start chunk hash", batchMeta.StartChunkHash.Hex(), dbBatch.StartChunkHash)
+	i.compare("end chunk hash", batchMeta.EndChunkHash.Hex(), dbBatch.EndChunkHash)
+	i.compare("batch header", common.Bytes2Hex(batchMeta.BatchBytes), common.Bytes2Hex(dbBatch.BatchHeader))
+
+	for j, dbChunk := range dbChunks {
+		if err = i.inspectChunk(dbChunk, chunks[j], codecVersion); err != nil {
+			return err
+		}
+	}
+
+	lastBlock := chunks[len(chunks)-1].Blocks[len(chunks[len(chunks)-1].Blocks)-1]
+	piHash, err := rutils.GetBatchPublicInputHash(i.chainID, common.HexToHash(dbParentBatch.StateRoot), lastBlock.Header.Root,
+		lastBlock.WithdrawRoot, batchMeta.BatchDataHash, batchMeta.BatchBlobDataProof, batchMeta.BlobVersionedHash)
+	if err != nil {
+		return fmt.Errorf("failed to compute public input hash of batch %d: %w", index, err)
+	}
+	fmt.Printf("batch %d: public input hash %s\n", index, piHash.Hex())
+	i.compare("state root", lastBlock.Header.Root.Hex(), dbBatch.StateRoot)
+	i.compare("withdraw root", lastBlock.WithdrawRoot.Hex(), dbBatch.WithdrawRoot)
+
+	proofBytes, err := i.loadProof(dbBatch.Proof, dbBatch.ProofURI, dbBatch.ProofHash)
+	if err != nil {
+		return fmt.Errorf("failed to load proof of batch %d: %w", index, err)
+	}
+	if proofBytes != nil {
+		var proof message.BatchProof
+		if err = json.Unmarshal(proofBytes, &proof); err != nil {
+			return fmt.Errorf("failed to unmarshal proof of batch %d: %w", index, err)
+		}
+		proofPIHash, getErr := rutils.GetPublicInputHashFromInstances(proof.Instances)
+		if getErr != nil {
+			return fmt.Errorf("failed to get public input hash from proof of batch %d: %w", index, getErr)
+		}
+		i.compare("proof public input hash", piHash.Hex(), proofPIHash.Hex())
+	} else {
+		fmt.Printf("batch %d: no proof to diff against\n", index)
+	}
+
+	if l1Client != nil {
+		return i.inspectCommitCalldata(l1Client, dbBatch, dbParentBatch, dbChunks, chunks, batchMeta, codecVersion)
+	}
+	return nil
+}
+
+func (i *batchInspector) inspectChunk(dbChunk *orm.Chunk, chunk *encoding.Chunk, codecVersion encoding.CodecVersion) error {
+	fmt.Printf("chunk %d: blocks %d-%d\n", dbChunk.Index, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber)
+
+	chunkHash, err := rutils.GetChunkHash(chunk, dbChunk.TotalL1MessagesPoppedBefore, codecVersion)
+	if err != nil {
+		return fmt.Errorf("failed to recompute chunk %d: %w", dbChunk.Index, err)
+	}
+	lastBlock := chunk.Blocks[len(chunk.Blocks)-1]
+	i.compare("chunk hash", chunkHash.Hex(), dbChunk.Hash)
+	i.compare("state root", lastBlock.Header.Root.Hex(), dbChunk.StateRoot)
+	i.compare("withdraw root", lastBlock.WithdrawRoot.Hex(), dbChunk.WithdrawRoot)
+
+	proofBytes, err := i.loadProof(dbChunk.Proof, dbChunk.ProofURI, dbChunk.ProofHash)
+	if err != nil {
+		return fmt.Errorf("failed to load proof of chunk %d: %w", dbChunk.Index, err)
+	}
+	var proof message.ChunkProof
+	if proofBytes != nil {
+		if err = json.Unmarshal(proofBytes, &proof); err != nil {
+			return fmt.Errorf("failed to unmarshal proof of chunk %d: %w", dbChunk.Index, err)
+		}
+	}
+	if proof.ChunkInfo == nil {
+		fmt.Printf("chunk %d: no chunk info reported by the prover to diff against\n", dbChunk.Index)
+		return nil
+	}
+
+	// The chunk info is what the prover built the public input hash of the chunk from.
+	i.compare("prover chain id", strconv.FormatUint(i.chainID, 10), strconv.FormatUint(proof.ChunkInfo.ChainID, 10))
+	i.compare("prover prev state root", common.HexToHash(dbChunk.ParentChunkStateRoot).Hex(), proof.ChunkInfo.PrevStateRoot.Hex())
+	i.compare("prover post state root", lastBlock.Header.Root.Hex(), proof.ChunkInfo.PostStateRoot.Hex())
+	i.compare("prover withdraw root", lastBlock.WithdrawRoot.Hex(), proof.ChunkInfo.WithdrawRoot.Hex())
+	i.compare("prover data hash", chunkHash.Hex(), proof.ChunkInfo.DataHash.Hex())
+	return nil
+}
+
+// inspectCommitCalldata diffs the chunks recomputed from the database against the calldata of the commit transaction.
+func (i *batchInspector) inspectCommitCalldata(l1Client *ethclient.Client, dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk, batchMeta *rutils.BatchMetadata, codecVersion encoding.CodecVersion) error {
+	if dbBatch.CommitTxHash == "" {
+		return fmt.Errorf("batch %d has no commit tx to diff against", dbBatch.Index)
+	}
+	tx, _, err := l1Client.TransactionByHash(i.ctx, common.HexToHash(dbBatch.CommitTxHash))
+	if err != nil {
+		return fmt.Errorf("failed to get commit tx %s: %w", dbBatch.CommitTxHash, err)
+	}
+	fmt.Printf("batch %d: commit tx %s\n", dbBatch.Index, dbBatch.CommitTxHash)
+
+	version, parentBatchHeader, l1Chunks, skippedL1MessageBitmap, err := decodeCommitCalldata(tx.Data())
+	if err != nil {
+		return fmt.Errorf("failed to decode commit tx %s: %w", dbBatch.CommitTxHash, err)
+	}
+
+	i.compare("l1 version", strconv.Itoa(int(codecVersion)), strconv.Itoa(int(version)))
+	i.compare("l1 parent batch header", common.Bytes2Hex(dbParentBatch.BatchHeader), common.Bytes2Hex(parentBatchHeader))
+	batchHeader, err := cencoding.DecodeBatchHeader(batchMeta.BatchBytes)
+	if err != nil {
+		return fmt.Errorf("failed to decode batch header of batch %d: %w", dbBatch.Index, err)
+	}
+	i.compare("l1 skipped message bitmap", common.Bytes2Hex(batchHeader.SkippedL1MessageBitmap), common.Bytes2Hex(skippedL1MessageBitmap))
+	i.compare("l1 chunk count", strconv.Itoa(len(chunks)), strconv.Itoa(len(l1Chunks)))
+
+	for j := 0; j < len(chunks) && j < len(l1Chunks); j++ {
+		encoded, encodeErr := encodeChunk(chunks[j], dbChunks[j].TotalL1MessagesPoppedBefore, codecVersion)
+		if encodeErr != nil {
+			return fmt.Errorf("failed to encode chunk %d: %w", dbChunks[j].Index, encodeErr)
+		}
+		if bytes.Equal(encoded, l1Chunks[j]) {
+			continue
+		}
+		// Every block context starts with the block number, point at the first block that differs.
+		diff := fmt.Sprintf("%d bytes", len(l1Chunks[j]))
+		for k, block := range chunks[j].Blocks {
+			offset := 1 + k*cencoding.BlockContextSize
+			if offset+cencoding.BlockContextSize > len(l1Chunks[j]) || !bytes.Equal(encoded[offset:offset+cencoding.BlockContextSize], l1Chunks[j][offset:offset+cencoding.BlockContextSize]) {
+				diff = fmt.Sprintf("first differing block context: %d", block.Header.Number.Uint64())
+				break
+			}
+		}
+		i.compare(fmt.Sprintf("l1 chunk %d", dbChunks[j].Index), fmt.Sprintf("%d bytes", len(encoded)), diff)
+	}
+	return nil
+}
+
+// loadProof reads the proof kept in the database or the proof store, it returns nil if there is none.
+func (i *batchInspector) loadProof(inline []byte, uri, hash string) ([]byte, error) {
+	if uri != "" {
+		return objectstore.Download(i.ctx, i.proofStore, uri, hash)
+	}
+	return inline, nil
+}
+
+// compare prints the recomputed value next to the one it's checked against, and counts the mismatches.
+func (i *batchInspector) compare(name, recomputed, reported string) {
+	if recomputed == reported {
+		fmt.Printf("  %-28s %s\n", name, recomputed)
+		return
+	}
+	i.mismatches++
+	fmt.Printf("  %-28s MISMATCH recomputed %s, reported %s\n", name, recomputed, reported)
+}
+
+// decodeCommitCalldata decodes the arguments of the commitBatch transaction of the batch.
+func decodeCommitCalldata(data []byte) (uint8, []byte, [][]byte, []byte, error) {
+	if len(data) < 4 {
+		return 0, nil, nil, nil, fmt.Errorf("invalid calldata length: %d", len(data))
+	}
+	method, err := bridgeAbi.ScrollChainABI.MethodById(data[:4])
+	if err != nil {
+		return 0, nil, nil, nil, err
+	}
+	if method.Name != "commitBatch" {
+		return 0, nil, nil, nil, fmt.Errorf("unexpected method %s", method.Name)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return 0, nil, nil, nil, err
+	}
+	return args[0].(uint8), args[1].([]byte), args[2].([][]byte), args[3].([]byte), nil
+}
+
+func encodeChunk(chunk *encoding.Chunk, totalL1MessagePoppedBefore uint64, codecVersion encoding.CodecVersion) ([]byte, error) {
+	switch codecVersion {
+	case encoding.CodecV0:
+		daChunk, err := codecv0.NewDAChunk(chunk, totalL1MessagePoppedBefore)
+		if err != nil {
+			return nil, err
+		}
+		return daChunk.Encode()
+	case encoding.CodecV1:
+		daChunk, err := codecv1.NewDAChunk(chunk, totalL1MessagePoppedBefore)
+		if err != nil {
+			return nil, err
+		}
+		return daChunk.Encode(), nil
+	case encoding.CodecV2:
+		daChunk, err := codecv2.NewDAChunk(chunk, totalL1MessagePoppedBefore)
+		if err != nil {
+			return nil, err
+		}
+		return daChunk.Encode(), nil
+	default:
+		return nil, fmt.Errorf("unsupported codec version: %v", codecVersion)
+	}
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// batchHistory prints the status transitions of a batch and of its chunks, as recorded in the status audit log.
+func batchHistory(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("expected exactly one argument: <batch-index>")
+	}
+	index, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid batch index %s: %w", ctx.Args().First(), err)
+	}
+
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config file %s: %w", cfgFile, err)
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to init db connection: %w", err)
+	}
+	defer func() {
+		_ = database.CloseDB(db)
+	}()
+
+	dbBatch, err := orm.NewBatch(db).GetBatchByIndex(ctx.Context, index)
+	if err != nil {
+		return fmt.Errorf("failed to get batch %d: %w", index, err)
+	}
+	dbChunks, err := orm.NewChunk(db).GetChunksInRange(ctx.Context, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	if err != nil {
+		return fmt.Errorf("failed to get chunks of batch %d: %w", index, err)
+	}
+
+	statusAuditLogOrm := orm.NewStatusAuditLog(db)
+	batchLogs, err := statusAuditLogOrm.GetStatusAuditLogs(ctx.Context, "batch", []string{dbBatch.Hash})
+	if err != nil {
+		return fmt.Errorf("failed to get status history of batch %d: %w", index, err)
+	}
+	fmt.Printf("batch %d: %s, created at %s\n", index, dbBatch.Hash, dbBatch.CreatedAt.UTC().Format("2006-01-02 15:04:05"))
+	printStatusAuditLogs(batchLogs)
+
+	for _, dbChunk := range dbChunks {
+		chunkLogs, getErr := statusAuditLogOrm.GetStatusAuditLogs(ctx.Context, "chunk", []string{dbChunk.Hash})
+		if getErr != nil {
+			return fmt.Errorf("failed to get status history of chunk %d: %w", dbChunk.Index, getErr)
+		}
+		fmt.Printf("chunk %d: %s, created at %s\n", dbChunk.Index, dbChunk.Hash, dbChunk.CreatedAt.UTC().Format("2006-01-02 15:04:05"))
+		printStatusAuditLogs(chunkLogs)
+	}
+	return nil
+}
+
+func printStatusAuditLogs(logs []*orm.StatusAuditLog) {
+	if len(logs) == 0 {
+		fmt.Println("  no status transition recorded")
+		return
+	}
+	for _, log := range logs {
+		from := "-"
+		if log.FromStatus != nil {
+			from = statusName(log.StatusColumn, *log.FromStatus)
+		}
+		fmt.Printf("  %s  %-20s %s -> %s  by %s\n", log.ChangedAt.UTC().Format("2006-01-02 15:04:05.000"), log.StatusColumn, from,
+			statusName(log.StatusColumn, log.ToStatus), log.Actor)
+	}
+}
+
+func statusName(statusColumn string, status int) string {
+	switch statusColumn {
+	case "proving_status":
+		return types.ProvingStatus(status).String()
+	case "rollup_status":
+		return types.RollupStatus(status).String()
+	case "chunk_proofs_status":
+		return types.ChunkProofsStatus(status).String()
+	default:
+		return strconv.Itoa(status)
+	}
+}
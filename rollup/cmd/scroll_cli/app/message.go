@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	geth "github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/utils"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/controller/sender"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// replayMessage replays a skipped l1 message through the L1ScrollMessenger with a new gas limit,
+// paying the fee of the new L1MessageQueue transaction.
+func replayMessage(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("expected exactly one argument: <queue-index>")
+	}
+	queueIndex, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid queue index %s: %w", ctx.Args().First(), err)
+	}
+	gasLimit := ctx.Uint64("gas-limit")
+	if ctx.IsSet("gas-limit") && (gasLimit == 0 || gasLimit > math.MaxUint32) {
+		return fmt.Errorf("invalid gas limit %d", gasLimit)
+	}
+	privKey, err := crypto.HexToECDSA(ctx.String("private-key"))
+	if err != nil {
+		return fmt.Errorf("invalid private key: %w", err)
+	}
+	signer := sender.NewPrivateKeySigner(privKey)
+	refundAddress := signer.Address()
+	if ctx.IsSet("refund-address") {
+		if !common.IsHexAddress(ctx.String("refund-address")) {
+			return fmt.Errorf("invalid refund address %s", ctx.String("refund-address"))
+		}
+		refundAddress = common.HexToAddress(ctx.String("refund-address"))
+	}
+
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config file %s: %w", cfgFile, err)
+	}
+	if cfg.L1Config.L1ScrollMessengerAddress == (common.Address{}) {
+		return errors.New("l1_scroll_messenger_address is not configured")
+	}
+
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		return fmt.Errorf("failed to init db connection: %w", err)
+	}
+	defer func() {
+		_ = database.CloseDB(db)
+	}()
+
+	skippedMessageOrm := orm.NewSkippedMessage(db)
+	skippedMessage, err := skippedMessageOrm.GetSkippedMessageByQueueIndex(ctx.Context, queueIndex)
+	if err != nil {
+		return err
+	}
+	if skippedMessage == nil {
+		return fmt.Errorf("l1 message %d is not recorded as skipped", queueIndex)
+	}
+	if skippedMessage.ReplayTxHash != "" && !ctx.Bool("force") {
+		return fmt.Errorf("l1 message %d was already replayed in tx %s with gas limit %d, use --force to replay it again",
+			queueIndex, skippedMessage.ReplayTxHash, skippedMessage.ReplayGasLimit)
+	}
+
+	l1Message, err := orm.NewL1Message(db).GetL1MessageByQueueIndex(ctx.Context, queueIndex)
+	if err != nil {
+		return err
+	}
+	if l1Message == nil {
+		return fmt.Errorf("l1 message %d not found", queueIndex)
+	}
+	calldata := common.FromHex(l1Message.Calldata)
+	msg, err := relayer.DecodeRelayMessage(calldata)
+	if err != nil {
+		return fmt.Errorf("failed to decode l1 message %d: %w", queueIndex, err)
+	}
+	if gasLimit == 0 {
+		if gasLimit, err = estimateReplayGasLimit(ctx.Context, cfg, calldata); err != nil {
+			return fmt.Errorf("failed to estimate the gas limit of l1 message %d, set it with --gas-limit: %w", queueIndex, err)
+		}
+		fmt.Printf("l1 message %d needs gas limit %d on l2\n", queueIndex, gasLimit)
+	}
+
+	l1Client, err := ethclient.Dial(cfg.L1Config.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to connect l1 geth: %w", err)
+	}
+	defer l1Client.Close()
+
+	fee, err := estimateCrossDomainMessageFee(ctx.Context, l1Client, cfg.L1Config.L1MessageQueueAddress, gasLimit)
+	if err != nil {
+		return err
+	}
+	data, err := bridgeAbi.L1ScrollMessengerABI.Pack("replayMessage", msg.From, msg.To, msg.Value, new(big.Int).SetUint64(queueIndex),
+		msg.Message, uint32(gasLimit), refundAddress)
+	if err != nil {
+		return fmt.Errorf("failed to pack replayMessage: %w", err)
+	}
+
+	tx, err := sendReplayTx(ctx.Context, l1Client, signer, cfg.L1Config.L1ScrollMessengerAddress, fee, data)
+	if err != nil {
+		return err
+	}
+	if err = skippedMessageOrm.UpdateReplay(ctx.Context, queueIndex, tx.Hash().String(), gasLimit); err != nil {
+		return fmt.Errorf("replay tx %s sent, but failed to record it: %w", tx.Hash().String(), err)
+	}
+	fmt.Printf("l1 message %d replayed with gas limit %d and fee %s wei, tx: %s\n", queueIndex, gasLimit, fee.String(), tx.Hash().String())
+	return nil
+}
+
+// estimateReplayGasLimit simulates the l1 message on l2geth to find the gas limit it needs.
+func estimateReplayGasLimit(ctx context.Context, cfg *config.Config, calldata []byte) (uint64, error) {
+	if cfg.L2Config.L2ScrollMessengerAddress == (common.Address{}) {
+		return 0, errors.New("l2_scroll_messenger_address is not configured")
+	}
+	l2Client, err := rpc.DialContext(ctx, cfg.L2Config.Endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect l2 geth: %w", err)
+	}
+	defer l2Client.Close()
+
+	estimator := relayer.NewL1MessageGasEstimator(cfg.L2Config.L1MessageGasEstimatorConfig, l2Client, cfg.L1Config.L1ScrollMessengerAddress,
+		cfg.L2Config.L2ScrollMessengerAddress, prometheus.NewRegistry())
+	return estimator.EstimateGasLimit(ctx, calldata)
+}
+
+func estimateCrossDomainMessageFee(ctx context.Context, client *ethclient.Client, messageQueueAddress common.Address, gasLimit uint64) (*big.Int, error) {
+	data, err := bridgeAbi.L1MessageQueueABI.Pack("estimateCrossDomainMessageFee", new(big.Int).SetUint64(gasLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack estimateCrossDomainMessageFee: %w", err)
+	}
+	result, err := client.CallContract(ctx, geth.CallMsg{To: &messageQueueAddress, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call estimateCrossDomainMessageFee: %w", err)
+	}
+	outputs, err := bridgeAbi.L1MessageQueueABI.Unpack("estimateCrossDomainMessageFee", result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack estimateCrossDomainMessageFee: %w", err)
+	}
+	fee, ok := outputs[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected estimateCrossDomainMessageFee output %v", outputs[0])
+	}
+	return fee, nil
+}
+
+func sendReplayTx(ctx context.Context, client *ethclient.Client, signer sender.Signer, to common.Address, value *big.Int, data []byte) (*gethTypes.Transaction, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain id: %w", err)
+	}
+	nonce, err := client.PendingNonceAt(ctx, signer.Address())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if head.BaseFee == nil {
+		return nil, errors.New("l1 chain does not support dynamic fee transactions")
+	}
+	gasFeeCap := new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), gasTipCap)
+
+	gas, err := client.EstimateGas(ctx, geth.CallMsg{
+		From:      signer.Address(),
+		To:        &to,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Value:     value,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate replayMessage gas: %w", err)
+	}
+
+	tx, err := signer.SignTx(ctx, gethTypes.NewTx(&gethTypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gas,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	}), chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replayMessage tx: %w", err)
+	}
+	if err = client.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send replayMessage tx: %w", err)
+	}
+	return tx, nil
+}
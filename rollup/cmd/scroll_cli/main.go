@@ -0,0 +1,7 @@
+package main
+
+import "scroll-tech/rollup/cmd/scroll_cli/app"
+
+func main() {
+	app.Run()
+}
@@ -2,17 +2,60 @@ package config
 
 import (
 	"encoding/json"
+	"math/big"
 	"os"
 	"path/filepath"
 
+	"github.com/scroll-tech/go-ethereum/common"
+
 	"scroll-tech/common/database"
 )
 
+// AdminConfig the admin api of the rollup relayer
+type AdminConfig struct {
+	// Addr is the listening address of the admin api, e.g. "127.0.0.1:8560".
+	Addr string `json:"addr"`
+	// Secret is the bearer token required by the admin api.
+	Secret string `json:"secret"`
+}
+
+// FundCheckerConfig the balance monitor of the operational accounts
+type FundCheckerConfig struct {
+	// CheckIntervalSec is how often the balances are checked, default 60s.
+	CheckIntervalSec uint64 `json:"check_interval_sec,omitempty"`
+	// Accounts are the monitored accounts, e.g. the relayer senders, the gas oracle senders and the fee vault.
+	Accounts []*FundCheckerAccount `json:"accounts"`
+	// AlertWebhookURL receives a POST request with a json body when an account drops below its minimum balance,
+	// the body has a "text" field so a Slack incoming webhook can be used. Empty disables it.
+	AlertWebhookURL string `json:"alert_webhook_url,omitempty"`
+	// PagerDutyRoutingKey triggers a PagerDuty incident through the Events API v2 when an account drops
+	// below its minimum balance, and resolves it once the account is funded again. Empty disables it.
+	PagerDutyRoutingKey string `json:"pagerduty_routing_key,omitempty"`
+	// RealertIntervalSec is how often the alert of an account still below its minimum balance is repeated,
+	// default 1h.
+	RealertIntervalSec uint64 `json:"realert_interval_sec,omitempty"`
+}
+
+// FundCheckerAccount a monitored account
+type FundCheckerAccount struct {
+	// Name labels the balance metric and the alerts, e.g. "commit_sender".
+	Name string `json:"name"`
+	// Chain is "l1" or "l2".
+	Chain   string         `json:"chain"`
+	Address common.Address `json:"address"`
+	// MinBalance is the balance in wei below which the account is alerted on, nil only exports the balance.
+	MinBalance *big.Int `json:"min_balance,omitempty"`
+}
+
 // Config load configuration items.
 type Config struct {
 	L1Config *L1Config        `json:"l1_config"`
 	L2Config *L2Config        `json:"l2_config"`
 	DBConfig *database.Config `json:"db_config"`
+	// Admin enables the admin api of the rollup relayer when set.
+	Admin *AdminConfig `json:"admin,omitempty"`
+	// FundChecker monitors the balances of the operational accounts when set.
+	FundChecker *FundCheckerConfig `json:"fund_checker,omitempty"`
 }
 
 // NewConfig returns a new instance of Config.
@@ -28,5 +71,11 @@ func NewConfig(file string) (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.L2Config != nil {
+		if err = cfg.L2Config.applyProposerPreset(); err != nil {
+			return nil, err
+		}
+	}
+
 	return cfg, nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,6 +36,45 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, cfg.DBConfig, cfg2.DBConfig)
 	})
 
+	t.Run("Sender Pool Private Keys", func(t *testing.T) {
+		var relayerCfg RelayerConfig
+		input := `{"commit_sender_private_key": "1414141414141414141414141414141414141414141414141414141414141414", "commit_sender_private_keys": ["1616161616161616161616161616161616161616161616161616161616161616"]}`
+		assert.NoError(t, json.Unmarshal([]byte(input), &relayerCfg))
+		assert.Len(t, relayerCfg.CommitSenderPrivateKeys, 1)
+		assert.Len(t, relayerCfg.FinalizeSenderPrivateKeys, 0)
+
+		duplicated := `{"commit_sender_private_key": "1414141414141414141414141414141414141414141414141414141414141414", "finalize_sender_private_keys": ["1414141414141414141414141414141414141414141414141414141414141414"]}`
+		assert.Error(t, json.Unmarshal([]byte(duplicated), &relayerCfg))
+	})
+
+	t.Run("Proposer Preset", func(t *testing.T) {
+		l2Config := L2Config{
+			ProposerPreset:      "devnet",
+			ChunkProposerConfig: &ChunkProposerConfig{ChunkTimeoutSec: 60},
+			BatchProposerConfig: &BatchProposerConfig{},
+		}
+		assert.NoError(t, l2Config.applyProposerPreset())
+		assert.Equal(t, uint64(60), l2Config.ChunkProposerConfig.ChunkTimeoutSec)
+		assert.Equal(t, uint64(2), l2Config.ChunkProposerConfig.ProposeWhenIdleSec)
+		assert.Equal(t, uint64(10), l2Config.BatchProposerConfig.BatchTimeoutSec)
+		assert.Equal(t, uint64(2), l2Config.BatchProposerConfig.ProposeWhenIdleSec)
+
+		l2Config.ProposerPreset = "unknown"
+		assert.Error(t, l2Config.applyProposerPreset())
+	})
+
+	t.Run("Finalize Approval", func(t *testing.T) {
+		approvers := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+		assert.NoError(t, (&FinalizeApprovalConfig{Threshold: 2, Approvers: approvers}).Validate())
+		assert.Error(t, (&FinalizeApprovalConfig{Threshold: 0, Approvers: approvers}).Validate())
+		assert.Error(t, (&FinalizeApprovalConfig{Threshold: 3, Approvers: approvers}).Validate())
+		assert.Error(t, (&FinalizeApprovalConfig{Threshold: 1, Approvers: []common.Address{approvers[0], approvers[0]}}).Validate())
+
+		cfg := &FinalizeApprovalConfig{Threshold: 1, Approvers: approvers}
+		assert.True(t, cfg.IsApprover(common.HexToAddress("0x02")))
+		assert.False(t, cfg.IsApprover(common.HexToAddress("0x03")))
+	})
+
 	t.Run("File Not Found", func(t *testing.T) {
 		_, err := NewConfig("non_existent_file.json")
 		assert.ErrorIs(t, err, os.ErrNotExist)
@@ -3,20 +3,39 @@ package config
 import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/common/rpcpool"
 )
 
 // L1Config loads l1eth configuration items.
 type L1Config struct {
 	// Confirmations block height confirmations number.
 	Confirmations rpc.BlockNumber `json:"confirmations"`
+	// EventConfirmations overrides Confirmations for the events of a category.
+	EventConfirmations *L1EventConfirmations `json:"event_confirmations,omitempty"`
 	// l1 eth node url.
 	Endpoint string `json:"endpoint"`
+	// RPCPool spreads the requests over several nodes with health checks and failover, nil uses the endpoint alone.
+	RPCPool *rpcpool.Config `json:"rpc_pool,omitempty"`
 	// The start height to sync event from layer 1
 	StartHeight uint64 `json:"start_height"`
 	// The L1MessageQueue contract address deployed on layer 1 chain.
 	L1MessageQueueAddress common.Address `json:"l1_message_queue_address"`
+	// The L1ScrollMessenger contract address deployed on layer 1 chain, used to replay the skipped messages.
+	L1ScrollMessengerAddress common.Address `json:"l1_scroll_messenger_address,omitempty"`
 	// The ScrollChain contract address deployed on layer 1 chain.
 	ScrollChainContractAddress common.Address `json:"scroll_chain_address"`
 	// The relayer config
 	RelayerConfig *RelayerConfig `json:"relayer_config"`
 }
+
+// L1EventConfirmations the confirmations the L1 watcher waits for per event category, a number of
+// blocks or the "safe"/"finalized" tag. The categories left unset use L1Config.Confirmations.
+type L1EventConfirmations struct {
+	// Deposit the L1MessageQueue QueueTransaction events.
+	Deposit *rpc.BlockNumber `json:"deposit,omitempty"`
+	// CommitBatch the ScrollChain CommitBatch events.
+	CommitBatch *rpc.BlockNumber `json:"commit_batch,omitempty"`
+	// FinalizeBatch the ScrollChain FinalizeBatch events.
+	FinalizeBatch *rpc.BlockNumber `json:"finalize_batch,omitempty"`
+}
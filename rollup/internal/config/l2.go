@@ -4,6 +4,9 @@ import (
 	"github.com/scroll-tech/go-ethereum/rpc"
 
 	"github.com/scroll-tech/go-ethereum/common"
+
+	"scroll-tech/common/objectstore"
+	"scroll-tech/common/rpcpool"
 )
 
 // L2Config loads l2geth configuration items.
@@ -12,16 +15,39 @@ type L2Config struct {
 	Confirmations rpc.BlockNumber `json:"confirmations"`
 	// l2geth node url.
 	Endpoint string `json:"endpoint"`
+	// RPCPool spreads the requests over several nodes with health checks and failover, nil uses the endpoint alone.
+	RPCPool *rpcpool.Config `json:"rpc_pool,omitempty"`
 	// The L2MessageQueue contract address deployed on layer 2 chain.
 	L2MessageQueueAddress common.Address `json:"l2_message_queue_address"`
 	// The WithdrawTrieRootSlot in L2MessageQueue contract.
 	WithdrawTrieRootSlot common.Hash `json:"withdraw_trie_root_slot,omitempty"`
+	// The L2ScrollMessenger contract address, the l1 messages whose relay fails on layer 2 are recorded as skipped messages. Unset disables it.
+	L2ScrollMessengerAddress common.Address `json:"l2_scroll_messenger_address,omitempty"`
+	// The opcodes that the circuits cannot prove, blocks whose traces contain them are flagged and excluded from chunking.
+	UnsupportedOpcodes []string `json:"unsupported_opcodes,omitempty"`
+	// The trace schema config of the l2 watcher, nil doesn't check the l2geth version of the block traces.
+	TraceSchema *TraceSchemaConfig `json:"trace_schema,omitempty"`
+	// The block fetcher config of the l2 watcher, nil uses the defaults.
+	BlockFetcherConfig *BlockFetcherConfig `json:"block_fetcher_config,omitempty"`
 	// The relayer config
 	RelayerConfig *RelayerConfig `json:"relayer_config"`
 	// The chunk_proposer config
 	ChunkProposerConfig *ChunkProposerConfig `json:"chunk_proposer_config"`
 	// The batch_proposer config
 	BatchProposerConfig *BatchProposerConfig `json:"batch_proposer_config"`
+	// ProposerPreset, "mainnet", "testnet" or "devnet", fills the time windows of the chunk and batch proposers
+	// left unset (0) with the values of the network. Empty uses the configured values alone.
+	ProposerPreset string `json:"proposer_preset,omitempty"`
+	// The pruner config, nil keeps the data of the finalized batches forever.
+	PrunerConfig *PrunerConfig `json:"pruner_config,omitempty"`
+	// The batch notifier config, nil doesn't notify the batch lifecycle events.
+	BatchNotifierConfig *BatchNotifierConfig `json:"batch_notifier_config,omitempty"`
+	// The event exporter config, nil doesn't export the lifecycle events to a message queue.
+	EventExporterConfig *EventExporterConfig `json:"event_exporter_config,omitempty"`
+	// The batch data availability checker config, nil doesn't check the data of the committed batches on L1.
+	BatchDACheckerConfig *BatchDACheckerConfig `json:"batch_da_checker_config,omitempty"`
+	// The l1 message gas estimator config, nil doesn't serve the gas limit estimates of the l1 messages on the admin api.
+	L1MessageGasEstimatorConfig *L1MessageGasEstimatorConfig `json:"l1_message_gas_estimator_config,omitempty"`
 }
 
 // ChunkProposerConfig loads chunk_proposer configuration items.
@@ -34,6 +60,36 @@ type ChunkProposerConfig struct {
 	MaxRowConsumptionPerChunk       uint64  `json:"max_row_consumption_per_chunk"`
 	GasCostIncreaseMultiplier       float64 `json:"gas_cost_increase_multiplier"`
 	MaxUncompressedBatchBytesSize   uint64  `json:"max_uncompressed_batch_bytes_size"`
+	// MaxRowConsumptionPerSubCircuit caps the rows of the named sub-circuits below max_row_consumption_per_chunk.
+	MaxRowConsumptionPerSubCircuit map[string]uint64 `json:"max_row_consumption_per_sub_circuit,omitempty"`
+	// SplitChunkAfterFailedAttempts, a chunk that failed proving this many times is split in two, 0 disables it.
+	// Only the chunks that are not in a batch, or whose batches are not yet committed, can be split.
+	SplitChunkAfterFailedAttempts int16 `json:"split_chunk_after_failed_attempts,omitempty"`
+	// ProposeWhenIdleSec, the pending blocks are proposed as a chunk once no new block was produced for this
+	// window, without waiting for chunk_timeout_sec. Meant for low-traffic chains, 0 disables it.
+	ProposeWhenIdleSec uint64 `json:"propose_when_idle_sec,omitempty"`
+}
+
+// BlockFetcherConfig loads the l2 watcher block fetcher configuration items.
+type BlockFetcherConfig struct {
+	// Workers is the number of blocks fetched concurrently from l2geth, default 4.
+	Workers int `json:"workers,omitempty"`
+	// MaxRetries is the number of times the fetch of a block is retried before the round is given up, default 3.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// InitialBackoffMs is the delay before the first retry of a block, doubled after every following failure, default 200ms.
+	InitialBackoffMs uint64 `json:"initial_backoff_ms,omitempty"`
+	// MaxBackoffMs caps the delay between the retries of a block, default 5s.
+	MaxBackoffMs uint64 `json:"max_backoff_ms,omitempty"`
+}
+
+// TraceSchemaConfig loads the l2 watcher trace schema configuration items.
+// The block traces of the l2geth versions out of the range are refused, the block is not stored and its fetch is
+// retried, so no chunk is proposed with it until the node is upgraded or downgraded.
+type TraceSchemaConfig struct {
+	// MinL2gethVersion is the oldest l2geth version, e.g. "5.3.0", whose traces match the schema of the circuits.
+	MinL2gethVersion string `json:"min_l2geth_version"`
+	// MaxL2gethVersion is the newest l2geth version whose traces match the schema of the circuits, empty has no bound.
+	MaxL2gethVersion string `json:"max_l2geth_version,omitempty"`
 }
 
 // BatchProposerConfig loads batch_proposer configuration items.
@@ -43,4 +99,121 @@ type BatchProposerConfig struct {
 	BatchTimeoutSec                 uint64  `json:"batch_timeout_sec"`
 	GasCostIncreaseMultiplier       float64 `json:"gas_cost_increase_multiplier"`
 	MaxUncompressedBatchBytesSize   uint64  `json:"max_uncompressed_batch_bytes_size"`
+	// L1MessageInclusionDeadlineSec, no batch is proposed while it leaves out an L1 message queued for longer
+	// than this window, 0 disables the check.
+	L1MessageInclusionDeadlineSec uint64 `json:"l1_message_inclusion_deadline_sec,omitempty"`
+	// ProposeWhenIdleSec, the pending chunks are proposed as a batch once their last block is older than this
+	// window, without waiting for batch_timeout_sec. Meant for low-traffic chains, 0 disables it.
+	ProposeWhenIdleSec uint64 `json:"propose_when_idle_sec,omitempty"`
+	// MaxUnprovenBatches and MaxUnprovenChunks pause the batch proposer while at least this many batches or
+	// chunks are waiting for their proof, so the unproven backlog stays bounded when the provers fall behind.
+	// 0 disables the cap.
+	MaxUnprovenBatches uint64 `json:"max_unproven_batches,omitempty"`
+	MaxUnprovenChunks  uint64 `json:"max_unproven_chunks,omitempty"`
+	// DynamicGasThreshold scales the commit gas the batches are filled up to with the L1 base fee, nil always
+	// fills them up to max_l1_commit_gas_per_batch.
+	DynamicGasThreshold *DynamicGasThresholdConfig `json:"dynamic_gas_threshold,omitempty"`
+}
+
+// DynamicGasThresholdConfig loads the dynamic gas threshold configuration items of the batch proposer.
+type DynamicGasThresholdConfig struct {
+	// MinL1CommitGasPerBatch is the threshold while L1 is cheap, the batches are smaller and proposed sooner.
+	// The threshold is max_l1_commit_gas_per_batch while L1 is expensive.
+	MinL1CommitGasPerBatch uint64 `json:"min_l1_commit_gas_per_batch"`
+	// LowBaseFee and HighBaseFee, in wei, are the L1 base fees at and below which, or at and above which, the
+	// threshold is at its min or max, it's linear in between.
+	LowBaseFee  uint64 `json:"low_base_fee"`
+	HighBaseFee uint64 `json:"high_base_fee"`
+	// BaseFeeWindow is the number of latest L1 blocks the base fee is averaged over, default 20.
+	BaseFeeWindow int `json:"base_fee_window,omitempty"`
+	// HysteresisPercent, the threshold only moves once the new one is this percent of the min-max range away
+	// from the current one, so it doesn't flap with the base fee. Default 10.
+	HysteresisPercent uint64 `json:"hysteresis_percent,omitempty"`
+}
+
+// BatchNotifierConfig loads batch_notifier configuration items.
+type BatchNotifierConfig struct {
+	// WebhookURLs receive a POST request with a json body on every batch lifecycle event.
+	WebhookURLs []string `json:"webhook_urls"`
+	// Secret signs the request bodies with HMAC-SHA256 in the X-Signature-256 header, empty doesn't sign them.
+	Secret string `json:"secret,omitempty"`
+	// TimeoutSec is the timeout of a webhook request, default 10s.
+	TimeoutSec uint64 `json:"timeout_sec,omitempty"`
+	// MaxEventsPerRun is the number of status transitions handled per run, default 100.
+	MaxEventsPerRun int `json:"max_events_per_run,omitempty"`
+}
+
+// EventExporterConfig loads event_exporter configuration items.
+type EventExporterConfig struct {
+	// Backend is the message queue the events are published to, "nats" or "kafka".
+	Backend string `json:"backend"`
+	// NATS is the nats server config of the "nats" backend.
+	NATS *NATSConfig `json:"nats,omitempty"`
+	// Kafka is the kafka cluster config of the "kafka" backend.
+	Kafka *KafkaConfig `json:"kafka,omitempty"`
+	// TopicPrefix prefixes the topics, the events are published to <topic_prefix>.batch, <topic_prefix>.chunk and
+	// <topic_prefix>.l1_message, default "scroll.rollup".
+	TopicPrefix string `json:"topic_prefix,omitempty"`
+	// TimeoutSec is the timeout of a publication, default 10s.
+	TimeoutSec uint64 `json:"timeout_sec,omitempty"`
+	// MaxEventsPerRun is the number of status transitions handled per run, default 100.
+	MaxEventsPerRun int `json:"max_events_per_run,omitempty"`
+}
+
+// NATSConfig loads the nats server configuration items.
+type NATSConfig struct {
+	// URL is the address of the server, nats://host:4222, or tls://host:4222 to require tls.
+	URL string `json:"url"`
+	// User and Password, or Token, authenticate the connection, empty doesn't authenticate.
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// KafkaConfig loads the kafka cluster configuration items.
+type KafkaConfig struct {
+	// Brokers are the addresses of the bootstrap brokers, host:9092.
+	Brokers []string `json:"brokers"`
+	// TLS connects to the brokers with tls.
+	TLS bool `json:"tls,omitempty"`
+	// Username and Password authenticate with SASL/PLAIN, empty doesn't authenticate.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// BatchDACheckerConfig loads batch_da_checker configuration items.
+type BatchDACheckerConfig struct {
+	// BeaconEndpoint is the beacon node api the blobs of the commit transactions are downloaded from,
+	// empty only checks the blob versioned hashes.
+	BeaconEndpoint string `json:"beacon_endpoint,omitempty"`
+	// AlertWebhookURL receives a POST request with a json body when the data of a batch doesn't match the database,
+	// empty only logs and counts the mismatches.
+	AlertWebhookURL string `json:"alert_webhook_url,omitempty"`
+	// MaxEventsPerRun is the number of status transitions handled per run, default 100.
+	MaxEventsPerRun int `json:"max_events_per_run,omitempty"`
+}
+
+// L1MessageGasEstimatorConfig loads l1_message_gas_estimator configuration items.
+type L1MessageGasEstimatorConfig struct {
+	// BufferPercent is the margin added to the simulated gas limit of an l1 message, in percent.
+	BufferPercent uint64 `json:"buffer_percent"`
+	// MaxGasLimit is the highest gas limit searched, the max gas limit of the L1MessageQueue, default 10000000.
+	MaxGasLimit uint64 `json:"max_gas_limit,omitempty"`
+	// CacheTTLSec is how long the estimate of a message type bounds the search of the next messages of the type, default 600.
+	CacheTTLSec uint64 `json:"cache_ttl_sec,omitempty"`
+}
+
+// PrunerConfig loads pruner configuration items.
+// The retention windows are counted from the finalization of the batch, 0 keeps the data forever.
+type PrunerConfig struct {
+	// L2BlockRetentionSec, the block traces are deleted from the l2_block table after this window.
+	L2BlockRetentionSec uint64 `json:"l2_block_retention_sec"`
+	// ChunkProofRetentionSec and BatchProofRetentionSec, the proofs kept in the chunk and batch tables are
+	// cleared after these windows, the proofs offloaded to the proof store are not touched.
+	ChunkProofRetentionSec uint64 `json:"chunk_proof_retention_sec"`
+	BatchProofRetentionSec uint64 `json:"batch_proof_retention_sec"`
+	// MaxRowsPerTx is the number of rows pruned per transaction, default 100.
+	MaxRowsPerTx int `json:"max_rows_per_tx,omitempty"`
+	// Archive uploads the pruned data to an object storage before deleting it, nil only deletes it.
+	Archive *objectstore.Config `json:"archive,omitempty"`
 }
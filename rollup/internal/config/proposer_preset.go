@@ -0,0 +1,50 @@
+package config
+
+import "fmt"
+
+// ProposerPreset the time windows of the chunk and batch proposers of a network.
+type ProposerPreset struct {
+	ChunkTimeoutSec         uint64
+	ChunkProposeWhenIdleSec uint64
+	BatchTimeoutSec         uint64
+	BatchProposeWhenIdleSec uint64
+}
+
+// ProposerPresets the proposer presets by network name.
+var ProposerPresets = map[string]ProposerPreset{
+	// mainnet amortizes the commit and finalize costs over large batches.
+	"mainnet": {ChunkTimeoutSec: 2700, BatchTimeoutSec: 2700},
+	// testnet keeps batches small enough to be proven and finalized quickly.
+	"testnet": {ChunkTimeoutSec: 300, BatchTimeoutSec: 300},
+	// devnet proposes as soon as the chain goes quiet.
+	"devnet": {ChunkTimeoutSec: 10, ChunkProposeWhenIdleSec: 2, BatchTimeoutSec: 10, BatchProposeWhenIdleSec: 2},
+}
+
+// applyProposerPreset fills the proposer time windows left unset with the values of the preset.
+func (c *L2Config) applyProposerPreset() error {
+	if c.ProposerPreset == "" {
+		return nil
+	}
+	preset, ok := ProposerPresets[c.ProposerPreset]
+	if !ok {
+		return fmt.Errorf("unknown proposer preset %q", c.ProposerPreset)
+	}
+
+	if cfg := c.ChunkProposerConfig; cfg != nil {
+		if cfg.ChunkTimeoutSec == 0 {
+			cfg.ChunkTimeoutSec = preset.ChunkTimeoutSec
+		}
+		if cfg.ProposeWhenIdleSec == 0 {
+			cfg.ProposeWhenIdleSec = preset.ChunkProposeWhenIdleSec
+		}
+	}
+	if cfg := c.BatchProposerConfig; cfg != nil {
+		if cfg.BatchTimeoutSec == 0 {
+			cfg.BatchTimeoutSec = preset.BatchTimeoutSec
+		}
+		if cfg.ProposeWhenIdleSec == 0 {
+			cfg.ProposeWhenIdleSec = preset.BatchProposeWhenIdleSec
+		}
+	}
+	return nil
+}
@@ -8,12 +8,16 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/common/rpcpool"
 )
 
 // SenderConfig The config for transaction sender
 type SenderConfig struct {
 	// The RPC endpoint of the ethereum or scroll public node.
 	Endpoint string `json:"endpoint"`
+	// RPCPool spreads the requests over several nodes with health checks and failover, nil uses the endpoint alone.
+	RPCPool *rpcpool.Config `json:"rpc_pool,omitempty"`
 	// The time to trigger check pending txs in sender.
 	CheckPendingTime uint64 `json:"check_pending_time"`
 	// The number of blocks to wait to escalate increase gas price of the transaction.
@@ -32,6 +36,47 @@ type SenderConfig struct {
 	MaxBlobGasPrice uint64 `json:"max_blob_gas_price"`
 	// The transaction type to use: LegacyTx, DynamicFeeTx, BlobTx
 	TxType string `json:"tx_type"`
+	// How a sender pool picks the account once the transactions of the previous one are confirmed: round_robin (default).
+	AccountSelection string `json:"account_selection,omitempty"`
+	// The minimum balance (in wei) an account of a sender pool needs to be picked, 0 disables the check.
+	MinBalance uint64 `json:"min_balance,omitempty"`
+	// Simulate the transactions with eth_call before sending them, the ones that fail the simulation are not sent.
+	SimulateTx bool `json:"simulate_tx,omitempty"`
+	// PauseOnInsufficientBalance doesn't send the transactions whose maximum cost the account balance can't cover,
+	// the sending is retried by the relayer once the account is funded.
+	PauseOnInsufficientBalance bool `json:"pause_on_insufficient_balance,omitempty"`
+	// FeeStrategy picks how the priority fee of the dynamic fee and blob transactions is suggested, nil asks the node.
+	FeeStrategy *FeeStrategyConfig `json:"fee_strategy,omitempty"`
+	// DryRun writes the transactions to files for an external submission instead of sending them, nil sends them.
+	DryRun *DryRunConfig `json:"dry_run,omitempty"`
+}
+
+// DryRunConfig the config of the dry-run mode of a sender, for the operators whose keys are held by a separate
+// signing infrastructure.
+type DryRunConfig struct {
+	// ExportDir is the directory the transactions are written to, one json file per transaction.
+	ExportDir string `json:"export_dir"`
+	// Sign signs the exported transactions with the signer of the sender, otherwise they are exported unsigned.
+	Sign bool `json:"sign,omitempty"`
+}
+
+// FeeStrategyConfig the config of the priority fee strategy of a sender
+type FeeStrategyConfig struct {
+	// Type is "node" (default, eth_maxPriorityFeePerGas), "block_percentile", "fee_history" or "oracle".
+	Type string `json:"type,omitempty"`
+	// Percentile of the priority fees paid in a block, used by block_percentile and fee_history, default 50.
+	Percentile float64 `json:"percentile,omitempty"`
+	// Blocks is the number of latest blocks fee_history takes the median percentile of, default 20.
+	Blocks uint64 `json:"blocks,omitempty"`
+	// OracleURL is the url the oracle strategy GETs the priority fee from.
+	OracleURL string `json:"oracle_url,omitempty"`
+	// OracleField is the field of the json object returned by the oracle holding the priority fee in wei,
+	// default "max_priority_fee_per_gas".
+	OracleField string `json:"oracle_field,omitempty"`
+	// OracleTimeoutSec is the timeout of the oracle requests, default 5s.
+	OracleTimeoutSec uint64 `json:"oracle_timeout_sec,omitempty"`
+	// Overrides replaces the strategy of the "commit_batch", "finalize_batch", "l1_gas_oracle" or "l2_gas_oracle" transactions.
+	Overrides map[string]*FeeStrategyConfig `json:"overrides,omitempty"`
 }
 
 // ChainMonitor this config is used to get batch status from chain_monitor API.
@@ -57,10 +102,30 @@ type RelayerConfig struct {
 	ChainMonitor *ChainMonitor `json:"chain_monitor"`
 	// L1CommitGasLimitMultiplier multiplier for fallback gas limit in commitBatch txs
 	L1CommitGasLimitMultiplier float64 `json:"l1_commit_gas_limit_multiplier,omitempty"`
+	// FinalizeBundle finalizes consecutive proven blob batches in a single finalizeBundleWithProof4844 tx,
+	// only enable it if the rollup contract supports finalizeBundleWithProof4844. nil finalizes the batches one by one.
+	FinalizeBundle *FinalizeBundleConfig `json:"finalize_bundle,omitempty"`
+	// FinalizeRetry retries the batches whose finalize transaction failed, nil leaves them to the operator.
+	FinalizeRetry *FinalizeRetryConfig `json:"finalize_retry,omitempty"`
+	// GasPriceThrottle defers the commit and finalize txs while the L1 base fee is high, nil sends them right away.
+	GasPriceThrottle *GasPriceThrottleConfig `json:"gas_price_throttle,omitempty"`
+	// FinalizeApproval holds the finalization of a batch until enough co-signers approved it, nil finalizes without approvals.
+	FinalizeApproval *FinalizeApprovalConfig `json:"finalize_approval,omitempty"`
 	// The private key of the relayer
 	GasOracleSenderPrivateKey *ecdsa.PrivateKey `json:"-"`
 	CommitSenderPrivateKey    *ecdsa.PrivateKey `json:"-"`
 	FinalizeSenderPrivateKey  *ecdsa.PrivateKey `json:"-"`
+	// The private keys of the additional accounts sending commit and finalize transactions alongside the keys above.
+	// Every account tracks its own nonce, a sender pool only moves to another account once its transactions are confirmed.
+	CommitSenderPrivateKeys   []*ecdsa.PrivateKey `json:"-"`
+	FinalizeSenderPrivateKeys []*ecdsa.PrivateKey `json:"-"`
+	// The signers of the accounts whose keys are kept in a KMS or a remote signer, a signer replaces
+	// the private key of the same sender, and the additional signers join the additional private keys.
+	GasOracleSenderSigner *SignerConfig   `json:"gas_oracle_sender_signer,omitempty"`
+	CommitSenderSigner    *SignerConfig   `json:"commit_sender_signer,omitempty"`
+	FinalizeSenderSigner  *SignerConfig   `json:"finalize_sender_signer,omitempty"`
+	CommitSenderSigners   []*SignerConfig `json:"commit_sender_signers,omitempty"`
+	FinalizeSenderSigners []*SignerConfig `json:"finalize_sender_signers,omitempty"`
 
 	// Indicates if bypass features specific to testing environments are enabled.
 	EnableTestEnvBypassFeatures bool `json:"enable_test_env_bypass_features"`
@@ -68,6 +133,109 @@ type RelayerConfig struct {
 	FinalizeBatchWithoutProofTimeoutSec uint64 `json:"finalize_batch_without_proof_timeout_sec"`
 }
 
+// SignerConfig The config of a transaction signer keeping the private key out of the relayer host.
+type SignerConfig struct {
+	// Backend is one of "aws_kms", "gcp_kms" and "remote_signer", the config of the backend is set below.
+	Backend      string              `json:"backend"`
+	AWSKMS       *AWSKMSConfig       `json:"aws_kms,omitempty"`
+	GCPKMS       *GCPKMSConfig       `json:"gcp_kms,omitempty"`
+	RemoteSigner *RemoteSignerConfig `json:"remote_signer,omitempty"`
+}
+
+// AWSKMSConfig The config of an ECC_SECG_P256K1 key in AWS KMS, the credentials are loaded
+// from the default AWS credential chain (environment, shared config, instance role).
+type AWSKMSConfig struct {
+	// KeyID is the key id, key ARN or alias of the key.
+	KeyID  string `json:"key_id"`
+	Region string `json:"region"`
+	// Endpoint overrides the KMS endpoint of the region, e.g. for a VPC endpoint.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// GCPKMSConfig The config of an EC_SIGN_SECP256K1_SHA256 key in GCP Cloud KMS, the access token
+// is fetched from the metadata server of the GCE instance or the GKE workload.
+type GCPKMSConfig struct {
+	// KeyVersionName is the resource name of the key version,
+	// projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>/cryptoKeyVersions/<version>.
+	KeyVersionName string `json:"key_version_name"`
+	// Endpoint overrides the Cloud KMS endpoint, https://cloudkms.googleapis.com by default.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// RemoteSignerConfig The config of a remote signer exposing the clef external api, the transactions are
+// signed with account_signTransaction, which doesn't support the blob transactions.
+type RemoteSignerConfig struct {
+	// Endpoint is the http, websocket or ipc endpoint of the signer.
+	Endpoint string `json:"endpoint"`
+	// Address is the account of the signer sending the transactions.
+	Address common.Address `json:"address"`
+}
+
+// FinalizeBundleConfig The config for finalizing several batches per L1 transaction.
+type FinalizeBundleConfig struct {
+	// MaxBatches is the maximum number of batches finalized in a tx.
+	MaxBatches int `json:"max_batches"`
+}
+
+// FinalizeRetryConfig The config for retrying the failed finalize transactions.
+type FinalizeRetryConfig struct {
+	// MaxAttempts is the number of failed finalize transactions after which the batch is quarantined,
+	// the finalization is halted until the operator fixes the batch and sets its rollup status back to committed.
+	MaxAttempts int16 `json:"max_attempts"`
+	// InitialBackoffSec is the delay before retrying after the first failure, doubled after every following failure.
+	InitialBackoffSec uint64 `json:"initial_backoff_sec"`
+	// MaxBackoffSec caps the delay between the retries, 0 means no cap.
+	MaxBackoffSec uint64 `json:"max_backoff_sec,omitempty"`
+	// AlertWebhookURL receives a POST request with a json body when a batch is quarantined, empty disables it.
+	AlertWebhookURL string `json:"alert_webhook_url,omitempty"`
+}
+
+// FinalizeApprovalConfig The config of the M-of-N co-signer approvals required before finalizing a batch.
+type FinalizeApprovalConfig struct {
+	// Threshold is the number of distinct approvers which must approve a batch before it's finalized.
+	Threshold int `json:"threshold"`
+	// Approvers are the addresses of the co-signers, the approvals signed by other keys are refused.
+	Approvers []common.Address `json:"approvers"`
+}
+
+// Validate checks the threshold can be met by the approvers.
+func (c *FinalizeApprovalConfig) Validate() error {
+	if c.Threshold <= 0 || c.Threshold > len(c.Approvers) {
+		return fmt.Errorf("finalize approval threshold %d must be between 1 and the number of approvers %d", c.Threshold, len(c.Approvers))
+	}
+	seen := make(map[common.Address]struct{}, len(c.Approvers))
+	for _, approver := range c.Approvers {
+		if _, ok := seen[approver]; ok {
+			return fmt.Errorf("duplicated finalize approver %s", approver.Hex())
+		}
+		seen[approver] = struct{}{}
+	}
+	return nil
+}
+
+// IsApprover reports whether the address is one of the approvers.
+func (c *FinalizeApprovalConfig) IsApprover(address common.Address) bool {
+	for _, approver := range c.Approvers {
+		if approver == address {
+			return true
+		}
+	}
+	return false
+}
+
+// GasPriceThrottleConfig The config for deferring the commit and finalize transactions while the L1 base fee
+// is above the max base fee.
+type GasPriceThrottleConfig struct {
+	// MaxBaseFee is the L1 base fee in wei above which the transactions are deferred.
+	MaxBaseFee uint64 `json:"max_base_fee"`
+	// MaxCommitHoldSec is how long the oldest pending batch is held since its creation, 0 holds the commits
+	// until the base fee drops.
+	MaxCommitHoldSec uint64 `json:"max_commit_hold_sec,omitempty"`
+	// FinalizeSafetyWindowSec is how long a batch ready to be finalized is held since it was proved,
+	// 0 never holds the finalizations.
+	FinalizeSafetyWindowSec uint64 `json:"finalize_safety_window_sec,omitempty"`
+}
+
 // GasOracleConfig The config for updating gas price oracle.
 type GasOracleConfig struct {
 	// MinGasPrice store the minimum gas price to set.
@@ -80,6 +248,40 @@ type GasOracleConfig struct {
 	L1BaseFeeWeight float64 `json:"l1_base_fee_weight"`
 	// The weight for L1 blob base fee.
 	L1BlobBaseFeeWeight float64 `json:"l1_blob_base_fee_weight"`
+
+	// MaxGasPrice caps the gas prices written to the oracle, 0 means no cap.
+	MaxGasPrice uint64 `json:"max_gas_price,omitempty"`
+	// Strategy turns the observed gas prices into the oracle prices, nil writes the latest observed prices.
+	Strategy *GasPriceStrategyConfig `json:"strategy,omitempty"`
+	// FeeScalar derives the fee scalars of the L1GasPriceOracle from the L1 costs of the latest batches,
+	// nil leaves them to the owner of the contract.
+	FeeScalar *FeeScalarOracleConfig `json:"fee_scalar,omitempty"`
+}
+
+// FeeScalarOracleConfig The config for deriving the fee scalars of the L1GasPriceOracle from the commit and
+// finalize transactions of the latest finalized batches. The gas oracle sender must own the contract.
+type FeeScalarOracleConfig struct {
+	// BatchWindow is the number of latest finalized batches the L1 costs are averaged over, default 50.
+	BatchWindow int `json:"batch_window,omitempty"`
+	// UpdateIntervalSec is how often the scalars are derived and written if they moved by the gas price diff, default 300.
+	UpdateIntervalSec uint64 `json:"update_interval_sec,omitempty"`
+}
+
+// GasPriceStrategyConfig The config of the gas price oracle pricing strategy.
+type GasPriceStrategyConfig struct {
+	// Type is one of "latest", "ema", "percentile" and "external".
+	Type string `json:"type"`
+	// EMAAlpha is the weight of the latest observed price in the exponential moving average, in (0, 1].
+	EMAAlpha float64 `json:"ema_alpha,omitempty"`
+	// Window is the number of recent observed prices the percentile is taken over.
+	Window int `json:"window,omitempty"`
+	// Percentile of the recent observed prices, in [0, 100].
+	Percentile float64 `json:"percentile,omitempty"`
+	// FeedURL is an external price feed answering GET requests with a json object of prices in wei,
+	// keyed by "l1_base_fee", "l1_blob_base_fee" and "l2_base_fee". The observed price is used if it fails.
+	FeedURL string `json:"feed_url,omitempty"`
+	// FeedTimeoutSec is the timeout of the price feed requests.
+	FeedTimeoutSec uint64 `json:"feed_timeout_sec,omitempty"`
 }
 
 // relayerConfigAlias RelayerConfig alias name
@@ -111,6 +313,9 @@ func (r *RelayerConfig) UnmarshalJSON(input []byte) error {
 		GasOracleSenderPrivateKey string `json:"gas_oracle_sender_private_key"`
 		CommitSenderPrivateKey    string `json:"commit_sender_private_key"`
 		FinalizeSenderPrivateKey  string `json:"finalize_sender_private_key"`
+
+		CommitSenderPrivateKeys   []string `json:"commit_sender_private_keys"`
+		FinalizeSenderPrivateKeys []string `json:"finalize_sender_private_keys"`
 	}
 	var err error
 	if err = json.Unmarshal(input, &privateKeysConfig); err != nil {
@@ -136,9 +341,44 @@ func (r *RelayerConfig) UnmarshalJSON(input []byte) error {
 		return fmt.Errorf("error converting and checking finalize sender private key: %w", err)
 	}
 
+	r.CommitSenderPrivateKeys, err = convertAndCheckAll(privateKeysConfig.CommitSenderPrivateKeys, uniqueAddressesSet)
+	if err != nil {
+		return fmt.Errorf("error converting and checking commit sender private keys: %w", err)
+	}
+
+	r.FinalizeSenderPrivateKeys, err = convertAndCheckAll(privateKeysConfig.FinalizeSenderPrivateKeys, uniqueAddressesSet)
+	if err != nil {
+		return fmt.Errorf("error converting and checking finalize sender private keys: %w", err)
+	}
+
 	return nil
 }
 
+func convertAndCheckAll(keys []string, uniqueAddressesSet map[string]struct{}) ([]*ecdsa.PrivateKey, error) {
+	var privKeys []*ecdsa.PrivateKey
+	for _, key := range keys {
+		privKey, err := convertAndCheck(key, uniqueAddressesSet)
+		if err != nil {
+			return nil, err
+		}
+		if privKey != nil {
+			privKeys = append(privKeys, privKey)
+		}
+	}
+	return privKeys, nil
+}
+
+func privateKeysToHex(privKeys []*ecdsa.PrivateKey) []string {
+	if len(privKeys) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(privKeys))
+	for _, privKey := range privKeys {
+		keys = append(keys, common.Bytes2Hex(crypto.FromECDSA(privKey)))
+	}
+	return keys
+}
+
 // MarshalJSON marshal RelayerConfig config, transfer private keys.
 func (r *RelayerConfig) MarshalJSON() ([]byte, error) {
 	privateKeysConfig := struct {
@@ -147,12 +387,17 @@ func (r *RelayerConfig) MarshalJSON() ([]byte, error) {
 		GasOracleSenderPrivateKey string `json:"gas_oracle_sender_private_key"`
 		CommitSenderPrivateKey    string `json:"commit_sender_private_key"`
 		FinalizeSenderPrivateKey  string `json:"finalize_sender_private_key"`
+
+		CommitSenderPrivateKeys   []string `json:"commit_sender_private_keys,omitempty"`
+		FinalizeSenderPrivateKeys []string `json:"finalize_sender_private_keys,omitempty"`
 	}{}
 
 	privateKeysConfig.relayerConfigAlias = relayerConfigAlias(*r)
 	privateKeysConfig.GasOracleSenderPrivateKey = common.Bytes2Hex(crypto.FromECDSA(r.GasOracleSenderPrivateKey))
 	privateKeysConfig.CommitSenderPrivateKey = common.Bytes2Hex(crypto.FromECDSA(r.CommitSenderPrivateKey))
 	privateKeysConfig.FinalizeSenderPrivateKey = common.Bytes2Hex(crypto.FromECDSA(r.FinalizeSenderPrivateKey))
+	privateKeysConfig.CommitSenderPrivateKeys = privateKeysToHex(r.CommitSenderPrivateKeys)
+	privateKeysConfig.FinalizeSenderPrivateKeys = privateKeysToHex(r.FinalizeSenderPrivateKeys)
 
 	return json.Marshal(&privateKeysConfig)
 }
@@ -0,0 +1,770 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/objectstore"
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/relayer"
+	"scroll-tech/rollup/internal/controller/watcher"
+	"scroll-tech/rollup/internal/orm"
+)
+
+// PauseParameter the pause and resume request parameter
+type PauseParameter struct {
+	Component string `form:"component" json:"component" binding:"required"`
+	Reason    string `form:"reason" json:"reason"`
+}
+
+// PauseStateSchema the paused state of a component returned to the admin
+type PauseStateSchema struct {
+	Component string `json:"component"`
+	Paused    bool   `json:"paused"`
+	Reason    string `json:"reason"`
+	UpdatedAt int64  `json:"updated_at,omitempty"`
+}
+
+// SkippedMessagesParameter the skipped messages request parameter
+type SkippedMessagesParameter struct {
+	Offset int `form:"offset" json:"offset" binding:"min=0"`
+	Limit  int `form:"limit" json:"limit" binding:"min=0,max=1000"`
+}
+
+// SkippedMessageSchema an l1 message skipped or failed relaying on l2, returned to the admin
+type SkippedMessageSchema struct {
+	QueueIndex     uint64 `json:"queue_index"`
+	MsgHash        string `json:"msg_hash"`
+	L2BlockNumber  uint64 `json:"l2_block_number"`
+	Layer2Hash     string `json:"layer2_hash,omitempty"`
+	Reason         string `json:"reason"`
+	ReplayTxHash   string `json:"replay_tx_hash,omitempty"`
+	ReplayGasLimit uint64 `json:"replay_gas_limit,omitempty"`
+	ReplayedAt     int64  `json:"replayed_at,omitempty"`
+	CreatedAt      int64  `json:"created_at"`
+}
+
+// SkippedBlocksParameter the skipped blocks request parameter
+type SkippedBlocksParameter struct {
+	Offset int `form:"offset" json:"offset" binding:"min=0"`
+	Limit  int `form:"limit" json:"limit" binding:"min=0,max=1000"`
+}
+
+// SkippedBlockSchema an l2 block flagged as unprovable, the chunk proposer halts at it, returned to the admin
+type SkippedBlockSchema struct {
+	Number     uint64 `json:"number"`
+	Hash       string `json:"hash"`
+	ChunkHash  string `json:"chunk_hash,omitempty"`
+	SkipReason string `json:"skip_reason"`
+}
+
+// UnskipBlockParameter the unskip block request parameter
+type UnskipBlockParameter struct {
+	BlockNumber *uint64 `form:"block_number" json:"block_number" binding:"required"`
+	Reason      string  `form:"reason" json:"reason" binding:"required"`
+}
+
+// L1MessageGasLimitParameter the l1 message gas limit request parameter
+type L1MessageGasLimitParameter struct {
+	QueueIndex *uint64 `form:"queue_index" json:"queue_index" binding:"required"`
+}
+
+// L1MessageGasLimitSchema the estimated l2 gas limit of an l1 message returned to the admin
+type L1MessageGasLimitSchema struct {
+	QueueIndex uint64 `json:"queue_index"`
+	GasLimit   uint64 `json:"gas_limit"`
+}
+
+// L1MessageGasUsedParameter the l1 message gas used and gas report request parameter, the sender is the L1 address
+// which sent the messages
+type L1MessageGasUsedParameter struct {
+	Sender string `form:"sender" json:"sender"`
+	Offset int    `form:"offset" json:"offset" binding:"min=0"`
+	Limit  int    `form:"limit" json:"limit" binding:"min=0,max=1000"`
+}
+
+// L1MessageGasUsedSchema the l2 gas used by an l1 message included on l2, returned to the admin
+type L1MessageGasUsedSchema struct {
+	QueueIndex uint64 `json:"queue_index"`
+	MsgHash    string `json:"msg_hash"`
+	Sender     string `json:"sender"`
+	Target     string `json:"target"`
+	Layer1Hash string `json:"layer1_hash"`
+	Layer2Hash string `json:"layer2_hash"`
+	GasLimit   uint64 `json:"gas_limit"`
+	GasUsed    uint64 `json:"gas_used"`
+	// UnusedGas is the part of the gas limit the sender paid for on l1 which the message didn't use on l2.
+	UnusedGas uint64 `json:"unused_gas"`
+}
+
+// L1MessageGasReportSchema the l2 gas used by the l1 messages of a sender included on l2, returned to the admin
+type L1MessageGasReportSchema struct {
+	Sender         string `json:"sender"`
+	MessageCount   uint64 `json:"message_count"`
+	TotalGasLimit  uint64 `json:"total_gas_limit"`
+	TotalGasUsed   uint64 `json:"total_gas_used"`
+	TotalUnusedGas uint64 `json:"total_unused_gas"`
+}
+
+// FinalityHeightSchema the latest L2 block of a finality level and the batch covering it
+type FinalityHeightSchema struct {
+	BlockNumber    uint64 `json:"block_number"`
+	BatchIndex     uint64 `json:"batch_index"`
+	BatchHash      string `json:"batch_hash"`
+	CommitTxHash   string `json:"commit_tx_hash"`
+	FinalizeTxHash string `json:"finalize_tx_hash,omitempty"`
+}
+
+// FinalityHeightsSchema the safe and finalized L2 heights, a level is null until a batch reaches it
+type FinalityHeightsSchema struct {
+	Safe      *FinalityHeightSchema `json:"safe"`
+	Finalized *FinalityHeightSchema `json:"finalized"`
+	UpdatedAt int64                 `json:"updated_at"`
+}
+
+// ArchivedObjectsParameter the archived objects request parameter
+type ArchivedObjectsParameter struct {
+	BatchIndex *uint64 `form:"batch_index" json:"batch_index" binding:"required"`
+}
+
+// ArchivedObjectParameter the archived object request parameter, the key is the l2 block number, or the chunk or batch hash
+type ArchivedObjectParameter struct {
+	Kind string `form:"kind" json:"kind" binding:"required,oneof=l2_block chunk_proof batch_proof"`
+	Key  string `form:"key" json:"key" binding:"required"`
+}
+
+// ArchivedObjectSchema the index entry of an object the pruner moved to the archive storage
+type ArchivedObjectSchema struct {
+	Kind        string `json:"kind"`
+	Key         string `json:"key"`
+	URI         string `json:"uri"`
+	ContentHash string `json:"content_hash"`
+	Size        uint64 `json:"size"`
+	ArchivedAt  int64  `json:"archived_at"`
+}
+
+// ArchivedObjectDataSchema an archived object read back from the archive storage, checked against its content hash
+type ArchivedObjectDataSchema struct {
+	Kind        string        `json:"kind"`
+	Key         string        `json:"key"`
+	ContentHash string        `json:"content_hash"`
+	Data        hexutil.Bytes `json:"data"`
+}
+
+// BatchApprovalParameter the batch approval request parameter, the signature is over the approval digest of the batch
+type BatchApprovalParameter struct {
+	BatchHash string `form:"batch_hash" json:"batch_hash" binding:"required"`
+	Signature string `form:"signature" json:"signature" binding:"required"`
+}
+
+// BatchApprovalsParameter the batch approvals request parameter
+type BatchApprovalsParameter struct {
+	BatchHash string `form:"batch_hash" json:"batch_hash" binding:"required"`
+}
+
+// BatchApprovalsSchema the approvals of a batch returned to the co-signers
+type BatchApprovalsSchema struct {
+	BatchIndex     uint64   `json:"batch_index"`
+	BatchHash      string   `json:"batch_hash"`
+	ApprovalDigest string   `json:"approval_digest"`
+	Approvers      []string `json:"approvers"`
+	Threshold      int      `json:"threshold"`
+}
+
+const defaultListLimit = 100
+
+// Controller the admin api controller, the paused state is persisted so restarts don't silently resume.
+type Controller struct {
+	pauseStateOrm     *orm.PauseState
+	skippedMessageOrm *orm.SkippedMessage
+	l1MessageOrm      *orm.L1Message
+	l2BlockOrm        *orm.L2Block
+	batchOrm          *orm.Batch
+	chunkOrm          *orm.Chunk
+	batchApprovalOrm  *orm.BatchApproval
+	archivedObjectOrm *orm.ArchivedObject
+
+	finalizeApproval *config.FinalizeApprovalConfig
+	gasEstimator     *relayer.L1MessageGasEstimator
+	finalityTracker  *watcher.FinalityTracker
+	archiveStore     objectstore.Store
+}
+
+// NewController creates an admin api controller, the batch approval api is served if finalizeApproval is set,
+// the l1 message gas limit api if gasEstimator is set, the finality api if finalityTracker is set, and the archive
+// api if archiveStore is set.
+func NewController(db *gorm.DB, finalizeApproval *config.FinalizeApprovalConfig, gasEstimator *relayer.L1MessageGasEstimator,
+	finalityTracker *watcher.FinalityTracker, archiveStore objectstore.Store) *Controller {
+	return &Controller{
+		pauseStateOrm:     orm.NewPauseState(db),
+		skippedMessageOrm: orm.NewSkippedMessage(db),
+		l1MessageOrm:      orm.NewL1Message(db),
+		l2BlockOrm:        orm.NewL2Block(db),
+		batchOrm:          orm.NewBatch(db),
+		chunkOrm:          orm.NewChunk(db),
+		batchApprovalOrm:  orm.NewBatchApproval(db),
+		archivedObjectOrm: orm.NewArchivedObject(db),
+		finalizeApproval:  finalizeApproval,
+		gasEstimator:      gasEstimator,
+		finalityTracker:   finalityTracker,
+		archiveStore:      archiveStore,
+	}
+}
+
+// Route registers the admin api, every request requires the "Authorization: Bearer <secret>" header.
+func Route(router *gin.Engine, cfg *config.AdminConfig, c *Controller) {
+	router.Use(gin.Recovery())
+
+	r := router.Group("/admin/v1", authMiddleware(cfg.Secret))
+	r.GET("/pause_states", c.GetPauseStates)
+	r.POST("/pause", c.Pause)
+	r.POST("/resume", c.Resume)
+	r.GET("/skipped_messages", c.GetSkippedMessages)
+	r.GET("/skipped_blocks", c.GetSkippedBlocks)
+	r.POST("/unskip_block", c.UnskipBlock)
+	r.GET("/l1_message_gas_used", c.GetL1MessageGasUsed)
+	r.GET("/l1_message_gas_report", c.GetL1MessageGasReport)
+	if c.gasEstimator != nil {
+		r.GET("/l1_message_gas_limit", c.GetL1MessageGasLimit)
+	}
+	if c.archiveStore != nil {
+		r.GET("/archived_objects", c.GetArchivedObjects)
+		r.GET("/archived_object", c.GetArchivedObject)
+	}
+
+	// the co-signers don't share the admin secret, their approvals are authenticated by their signatures.
+	if c.finalizeApproval != nil {
+		approval := router.Group("/approval/v1")
+		approval.GET("/batch_approvals", c.GetBatchApprovals)
+		approval.POST("/batch_approvals", c.ApproveBatch)
+	}
+
+	// the finality heights are public, the bridges and exchanges follow them without the admin secret.
+	if c.finalityTracker != nil {
+		finality := router.Group("/finality/v1")
+		finality.GET("/heights", c.GetFinalityHeights)
+	}
+}
+
+// Server starts the admin api server, it is shut down when the context is canceled.
+func Server(ctx context.Context, cfg *config.AdminConfig, finalizeApproval *config.FinalizeApprovalConfig, gasEstimator *relayer.L1MessageGasEstimator,
+	finalityTracker *watcher.FinalityTracker, archiveStore objectstore.Store, db *gorm.DB) error {
+	if cfg.Secret == "" {
+		return errors.New("admin api requires a secret")
+	}
+	if finalizeApproval != nil {
+		if err := finalizeApproval.Validate(); err != nil {
+			return err
+		}
+	}
+
+	router := gin.New()
+	Route(router, cfg, NewController(db, finalizeApproval, gasEstimator, finalityTracker, archiveStore))
+
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           router,
+		ReadHeaderTimeout: time.Minute,
+	}
+	log.Info("Starting admin server", "address", cfg.Addr)
+
+	go func() {
+		if runServerErr := server.ListenAndServe(); runServerErr != nil && !errors.Is(runServerErr, http.ErrServerClosed) {
+			log.Crit("run admin http server failure", "error", runServerErr)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Warn("shutdown admin http server failure", "error", err)
+		}
+	}()
+	return nil
+}
+
+func authMiddleware(secret string) gin.HandlerFunc {
+	expected := []byte("Bearer " + secret)
+	return func(c *gin.Context) {
+		if secret == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			types.RenderFailure(c, types.ErrRollupAdminUnauthorized, errors.New("invalid admin secret"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetPauseStates returns the paused state of all the components
+func (c *Controller) GetPauseStates(ctx *gin.Context) {
+	pauseStates, err := c.pauseStateOrm.GetPauseStates(ctx.Copy())
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+
+	persisted := make(map[string]orm.PauseState, len(pauseStates))
+	for _, pauseState := range pauseStates {
+		persisted[pauseState.Component] = pauseState
+	}
+
+	schemas := make([]PauseStateSchema, 0, len(orm.PauseComponents))
+	for _, component := range orm.PauseComponents {
+		schema := PauseStateSchema{Component: component}
+		if pauseState, ok := persisted[component]; ok {
+			schema.Paused = pauseState.Paused
+			schema.Reason = pauseState.Reason
+			schema.UpdatedAt = pauseState.UpdatedAt.Unix()
+		}
+		schemas = append(schemas, schema)
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// Pause pauses the component
+func (c *Controller) Pause(ctx *gin.Context) {
+	c.setPaused(ctx, true)
+}
+
+// Resume resumes the component
+func (c *Controller) Resume(ctx *gin.Context) {
+	c.setPaused(ctx, false)
+}
+
+func (c *Controller) setPaused(ctx *gin.Context, paused bool) {
+	var pp PauseParameter
+	if err := ctx.ShouldBind(&pp); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if !isPauseComponent(pp.Component) {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, unknown component: %s", pp.Component))
+		return
+	}
+
+	if err := c.pauseStateOrm.SetPaused(ctx.Copy(), pp.Component, paused, pp.Reason); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	log.Warn("component pause state changed by admin", "component", pp.Component, "paused", paused, "reason", pp.Reason, "remote", ctx.ClientIP())
+	types.RenderSuccess(ctx, nil)
+}
+
+func isPauseComponent(component string) bool {
+	for _, c := range orm.PauseComponents {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSkippedMessages returns the l1 messages skipped or failed relaying on l2, the latest first.
+// They are replayed with "scroll-cli message replay".
+func (c *Controller) GetSkippedMessages(ctx *gin.Context) {
+	var sp SkippedMessagesParameter
+	if err := ctx.ShouldBindQuery(&sp); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if sp.Limit == 0 {
+		sp.Limit = defaultListLimit
+	}
+
+	skippedMessages, err := c.skippedMessageOrm.GetSkippedMessages(ctx.Copy(), sp.Offset, sp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+
+	schemas := make([]SkippedMessageSchema, 0, len(skippedMessages))
+	for _, skippedMessage := range skippedMessages {
+		schema := SkippedMessageSchema{
+			QueueIndex:     skippedMessage.QueueIndex,
+			MsgHash:        skippedMessage.MsgHash,
+			L2BlockNumber:  skippedMessage.L2BlockNumber,
+			Layer2Hash:     skippedMessage.Layer2Hash,
+			Reason:         skippedMessage.Reason,
+			ReplayTxHash:   skippedMessage.ReplayTxHash,
+			ReplayGasLimit: skippedMessage.ReplayGasLimit,
+			CreatedAt:      skippedMessage.CreatedAt.Unix(),
+		}
+		if skippedMessage.ReplayedAt != nil {
+			schema.ReplayedAt = skippedMessage.ReplayedAt.Unix()
+		}
+		schemas = append(schemas, schema)
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// GetSkippedBlocks returns the l2 blocks flagged as unprovable, the lowest first. The chunk proposer halts at the
+// lowest one, the rollup_propose_chunk_halted_block_number metric is set to its number.
+func (c *Controller) GetSkippedBlocks(ctx *gin.Context) {
+	var sp SkippedBlocksParameter
+	if err := ctx.ShouldBindQuery(&sp); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	if sp.Limit == 0 {
+		sp.Limit = defaultListLimit
+	}
+
+	l2Blocks, err := c.l2BlockOrm.GetSkippedL2Blocks(ctx.Copy(), sp.Offset, sp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+
+	schemas := make([]SkippedBlockSchema, 0, len(l2Blocks))
+	for _, l2Block := range l2Blocks {
+		schemas = append(schemas, SkippedBlockSchema{
+			Number:     l2Block.Number,
+			Hash:       l2Block.Hash,
+			ChunkHash:  l2Block.ChunkHash,
+			SkipReason: l2Block.SkipReason,
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// UnskipBlock clears the flag of an l2 block so the chunk proposer chunks it again, e.g. once the circuits
+// support the opcode it was flagged for. The operator overrides the watcher, so the reason is logged.
+func (c *Controller) UnskipBlock(ctx *gin.Context) {
+	var up UnskipBlockParameter
+	if err := ctx.ShouldBind(&up); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	if err := c.l2BlockOrm.ClearSkipReason(ctx.Copy(), *up.BlockNumber); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("l2 block %d is not flagged as skipped", *up.BlockNumber))
+			return
+		}
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	log.Warn("skipped l2 block cleared by admin", "block number", *up.BlockNumber, "reason", up.Reason, "remote", ctx.ClientIP())
+	types.RenderSuccess(ctx, nil)
+}
+
+// GetL1MessageGasLimit returns the l2 gas limit the l1 message needs, estimated by simulating it on l2geth.
+// It's the gas limit to replay a skipped message with.
+func (c *Controller) GetL1MessageGasLimit(ctx *gin.Context) {
+	var lp L1MessageGasLimitParameter
+	if err := ctx.ShouldBindQuery(&lp); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	l1Message, err := c.l1MessageOrm.GetL1MessageByQueueIndex(ctx.Copy(), *lp.QueueIndex)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	if l1Message == nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("l1 message %d not found", *lp.QueueIndex))
+		return
+	}
+	gasLimit, err := c.gasEstimator.EstimateGasLimit(ctx.Copy(), common.FromHex(l1Message.Calldata))
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, L1MessageGasLimitSchema{QueueIndex: *lp.QueueIndex, GasLimit: gasLimit})
+}
+
+// GetL1MessageGasUsed returns the l2 gas used by the l1 messages included on l2, of the sender if it's set, the latest first.
+func (c *Controller) GetL1MessageGasUsed(ctx *gin.Context) {
+	gp, ok := bindL1MessageGasUsedParameter(ctx)
+	if !ok {
+		return
+	}
+
+	l1Messages, err := c.l1MessageOrm.GetIncludedL1Messages(ctx.Copy(), gp.Sender, gp.Offset, gp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+
+	schemas := make([]L1MessageGasUsedSchema, 0, len(l1Messages))
+	for _, l1Message := range l1Messages {
+		var gasUsed uint64
+		if l1Message.GasUsed != nil {
+			gasUsed = *l1Message.GasUsed
+		}
+		schemas = append(schemas, L1MessageGasUsedSchema{
+			QueueIndex: l1Message.QueueIndex,
+			MsgHash:    l1Message.MsgHash,
+			Sender:     l1Message.Sender,
+			Target:     l1Message.Target,
+			Layer1Hash: l1Message.Layer1Hash,
+			Layer2Hash: l1Message.Layer2Hash,
+			GasLimit:   l1Message.GasLimit,
+			GasUsed:    gasUsed,
+			UnusedGas:  unusedGas(l1Message.GasLimit, gasUsed),
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// GetL1MessageGasReport returns the l2 gas used by the l1 messages included on l2 per sender, of the sender if it's set,
+// the senders whose messages used the most gas first. The unused gas is what a fee refund is computed from.
+func (c *Controller) GetL1MessageGasReport(ctx *gin.Context) {
+	gp, ok := bindL1MessageGasUsedParameter(ctx)
+	if !ok {
+		return
+	}
+
+	usages, err := c.l1MessageOrm.GetL1MessageGasUsageBySender(ctx.Copy(), gp.Sender, gp.Offset, gp.Limit)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+
+	schemas := make([]L1MessageGasReportSchema, 0, len(usages))
+	for _, usage := range usages {
+		schemas = append(schemas, L1MessageGasReportSchema{
+			Sender:         usage.Sender,
+			MessageCount:   usage.MessageCount,
+			TotalGasLimit:  usage.TotalGasLimit,
+			TotalGasUsed:   usage.TotalGasUsed,
+			TotalUnusedGas: unusedGas(usage.TotalGasLimit, usage.TotalGasUsed),
+		})
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// bindL1MessageGasUsedParameter binds the parameter and checksums the sender as the l1 watcher stores it, it renders
+// the failure and returns false if the parameter is invalid.
+func bindL1MessageGasUsedParameter(ctx *gin.Context) (*L1MessageGasUsedParameter, bool) {
+	var gp L1MessageGasUsedParameter
+	if err := ctx.ShouldBindQuery(&gp); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return nil, false
+	}
+	if gp.Sender != "" {
+		if !common.IsHexAddress(gp.Sender) {
+			types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, sender is not an address: %s", gp.Sender))
+			return nil, false
+		}
+		gp.Sender = common.HexToAddress(gp.Sender).String()
+	}
+	if gp.Limit == 0 {
+		gp.Limit = defaultListLimit
+	}
+	return &gp, true
+}
+
+// unusedGas returns the gas limit left over, the gas used never exceeds the gas limit of an included message.
+func unusedGas(gasLimit, gasUsed uint64) uint64 {
+	if gasUsed >= gasLimit {
+		return 0
+	}
+	return gasLimit - gasUsed
+}
+
+// GetFinalityHeights returns the latest L2 blocks covered by a batch committed and by a batch finalized on L1.
+func (c *Controller) GetFinalityHeights(ctx *gin.Context) {
+	heights := c.finalityTracker.Heights()
+	if heights == nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, errors.New("finality heights not computed yet"))
+		return
+	}
+	types.RenderSuccess(ctx, FinalityHeightsSchema{
+		Safe:      toFinalityHeightSchema(heights.Safe),
+		Finalized: toFinalityHeightSchema(heights.Finalized),
+		UpdatedAt: heights.UpdatedAt.Unix(),
+	})
+}
+
+func toFinalityHeightSchema(height *watcher.FinalityHeight) *FinalityHeightSchema {
+	if height == nil {
+		return nil
+	}
+	return &FinalityHeightSchema{
+		BlockNumber:    height.BlockNumber,
+		BatchIndex:     height.BatchIndex,
+		BatchHash:      height.BatchHash,
+		CommitTxHash:   height.CommitTxHash,
+		FinalizeTxHash: height.FinalizeTxHash,
+	}
+}
+
+// GetArchivedObjects returns the index entries of the archived block traces and proofs of the batch.
+func (c *Controller) GetArchivedObjects(ctx *gin.Context) {
+	var ap ArchivedObjectsParameter
+	if err := ctx.ShouldBindQuery(&ap); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	// the batch and chunk rows are never pruned, they give the keys of the archived objects.
+	batch, err := c.batchOrm.GetBatchByIndex(ctx.Copy(), *ap.BatchIndex)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	chunks, err := c.chunkOrm.GetChunksInRange(ctx.Copy(), batch.StartChunkIndex, batch.EndChunkIndex)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	keys := map[string][]string{orm.ArchivedObjectKindBatchProof: {batch.Hash}}
+	for _, chunk := range chunks {
+		keys[orm.ArchivedObjectKindChunkProof] = append(keys[orm.ArchivedObjectKindChunkProof], chunk.Hash)
+		for number := chunk.StartBlockNumber; number <= chunk.EndBlockNumber; number++ {
+			keys[orm.ArchivedObjectKindL2Block] = append(keys[orm.ArchivedObjectKindL2Block], strconv.FormatUint(number, 10))
+		}
+	}
+
+	schemas := make([]ArchivedObjectSchema, 0)
+	for _, kind := range []string{orm.ArchivedObjectKindBatchProof, orm.ArchivedObjectKindChunkProof, orm.ArchivedObjectKindL2Block} {
+		archivedObjects, err := c.archivedObjectOrm.GetArchivedObjects(ctx.Copy(), kind, keys[kind])
+		if err != nil {
+			types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+			return
+		}
+		for _, archivedObject := range archivedObjects {
+			schemas = append(schemas, ArchivedObjectSchema{
+				Kind:        archivedObject.Kind,
+				Key:         archivedObject.ObjectKey,
+				URI:         archivedObject.URI,
+				ContentHash: archivedObject.ContentHash,
+				Size:        archivedObject.Size,
+				ArchivedAt:  archivedObject.UpdatedAt.Unix(),
+			})
+		}
+	}
+	types.RenderSuccess(ctx, schemas)
+}
+
+// GetArchivedObject reads the archived object back from the archive storage, e.g. the exact proof of a historical
+// batch for an audit. The data is checked against the content hash recorded when it was archived.
+func (c *Controller) GetArchivedObject(ctx *gin.Context) {
+	var ap ArchivedObjectParameter
+	if err := ctx.ShouldBindQuery(&ap); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	archivedObject, err := c.archivedObjectOrm.GetArchivedObject(ctx.Copy(), ap.Kind, ap.Key)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	if archivedObject == nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("%s %s is not archived", ap.Kind, ap.Key))
+		return
+	}
+	data, err := objectstore.Download(ctx.Copy(), c.archiveStore, archivedObject.URI, archivedObject.ContentHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	types.RenderSuccess(ctx, ArchivedObjectDataSchema{
+		Kind:        archivedObject.Kind,
+		Key:         archivedObject.ObjectKey,
+		ContentHash: archivedObject.ContentHash,
+		Data:        data,
+	})
+}
+
+// GetBatchApprovals returns the approval digest of the batch and the approvers which approved it.
+func (c *Controller) GetBatchApprovals(ctx *gin.Context) {
+	var bp BatchApprovalsParameter
+	if err := ctx.ShouldBindQuery(&bp); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+
+	batch, err := c.batchOrm.GetBatchByHash(ctx.Copy(), bp.BatchHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	approvals, err := c.batchApprovalOrm.GetBatchApprovals(ctx.Copy(), batch.Hash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+
+	schema := BatchApprovalsSchema{
+		BatchIndex:     batch.Index,
+		BatchHash:      batch.Hash,
+		ApprovalDigest: batch.ApprovalDigest().Hex(),
+		Approvers:      make([]string, 0, len(approvals)),
+		Threshold:      c.finalizeApproval.Threshold,
+	}
+	for _, approval := range approvals {
+		schema.Approvers = append(schema.Approvers, approval.Approver)
+	}
+	types.RenderSuccess(ctx, schema)
+}
+
+// ApproveBatch records the approval of a batch by a co-signer, the approval must be signed by one of the approvers.
+func (c *Controller) ApproveBatch(ctx *gin.Context) {
+	var bp BatchApprovalParameter
+	if err := ctx.ShouldBind(&bp); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, err:%w", err))
+		return
+	}
+	signature, err := hexutil.Decode(bp.Signature)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("parameter invalid, signature must be %d hex bytes", crypto.SignatureLength))
+		return
+	}
+	// accept the signatures with the 27/28 recovery id of the ethereum signers too.
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+
+	batch, err := c.batchOrm.GetBatchByHash(ctx.Copy(), bp.BatchHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	if types.RollupStatus(batch.RollupStatus) == types.RollupFinalized {
+		types.RenderFailure(ctx, types.ErrRollupAdminParameterInvalidNo, fmt.Errorf("batch %d is already finalized", batch.Index))
+		return
+	}
+
+	pubKey, err := crypto.SigToPub(batch.ApprovalDigest().Bytes(), signature)
+	if err != nil {
+		types.RenderFailure(ctx, types.ErrRollupApprovalUnauthorized, fmt.Errorf("invalid signature: %w", err))
+		return
+	}
+	approver := crypto.PubkeyToAddress(*pubKey)
+	if !c.finalizeApproval.IsApprover(approver) {
+		types.RenderFailure(ctx, types.ErrRollupApprovalUnauthorized, fmt.Errorf("%s is not an approver", approver.Hex()))
+		return
+	}
+
+	approval := &orm.BatchApproval{
+		BatchIndex: batch.Index,
+		BatchHash:  batch.Hash,
+		Approver:   approver.Hex(),
+		Signature:  hexutil.Encode(signature),
+	}
+	if err := c.batchApprovalOrm.InsertBatchApproval(ctx.Copy(), approval); err != nil {
+		types.RenderFailure(ctx, types.ErrRollupAdminFailure, err)
+		return
+	}
+	log.Info("batch approved by co-signer", "index", batch.Index, "hash", batch.Hash, "approver", approver.Hex(), "remote", ctx.ClientIP())
+	types.RenderSuccess(ctx, nil)
+}
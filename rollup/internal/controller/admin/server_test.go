@@ -0,0 +1,153 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/objectstore"
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/controller/watcher"
+)
+
+func TestAdminAuthAndParameter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	Route(router, &config.AdminConfig{Secret: "secret"}, &Controller{})
+
+	// the batch approval api is only served when the finalize approval is configured.
+	req := httptest.NewRequest(http.MethodPost, "/approval/v1/batch_approvals", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// so is the finality api without the finality tracker.
+	req = httptest.NewRequest(http.MethodGet, "/finality/v1/heights", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// so is the l1 message gas limit api without the gas estimator.
+	req = httptest.NewRequest(http.MethodGet, "/admin/v1/l1_message_gas_limit?queue_index=1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	request := func(authorization, body string) types.Response {
+		req := httptest.NewRequest(http.MethodPost, "/admin/v1/pause", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp types.Response
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	assert.Equal(t, types.ErrRollupAdminUnauthorized, request("", `{"component":"commit"}`).ErrCode)
+	assert.Equal(t, types.ErrRollupAdminUnauthorized, request("Bearer wrong", `{"component":"commit"}`).ErrCode)
+	assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, request("Bearer secret", `{}`).ErrCode)
+	assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, request("Bearer secret", `{"component":"gas_oracle"}`).ErrCode)
+
+	for _, path := range []string{"/admin/v1/l1_message_gas_used", "/admin/v1/l1_message_gas_report", "/admin/v1/skipped_blocks"} {
+		for _, query := range []string{"?sender=0x1234", "?limit=1001", "?offset=-1"} {
+			if path == "/admin/v1/skipped_blocks" && query == "?sender=0x1234" {
+				continue
+			}
+			req := httptest.NewRequest(http.MethodGet, path+query, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var resp types.Response
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, resp.ErrCode, path+query)
+		}
+	}
+
+	// clearing the flag of a skipped block requires the block number and the reason of the override.
+	for _, body := range []string{`{}`, `{"block_number":1}`, `{"reason":"circuits upgraded"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/admin/v1/unskip_block", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp types.Response
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, resp.ErrCode, body)
+	}
+}
+
+func TestBatchApprovalParameter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	Route(router, &config.AdminConfig{Secret: "secret"}, &Controller{finalizeApproval: &config.FinalizeApprovalConfig{Threshold: 1}})
+
+	request := func(body string) types.Response {
+		req := httptest.NewRequest(http.MethodPost, "/approval/v1/batch_approvals", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp types.Response
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	// the co-signers don't need the admin secret, but must send a well-formed signature.
+	assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, request(`{"batch_hash":"0x01"}`).ErrCode)
+	assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, request(`{"batch_hash":"0x01","signature":"0x1234"}`).ErrCode)
+}
+
+func TestFinalityHeights(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	tracker := watcher.NewFinalityTracker(context.Background(), nil, prometheus.NewRegistry())
+	Route(router, &config.AdminConfig{Secret: "secret"}, &Controller{finalityTracker: tracker})
+
+	// the finality api doesn't require the admin secret.
+	req := httptest.NewRequest(http.MethodGet, "/finality/v1/heights", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp types.Response
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, types.ErrRollupAdminFailure, resp.ErrCode)
+}
+
+func TestArchivedObjectParameter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	archiveStore, err := objectstore.New(&objectstore.Config{Backend: objectstore.FilesystemBackend, Dir: t.TempDir()})
+	assert.NoError(t, err)
+	Route(router, &config.AdminConfig{Secret: "secret"}, &Controller{archiveStore: archiveStore})
+
+	request := func(url string) types.Response {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var resp types.Response
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, request("/admin/v1/archived_objects").ErrCode)
+	assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, request("/admin/v1/archived_object?kind=l2_block").ErrCode)
+	assert.Equal(t, types.ErrRollupAdminParameterInvalidNo, request("/admin/v1/archived_object?kind=chunk&key=0x01").ErrCode)
+}
@@ -0,0 +1,480 @@
+package relayer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/scroll-tech/da-codec/encoding/codecv0"
+	"github.com/scroll-tech/da-codec/encoding/codecv1"
+	"github.com/scroll-tech/da-codec/encoding/codecv2"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+	cencoding "scroll-tech/common/types/encoding"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const (
+	batchDACheckerCursorName          = "batch_da_checker"
+	defaultBatchDACheckerEventsPerRun = 100
+	// beaconSecondsPerSlot is the slot time of the beacon chain, the slot of an L1 block is derived from its timestamp.
+	beaconSecondsPerSlot = 12
+)
+
+// daCheckerL1Client is the part of the L1 client used by the BatchDAChecker.
+type daCheckerL1Client interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*gethTypes.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethTypes.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+}
+
+// BatchDAChecker checks the data of the batches posted on L1 once their commit transaction is confirmed:
+// it re-derives the chunk data hashes and the commit payload of the batch from the database, and compares them
+// with the calldata and the blob of the commit transaction, and with the batch hash computed by the contract.
+// A mismatch means a corrupted commitment and raises a critical alert. It follows the batch status transitions
+// recorded in the status_audit_log table from a persisted cursor, like the batch notifier.
+type BatchDAChecker struct {
+	ctx context.Context
+	cfg *config.BatchDACheckerConfig
+
+	l1Client     daCheckerL1Client
+	beaconClient *resty.Client
+	alertClient  *resty.Client
+	l1RollupABI  *abi.ABI
+	chainCfg     *params.ChainConfig
+	eventsPerRun int
+	// beaconGenesisTime is the genesis time of the beacon chain, read once from the beacon node.
+	beaconGenesisTime uint64
+
+	batchOrm          *orm.Batch
+	chunkOrm          *orm.Chunk
+	l2BlockOrm        *orm.L2Block
+	statusAuditLogOrm *orm.StatusAuditLog
+	notifierCursorOrm *orm.NotifierCursor
+
+	batchDACheckerCheckedTotal  prometheus.Counter
+	batchDACheckerMismatchTotal prometheus.Counter
+	batchDACheckerFailureTotal  prometheus.Counter
+}
+
+// NewBatchDAChecker creates a new BatchDAChecker instance.
+func NewBatchDAChecker(ctx context.Context, cfg *config.BatchDACheckerConfig, l1Client daCheckerL1Client, db *gorm.DB, chainCfg *params.ChainConfig, reg prometheus.Registerer) *BatchDAChecker {
+	eventsPerRun := cfg.MaxEventsPerRun
+	if eventsPerRun <= 0 {
+		eventsPerRun = defaultBatchDACheckerEventsPerRun
+	}
+
+	c := &BatchDAChecker{
+		ctx:          ctx,
+		cfg:          cfg,
+		l1Client:     l1Client,
+		l1RollupABI:  bridgeAbi.ScrollChainABI,
+		chainCfg:     chainCfg,
+		eventsPerRun: eventsPerRun,
+
+		batchOrm:          orm.NewBatch(db),
+		chunkOrm:          orm.NewChunk(db),
+		l2BlockOrm:        orm.NewL2Block(db),
+		statusAuditLogOrm: orm.NewStatusAuditLog(db),
+		notifierCursorOrm: orm.NewNotifierCursor(db),
+
+		batchDACheckerCheckedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_batch_da_checker_checked_total",
+			Help: "Total number of committed batches whose data was checked on L1.",
+		}),
+		batchDACheckerMismatchTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_batch_da_checker_mismatch_total",
+			Help: "Total number of committed batches whose data on L1 doesn't match the database.",
+		}),
+		batchDACheckerFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_batch_da_checker_failure_total",
+			Help: "Total number of batch da checker runs stopped by a failure, the batch is checked again by the next run.",
+		}),
+	}
+	if cfg.BeaconEndpoint != "" {
+		c.beaconClient = resty.New().SetBaseURL(cfg.BeaconEndpoint).SetTimeout(30 * time.Second)
+	}
+	if cfg.AlertWebhookURL != "" {
+		c.alertClient = resty.New().SetTimeout(10 * time.Second)
+	}
+	return c
+}
+
+// TryCheckBatches checks the batches committed since the cursor, and moves the cursor past every checked one.
+// A failure to fetch the data stops the run, the batch is checked again by the next run.
+func (c *BatchDAChecker) TryCheckBatches() {
+	lastID, err := c.notifierCursorOrm.GetLastID(c.ctx, batchDACheckerCursorName)
+	if err != nil {
+		c.batchDACheckerFailureTotal.Inc()
+		log.Error("failed to get batch da checker cursor", "err", err)
+		return
+	}
+	if lastID == nil {
+		// a new checker starts from now, the batches committed before are not checked.
+		latestID, latestErr := c.statusAuditLogOrm.GetLatestID(c.ctx)
+		if latestErr != nil {
+			c.batchDACheckerFailureTotal.Inc()
+			log.Error("failed to get latest status audit log id", "err", latestErr)
+			return
+		}
+		if err = c.notifierCursorOrm.UpdateLastID(c.ctx, batchDACheckerCursorName, latestID); err != nil {
+			c.batchDACheckerFailureTotal.Inc()
+			log.Error("failed to init batch da checker cursor", "err", err)
+		}
+		return
+	}
+
+	logs, err := c.statusAuditLogOrm.GetStatusAuditLogsAfterID(c.ctx, "batch", *lastID, c.eventsPerRun)
+	if err != nil {
+		c.batchDACheckerFailureTotal.Inc()
+		log.Error("failed to get batch status transitions", "after id", *lastID, "err", err)
+		return
+	}
+
+	for _, auditLog := range logs {
+		if auditLog.StatusColumn == "rollup_status" && types.RollupStatus(auditLog.ToStatus) == types.RollupCommitted {
+			if err = c.checkBatchByHash(auditLog.RecordKey); err != nil {
+				c.batchDACheckerFailureTotal.Inc()
+				log.Error("failed to check the data of the committed batch", "batch hash", auditLog.RecordKey, "err", err)
+				return
+			}
+		}
+		if err = c.notifierCursorOrm.UpdateLastID(c.ctx, batchDACheckerCursorName, auditLog.ID); err != nil {
+			c.batchDACheckerFailureTotal.Inc()
+			log.Error("failed to update batch da checker cursor", "last id", auditLog.ID, "err", err)
+			return
+		}
+	}
+}
+
+func (c *BatchDAChecker) checkBatchByHash(batchHash string) error {
+	batch, err := c.batchOrm.GetBatchByHash(c.ctx, batchHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// the batch was deleted by an L2 reorg.
+		log.Warn("skip the da check of a deleted batch", "batch hash", batchHash)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	mismatches, err := c.checkBatch(batch)
+	if err != nil {
+		return err
+	}
+	c.batchDACheckerCheckedTotal.Inc()
+	if len(mismatches) == 0 {
+		log.Debug("batch data on L1 matches the database", "index", batch.Index, "batch hash", batch.Hash)
+		return nil
+	}
+
+	c.batchDACheckerMismatchTotal.Inc()
+	log.Error("batch data on L1 doesn't match the database, the commitment is corrupted", "index", batch.Index, "batch hash", batch.Hash,
+		"commit tx hash", batch.CommitTxHash, "mismatches", mismatches)
+	c.sendAlert(batch, mismatches)
+	return nil
+}
+
+// checkBatch compares the data of the batch on L1 with the database, it returns the mismatches found.
+func (c *BatchDAChecker) checkBatch(batch *orm.Batch) ([]string, error) {
+	dbParentBatch, dbChunks, chunks, err := loadBatchChunks(c.ctx, c.batchOrm, c.chunkOrm, c.l2BlockOrm, batch)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := newCommitBatchPayload(c.chainCfg, batch, dbParentBatch, dbChunks, chunks)
+	if err != nil {
+		return nil, err
+	}
+	batchHeader, err := cencoding.DecodeBatchHeader(batch.BatchHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode batch header: %w", err)
+	}
+
+	var mismatches []string
+
+	// the chunk data hashes re-derived from the blocks, and the batch data hash committed to by the header.
+	chunkHashes := make([]byte, 0, len(chunks)*common.HashLength)
+	for i, chunk := range chunks {
+		chunkHash, hashErr := chunkDataHash(payload.version, chunk, dbChunks[i].TotalL1MessagesPoppedBefore)
+		if hashErr != nil {
+			return nil, fmt.Errorf("failed to hash chunk %d: %w", dbChunks[i].Index, hashErr)
+		}
+		if chunkHash != common.HexToHash(dbChunks[i].Hash) {
+			mismatches = append(mismatches, fmt.Sprintf("chunk %d data hash %s, database %s", dbChunks[i].Index, chunkHash.Hex(), dbChunks[i].Hash))
+		}
+		chunkHashes = append(chunkHashes, chunkHash.Bytes()...)
+	}
+	if dataHash := crypto.Keccak256Hash(chunkHashes); dataHash != batchHeader.DataHash {
+		mismatches = append(mismatches, fmt.Sprintf("batch data hash %s, header %s", dataHash.Hex(), batchHeader.DataHash.Hex()))
+	}
+
+	txHash := common.HexToHash(batch.CommitTxHash)
+	tx, _, err := c.l1Client.TransactionByHash(c.ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit tx %s: %w", txHash.Hex(), err)
+	}
+	receipt, err := c.l1Client.TransactionReceipt(c.ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit tx receipt %s: %w", txHash.Hex(), err)
+	}
+
+	committedHash, found := committedBatchHash(receipt, batch.Index)
+	if !found {
+		mismatches = append(mismatches, fmt.Sprintf("no CommitBatch event of the batch in tx %s", txHash.Hex()))
+	} else if committedHash != common.HexToHash(batch.Hash) {
+		mismatches = append(mismatches, fmt.Sprintf("committed batch hash %s, database %s", committedHash.Hex(), batch.Hash))
+	}
+
+	calldataMismatches, err := c.compareCalldata(tx.Data(), payload)
+	if err != nil {
+		return nil, err
+	}
+	mismatches = append(mismatches, calldataMismatches...)
+
+	if payload.blob != nil {
+		blobMismatches, blobErr := c.compareBlob(tx, receipt, payload.blob, batchHeader.BlobVersionedHash)
+		if blobErr != nil {
+			return nil, blobErr
+		}
+		mismatches = append(mismatches, blobMismatches...)
+	}
+	return mismatches, nil
+}
+
+// compareCalldata compares the arguments of the commitBatch calldata with the payload re-derived from the database.
+func (c *BatchDAChecker) compareCalldata(calldata []byte, payload *commitBatchPayload) ([]string, error) {
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("commit tx calldata too short: %d bytes", len(calldata))
+	}
+	method, err := c.l1RollupABI.MethodById(calldata[:4])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode commit tx method: %w", err)
+	}
+	if method.Name != "commitBatch" {
+		return nil, fmt.Errorf("unexpected commit tx method: %s", method.Name)
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s calldata: %w", method.Name, err)
+	}
+	if len(args) != 4 {
+		return nil, fmt.Errorf("unexpected %s arguments number: %d", method.Name, len(args))
+	}
+	version, _ := args[0].(uint8)
+	parentBatchHeader, _ := args[1].([]byte)
+	chunks, _ := args[2].([][]byte)
+	bitmap, _ := args[3].([]byte)
+
+	var mismatches []string
+	if version != payload.version {
+		mismatches = append(mismatches, fmt.Sprintf("calldata version %d, expected %d", version, payload.version))
+	}
+	if !bytes.Equal(parentBatchHeader, payload.parentBatchHeader) {
+		mismatches = append(mismatches, "calldata parent batch header differs from the database")
+	}
+	if len(chunks) != len(payload.chunks) {
+		mismatches = append(mismatches, fmt.Sprintf("calldata has %d chunks, expected %d", len(chunks), len(payload.chunks)))
+	} else {
+		for i := range chunks {
+			if !bytes.Equal(chunks[i], payload.chunks[i]) {
+				mismatches = append(mismatches, fmt.Sprintf("calldata chunk %d differs from the database", i))
+			}
+		}
+	}
+	if !bytes.Equal(bitmap, payload.skippedL1MessageBitmap) {
+		mismatches = append(mismatches, "calldata skipped l1 message bitmap differs from the database")
+	}
+	return mismatches, nil
+}
+
+// compareBlob compares the blob of the batch with the one re-derived from the database, by its versioned hash,
+// and by its content when the blob is downloaded from the beacon node.
+func (c *BatchDAChecker) compareBlob(tx *gethTypes.Transaction, receipt *gethTypes.Receipt, blob *kzg4844.Blob, headerVersionedHash common.Hash) ([]string, error) {
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute blob commitment: %w", err)
+	}
+	versionedHash := common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment))
+
+	var mismatches []string
+	if versionedHash != headerVersionedHash {
+		mismatches = append(mismatches, fmt.Sprintf("blob versioned hash %s, header %s", versionedHash.Hex(), headerVersionedHash.Hex()))
+	}
+	blobHashes := tx.BlobHashes()
+	if len(blobHashes) != 1 {
+		return append(mismatches, fmt.Sprintf("commit tx carries %d blobs, expected 1", len(blobHashes))), nil
+	}
+	if blobHashes[0] != versionedHash {
+		return append(mismatches, fmt.Sprintf("commit tx blob versioned hash %s, expected %s", blobHashes[0].Hex(), versionedHash.Hex())), nil
+	}
+
+	if c.beaconClient == nil {
+		return mismatches, nil
+	}
+	downloaded, err := c.downloadBlob(receipt.BlockNumber, versionedHash)
+	if err != nil {
+		return nil, err
+	}
+	if downloaded == nil {
+		return append(mismatches, fmt.Sprintf("blob %s is not available on the beacon node", versionedHash.Hex())), nil
+	}
+	if !bytes.Equal(downloaded, blob[:]) {
+		mismatches = append(mismatches, fmt.Sprintf("blob %s downloaded from the beacon node differs from the database", versionedHash.Hex()))
+	}
+	return mismatches, nil
+}
+
+// beaconGenesisResponse the response of the beacon api /eth/v1/beacon/genesis.
+type beaconGenesisResponse struct {
+	Data struct {
+		GenesisTime string `json:"genesis_time"`
+	} `json:"data"`
+}
+
+// blobSidecarsResponse the response of the beacon api /eth/v1/beacon/blob_sidecars/{block_id}.
+type blobSidecarsResponse struct {
+	Data []struct {
+		Blob          hexutil.Bytes `json:"blob"`
+		KZGCommitment hexutil.Bytes `json:"kzg_commitment"`
+	} `json:"data"`
+}
+
+// downloadBlob downloads the blob of the versioned hash included in the L1 block from the beacon node,
+// it returns nil if the block has no such blob.
+func (c *BatchDAChecker) downloadBlob(blockNumber *big.Int, versionedHash common.Hash) ([]byte, error) {
+	if c.beaconGenesisTime == 0 {
+		var genesis beaconGenesisResponse
+		resp, err := c.beaconClient.R().SetContext(c.ctx).ForceContentType("application/json").SetResult(&genesis).Get("/eth/v1/beacon/genesis")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get beacon genesis: %w", err)
+		}
+		if resp.IsError() {
+			return nil, fmt.Errorf("failed to get beacon genesis, status: %s", resp.Status())
+		}
+		if c.beaconGenesisTime, err = strconv.ParseUint(genesis.Data.GenesisTime, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid beacon genesis time %q: %w", genesis.Data.GenesisTime, err)
+		}
+	}
+
+	header, err := c.l1Client.HeaderByNumber(c.ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get l1 block %v: %w", blockNumber, err)
+	}
+	if header.Time < c.beaconGenesisTime {
+		return nil, fmt.Errorf("l1 block %v is before the beacon genesis", blockNumber)
+	}
+	slot := (header.Time - c.beaconGenesisTime) / beaconSecondsPerSlot
+
+	var sidecars blobSidecarsResponse
+	resp, err := c.beaconClient.R().SetContext(c.ctx).ForceContentType("application/json").SetResult(&sidecars).Get(fmt.Sprintf("/eth/v1/beacon/blob_sidecars/%d", slot))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob sidecars of slot %d: %w", slot, err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to get blob sidecars of slot %d, status: %s", slot, resp.Status())
+	}
+	for _, sidecar := range sidecars.Data {
+		var commitment kzg4844.Commitment
+		if len(sidecar.KZGCommitment) != len(commitment) {
+			continue
+		}
+		copy(commitment[:], sidecar.KZGCommitment)
+		if common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment)) == versionedHash {
+			return sidecar.Blob, nil
+		}
+	}
+	return nil, nil
+}
+
+// batchDAAlert the body of the alert posted to the webhook
+type batchDAAlert struct {
+	Message      string   `json:"message"`
+	BatchIndex   uint64   `json:"batch_index"`
+	BatchHash    string   `json:"batch_hash"`
+	CommitTxHash string   `json:"commit_tx_hash"`
+	Mismatches   []string `json:"mismatches"`
+	Severity     string   `json:"severity"`
+}
+
+func (c *BatchDAChecker) sendAlert(batch *orm.Batch, mismatches []string) {
+	if c.alertClient == nil {
+		return
+	}
+
+	alert := batchDAAlert{
+		Message:      fmt.Sprintf("the data of batch %d committed on L1 doesn't match the database, the commitment is corrupted", batch.Index),
+		BatchIndex:   batch.Index,
+		BatchHash:    batch.Hash,
+		CommitTxHash: batch.CommitTxHash,
+		Mismatches:   mismatches,
+		Severity:     "critical",
+	}
+	resp, err := c.alertClient.R().SetBody(alert).Post(c.cfg.AlertWebhookURL)
+	if err != nil {
+		log.Error("failed to send batch da alert", "index", batch.Index, "err", err)
+		return
+	}
+	if resp.IsError() {
+		log.Error("failed to send batch da alert", "index", batch.Index, "status", resp.Status())
+	}
+}
+
+// committedBatchHash returns the batch hash of the CommitBatch event of the batch index in the receipt.
+func committedBatchHash(receipt *gethTypes.Receipt, batchIndex uint64) (common.Hash, bool) {
+	for _, vLog := range receipt.Logs {
+		if len(vLog.Topics) != 3 || vLog.Topics[0] != bridgeAbi.L1CommitBatchEventSignature {
+			continue
+		}
+		if vLog.Topics[1].Big().Uint64() == batchIndex {
+			return vLog.Topics[2], true
+		}
+	}
+	return common.Hash{}, false
+}
+
+// chunkDataHash re-derives the data hash of the chunk with the codec of the batch version.
+func chunkDataHash(version uint8, chunk *encoding.Chunk, totalL1MessagePoppedBefore uint64) (common.Hash, error) {
+	switch version {
+	case 0:
+		daChunk, err := codecv0.NewDAChunk(chunk, totalL1MessagePoppedBefore)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return daChunk.Hash()
+	case 1:
+		daChunk, err := codecv1.NewDAChunk(chunk, totalL1MessagePoppedBefore)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return daChunk.Hash()
+	case 2:
+		daChunk, err := codecv2.NewDAChunk(chunk, totalL1MessagePoppedBefore)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return daChunk.Hash()
+	default:
+		return common.Hash{}, fmt.Errorf("unsupported codec version: %d", version)
+	}
+}
@@ -0,0 +1,121 @@
+package relayer
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/assert"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+type mockDACheckerL1Client struct {
+	header *gethTypes.Header
+}
+
+func (m *mockDACheckerL1Client) TransactionByHash(context.Context, common.Hash) (*gethTypes.Transaction, bool, error) {
+	return nil, false, nil
+}
+
+func (m *mockDACheckerL1Client) TransactionReceipt(context.Context, common.Hash) (*gethTypes.Receipt, error) {
+	return nil, nil
+}
+
+func (m *mockDACheckerL1Client) HeaderByNumber(context.Context, *big.Int) (*gethTypes.Header, error) {
+	return m.header, nil
+}
+
+func TestBatchDACheckerCompareCalldata(t *testing.T) {
+	checker := NewBatchDAChecker(context.Background(), &config.BatchDACheckerConfig{}, &mockDACheckerL1Client{}, nil, nil, prometheus.NewRegistry())
+	r := &Layer2Relayer{l1RollupABI: bridgeAbi.ScrollChainABI}
+
+	payload := &commitBatchPayload{version: 2, parentBatchHeader: []byte{0xaa}, chunks: [][]byte{{1}, {2}}, skippedL1MessageBitmap: []byte{}}
+	calldata, err := r.packCommitBatch(payload)
+	assert.NoError(t, err)
+	mismatches, err := checker.compareCalldata(calldata, payload)
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	// a corrupted chunk
+	corrupted := &commitBatchPayload{version: 2, parentBatchHeader: []byte{0xaa}, chunks: [][]byte{{1}, {4}}, skippedL1MessageBitmap: []byte{}}
+	mismatches, err = checker.compareCalldata(calldata, corrupted)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"calldata chunk 1 differs from the database"}, mismatches)
+
+	// not a commit tx
+	calldata, err = bridgeAbi.ScrollChainABI.Pack("lastFinalizedBatchIndex")
+	assert.NoError(t, err)
+	_, err = checker.compareCalldata(calldata, payload)
+	assert.ErrorContains(t, err, "unexpected commit tx method")
+}
+
+func TestBatchDACheckerCommittedBatchHash(t *testing.T) {
+	batchHash := common.HexToHash("0x1234")
+	receipt := &gethTypes.Receipt{Logs: []*gethTypes.Log{
+		{Topics: []common.Hash{bridgeAbi.L1CommitBatchEventSignature, common.BigToHash(big.NewInt(10)), common.HexToHash("0x01")}},
+		{Topics: []common.Hash{bridgeAbi.L1CommitBatchEventSignature, common.BigToHash(big.NewInt(11)), batchHash}},
+	}}
+
+	hash, found := committedBatchHash(receipt, 11)
+	assert.True(t, found)
+	assert.Equal(t, batchHash, hash)
+	_, found = committedBatchHash(receipt, 12)
+	assert.False(t, found)
+}
+
+func TestBatchDACheckerCompareBlob(t *testing.T) {
+	blob := &kzg4844.Blob{1, 2, 3}
+	commitment, err := kzg4844.BlobToCommitment(blob)
+	assert.NoError(t, err)
+	versionedHash := common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment))
+
+	const genesisTime, slot = 1606824023, 100
+	served := blob[:]
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/eth/v1/beacon/genesis":
+			_, _ = fmt.Fprintf(w, `{"data":{"genesis_time":"%d"}}`, genesisTime)
+		case fmt.Sprintf("/eth/v1/beacon/blob_sidecars/%d", slot):
+			_, _ = fmt.Fprintf(w, `{"data":[{"index":"0","blob":"%s","kzg_commitment":"%s"}]}`, hexutil.Encode(served), hexutil.Encode(commitment[:]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	l1Client := &mockDACheckerL1Client{header: &gethTypes.Header{Time: genesisTime + slot*beaconSecondsPerSlot + 5}}
+	checker := NewBatchDAChecker(context.Background(), &config.BatchDACheckerConfig{BeaconEndpoint: server.URL}, l1Client, nil, nil, prometheus.NewRegistry())
+	tx := gethTypes.NewTx(&gethTypes.BlobTx{BlobHashes: []common.Hash{versionedHash}})
+	receipt := &gethTypes.Receipt{BlockNumber: big.NewInt(1)}
+
+	mismatches, err := checker.compareBlob(tx, receipt, blob, versionedHash)
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	// the blob of the header differs from the one re-derived from the blocks
+	mismatches, err = checker.compareBlob(tx, receipt, blob, common.HexToHash("0x01"))
+	assert.NoError(t, err)
+	assert.Len(t, mismatches, 1)
+
+	// the commit tx doesn't carry the blob of the batch
+	mismatches, err = checker.compareBlob(gethTypes.NewTx(&gethTypes.BlobTx{}), receipt, blob, versionedHash)
+	assert.NoError(t, err)
+	assert.Len(t, mismatches, 1)
+
+	// the blob served by the beacon node differs
+	served = make([]byte, len(blob))
+	mismatches, err = checker.compareBlob(tx, receipt, blob, versionedHash)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fmt.Sprintf("blob %s downloaded from the beacon node differs from the database", versionedHash.Hex())}, mismatches)
+}
@@ -6,6 +6,10 @@ const (
 	gasPriceDiffPrecision = 1000000
 
 	defaultGasPriceDiff = 50000 // 5%
+
+	// contextIDSeparator joins the batch hashes in the context id of a finalizeBundleWithProof4844 transaction.
+	// It's only used by the finalize bundles, a commitBatch transaction carries the hash of its single batch.
+	contextIDSeparator = ","
 )
 
 var (
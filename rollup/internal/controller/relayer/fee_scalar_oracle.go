@@ -0,0 +1,292 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const (
+	// DefaultFeeScalarUpdateIntervalSec is how often the fee scalars are derived if the config doesn't set it.
+	DefaultFeeScalarUpdateIntervalSec = 300
+	defaultFeeScalarBatchWindow       = 50
+
+	// feeScalarPrecision is the PRECISION of the L1GasPriceOracle, the scalars have 9 decimals.
+	feeScalarPrecision = 1e9
+	// The bounds the L1GasPriceOracle enforces on the parameters.
+	maxFeeOverhead     = 30000000 / 16
+	maxFeeScalar       = 1000 * feeScalarPrecision
+	maxFeeCommitScalar = 1e9 * feeScalarPrecision
+	maxFeeBlobScalar   = 1e9 * feeScalarPrecision
+	// txSignatureGas is the calldata gas the L1GasPriceOracle adds to a tx for its signature before Curie.
+	txSignatureGas = 4 * 16
+
+	// feeScalarContextIDPrefix prefixes the context id of the fee scalar transactions of the gas oracle sender,
+	// whose other transactions are keyed by the L1 block hash.
+	feeScalarContextIDPrefix = "fee-scalar-"
+)
+
+// FeeScalars are the parameters of the L1GasPriceOracle the L1 fee of the L2 txs is computed with. Since Curie the
+// fee is (commitScalar * l1BaseFee + blobScalar * len(tx) * l1BlobBaseFee) / 1e9, before it's
+// (calldataGas(tx) + overhead) * l1BaseFee * scalar / 1e9.
+type FeeScalars struct {
+	CommitScalar uint64
+	BlobScalar   uint64
+	Overhead     uint64
+	Scalar       uint64
+	// BatchIndex is the latest finalized batch the scalars were derived up to.
+	BatchIndex uint64
+}
+
+// feeScalarL1Client is the part of the l1geth client the FeeScalarOracle reads the rollup costs with.
+type feeScalarL1Client interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethTypes.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+}
+
+// batchTxStats are the L2 txs of a batch paying the L1 fee, i.e. without the l1 messages.
+type batchTxStats struct {
+	txNum       uint64
+	txBytes     uint64
+	calldataGas uint64
+}
+
+// l1TxCost is the L1 cost of a commit or finalize transaction.
+type l1TxCost struct {
+	gasUsed uint64
+	// baseFeeGas is the cost of the execution gas divided by the base fee of its block, the tips included.
+	baseFeeGas float64
+	blobGas    uint64
+}
+
+// feeScalarCosts sums up the L1 costs of the batches of a window and their L2 txs paying the L1 fee.
+type feeScalarCosts struct {
+	batchTxStats
+	gasUsed    float64
+	baseFeeGas float64
+	blobGas    float64
+}
+
+// FeeScalarOracle derives the fee scalars of the L1GasPriceOracle from the receipts of the commit and finalize
+// transactions of the latest finalized batches, so that the L1 fee paid by the L2 txs covers the rollup costs. A
+// transaction shared with the batches out of the window is only accounted for the share of its batches in the window.
+type FeeScalarOracle struct {
+	ctx      context.Context
+	l1Client feeScalarL1Client
+
+	batchOrm   *orm.Batch
+	chunkOrm   *orm.Chunk
+	l2BlockOrm *orm.L2Block
+
+	batchWindow int
+
+	// batchTxs and txCosts cache the finalized batches and their transactions, none of them change. They only keep
+	// the entries of the latest window.
+	batchTxs map[string]*batchTxStats
+	txCosts  map[string]*l1TxCost
+}
+
+// NewFeeScalarOracle creates a new FeeScalarOracle instance.
+func NewFeeScalarOracle(ctx context.Context, cfg *config.FeeScalarOracleConfig, l1Client feeScalarL1Client, db *gorm.DB) *FeeScalarOracle {
+	batchWindow := cfg.BatchWindow
+	if batchWindow <= 0 {
+		batchWindow = defaultFeeScalarBatchWindow
+	}
+	return &FeeScalarOracle{
+		ctx:         ctx,
+		l1Client:    l1Client,
+		batchOrm:    orm.NewBatch(db),
+		chunkOrm:    orm.NewChunk(db),
+		l2BlockOrm:  orm.NewL2Block(db),
+		batchWindow: batchWindow,
+		batchTxs:    make(map[string]*batchTxStats),
+		txCosts:     make(map[string]*l1TxCost),
+	}
+}
+
+// FeeScalars derives the fee scalars from the latest finalized batches, nil if they have no L2 tx paying the L1 fee.
+func (o *FeeScalarOracle) FeeScalars() (*FeeScalars, error) {
+	batches, err := o.batchOrm.GetLatestFinalizedBatches(o.ctx, o.batchWindow)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, nil
+	}
+
+	commitShares, err := o.txShares(batches, func(b *orm.Batch) string { return b.CommitTxHash }, o.batchOrm.GetBatchCountsByCommitTxHashes)
+	if err != nil {
+		return nil, err
+	}
+	finalizeShares, err := o.txShares(batches, func(b *orm.Batch) string { return b.FinalizeTxHash }, o.batchOrm.GetBatchCountsByFinalizeTxHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	var costs feeScalarCosts
+	batchTxs := make(map[string]*batchTxStats, len(batches))
+	for _, batch := range batches {
+		stats, ok := o.batchTxs[batch.Hash]
+		if !ok {
+			if stats, err = o.getBatchTxStats(batch); err != nil {
+				return nil, fmt.Errorf("failed to get the txs of batch %d: %w", batch.Index, err)
+			}
+		}
+		batchTxs[batch.Hash] = stats
+		costs.txNum += stats.txNum
+		costs.txBytes += stats.txBytes
+		costs.calldataGas += stats.calldataGas
+	}
+	o.batchTxs = batchTxs
+
+	txCosts := make(map[string]*l1TxCost, len(commitShares)+len(finalizeShares))
+	for _, shares := range []map[string]float64{commitShares, finalizeShares} {
+		for txHash, share := range shares {
+			cost, ok := o.txCosts[txHash]
+			if !ok {
+				if cost, err = o.getL1TxCost(txHash); err != nil {
+					return nil, fmt.Errorf("failed to get the cost of tx %s: %w", txHash, err)
+				}
+			}
+			txCosts[txHash] = cost
+			costs.gasUsed += share * float64(cost.gasUsed)
+			costs.baseFeeGas += share * cost.baseFeeGas
+			costs.blobGas += share * float64(cost.blobGas)
+		}
+	}
+	o.txCosts = txCosts
+
+	if costs.txNum == 0 {
+		return nil, nil
+	}
+	scalars := deriveFeeScalars(&costs)
+	scalars.BatchIndex = batches[0].Index
+	return scalars, nil
+}
+
+// txShares returns the share of the batches of the window in each of their transactions.
+func (o *FeeScalarOracle) txShares(batches []*orm.Batch, txHashOf func(*orm.Batch) string, countBatches func(context.Context, []string) (map[string]uint64, error)) (map[string]float64, error) {
+	inWindow := make(map[string]uint64)
+	for _, batch := range batches {
+		if txHash := txHashOf(batch); txHash != "" {
+			inWindow[txHash]++
+		}
+	}
+	txHashes := make([]string, 0, len(inWindow))
+	for txHash := range inWindow {
+		txHashes = append(txHashes, txHash)
+	}
+	counts, err := countBatches(o.ctx, txHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make(map[string]float64, len(inWindow))
+	for txHash, count := range inWindow {
+		total := counts[txHash]
+		if total < count {
+			total = count
+		}
+		shares[txHash] = float64(count) / float64(total)
+	}
+	return shares, nil
+}
+
+func (o *FeeScalarOracle) getBatchTxStats(batch *orm.Batch) (*batchTxStats, error) {
+	chunks, err := o.chunkOrm.GetChunksInRange(o.ctx, batch.StartChunkIndex, batch.EndChunkIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats batchTxStats
+	for _, chunk := range chunks {
+		blocks, getErr := o.l2BlockOrm.GetL2BlocksInRange(o.ctx, chunk.StartBlockNumber, chunk.EndBlockNumber)
+		if getErr != nil {
+			return nil, getErr
+		}
+		for _, block := range blocks {
+			for _, tx := range block.Transactions {
+				if tx.Type == gethTypes.L1MessageTxType {
+					continue
+				}
+				rlpTx, encodeErr := encoding.ConvertTxDataToRLPEncoding(tx, false)
+				if encodeErr != nil {
+					return nil, encodeErr
+				}
+				stats.txNum++
+				stats.txBytes += uint64(len(rlpTx))
+				stats.calldataGas += calldataGas(rlpTx) + txSignatureGas
+			}
+		}
+	}
+	return &stats, nil
+}
+
+func (o *FeeScalarOracle) getL1TxCost(txHash string) (*l1TxCost, error) {
+	receipt, err := o.l1Client.TransactionReceipt(o.ctx, common.HexToHash(txHash))
+	if err != nil {
+		return nil, err
+	}
+	header, err := o.l1Client.HeaderByNumber(o.ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	cost := &l1TxCost{gasUsed: receipt.GasUsed, blobGas: receipt.BlobGasUsed}
+	if header.BaseFee != nil && header.BaseFee.Sign() > 0 && receipt.EffectiveGasPrice != nil {
+		price, _ := new(big.Float).Quo(new(big.Float).SetInt(receipt.EffectiveGasPrice), new(big.Float).SetInt(header.BaseFee)).Float64()
+		cost.baseFeeGas = float64(receipt.GasUsed) * price
+	} else {
+		cost.baseFeeGas = float64(receipt.GasUsed)
+	}
+	return cost, nil
+}
+
+// deriveFeeScalars sets the scalars so that the L1 fee of the L2 txs of the window covers its L1 costs. Since Curie
+// the commit scalar spreads the execution cost of the commit and finalize transactions evenly over the txs, and
+// the blob scalar spreads the blob gas over their bytes. Before Curie the overhead is the execution gas not
+// accounted by the calldata gas of the txs, and the scalar is the ratio of the cost to the gas, i.e. the tips.
+func deriveFeeScalars(costs *feeScalarCosts) *FeeScalars {
+	var scalars FeeScalars
+	scalars.CommitScalar = clampFeeScalar(costs.baseFeeGas/float64(costs.txNum)*feeScalarPrecision, maxFeeCommitScalar)
+	if costs.txBytes > 0 {
+		scalars.BlobScalar = clampFeeScalar(costs.blobGas/float64(costs.txBytes)*feeScalarPrecision, maxFeeBlobScalar)
+	}
+
+	if costs.gasUsed > float64(costs.calldataGas) {
+		scalars.Overhead = clampFeeScalar((costs.gasUsed-float64(costs.calldataGas))/float64(costs.txNum), maxFeeOverhead)
+	}
+	if gas := float64(costs.calldataGas + scalars.Overhead*costs.txNum); gas > 0 {
+		scalars.Scalar = clampFeeScalar(costs.baseFeeGas/gas*feeScalarPrecision, maxFeeScalar)
+	}
+	return &scalars
+}
+
+func clampFeeScalar(value float64, maxValue uint64) uint64 {
+	if value >= float64(maxValue) {
+		return maxValue
+	}
+	return uint64(math.Ceil(value))
+}
+
+// calldataGas is the gas of the data as calldata, 4 for a zero byte and 16 for the others.
+func calldataGas(data []byte) uint64 {
+	var gas uint64
+	for _, b := range data {
+		if b == 0 {
+			gas += 4
+		} else {
+			gas += 16
+		}
+	}
+	return gas
+}
@@ -0,0 +1,49 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveFeeScalars(t *testing.T) {
+	scalars := deriveFeeScalars(&feeScalarCosts{
+		batchTxStats: batchTxStats{txNum: 100, txBytes: 8192, calldataGas: 160000},
+		gasUsed:      400000,
+		baseFeeGas:   500000,
+		blobGas:      131072,
+	})
+	assert.Equal(t, uint64(5000e9), scalars.CommitScalar)
+	assert.Equal(t, uint64(16e9), scalars.BlobScalar)
+	assert.Equal(t, uint64(2400), scalars.Overhead)
+	// the tips are 25% of the base fee.
+	assert.Equal(t, uint64(1.25e9), scalars.Scalar)
+
+	// the scalars are bounded by the contract, the overhead isn't negative.
+	scalars = deriveFeeScalars(&feeScalarCosts{
+		batchTxStats: batchTxStats{txNum: 1, calldataGas: 1000000},
+		gasUsed:      500000,
+		baseFeeGas:   1e12,
+	})
+	assert.Equal(t, uint64(maxFeeCommitScalar), scalars.CommitScalar)
+	assert.Equal(t, uint64(0), scalars.BlobScalar)
+	assert.Equal(t, uint64(0), scalars.Overhead)
+	assert.Equal(t, uint64(maxFeeScalar), scalars.Scalar)
+}
+
+func TestShouldUpdateFeeScalar(t *testing.T) {
+	r := &Layer1Relayer{gasPriceDiff: defaultGasPriceDiff}
+	assert.False(t, r.shouldUpdateFeeScalar(1e9, 1e9))
+	assert.False(t, r.shouldUpdateFeeScalar(1e9, 1.05e9))
+	assert.True(t, r.shouldUpdateFeeScalar(1e9, 1.06e9))
+	assert.True(t, r.shouldUpdateFeeScalar(1e9, 0.94e9))
+	assert.True(t, r.shouldUpdateFeeScalar(0, 1))
+	// no overflow with the largest scalars.
+	assert.False(t, r.shouldUpdateFeeScalar(maxFeeCommitScalar, maxFeeCommitScalar))
+	assert.True(t, r.shouldUpdateFeeScalar(maxFeeCommitScalar, maxFeeCommitScalar/2))
+}
+
+func TestCalldataGas(t *testing.T) {
+	assert.Equal(t, uint64(0), calldataGas(nil))
+	assert.Equal(t, uint64(40), calldataGas([]byte{0, 1, 0, 2}))
+}
@@ -0,0 +1,195 @@
+package relayer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	// LatestGasPriceStrategy writes the latest observed price.
+	LatestGasPriceStrategy = "latest"
+	// EMAGasPriceStrategy writes the exponential moving average of the observed prices.
+	EMAGasPriceStrategy = "ema"
+	// PercentileGasPriceStrategy writes a percentile of the recent observed prices.
+	PercentileGasPriceStrategy = "percentile"
+	// ExternalGasPriceStrategy writes the price returned by an external price feed.
+	ExternalGasPriceStrategy = "external"
+
+	// the keys of the prices returned by an external price feed.
+	l1BaseFeeFeedKey     = "l1_base_fee"
+	l1BlobBaseFeeFeedKey = "l1_blob_base_fee"
+	l2BaseFeeFeedKey     = "l2_base_fee"
+
+	defaultGasPriceFeedTimeout = 5 * time.Second
+)
+
+// gasPriceStrategy turns the observed gas prices into the price written to the gas oracle contract.
+type gasPriceStrategy interface {
+	// next records the observed price and returns the price to write.
+	next(ctx context.Context, observed uint64) uint64
+}
+
+// gasPriceOracle applies the pricing strategy once per observation key, e.g. the L1 block or the
+// L2 batch the price was observed at, and caps the price.
+type gasPriceOracle struct {
+	strategy    gasPriceStrategy
+	maxGasPrice uint64
+
+	lastKey   string
+	lastPrice uint64
+}
+
+// newGasPriceOracle creates the pricing of one of the oracle prices, feedKey selects the price of an external feed.
+func newGasPriceOracle(cfg *config.GasOracleConfig, feedKey string) (*gasPriceOracle, error) {
+	if cfg == nil {
+		return &gasPriceOracle{strategy: &latestStrategy{}}, nil
+	}
+
+	strategy, err := newGasPriceStrategy(cfg.Strategy, feedKey)
+	if err != nil {
+		return nil, err
+	}
+	return &gasPriceOracle{strategy: strategy, maxGasPrice: cfg.MaxGasPrice}, nil
+}
+
+// price returns the price to write for the price observed at key.
+func (o *gasPriceOracle) price(ctx context.Context, key string, observed uint64) uint64 {
+	if key != o.lastKey || key == "" {
+		o.lastKey = key
+		o.lastPrice = o.strategy.next(ctx, observed)
+	}
+	if o.maxGasPrice > 0 && o.lastPrice > o.maxGasPrice {
+		return o.maxGasPrice
+	}
+	return o.lastPrice
+}
+
+func newGasPriceStrategy(cfg *config.GasPriceStrategyConfig, feedKey string) (gasPriceStrategy, error) {
+	if cfg == nil {
+		return &latestStrategy{}, nil
+	}
+
+	switch cfg.Type {
+	case "", LatestGasPriceStrategy:
+		return &latestStrategy{}, nil
+	case EMAGasPriceStrategy:
+		if cfg.EMAAlpha <= 0 || cfg.EMAAlpha > 1 {
+			return nil, fmt.Errorf("invalid ema alpha: %v, it must be in (0, 1]", cfg.EMAAlpha)
+		}
+		return &emaStrategy{alpha: cfg.EMAAlpha}, nil
+	case PercentileGasPriceStrategy:
+		if cfg.Window <= 0 {
+			return nil, fmt.Errorf("invalid percentile window: %v", cfg.Window)
+		}
+		if cfg.Percentile < 0 || cfg.Percentile > 100 {
+			return nil, fmt.Errorf("invalid percentile: %v, it must be in [0, 100]", cfg.Percentile)
+		}
+		return &percentileStrategy{window: cfg.Window, percentile: cfg.Percentile}, nil
+	case ExternalGasPriceStrategy:
+		if cfg.FeedURL == "" {
+			return nil, fmt.Errorf("external gas price strategy needs a feed url")
+		}
+		timeout := defaultGasPriceFeedTimeout
+		if cfg.FeedTimeoutSec > 0 {
+			timeout = time.Duration(cfg.FeedTimeoutSec) * time.Second
+		}
+		return &externalStrategy{url: cfg.FeedURL, key: feedKey, client: &http.Client{Timeout: timeout}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported gas price strategy: %s", cfg.Type)
+	}
+}
+
+type latestStrategy struct{}
+
+func (*latestStrategy) next(_ context.Context, observed uint64) uint64 {
+	return observed
+}
+
+type emaStrategy struct {
+	alpha   float64
+	average float64
+}
+
+func (s *emaStrategy) next(_ context.Context, observed uint64) uint64 {
+	if s.average == 0 {
+		s.average = float64(observed)
+	} else {
+		s.average = s.alpha*float64(observed) + (1-s.alpha)*s.average
+	}
+	return uint64(math.Ceil(s.average))
+}
+
+type percentileStrategy struct {
+	window     int
+	percentile float64
+	prices     []uint64
+}
+
+func (s *percentileStrategy) next(_ context.Context, observed uint64) uint64 {
+	s.prices = append(s.prices, observed)
+	if len(s.prices) > s.window {
+		s.prices = s.prices[len(s.prices)-s.window:]
+	}
+
+	sorted := make([]uint64, len(s.prices))
+	copy(sorted, s.prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(s.percentile/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+type externalStrategy struct {
+	url    string
+	key    string
+	client *http.Client
+}
+
+func (s *externalStrategy) next(ctx context.Context, observed uint64) uint64 {
+	price, err := s.fetch(ctx)
+	if err != nil {
+		log.Warn("failed to fetch gas price from feed, use the observed price", "url", s.url, "key", s.key, "observed", observed, "err", err)
+		return observed
+	}
+	return price
+}
+
+func (s *externalStrategy) fetch(ctx context.Context) (uint64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Warn("failed to close gas price feed response body", "err", closeErr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	var prices map[string]uint64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return 0, err
+	}
+	price, ok := prices[s.key]
+	if !ok {
+		return 0, fmt.Errorf("price %s not found in the feed response", s.key)
+	}
+	return price, nil
+}
@@ -0,0 +1,57 @@
+package relayer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestGasPriceStrategies(t *testing.T) {
+	ctx := context.Background()
+
+	latest, err := newGasPriceOracle(nil, l1BaseFeeFeedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), latest.price(ctx, "1", 100))
+	assert.Equal(t, uint64(200), latest.price(ctx, "2", 200))
+
+	ema, err := newGasPriceOracle(&config.GasOracleConfig{Strategy: &config.GasPriceStrategyConfig{Type: EMAGasPriceStrategy, EMAAlpha: 0.5}}, l1BaseFeeFeedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), ema.price(ctx, "1", 100))
+	assert.Equal(t, uint64(150), ema.price(ctx, "2", 200))
+	// the same observation is only accounted once.
+	assert.Equal(t, uint64(150), ema.price(ctx, "2", 200))
+
+	percentile, err := newGasPriceOracle(&config.GasOracleConfig{
+		MaxGasPrice: 250,
+		Strategy:    &config.GasPriceStrategyConfig{Type: PercentileGasPriceStrategy, Window: 3, Percentile: 50},
+	}, l1BaseFeeFeedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(300), percentile.strategy.next(ctx, 300))
+	assert.Equal(t, uint64(100), percentile.price(ctx, "1", 100))
+	assert.Equal(t, uint64(200), percentile.price(ctx, "2", 200))
+	// the first observation is out of the window.
+	assert.Equal(t, uint64(200), percentile.price(ctx, "3", 400))
+	// capped by the max gas price.
+	assert.Equal(t, uint64(250), percentile.price(ctx, "4", 500))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"l1_base_fee": 42}`))
+	}))
+	defer srv.Close()
+	external, err := newGasPriceOracle(&config.GasOracleConfig{Strategy: &config.GasPriceStrategyConfig{Type: ExternalGasPriceStrategy, FeedURL: srv.URL}}, l1BaseFeeFeedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), external.price(ctx, "1", 100))
+	missing, err := newGasPriceOracle(&config.GasOracleConfig{Strategy: &config.GasPriceStrategyConfig{Type: ExternalGasPriceStrategy, FeedURL: srv.URL}}, l2BaseFeeFeedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), missing.price(ctx, "1", 100))
+
+	_, err = newGasPriceOracle(&config.GasOracleConfig{Strategy: &config.GasPriceStrategyConfig{Type: "unknown"}}, l1BaseFeeFeedKey)
+	assert.Error(t, err)
+	_, err = newGasPriceOracle(&config.GasOracleConfig{Strategy: &config.GasPriceStrategyConfig{Type: EMAGasPriceStrategy}}, l1BaseFeeFeedKey)
+	assert.Error(t, err)
+}
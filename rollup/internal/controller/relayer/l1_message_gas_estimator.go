@@ -0,0 +1,302 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	defaultL1MessageMaxGasLimit = uint64(10000000)
+	defaultL1MessageGasCacheTTL = 10 * time.Minute
+	// l1MessageGasSearchPrecision is the gas granularity the search of the lowest working gas limit stops at.
+	l1MessageGasSearchPrecision = uint64(1000)
+)
+
+// l1ToL2AliasOffset is added to the address of an L1 contract sending a message, the L1MessageTx of the message is
+// sent on L2 from the aliased address.
+var l1ToL2AliasOffset = new(big.Int).SetBytes(common.FromHex("0x1111000000000000000000000000000000001111"))
+
+// ErrL1MessageRelayFails is returned when the relay of the l1 message fails on L2 even with the max gas limit,
+// replaying it only wastes the fee.
+var ErrL1MessageRelayFails = errors.New("l1 message relay fails on l2 with the max gas limit")
+
+// RelayMessage is the L2ScrollMessenger relayMessage call of an l1 message, as queued on L1.
+type RelayMessage struct {
+	From    common.Address
+	To      common.Address
+	Value   *big.Int
+	Nonce   *big.Int
+	Message []byte
+}
+
+// DecodeRelayMessage decodes the L2ScrollMessenger relayMessage calldata stored with an l1 message.
+func DecodeRelayMessage(calldata []byte) (*RelayMessage, error) {
+	if len(calldata) < 4 {
+		return nil, fmt.Errorf("calldata too short: %d bytes", len(calldata))
+	}
+	method, err := bridgeAbi.L2ScrollMessengerABI.MethodById(calldata[:4])
+	if err != nil {
+		return nil, err
+	}
+	if method.Name != "relayMessage" {
+		return nil, fmt.Errorf("unexpected method %s", method.Name)
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	var msg RelayMessage
+	if err = method.Inputs.Copy(&msg, args); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// l2CallClient is the part of the l2geth rpc client used by the L1MessageGasEstimator, *rpc.Client implements it.
+type l2CallClient interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// l1MessageType is the kind of an l1 message, the messages of a type, e.g. the deposits of a gateway, need similar gas.
+type l1MessageType struct {
+	target   common.Address
+	selector [4]byte
+}
+
+type cachedGasLimit struct {
+	gasLimit  uint64
+	updatedAt time.Time
+}
+
+// callArgs are the eth_estimateGas and debug_traceCall transaction arguments.
+type callArgs struct {
+	From common.Address  `json:"from"`
+	To   common.Address  `json:"to"`
+	Gas  *hexutil.Uint64 `json:"gas,omitempty"`
+	Data hexutil.Bytes   `json:"data"`
+}
+
+// callFrame is a frame of the l2geth callTracer output.
+type callFrame struct {
+	Type  string      `json:"type"`
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Error string      `json:"error"`
+	Calls []callFrame `json:"calls"`
+}
+
+// L1MessageGasEstimator estimates the L2 gas limit an l1 message needs to be relayed, by simulating its
+// L1MessageTx on l2geth. The L2ScrollMessenger doesn't revert when the call of the message fails, so
+// eth_estimateGas only gives the lower bound, the lowest gas limit the target call succeeds with is searched
+// with debug_traceCall. The estimate of every message type is cached and bounds the search of the next
+// messages of the type, which mostly need the same gas.
+type L1MessageGasEstimator struct {
+	cfg    *config.L1MessageGasEstimatorConfig
+	client l2CallClient
+
+	// sender is the aliased L1ScrollMessenger the L1MessageTxs are sent from.
+	sender    common.Address
+	messenger common.Address
+
+	maxGasLimit uint64
+	cacheTTL    time.Duration
+
+	mu    sync.Mutex
+	cache map[l1MessageType]cachedGasLimit
+
+	l1MessageGasEstimateTotal        prometheus.Counter
+	l1MessageGasEstimateFailureTotal prometheus.Counter
+	l1MessageGasSimulationTotal      prometheus.Counter
+	l1MessageGasCacheHitTotal        prometheus.Counter
+}
+
+// NewL1MessageGasEstimator creates a new L1MessageGasEstimator instance, a nil config uses the defaults.
+func NewL1MessageGasEstimator(cfg *config.L1MessageGasEstimatorConfig, client l2CallClient, l1ScrollMessenger, l2ScrollMessenger common.Address, reg prometheus.Registerer) *L1MessageGasEstimator {
+	if cfg == nil {
+		cfg = &config.L1MessageGasEstimatorConfig{}
+	}
+	maxGasLimit := cfg.MaxGasLimit
+	if maxGasLimit == 0 {
+		maxGasLimit = defaultL1MessageMaxGasLimit
+	}
+	cacheTTL := time.Duration(cfg.CacheTTLSec) * time.Second
+	if cacheTTL == 0 {
+		cacheTTL = defaultL1MessageGasCacheTTL
+	}
+
+	return &L1MessageGasEstimator{
+		cfg:         cfg,
+		client:      client,
+		sender:      applyL1ToL2Alias(l1ScrollMessenger),
+		messenger:   l2ScrollMessenger,
+		maxGasLimit: maxGasLimit,
+		cacheTTL:    cacheTTL,
+		cache:       make(map[l1MessageType]cachedGasLimit),
+
+		l1MessageGasEstimateTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_message_gas_estimate_total",
+			Help: "Total number of l1 message gas limit estimates.",
+		}),
+		l1MessageGasEstimateFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_message_gas_estimate_failure_total",
+			Help: "Total number of l1 message gas limit estimates that failed.",
+		}),
+		l1MessageGasSimulationTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_message_gas_simulation_total",
+			Help: "Total number of l1 message relays simulated on l2geth.",
+		}),
+		l1MessageGasCacheHitTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_l1_message_gas_cache_hit_total",
+			Help: "Total number of l1 message gas limit estimates bounded by the cached estimate of the message type.",
+		}),
+	}
+}
+
+func applyL1ToL2Alias(address common.Address) common.Address {
+	aliased := new(big.Int).Add(new(big.Int).SetBytes(address.Bytes()), l1ToL2AliasOffset)
+	// keep the lowest 160 bits, the addition wraps around.
+	return common.BytesToAddress(aliased.Bytes())
+}
+
+// EstimateGasLimit returns the gas limit the l1 message of the relayMessage calldata needs on L2, the buffer included.
+func (e *L1MessageGasEstimator) EstimateGasLimit(ctx context.Context, calldata []byte) (uint64, error) {
+	e.l1MessageGasEstimateTotal.Inc()
+	gasLimit, err := e.estimateGasLimit(ctx, calldata)
+	if err != nil {
+		e.l1MessageGasEstimateFailureTotal.Inc()
+		return 0, err
+	}
+	gasLimit += gasLimit * e.cfg.BufferPercent / 100
+	if gasLimit > e.maxGasLimit {
+		gasLimit = e.maxGasLimit
+	}
+	return gasLimit, nil
+}
+
+func (e *L1MessageGasEstimator) estimateGasLimit(ctx context.Context, calldata []byte) (uint64, error) {
+	msg, err := DecodeRelayMessage(calldata)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode relayMessage calldata: %w", err)
+	}
+	msgType := l1MessageType{target: msg.To}
+	copy(msgType.selector[:], msg.Message)
+
+	// the relayMessage call itself needs the gas estimated by l2geth, whether the target call succeeds or not.
+	var estimated hexutil.Uint64
+	if err = e.client.CallContext(ctx, &estimated, "eth_estimateGas", callArgs{From: e.sender, To: e.messenger, Data: calldata}, "latest"); err != nil {
+		return 0, fmt.Errorf("failed to estimate relayMessage gas: %w", err)
+	}
+
+	// the lowest gas limit the target call succeeds with is in [low, high].
+	low, high := uint64(estimated), e.maxGasLimit
+	if low >= high {
+		return 0, fmt.Errorf("relayMessage needs %d gas, above the max gas limit %d", low, high)
+	}
+	ok, err := e.simulate(ctx, calldata, msg.To, low)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		e.updateCache(msgType, low)
+		return low, nil
+	}
+
+	if cached, found := e.getCache(msgType); found && cached > low && cached < high {
+		e.l1MessageGasCacheHitTotal.Inc()
+		if ok, err = e.simulate(ctx, calldata, msg.To, cached); err != nil {
+			return 0, err
+		}
+		if ok {
+			high = cached
+		} else {
+			low = cached
+		}
+	}
+	if high == e.maxGasLimit {
+		if ok, err = e.simulate(ctx, calldata, msg.To, high); err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, ErrL1MessageRelayFails
+		}
+	}
+
+	for high-low > l1MessageGasSearchPrecision {
+		mid := low + (high-low)/2
+		if ok, err = e.simulate(ctx, calldata, msg.To, mid); err != nil {
+			return 0, err
+		}
+		if ok {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	e.updateCache(msgType, high)
+	return high, nil
+}
+
+// simulate traces the L1MessageTx of the message with the gas limit, and tells whether the target call succeeds.
+func (e *L1MessageGasEstimator) simulate(ctx context.Context, calldata []byte, target common.Address, gasLimit uint64) (bool, error) {
+	e.l1MessageGasSimulationTotal.Inc()
+	gas := hexutil.Uint64(gasLimit)
+	var frame callFrame
+	if err := e.client.CallContext(ctx, &frame, "debug_traceCall", callArgs{From: e.sender, To: e.messenger, Gas: &gas, Data: calldata},
+		"latest", map[string]interface{}{"tracer": "callTracer"}); err != nil {
+		return false, fmt.Errorf("failed to trace relayMessage with gas limit %d: %w", gasLimit, err)
+	}
+	if frame.Error != "" {
+		return false, nil
+	}
+	targetFrame := findCallFrame(&frame, e.messenger, target)
+	if targetFrame == nil {
+		log.Debug("target call of the l1 message not found in the trace", "target", target.Hex(), "gas limit", gasLimit)
+		return false, nil
+	}
+	return targetFrame.Error == "", nil
+}
+
+// findCallFrame returns the first call from the messenger to the target, the messenger is a proxy so the call is
+// made from the proxy address in the delegated frame of the implementation.
+func findCallFrame(frame *callFrame, from, to common.Address) *callFrame {
+	for i := range frame.Calls {
+		call := &frame.Calls[i]
+		if call.Type == "CALL" && common.HexToAddress(call.From) == from && common.HexToAddress(call.To) == to {
+			return call
+		}
+		if found := findCallFrame(call, from, to); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (e *L1MessageGasEstimator) getCache(msgType l1MessageType) (uint64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cached, ok := e.cache[msgType]
+	if !ok || time.Since(cached.updatedAt) > e.cacheTTL {
+		return 0, false
+	}
+	return cached.gasLimit, true
+}
+
+func (e *L1MessageGasEstimator) updateCache(msgType l1MessageType, gasLimit uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cache[msgType] = cachedGasLimit{gasLimit: gasLimit, updatedAt: time.Now()}
+}
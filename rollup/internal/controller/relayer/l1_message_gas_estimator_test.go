@@ -0,0 +1,98 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
+)
+
+// mockL2CallClient simulates an L2ScrollMessenger proxy whose target call succeeds with at least needed gas.
+type mockL2CallClient struct {
+	estimated uint64
+	needed    uint64
+	traces    int
+}
+
+func (m *mockL2CallClient) CallContext(_ context.Context, result interface{}, method string, args ...interface{}) error {
+	call := args[0].(callArgs)
+	switch method {
+	case "eth_estimateGas":
+		*result.(*hexutil.Uint64) = hexutil.Uint64(m.estimated)
+	case "debug_traceCall":
+		m.traces++
+		msg, err := DecodeRelayMessage(call.Data)
+		if err != nil {
+			return err
+		}
+		target := callFrame{Type: "CALL", From: call.To.Hex(), To: msg.To.Hex()}
+		if uint64(*call.Gas) < m.needed {
+			target.Error = "out of gas"
+		}
+		*result.(*callFrame) = callFrame{Type: "CALL", From: call.From.Hex(), To: call.To.Hex(), Calls: []callFrame{
+			{Type: "DELEGATECALL", From: call.To.Hex(), To: common.HexToAddress("0x1234").Hex(), Calls: []callFrame{target}},
+		}}
+	default:
+		return errors.New("unexpected method " + method)
+	}
+	return nil
+}
+
+func TestApplyL1ToL2Alias(t *testing.T) {
+	assert.Equal(t, common.HexToAddress("0x1111000000000000000000000000000000001112"), applyL1ToL2Alias(common.HexToAddress("0x1")))
+	// the addition wraps around.
+	assert.Equal(t, common.HexToAddress("0x1111000000000000000000000000000000001110"), applyL1ToL2Alias(common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")))
+}
+
+func TestL1MessageGasEstimator(t *testing.T) {
+	gateway := common.HexToAddress("0x5678")
+	relayCalldata := func(nonce int64) []byte {
+		data, err := bridgeAbi.L2ScrollMessengerABI.Pack("relayMessage", common.HexToAddress("0x9abc"), gateway, big.NewInt(0),
+			big.NewInt(nonce), common.FromHex("0x8431f5c1"))
+		assert.NoError(t, err)
+		return data
+	}
+
+	client := &mockL2CallClient{estimated: 80000, needed: 80000}
+	estimator := NewL1MessageGasEstimator(&config.L1MessageGasEstimatorConfig{BufferPercent: 10}, client,
+		common.HexToAddress("0x1"), common.HexToAddress("0x2"), prometheus.NewRegistry())
+
+	// the target call succeeds with the gas estimated by l2geth.
+	gasLimit, err := estimator.EstimateGasLimit(context.Background(), relayCalldata(0))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(88000), gasLimit)
+	assert.Equal(t, 1, client.traces)
+
+	// the target call needs more gas than the relayMessage call, the lowest working gas limit is searched.
+	estimator.cache = make(map[l1MessageType]cachedGasLimit)
+	client.needed, client.traces = 150000, 0
+	gasLimit, err = estimator.EstimateGasLimit(context.Background(), relayCalldata(1))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, gasLimit, uint64(165000))
+	assert.LessOrEqual(t, gasLimit, uint64(151000*110/100))
+	searchTraces := client.traces
+
+	// the estimate of the message type bounds the search of the next message of the type.
+	client.needed, client.traces = 149000, 0
+	gasLimit, err = estimator.EstimateGasLimit(context.Background(), relayCalldata(2))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, gasLimit, uint64(149000*110/100))
+	assert.LessOrEqual(t, gasLimit, uint64(151000*110/100))
+	assert.Less(t, client.traces, searchTraces)
+
+	// the message fails whatever the gas limit.
+	client.needed = defaultL1MessageMaxGasLimit + 1
+	_, err = estimator.EstimateGasLimit(context.Background(), relayCalldata(3))
+	assert.ErrorIs(t, err, ErrL1MessageRelayFails)
+
+	_, err = estimator.EstimateGasLimit(context.Background(), []byte{0x1})
+	assert.Error(t, err)
+}
@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/go-ethereum/accounts/abi"
-	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"gorm.io/gorm"
@@ -42,6 +43,14 @@ type Layer1Relayer struct {
 	gasPriceDiff        uint64
 	l1BaseFeeWeight     float64
 	l1BlobBaseFeeWeight float64
+	baseFeeOracle       *gasPriceOracle
+	blobBaseFeeOracle   *gasPriceOracle
+
+	// feeScalarOracle derives the fee scalars, nil leaves them to the owner of the contract. lastFeeScalars
+	// holds the last scalars sent by name, a failed update is removed to be sent again.
+	feeScalarOracle *FeeScalarOracle
+	feeScalarsMu    sync.Mutex
+	lastFeeScalars  map[string]uint64
 
 	l1BlockOrm *orm.L1Block
 	l2BlockOrm *orm.L2Block
@@ -56,10 +65,13 @@ func NewLayer1Relayer(ctx context.Context, db *gorm.DB, cfg *config.RelayerConfi
 
 	switch serviceType {
 	case ServiceTypeL1GasOracle:
-		gasOracleSender, err = sender.NewSender(ctx, cfg.SenderConfig, cfg.GasOracleSenderPrivateKey, "l1_relayer", "gas_oracle_sender", types.SenderTypeL1GasOracle, db, reg)
+		signer, signerErr := sender.NewAccountSigner(ctx, cfg.GasOracleSenderPrivateKey, cfg.GasOracleSenderSigner)
+		if signerErr != nil {
+			return nil, fmt.Errorf("new gas oracle sender signer failed, err: %v", signerErr)
+		}
+		gasOracleSender, err = sender.NewSenderWithSigner(ctx, cfg.SenderConfig, signer, "l1_relayer", "gas_oracle_sender", types.SenderTypeL1GasOracle, db, reg)
 		if err != nil {
-			addr := crypto.PubkeyToAddress(cfg.GasOracleSenderPrivateKey.PublicKey)
-			return nil, fmt.Errorf("new gas oracle sender failed for address %s, err: %v", addr.Hex(), err)
+			return nil, fmt.Errorf("new gas oracle sender failed for address %s, err: %v", signer.Address().Hex(), err)
 		}
 
 		// Ensure test features aren't enabled on the scroll mainnet.
@@ -80,6 +92,15 @@ func NewLayer1Relayer(ctx context.Context, db *gorm.DB, cfg *config.RelayerConfi
 		gasPriceDiff = defaultGasPriceDiff
 	}
 
+	baseFeeOracle, err := newGasPriceOracle(cfg.GasOracleConfig, l1BaseFeeFeedKey)
+	if err != nil {
+		return nil, fmt.Errorf("new l1 base fee oracle failed, err: %w", err)
+	}
+	blobBaseFeeOracle, err := newGasPriceOracle(cfg.GasOracleConfig, l1BlobBaseFeeFeedKey)
+	if err != nil {
+		return nil, fmt.Errorf("new l1 blob base fee oracle failed, err: %w", err)
+	}
+
 	l1Relayer := &Layer1Relayer{
 		cfg:        cfg,
 		chainCfg:   chainCfg,
@@ -94,6 +115,8 @@ func NewLayer1Relayer(ctx context.Context, db *gorm.DB, cfg *config.RelayerConfi
 		gasPriceDiff:        gasPriceDiff,
 		l1BaseFeeWeight:     cfg.GasOracleConfig.L1BaseFeeWeight,
 		l1BlobBaseFeeWeight: cfg.GasOracleConfig.L1BlobBaseFeeWeight,
+		baseFeeOracle:       baseFeeOracle,
+		blobBaseFeeOracle:   blobBaseFeeOracle,
 	}
 
 	l1Relayer.metrics = initL1RelayerMetrics(reg)
@@ -150,6 +173,10 @@ func (r *Layer1Relayer) ProcessGasPriceOracle() {
 		} else {
 			baseFee = block.BaseFee
 		}
+		baseFee = r.baseFeeOracle.price(r.ctx, block.Hash, baseFee)
+		if isCurie {
+			blobBaseFee = r.blobBaseFeeOracle.price(r.ctx, block.Hash, blobBaseFee)
+		}
 
 		if r.shouldUpdateGasOracle(baseFee, blobBaseFee, isCurie) {
 			var data []byte
@@ -188,9 +215,106 @@ func (r *Layer1Relayer) ProcessGasPriceOracle() {
 	}
 }
 
+// WithFeeScalarOracle writes the fee scalars derived by the oracle to the L1GasPriceOracle in ProcessFeeScalarOracle.
+func (r *Layer1Relayer) WithFeeScalarOracle(oracle *FeeScalarOracle) *Layer1Relayer {
+	r.feeScalarOracle = oracle
+	r.lastFeeScalars = make(map[string]uint64)
+	return r
+}
+
+// ProcessFeeScalarOracle writes the fee scalars derived from the L1 costs of the latest batches to layer2, the commit
+// and blob scalars since Curie and the overhead and scalar before. A scalar is only written when it moved by the gas
+// price diff since it was last written.
+func (r *Layer1Relayer) ProcessFeeScalarOracle() {
+	if r.feeScalarOracle == nil {
+		return
+	}
+	r.metrics.rollupL1RelayerFeeScalarOracleRunTotal.Inc()
+
+	scalars, err := r.feeScalarOracle.FeeScalars()
+	if err != nil {
+		log.Warn("Failed to derive the fee scalars", "err", err)
+		return
+	}
+	if scalars == nil {
+		return
+	}
+
+	latestL2Height, err := r.l2BlockOrm.GetL2BlocksLatestHeight(r.ctx)
+	if err != nil {
+		log.Warn("Failed to fetch latest L2 block height from db", "err", err)
+		return
+	}
+	if r.chainCfg.IsCurie(new(big.Int).SetUint64(latestL2Height)) {
+		r.updateFeeScalar("commit_scalar", "setCommitScalar", scalars.CommitScalar, scalars.BatchIndex)
+		r.updateFeeScalar("blob_scalar", "setBlobScalar", scalars.BlobScalar, scalars.BatchIndex)
+	} else {
+		r.updateFeeScalar("overhead", "setOverhead", scalars.Overhead, scalars.BatchIndex)
+		r.updateFeeScalar("scalar", "setScalar", scalars.Scalar, scalars.BatchIndex)
+	}
+}
+
+func (r *Layer1Relayer) updateFeeScalar(name string, method string, value uint64, batchIndex uint64) {
+	r.feeScalarsMu.Lock()
+	last, ok := r.lastFeeScalars[name]
+	r.feeScalarsMu.Unlock()
+	if ok && !r.shouldUpdateFeeScalar(last, value) {
+		return
+	}
+
+	data, err := r.l1GasOracleABI.Pack(method, new(big.Int).SetUint64(value))
+	if err != nil {
+		log.Error("Failed to pack fee scalar update", "method", method, "value", value, "err", err)
+		return
+	}
+
+	contextID := fmt.Sprintf("%s%s-%d", feeScalarContextIDPrefix, name, batchIndex)
+	hash, err := r.gasOracleSender.SendTransaction(contextID, &r.cfg.GasPriceOracleContractAddress, data, nil, 0)
+	if err != nil {
+		log.Error("Failed to send fee scalar update tx to layer2", "method", method, "value", value, "batchIndex", batchIndex, "err", err)
+		return
+	}
+
+	r.feeScalarsMu.Lock()
+	r.lastFeeScalars[name] = value
+	r.feeScalarsMu.Unlock()
+	r.metrics.rollupL1RelayerLatestFeeScalar.WithLabelValues(name).Set(float64(value))
+	log.Info("Update l1 fee scalar", "txHash", hash.String(), "name", name, "value", value, "last", last, "batchIndex", batchIndex)
+}
+
+func (r *Layer1Relayer) shouldUpdateFeeScalar(last uint64, value uint64) bool {
+	// The scalars are up to 1e18, the delta is computed in float64 not to overflow.
+	expectedDelta := uint64(float64(last)*float64(r.gasPriceDiff)/gasPriceDiffPrecision) + 1
+	return value >= last+expectedDelta || value+expectedDelta <= last
+}
+
+func (r *Layer1Relayer) handleFeeScalarConfirmation(cfm *sender.Confirmation) {
+	if cfm.IsSuccessful {
+		r.metrics.rollupL1UpdateFeeScalarConfirmedTotal.Inc()
+		log.Info("Fee scalar update transaction confirmed in layer2", "confirmation", cfm)
+		return
+	}
+
+	r.metrics.rollupL1UpdateFeeScalarConfirmedFailedTotal.Inc()
+	log.Warn("Fee scalar update transaction confirmed but failed in layer2", "confirmation", cfm)
+	// the context id is the prefix, the name and the batch index, the name is sent again by the next run.
+	name := strings.TrimPrefix(cfm.ContextID, feeScalarContextIDPrefix)
+	if i := strings.LastIndex(name, "-"); i >= 0 {
+		name = name[:i]
+	}
+	r.feeScalarsMu.Lock()
+	delete(r.lastFeeScalars, name)
+	r.feeScalarsMu.Unlock()
+}
+
 func (r *Layer1Relayer) handleConfirmation(cfm *sender.Confirmation) {
 	switch cfm.SenderType {
 	case types.SenderTypeL1GasOracle:
+		if strings.HasPrefix(cfm.ContextID, feeScalarContextIDPrefix) {
+			r.handleFeeScalarConfirmation(cfm)
+			break
+		}
+
 		var status types.GasOracleStatus
 		if cfm.IsSuccessful {
 			status = types.GasOracleImported
@@ -224,6 +348,14 @@ func (r *Layer1Relayer) handleL1GasOracleConfirmLoop(ctx context.Context) {
 	}
 }
 
+// CheckSenders checks the endpoint and the signer of the gas oracle sender, for its readiness probe.
+func (r *Layer1Relayer) CheckSenders(ctx context.Context) error {
+	if r.gasOracleSender != nil {
+		return r.gasOracleSender.Check(ctx)
+	}
+	return nil
+}
+
 // StopSenders stops the senders of the rollup-relayer to prevent querying the removed pending_transaction table in unit tests.
 // for unit test
 func (r *Layer1Relayer) StopSenders() {
@@ -13,6 +13,11 @@ type l1RelayerMetrics struct {
 	rollupL1RelayerLatestBlobBaseFee            prometheus.Gauge
 	rollupL1UpdateGasOracleConfirmedTotal       prometheus.Counter
 	rollupL1UpdateGasOracleConfirmedFailedTotal prometheus.Counter
+
+	rollupL1RelayerFeeScalarOracleRunTotal      prometheus.Counter
+	rollupL1RelayerLatestFeeScalar              *prometheus.GaugeVec
+	rollupL1UpdateFeeScalarConfirmedTotal       prometheus.Counter
+	rollupL1UpdateFeeScalarConfirmedFailedTotal prometheus.Counter
 }
 
 var (
@@ -43,6 +48,22 @@ func initL1RelayerMetrics(reg prometheus.Registerer) *l1RelayerMetrics {
 				Name: "rollup_layer1_update_gas_oracle_confirmed_failed_total",
 				Help: "The total number of updating layer1 gas oracle confirmed failed",
 			}),
+			rollupL1RelayerFeeScalarOracleRunTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer1_fee_scalar_oracle_run_total",
+				Help: "The total number of layer1 fee scalar oracle run total",
+			}),
+			rollupL1RelayerLatestFeeScalar: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+				Name: "rollup_layer1_latest_fee_scalar",
+				Help: "The latest fee scalars sent to the l1 gas price oracle",
+			}, []string{"name"}),
+			rollupL1UpdateFeeScalarConfirmedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer1_update_fee_scalar_confirmed_total",
+				Help: "The total number of updating layer1 fee scalars confirmed",
+			}),
+			rollupL1UpdateFeeScalarConfirmedFailedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer1_update_fee_scalar_confirmed_failed_total",
+				Help: "The total number of updating layer1 fee scalars confirmed failed",
+			}),
 		}
 	})
 	return l1RelayerMetric
@@ -2,9 +2,11 @@ package relayer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -16,14 +18,15 @@ import (
 	"github.com/scroll-tech/go-ethereum/accounts/abi"
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
-	"github.com/scroll-tech/go-ethereum/crypto"
 	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
 	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
+	cencoding "scroll-tech/common/types/encoding"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
 
@@ -45,26 +48,31 @@ type Layer2Relayer struct {
 
 	l2Client *ethclient.Client
 
-	db         *gorm.DB
-	batchOrm   *orm.Batch
-	chunkOrm   *orm.Chunk
-	l2BlockOrm *orm.L2Block
+	db               *gorm.DB
+	batchOrm         *orm.Batch
+	pauseStateOrm    *orm.PauseState
+	chunkOrm         *orm.Chunk
+	l2BlockOrm       *orm.L2Block
+	batchApprovalOrm *orm.BatchApproval
 
 	cfg *config.RelayerConfig
 
-	commitSender   *sender.Sender
-	finalizeSender *sender.Sender
+	commitSender   *sender.Pool
+	finalizeSender *sender.Pool
 	l1RollupABI    *abi.ABI
 
 	gasOracleSender *sender.Sender
 	l2GasOracleABI  *abi.ABI
 
-	lastGasPrice uint64
-	minGasPrice  uint64
-	gasPriceDiff uint64
+	lastGasPrice   uint64
+	minGasPrice    uint64
+	gasPriceDiff   uint64
+	gasPriceOracle *gasPriceOracle
 
 	// Used to get batch status from chain_monitor api.
 	chainMonitorClient *resty.Client
+	// Used to alert the operator of the quarantined batches.
+	alertClient *resty.Client
 
 	metrics *l2RelayerMetrics
 
@@ -72,16 +80,20 @@ type Layer2Relayer struct {
 }
 
 // NewLayer2Relayer will return a new instance of Layer2RelayerClient
-func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.DB, cfg *config.RelayerConfig, chainCfg *params.ChainConfig, initGenesis bool, serviceType ServiceType, reg prometheus.Registerer) (*Layer2Relayer, error) {
-	var gasOracleSender, commitSender, finalizeSender *sender.Sender
+func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.DB, proofStore objectstore.Store, cfg *config.RelayerConfig, chainCfg *params.ChainConfig, initGenesis bool, serviceType ServiceType, reg prometheus.Registerer) (*Layer2Relayer, error) {
+	var gasOracleSender *sender.Sender
+	var commitSender, finalizeSender *sender.Pool
 	var err error
 
 	switch serviceType {
 	case ServiceTypeL2GasOracle:
-		gasOracleSender, err = sender.NewSender(ctx, cfg.SenderConfig, cfg.GasOracleSenderPrivateKey, "l2_relayer", "gas_oracle_sender", types.SenderTypeL2GasOracle, db, reg)
+		signer, signerErr := sender.NewAccountSigner(ctx, cfg.GasOracleSenderPrivateKey, cfg.GasOracleSenderSigner)
+		if signerErr != nil {
+			return nil, fmt.Errorf("new gas oracle sender signer failed, err: %w", signerErr)
+		}
+		gasOracleSender, err = sender.NewSenderWithSigner(ctx, cfg.SenderConfig, signer, "l2_relayer", "gas_oracle_sender", types.SenderTypeL2GasOracle, db, reg)
 		if err != nil {
-			addr := crypto.PubkeyToAddress(cfg.GasOracleSenderPrivateKey.PublicKey)
-			return nil, fmt.Errorf("new gas oracle sender failed for address %s, err: %w", addr.Hex(), err)
+			return nil, fmt.Errorf("new gas oracle sender failed for address %s, err: %w", signer.Address().Hex(), err)
 		}
 
 		// Ensure test features aren't enabled on the ethereum mainnet.
@@ -90,16 +102,22 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		}
 
 	case ServiceTypeL2RollupRelayer:
-		commitSender, err = sender.NewSender(ctx, cfg.SenderConfig, cfg.CommitSenderPrivateKey, "l2_relayer", "commit_sender", types.SenderTypeCommitBatch, db, reg)
+		commitSigners, signerErr := sender.NewSigners(ctx, cfg.CommitSenderPrivateKey, cfg.CommitSenderSigner, cfg.CommitSenderPrivateKeys, cfg.CommitSenderSigners)
+		if signerErr != nil {
+			return nil, fmt.Errorf("new commit sender signers failed, err: %w", signerErr)
+		}
+		commitSender, err = sender.NewPoolWithSigners(ctx, cfg.SenderConfig, commitSigners, "l2_relayer", "commit_sender", types.SenderTypeCommitBatch, db, reg)
 		if err != nil {
-			addr := crypto.PubkeyToAddress(cfg.CommitSenderPrivateKey.PublicKey)
-			return nil, fmt.Errorf("new commit sender failed for address %s, err: %w", addr.Hex(), err)
+			return nil, fmt.Errorf("new commit sender failed for address %s, err: %w", commitSigners[0].Address().Hex(), err)
 		}
 
-		finalizeSender, err = sender.NewSender(ctx, cfg.SenderConfig, cfg.FinalizeSenderPrivateKey, "l2_relayer", "finalize_sender", types.SenderTypeFinalizeBatch, db, reg)
+		finalizeSigners, signerErr := sender.NewSigners(ctx, cfg.FinalizeSenderPrivateKey, cfg.FinalizeSenderSigner, cfg.FinalizeSenderPrivateKeys, cfg.FinalizeSenderSigners)
+		if signerErr != nil {
+			return nil, fmt.Errorf("new finalize sender signers failed, err: %w", signerErr)
+		}
+		finalizeSender, err = sender.NewPoolWithSigners(ctx, cfg.SenderConfig, finalizeSigners, "l2_relayer", "finalize_sender", types.SenderTypeFinalizeBatch, db, reg)
 		if err != nil {
-			addr := crypto.PubkeyToAddress(cfg.FinalizeSenderPrivateKey.PublicKey)
-			return nil, fmt.Errorf("new finalize sender failed for address %s, err: %w", addr.Hex(), err)
+			return nil, fmt.Errorf("new finalize sender failed for address %s, err: %w", finalizeSigners[0].Address().Hex(), err)
 		}
 
 		// Ensure test features aren't enabled on the ethereum mainnet.
@@ -111,6 +129,12 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		return nil, fmt.Errorf("invalid service type for l2_relayer: %v", serviceType)
 	}
 
+	if cfg.FinalizeApproval != nil {
+		if err = cfg.FinalizeApproval.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	var minGasPrice uint64
 	var gasPriceDiff uint64
 	if cfg.GasOracleConfig != nil {
@@ -121,13 +145,20 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		gasPriceDiff = defaultGasPriceDiff
 	}
 
+	gasPriceOracle, err := newGasPriceOracle(cfg.GasOracleConfig, l2BaseFeeFeedKey)
+	if err != nil {
+		return nil, fmt.Errorf("new l2 base fee oracle failed, err: %w", err)
+	}
+
 	layer2Relayer := &Layer2Relayer{
 		ctx: ctx,
 		db:  db,
 
-		batchOrm:   orm.NewBatch(db),
-		l2BlockOrm: orm.NewL2Block(db),
-		chunkOrm:   orm.NewChunk(db),
+		batchOrm:         orm.NewBatch(db).WithProofStore(proofStore),
+		pauseStateOrm:    orm.NewPauseState(db),
+		l2BlockOrm:       orm.NewL2Block(db),
+		chunkOrm:         orm.NewChunk(db),
+		batchApprovalOrm: orm.NewBatchApproval(db),
 
 		l2Client: l2Client,
 
@@ -138,8 +169,9 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		gasOracleSender: gasOracleSender,
 		l2GasOracleABI:  bridgeAbi.L2GasPriceOracleABI,
 
-		minGasPrice:  minGasPrice,
-		gasPriceDiff: gasPriceDiff,
+		minGasPrice:    minGasPrice,
+		gasPriceDiff:   gasPriceDiff,
+		gasPriceOracle: gasPriceOracle,
 
 		cfg:      cfg,
 		chainCfg: chainCfg,
@@ -152,6 +184,11 @@ func NewLayer2Relayer(ctx context.Context, l2Client *ethclient.Client, db *gorm.
 		layer2Relayer.chainMonitorClient.SetTimeout(time.Duration(cfg.ChainMonitor.TimeOut) * time.Second)
 	}
 
+	if cfg.FinalizeRetry != nil && cfg.FinalizeRetry.AlertWebhookURL != "" {
+		layer2Relayer.alertClient = resty.New()
+		layer2Relayer.alertClient.SetTimeout(10 * time.Second)
+	}
+
 	// Initialize genesis before we do anything else
 	if initGenesis {
 		if err := layer2Relayer.initializeGenesis(); err != nil {
@@ -304,7 +341,8 @@ func (r *Layer2Relayer) ProcessGasPriceOracle() {
 			log.Error("Failed to fetch SuggestGasPrice from l2geth", "err", err)
 			return
 		}
-		suggestGasPriceUint64 := uint64(suggestGasPrice.Int64())
+		suggestGasPriceUint64 := r.gasPriceOracle.price(r.ctx, batch.Hash, uint64(suggestGasPrice.Int64()))
+		suggestGasPrice = new(big.Int).SetUint64(suggestGasPriceUint64)
 		expectedDelta := r.lastGasPrice * r.gasPriceDiff / gasPriceDiffPrecision
 		if r.lastGasPrice > 0 && expectedDelta == 0 {
 			expectedDelta = 1
@@ -336,111 +374,207 @@ func (r *Layer2Relayer) ProcessGasPriceOracle() {
 	}
 }
 
+// isPaused reports whether the component was paused by an operator, it fails closed if the state can't be read.
+func (r *Layer2Relayer) isPaused(component string) bool {
+	paused, err := r.pauseStateOrm.IsPaused(r.ctx, component)
+	if err != nil {
+		log.Error("failed to get pause state, skip this round", "component", component, "err", err)
+		return true
+	}
+	if paused {
+		log.Debug("component paused by operator", "component", component)
+	}
+	return paused
+}
+
 // ProcessPendingBatches processes the pending batches by sending commitBatch transactions to layer 1.
 func (r *Layer2Relayer) ProcessPendingBatches() {
+	if r.isPaused(orm.PauseComponentCommit) {
+		return
+	}
+
 	// get pending batches from database in ascending order by their index.
 	dbBatches, err := r.batchOrm.GetFailedAndPendingBatches(r.ctx, 5)
 	if err != nil {
 		log.Error("Failed to fetch pending L2 batches", "err", err)
 		return
 	}
+	if len(dbBatches) > 0 && r.holdCommit(dbBatches[0]) {
+		return
+	}
+
+	// still commit the batches before the first one failing to construct its payload.
+	payloads := make([]*commitBatchPayload, 0, len(dbBatches))
 	for _, dbBatch := range dbBatches {
 		r.metrics.rollupL2RelayerProcessPendingBatchTotal.Inc()
 
-		dbChunks, err := r.chunkOrm.GetChunksInRange(r.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+		payload, err := r.constructCommitBatchPayload(dbBatch)
 		if err != nil {
-			log.Error("failed to get chunks in range", "err", err)
-			return
-		}
-
-		chunks := make([]*encoding.Chunk, len(dbChunks))
-		for i, c := range dbChunks {
-			blocks, getErr := r.l2BlockOrm.GetL2BlocksInRange(r.ctx, c.StartBlockNumber, c.EndBlockNumber)
-			if getErr != nil {
-				log.Error("failed to get blocks in range", "err", getErr)
-				return
-			}
-			chunks[i] = &encoding.Chunk{Blocks: blocks}
+			log.Error("failed to construct commitBatch payload", "index", dbBatch.Index, "err", err)
+			break
 		}
+		payloads = append(payloads, payload)
+	}
 
-		if dbBatch.Index == 0 {
-			log.Error("invalid args: batch index is 0, should only happen in committing genesis batch")
+	for _, payload := range payloads {
+		if err := r.commitBatch(payload); err != nil {
 			return
 		}
+	}
+}
+
+// commitBatchPayload holds the commitBatch arguments of a batch.
+type commitBatchPayload struct {
+	dbBatch                *orm.Batch
+	version                uint8
+	parentBatchHeader      []byte
+	chunks                 [][]byte
+	skippedL1MessageBitmap []byte
+	blob                   *kzg4844.Blob
+}
 
-		dbParentBatch, getErr := r.batchOrm.GetBatchByIndex(r.ctx, dbBatch.Index-1)
+func (r *Layer2Relayer) constructCommitBatchPayload(dbBatch *orm.Batch) (*commitBatchPayload, error) {
+	dbParentBatch, dbChunks, chunks, err := loadBatchChunks(r.ctx, r.batchOrm, r.chunkOrm, r.l2BlockOrm, dbBatch)
+	if err != nil {
+		return nil, err
+	}
+	return newCommitBatchPayload(r.chainCfg, dbBatch, dbParentBatch, dbChunks, chunks)
+}
+
+// loadBatchChunks loads the parent batch, the chunks and the blocks of the batch.
+func loadBatchChunks(ctx context.Context, batchOrm *orm.Batch, chunkOrm *orm.Chunk, l2BlockOrm *orm.L2Block, dbBatch *orm.Batch) (*orm.Batch, []*orm.Chunk, []*encoding.Chunk, error) {
+	dbChunks, err := chunkOrm.GetChunksInRange(ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get chunks in range: %w", err)
+	}
+
+	chunks := make([]*encoding.Chunk, len(dbChunks))
+	for i, c := range dbChunks {
+		blocks, getErr := l2BlockOrm.GetL2BlocksInRange(ctx, c.StartBlockNumber, c.EndBlockNumber)
 		if getErr != nil {
-			log.Error("failed to get parent batch header", "err", getErr)
-			return
+			return nil, nil, nil, fmt.Errorf("failed to get blocks in range: %w", getErr)
 		}
+		chunks[i] = &encoding.Chunk{Blocks: blocks}
+	}
 
-		var calldata []byte
-		var blob *kzg4844.Blob
-		if !r.chainCfg.IsBernoulli(new(big.Int).SetUint64(dbChunks[0].StartBlockNumber)) { // codecv0
-			calldata, err = r.constructCommitBatchPayloadCodecV0(dbBatch, dbParentBatch, dbChunks, chunks)
-			if err != nil {
-				log.Error("failed to construct commitBatch payload codecv0", "index", dbBatch.Index, "err", err)
-				return
-			}
-		} else if !r.chainCfg.IsCurie(new(big.Int).SetUint64(dbChunks[0].StartBlockNumber)) { // codecv1
-			calldata, blob, err = r.constructCommitBatchPayloadCodecV1(dbBatch, dbParentBatch, dbChunks, chunks)
-			if err != nil {
-				log.Error("failed to construct commitBatch payload codecv1", "index", dbBatch.Index, "err", err)
-				return
-			}
-		} else { // codecv2
-			calldata, blob, err = r.constructCommitBatchPayloadCodecV2(dbBatch, dbParentBatch, dbChunks, chunks)
-			if err != nil {
-				log.Error("failed to construct commitBatch payload codecv2", "index", dbBatch.Index, "err", err)
-				return
-			}
-		}
+	if dbBatch.Index == 0 {
+		return nil, nil, nil, fmt.Errorf("invalid args: batch index is 0, should only happen in committing genesis batch")
+	}
 
-		// fallbackGasLimit is non-zero only in sending non-blob transactions.
-		fallbackGasLimit := uint64(float64(dbBatch.TotalL1CommitGas) * r.cfg.L1CommitGasLimitMultiplier)
-		if types.RollupStatus(dbBatch.RollupStatus) == types.RollupCommitFailed {
-			// use eth_estimateGas if this batch has been committed and failed at least once.
-			fallbackGasLimit = 0
-			log.Warn("Batch commit previously failed, using eth_estimateGas for the re-submission", "hash", dbBatch.Hash)
-		}
+	dbParentBatch, err := batchOrm.GetBatchByIndex(ctx, dbBatch.Index-1)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get parent batch header: %w", err)
+	}
+	return dbParentBatch, dbChunks, chunks, nil
+}
 
-		txHash, err := r.commitSender.SendTransaction(dbBatch.Hash, &r.cfg.RollupContractAddress, calldata, blob, fallbackGasLimit)
-		if err != nil {
-			log.Error(
-				"Failed to send commitBatch tx to layer1",
-				"index", dbBatch.Index,
-				"hash", dbBatch.Hash,
-				"RollupContractAddress", r.cfg.RollupContractAddress,
-				"err", err,
-			)
-			log.Debug(
-				"Failed to send commitBatch tx to layer1",
-				"index", dbBatch.Index,
-				"hash", dbBatch.Hash,
-				"RollupContractAddress", r.cfg.RollupContractAddress,
-				"calldata", common.Bytes2Hex(calldata),
-				"err", err,
-			)
-			return
-		}
+// newCommitBatchPayload encodes the commitBatch arguments of the batch with the codec its header was encoded with.
+func newCommitBatchPayload(chainCfg *params.ChainConfig, dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) (*commitBatchPayload, error) {
+	codecVersion, err := batchCodecVersion(chainCfg, dbBatch, dbChunks)
+	if err != nil {
+		return nil, err
+	}
+	switch codecVersion {
+	case encoding.CodecV0:
+		return constructCommitBatchPayloadCodecV0(dbBatch, dbParentBatch, dbChunks, chunks)
+	case encoding.CodecV1:
+		return constructCommitBatchPayloadCodecV1(dbBatch, dbParentBatch, dbChunks, chunks)
+	case encoding.CodecV2:
+		return constructCommitBatchPayloadCodecV2(dbBatch, dbParentBatch, dbChunks, chunks)
+	default:
+		return nil, fmt.Errorf("unsupported codec version: %d, index: %d", codecVersion, dbBatch.Index)
+	}
+}
 
-		err = r.batchOrm.UpdateCommitTxHashAndRollupStatus(r.ctx, dbBatch.Hash, txHash.String(), types.RollupCommitting)
-		if err != nil {
-			log.Error("UpdateCommitTxHashAndRollupStatus failed", "hash", dbBatch.Hash, "index", dbBatch.Index, "err", err)
-			return
-		}
-		r.metrics.rollupL2RelayerProcessPendingBatchSuccessTotal.Inc()
-		log.Info("Sent the commitBatch tx to layer1", "batch index", dbBatch.Index, "batch hash", dbBatch.Hash, "tx hash", txHash.String())
+// batchCodecVersion returns the codec version of the batch header. The batch hash the provers prove commits
+// to the header, so the batch is committed and finalized with the contract methods of its header version, even
+// if the fork heights of the chain config moved since it was proposed.
+func batchCodecVersion(chainCfg *params.ChainConfig, dbBatch *orm.Batch, dbChunks []*orm.Chunk) (encoding.CodecVersion, error) {
+	codecVersion, err := rutils.BatchHeaderCodecVersion(dbBatch.BatchHeader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode batch header, index: %d, err: %w", dbBatch.Index, err)
 	}
+	if forkCodecVersion := rutils.CodecVersion(chainCfg, new(big.Int).SetUint64(dbChunks[0].StartBlockNumber)); forkCodecVersion != codecVersion {
+		log.Warn("batch header version is not the codec version of its hard fork", "index", dbBatch.Index, "hash", dbBatch.Hash,
+			"header version", codecVersion, "fork codec version", forkCodecVersion)
+	}
+	return codecVersion, nil
+}
+
+func (r *Layer2Relayer) packCommitBatch(p *commitBatchPayload) ([]byte, error) {
+	calldata, err := r.l1RollupABI.Pack("commitBatch", p.version, p.parentBatchHeader, p.chunks, p.skippedL1MessageBitmap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack commitBatch: %w", err)
+	}
+	return calldata, nil
+}
+
+// commitBatch sends the commitBatch transaction of the payload, the batch hash is the context id of the transaction.
+func (r *Layer2Relayer) commitBatch(p *commitBatchPayload) error {
+	dbBatch := p.dbBatch
+	calldata, err := r.packCommitBatch(p)
+	if err != nil {
+		log.Error("failed to pack commitBatch payload", "index", dbBatch.Index, "err", err)
+		return err
+	}
+
+	// fallbackGasLimit is non-zero only in sending non-blob transactions.
+	fallbackGasLimit := uint64(float64(dbBatch.TotalL1CommitGas) * r.cfg.L1CommitGasLimitMultiplier)
+	if types.RollupStatus(dbBatch.RollupStatus) == types.RollupCommitFailed {
+		// use eth_estimateGas if this batch has been committed and failed at least once.
+		fallbackGasLimit = 0
+		log.Warn("Batch commit previously failed, using eth_estimateGas for the re-submission", "hash", dbBatch.Hash)
+	}
+
+	txHash, err := r.commitSender.SendTransaction(dbBatch.Hash, &r.cfg.RollupContractAddress, calldata, p.blob, fallbackGasLimit)
+	if err != nil {
+		log.Error(
+			"Failed to send commitBatch tx to layer1",
+			"index", dbBatch.Index,
+			"hash", dbBatch.Hash,
+			"RollupContractAddress", r.cfg.RollupContractAddress,
+			"err", err,
+		)
+		log.Debug(
+			"Failed to send commitBatch tx to layer1",
+			"index", dbBatch.Index,
+			"hash", dbBatch.Hash,
+			"RollupContractAddress", r.cfg.RollupContractAddress,
+			"calldata", common.Bytes2Hex(calldata),
+			"err", err,
+		)
+		r.recordRevertReason([]string{dbBatch.Hash}, err)
+		return err
+	}
+
+	err = r.batchOrm.UpdateCommitTxHashAndRollupStatus(r.ctx, dbBatch.Hash, txHash.String(), types.RollupCommitting)
+	if err != nil {
+		log.Error("UpdateCommitTxHashAndRollupStatus failed", "hash", dbBatch.Hash, "index", dbBatch.Index, "err", err)
+		return err
+	}
+	r.metrics.rollupL2RelayerProcessPendingBatchSuccessTotal.Inc()
+	utils.TaskLogger(dbBatch.Hash, "").Info("batch included in commitBatch tx", "index", dbBatch.Index, "tx hash", txHash.String())
+	log.Info("Sent the commitBatch tx to layer1", "batch index", dbBatch.Index, "batch hash", dbBatch.Hash, "tx hash", txHash.String())
+	return nil
 }
 
 // ProcessCommittedBatches submit proof to layer 1 rollup contract
 func (r *Layer2Relayer) ProcessCommittedBatches() {
+	if r.isPaused(orm.PauseComponentFinalize) {
+		return
+	}
+
 	// retrieves the earliest batch whose rollup status is 'committed'
 	fields := map[string]interface{}{
 		"rollup_status": types.RollupCommitted,
 	}
-	orderByList := []string{"index ASC"}
+	if r.cfg.FinalizeRetry != nil {
+		// the batches are finalized in order, so the failed batches are retried before the following ones.
+		fields = map[string]interface{}{
+			"rollup_status IN ?": []int{int(types.RollupCommitted), int(types.RollupFinalizeFailed), int(types.RollupFinalizeQuarantined)},
+		}
+	}
+	orderByList := []string{`"index" ASC`}
 	limit := 1
 	batches, err := r.batchOrm.GetBatches(r.ctx, fields, orderByList, limit)
 	if err != nil {
@@ -455,6 +589,17 @@ func (r *Layer2Relayer) ProcessCommittedBatches() {
 	r.metrics.rollupL2RelayerProcessCommittedBatchesTotal.Inc()
 
 	batch := batches[0]
+	switch types.RollupStatus(batch.RollupStatus) {
+	case types.RollupFinalizeQuarantined:
+		log.Warn("finalization halted by a quarantined batch, fix it and set its rollup status back to committed", "index", batch.Index, "hash", batch.Hash, "finalize attempts", batch.FinalizeAttempts)
+		return
+	case types.RollupFinalizeFailed:
+		if batch.NextFinalizeAt != nil && utils.NowUTC().Before(*batch.NextFinalizeAt) {
+			return
+		}
+		log.Info("retry finalizing batch", "index", batch.Index, "hash", batch.Hash, "finalize attempts", batch.FinalizeAttempts)
+	}
+
 	status := types.ProvingStatus(batch.ProvingStatus)
 	switch status {
 	case types.ProvingTaskUnassigned, types.ProvingTaskAssigned:
@@ -464,19 +609,37 @@ func (r *Layer2Relayer) ProcessCommittedBatches() {
 		}
 
 		if r.cfg.EnableTestEnvBypassFeatures && utils.NowUTC().Sub(*batch.CommittedAt) > time.Duration(r.cfg.FinalizeBatchWithoutProofTimeoutSec)*time.Second {
+			if r.holdFinalize(batch, *batch.CommittedAt) || r.awaitApprovals(batch) {
+				return
+			}
 			if err := r.finalizeBatch(batch, false); err != nil {
 				log.Error("Failed to finalize timeout batch without proof", "index", batch.Index, "hash", batch.Hash, "err", err)
 			}
 		}
 
 	case types.ProvingTaskVerified:
+		if batch.ProvedAt != nil && r.holdFinalize(batch, *batch.ProvedAt) {
+			return
+		}
+		if r.awaitApprovals(batch) {
+			return
+		}
 		log.Info("Start to roll up zk proof", "hash", batch.Hash)
 		r.metrics.rollupL2RelayerProcessCommittedBatchesFinalizedTotal.Inc()
+		if r.cfg.FinalizeBundle != nil && r.cfg.FinalizeBundle.MaxBatches > 1 {
+			bundled, bundleErr := r.finalizeBundle(batch)
+			if bundleErr != nil {
+				log.Error("Failed to finalize bundle with proof", "start index", batch.Index, "err", bundleErr)
+			}
+			if bundled || bundleErr != nil {
+				return
+			}
+		}
 		if err := r.finalizeBatch(batch, true); err != nil {
 			log.Error("Failed to finalize batch with proof", "index", batch.Index, "hash", batch.Hash, "err", err)
 		}
 
-	case types.ProvingTaskFailed:
+	case types.ProvingTaskFailed, types.ProvingTaskQuarantined:
 		// We were unable to prove this batch. There are two possibilities:
 		// (a) Prover bug. In this case, we should fix and redeploy the prover.
 		//     In the meantime, we continue to commit batches to L1 as well as
@@ -499,21 +662,89 @@ func (r *Layer2Relayer) ProcessCommittedBatches() {
 	}
 }
 
+// holdCommit reports whether committing the pending batches is deferred by the L1 base fee,
+// the batches are committed anyway once the oldest one has been held for max_commit_hold_sec.
+func (r *Layer2Relayer) holdCommit(oldest *orm.Batch) bool {
+	throttle := r.cfg.GasPriceThrottle
+	if throttle == nil {
+		return false
+	}
+	baseFee, err := r.commitSender.GetBaseFee(r.ctx)
+	if err != nil {
+		log.Warn("failed to get L1 base fee, commit without throttling", "err", err)
+		return false
+	}
+	if !deferSubmission(throttle, baseFee, oldest.CreatedAt, throttle.MaxCommitHoldSec) {
+		return false
+	}
+	r.metrics.rollupL2RelayerCommitDeferredTotal.Inc()
+	log.Info("L1 base fee too high, defer committing batches", "index", oldest.Index, "base fee", baseFee, "max base fee", throttle.MaxBaseFee)
+	return true
+}
+
+// holdFinalize reports whether finalizing the batch ready since readyAt is deferred by the L1 base fee,
+// a finalization is never held past the finalize safety window.
+func (r *Layer2Relayer) holdFinalize(batch *orm.Batch, readyAt time.Time) bool {
+	throttle := r.cfg.GasPriceThrottle
+	if throttle == nil || throttle.FinalizeSafetyWindowSec == 0 {
+		return false
+	}
+	baseFee, err := r.finalizeSender.GetBaseFee(r.ctx)
+	if err != nil {
+		log.Warn("failed to get L1 base fee, finalize without throttling", "err", err)
+		return false
+	}
+	if !deferSubmission(throttle, baseFee, readyAt, throttle.FinalizeSafetyWindowSec) {
+		return false
+	}
+	r.metrics.rollupL2RelayerFinalizeDeferredTotal.Inc()
+	log.Info("L1 base fee too high, defer finalizing batch", "index", batch.Index, "hash", batch.Hash, "base fee", baseFee, "max base fee", throttle.MaxBaseFee)
+	return true
+}
+
+// awaitApprovals reports whether finalizing the batch is held until enough approvers approved it.
+// The approvals are verified when they are submitted, only the ones of the current approvers count.
+func (r *Layer2Relayer) awaitApprovals(batch *orm.Batch) bool {
+	approvalCfg := r.cfg.FinalizeApproval
+	if approvalCfg == nil {
+		return false
+	}
+	approvals, err := r.batchApprovalOrm.GetBatchApprovals(r.ctx, batch.Hash)
+	if err != nil {
+		log.Error("failed to get batch approvals, hold finalizing batch", "index", batch.Index, "hash", batch.Hash, "err", err)
+		return true
+	}
+	approved := 0
+	for _, approval := range approvals {
+		if approvalCfg.IsApprover(common.HexToAddress(approval.Approver)) {
+			approved++
+		}
+	}
+	if approved >= approvalCfg.Threshold {
+		return false
+	}
+	r.metrics.rollupL2RelayerFinalizeAwaitingApprovalTotal.Inc()
+	log.Info("batch not approved by enough co-signers, hold finalizing batch", "index", batch.Index, "hash", batch.Hash,
+		"approvals", approved, "threshold", approvalCfg.Threshold, "approval digest", batch.ApprovalDigest().Hex())
+	return true
+}
+
+// deferSubmission reports whether a transaction waiting since the given time is held at the base fee,
+// it is held for at most maxHoldSec, 0 holds it until the base fee drops.
+func deferSubmission(throttle *config.GasPriceThrottleConfig, baseFee uint64, since time.Time, maxHoldSec uint64) bool {
+	if baseFee <= throttle.MaxBaseFee {
+		return false
+	}
+	if maxHoldSec == 0 {
+		return true
+	}
+	return utils.NowUTC().Sub(since) < time.Duration(maxHoldSec)*time.Second
+}
+
 func (r *Layer2Relayer) finalizeBatch(dbBatch *orm.Batch, withProof bool) error {
 	// Check batch status before send `finalizeBatch` tx.
-	if r.cfg.ChainMonitor.Enabled {
-		var batchStatus bool
-		batchStatus, err := r.getBatchStatusByIndex(dbBatch)
-		if err != nil {
-			r.metrics.rollupL2ChainMonitorLatestFailedCall.Inc()
-			log.Warn("failed to get batch status, please check chain_monitor api server", "batch_index", dbBatch.Index, "err", err)
-			return err
-		}
-		if !batchStatus {
-			r.metrics.rollupL2ChainMonitorLatestFailedBatchStatus.Inc()
-			log.Error("the batch status is not right, stop finalize batch and check the reason", "batch_index", dbBatch.Index)
-			return err
-		}
+	if ok, err := r.checkBatchStatus(dbBatch); !ok {
+		return err
 	}
 
 	if dbBatch.Index == 0 {
@@ -525,57 +756,13 @@ func (r *Layer2Relayer) finalizeBatch(dbBatch *orm.Batch, withProof bool) error
 		return fmt.Errorf("failed to get batch, index: %d, err: %w", dbBatch.Index-1, getErr)
 	}
 
-	dbChunks, err := r.chunkOrm.GetChunksInRange(r.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	args, err := r.constructFinalizeBatchArgs(dbBatch, dbParentBatch, withProof)
 	if err != nil {
-		return fmt.Errorf("failed to fetch chunks: %w", err)
+		return fmt.Errorf("failed to construct finalizeBatch args, index: %v, err: %w", dbBatch.Index, err)
 	}
-
-	var aggProof *message.BatchProof
-	if withProof {
-		aggProof, getErr = r.batchOrm.GetVerifiedProofByHash(r.ctx, dbBatch.Hash)
-		if getErr != nil {
-			return fmt.Errorf("failed to get verified proof by hash, index: %d, err: %w", dbBatch.Index, getErr)
-		}
-
-		if err = aggProof.SanityCheck(); err != nil {
-			return fmt.Errorf("failed to check agg_proof sanity, index: %d, err: %w", dbBatch.Index, err)
-		}
-	}
-
-	var calldata []byte
-	if !r.chainCfg.IsBernoulli(new(big.Int).SetUint64(dbChunks[0].StartBlockNumber)) { // codecv0
-		calldata, err = r.constructFinalizeBatchPayloadCodecV0(dbBatch, dbParentBatch, aggProof)
-		if err != nil {
-			return fmt.Errorf("failed to construct commitBatch payload codecv0, index: %v, err: %w", dbBatch.Index, err)
-		}
-	} else if !r.chainCfg.IsCurie(new(big.Int).SetUint64(dbChunks[0].StartBlockNumber)) { // codecv1
-		chunks := make([]*encoding.Chunk, len(dbChunks))
-		for i, c := range dbChunks {
-			blocks, dbErr := r.l2BlockOrm.GetL2BlocksInRange(r.ctx, c.StartBlockNumber, c.EndBlockNumber)
-			if dbErr != nil {
-				return fmt.Errorf("failed to fetch blocks: %w", dbErr)
-			}
-			chunks[i] = &encoding.Chunk{Blocks: blocks}
-		}
-
-		calldata, err = r.constructFinalizeBatchPayloadCodecV1(dbBatch, dbParentBatch, dbChunks, chunks, aggProof)
-		if err != nil {
-			return fmt.Errorf("failed to construct commitBatch payload codecv1, index: %v, err: %w", dbBatch.Index, err)
-		}
-	} else { // codecv2
-		chunks := make([]*encoding.Chunk, len(dbChunks))
-		for i, c := range dbChunks {
-			blocks, dbErr := r.l2BlockOrm.GetL2BlocksInRange(r.ctx, c.StartBlockNumber, c.EndBlockNumber)
-			if dbErr != nil {
-				return fmt.Errorf("failed to fetch blocks: %w", dbErr)
-			}
-			chunks[i] = &encoding.Chunk{Blocks: blocks}
-		}
-
-		calldata, err = r.constructFinalizeBatchPayloadCodecV2(dbBatch, dbParentBatch, dbChunks, chunks, aggProof)
-		if err != nil {
-			return fmt.Errorf("failed to construct commitBatch payload codecv1, index: %v, err: %w", dbBatch.Index, err)
-		}
+	calldata, err := r.packFinalizeBatch(args)
+	if err != nil {
+		return fmt.Errorf("failed to construct finalizeBatch payload, index: %v, err: %w", dbBatch.Index, err)
 	}
 
 	txHash, err := r.finalizeSender.SendTransaction(dbBatch.Hash, &r.cfg.RollupContractAddress, calldata, nil, 0)
@@ -597,10 +784,11 @@ func (r *Layer2Relayer) finalizeBatch(dbBatch *orm.Batch, withProof bool) error
 			"calldata", common.Bytes2Hex(calldata),
 			"err", err,
 		)
+		r.recordRevertReason([]string{dbBatch.Hash}, err)
 		return err
 	}
 
-	log.Info("finalizeBatch in layer1", "with proof", withProof, "index", dbBatch.Index, "batch hash", dbBatch.Hash, "tx hash", txHash.String())
+	utils.TaskLogger(dbBatch.Hash, "").Info("finalizeBatch in layer1", "with proof", withProof, "index", dbBatch.Index, "tx hash", txHash.String())
 
 	// record and sync with db, @todo handle db error
 	if err := r.batchOrm.UpdateFinalizeTxHashAndRollupStatus(r.ctx, dbBatch.Hash, txHash.String(), types.RollupFinalizing); err != nil {
@@ -629,6 +817,205 @@ func (r *Layer2Relayer) finalizeBatch(dbBatch *orm.Batch, withProof bool) error
 	return nil
 }
 
+// checkBatchStatus asks the chain monitor whether the batch can be finalized, if it's enabled.
+func (r *Layer2Relayer) checkBatchStatus(dbBatch *orm.Batch) (bool, error) {
+	if !r.cfg.ChainMonitor.Enabled {
+		return true, nil
+	}
+	batchStatus, err := r.getBatchStatusByIndex(dbBatch)
+	if err != nil {
+		r.metrics.rollupL2ChainMonitorLatestFailedCall.Inc()
+		log.Warn("failed to get batch status, please check chain_monitor api server", "batch_index", dbBatch.Index, "err", err)
+		return false, err
+	}
+	if !batchStatus {
+		r.metrics.rollupL2ChainMonitorLatestFailedBatchStatus.Inc()
+		log.Error("the batch status is not right, stop finalize batch and check the reason", "batch_index", dbBatch.Index)
+		return false, nil
+	}
+	return true, nil
+}
+
+// finalizeBundle sends a single finalizeBundleWithProof4844 transaction finalizing the largest prefix of the
+// consecutive committed and proven blob batches from the first one, up to finalize_bundle.max_batches, the batch
+// hashes joined by commas are its context id. It returns false without sending anything when fewer than two
+// batches can be bundled, the first batch is finalized alone then.
+func (r *Layer2Relayer) finalizeBundle(first *orm.Batch) (bool, error) {
+	// a batch whose finalization failed is retried alone.
+	if types.RollupStatus(first.RollupStatus) != types.RollupCommitted || first.Index == 0 {
+		return false, nil
+	}
+
+	fields := map[string]interface{}{`"index" >= ?`: first.Index}
+	batches, err := r.batchOrm.GetBatches(r.ctx, fields, []string{`"index" ASC`}, r.cfg.FinalizeBundle.MaxBatches)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch batches from index %d: %w", first.Index, err)
+	}
+
+	dbParentBatch, err := r.batchOrm.GetBatchByIndex(r.ctx, first.Index-1)
+	if err != nil {
+		return false, fmt.Errorf("failed to get batch, index: %d, err: %w", first.Index-1, err)
+	}
+
+	var batchHashes []string
+	var batchHeaders, blobDataProofs, aggrProofs [][]byte
+	var postStateRoots, withdrawRoots []common.Hash
+	parent := dbParentBatch
+	for i, dbBatch := range batches {
+		if dbBatch.Index != first.Index+uint64(i) ||
+			types.RollupStatus(dbBatch.RollupStatus) != types.RollupCommitted ||
+			types.ProvingStatus(dbBatch.ProvingStatus) != types.ProvingTaskVerified {
+			break
+		}
+		if i > 0 && r.awaitApprovals(dbBatch) {
+			break
+		}
+		if ok, checkErr := r.checkBatchStatus(dbBatch); !ok {
+			if checkErr != nil {
+				return false, checkErr
+			}
+			break
+		}
+
+		args, argsErr := r.constructFinalizeBatchArgs(dbBatch, parent, true)
+		if argsErr != nil {
+			return false, fmt.Errorf("failed to construct finalizeBundle args, index: %d, err: %w", dbBatch.Index, argsErr)
+		}
+		// the codecv0 batches have no blob, they can't be bundled.
+		if args.blobDataProof == nil {
+			break
+		}
+
+		batchHashes = append(batchHashes, dbBatch.Hash)
+		batchHeaders = append(batchHeaders, args.batchHeader)
+		postStateRoots = append(postStateRoots, args.postStateRoot)
+		withdrawRoots = append(withdrawRoots, args.withdrawRoot)
+		blobDataProofs = append(blobDataProofs, args.blobDataProof)
+		aggrProofs = append(aggrProofs, args.aggProof.Proof)
+		parent = dbBatch
+	}
+	if len(batchHashes) < 2 {
+		return false, nil
+	}
+
+	calldata, err := r.l1RollupABI.Pack(
+		"finalizeBundleWithProof4844",
+		batchHeaders,
+		common.HexToHash(dbParentBatch.StateRoot),
+		postStateRoots,
+		withdrawRoots,
+		blobDataProofs,
+		aggrProofs,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack finalizeBundleWithProof4844: %w", err)
+	}
+
+	startIndex, endIndex := first.Index, first.Index+uint64(len(batchHashes))-1
+	txHash, err := r.finalizeSender.SendTransaction(strings.Join(batchHashes, contextIDSeparator), &r.cfg.RollupContractAddress, calldata, nil, 0)
+	if err != nil {
+		log.Error("finalizeBundle in layer1 failed", "start index", startIndex, "end index", endIndex, "batch hashes", batchHashes,
+			"RollupContractAddress", r.cfg.RollupContractAddress, "err", err)
+		r.recordRevertReason(batchHashes, err)
+		return true, err
+	}
+
+	// the batches are finalized by the same transaction, they are all recorded or none.
+	if err = r.batchOrm.UpdateFinalizeTxHashAndRollupStatusByHashes(r.ctx, batchHashes, txHash.String(), types.RollupFinalizing); err != nil {
+		log.Error("UpdateFinalizeTxHashAndRollupStatusByHashes failed", "start index", startIndex, "end index", endIndex, "tx hash", txHash.String(), "err", err)
+		return true, err
+	}
+
+	r.metrics.rollupL2RelayerProcessCommittedBatchesFinalizedSuccessTotal.Add(float64(len(batchHashes)))
+	r.metrics.rollupL2RelayerFinalizeBundleSize.Observe(float64(len(batchHashes)))
+	log.Info("Sent the finalizeBundle tx to layer1", "start index", startIndex, "end index", endIndex, "batch hashes", batchHashes, "tx hash", txHash.String())
+	return true, nil
+}
+
+// finalizeBatchArgs are the arguments finalizing a batch, alone or in a bundle.
+type finalizeBatchArgs struct {
+	batchHeader   []byte
+	prevStateRoot common.Hash
+	postStateRoot common.Hash
+	withdrawRoot  common.Hash
+	// blobDataProof is nil for the codecv0 batches, which have no blob.
+	blobDataProof []byte
+	// aggProof is nil when the batch is finalized without proof.
+	aggProof *message.BatchProof
+}
+
+// constructFinalizeBatchArgs returns the arguments finalizing the batch, the blob data proof of the codecv1 and
+// codecv2 batches is re-derived from their blocks.
+func (r *Layer2Relayer) constructFinalizeBatchArgs(dbBatch *orm.Batch, dbParentBatch *orm.Batch, withProof bool) (*finalizeBatchArgs, error) {
+	args := &finalizeBatchArgs{
+		batchHeader:   dbBatch.BatchHeader,
+		prevStateRoot: common.HexToHash(dbParentBatch.StateRoot),
+		postStateRoot: common.HexToHash(dbBatch.StateRoot),
+		withdrawRoot:  common.HexToHash(dbBatch.WithdrawRoot),
+	}
+
+	if withProof {
+		aggProof, err := r.batchOrm.GetVerifiedProofByHash(r.ctx, dbBatch.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get verified proof by hash: %w", err)
+		}
+		if err = aggProof.SanityCheck(); err != nil {
+			return nil, fmt.Errorf("failed to check agg_proof sanity: %w", err)
+		}
+		args.aggProof = aggProof
+	}
+
+	dbChunks, err := r.chunkOrm.GetChunksInRange(r.ctx, dbBatch.StartChunkIndex, dbBatch.EndChunkIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chunks: %w", err)
+	}
+
+	codecVersion, err := batchCodecVersion(r.chainCfg, dbBatch, dbChunks)
+	if err != nil {
+		return nil, err
+	}
+	if codecVersion == encoding.CodecV0 {
+		return args, nil
+	}
+
+	chunks := make([]*encoding.Chunk, len(dbChunks))
+	for i, c := range dbChunks {
+		blocks, dbErr := r.l2BlockOrm.GetL2BlocksInRange(r.ctx, c.StartBlockNumber, c.EndBlockNumber)
+		if dbErr != nil {
+			return nil, fmt.Errorf("failed to fetch blocks: %w", dbErr)
+		}
+		chunks[i] = &encoding.Chunk{Blocks: blocks}
+	}
+
+	batch := &encoding.Batch{
+		Index:                      dbBatch.Index,
+		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
+		ParentBatchHash:            common.HexToHash(dbParentBatch.Hash),
+		Chunks:                     chunks,
+	}
+
+	switch codecVersion {
+	case encoding.CodecV1:
+		daBatch, createErr := codecv1.NewDABatch(batch)
+		if createErr != nil {
+			return nil, fmt.Errorf("failed to create DA batch: %w", createErr)
+		}
+		args.blobDataProof, err = daBatch.BlobDataProof()
+	case encoding.CodecV2:
+		daBatch, createErr := codecv2.NewDABatch(batch)
+		if createErr != nil {
+			return nil, fmt.Errorf("failed to create DA batch: %w", createErr)
+		}
+		args.blobDataProof, err = daBatch.BlobDataProof()
+	default:
+		return nil, fmt.Errorf("unsupported codec version: %d", codecVersion)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob data proof: %w", err)
+	}
+	return args, nil
+}
+
 // batchStatusResponse the response schema
 type batchStatusResponse struct {
 	ErrCode int    `json:"errcode"`
@@ -692,6 +1079,7 @@ func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
 		if err != nil {
 			log.Warn("UpdateCommitTxHashAndRollupStatus failed", "confirmation", cfm, "err", err)
 		}
+		r.recordConfirmedRevertReason([]string{cfm.ContextID}, cfm)
 	case types.SenderTypeFinalizeBatch:
 		var status types.RollupStatus
 		if cfm.IsSuccessful {
@@ -703,9 +1091,20 @@ func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
 			log.Warn("FinalizeBatchTxType transaction confirmed but failed in layer1", "confirmation", cfm)
 		}
 
-		err := r.batchOrm.UpdateFinalizeTxHashAndRollupStatus(r.ctx, cfm.ContextID, cfm.TxHash.String(), status)
+		// a finalizeBundleWithProof4844 tx carries the hashes of all its batches in the context id.
+		batchHashes := strings.Split(cfm.ContextID, contextIDSeparator)
+		r.recordConfirmedRevertReason(batchHashes, cfm)
+
+		if status == types.RollupFinalizeFailed && r.cfg.FinalizeRetry != nil {
+			for _, batchHash := range batchHashes {
+				r.handleFinalizeFailure(cfm, batchHash)
+			}
+			break
+		}
+
+		err := r.batchOrm.UpdateFinalizeTxHashAndRollupStatusByHashes(r.ctx, batchHashes, cfm.TxHash.String(), status)
 		if err != nil {
-			log.Warn("UpdateFinalizeTxHashAndRollupStatus failed", "confirmation", cfm, "err", err)
+			log.Warn("UpdateFinalizeTxHashAndRollupStatusByHashes failed", "confirmation", cfm, "err", err)
 		}
 	case types.SenderTypeL2GasOracle:
 		batchHash := cfm.ContextID
@@ -730,6 +1129,85 @@ func (r *Layer2Relayer) handleConfirmation(cfm *sender.Confirmation) {
 	log.Info("Transaction confirmed in layer1", "confirmation", cfm)
 }
 
+// handleFinalizeFailure schedules the next finalize attempt of the batch with an exponential backoff,
+// or quarantines the batch and alerts the operator once it reaches the max attempts.
+func (r *Layer2Relayer) handleFinalizeFailure(cfm *sender.Confirmation, batchHash string) {
+	batch, err := r.batchOrm.GetBatchByHash(r.ctx, batchHash)
+	if err != nil {
+		log.Warn("GetBatchByHash failed", "confirmation", cfm, "err", err)
+		return
+	}
+
+	retryCfg := r.cfg.FinalizeRetry
+	attempts := batch.FinalizeAttempts + 1
+	status := types.RollupFinalizeFailed
+	if attempts >= retryCfg.MaxAttempts {
+		status = types.RollupFinalizeQuarantined
+	}
+
+	nextFinalizeAt := utils.NowUTC().Add(finalizeBackoff(retryCfg, attempts))
+	if err = r.batchOrm.UpdateFinalizeAttempts(r.ctx, batch.Hash, cfm.TxHash.String(), status, attempts, nextFinalizeAt); err != nil {
+		log.Warn("UpdateFinalizeAttempts failed", "confirmation", cfm, "err", err)
+		return
+	}
+
+	if status != types.RollupFinalizeQuarantined {
+		log.Warn("finalize batch failed, retry later", "index", batch.Index, "hash", batch.Hash, "finalize attempts", attempts, "next finalize at", nextFinalizeAt)
+		return
+	}
+
+	r.metrics.rollupL2BatchesFinalizeQuarantinedTotal.Inc()
+	log.Error("batch quarantined after too many failed finalize attempts", "index", batch.Index, "hash", batch.Hash, "finalize attempts", attempts, "tx hash", cfm.TxHash.String())
+	r.alertQuarantinedBatch(batch, attempts, cfm.TxHash)
+}
+
+// finalizeBackoff returns the delay before the next finalize attempt after the given number of failed attempts.
+func finalizeBackoff(retryCfg *config.FinalizeRetryConfig, attempts int16) time.Duration {
+	backoff := time.Duration(retryCfg.InitialBackoffSec) * time.Second
+	maxBackoff := time.Duration(retryCfg.MaxBackoffSec) * time.Second
+	for i := int16(1); i < attempts; i++ {
+		if maxBackoff > 0 && backoff >= maxBackoff {
+			break
+		}
+		backoff *= 2
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// quarantineAlert the body of the alert posted to the webhook
+type quarantineAlert struct {
+	Message          string `json:"message"`
+	BatchIndex       uint64 `json:"batch_index"`
+	BatchHash        string `json:"batch_hash"`
+	FinalizeAttempts int16  `json:"finalize_attempts"`
+	LastTxHash       string `json:"last_tx_hash"`
+}
+
+func (r *Layer2Relayer) alertQuarantinedBatch(batch *orm.Batch, attempts int16, txHash common.Hash) {
+	if r.alertClient == nil {
+		return
+	}
+
+	alert := quarantineAlert{
+		Message:          fmt.Sprintf("batch %d can't be finalized after %d attempts, finalization is halted", batch.Index, attempts),
+		BatchIndex:       batch.Index,
+		BatchHash:        batch.Hash,
+		FinalizeAttempts: attempts,
+		LastTxHash:       txHash.String(),
+	}
+	resp, err := r.alertClient.R().SetBody(alert).Post(r.cfg.FinalizeRetry.AlertWebhookURL)
+	if err != nil {
+		log.Error("failed to send quarantined batch alert", "index", batch.Index, "err", err)
+		return
+	}
+	if resp.IsError() {
+		log.Error("failed to send quarantined batch alert", "index", batch.Index, "status", resp.Status())
+	}
+}
+
 func (r *Layer2Relayer) handleL2GasOracleConfirmLoop(ctx context.Context) {
 	for {
 		select {
@@ -741,6 +1219,27 @@ func (r *Layer2Relayer) handleL2GasOracleConfirmLoop(ctx context.Context) {
 	}
 }
 
+// recordRevertReason records the revert reason of a commit or finalize transaction that was not sent because its simulation failed.
+func (r *Layer2Relayer) recordRevertReason(batchHashes []string, err error) {
+	var simulationErr *sender.SimulationError
+	if !errors.As(err, &simulationErr) {
+		return
+	}
+	if updateErr := r.batchOrm.UpdateRevertReasonByHashes(r.ctx, batchHashes, simulationErr.RevertReason); updateErr != nil {
+		log.Error("UpdateRevertReasonByHashes failed", "hashes", batchHashes, "revert reason", simulationErr.RevertReason, "err", updateErr)
+	}
+}
+
+// recordConfirmedRevertReason records the revert reason of a commit or finalize transaction that reverted on L1.
+func (r *Layer2Relayer) recordConfirmedRevertReason(batchHashes []string, cfm *sender.Confirmation) {
+	if cfm.IsSuccessful || cfm.RevertReason == "" {
+		return
+	}
+	if err := r.batchOrm.UpdateRevertReasonByHashes(r.ctx, batchHashes, cfm.RevertReason); err != nil {
+		log.Error("UpdateRevertReasonByHashes failed", "hashes", batchHashes, "revert reason", cfm.RevertReason, "err", err)
+	}
+}
+
 func (r *Layer2Relayer) handleL2RollupRelayerConfirmLoop(ctx context.Context) {
 	for {
 		select {
@@ -754,10 +1253,10 @@ func (r *Layer2Relayer) handleL2RollupRelayerConfirmLoop(ctx context.Context) {
 	}
 }
 
-func (r *Layer2Relayer) constructCommitBatchPayloadCodecV0(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) ([]byte, error) {
-	daBatch, err := codecv0.NewDABatchFromBytes(dbBatch.BatchHeader)
+func constructCommitBatchPayloadCodecV0(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) (*commitBatchPayload, error) {
+	batchHeader, err := cencoding.DecodeBatchHeader(dbBatch.BatchHeader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create DA batch from bytes: %w", err)
+		return nil, fmt.Errorf("failed to decode batch header: %w", err)
 	}
 
 	encodedChunks := make([][]byte, len(dbChunks))
@@ -773,14 +1272,16 @@ func (r *Layer2Relayer) constructCommitBatchPayloadCodecV0(dbBatch *orm.Batch, d
 		encodedChunks[i] = daChunkBytes
 	}
 
-	calldata, packErr := r.l1RollupABI.Pack("commitBatch", daBatch.Version, dbParentBatch.BatchHeader, encodedChunks, daBatch.SkippedL1MessageBitmap)
-	if packErr != nil {
-		return nil, fmt.Errorf("failed to pack commitBatch: %w", packErr)
-	}
-	return calldata, nil
+	return &commitBatchPayload{
+		dbBatch:                dbBatch,
+		version:                batchHeader.Version,
+		parentBatchHeader:      dbParentBatch.BatchHeader,
+		chunks:                 encodedChunks,
+		skippedL1MessageBitmap: batchHeader.SkippedL1MessageBitmap,
+	}, nil
 }
 
-func (r *Layer2Relayer) constructCommitBatchPayloadCodecV1(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) ([]byte, *kzg4844.Blob, error) {
+func constructCommitBatchPayloadCodecV1(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) (*commitBatchPayload, error) {
 	batch := &encoding.Batch{
 		Index:                      dbBatch.Index,
 		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
@@ -790,26 +1291,29 @@ func (r *Layer2Relayer) constructCommitBatchPayloadCodecV1(dbBatch *orm.Batch, d
 
 	daBatch, createErr := codecv1.NewDABatch(batch)
 	if createErr != nil {
-		return nil, nil, fmt.Errorf("failed to create DA batch: %w", createErr)
+		return nil, fmt.Errorf("failed to create DA batch: %w", createErr)
 	}
 
 	encodedChunks := make([][]byte, len(dbChunks))
 	for i, c := range dbChunks {
 		daChunk, createErr := codecv1.NewDAChunk(chunks[i], c.TotalL1MessagesPoppedBefore)
 		if createErr != nil {
-			return nil, nil, fmt.Errorf("failed to create DA chunk: %w", createErr)
+			return nil, fmt.Errorf("failed to create DA chunk: %w", createErr)
 		}
 		encodedChunks[i] = daChunk.Encode()
 	}
 
-	calldata, packErr := r.l1RollupABI.Pack("commitBatch", daBatch.Version, dbParentBatch.BatchHeader, encodedChunks, daBatch.SkippedL1MessageBitmap)
-	if packErr != nil {
-		return nil, nil, fmt.Errorf("failed to pack commitBatch: %w", packErr)
-	}
-	return calldata, daBatch.Blob(), nil
+	return &commitBatchPayload{
+		dbBatch:                dbBatch,
+		version:                daBatch.Version,
+		parentBatchHeader:      dbParentBatch.BatchHeader,
+		chunks:                 encodedChunks,
+		skippedL1MessageBitmap: daBatch.SkippedL1MessageBitmap,
+		blob:                   daBatch.Blob(),
+	}, nil
 }
 
-func (r *Layer2Relayer) constructCommitBatchPayloadCodecV2(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) ([]byte, *kzg4844.Blob, error) {
+func constructCommitBatchPayloadCodecV2(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk) (*commitBatchPayload, error) {
 	batch := &encoding.Batch{
 		Index:                      dbBatch.Index,
 		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
@@ -819,151 +1323,74 @@ func (r *Layer2Relayer) constructCommitBatchPayloadCodecV2(dbBatch *orm.Batch, d
 
 	daBatch, createErr := codecv2.NewDABatch(batch)
 	if createErr != nil {
-		return nil, nil, fmt.Errorf("failed to create DA batch: %w", createErr)
+		return nil, fmt.Errorf("failed to create DA batch: %w", createErr)
 	}
 
 	encodedChunks := make([][]byte, len(dbChunks))
 	for i, c := range dbChunks {
 		daChunk, createErr := codecv2.NewDAChunk(chunks[i], c.TotalL1MessagesPoppedBefore)
 		if createErr != nil {
-			return nil, nil, fmt.Errorf("failed to create DA chunk: %w", createErr)
+			return nil, fmt.Errorf("failed to create DA chunk: %w", createErr)
 		}
 		encodedChunks[i] = daChunk.Encode()
 	}
 
-	calldata, packErr := r.l1RollupABI.Pack("commitBatch", daBatch.Version, dbParentBatch.BatchHeader, encodedChunks, daBatch.SkippedL1MessageBitmap)
-	if packErr != nil {
-		return nil, nil, fmt.Errorf("failed to pack commitBatch: %w", packErr)
-	}
-	return calldata, daBatch.Blob(), nil
+	return &commitBatchPayload{
+		dbBatch:                dbBatch,
+		version:                daBatch.Version,
+		parentBatchHeader:      dbParentBatch.BatchHeader,
+		chunks:                 encodedChunks,
+		skippedL1MessageBitmap: daBatch.SkippedL1MessageBitmap,
+		blob:                   daBatch.Blob(),
+	}, nil
 }
 
-func (r *Layer2Relayer) constructFinalizeBatchPayloadCodecV0(dbBatch *orm.Batch, dbParentBatch *orm.Batch, aggProof *message.BatchProof) ([]byte, error) {
-	if aggProof != nil { // finalizeBatch with proof.
-		calldata, packErr := r.l1RollupABI.Pack(
-			"finalizeBatchWithProof",
-			dbBatch.BatchHeader,
-			common.HexToHash(dbParentBatch.StateRoot),
-			common.HexToHash(dbBatch.StateRoot),
-			common.HexToHash(dbBatch.WithdrawRoot),
-			aggProof.Proof,
-		)
-		if packErr != nil {
-			return nil, fmt.Errorf("failed to pack finalizeBatchWithProof: %w", packErr)
-		}
-		return calldata, nil
+// packFinalizeBatch packs the finalizeBatch call of a single batch, finalizeBatch4844 for the blob batches and
+// finalizeBatchWithProof or finalizeBatchWithProof4844 with the proof.
+func (r *Layer2Relayer) packFinalizeBatch(args *finalizeBatchArgs) ([]byte, error) {
+	var method string
+	var calldata []byte
+	var err error
+	switch {
+	case args.blobDataProof == nil && args.aggProof == nil:
+		method = "finalizeBatch"
+		calldata, err = r.l1RollupABI.Pack(method, args.batchHeader, args.prevStateRoot, args.postStateRoot, args.withdrawRoot)
+	case args.blobDataProof == nil:
+		method = "finalizeBatchWithProof"
+		calldata, err = r.l1RollupABI.Pack(method, args.batchHeader, args.prevStateRoot, args.postStateRoot, args.withdrawRoot, args.aggProof.Proof)
+	case args.aggProof == nil:
+		method = "finalizeBatch4844"
+		calldata, err = r.l1RollupABI.Pack(method, args.batchHeader, args.prevStateRoot, args.postStateRoot, args.withdrawRoot, args.blobDataProof)
+	default:
+		method = "finalizeBatchWithProof4844"
+		calldata, err = r.l1RollupABI.Pack(method, args.batchHeader, args.prevStateRoot, args.postStateRoot, args.withdrawRoot, args.blobDataProof, args.aggProof.Proof)
 	}
-
-	// finalizeBatch without proof.
-	calldata, packErr := r.l1RollupABI.Pack(
-		"finalizeBatch",
-		dbBatch.BatchHeader,
-		common.HexToHash(dbParentBatch.StateRoot),
-		common.HexToHash(dbBatch.StateRoot),
-		common.HexToHash(dbBatch.WithdrawRoot),
-	)
-	if packErr != nil {
-		return nil, fmt.Errorf("failed to pack finalizeBatch: %w", packErr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
 	}
 	return calldata, nil
 }
 
-func (r *Layer2Relayer) constructFinalizeBatchPayloadCodecV1(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk, aggProof *message.BatchProof) ([]byte, error) {
-	batch := &encoding.Batch{
-		Index:                      dbBatch.Index,
-		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
-		ParentBatchHash:            common.HexToHash(dbParentBatch.Hash),
-		Chunks:                     chunks,
-	}
-
-	daBatch, createErr := codecv1.NewDABatch(batch)
-	if createErr != nil {
-		return nil, fmt.Errorf("failed to create DA batch: %w", createErr)
-	}
-
-	blobDataProof, getErr := daBatch.BlobDataProof()
-	if getErr != nil {
-		return nil, fmt.Errorf("failed to get blob data proof: %w", getErr)
-	}
-
-	if aggProof != nil { // finalizeBatch4844 with proof.
-		calldata, packErr := r.l1RollupABI.Pack(
-			"finalizeBatchWithProof4844",
-			dbBatch.BatchHeader,
-			common.HexToHash(dbParentBatch.StateRoot),
-			common.HexToHash(dbBatch.StateRoot),
-			common.HexToHash(dbBatch.WithdrawRoot),
-			blobDataProof,
-			aggProof.Proof,
-		)
-		if packErr != nil {
-			return nil, fmt.Errorf("failed to pack finalizeBatchWithProof4844: %w", packErr)
+// CheckSenders checks the endpoints and the signers of the senders of the relayer, for its readiness probe.
+func (r *Layer2Relayer) CheckSenders(ctx context.Context) error {
+	if r.gasOracleSender != nil {
+		if err := r.gasOracleSender.Check(ctx); err != nil {
+			return err
 		}
-		return calldata, nil
 	}
 
-	// finalizeBatch4844 without proof.
-	calldata, packErr := r.l1RollupABI.Pack(
-		"finalizeBatch4844",
-		dbBatch.BatchHeader,
-		common.HexToHash(dbParentBatch.StateRoot),
-		common.HexToHash(dbBatch.StateRoot),
-		common.HexToHash(dbBatch.WithdrawRoot),
-		blobDataProof,
-	)
-	if packErr != nil {
-		return nil, fmt.Errorf("failed to pack finalizeBatch4844: %w", packErr)
-	}
-	return calldata, nil
-}
-
-func (r *Layer2Relayer) constructFinalizeBatchPayloadCodecV2(dbBatch *orm.Batch, dbParentBatch *orm.Batch, dbChunks []*orm.Chunk, chunks []*encoding.Chunk, aggProof *message.BatchProof) ([]byte, error) {
-	batch := &encoding.Batch{
-		Index:                      dbBatch.Index,
-		TotalL1MessagePoppedBefore: dbChunks[0].TotalL1MessagesPoppedBefore,
-		ParentBatchHash:            common.HexToHash(dbParentBatch.Hash),
-		Chunks:                     chunks,
-	}
-
-	daBatch, createErr := codecv2.NewDABatch(batch)
-	if createErr != nil {
-		return nil, fmt.Errorf("failed to create DA batch: %w", createErr)
-	}
-
-	blobDataProof, getErr := daBatch.BlobDataProof()
-	if getErr != nil {
-		return nil, fmt.Errorf("failed to get blob data proof: %w", getErr)
-	}
-
-	if aggProof != nil { // finalizeBatch4844 with proof.
-		calldata, packErr := r.l1RollupABI.Pack(
-			"finalizeBatchWithProof4844",
-			dbBatch.BatchHeader,
-			common.HexToHash(dbParentBatch.StateRoot),
-			common.HexToHash(dbBatch.StateRoot),
-			common.HexToHash(dbBatch.WithdrawRoot),
-			blobDataProof,
-			aggProof.Proof,
-		)
-		if packErr != nil {
-			return nil, fmt.Errorf("failed to pack finalizeBatchWithProof4844: %w", packErr)
+	if r.commitSender != nil {
+		if err := r.commitSender.Check(ctx); err != nil {
+			return err
 		}
-		return calldata, nil
 	}
 
-	// finalizeBatch4844 without proof.
-	calldata, packErr := r.l1RollupABI.Pack(
-		"finalizeBatch4844",
-		dbBatch.BatchHeader,
-		common.HexToHash(dbParentBatch.StateRoot),
-		common.HexToHash(dbBatch.StateRoot),
-		common.HexToHash(dbBatch.WithdrawRoot),
-		blobDataProof,
-	)
-	if packErr != nil {
-		return nil, fmt.Errorf("failed to pack finalizeBatch4844: %w", packErr)
+	if r.finalizeSender != nil {
+		if err := r.finalizeSender.Check(ctx); err != nil {
+			return err
+		}
 	}
-	return calldata, nil
+	return nil
 }
 
 // StopSenders stops the senders of the rollup-relayer to prevent querying the removed pending_transaction table in unit tests.
@@ -19,10 +19,15 @@ type l2RelayerMetrics struct {
 	rollupL2BatchesCommittedConfirmedFailedTotal                prometheus.Counter
 	rollupL2BatchesFinalizedConfirmedTotal                      prometheus.Counter
 	rollupL2BatchesFinalizedConfirmedFailedTotal                prometheus.Counter
+	rollupL2BatchesFinalizeQuarantinedTotal                     prometheus.Counter
 	rollupL2UpdateGasOracleConfirmedTotal                       prometheus.Counter
 	rollupL2UpdateGasOracleConfirmedFailedTotal                 prometheus.Counter
 	rollupL2ChainMonitorLatestFailedCall                        prometheus.Counter
 	rollupL2ChainMonitorLatestFailedBatchStatus                 prometheus.Counter
+	rollupL2RelayerCommitDeferredTotal                          prometheus.Counter
+	rollupL2RelayerFinalizeDeferredTotal                        prometheus.Counter
+	rollupL2RelayerFinalizeAwaitingApprovalTotal                prometheus.Counter
+	rollupL2RelayerFinalizeBundleSize                           prometheus.Histogram
 }
 
 var (
@@ -77,6 +82,10 @@ func initL2RelayerMetrics(reg prometheus.Registerer) *l2RelayerMetrics {
 				Name: "rollup_layer2_process_finalized_batches_confirmed_failed_total",
 				Help: "The total number of layer2 process finalized batches confirmed failed total",
 			}),
+			rollupL2BatchesFinalizeQuarantinedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_batches_finalize_quarantined_total",
+				Help: "The total number of layer2 batches quarantined after too many failed finalize attempts",
+			}),
 			rollupL2UpdateGasOracleConfirmedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 				Name: "rollup_layer2_update_layer1_gas_oracle_confirmed_total",
 				Help: "The total number of updating layer2 gas oracle confirmed",
@@ -93,6 +102,23 @@ func initL2RelayerMetrics(reg prometheus.Registerer) *l2RelayerMetrics {
 				Name: "rollup_layer2_chain_monitor_latest_failed_batch_status",
 				Help: "The total number of failed batch status get from chain_monitor",
 			}),
+			rollupL2RelayerCommitDeferredTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_commit_deferred_total",
+				Help: "The total number of layer2 commit submissions deferred by the L1 base fee",
+			}),
+			rollupL2RelayerFinalizeDeferredTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_finalize_deferred_total",
+				Help: "The total number of layer2 finalize submissions deferred by the L1 base fee",
+			}),
+			rollupL2RelayerFinalizeAwaitingApprovalTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_layer2_relayer_finalize_awaiting_approval_total",
+				Help: "The total number of layer2 finalize submissions held until the batch is approved by enough co-signers",
+			}),
+			rollupL2RelayerFinalizeBundleSize: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+				Name:    "rollup_layer2_relayer_finalize_bundle_size",
+				Help:    "The number of batches finalized by the layer2 finalizeBundle transactions",
+				Buckets: prometheus.LinearBuckets(2, 1, 15),
+			}),
 		}
 	})
 	return l2RelayerMetric
@@ -27,6 +27,7 @@ import (
 
 	"scroll-tech/database/migrate"
 
+	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/controller/sender"
 	"scroll-tech/rollup/internal/orm"
 	rutils "scroll-tech/rollup/internal/utils"
@@ -44,7 +45,7 @@ func setupL2RelayerDB(t *testing.T) *gorm.DB {
 func testCreateNewRelayer(t *testing.T) {
 	db := setupL2RelayerDB(t)
 	defer database.CloseDB(db)
-	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, nil, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, relayer)
 	defer relayer.StopSenders()
@@ -62,7 +63,7 @@ func testL2RelayerProcessPendingBatches(t *testing.T) {
 			chainConfig.BernoulliBlock = big.NewInt(0)
 		}
 
-		relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, l2Cfg.RelayerConfig, chainConfig, true, ServiceTypeL2RollupRelayer, nil)
+		relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, nil, l2Cfg.RelayerConfig, chainConfig, true, ServiceTypeL2RollupRelayer, nil)
 		assert.NoError(t, err)
 
 		patchGuard := gomonkey.ApplyMethodFunc(l2Cli, "SendTransaction", func(_ context.Context, _ *gethTypes.Transaction) error {
@@ -111,7 +112,7 @@ func testL2RelayerProcessCommittedBatches(t *testing.T) {
 		if codecVersion == encoding.CodecV0 {
 			chainConfig.BernoulliBlock = big.NewInt(0)
 		}
-		relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, l2Cfg.RelayerConfig, chainConfig, true, ServiceTypeL2RollupRelayer, nil)
+		relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, nil, l2Cfg.RelayerConfig, chainConfig, true, ServiceTypeL2RollupRelayer, nil)
 		assert.NoError(t, err)
 
 		l2BlockOrm := orm.NewL2Block(db)
@@ -176,7 +177,7 @@ func testL2RelayerFinalizeTimeoutBatches(t *testing.T) {
 		if codecVersion == encoding.CodecV0 {
 			chainConfig.BernoulliBlock = big.NewInt(0)
 		}
-		relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, l2Cfg.RelayerConfig, chainConfig, true, ServiceTypeL2RollupRelayer, nil)
+		relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, nil, l2Cfg.RelayerConfig, chainConfig, true, ServiceTypeL2RollupRelayer, nil)
 		assert.NoError(t, err)
 
 		l2BlockOrm := orm.NewL2Block(db)
@@ -240,7 +241,7 @@ func testL2RelayerCommitConfirm(t *testing.T) {
 	l2Cfg := cfg.L2Config
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, l2Cfg.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, nil, l2Cfg.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
 	assert.NoError(t, err)
 	defer l2Relayer.StopSenders()
 
@@ -296,7 +297,7 @@ func testL2RelayerFinalizeConfirm(t *testing.T) {
 	l2Cfg := cfg.L2Config
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, l2Cfg.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, nil, l2Cfg.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
 	assert.NoError(t, err)
 	defer l2Relayer.StopSenders()
 
@@ -344,6 +345,122 @@ func testL2RelayerFinalizeConfirm(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func testL2RelayerFinalizeBundleConfirm(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	// Create and set up the Layer2 Relayer.
+	l2Cfg := cfg.L2Config
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, nil, l2Cfg.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer l2Relayer.StopSenders()
+
+	batchOrm := orm.NewBatch(db)
+	batchHashes := make([]string, 2)
+	for i := range batchHashes {
+		batch := &encoding.Batch{
+			Index:                      uint64(i + 1),
+			TotalL1MessagePoppedBefore: 0,
+			ParentBatchHash:            common.Hash{},
+			Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+		}
+
+		dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, rutils.BatchMetrics{})
+		assert.NoError(t, err)
+		batchHashes[i] = dbBatch.Hash
+	}
+
+	// a single confirmation of a finalizeBundleWithProof4844 tx updates all its batches.
+	l2Relayer.finalizeSender.SendConfirmation(&sender.Confirmation{
+		ContextID:    strings.Join(batchHashes, contextIDSeparator),
+		IsSuccessful: true,
+		TxHash:       common.HexToHash("0x123456789abcdef"),
+		SenderType:   types.SenderTypeFinalizeBatch,
+	})
+
+	ok := utils.TryTimes(5, func() bool {
+		statuses, err := batchOrm.GetRollupStatusByHashList(context.Background(), batchHashes)
+		if err != nil || len(statuses) != len(batchHashes) {
+			return false
+		}
+		for _, status := range statuses {
+			if status != types.RollupFinalized {
+				return false
+			}
+		}
+		return true
+	})
+	assert.True(t, ok)
+}
+
+func testL2RelayerFinalizeRetry(t *testing.T) {
+	db := setupL2RelayerDB(t)
+	defer database.CloseDB(db)
+
+	// Create and set up the Layer2 Relayer quarantining a batch after two failed finalize attempts.
+	relayerCfg := *cfg.L2Config.RelayerConfig
+	relayerCfg.FinalizeRetry = &config.FinalizeRetryConfig{MaxAttempts: 2, InitialBackoffSec: 60}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, nil, &relayerCfg, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	assert.NoError(t, err)
+	defer l2Relayer.StopSenders()
+
+	batchOrm := orm.NewBatch(db)
+	batch := &encoding.Batch{
+		Index:                      1,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.Hash{},
+		Chunks:                     []*encoding.Chunk{chunk1, chunk2},
+	}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, rutils.BatchMetrics{})
+	assert.NoError(t, err)
+
+	expectedStatuses := []types.RollupStatus{types.RollupFinalizeFailed, types.RollupFinalizeQuarantined}
+	for i, expectedStatus := range expectedStatuses {
+		l2Relayer.finalizeSender.SendConfirmation(&sender.Confirmation{
+			ContextID:    dbBatch.Hash,
+			IsSuccessful: false,
+			TxHash:       common.HexToHash("0x123456789abcdef"),
+			SenderType:   types.SenderTypeFinalizeBatch,
+		})
+
+		ok := utils.TryTimes(5, func() bool {
+			batchInDB, err := batchOrm.GetBatchByHash(context.Background(), dbBatch.Hash)
+			return err == nil && types.RollupStatus(batchInDB.RollupStatus) == expectedStatus &&
+				batchInDB.FinalizeAttempts == int16(i+1) && batchInDB.NextFinalizeAt != nil
+		})
+		assert.True(t, ok)
+	}
+}
+
+func testFinalizeBackoff(t *testing.T) {
+	retryCfg := &config.FinalizeRetryConfig{InitialBackoffSec: 10, MaxBackoffSec: 60}
+	assert.Equal(t, 10*time.Second, finalizeBackoff(retryCfg, 1))
+	assert.Equal(t, 20*time.Second, finalizeBackoff(retryCfg, 2))
+	assert.Equal(t, 40*time.Second, finalizeBackoff(retryCfg, 3))
+	assert.Equal(t, 60*time.Second, finalizeBackoff(retryCfg, 4))
+	assert.Equal(t, 60*time.Second, finalizeBackoff(retryCfg, 100))
+
+	retryCfg.MaxBackoffSec = 0
+	assert.Equal(t, 80*time.Second, finalizeBackoff(retryCfg, 4))
+}
+
+func testDeferSubmission(t *testing.T) {
+	throttle := &config.GasPriceThrottleConfig{MaxBaseFee: 100}
+	now := utils.NowUTC()
+
+	// nothing is held below the max base fee.
+	assert.False(t, deferSubmission(throttle, 100, now, 0))
+	assert.True(t, deferSubmission(throttle, 101, now.Add(-time.Hour), 0))
+
+	// nor past the max hold time.
+	assert.True(t, deferSubmission(throttle, 101, now.Add(-30*time.Second), 60))
+	assert.False(t, deferSubmission(throttle, 101, now.Add(-61*time.Second), 60))
+}
+
 func testL2RelayerGasOracleConfirm(t *testing.T) {
 	db := setupL2RelayerDB(t)
 	defer database.CloseDB(db)
@@ -373,7 +490,7 @@ func testL2RelayerGasOracleConfirm(t *testing.T) {
 	l2Cfg := cfg.L2Config
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, l2Cfg.RelayerConfig, &params.ChainConfig{}, false, ServiceTypeL2GasOracle, nil)
+	l2Relayer, err := NewLayer2Relayer(ctx, l2Cli, db, nil, l2Cfg.RelayerConfig, &params.ChainConfig{}, false, ServiceTypeL2GasOracle, nil)
 	assert.NoError(t, err)
 	defer l2Relayer.StopSenders()
 
@@ -413,7 +530,7 @@ func testLayer2RelayerProcessGasPriceOracle(t *testing.T) {
 	db := setupL2RelayerDB(t)
 	defer database.CloseDB(db)
 
-	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, false, ServiceTypeL2GasOracle, nil)
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, nil, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, false, ServiceTypeL2GasOracle, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, relayer)
 	defer relayer.StopSenders()
@@ -509,7 +626,7 @@ func testGetBatchStatusByIndex(t *testing.T) {
 	defer database.CloseDB(db)
 
 	cfg.L2Config.RelayerConfig.ChainMonitor.Enabled = true
-	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
+	relayer, err := NewLayer2Relayer(context.Background(), l2Cli, db, nil, cfg.L2Config.RelayerConfig, &params.ChainConfig{}, true, ServiceTypeL2RollupRelayer, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, relayer)
 	defer relayer.StopSenders()
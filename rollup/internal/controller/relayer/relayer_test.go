@@ -64,7 +64,7 @@ func setupEnv(t *testing.T) {
 	assert.NoError(t, err)
 	cfg.DBConfig = &database.Config{
 		DSN:        dsn,
-		DriverName: "postgres",
+		DriverName: testApps.GetDBDriverName(),
 		MaxOpenNum: 200,
 		MaxIdleNum: 20,
 	}
@@ -127,6 +127,10 @@ func TestFunctions(t *testing.T) {
 	t.Run("TestL2RelayerFinalizeTimeoutBatches", testL2RelayerFinalizeTimeoutBatches)
 	t.Run("TestL2RelayerCommitConfirm", testL2RelayerCommitConfirm)
 	t.Run("TestL2RelayerFinalizeConfirm", testL2RelayerFinalizeConfirm)
+	t.Run("TestL2RelayerFinalizeBundleConfirm", testL2RelayerFinalizeBundleConfirm)
+	t.Run("TestL2RelayerFinalizeRetry", testL2RelayerFinalizeRetry)
+	t.Run("TestFinalizeBackoff", testFinalizeBackoff)
+	t.Run("TestDeferSubmission", testDeferSubmission)
 	t.Run("TestL2RelayerGasOracleConfirm", testL2RelayerGasOracleConfirm)
 	t.Run("TestLayer2RelayerProcessGasPriceOracle", testLayer2RelayerProcessGasPriceOracle)
 	// test getBatchStatusByIndex
@@ -0,0 +1,85 @@
+package sender
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+)
+
+// exportedTransaction is the file a transaction is exported to by the dry-run mode.
+type exportedTransaction struct {
+	ContextID string         `json:"context_id"`
+	Service   string         `json:"service"`
+	Name      string         `json:"name"`
+	From      common.Address `json:"from"`
+	ChainID   *hexutil.Big   `json:"chain_id"`
+	Nonce     uint64         `json:"nonce"`
+	Signed    bool           `json:"signed"`
+	// Hash is the hash of the signed transaction, or the signing hash of the unsigned one.
+	Hash common.Hash `json:"hash"`
+	// Tx is the transaction in json, RawTx its binary encoding, with the blobs of a blob transaction.
+	Tx    *gethTypes.Transaction `json:"tx"`
+	RawTx hexutil.Bytes          `json:"raw_tx"`
+}
+
+// exportTransaction writes the transaction of the next nonce to the export dir instead of sending it, and advances
+// the nonce. The transaction is not tracked as pending, the rollup status of its batch is updated from the L1 events
+// once it has been submitted externally.
+func (s *Sender) exportTransaction(contextID string, feeData *FeeData, target *common.Address, data []byte, sidecar *gethTypes.BlobTxSidecar) (common.Hash, error) {
+	nonce := s.auth.Nonce.Uint64()
+	txData, err := s.newTxData(feeData, target, data, sidecar, nonce)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx := gethTypes.NewTx(txData)
+	hash := gethTypes.LatestSignerForChainID(s.chainID).Hash(tx)
+	if s.config.DryRun.Sign {
+		if tx, err = s.auth.Signer(s.auth.From, tx); err != nil {
+			return common.Hash{}, fmt.Errorf("failed to sign the exported transaction, err: %w", err)
+		}
+		hash = tx.Hash()
+	}
+
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode the exported transaction, err: %w", err)
+	}
+	buf, err := json.MarshalIndent(&exportedTransaction{
+		ContextID: contextID,
+		Service:   s.service,
+		Name:      s.name,
+		From:      s.auth.From,
+		ChainID:   (*hexutil.Big)(new(big.Int).Set(s.chainID)),
+		Nonce:     nonce,
+		Signed:    s.config.DryRun.Sign,
+		Hash:      hash,
+		Tx:        tx,
+		RawTx:     rawTx,
+	}, "", "  ")
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to marshal the exported transaction, err: %w", err)
+	}
+
+	// the file is renamed into place, so the submitters never read a partial file.
+	fileName := filepath.Join(s.config.DryRun.ExportDir, fmt.Sprintf("%s-%s-%s-%d.json", s.service, s.name, s.auth.From.Hex(), nonce))
+	if err = os.WriteFile(fileName+".tmp", buf, 0o600); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to write the exported transaction, err: %w", err)
+	}
+	if err = os.Rename(fileName+".tmp", fileName); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to write the exported transaction, err: %w", err)
+	}
+
+	s.auth.Nonce = big.NewInt(int64(nonce + 1))
+	s.metrics.exportTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+	log.Info("exported transaction instead of sending it", "service", s.service, "name", s.name, "context id", contextID,
+		"from", s.auth.From.String(), "nonce", nonce, "hash", hash.String(), "signed", s.config.DryRun.Sign, "file", fileName)
+	return hash, nil
+}
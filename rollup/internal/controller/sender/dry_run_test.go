@@ -0,0 +1,76 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestExportTransaction(t *testing.T) {
+	priv, err := crypto.HexToECDSA("1212121212121212121212121212121212121212121212121212121212121212")
+	assert.NoError(t, err)
+	signer := NewPrivateKeySigner(priv)
+	chainID := big.NewInt(1337)
+
+	exportDir := t.TempDir()
+	s := &Sender{
+		config:  &config.SenderConfig{TxType: DynamicFeeTxType, DryRun: &config.DryRunConfig{ExportDir: exportDir}},
+		chainID: chainID,
+		service: "rollup_relayer",
+		name:    "commit_sender",
+		auth: &bind.TransactOpts{
+			From:  signer.Address(),
+			Nonce: big.NewInt(7),
+			Signer: func(_ common.Address, tx *gethTypes.Transaction) (*gethTypes.Transaction, error) {
+				return signer.SignTx(context.Background(), tx, chainID)
+			},
+		},
+		metrics: initSenderMetrics(nil),
+	}
+	feeData := &FeeData{gasFeeCap: big.NewInt(2e9), gasTipCap: big.NewInt(1e9), gasLimit: 100000}
+	target := common.HexToAddress("0x1234")
+
+	readExported := func(nonce uint64) *exportedTransaction {
+		buf, readErr := os.ReadFile(filepath.Join(exportDir, "rollup_relayer-commit_sender-"+signer.Address().Hex()+"-"+big.NewInt(int64(nonce)).String()+".json"))
+		assert.NoError(t, readErr)
+		var exported exportedTransaction
+		assert.NoError(t, json.Unmarshal(buf, &exported))
+		return &exported
+	}
+
+	// unsigned, the hash is the signing hash.
+	hash, err := s.exportTransaction("batch-1", feeData, &target, []byte{0x01}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), s.auth.Nonce.Uint64())
+	exported := readExported(7)
+	assert.Equal(t, "batch-1", exported.ContextID)
+	assert.False(t, exported.Signed)
+	assert.Equal(t, hash, exported.Hash)
+	assert.Equal(t, uint64(7), exported.Tx.Nonce())
+	assert.Equal(t, &target, exported.Tx.To())
+	assert.Equal(t, gethTypes.LatestSignerForChainID(chainID).Hash(exported.Tx), hash)
+
+	// signed but not broadcast.
+	s.config.DryRun.Sign = true
+	hash, err = s.exportTransaction("batch-2", feeData, &target, []byte{0x02}, nil)
+	assert.NoError(t, err)
+	exported = readExported(8)
+	assert.True(t, exported.Signed)
+	var tx gethTypes.Transaction
+	assert.NoError(t, tx.UnmarshalBinary(exported.RawTx))
+	assert.Equal(t, hash, tx.Hash())
+	from, err := gethTypes.Sender(gethTypes.LatestSignerForChainID(chainID), &tx)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.Address(), from)
+}
@@ -41,7 +41,7 @@ func (s *Sender) estimateLegacyGas(to *common.Address, data []byte, fallbackGasL
 }
 
 func (s *Sender) estimateDynamicGas(to *common.Address, data []byte, baseFee uint64, fallbackGasLimit uint64) (*FeeData, error) {
-	gasTipCap, err := s.client.SuggestGasTipCap(s.ctx)
+	gasTipCap, err := s.suggestGasTipCap()
 	if err != nil {
 		log.Error("estimateDynamicGas SuggestGasTipCap failure", "error", err)
 		return nil, err
@@ -77,7 +77,7 @@ func (s *Sender) estimateDynamicGas(to *common.Address, data []byte, baseFee uin
 }
 
 func (s *Sender) estimateBlobGas(to *common.Address, data []byte, sidecar *gethTypes.BlobTxSidecar, baseFee, blobBaseFee uint64, fallbackGasLimit uint64) (*FeeData, error) {
-	gasTipCap, err := s.client.SuggestGasTipCap(s.ctx)
+	gasTipCap, err := s.suggestGasTipCap()
 	if err != nil {
 		log.Error("estimateBlobGas SuggestGasTipCap failure", "error", err)
 		return nil, err
@@ -115,6 +115,20 @@ func (s *Sender) estimateBlobGas(to *common.Address, data []byte, sidecar *gethT
 	return feeData, nil
 }
 
+// suggestGasTipCap returns the priority fee suggested by the fee strategy, or by the node if the strategy fails.
+func (s *Sender) suggestGasTipCap() (*big.Int, error) {
+	gasTipCap, err := s.feeStrategy.SuggestGasTipCap(s.ctx)
+	if err == nil {
+		return gasTipCap, nil
+	}
+	if _, ok := s.feeStrategy.(*nodeFeeStrategy); ok {
+		return nil, err
+	}
+	s.metrics.feeStrategyFallbackTotal.WithLabelValues(s.service, s.name).Inc()
+	log.Warn("fee strategy failed, fall back to the priority fee suggested by the node", "sender meta", s.getSenderMeta(), "err", err)
+	return s.client.SuggestGasTipCap(s.ctx)
+}
+
 func (s *Sender) estimateGasLimit(to *common.Address, data []byte, sidecar *gethTypes.BlobTxSidecar, gasPrice, gasTipCap, gasFeeCap, blobGasFeeCap *big.Int) (uint64, *types.AccessList, error) {
 	msg := ethereum.CallMsg{
 		From:      s.auth.From,
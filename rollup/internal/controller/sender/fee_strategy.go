@@ -0,0 +1,186 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	// NodeFeeStrategy asks the node for the priority fee with eth_maxPriorityFeePerGas.
+	NodeFeeStrategy = "node"
+	// BlockPercentileFeeStrategy pays the percentile of the priority fees paid in the latest block.
+	BlockPercentileFeeStrategy = "block_percentile"
+	// FeeHistoryFeeStrategy pays the median over the latest blocks of the percentile of the priority fees paid in a block.
+	FeeHistoryFeeStrategy = "fee_history"
+	// OracleFeeStrategy pays the priority fee returned by an external fee oracle.
+	OracleFeeStrategy = "oracle"
+
+	defaultFeePercentile      = 50
+	defaultFeeHistoryBlocks   = 20
+	defaultOracleField        = "max_priority_fee_per_gas"
+	defaultOracleTimeoutSec   = 5
+	maxFeeHistoryBlocksPerReq = 1024
+)
+
+// FeeStrategy suggests the priority fee per gas of the dynamic fee and blob transactions.
+type FeeStrategy interface {
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// NewFeeStrategy creates the fee strategy of the config for the transactions of the sender type,
+// the override of the sender type replaces the strategy of the config.
+func NewFeeStrategy(cfg *config.FeeStrategyConfig, senderType types.SenderType, rpcClient *rpc.Client) (FeeStrategy, error) {
+	if cfg == nil {
+		return &nodeFeeStrategy{client: ethclient.NewClient(rpcClient)}, nil
+	}
+	if override, ok := cfg.Overrides[feeStrategyOverrideKey(senderType)]; ok && override != nil {
+		cfg = override
+	}
+
+	percentile := cfg.Percentile
+	if percentile == 0 {
+		percentile = defaultFeePercentile
+	}
+	if percentile < 0 || percentile > 100 {
+		return nil, fmt.Errorf("invalid fee strategy percentile: %v", cfg.Percentile)
+	}
+
+	switch cfg.Type {
+	case "", NodeFeeStrategy:
+		return &nodeFeeStrategy{client: ethclient.NewClient(rpcClient)}, nil
+	case BlockPercentileFeeStrategy:
+		return &feeHistoryFeeStrategy{client: rpcClient, blocks: 1, percentile: percentile}, nil
+	case FeeHistoryFeeStrategy:
+		blocks := cfg.Blocks
+		if blocks == 0 {
+			blocks = defaultFeeHistoryBlocks
+		}
+		if blocks > maxFeeHistoryBlocksPerReq {
+			return nil, fmt.Errorf("fee history strategy blocks must be at most %d, got %d", maxFeeHistoryBlocksPerReq, blocks)
+		}
+		return &feeHistoryFeeStrategy{client: rpcClient, blocks: blocks, percentile: percentile}, nil
+	case OracleFeeStrategy:
+		if cfg.OracleURL == "" {
+			return nil, errors.New("oracle fee strategy has no oracle url")
+		}
+		field := cfg.OracleField
+		if field == "" {
+			field = defaultOracleField
+		}
+		timeout := time.Duration(cfg.OracleTimeoutSec) * time.Second
+		if timeout == 0 {
+			timeout = defaultOracleTimeoutSec * time.Second
+		}
+		return &oracleFeeStrategy{url: cfg.OracleURL, field: field, client: &http.Client{Timeout: timeout}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported fee strategy: %s", cfg.Type)
+	}
+}
+
+func feeStrategyOverrideKey(senderType types.SenderType) string {
+	switch senderType {
+	case types.SenderTypeCommitBatch:
+		return "commit_batch"
+	case types.SenderTypeFinalizeBatch:
+		return "finalize_batch"
+	case types.SenderTypeL1GasOracle:
+		return "l1_gas_oracle"
+	case types.SenderTypeL2GasOracle:
+		return "l2_gas_oracle"
+	default:
+		return ""
+	}
+}
+
+type nodeFeeStrategy struct {
+	client *ethclient.Client
+}
+
+func (f *nodeFeeStrategy) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return f.client.SuggestGasTipCap(ctx)
+}
+
+type feeHistoryFeeStrategy struct {
+	client     *rpc.Client
+	blocks     uint64
+	percentile float64
+}
+
+func (f *feeHistoryFeeStrategy) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var feeHistory struct {
+		Reward [][]*hexutil.Big `json:"reward"`
+	}
+	if err := f.client.CallContext(ctx, &feeHistory, "eth_feeHistory", hexutil.Uint64(f.blocks), "latest", []float64{f.percentile}); err != nil {
+		return nil, err
+	}
+
+	rewards := make([]*big.Int, 0, len(feeHistory.Reward))
+	for _, blockReward := range feeHistory.Reward {
+		if len(blockReward) > 0 && blockReward[0] != nil {
+			rewards = append(rewards, blockReward[0].ToInt())
+		}
+	}
+	if len(rewards) == 0 {
+		return nil, errors.New("fee history has no reward")
+	}
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+	return new(big.Int).Set(rewards[len(rewards)/2]), nil
+}
+
+type oracleFeeStrategy struct {
+	url    string
+	field  string
+	client *http.Client
+}
+
+func (f *oracleFeeStrategy) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fee oracle status %s", resp.Status)
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.NewDecoder(resp.Body).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to decode fee oracle response, err: %w", err)
+	}
+	raw, ok := fields[f.field]
+	if !ok {
+		return nil, fmt.Errorf("fee oracle response has no %s field", f.field)
+	}
+	return parseWei(strings.Trim(string(raw), `"`))
+}
+
+// parseWei parses a wei amount written in decimal or in 0x prefixed hex.
+func parseWei(value string) (*big.Int, error) {
+	if strings.HasPrefix(value, "0x") {
+		return hexutil.DecodeBig(value)
+	}
+	wei, ok := new(big.Int).SetString(value, 10)
+	if !ok || wei.Sign() < 0 {
+		return nil, fmt.Errorf("invalid wei amount: %s", value)
+	}
+	return wei, nil
+}
@@ -0,0 +1,86 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestNewFeeStrategy(t *testing.T) {
+	rpcClient, err := rpc.DialHTTP("http://localhost:8545")
+	assert.NoError(t, err)
+
+	strategy, err := NewFeeStrategy(nil, types.SenderTypeCommitBatch, rpcClient)
+	assert.NoError(t, err)
+	assert.IsType(t, &nodeFeeStrategy{}, strategy)
+
+	cfg := &config.FeeStrategyConfig{
+		Type:   FeeHistoryFeeStrategy,
+		Blocks: 10,
+		Overrides: map[string]*config.FeeStrategyConfig{
+			"finalize_batch": {Type: BlockPercentileFeeStrategy, Percentile: 90},
+		},
+	}
+	strategy, err = NewFeeStrategy(cfg, types.SenderTypeCommitBatch, rpcClient)
+	assert.NoError(t, err)
+	assert.Equal(t, &feeHistoryFeeStrategy{client: rpcClient, blocks: 10, percentile: defaultFeePercentile}, strategy)
+	strategy, err = NewFeeStrategy(cfg, types.SenderTypeFinalizeBatch, rpcClient)
+	assert.NoError(t, err)
+	assert.Equal(t, &feeHistoryFeeStrategy{client: rpcClient, blocks: 1, percentile: 90}, strategy)
+
+	_, err = NewFeeStrategy(&config.FeeStrategyConfig{Type: OracleFeeStrategy}, types.SenderTypeCommitBatch, rpcClient)
+	assert.Error(t, err)
+	_, err = NewFeeStrategy(&config.FeeStrategyConfig{Type: BlockPercentileFeeStrategy, Percentile: 101}, types.SenderTypeCommitBatch, rpcClient)
+	assert.Error(t, err)
+	_, err = NewFeeStrategy(&config.FeeStrategyConfig{Type: "fixed"}, types.SenderTypeCommitBatch, rpcClient)
+	assert.Error(t, err)
+}
+
+func TestFeeHistoryFeeStrategy(t *testing.T) {
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"oldestBlock":"0x1","reward":[["0x3"],["0x1"],["0x2"]],"baseFeePerGas":["0x1","0x1","0x1","0x1"],"gasUsedRatio":[0.5,0.5,0.5]}}`)
+	}))
+	defer node.Close()
+
+	rpcClient, err := rpc.DialHTTP(node.URL)
+	assert.NoError(t, err)
+	strategy := &feeHistoryFeeStrategy{client: rpcClient, blocks: 3, percentile: 50}
+	gasTipCap, err := strategy.SuggestGasTipCap(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2), gasTipCap)
+}
+
+func TestOracleFeeStrategy(t *testing.T) {
+	response := `{"max_priority_fee_per_gas":"1500000000","fast":"0x77359400"}`
+	oracle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, response)
+	}))
+	defer oracle.Close()
+
+	strategy, err := NewFeeStrategy(&config.FeeStrategyConfig{Type: OracleFeeStrategy, OracleURL: oracle.URL}, types.SenderTypeL2GasOracle, nil)
+	assert.NoError(t, err)
+	gasTipCap, err := strategy.SuggestGasTipCap(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1500000000), gasTipCap)
+
+	strategy, err = NewFeeStrategy(&config.FeeStrategyConfig{Type: OracleFeeStrategy, OracleURL: oracle.URL, OracleField: "fast"}, types.SenderTypeL2GasOracle, nil)
+	assert.NoError(t, err)
+	gasTipCap, err = strategy.SuggestGasTipCap(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2000000000), gasTipCap)
+
+	response = `{"fast":"-1"}`
+	_, err = strategy.SuggestGasTipCap(context.Background())
+	assert.Error(t, err)
+}
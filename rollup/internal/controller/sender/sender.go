@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"strings"
 	"time"
 
@@ -20,10 +21,11 @@ import (
 	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/ethclient/gethclient"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rlp"
-	"github.com/scroll-tech/go-ethereum/rpc"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/rpcpool"
 	"scroll-tech/common/types"
 
 	"scroll-tech/rollup/internal/config"
@@ -39,12 +41,23 @@ const (
 	DynamicFeeTxType = "DynamicFeeTx"
 )
 
+// maxReconciledTransactions bounds the pending transactions of an account picked up on startup.
+const maxReconciledTransactions = 1000
+
+// ErrInsufficientBalance is returned when a transaction is not sent because the account balance can't cover its maximum cost.
+var ErrInsufficientBalance = errors.New("account balance can't cover the transaction cost")
+
+// ErrFeeCapReached is returned when a transaction can't be resubmitted because its fees are already at the configured cap.
+var ErrFeeCapReached = errors.New("transaction fees already reached the configured cap")
+
 // Confirmation struct used to indicate transaction confirmation details
 type Confirmation struct {
 	ContextID    string
 	IsSuccessful bool
 	TxHash       common.Hash
 	SenderType   types.SenderType
+	// RevertReason is the decoded revert reason of a failed transaction, empty if it couldn't be replayed.
+	RevertReason string
 }
 
 // FeeData fee struct used to estimate gas price
@@ -71,7 +84,10 @@ type Sender struct {
 	name       string
 	senderType types.SenderType
 
-	auth *bind.TransactOpts
+	feeStrategy FeeStrategy
+
+	auth   *bind.TransactOpts
+	signer Signer
 
 	db                    *gorm.DB
 	pendingTransactionOrm *orm.PendingTransaction
@@ -82,26 +98,51 @@ type Sender struct {
 	metrics *senderMetrics
 }
 
-// NewSender returns a new instance of transaction sender
+// NewSender returns a new instance of transaction sender signing with a private key
 func NewSender(ctx context.Context, config *config.SenderConfig, priv *ecdsa.PrivateKey, service, name string, senderType types.SenderType, db *gorm.DB, reg prometheus.Registerer) (*Sender, error) {
+	return NewSenderWithSigner(ctx, config, NewPrivateKeySigner(priv), service, name, senderType, db, reg)
+}
+
+// NewSenderWithSigner returns a new instance of transaction sender signing with the signer
+func NewSenderWithSigner(ctx context.Context, config *config.SenderConfig, signer Signer, service, name string, senderType types.SenderType, db *gorm.DB, reg prometheus.Registerer) (*Sender, error) {
 	if config.EscalateMultipleNum <= config.EscalateMultipleDen {
 		return nil, fmt.Errorf("invalid params, EscalateMultipleNum; %v, EscalateMultipleDen: %v", config.EscalateMultipleNum, config.EscalateMultipleDen)
 	}
 
-	rpcClient, err := rpc.Dial(config.Endpoint)
+	if config.DryRun != nil {
+		if config.DryRun.ExportDir == "" {
+			return nil, errors.New("invalid params, the dry run export dir is empty")
+		}
+		if err := os.MkdirAll(config.DryRun.ExportDir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create the dry run export dir, err: %w", err)
+		}
+	}
+
+	rpcClient, err := rpcpool.Dial(ctx, config.Endpoint, config.RPCPool)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial eth client, err: %w", err)
 	}
 
+	feeStrategy, err := NewFeeStrategy(config.FeeStrategy, senderType, rpcClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fee strategy, err: %w", err)
+	}
+
 	client := ethclient.NewClient(rpcClient)
 	chainID, err := client.ChainID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chain ID, err: %w", err)
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(priv, chainID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor with chain ID %v, err: %w", chainID, err)
+	auth := &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(address common.Address, tx *gethTypes.Transaction) (*gethTypes.Transaction, error) {
+			if address != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(ctx, tx, chainID)
+		},
+		Context: ctx,
 	}
 
 	// Set pending nonce
@@ -118,6 +159,7 @@ func NewSender(ctx context.Context, config *config.SenderConfig, priv *ecdsa.Pri
 		client:                client,
 		chainID:               chainID,
 		auth:                  auth,
+		signer:                signer,
 		db:                    db,
 		pendingTransactionOrm: orm.NewPendingTransaction(db),
 		confirmCh:             make(chan *Confirmation, 128),
@@ -125,9 +167,15 @@ func NewSender(ctx context.Context, config *config.SenderConfig, priv *ecdsa.Pri
 		name:                  name,
 		service:               service,
 		senderType:            senderType,
+		feeStrategy:           feeStrategy,
 	}
 	sender.metrics = initSenderMetrics(reg)
 
+	// the transactions sent before a restart are picked up before sending anything new, so no nonce is reused or skipped.
+	if err = sender.reconcilePendingTransactions(ctx); err != nil {
+		return nil, fmt.Errorf("failed to reconcile pending transactions for address %s, err: %w", auth.From.Hex(), err)
+	}
+
 	go sender.loop(ctx)
 
 	return sender, nil
@@ -138,6 +186,40 @@ func (s *Sender) GetChainID() *big.Int {
 	return s.chainID
 }
 
+// GetAddress returns the address of the sender account.
+func (s *Sender) GetAddress() common.Address {
+	return s.auth.From
+}
+
+// Check checks the endpoint of the sender is reachable, and signs a dummy transaction, which is never sent, to check
+// the signer of the account is available.
+func (s *Sender) Check(ctx context.Context) error {
+	if _, err := s.client.BlockNumber(ctx); err != nil {
+		return fmt.Errorf("endpoint of %s unreachable, err: %w", s.auth.From.Hex(), err)
+	}
+	tx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{ChainID: s.chainID, To: &s.auth.From})
+	if _, err := s.signer.SignTx(ctx, tx, s.chainID); err != nil {
+		return fmt.Errorf("signer of %s unavailable, err: %w", s.auth.From.Hex(), err)
+	}
+	return nil
+}
+
+// GetPendingCount returns the number of transactions of the sender account waiting for confirmation.
+func (s *Sender) GetPendingCount(ctx context.Context) (int64, error) {
+	return s.pendingTransactionOrm.GetPendingTransactionCountBySenderTypeAndAddress(ctx, s.senderType, s.auth.From.String())
+}
+
+// GetBalance returns the latest balance of the sender account.
+func (s *Sender) GetBalance(ctx context.Context) (*big.Int, error) {
+	return s.client.BalanceAt(ctx, s.auth.From, nil)
+}
+
+// GetBaseFee returns the base fee of the latest block.
+func (s *Sender) GetBaseFee(ctx context.Context) (uint64, error) {
+	_, baseFee, _, err := s.getBlockNumberAndBaseFeeAndBlobFee(ctx)
+	return baseFee, err
+}
+
 // Stop stop the sender module.
 func (s *Sender) Stop() {
 	close(s.stopCh)
@@ -199,6 +281,26 @@ func (s *Sender) SendTransaction(contextID string, target *common.Address, data
 		return common.Hash{}, fmt.Errorf("failed to get fee data, err: %w", err)
 	}
 
+	if s.config.SimulateTx {
+		if err = s.simulateTx(feeData, target, data, sidecar); err != nil {
+			s.metrics.sendTransactionFailureSimulate.WithLabelValues(s.service, s.name).Inc()
+			log.Error("failed to simulate tx, not sending it", "from", s.auth.From.String(), "nonce", s.auth.Nonce.Uint64(), "context id", contextID, "err", err)
+			return common.Hash{}, err
+		}
+	}
+
+	if s.config.PauseOnInsufficientBalance {
+		if err = s.checkBalance(feeData, sidecar); err != nil {
+			s.metrics.sendTransactionFailureBalance.WithLabelValues(s.service, s.name).Inc()
+			log.Error("account balance can't cover the tx, not sending it", "from", s.auth.From.String(), "nonce", s.auth.Nonce.Uint64(), "context id", contextID, "err", err)
+			return common.Hash{}, err
+		}
+	}
+
+	if s.config.DryRun != nil {
+		return s.exportTransaction(contextID, feeData, target, data, sidecar)
+	}
+
 	if tx, err = s.createAndSendTx(feeData, target, data, sidecar, nil); err != nil {
 		s.metrics.sendTransactionFailureSendTx.WithLabelValues(s.service, s.name).Inc()
 		log.Error("failed to create and send tx (non-resubmit case)", "from", s.auth.From.String(), "nonce", s.auth.Nonce.Uint64(), "err", err)
@@ -212,17 +314,86 @@ func (s *Sender) SendTransaction(contextID string, target *common.Address, data
 	return tx.Hash(), nil
 }
 
+// checkBalance returns ErrInsufficientBalance if the balance of the account is below the maximum cost of the transaction.
+func (s *Sender) checkBalance(feeData *FeeData, sidecar *gethTypes.BlobTxSidecar) error {
+	gasPrice := feeData.gasPrice
+	if s.config.TxType != LegacyTxType {
+		gasPrice = feeData.gasFeeCap
+	}
+	cost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(feeData.gasLimit))
+	if sidecar != nil && feeData.blobGasFeeCap != nil {
+		blobGas := new(big.Int).SetUint64(uint64(len(sidecar.Blobs)) * params.BlobTxBlobGasPerBlob)
+		cost.Add(cost, blobGas.Mul(blobGas, feeData.blobGasFeeCap))
+	}
+
+	balance, err := s.GetBalance(s.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get sender balance, err: %w", err)
+	}
+	if balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w, balance: %s, cost: %s", ErrInsufficientBalance, balance, cost)
+	}
+	return nil
+}
+
 func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, data []byte, sidecar *gethTypes.BlobTxSidecar, overrideNonce *uint64) (*gethTypes.Transaction, error) {
-	var (
-		nonce  = s.auth.Nonce.Uint64()
-		txData gethTypes.TxData
-	)
+	nonce := s.auth.Nonce.Uint64()
 
 	// this is a resubmit call, override the nonce
 	if overrideNonce != nil {
 		nonce = *overrideNonce
 	}
 
+	txData, err := s.newTxData(feeData, target, data, sidecar, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// sign and send
+	signedTx, err := s.auth.Signer(s.auth.From, gethTypes.NewTx(txData))
+	if err != nil {
+		log.Error("failed to sign tx", "address", s.auth.From.String(), "err", err)
+		return nil, err
+	}
+
+	if err = s.client.SendTransaction(s.ctx, signedTx); err != nil {
+		log.Error("failed to send tx", "tx hash", signedTx.Hash().String(), "from", s.auth.From.String(), "nonce", signedTx.Nonce(), "err", err)
+		// Check if contain nonce, and reset nonce
+		// only reset nonce when it is not from resubmit
+		if strings.Contains(err.Error(), "nonce too low") && overrideNonce == nil {
+			s.resetNonce(context.Background())
+		}
+		return nil, err
+	}
+
+	if feeData.gasTipCap != nil {
+		s.metrics.currentGasTipCap.WithLabelValues(s.service, s.name).Set(float64(feeData.gasTipCap.Uint64()))
+	}
+
+	if feeData.gasFeeCap != nil {
+		s.metrics.currentGasFeeCap.WithLabelValues(s.service, s.name).Set(float64(feeData.gasFeeCap.Uint64()))
+	}
+
+	if feeData.gasPrice != nil {
+		s.metrics.currentGasPrice.WithLabelValues(s.service, s.name).Set(float64(feeData.gasPrice.Uint64()))
+	}
+
+	if feeData.blobGasFeeCap != nil {
+		s.metrics.currentBlobGasFeeCap.WithLabelValues(s.service, s.name).Set(float64(feeData.blobGasFeeCap.Uint64()))
+	}
+
+	s.metrics.currentGasLimit.WithLabelValues(s.service, s.name).Set(float64(feeData.gasLimit))
+
+	// update nonce when it is not from resubmit
+	if overrideNonce == nil {
+		s.auth.Nonce = big.NewInt(int64(nonce + 1))
+	}
+	return signedTx, nil
+}
+
+// newTxData builds the transaction of the configured type with the fees and the nonce.
+func (s *Sender) newTxData(feeData *FeeData, target *common.Address, data []byte, sidecar *gethTypes.BlobTxSidecar, nonce uint64) (gethTypes.TxData, error) {
+	var txData gethTypes.TxData
 	switch s.config.TxType {
 	case LegacyTxType:
 		txData = &gethTypes.LegacyTx{
@@ -265,57 +436,75 @@ func (s *Sender) createAndSendTx(feeData *FeeData, target *common.Address, data
 			}
 		}
 	}
+	return txData, nil
+}
 
-	// sign and send
-	signedTx, err := s.auth.Signer(s.auth.From, gethTypes.NewTx(txData))
+// resetNonce reset nonce if send signed tx failed.
+func (s *Sender) resetNonce(ctx context.Context) {
+	nonce, err := s.client.PendingNonceAt(ctx, s.auth.From)
 	if err != nil {
-		log.Error("failed to sign tx", "address", s.auth.From.String(), "err", err)
-		return nil, err
+		log.Warn("failed to reset nonce", "address", s.auth.From.String(), "err", err)
+		return
 	}
+	s.auth.Nonce = big.NewInt(int64(nonce))
+}
 
-	if err = s.client.SendTransaction(s.ctx, signedTx); err != nil {
-		log.Error("failed to send tx", "tx hash", signedTx.Hash().String(), "from", s.auth.From.String(), "nonce", signedTx.Nonce(), "err", err)
-		// Check if contain nonce, and reset nonce
-		// only reset nonce when it is not from resubmit
-		if strings.Contains(err.Error(), "nonce too low") && overrideNonce == nil {
-			s.resetNonce(context.Background())
-		}
-		return nil, err
+// reconcilePendingTransactions sends the pending transactions of the account recorded in the db again, as the node
+// may have dropped them while the sender was down, and moves the nonce past them. The transactions mined meanwhile
+// are left to checkPendingTransaction, which confirms them as usual.
+func (s *Sender) reconcilePendingTransactions(ctx context.Context) error {
+	txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderTypeAndAddress(ctx, s.senderType, s.auth.From.String(), maxReconciledTransactions)
+	if err != nil {
+		return fmt.Errorf("failed to load pending transactions, err: %w", err)
 	}
-
-	if feeData.gasTipCap != nil {
-		s.metrics.currentGasTipCap.WithLabelValues(s.service, s.name).Set(float64(feeData.gasTipCap.Uint64()))
+	if len(txs) == 0 {
+		return nil
 	}
 
-	if feeData.gasFeeCap != nil {
-		s.metrics.currentGasFeeCap.WithLabelValues(s.service, s.name).Set(float64(feeData.gasFeeCap.Uint64()))
+	minedNonce, err := s.client.NonceAt(ctx, s.auth.From, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce, err: %w", err)
 	}
 
-	if feeData.gasPrice != nil {
-		s.metrics.currentGasPrice.WithLabelValues(s.service, s.name).Set(float64(feeData.gasPrice.Uint64()))
-	}
+	nextNonce := s.auth.Nonce.Uint64()
+	var resent int
+	for _, txn := range txs {
+		// a replaced transaction is superseded by the pending one of the same nonce.
+		if txn.Status != types.TxStatusPending || txn.Nonce < minedNonce {
+			continue
+		}
 
-	if feeData.blobGasFeeCap != nil {
-		s.metrics.currentBlobGasFeeCap.WithLabelValues(s.service, s.name).Set(float64(feeData.blobGasFeeCap.Uint64()))
-	}
+		tx := new(gethTypes.Transaction)
+		if err := tx.DecodeRLP(rlp.NewStream(bytes.NewReader(txn.RLPEncoding), 0)); err != nil {
+			log.Error("failed to decode RLP", "context ID", txn.ContextID, "sender meta", s.getSenderMeta(), "err", err)
+			continue
+		}
 
-	s.metrics.currentGasLimit.WithLabelValues(s.service, s.name).Set(float64(feeData.gasLimit))
+		if err := s.client.SendTransaction(ctx, tx); err != nil && !isKnownTransactionError(err) {
+			log.Warn("failed to send pending transaction again", "context ID", txn.ContextID, "hash", tx.Hash().String(), "from", s.auth.From.String(), "nonce", tx.Nonce(), "err", err)
+		} else {
+			resent++
+			s.metrics.reconcileTransactionTotal.WithLabelValues(s.service, s.name).Inc()
+		}
 
-	// update nonce when it is not from resubmit
-	if overrideNonce == nil {
-		s.auth.Nonce = big.NewInt(int64(nonce + 1))
+		if tx.Nonce() >= nextNonce {
+			nextNonce = tx.Nonce() + 1
+		}
 	}
-	return signedTx, nil
-}
 
-// resetNonce reset nonce if send signed tx failed.
-func (s *Sender) resetNonce(ctx context.Context) {
-	nonce, err := s.client.PendingNonceAt(ctx, s.auth.From)
-	if err != nil {
-		log.Warn("failed to reset nonce", "address", s.auth.From.String(), "err", err)
-		return
+	if nextNonce != s.auth.Nonce.Uint64() {
+		log.Warn("pending nonce of the node is behind the pending transactions", "address", s.auth.From.String(), "node nonce", s.auth.Nonce.Uint64(), "nonce", nextNonce)
 	}
-	s.auth.Nonce = big.NewInt(int64(nonce))
+	s.auth.Nonce = new(big.Int).SetUint64(nextNonce)
+
+	log.Info("reconciled pending transactions", "service", s.service, "name", s.name, "address", s.auth.From.String(), "resent", resent, "mined nonce", minedNonce, "nonce", nextNonce)
+	return nil
+}
+
+// isKnownTransactionError reports whether sending a transaction failed because the node already has it, or it's mined.
+func isKnownTransactionError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction") || strings.Contains(msg, "nonce too low")
 }
 
 func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee, blobBaseFee uint64) (*gethTypes.Transaction, error) {
@@ -336,6 +525,10 @@ func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee, blobBas
 	switch s.config.TxType {
 	case LegacyTxType:
 		originalGasPrice := tx.GasPrice()
+		if originalGasPrice.Cmp(maxGasPrice) >= 0 {
+			return nil, ErrFeeCapReached
+		}
+
 		gasPrice := new(big.Int).Mul(originalGasPrice, escalateMultipleNum)
 		gasPrice = new(big.Int).Div(gasPrice, escalateMultipleDen)
 		if gasPrice.Cmp(maxGasPrice) > 0 {
@@ -355,6 +548,9 @@ func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee, blobBas
 		if tx.BlobTxSidecar() == nil {
 			originalGasTipCap := tx.GasTipCap()
 			originalGasFeeCap := tx.GasFeeCap()
+			if originalGasFeeCap.Cmp(maxGasPrice) >= 0 {
+				return nil, ErrFeeCapReached
+			}
 
 			gasTipCap := new(big.Int).Mul(originalGasTipCap, escalateMultipleNum)
 			gasTipCap = new(big.Int).Div(gasTipCap, escalateMultipleDen)
@@ -397,6 +593,10 @@ func (s *Sender) resubmitTransaction(tx *gethTypes.Transaction, baseFee, blobBas
 			originalGasTipCap := tx.GasTipCap()
 			originalGasFeeCap := tx.GasFeeCap()
 			originalBlobGasFeeCap := tx.BlobGasFeeCap()
+			// a blob transaction replacement must bump both the fee cap and the blob fee cap.
+			if originalGasFeeCap.Cmp(maxGasPrice) >= 0 || originalBlobGasFeeCap.Cmp(maxBlobGasPrice) >= 0 {
+				return nil, ErrFeeCapReached
+			}
 
 			// bumping at least 100%
 			gasTipCap := new(big.Int).Mul(originalGasTipCap, big.NewInt(2))
@@ -468,7 +668,8 @@ func (s *Sender) checkPendingTransaction() {
 		return
 	}
 
-	transactionsToCheck, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(s.ctx, s.senderType, 100)
+	// only check the transactions of this account, other accounts of a pool sign with other keys and track their own nonces.
+	transactionsToCheck, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderTypeAndAddress(s.ctx, s.senderType, s.auth.From.String(), 100)
 	if err != nil {
 		log.Error("failed to load pending transactions", "sender meta", s.getSenderMeta(), "err", err)
 		return
@@ -507,14 +708,19 @@ func (s *Sender) checkPendingTransaction() {
 					log.Error("db transaction failed after receiving confirmation", "err", err)
 					return
 				}
+				s.metrics.transactionConfirmationDuration.WithLabelValues(s.service, s.name).Observe(time.Since(txnToCheck.CreatedAt).Seconds())
 
 				// send confirm message
-				s.confirmCh <- &Confirmation{
+				cfm := &Confirmation{
 					ContextID:    txnToCheck.ContextID,
 					IsSuccessful: receipt.Status == gethTypes.ReceiptStatusSuccessful,
 					TxHash:       tx.Hash(),
 					SenderType:   s.senderType,
 				}
+				if !cfm.IsSuccessful {
+					cfm.RevertReason = s.replayRevertReason(tx, receipt.BlockNumber)
+				}
+				s.confirmCh <- cfm
 			}
 		} else if txnToCheck.Status == types.TxStatusPending && // Only try resubmitting a new transaction based on gas price of the last transaction (status pending) with same ContextID.
 			s.config.EscalateBlocks+txnToCheck.SubmitBlockNumber <= blockNumber {
@@ -540,7 +746,11 @@ func (s *Sender) checkPendingTransaction() {
 				"currentBlockNumber", blockNumber,
 				"escalateBlocks", s.config.EscalateBlocks)
 
-			if newTx, err := s.resubmitTransaction(tx, baseFee, blobBaseFee); err != nil {
+			if newTx, err := s.resubmitTransaction(tx, baseFee, blobBaseFee); errors.Is(err, ErrFeeCapReached) {
+				// keep waiting for the transaction, a replacement above the cap would be rejected or overpay.
+				s.metrics.resubmitTransactionFeeCapReachedTotal.WithLabelValues(s.service, s.name).Inc()
+				log.Warn("skip resubmitting transaction at fee cap", "context ID", txnToCheck.ContextID, "sender meta", s.getSenderMeta(), "hash", tx.Hash().String(), "nonce", tx.Nonce())
+			} else if err != nil {
 				s.metrics.resubmitTransactionFailedTotal.WithLabelValues(s.service, s.name).Inc()
 				log.Error("failed to resubmit transaction", "context ID", txnToCheck.ContextID, "sender meta", s.getSenderMeta(), "from", s.auth.From.String(), "nonce", tx.Nonce(), "err", err)
 			} else {
@@ -8,17 +8,24 @@ import (
 )
 
 type senderMetrics struct {
-	senderCheckPendingTransactionTotal *prometheus.CounterVec
-	sendTransactionTotal               *prometheus.CounterVec
-	sendTransactionFailureGetFee       *prometheus.CounterVec
-	sendTransactionFailureSendTx       *prometheus.CounterVec
-	resubmitTransactionTotal           *prometheus.CounterVec
-	resubmitTransactionFailedTotal     *prometheus.CounterVec
-	currentGasFeeCap                   *prometheus.GaugeVec
-	currentGasTipCap                   *prometheus.GaugeVec
-	currentGasPrice                    *prometheus.GaugeVec
-	currentBlobGasFeeCap               *prometheus.GaugeVec
-	currentGasLimit                    *prometheus.GaugeVec
+	senderCheckPendingTransactionTotal    *prometheus.CounterVec
+	sendTransactionTotal                  *prometheus.CounterVec
+	sendTransactionFailureGetFee          *prometheus.CounterVec
+	sendTransactionFailureSendTx          *prometheus.CounterVec
+	sendTransactionFailureSimulate        *prometheus.CounterVec
+	sendTransactionFailureBalance         *prometheus.CounterVec
+	feeStrategyFallbackTotal              *prometheus.CounterVec
+	resubmitTransactionTotal              *prometheus.CounterVec
+	resubmitTransactionFailedTotal        *prometheus.CounterVec
+	resubmitTransactionFeeCapReachedTotal *prometheus.CounterVec
+	reconcileTransactionTotal             *prometheus.CounterVec
+	exportTransactionTotal                *prometheus.CounterVec
+	currentGasFeeCap                      *prometheus.GaugeVec
+	currentGasTipCap                      *prometheus.GaugeVec
+	currentGasPrice                       *prometheus.GaugeVec
+	currentBlobGasFeeCap                  *prometheus.GaugeVec
+	currentGasLimit                       *prometheus.GaugeVec
+	transactionConfirmationDuration       *prometheus.HistogramVec
 }
 
 var (
@@ -41,6 +48,18 @@ func initSenderMetrics(reg prometheus.Registerer) *senderMetrics {
 				Name: "rollup_sender_send_transaction_send_tx_failure_total",
 				Help: "The total number of sending transactions failure for sending tx.",
 			}, []string{"service", "name"}),
+			sendTransactionFailureSimulate: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_send_transaction_simulate_failure_total",
+				Help: "The total number of transactions not sent because their simulation failed.",
+			}, []string{"service", "name"}),
+			sendTransactionFailureBalance: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_send_transaction_insufficient_balance_total",
+				Help: "The total number of transactions not sent because the account balance can't cover their cost.",
+			}, []string{"service", "name"}),
+			feeStrategyFallbackTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_fee_strategy_fallback_total",
+				Help: "The total number of priority fees suggested by the node because the fee strategy failed.",
+			}, []string{"service", "name"}),
 			resubmitTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 				Name: "rollup_sender_send_transaction_resubmit_send_transaction_total",
 				Help: "The total number of resubmit transactions.",
@@ -49,6 +68,18 @@ func initSenderMetrics(reg prometheus.Registerer) *senderMetrics {
 				Name: "rollup_sender_send_transaction_resubmit_send_transaction_failed_total",
 				Help: "The total number of failed resubmit transactions.",
 			}, []string{"service", "name"}),
+			resubmitTransactionFeeCapReachedTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_send_transaction_resubmit_fee_cap_reached_total",
+				Help: "The total number of resubmissions skipped because the transaction fees reached the cap.",
+			}, []string{"service", "name"}),
+			exportTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_export_transaction_total",
+				Help: "The total number of transactions exported for an external submission by the dry-run mode.",
+			}, []string{"service", "name"}),
+			reconcileTransactionTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+				Name: "rollup_sender_reconcile_transaction_total",
+				Help: "The total number of pending transactions sent again on startup.",
+			}, []string{"service", "name"}),
 			currentGasFeeCap: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
 				Name: "rollup_sender_gas_fee_cap",
 				Help: "The gas fee cap of current transaction.",
@@ -73,6 +104,11 @@ func initSenderMetrics(reg prometheus.Registerer) *senderMetrics {
 				Name: "rollup_sender_check_pending_transaction_total",
 				Help: "The total number of check pending transaction.",
 			}, []string{"service", "name"}),
+			transactionConfirmationDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "rollup_sender_transaction_confirmation_duration_seconds",
+				Help:    "The time from sending a transaction to its confirmation.",
+				Buckets: prometheus.ExponentialBuckets(12, 2, 10),
+			}, []string{"service", "name"}),
 		}
 	})
 
@@ -0,0 +1,220 @@
+package sender
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto/kzg4844"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// RoundRobinAccountSelection picks the sender accounts of a pool in turn.
+const RoundRobinAccountSelection = "round_robin"
+
+// ErrNoAvailableSender is returned when every account of the pool is below the minimum balance.
+var ErrNoAvailableSender = errors.New("no sender account with enough balance")
+
+// Pool sends transactions from several accounts, each account is a Sender with its own nonce and balance.
+// The transactions of a pool are ordered, e.g. the commitBatch of a batch reverts if it's included before the
+// one of its parent, and the L1 only keeps the order of the transactions of an account. So the pool sends
+// from one account at a time: it keeps the account while it has unconfirmed transactions, and picks the next
+// account once they're all confirmed, skipping the accounts below the minimum balance.
+type Pool struct {
+	ctx        context.Context
+	config     *config.SenderConfig
+	senders    []*Sender
+	accounts   []poolAccount
+	minBalance *big.Int
+
+	mu   sync.Mutex
+	next int
+
+	confirmCh chan *Confirmation
+	stopCh    chan struct{}
+}
+
+// NewPool returns a sender pool with one account per private key.
+func NewPool(ctx context.Context, config *config.SenderConfig, privs []*ecdsa.PrivateKey, service, name string, senderType types.SenderType, db *gorm.DB, reg prometheus.Registerer) (*Pool, error) {
+	signers := make([]Signer, 0, len(privs))
+	for _, priv := range privs {
+		signers = append(signers, NewPrivateKeySigner(priv))
+	}
+	return NewPoolWithSigners(ctx, config, signers, service, name, senderType, db, reg)
+}
+
+// NewPoolWithSigners returns a sender pool with one account per signer.
+func NewPoolWithSigners(ctx context.Context, config *config.SenderConfig, signers []Signer, service, name string, senderType types.SenderType, db *gorm.DB, reg prometheus.Registerer) (*Pool, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("sender pool needs at least one signer")
+	}
+	switch config.AccountSelection {
+	case "", RoundRobinAccountSelection:
+	default:
+		return nil, fmt.Errorf("unsupported account selection: %s", config.AccountSelection)
+	}
+
+	p := &Pool{
+		ctx:        ctx,
+		config:     config,
+		minBalance: new(big.Int).SetUint64(config.MinBalance),
+		confirmCh:  make(chan *Confirmation, 128),
+		stopCh:     make(chan struct{}),
+	}
+	for _, signer := range signers {
+		s, err := NewSenderWithSigner(ctx, config, signer, service, name, senderType, db, reg)
+		if err != nil {
+			p.stopSenders()
+			return nil, err
+		}
+		p.senders = append(p.senders, s)
+		p.accounts = append(p.accounts, s)
+	}
+
+	for _, s := range p.senders {
+		go p.forwardConfirmations(s)
+	}
+	return p, nil
+}
+
+func (p *Pool) forwardConfirmations(s *Sender) {
+	for {
+		select {
+		case cfm := <-s.ConfirmChan():
+			select {
+			case p.confirmCh <- cfm:
+			case <-p.stopCh:
+				return
+			}
+		case <-p.ctx.Done():
+			return
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// SendTransaction sends the transaction from the account of the unconfirmed transactions of the pool, or from
+// the account picked by the configured account selection if they're all confirmed.
+func (p *Pool) SendTransaction(contextID string, target *common.Address, data []byte, blob *kzg4844.Blob, fallbackGasLimit uint64) (common.Hash, error) {
+	// the pending transaction is stored before SendTransaction returns, so the next pick sees it.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i, err := p.pick()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return p.senders[i].SendTransaction(contextID, target, data, blob, fallbackGasLimit)
+}
+
+// Check checks the endpoint and the signers of all the accounts of the pool.
+func (p *Pool) Check(ctx context.Context) error {
+	for _, s := range p.senders {
+		if err := s.Check(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// poolAccount is the part of a Sender the pool picks its accounts by.
+type poolAccount interface {
+	GetAddress() common.Address
+	GetBalance(ctx context.Context) (*big.Int, error)
+	GetPendingCount(ctx context.Context) (int64, error)
+}
+
+// pick returns the index of the account to use, the caller holds the lock.
+func (p *Pool) pick() (int, error) {
+	busy := -1
+	for i, a := range p.accounts {
+		count, err := a.GetPendingCount(p.ctx)
+		if err != nil {
+			// an unknown count could break the order of the transactions.
+			return 0, fmt.Errorf("failed to get pending transaction count of sender %s: %w", a.GetAddress().String(), err)
+		}
+		if count == 0 {
+			continue
+		}
+		if busy >= 0 {
+			log.Warn("several sender accounts have unconfirmed transactions, keep the first one", "address", p.accounts[busy].GetAddress().String(), "other address", a.GetAddress().String())
+			continue
+		}
+		busy = i
+	}
+	if busy >= 0 {
+		if !p.hasMinBalance(p.accounts[busy]) {
+			// the account can't be switched before its transactions are confirmed.
+			return 0, ErrNoAvailableSender
+		}
+		return busy, nil
+	}
+
+	for j := range p.accounts {
+		i := (p.next + j) % len(p.accounts)
+		if p.hasMinBalance(p.accounts[i]) {
+			p.next = (i + 1) % len(p.accounts)
+			return i, nil
+		}
+	}
+	return 0, ErrNoAvailableSender
+}
+
+func (p *Pool) hasMinBalance(a poolAccount) bool {
+	if p.minBalance.Sign() <= 0 {
+		return true
+	}
+	balance, err := a.GetBalance(p.ctx)
+	if err != nil {
+		log.Warn("failed to get sender balance", "address", a.GetAddress().String(), "err", err)
+		return false
+	}
+	if balance.Cmp(p.minBalance) < 0 {
+		log.Warn("sender balance below minimum, skip it", "address", a.GetAddress().String(), "balance", balance.String(), "min balance", p.minBalance.String())
+		return false
+	}
+	return true
+}
+
+// GetChainID returns the chain ID associated with the pool.
+func (p *Pool) GetChainID() *big.Int {
+	return p.senders[0].GetChainID()
+}
+
+// GetBaseFee returns the base fee of the latest block, the accounts of the pool share the chain.
+func (p *Pool) GetBaseFee(ctx context.Context) (uint64, error) {
+	return p.senders[0].GetBaseFee(ctx)
+}
+
+// ConfirmChan channel of the confirmations of all the accounts in the pool
+func (p *Pool) ConfirmChan() <-chan *Confirmation {
+	return p.confirmCh
+}
+
+// SendConfirmation sends a confirmation to the confirmation channel.
+// Note: This function is only used in tests.
+func (p *Pool) SendConfirmation(cfm *Confirmation) {
+	p.confirmCh <- cfm
+}
+
+// Stop stop the pool and all its senders.
+func (p *Pool) Stop() {
+	close(p.stopCh)
+	p.stopSenders()
+}
+
+func (p *Pool) stopSenders() {
+	for _, s := range p.senders {
+		s.Stop()
+	}
+}
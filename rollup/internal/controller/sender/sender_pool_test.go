@@ -0,0 +1,83 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+type mockPoolAccount struct {
+	address  common.Address
+	balance  *big.Int
+	pending  int64
+	countErr error
+}
+
+func (a *mockPoolAccount) GetAddress() common.Address {
+	return a.address
+}
+
+func (a *mockPoolAccount) GetBalance(context.Context) (*big.Int, error) {
+	return a.balance, nil
+}
+
+func (a *mockPoolAccount) GetPendingCount(context.Context) (int64, error) {
+	return a.pending, a.countErr
+}
+
+func TestPoolPickKeepsTransactionOrder(t *testing.T) {
+	accounts := []*mockPoolAccount{
+		{address: common.HexToAddress("0x01"), balance: big.NewInt(100)},
+		{address: common.HexToAddress("0x02"), balance: big.NewInt(100)},
+		{address: common.HexToAddress("0x03"), balance: big.NewInt(100)},
+	}
+	p := &Pool{ctx: context.Background(), config: &config.SenderConfig{}, minBalance: big.NewInt(10)}
+	for _, a := range accounts {
+		p.accounts = append(p.accounts, a)
+	}
+	// send picks an account and records the unconfirmed transaction, like SendTransaction.
+	send := func() int {
+		i, err := p.pick()
+		assert.NoError(t, err)
+		accounts[i].pending++
+		return i
+	}
+
+	// the ordered transactions are all sent from one account while the previous ones are unconfirmed.
+	assert.Equal(t, 0, send())
+	assert.Equal(t, 0, send())
+	assert.Equal(t, 0, send())
+
+	// the next account is only picked once they're all confirmed.
+	accounts[0].pending = 0
+	assert.Equal(t, 1, send())
+	accounts[1].pending = 0
+
+	// an account below the minimum balance is skipped when picking the next account.
+	accounts[2].balance = big.NewInt(1)
+	assert.Equal(t, 0, send())
+
+	// but not switched while it has unconfirmed transactions.
+	accounts[0].balance = big.NewInt(1)
+	_, err := p.pick()
+	assert.ErrorIs(t, err, ErrNoAvailableSender)
+	accounts[0].pending = 0
+	assert.Equal(t, 1, send())
+
+	// an account with unconfirmed transactions before a restart is kept.
+	accounts[1].pending = 0
+	accounts[2].balance = big.NewInt(100)
+	accounts[2].pending = 1
+	assert.Equal(t, 2, send())
+
+	// an unknown pending count stops the pool.
+	accounts[0].countErr = errors.New("db error")
+	_, err = p.pick()
+	assert.Error(t, err)
+}
@@ -4,10 +4,17 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -124,10 +131,12 @@ func TestSender(t *testing.T) {
 	t.Run("test new sender", testNewSender)
 	t.Run("test send and retrieve transaction", testSendAndRetrieveTransaction)
 	t.Run("test fallback gas limit", testFallbackGasLimit)
+	t.Run("test simulate transaction", testSimulateTransaction)
 	t.Run("test access list transaction gas limit", testAccessListTransactionGasLimit)
 	t.Run("test resubmit zero gas price transaction", testResubmitZeroGasPriceTransaction)
 	t.Run("test resubmit non-zero gas price transaction", testResubmitNonZeroGasPriceTransaction)
 	t.Run("test resubmit under priced transaction", testResubmitUnderpricedTransaction)
+	t.Run("test resubmit transaction at fee cap", testResubmitTransactionAtFeeCap)
 	t.Run("test resubmit dynamic fee transaction with rising base fee", testResubmitDynamicFeeTransactionWithRisingBaseFee)
 	t.Run("test resubmit blob transaction with rising base fee and blob base fee", testResubmitBlobTransactionWithRisingBaseFeeAndBlobBaseFee)
 	t.Run("test check pending transaction tx confirmed", testCheckPendingTransactionTxConfirmed)
@@ -135,6 +144,7 @@ func TestSender(t *testing.T) {
 	t.Run("test check pending transaction replaced tx confirmed", testCheckPendingTransactionReplacedTxConfirmed)
 	t.Run("test check pending transaction multiple times with only one transaction pending", testCheckPendingTransactionTxMultipleTimesWithOnlyOneTxPending)
 	t.Run("test blob transaction with blobhash op contract call", testBlobTransactionWithBlobhashOpContractCall)
+	t.Run("test reconcile pending transactions on restart", testReconcilePendingTransactionsOnRestart)
 }
 
 func testNewSender(t *testing.T) {
@@ -248,6 +258,58 @@ func testFallbackGasLimit(t *testing.T) {
 	}
 }
 
+func testSimulateTransaction(t *testing.T) {
+	for i, txType := range txTypes {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L2Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		cfgCopy.SimulateTx = true
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+		assert.NoError(t, err)
+
+		// the test contract has no such method, the transaction reverts even with a fallback gas limit.
+		nonce := s.auth.Nonce.Uint64()
+		_, err = s.SendTransaction("0", &testContractsAddress, []byte{0xde, 0xad, 0xbe, 0xef}, txBlob[i], 100000)
+		var simulationErr *SimulationError
+		assert.ErrorAs(t, err, &simulationErr)
+		assert.NotEmpty(t, simulationErr.RevertReason)
+		assert.Equal(t, nonce, s.auth.Nonce.Uint64())
+
+		txs, err := s.pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderType(context.Background(), s.senderType, 100)
+		assert.NoError(t, err)
+		assert.Empty(t, txs)
+
+		// a transaction passing the simulation is sent.
+		_, err = s.SendTransaction("1", &common.Address{}, nil, txBlob[i], 0)
+		assert.NoError(t, err)
+		assert.Equal(t, nonce+1, s.auth.Nonce.Uint64())
+
+		s.Stop()
+	}
+}
+
+func TestDecodeRevertData(t *testing.T) {
+	errorData := append(common.FromHex("0x08c379a0"), common.LeftPadBytes([]byte{0x20}, 32)...)
+	errorData = append(errorData, common.LeftPadBytes([]byte{0x0f}, 32)...)
+	errorData = append(errorData, common.RightPadBytes([]byte("Batch is proved"), 32)...)
+	assert.Equal(t, "Batch is proved", decodeRevertData(errorData))
+
+	panicData := append(common.FromHex("0x4e487b71"), common.LeftPadBytes([]byte{0x11}, 32)...)
+	assert.Equal(t, "panic code 0x11", decodeRevertData(panicData))
+
+	customData := append(common.FromHex("0x12345678"), common.LeftPadBytes([]byte{0x01}, 32)...)
+	assert.Equal(t, "custom error 0x12345678, data 0x0000000000000000000000000000000000000000000000000000000000000001", decodeRevertData(customData))
+
+	assert.Equal(t, "unknown revert data 0x01", decodeRevertData([]byte{0x01}))
+
+	// the custom errors of the rollup contract are decoded by name.
+	scrollChainErrorData := bridgeAbi.ScrollChainABI.Errors["ErrorIncorrectPreviousStateRoot"].ID.Bytes()[:4]
+	assert.Equal(t, "ErrorIncorrectPreviousStateRoot", decodeRevertData(scrollChainErrorData))
+}
+
 func testResubmitZeroGasPriceTransaction(t *testing.T) {
 	for i, txType := range txTypes {
 		if txBlob[i] != nil {
@@ -418,6 +480,37 @@ func testResubmitUnderpricedTransaction(t *testing.T) {
 	}
 }
 
+func testResubmitTransactionAtFeeCap(t *testing.T) {
+	for i, txType := range txTypes {
+		if txBlob[i] != nil {
+			continue
+		}
+
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L2Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		cfgCopy.MaxGasPrice = 1000000000
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeUnknown, db, nil)
+		assert.NoError(t, err)
+		feeData := &FeeData{
+			gasPrice:  big.NewInt(1000000000),
+			gasTipCap: big.NewInt(1000000000),
+			gasFeeCap: big.NewInt(1000000000),
+			gasLimit:  50000,
+		}
+		tx, err := s.createAndSendTx(feeData, &common.Address{}, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, tx)
+		_, err = s.resubmitTransaction(tx, 0, 0)
+		assert.ErrorIs(t, err, ErrFeeCapReached)
+
+		s.Stop()
+	}
+}
+
 func testResubmitDynamicFeeTransactionWithRisingBaseFee(t *testing.T) {
 	sqlDB, err := db.DB()
 	assert.NoError(t, err)
@@ -818,3 +911,144 @@ func randFieldElement() [32]byte {
 
 	return gokzg4844.SerializeScalar(r)
 }
+
+func TestKMSSigners(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	spki, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1},
+			Parameters: asn1.RawValue{FullBytes: []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}},
+		},
+		PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 8 * 65},
+	})
+	assert.NoError(t, err)
+
+	// signDER signs like a KMS, returning the DER signature with the higher s half of the time.
+	signDER := func(digest []byte) []byte {
+		sig, signErr := crypto.Sign(digest, key)
+		assert.NoError(t, signErr)
+		s := new(big.Int).SetBytes(sig[32:64])
+		if digest[0]%2 == 0 {
+			s.Sub(crypto.S256().Params().N, s)
+		}
+		der, marshalErr := asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(sig[:32]), s})
+		assert.NoError(t, marshalErr)
+		return der
+	}
+
+	awsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256")
+		var req struct {
+			KeyId   string
+			Message []byte
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "alias/commit-sender", req.KeyId)
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string][]byte{"PublicKey": spki}))
+		case "TrentService.Sign":
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string][]byte{"Signature": signDER(req.Message)}))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer awsServer.Close()
+
+	keyVersionName := "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+	gcpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 3600}))
+		case "/v1/" + keyVersionName + "/publicKey":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki})
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{"pem": string(pemKey)}))
+		case "/v1/" + keyVersionName + ":asymmetricSign":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			var req struct {
+				Digest struct {
+					Sha256 []byte `json:"sha256"`
+				} `json:"digest"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.NoError(t, json.NewEncoder(w).Encode(map[string][]byte{"signature": signDER(req.Digest.Sha256)}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gcpServer.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(gcpServer.URL, "http://"))
+
+	signers := make([]Signer, 0, 2)
+	for _, cfg := range []*config.SignerConfig{
+		{Backend: AWSKMSSignerBackend, AWSKMS: &config.AWSKMSConfig{KeyID: "alias/commit-sender", Region: "us-east-1", Endpoint: awsServer.URL}},
+		{Backend: GCPKMSSignerBackend, GCPKMS: &config.GCPKMSConfig{KeyVersionName: keyVersionName, Endpoint: gcpServer.URL}},
+	} {
+		signer, signerErr := NewSigner(context.Background(), cfg)
+		assert.NoError(t, signerErr)
+		assert.Equal(t, address, signer.Address())
+		signers = append(signers, signer)
+	}
+
+	chainID := big.NewInt(1)
+	txSigner := gethTypes.LatestSignerForChainID(chainID)
+	for _, signer := range signers {
+		for nonce := uint64(0); nonce < 8; nonce++ {
+			tx := gethTypes.NewTx(&gethTypes.DynamicFeeTx{ChainID: chainID, Nonce: nonce, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), To: &common.Address{}})
+			signedTx, signErr := signer.SignTx(context.Background(), tx, chainID)
+			assert.NoError(t, signErr)
+			from, senderErr := gethTypes.Sender(txSigner, signedTx)
+			assert.NoError(t, senderErr)
+			assert.Equal(t, address, from)
+		}
+	}
+
+	_, err = NewSigners(context.Background(), key, nil, []*ecdsa.PrivateKey{key}, nil)
+	assert.ErrorContains(t, err, "duplicated sender address")
+}
+
+func testReconcilePendingTransactionsOnRestart(t *testing.T) {
+	for i, txType := range txTypes {
+		sqlDB, err := db.DB()
+		assert.NoError(t, err)
+		assert.NoError(t, migrate.ResetDB(sqlDB))
+
+		cfgCopy := *cfg.L2Config.RelayerConfig.SenderConfig
+		cfgCopy.TxType = txType
+		s, err := NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+		assert.NoError(t, err)
+
+		// the node drops the transaction, as if it restarted together with the sender.
+		patchGuard := gomonkey.ApplyMethodFunc(s.client, "SendTransaction", func(_ context.Context, _ *gethTypes.Transaction) error {
+			return nil
+		})
+		hash, err := s.SendTransaction("test", &common.Address{}, nil, txBlob[i], 0)
+		assert.NoError(t, err)
+		nonce := s.auth.Nonce.Uint64()
+		s.Stop()
+		patchGuard.Reset()
+
+		// the restarted sender sends the transaction again and continues after its nonce.
+		s, err = NewSender(context.Background(), &cfgCopy, privateKey, "test", "test", types.SenderTypeCommitBatch, db, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, nonce, s.auth.Nonce.Uint64())
+
+		assert.Eventually(t, func() bool {
+			receipt, err := s.client.TransactionReceipt(context.Background(), hash)
+			return err == nil && receipt.Status == gethTypes.ReceiptStatusSuccessful
+		}, 30*time.Second, time.Second)
+
+		s.Stop()
+	}
+}
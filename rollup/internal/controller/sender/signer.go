@@ -0,0 +1,216 @@
+package sender
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	// AWSKMSSignerBackend signs the transactions with a key in AWS KMS.
+	AWSKMSSignerBackend = "aws_kms"
+
+	// GCPKMSSignerBackend signs the transactions with a key in GCP Cloud KMS.
+	GCPKMSSignerBackend = "gcp_kms"
+
+	// RemoteSignerBackend signs the transactions with a remote clef signer.
+	RemoteSignerBackend = "remote_signer"
+)
+
+// signerRequestTimeout is the timeout of the requests to the KMS.
+const signerRequestTimeout = 10 * time.Second
+
+// secp256k1HalfN is half the order of the secp256k1 curve, the signatures with a greater s are not accepted by ethereum.
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// Signer signs the transactions of a sender account.
+type Signer interface {
+	// Address returns the address of the account.
+	Address() common.Address
+	// SignTx returns the transaction signed for the chain.
+	SignTx(ctx context.Context, tx *gethTypes.Transaction, chainID *big.Int) (*gethTypes.Transaction, error)
+}
+
+// NewSigner creates the signer of the configured backend.
+func NewSigner(ctx context.Context, cfg *config.SignerConfig) (Signer, error) {
+	switch cfg.Backend {
+	case AWSKMSSignerBackend:
+		if cfg.AWSKMS == nil {
+			return nil, errors.New("missing aws_kms signer config")
+		}
+		return newAWSKMSSigner(ctx, cfg.AWSKMS)
+	case GCPKMSSignerBackend:
+		if cfg.GCPKMS == nil {
+			return nil, errors.New("missing gcp_kms signer config")
+		}
+		return newGCPKMSSigner(ctx, cfg.GCPKMS)
+	case RemoteSignerBackend:
+		if cfg.RemoteSigner == nil {
+			return nil, errors.New("missing remote_signer signer config")
+		}
+		return newRemoteSigner(ctx, cfg.RemoteSigner)
+	default:
+		return nil, fmt.Errorf("unsupported signer backend: %s", cfg.Backend)
+	}
+}
+
+// NewAccountSigner creates the signer of a sender account, the signer config replaces the private key if set.
+func NewAccountSigner(ctx context.Context, privKey *ecdsa.PrivateKey, signerCfg *config.SignerConfig) (Signer, error) {
+	if signerCfg != nil {
+		return NewSigner(ctx, signerCfg)
+	}
+	if privKey == nil {
+		return nil, errors.New("missing private key or signer config")
+	}
+	return NewPrivateKeySigner(privKey), nil
+}
+
+// NewSigners creates the signers of a sender pool, the first account is privKey or signerCfg,
+// followed by the additional private keys and signers.
+func NewSigners(ctx context.Context, privKey *ecdsa.PrivateKey, signerCfg *config.SignerConfig, privKeys []*ecdsa.PrivateKey, signerCfgs []*config.SignerConfig) ([]Signer, error) {
+	signer, err := NewAccountSigner(ctx, privKey, signerCfg)
+	if err != nil {
+		return nil, err
+	}
+	signers := []Signer{signer}
+
+	for _, privKey := range privKeys {
+		signers = append(signers, NewPrivateKeySigner(privKey))
+	}
+	for _, signerCfg := range signerCfgs {
+		signer, err := NewSigner(ctx, signerCfg)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+
+	seen := make(map[common.Address]struct{}, len(signers))
+	for _, signer := range signers {
+		if _, exists := seen[signer.Address()]; exists {
+			return nil, fmt.Errorf("detected duplicated sender address: %s", signer.Address().Hex())
+		}
+		seen[signer.Address()] = struct{}{}
+	}
+	return signers, nil
+}
+
+// privateKeySigner signs the transactions with a private key loaded from the config file.
+type privateKeySigner struct {
+	privKey *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner creates a signer holding the private key in memory.
+func NewPrivateKeySigner(privKey *ecdsa.PrivateKey) Signer {
+	return &privateKeySigner{privKey: privKey, address: crypto.PubkeyToAddress(privKey.PublicKey)}
+}
+
+func (s *privateKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *privateKeySigner) SignTx(_ context.Context, tx *gethTypes.Transaction, chainID *big.Int) (*gethTypes.Transaction, error) {
+	return gethTypes.SignTx(tx, gethTypes.LatestSignerForChainID(chainID), s.privKey)
+}
+
+// digestSigner signs a digest with a KMS key, returning the DER encoded ECDSA signature.
+type digestSigner func(ctx context.Context, digest []byte) ([]byte, error)
+
+// signTxWithDigestSigner signs the transaction hash with a KMS key, and turns the DER signature into
+// the [R || S || V] signature of ethereum, V being recovered by matching the address of the key.
+func signTxWithDigestSigner(ctx context.Context, tx *gethTypes.Transaction, chainID *big.Int, address common.Address, sign digestSigner) (*gethTypes.Transaction, error) {
+	txSigner := gethTypes.LatestSignerForChainID(chainID)
+	digest := txSigner.Hash(tx).Bytes()
+
+	der, err := sign(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := ethereumSignature(digest, der, address)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(txSigner, sig)
+}
+
+// ethereumSignature converts a DER encoded ECDSA signature of the digest into the [R || S || V] format.
+func ethereumSignature(digest, der []byte, address common.Address) ([]byte, error) {
+	var rs struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(der, &rs); err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("invalid DER signature: %x", der)
+	}
+
+	// ethereum only accepts the signatures with the lower s, the symmetric one is equally valid.
+	if rs.S.Cmp(secp256k1HalfN) > 0 {
+		rs.S = new(big.Int).Sub(crypto.S256().Params().N, rs.S)
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	rs.R.FillBytes(sig[0:32])
+	rs.S.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pubKey, err := crypto.SigToPub(digest, sig)
+		if err == nil && crypto.PubkeyToAddress(*pubKey) == address {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("signature doesn't recover to the signer address %s", address.Hex())
+}
+
+// addressFromPKIXPublicKey returns the address of a DER encoded SubjectPublicKeyInfo secp256k1 public key,
+// which x509.ParsePKIXPublicKey doesn't support.
+func addressFromPKIXPublicKey(der []byte) (common.Address, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil || len(rest) != 0 {
+		return common.Address{}, errors.New("invalid DER public key")
+	}
+	pubKey, err := crypto.UnmarshalPubkey(spki.PublicKey.Bytes)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// doSignerRequest sends a request to the KMS and decodes its json response into result.
+func doSignerRequest(client *http.Client, req *http.Request, result interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Warn("failed to close signer response body", "err", closeErr)
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s, body: %s", resp.Status, body)
+	}
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,109 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// awsKMSSigner signs the transactions with an ECC_SECG_P256K1 key of AWS KMS, calling the KMS json api.
+type awsKMSSigner struct {
+	cfg         *config.AWSKMSConfig
+	endpoint    string
+	credentials aws.CredentialsProvider
+	v4Signer    *v4.Signer
+	client      *http.Client
+	address     common.Address
+}
+
+func newAWSKMSSigner(ctx context.Context, cfg *config.AWSKMSConfig) (*awsKMSSigner, error) {
+	awsCfg, err := awsConfig.LoadDefaultConfig(ctx, awsConfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config, err: %w", err)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", cfg.Region)
+	}
+	s := &awsKMSSigner{
+		cfg:         cfg,
+		endpoint:    endpoint,
+		credentials: awsCfg.Credentials,
+		v4Signer:    v4.NewSigner(),
+		client:      &http.Client{Timeout: signerRequestTimeout},
+	}
+
+	var publicKey struct {
+		PublicKey []byte `json:"PublicKey"`
+	}
+	if err = s.call(ctx, "GetPublicKey", map[string]interface{}{"KeyId": cfg.KeyID}, &publicKey); err != nil {
+		return nil, fmt.Errorf("failed to get the public key of aws kms key %s, err: %w", cfg.KeyID, err)
+	}
+	if s.address, err = addressFromPKIXPublicKey(publicKey.PublicKey); err != nil {
+		return nil, fmt.Errorf("failed to parse the public key of aws kms key %s, err: %w", cfg.KeyID, err)
+	}
+	return s, nil
+}
+
+func (s *awsKMSSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *awsKMSSigner) SignTx(ctx context.Context, tx *gethTypes.Transaction, chainID *big.Int) (*gethTypes.Transaction, error) {
+	return signTxWithDigestSigner(ctx, tx, chainID, s.address, s.signDigest)
+}
+
+func (s *awsKMSSigner) signDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	var signature struct {
+		Signature []byte `json:"Signature"`
+	}
+	params := map[string]interface{}{
+		"KeyId":            s.cfg.KeyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}
+	if err := s.call(ctx, "Sign", params, &signature); err != nil {
+		return nil, fmt.Errorf("failed to sign with aws kms key %s, err: %w", s.cfg.KeyID, err)
+	}
+	return signature.Signature, nil
+}
+
+// call sends a SigV4 signed request to the KMS json api, the []byte params and results are base64 encoded as expected.
+func (s *awsKMSSigner) call(ctx context.Context, operation string, params, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+operation)
+
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve aws credentials, err: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err = s.v4Signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), "kms", s.cfg.Region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign aws request, err: %w", err)
+	}
+	return doSignerRequest(s.client, req, result)
+}
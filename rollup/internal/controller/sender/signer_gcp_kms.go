@@ -0,0 +1,148 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	defaultGCPKMSEndpoint = "https://cloudkms.googleapis.com"
+
+	// defaultGCPMetadataHost is the metadata server of GCE and GKE, it's overridden by the GCE_METADATA_HOST env
+	// like in the google cloud client libraries.
+	defaultGCPMetadataHost = "metadata.google.internal"
+)
+
+// gcpKMSSigner signs the transactions with an EC_SIGN_SECP256K1_SHA256 key version of GCP Cloud KMS, calling the KMS rest api.
+type gcpKMSSigner struct {
+	cfg      *config.GCPKMSConfig
+	endpoint string
+	client   *http.Client
+	address  common.Address
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func newGCPKMSSigner(ctx context.Context, cfg *config.GCPKMSConfig) (*gcpKMSSigner, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGCPKMSEndpoint
+	}
+	s := &gcpKMSSigner{
+		cfg:      cfg,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: signerRequestTimeout},
+	}
+
+	var publicKey struct {
+		Pem string `json:"pem"`
+	}
+	if err := s.call(ctx, http.MethodGet, "/publicKey", nil, &publicKey); err != nil {
+		return nil, fmt.Errorf("failed to get the public key of gcp kms key %s, err: %w", cfg.KeyVersionName, err)
+	}
+	block, _ := pem.Decode([]byte(publicKey.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("invalid pem public key of gcp kms key %s", cfg.KeyVersionName)
+	}
+	address, err := addressFromPKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key of gcp kms key %s, err: %w", cfg.KeyVersionName, err)
+	}
+	s.address = address
+	return s, nil
+}
+
+func (s *gcpKMSSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *gcpKMSSigner) SignTx(ctx context.Context, tx *gethTypes.Transaction, chainID *big.Int) (*gethTypes.Transaction, error) {
+	return signTxWithDigestSigner(ctx, tx, chainID, s.address, s.signDigest)
+}
+
+func (s *gcpKMSSigner) signDigest(ctx context.Context, digest []byte) ([]byte, error) {
+	// the key version hashes with sha256, the keccak256 transaction hash is passed as the sha256 digest.
+	params := map[string]interface{}{
+		"digest": map[string][]byte{"sha256": digest},
+	}
+	var signature struct {
+		Signature []byte `json:"signature"`
+	}
+	if err := s.call(ctx, http.MethodPost, ":asymmetricSign", params, &signature); err != nil {
+		return nil, fmt.Errorf("failed to sign with gcp kms key %s, err: %w", s.cfg.KeyVersionName, err)
+	}
+	return signature.Signature, nil
+}
+
+// call sends a request about the key version to the KMS rest api.
+func (s *gcpKMSSigner) call(ctx context.Context, method, suffix string, params, result interface{}) error {
+	var body []byte
+	if params != nil {
+		var err error
+		if body, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+"/v1/"+s.cfg.KeyVersionName+suffix, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	return doSignerRequest(s.client, req, result)
+}
+
+// token returns the cached access token of the service account, refreshed from the metadata server a minute before it expires.
+func (s *gcpKMSSigner) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Add(time.Minute).Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = defaultGCPMetadataHost
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err = doSignerRequest(s.client, req, &token); err != nil {
+		return "", fmt.Errorf("failed to get gcp access token from the metadata server, err: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("empty gcp access token from the metadata server")
+	}
+	s.accessToken = token.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
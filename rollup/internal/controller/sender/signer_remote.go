@@ -0,0 +1,96 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/scroll-tech/go-ethereum/signer/core/apitypes"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// remoteSigner signs the transactions with the account_signTransaction method of a clef signer.
+type remoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+func newRemoteSigner(ctx context.Context, cfg *config.RemoteSignerConfig) (*remoteSigner, error) {
+	client, err := rpc.DialContext(ctx, cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial remote signer, err: %w", err)
+	}
+
+	var accounts []common.Address
+	if err = client.CallContext(ctx, &accounts, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list the accounts of remote signer, err: %w", err)
+	}
+	for _, account := range accounts {
+		if account == cfg.Address {
+			return &remoteSigner{client: client, address: cfg.Address}, nil
+		}
+	}
+	client.Close()
+	return nil, fmt.Errorf("account %s not found in remote signer", cfg.Address.Hex())
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *remoteSigner) SignTx(ctx context.Context, tx *gethTypes.Transaction, chainID *big.Int) (*gethTypes.Transaction, error) {
+	if tx.Type() == gethTypes.BlobTxType {
+		return nil, errors.New("remote signer doesn't support blob transactions")
+	}
+
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	if tx.Type() == gethTypes.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		accessList := tx.AccessList()
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		args.AccessList = &accessList
+	}
+
+	var result struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign transaction, err: %w", err)
+	}
+	signedTx := new(gethTypes.Transaction)
+	if err := signedTx.UnmarshalBinary(result.Raw); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction signed by remote signer, err: %w", err)
+	}
+
+	// make sure the signer signed the very transaction that was asked for.
+	txSigner := gethTypes.LatestSignerForChainID(chainID)
+	if txSigner.Hash(signedTx) != txSigner.Hash(tx) {
+		return nil, errors.New("remote signer returned a different transaction")
+	}
+	from, err := gethTypes.Sender(txSigner, signedTx)
+	if err != nil || from != s.address {
+		return nil, fmt.Errorf("remote signer returned a transaction not signed by %s", s.address.Hex())
+	}
+	return signedTx, nil
+}
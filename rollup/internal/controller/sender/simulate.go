@@ -0,0 +1,147 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+
+	bridgeAbi "scroll-tech/rollup/abi"
+)
+
+// panicSelector is the selector of the solidity Panic(uint256) revert data.
+var panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+// SimulationError is returned when the simulation of a transaction fails, the transaction is not sent.
+type SimulationError struct {
+	// RevertReason is the decoded revert reason, or the error message of the node if there is no revert data.
+	RevertReason string
+	Err          error
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("transaction simulation failed, revert reason: %s", e.RevertReason)
+}
+
+func (e *SimulationError) Unwrap() error {
+	return e.Err
+}
+
+// simulateTx runs the transaction with eth_call on the latest block, it returns a SimulationError if the
+// execution fails, so a transaction doomed to revert is not sent.
+func (s *Sender) simulateTx(feeData *FeeData, target *common.Address, data []byte, sidecar *gethTypes.BlobTxSidecar) error {
+	msg := ethereum.CallMsg{
+		From:       s.auth.From,
+		To:         target,
+		Gas:        feeData.gasLimit,
+		GasPrice:   feeData.gasPrice,
+		GasTipCap:  feeData.gasTipCap,
+		GasFeeCap:  feeData.gasFeeCap,
+		Data:       data,
+		AccessList: feeData.accessList,
+	}
+	if sidecar != nil {
+		msg.BlobHashes = sidecar.BlobHashes()
+		msg.BlobGasFeeCap = feeData.blobGasFeeCap
+	}
+
+	if _, err := s.client.CallContract(s.ctx, msg, nil); err != nil {
+		// only an error returned by the node is an execution failure, the other ones are transport errors.
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) {
+			return fmt.Errorf("failed to simulate transaction, err: %w", err)
+		}
+		return &SimulationError{RevertReason: decodeRevertReason(err), Err: err}
+	}
+	return nil
+}
+
+// decodeRevertReason decodes the revert data carried by the error of eth_call.
+func decodeRevertReason(err error) string {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error()
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error()
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil || len(data) == 0 {
+		return err.Error()
+	}
+	return decodeRevertData(data)
+}
+
+// decodeRevertData decodes the solidity Error(string) and Panic(uint256) revert data and the custom errors
+// of the rollup contract, the other custom errors are returned as their hex encoded selector and arguments.
+func decodeRevertData(data []byte) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if len(data) == 4+32 && bytes.Equal(data[:4], panicSelector) {
+		return fmt.Sprintf("panic code 0x%x", new(big.Int).SetBytes(data[4:]))
+	}
+	if len(data) < 4 {
+		return fmt.Sprintf("unknown revert data %s", hexutil.Encode(data))
+	}
+	for _, customErr := range bridgeAbi.ScrollChainABI.Errors {
+		if !bytes.Equal(data[:4], customErr.ID[:4]) {
+			continue
+		}
+		args, err := customErr.Inputs.Unpack(data[4:])
+		if err != nil {
+			break
+		}
+		if len(args) == 0 {
+			return customErr.Name
+		}
+		return fmt.Sprintf("%s%v", customErr.Name, args)
+	}
+	log.Debug("undecoded custom error", "data", hexutil.Encode(data))
+	return fmt.Sprintf("custom error %s, data %s", hexutil.Encode(data[:4]), hexutil.Encode(data[4:]))
+}
+
+// replayRevertReason replays a reverted transaction with eth_call on the state of the parent of its block and
+// returns the decoded revert reason. The transactions before it in the block are not replayed, so it returns an
+// empty string if the replay doesn't revert.
+func (s *Sender) replayRevertReason(tx *gethTypes.Transaction, blockNumber *big.Int) string {
+	msg := ethereum.CallMsg{
+		From:       s.auth.From,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	if tx.Type() == gethTypes.LegacyTxType {
+		msg.GasPrice = tx.GasPrice()
+	} else {
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	}
+	if tx.Type() == gethTypes.BlobTxType {
+		msg.BlobHashes = tx.BlobHashes()
+		msg.BlobGasFeeCap = tx.BlobGasFeeCap()
+	}
+
+	_, err := s.client.CallContract(s.ctx, msg, new(big.Int).Sub(blockNumber, big.NewInt(1)))
+	if err == nil {
+		return ""
+	}
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		log.Warn("failed to replay reverted transaction", "hash", tx.Hash().String(), "sender meta", s.getSenderMeta(), "err", err)
+		return ""
+	}
+	return decodeRevertReason(err)
+}
@@ -0,0 +1,228 @@
+package watcher
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const (
+	// BatchEventProposed the batch is proposed by the batch proposer.
+	BatchEventProposed = "proposed"
+	// BatchEventCommitted the commit transaction of the batch is confirmed on L1.
+	BatchEventCommitted = "committed"
+	// BatchEventProven the proof of the batch is verified by the coordinator.
+	BatchEventProven = "proven"
+	// BatchEventFinalized the finalize transaction of the batch is confirmed on L1.
+	BatchEventFinalized = "finalized"
+
+	batchNotifierCursorName          = "batch_notifier"
+	defaultBatchNotifierTimeoutSec   = 10
+	defaultBatchNotifierEventsPerRun = 100
+	batchNotifierSignatureHeader     = "X-Signature-256"
+)
+
+// BatchEvent the body of the batch lifecycle event posted to the webhooks.
+type BatchEvent struct {
+	// EventID is the id of the status transition, the events are delivered at least once so it dedups them.
+	EventID        uint64     `json:"event_id"`
+	Event          string     `json:"event"`
+	BatchIndex     uint64     `json:"batch_index"`
+	BatchHash      string     `json:"batch_hash"`
+	CommitTxHash   string     `json:"commit_tx_hash,omitempty"`
+	FinalizeTxHash string     `json:"finalize_tx_hash,omitempty"`
+	ProposedAt     time.Time  `json:"proposed_at"`
+	CommittedAt    *time.Time `json:"committed_at,omitempty"`
+	ProvedAt       *time.Time `json:"proved_at,omitempty"`
+	FinalizedAt    *time.Time `json:"finalized_at,omitempty"`
+	// ChangedAt is when the transition of the event happened.
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// BatchNotifier posts the batch lifecycle events to webhooks, so the downstream systems don't have to poll
+// the database. It follows the batch status transitions recorded in the status_audit_log table from a
+// persisted cursor, so the transitions made by any service are notified, and none is lost across restarts.
+type BatchNotifier struct {
+	ctx context.Context
+
+	batchOrm          *orm.Batch
+	statusAuditLogOrm *orm.StatusAuditLog
+	notifierCursorOrm *orm.NotifierCursor
+
+	cfg          *config.BatchNotifierConfig
+	client       *resty.Client
+	eventsPerRun int
+
+	batchNotifierEventTotal   *prometheus.CounterVec
+	batchNotifierFailureTotal prometheus.Counter
+}
+
+// NewBatchNotifier creates a new BatchNotifier instance.
+func NewBatchNotifier(ctx context.Context, cfg *config.BatchNotifierConfig, db *gorm.DB, reg prometheus.Registerer) (*BatchNotifier, error) {
+	if len(cfg.WebhookURLs) == 0 {
+		return nil, errors.New("batch notifier has no webhook url")
+	}
+
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+	if timeout == 0 {
+		timeout = defaultBatchNotifierTimeoutSec * time.Second
+	}
+	eventsPerRun := cfg.MaxEventsPerRun
+	if eventsPerRun <= 0 {
+		eventsPerRun = defaultBatchNotifierEventsPerRun
+	}
+
+	return &BatchNotifier{
+		ctx:               ctx,
+		batchOrm:          orm.NewBatch(db),
+		statusAuditLogOrm: orm.NewStatusAuditLog(db),
+		notifierCursorOrm: orm.NewNotifierCursor(db),
+		cfg:               cfg,
+		client:            resty.New().SetTimeout(timeout),
+		eventsPerRun:      eventsPerRun,
+
+		batchNotifierEventTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_batch_notifier_event_total",
+			Help: "Total number of batch lifecycle events delivered to the webhooks.",
+		}, []string{"event"}),
+		batchNotifierFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_batch_notifier_failure_total",
+			Help: "Total number of batch notifier runs stopped by a failure, the event is retried by the next run.",
+		}),
+	}, nil
+}
+
+// TryNotify delivers the events of the batch status transitions recorded since the cursor, and moves the cursor
+// past every delivered one. A failed delivery stops the run, the event is retried by the next run.
+func (n *BatchNotifier) TryNotify() {
+	lastID, err := n.notifierCursorOrm.GetLastID(n.ctx, batchNotifierCursorName)
+	if err != nil {
+		n.batchNotifierFailureTotal.Inc()
+		log.Error("failed to get batch notifier cursor", "err", err)
+		return
+	}
+	if lastID == nil {
+		// a new notifier starts from now, the history is in the database.
+		latestID, latestErr := n.statusAuditLogOrm.GetLatestID(n.ctx)
+		if latestErr != nil {
+			n.batchNotifierFailureTotal.Inc()
+			log.Error("failed to get latest status audit log id", "err", latestErr)
+			return
+		}
+		if err = n.notifierCursorOrm.UpdateLastID(n.ctx, batchNotifierCursorName, latestID); err != nil {
+			n.batchNotifierFailureTotal.Inc()
+			log.Error("failed to init batch notifier cursor", "err", err)
+		}
+		return
+	}
+
+	logs, err := n.statusAuditLogOrm.GetStatusAuditLogsAfterID(n.ctx, "batch", *lastID, n.eventsPerRun)
+	if err != nil {
+		n.batchNotifierFailureTotal.Inc()
+		log.Error("failed to get batch status transitions", "after id", *lastID, "err", err)
+		return
+	}
+
+	for _, auditLog := range logs {
+		if event := batchEventOf(auditLog); event != "" {
+			if err = n.notify(auditLog, event); err != nil {
+				n.batchNotifierFailureTotal.Inc()
+				log.Error("failed to notify batch event", "event", event, "batch hash", auditLog.RecordKey, "event id", auditLog.ID, "err", err)
+				return
+			}
+			n.batchNotifierEventTotal.WithLabelValues(event).Inc()
+		}
+		if err = n.notifierCursorOrm.UpdateLastID(n.ctx, batchNotifierCursorName, auditLog.ID); err != nil {
+			n.batchNotifierFailureTotal.Inc()
+			log.Error("failed to update batch notifier cursor", "last id", auditLog.ID, "err", err)
+			return
+		}
+	}
+}
+
+// batchEventOf returns the lifecycle event of the status transition, empty if it's not one.
+func batchEventOf(auditLog *orm.StatusAuditLog) string {
+	switch auditLog.StatusColumn {
+	case "rollup_status":
+		if auditLog.FromStatus == nil {
+			return BatchEventProposed
+		}
+		switch types.RollupStatus(auditLog.ToStatus) {
+		case types.RollupCommitted:
+			return BatchEventCommitted
+		case types.RollupFinalized:
+			return BatchEventFinalized
+		}
+	case "proving_status":
+		if auditLog.FromStatus != nil && types.ProvingStatus(auditLog.ToStatus) == types.ProvingTaskVerified {
+			return BatchEventProven
+		}
+	}
+	return ""
+}
+
+func (n *BatchNotifier) notify(auditLog *orm.StatusAuditLog, event string) error {
+	batch, err := n.batchOrm.GetBatchByHash(n.ctx, auditLog.RecordKey)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// the batch was deleted by an L2 reorg, its events are not delivered anymore.
+		log.Warn("skip the event of a deleted batch", "event", event, "batch hash", auditLog.RecordKey)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(&BatchEvent{
+		EventID:        auditLog.ID,
+		Event:          event,
+		BatchIndex:     batch.Index,
+		BatchHash:      batch.Hash,
+		CommitTxHash:   batch.CommitTxHash,
+		FinalizeTxHash: batch.FinalizeTxHash,
+		ProposedAt:     batch.CreatedAt,
+		CommittedAt:    batch.CommittedAt,
+		ProvedAt:       batch.ProvedAt,
+		FinalizedAt:    batch.FinalizedAt,
+		ChangedAt:      auditLog.ChangedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	var signature string
+	if n.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.cfg.Secret))
+		mac.Write(body)
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	for _, url := range n.cfg.WebhookURLs {
+		req := n.client.R().SetHeader("Content-Type", "application/json").SetBody(body)
+		if signature != "" {
+			req.SetHeader(batchNotifierSignatureHeader, signature)
+		}
+		resp, postErr := req.Post(url)
+		if postErr != nil {
+			return postErr
+		}
+		if resp.IsError() {
+			return fmt.Errorf("webhook %s status %s", url, resp.Status())
+		}
+	}
+	return nil
+}
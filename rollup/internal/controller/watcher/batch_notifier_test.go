@@ -0,0 +1,89 @@
+package watcher
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/testcontainers"
+	"scroll-tech/common/types"
+	"scroll-tech/database/migrate"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
+)
+
+func TestBatchNotifier(t *testing.T) {
+	apps := testcontainers.NewTestcontainerApps()
+	defer apps.Free()
+	assert.NoError(t, apps.StartPostgresContainer())
+	db, err := apps.GetGormDBClient()
+	assert.NoError(t, err)
+	defer database.CloseDB(db)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	var events []BatchEvent
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, readErr := io.ReadAll(r.Body)
+		assert.NoError(t, readErr)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Signature-256"))
+
+		var event BatchEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	notifier, err := NewBatchNotifier(context.Background(), &config.BatchNotifierConfig{WebhookURLs: []string{server.URL}, Secret: "secret"}, db, prometheus.NewRegistry())
+	assert.NoError(t, err)
+
+	// the first run only starts the cursor.
+	notifier.TryNotify()
+
+	block := readBlockFromJSON(t, "../../../testdata/blockTrace_02.json")
+	batchOrm := orm.NewBatch(db)
+	batch := &encoding.Batch{Chunks: []*encoding.Chunk{{Blocks: []*encoding.Block{block}}}}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+	assert.NoError(t, err)
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitting))
+	assert.NoError(t, batchOrm.UpdateCommitTxHashAndRollupStatus(context.Background(), dbBatch.Hash, "0x01", types.RollupCommitted))
+
+	// a failed delivery is retried by the next run.
+	fail = true
+	notifier.TryNotify()
+	assert.Empty(t, events)
+
+	fail = false
+	notifier.TryNotify()
+	assert.Len(t, events, 2)
+	assert.Equal(t, BatchEventProposed, events[0].Event)
+	assert.Equal(t, BatchEventCommitted, events[1].Event)
+	assert.Equal(t, dbBatch.Hash, events[1].BatchHash)
+	assert.Equal(t, "0x01", events[1].CommitTxHash)
+	assert.NotNil(t, events[1].CommittedAt)
+
+	// the delivered events are not delivered again.
+	notifier.TryNotify()
+	assert.Len(t, events, 2)
+}
@@ -10,11 +10,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/scroll-tech/da-codec/encoding"
 	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/params"
 	"gorm.io/gorm"
 
 	"scroll-tech/common/forks"
+	cutils "scroll-tech/common/utils"
 
 	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
@@ -26,15 +28,23 @@ type BatchProposer struct {
 	ctx context.Context
 	db  *gorm.DB
 
-	batchOrm   *orm.Batch
-	chunkOrm   *orm.Chunk
-	l2BlockOrm *orm.L2Block
+	batchOrm          *orm.Batch
+	chunkOrm          *orm.Chunk
+	l2BlockOrm        *orm.L2Block
+	pauseStateOrm     *orm.PauseState
+	l1MessageOrm      *orm.L1Message
+	skippedMessageOrm *orm.SkippedMessage
 
 	maxL1CommitGasPerBatch          uint64
 	maxL1CommitCalldataSizePerBatch uint64
 	batchTimeoutSec                 uint64
+	proposeWhenIdleSec              uint64
 	gasCostIncreaseMultiplier       float64
 	maxUncompressedBatchBytesSize   uint64
+	l1MessageInclusionDeadlineSec   uint64
+	maxUnprovenBatches              uint64
+	maxUnprovenChunks               uint64
+	gasThreshold                    *gasThresholdController
 	forkMap                         map[uint64]bool
 
 	chainCfg *params.ChainConfig
@@ -52,6 +62,14 @@ type BatchProposer struct {
 	batchEstimateGasTime               prometheus.Gauge
 	batchEstimateCalldataSizeTime      prometheus.Gauge
 	batchEstimateBlobSizeTime          prometheus.Gauge
+	batchBlocksNum                     prometheus.Histogram
+	batchL2Gas                         prometheus.Histogram
+	batchL1MessageDeadlineExceeded     prometheus.Counter
+	batchUnprovenBatches               prometheus.Gauge
+	batchUnprovenChunks                prometheus.Gauge
+	batchProvingBacklogPaused          prometheus.Gauge
+	batchProvingBacklogPausedTotal     prometheus.Counter
+	batchL1CommitGasThreshold          prometheus.Gauge
 }
 
 // NewBatchProposer creates a new BatchProposer instance.
@@ -61,8 +79,12 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 		"maxL1CommitGasPerBatch", cfg.MaxL1CommitGasPerBatch,
 		"maxL1CommitCalldataSizePerBatch", cfg.MaxL1CommitCalldataSizePerBatch,
 		"batchTimeoutSec", cfg.BatchTimeoutSec,
+		"proposeWhenIdleSec", cfg.ProposeWhenIdleSec,
 		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
 		"maxUncompressedBatchBytesSize", cfg.MaxUncompressedBatchBytesSize,
+		"l1MessageInclusionDeadlineSec", cfg.L1MessageInclusionDeadlineSec,
+		"maxUnprovenBatches", cfg.MaxUnprovenBatches,
+		"maxUnprovenChunks", cfg.MaxUnprovenChunks,
 		"forkHeights", forkHeights)
 
 	p := &BatchProposer{
@@ -71,11 +93,18 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 		batchOrm:                        orm.NewBatch(db),
 		chunkOrm:                        orm.NewChunk(db),
 		l2BlockOrm:                      orm.NewL2Block(db),
+		pauseStateOrm:                   orm.NewPauseState(db),
+		l1MessageOrm:                    orm.NewL1Message(db),
+		skippedMessageOrm:               orm.NewSkippedMessage(db),
 		maxL1CommitGasPerBatch:          cfg.MaxL1CommitGasPerBatch,
 		maxL1CommitCalldataSizePerBatch: cfg.MaxL1CommitCalldataSizePerBatch,
 		batchTimeoutSec:                 cfg.BatchTimeoutSec,
+		proposeWhenIdleSec:              cfg.ProposeWhenIdleSec,
 		gasCostIncreaseMultiplier:       cfg.GasCostIncreaseMultiplier,
 		maxUncompressedBatchBytesSize:   cfg.MaxUncompressedBatchBytesSize,
+		l1MessageInclusionDeadlineSec:   cfg.L1MessageInclusionDeadlineSec,
+		maxUnprovenBatches:              cfg.MaxUnprovenBatches,
+		maxUnprovenChunks:               cfg.MaxUnprovenChunks,
 		forkMap:                         forkMap,
 		chainCfg:                        chainCfg,
 
@@ -131,6 +160,44 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 			Name: "rollup_propose_batch_estimate_blob_size_time",
 			Help: "Time taken to estimate blob size for the chunk.",
 		}),
+		batchBlocksNum: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "rollup_propose_batch_blocks_number",
+			Help:    "The number of blocks in the proposed batches.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}),
+		batchL2Gas: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "rollup_propose_batch_l2_gas",
+			Help:    "The l2 gas used by the blocks in the proposed batches.",
+			Buckets: prometheus.ExponentialBuckets(1e6, 2, 12),
+		}),
+		batchL1MessageDeadlineExceeded: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_l1_message_deadline_exceeded_total",
+			Help: "Total number of batches refused for leaving out an L1 message past its inclusion deadline.",
+		}),
+		batchUnprovenBatches: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_unproven_batches",
+			Help: "The number of batches waiting for their proof, updated when the unproven backlog is capped.",
+		}),
+		batchUnprovenChunks: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_unproven_chunks",
+			Help: "The number of chunks waiting for their proof, updated when the unproven backlog is capped.",
+		}),
+		batchProvingBacklogPaused: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_proving_backlog_paused",
+			Help: "1 while the batch proposer is paused by the unproven backlog, 0 otherwise.",
+		}),
+		batchProvingBacklogPausedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_batch_proving_backlog_paused_total",
+			Help: "Total number of batch proposals skipped because the unproven backlog reached its cap.",
+		}),
+		batchL1CommitGasThreshold: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_batch_l1_commit_gas_threshold",
+			Help: "The l1 commit gas the batches are filled up to, scaled with the L1 base fee.",
+		}),
+	}
+
+	if cfg.DynamicGasThreshold != nil {
+		p.gasThreshold = newGasThresholdController(cfg.DynamicGasThreshold, cfg.MaxL1CommitGasPerBatch, db)
 	}
 
 	return p
@@ -138,6 +205,25 @@ func NewBatchProposer(ctx context.Context, cfg *config.BatchProposerConfig, chai
 
 // TryProposeBatch tries to propose a new batches.
 func (p *BatchProposer) TryProposeBatch() {
+	paused, err := p.pauseStateOrm.IsPaused(p.ctx, orm.PauseComponentBatchProposer)
+	if err != nil {
+		log.Error("failed to get pause state, skip proposing batch", "err", err)
+		return
+	}
+	if paused {
+		log.Debug("batch proposer paused by operator")
+		return
+	}
+
+	backlogged, err := p.provingBacklogReached()
+	if err != nil {
+		log.Error("failed to get the unproven backlog, skip proposing batch", "err", err)
+		return
+	}
+	if backlogged {
+		return
+	}
+
 	p.batchProposerCircleTotal.Inc()
 	if err := p.proposeBatch(); err != nil {
 		p.proposeBatchFailureTotal.Inc()
@@ -147,8 +233,18 @@ func (p *BatchProposer) TryProposeBatch() {
 }
 
 func (p *BatchProposer) updateDBBatchInfo(batch *encoding.Batch, codecVersion encoding.CodecVersion, metrics utils.BatchMetrics) error {
-	err := p.db.Transaction(func(dbTX *gorm.DB) error {
-		dbBatch, dbErr := p.batchOrm.InsertBatch(p.ctx, batch, codecVersion, metrics, dbTX)
+	p.proposeBatchUpdateInfoTotal.Inc()
+	skippedMessages, err := p.skippedL1Messages(batch)
+	if err != nil {
+		p.proposeBatchUpdateInfoFailureTotal.Inc()
+		log.Error("failed to get the l1 messages skipped by the batch", "index", batch.Index, "err", err)
+		return nil
+	}
+
+	var dbBatch *orm.Batch
+	err = p.db.Transaction(func(dbTX *gorm.DB) error {
+		var dbErr error
+		dbBatch, dbErr = p.batchOrm.InsertBatch(p.ctx, batch, codecVersion, metrics, dbTX)
 		if dbErr != nil {
 			log.Warn("BatchProposer.updateBatchInfoInDB insert batch failure", "index", batch.Index, "parent hash", batch.ParentBatchHash.Hex(), "error", dbErr)
 			return dbErr
@@ -157,12 +253,28 @@ func (p *BatchProposer) updateDBBatchInfo(batch *encoding.Batch, codecVersion en
 			log.Warn("BatchProposer.UpdateBatchHashInRange update the chunk's batch hash failure", "hash", dbBatch.Hash, "error", dbErr)
 			return dbErr
 		}
+		if dbErr = p.skippedMessageOrm.InsertSkippedMessages(p.ctx, skippedMessages, dbTX); dbErr != nil {
+			log.Warn("BatchProposer.InsertSkippedMessages insert the skipped l1 messages failure", "hash", dbBatch.Hash, "error", dbErr)
+			return dbErr
+		}
 		return nil
 	})
 	if err != nil {
 		p.proposeBatchUpdateInfoFailureTotal.Inc()
 		log.Error("update batch info in db failed", "err", err)
+		return nil
 	}
+	cutils.TaskLogger(dbBatch.Hash, "").Info("proposed batch", "index", dbBatch.Index, "start chunk", dbBatch.StartChunkIndex, "end chunk", dbBatch.EndChunkIndex)
+
+	var numBlocks, l2Gas uint64
+	for _, chunk := range batch.Chunks {
+		numBlocks += uint64(len(chunk.Blocks))
+		for _, block := range chunk.Blocks {
+			l2Gas += block.Header.GasUsed
+		}
+	}
+	p.batchBlocksNum.Observe(float64(numBlocks))
+	p.batchL2Gas.Observe(float64(l2Gas))
 	return nil
 }
 
@@ -179,16 +291,11 @@ func (p *BatchProposer) proposeBatch() error {
 
 	startBlockNum := new(big.Int).SetUint64(firstUnbatchedChunk.StartBlockNumber)
 
-	var codecVersion encoding.CodecVersion
-	var maxChunksThisBatch uint64
-	if !p.chainCfg.IsBernoulli(startBlockNum) {
-		codecVersion = encoding.CodecV0
-		maxChunksThisBatch = 15
-	} else if !p.chainCfg.IsCurie(startBlockNum) {
-		codecVersion = encoding.CodecV1
-		maxChunksThisBatch = 15
-	} else {
-		codecVersion = encoding.CodecV2
+	// the batches before and after a hard fork are encoded with the codec of their own fork, the chunks
+	// starting at a fork height are never batched with the earlier ones.
+	codecVersion := utils.CodecVersion(p.chainCfg, startBlockNum)
+	maxChunksThisBatch := uint64(15)
+	if codecVersion >= encoding.CodecV2 {
 		maxChunksThisBatch = 45
 	}
 
@@ -223,6 +330,8 @@ func (p *BatchProposer) proposeBatch() error {
 		return err
 	}
 
+	l1CommitGasThreshold := p.l1CommitGasThreshold()
+
 	var batch encoding.Batch
 	batch.Index = dbParentBatch.Index + 1
 	batch.ParentBatchHash = common.HexToHash(dbParentBatch.Hash)
@@ -238,8 +347,10 @@ func (p *BatchProposer) proposeBatch() error {
 		p.recordTimerBatchMetrics(metrics)
 
 		totalOverEstimateL1CommitGas := uint64(p.gasCostIncreaseMultiplier * float64(metrics.L1CommitGas))
-		if metrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerBatch || totalOverEstimateL1CommitGas > p.maxL1CommitGasPerBatch ||
-			metrics.L1CommitBlobSize > maxBlobSize || metrics.L1CommitUncompressedBatchBytesSize > p.maxUncompressedBatchBytesSize {
+		exceedsLimits := metrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerBatch || totalOverEstimateL1CommitGas > p.maxL1CommitGasPerBatch ||
+			metrics.L1CommitBlobSize > maxBlobSize || metrics.L1CommitUncompressedBatchBytesSize > p.maxUncompressedBatchBytesSize
+		// the first chunk is always taken, a batch over the gas threshold with a single chunk is proposed below.
+		if exceedsLimits || (i > 0 && totalOverEstimateL1CommitGas > l1CommitGasThreshold) {
 			if i == 0 {
 				// The first chunk exceeds hard limits, which indicates a bug in the chunk-proposer, manual fix is needed.
 				return fmt.Errorf("the first chunk exceeds limits; start block number: %v, end block number: %v, limits: %+v, maxChunkNum: %v, maxL1CommitCalldataSize: %v, maxL1CommitGas: %v, maxBlobSize: %v, maxUncompressedBatchBytesSize: %v",
@@ -252,6 +363,7 @@ func (p *BatchProposer) proposeBatch() error {
 				"l1CommitGas", metrics.L1CommitGas,
 				"overEstimateL1CommitGas", totalOverEstimateL1CommitGas,
 				"maxL1CommitGas", p.maxL1CommitGasPerBatch,
+				"l1CommitGasThreshold", l1CommitGasThreshold,
 				"l1CommitBlobSize", metrics.L1CommitBlobSize,
 				"maxBlobSize", maxBlobSize,
 				"L1CommitUncompressedBatchBytesSize", metrics.L1CommitUncompressedBatchBytesSize,
@@ -264,6 +376,9 @@ func (p *BatchProposer) proposeBatch() error {
 				return fmt.Errorf("failed to calculate batch metrics: %w", err)
 			}
 
+			if err := p.checkL1MessageInclusion(&batch); err != nil {
+				return err
+			}
 			p.recordAllBatchMetrics(metrics)
 			return p.updateDBBatchInfo(&batch, codecVersion, *metrics)
 		}
@@ -274,13 +389,22 @@ func (p *BatchProposer) proposeBatch() error {
 		return fmt.Errorf("failed to calculate batch metrics: %w", calcErr)
 	}
 	currentTimeSec := uint64(time.Now().Unix())
-	if metrics.FirstBlockTimestamp+p.batchTimeoutSec < currentTimeSec || metrics.NumChunks == maxChunksThisBatch {
-		log.Info("reached maximum number of chunks in batch or first block timeout",
+	// the pending chunks are idle once their last block is older than the window, e.g. on a low-traffic chain.
+	lastChunk := batch.Chunks[len(batch.Chunks)-1]
+	lastBlockTimestamp := lastChunk.Blocks[len(lastChunk.Blocks)-1].Header.Time
+	idle := p.proposeWhenIdleSec > 0 && lastBlockTimestamp+p.proposeWhenIdleSec < currentTimeSec
+	overGasThreshold := uint64(p.gasCostIncreaseMultiplier*float64(metrics.L1CommitGas)) > l1CommitGasThreshold
+	if metrics.FirstBlockTimestamp+p.batchTimeoutSec < currentTimeSec || metrics.NumChunks == maxChunksThisBatch || idle || overGasThreshold {
+		log.Info("reached maximum number of chunks in batch or first block timeout or idle timeout or gas threshold",
 			"chunk count", metrics.NumChunks,
 			"start block number", dbChunks[0].StartBlockNumber,
 			"start block timestamp", dbChunks[0].StartBlockTime,
+			"last block timestamp", lastBlockTimestamp,
 			"current time", currentTimeSec)
 
+		if err := p.checkL1MessageInclusion(&batch); err != nil {
+			return err
+		}
 		p.batchFirstBlockTimeoutReached.Inc()
 		p.recordAllBatchMetrics(metrics)
 		return p.updateDBBatchInfo(&batch, codecVersion, *metrics)
@@ -292,6 +416,117 @@ func (p *BatchProposer) proposeBatch() error {
 	return nil
 }
 
+// l1CommitGasThreshold returns the l1 commit gas the batch is filled up to, max_l1_commit_gas_per_batch unless
+// the dynamic gas threshold is enabled.
+func (p *BatchProposer) l1CommitGasThreshold() uint64 {
+	threshold := p.maxL1CommitGasPerBatch
+	if p.gasThreshold != nil {
+		var err error
+		if threshold, err = p.gasThreshold.update(p.ctx); err != nil {
+			log.Warn("failed to update the l1 commit gas threshold, keep the current one", "threshold", threshold, "err", err)
+		}
+	}
+	p.batchL1CommitGasThreshold.Set(float64(threshold))
+	return threshold
+}
+
+// provingBacklogReached tells whether the unproven batches or chunks reached their cap, no batch is proposed until
+// the provers catch up.
+func (p *BatchProposer) provingBacklogReached() (bool, error) {
+	if p.maxUnprovenBatches == 0 && p.maxUnprovenChunks == 0 {
+		return false, nil
+	}
+
+	unprovenBatches, err := p.batchOrm.CountUnprovenBatches(p.ctx)
+	if err != nil {
+		return false, err
+	}
+	unprovenChunks, err := p.chunkOrm.CountUnprovenChunks(p.ctx)
+	if err != nil {
+		return false, err
+	}
+	p.batchUnprovenBatches.Set(float64(unprovenBatches))
+	p.batchUnprovenChunks.Set(float64(unprovenChunks))
+
+	if (p.maxUnprovenBatches > 0 && unprovenBatches >= p.maxUnprovenBatches) ||
+		(p.maxUnprovenChunks > 0 && unprovenChunks >= p.maxUnprovenChunks) {
+		p.batchProvingBacklogPaused.Set(1)
+		p.batchProvingBacklogPausedTotal.Inc()
+		log.Warn("unproven backlog reached its cap, batch proposer paused",
+			"unproven batches", unprovenBatches, "max unproven batches", p.maxUnprovenBatches,
+			"unproven chunks", unprovenChunks, "max unproven chunks", p.maxUnprovenChunks)
+		return true, nil
+	}
+	p.batchProvingBacklogPaused.Set(0)
+	return false, nil
+}
+
+// checkL1MessageInclusion refuses the batch if the first L1 message it leaves out has been queued
+// for longer than the inclusion deadline, the L1 messages must be included in order.
+func (p *BatchProposer) checkL1MessageInclusion(batch *encoding.Batch) error {
+	if p.l1MessageInclusionDeadlineSec == 0 {
+		return nil
+	}
+
+	totalL1MessagePopped := batch.TotalL1MessagePoppedBefore
+	for _, chunk := range batch.Chunks {
+		totalL1MessagePopped += chunk.NumL1Messages(totalL1MessagePopped)
+	}
+
+	l1Message, err := p.l1MessageOrm.GetFirstL1MessageGEQueueIndex(p.ctx, totalL1MessagePopped)
+	if err != nil {
+		return err
+	}
+	if l1Message == nil {
+		return nil
+	}
+
+	deadline := l1Message.CreatedAt.Add(time.Duration(p.l1MessageInclusionDeadlineSec) * time.Second)
+	if now := cutils.NowUTC(); now.After(deadline) {
+		p.batchL1MessageDeadlineExceeded.Inc()
+		return fmt.Errorf("batch %v leaves out l1 message %v past its inclusion deadline, queued at: %v, deadline: %v",
+			batch.Index, l1Message.QueueIndex, l1Message.CreatedAt, deadline)
+	}
+	return nil
+}
+
+// skippedL1Messages returns the L1 messages skipped by the sequencer in the blocks of the batch, the gaps between
+// the queue indices of the included ones.
+func (p *BatchProposer) skippedL1Messages(batch *encoding.Batch) ([]*orm.SkippedMessage, error) {
+	var skippedMessages []*orm.SkippedMessage
+	nextQueueIndex := batch.TotalL1MessagePoppedBefore
+	for _, chunk := range batch.Chunks {
+		for _, block := range chunk.Blocks {
+			for _, tx := range block.Transactions {
+				if tx.Type != gethTypes.L1MessageTxType {
+					continue
+				}
+				// the nonce of an L1MessageTx holds its queue index, see txsToTxsData.
+				for ; nextQueueIndex < tx.Nonce; nextQueueIndex++ {
+					skippedMessages = append(skippedMessages, &orm.SkippedMessage{
+						QueueIndex:    nextQueueIndex,
+						L2BlockNumber: block.Header.Number.Uint64(),
+						Reason:        orm.SkippedMessageReasonSkipped,
+					})
+				}
+				nextQueueIndex = tx.Nonce + 1
+			}
+		}
+	}
+
+	for _, skippedMessage := range skippedMessages {
+		l1Message, err := p.l1MessageOrm.GetL1MessageByQueueIndex(p.ctx, skippedMessage.QueueIndex)
+		if err != nil {
+			return nil, err
+		}
+		if l1Message != nil {
+			skippedMessage.MsgHash = l1Message.MsgHash
+		}
+		log.Warn("l1 message skipped by the sequencer", "queue index", skippedMessage.QueueIndex, "l2 block", skippedMessage.L2BlockNumber, "batch index", batch.Index)
+	}
+	return skippedMessages, nil
+}
+
 func (p *BatchProposer) getDAChunks(dbChunks []*orm.Chunk) ([]*encoding.Chunk, error) {
 	chunks := make([]*encoding.Chunk, len(dbChunks))
 	for i, c := range dbChunks {
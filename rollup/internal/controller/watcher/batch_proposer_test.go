@@ -5,7 +5,9 @@ import (
 	"math"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/da-codec/encoding"
 	"github.com/scroll-tech/go-ethereum/common"
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
@@ -13,7 +15,10 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"scroll-tech/common/database"
+	"scroll-tech/common/testcontainers"
 	"scroll-tech/common/types"
+	cutils "scroll-tech/common/utils"
+	"scroll-tech/database/migrate"
 
 	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
@@ -893,3 +898,283 @@ func testBatchProposerMaxChunkNumPerBatchLimit(t *testing.T) {
 		database.CloseDB(db)
 	}
 }
+
+func testBatchProposerL1MessageInclusionDeadline(t *testing.T) {
+	tests := []struct {
+		name               string
+		deadlineSec        uint64
+		queuedAgo          time.Duration
+		expectedBatchesLen int
+	}{
+		{
+			name:               "DeadlineDisabled",
+			deadlineSec:        0,
+			queuedAgo:          time.Hour,
+			expectedBatchesLen: 1,
+		},
+		{
+			name:               "DeadlineNotReached",
+			deadlineSec:        3600,
+			queuedAgo:          time.Minute,
+			expectedBatchesLen: 1,
+		},
+		{
+			name:               "DeadlineExceeded",
+			deadlineSec:        60,
+			queuedAgo:          time.Hour,
+			expectedBatchesLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupDB(t)
+			defer database.CloseDB(db)
+
+			// Add genesis batch.
+			block := &encoding.Block{
+				Header: &gethTypes.Header{
+					Number: big.NewInt(0),
+				},
+				RowConsumption: &gethTypes.RowConsumption{},
+			}
+			chunk := &encoding.Chunk{
+				Blocks: []*encoding.Block{block},
+			}
+			chunkOrm := orm.NewChunk(db)
+			_, err := chunkOrm.InsertChunk(context.Background(), chunk, encoding.CodecV0, utils.ChunkMetrics{})
+			assert.NoError(t, err)
+			batch := &encoding.Batch{
+				Index:                      0,
+				TotalL1MessagePoppedBefore: 0,
+				ParentBatchHash:            common.Hash{},
+				Chunks:                     []*encoding.Chunk{chunk},
+			}
+			batchOrm := orm.NewBatch(db)
+			_, err = batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+			assert.NoError(t, err)
+
+			l2BlockOrm := orm.NewL2Block(db)
+			err = l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+			assert.NoError(t, err)
+
+			// block1 and block2 contain no L1 message, the first queued message is left out of the batch.
+			l1MessageOrm := orm.NewL1Message(db)
+			err = l1MessageOrm.SaveL1Messages(context.Background(), []*orm.L1Message{{
+				QueueIndex: 0,
+				MsgHash:    common.Hash{1}.String(),
+				Height:     1,
+				Sender:     common.Address{}.String(),
+				Target:     common.Address{}.String(),
+				Value:      "0",
+				Layer1Hash: common.Hash{2}.String(),
+				CreatedAt:  cutils.NowUTC().Add(-tt.queuedAgo),
+			}})
+			assert.NoError(t, err)
+
+			cp := NewChunkProposer(context.Background(), &config.ChunkProposerConfig{
+				MaxBlockNumPerChunk:             1,
+				MaxTxNumPerChunk:                10000,
+				MaxL1CommitGasPerChunk:          50000000000,
+				MaxL1CommitCalldataSizePerChunk: 1000000,
+				MaxRowConsumptionPerChunk:       1000000,
+				ChunkTimeoutSec:                 300,
+				GasCostIncreaseMultiplier:       1.2,
+				MaxUncompressedBatchBytesSize:   math.MaxUint64,
+			}, &params.ChainConfig{}, db, nil)
+			cp.TryProposeChunk() // chunk1 contains block1
+			cp.TryProposeChunk() // chunk2 contains block2
+
+			bp := NewBatchProposer(context.Background(), &config.BatchProposerConfig{
+				MaxL1CommitGasPerBatch:          50000000000,
+				MaxL1CommitCalldataSizePerBatch: 1000000,
+				BatchTimeoutSec:                 0,
+				GasCostIncreaseMultiplier:       1.2,
+				MaxUncompressedBatchBytesSize:   math.MaxUint64,
+				L1MessageInclusionDeadlineSec:   tt.deadlineSec,
+			}, &params.ChainConfig{}, db, nil)
+			bp.TryProposeBatch()
+
+			batches, err := batchOrm.GetBatches(context.Background(), map[string]interface{}{}, []string{}, 0)
+			assert.NoError(t, err)
+			assert.Len(t, batches, tt.expectedBatchesLen+1)
+		})
+	}
+}
+
+func testBatchProposerProvingBacklog(t *testing.T) {
+	tests := []struct {
+		name               string
+		maxUnprovenBatches uint64
+		maxUnprovenChunks  uint64
+		expectedBatchesLen int
+	}{
+		{
+			name:               "CapDisabled",
+			expectedBatchesLen: 1,
+		},
+		{
+			name:               "BelowCap",
+			maxUnprovenBatches: 2,
+			maxUnprovenChunks:  4,
+			expectedBatchesLen: 1,
+		},
+		{
+			name:               "UnprovenBatchesCapReached",
+			maxUnprovenBatches: 1,
+			expectedBatchesLen: 0,
+		},
+		{
+			name:               "UnprovenChunksCapReached",
+			maxUnprovenChunks:  3,
+			expectedBatchesLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupDB(t)
+			defer database.CloseDB(db)
+
+			// Add genesis batch, it's unproven like the chunks proposed below.
+			block := &encoding.Block{
+				Header: &gethTypes.Header{
+					Number: big.NewInt(0),
+				},
+				RowConsumption: &gethTypes.RowConsumption{},
+			}
+			chunk := &encoding.Chunk{
+				Blocks: []*encoding.Block{block},
+			}
+			chunkOrm := orm.NewChunk(db)
+			_, err := chunkOrm.InsertChunk(context.Background(), chunk, encoding.CodecV0, utils.ChunkMetrics{})
+			assert.NoError(t, err)
+			batch := &encoding.Batch{
+				Index:                      0,
+				TotalL1MessagePoppedBefore: 0,
+				ParentBatchHash:            common.Hash{},
+				Chunks:                     []*encoding.Chunk{chunk},
+			}
+			batchOrm := orm.NewBatch(db)
+			_, err = batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+			assert.NoError(t, err)
+
+			l2BlockOrm := orm.NewL2Block(db)
+			err = l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+			assert.NoError(t, err)
+
+			cp := NewChunkProposer(context.Background(), &config.ChunkProposerConfig{
+				MaxBlockNumPerChunk:             1,
+				MaxTxNumPerChunk:                10000,
+				MaxL1CommitGasPerChunk:          50000000000,
+				MaxL1CommitCalldataSizePerChunk: 1000000,
+				MaxRowConsumptionPerChunk:       1000000,
+				ChunkTimeoutSec:                 300,
+				GasCostIncreaseMultiplier:       1.2,
+				MaxUncompressedBatchBytesSize:   math.MaxUint64,
+			}, &params.ChainConfig{}, db, nil)
+			cp.TryProposeChunk() // chunk1 contains block1
+			cp.TryProposeChunk() // chunk2 contains block2
+
+			bp := NewBatchProposer(context.Background(), &config.BatchProposerConfig{
+				MaxL1CommitGasPerBatch:          50000000000,
+				MaxL1CommitCalldataSizePerBatch: 1000000,
+				BatchTimeoutSec:                 0,
+				GasCostIncreaseMultiplier:       1.2,
+				MaxUncompressedBatchBytesSize:   math.MaxUint64,
+				MaxUnprovenBatches:              tt.maxUnprovenBatches,
+				MaxUnprovenChunks:               tt.maxUnprovenChunks,
+			}, &params.ChainConfig{}, db, nil)
+			bp.TryProposeBatch()
+
+			batches, err := batchOrm.GetBatches(context.Background(), map[string]interface{}{}, []string{}, 0)
+			assert.NoError(t, err)
+			assert.Len(t, batches, tt.expectedBatchesLen+1)
+		})
+	}
+}
+
+func TestBatchProposerMetrics(t *testing.T) {
+	apps := testcontainers.NewTestcontainerApps()
+	defer apps.Free()
+	assert.NoError(t, apps.StartPostgresContainer())
+	db, err := apps.GetGormDBClient()
+	assert.NoError(t, err)
+	defer database.CloseDB(db)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	// Add genesis batch.
+	genesis := &encoding.Block{
+		Header: &gethTypes.Header{
+			Number: big.NewInt(0),
+		},
+		RowConsumption: &gethTypes.RowConsumption{},
+	}
+	chunk := &encoding.Chunk{
+		Blocks: []*encoding.Block{genesis},
+	}
+	_, err = orm.NewChunk(db).InsertChunk(context.Background(), chunk, encoding.CodecV0, utils.ChunkMetrics{})
+	assert.NoError(t, err)
+	batch := &encoding.Batch{
+		Index:                      0,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.Hash{},
+		Chunks:                     []*encoding.Chunk{chunk},
+	}
+	_, err = orm.NewBatch(db).InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+	assert.NoError(t, err)
+
+	blocks := []*encoding.Block{
+		readBlockFromJSON(t, "../../../testdata/blockTrace_02.json"),
+		readBlockFromJSON(t, "../../../testdata/blockTrace_03.json"),
+	}
+	assert.NoError(t, orm.NewL2Block(db).InsertL2Blocks(context.Background(), blocks))
+
+	cp := NewChunkProposer(context.Background(), &config.ChunkProposerConfig{
+		MaxBlockNumPerChunk:             1,
+		MaxTxNumPerChunk:                10000,
+		MaxL1CommitGasPerChunk:          50000000000,
+		MaxL1CommitCalldataSizePerChunk: 1000000,
+		MaxRowConsumptionPerChunk:       1000000,
+		ChunkTimeoutSec:                 300,
+		GasCostIncreaseMultiplier:       1.2,
+		MaxUncompressedBatchBytesSize:   math.MaxUint64,
+	}, &params.ChainConfig{}, db, nil)
+	cp.TryProposeChunk() // chunk1 contains block1
+	cp.TryProposeChunk() // chunk2 contains block2
+
+	reg := prometheus.NewRegistry()
+	bp := NewBatchProposer(context.Background(), &config.BatchProposerConfig{
+		MaxL1CommitGasPerBatch:          50000000000,
+		MaxL1CommitCalldataSizePerBatch: 1000000,
+		BatchTimeoutSec:                 0,
+		GasCostIncreaseMultiplier:       1.2,
+		MaxUncompressedBatchBytesSize:   math.MaxUint64,
+	}, &params.ChainConfig{}, db, reg)
+	bp.TryProposeBatch()
+
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+	counts := make(map[string]uint64)
+	sums := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			// the batch proposer metrics have no labels.
+			assert.Empty(t, metric.GetLabel())
+			if histogram := metric.GetHistogram(); histogram != nil {
+				counts[family.GetName()] = histogram.GetSampleCount()
+				sums[family.GetName()] = histogram.GetSampleSum()
+			}
+		}
+	}
+	// the proposed batch has the two chunks of one block each.
+	assert.Equal(t, map[string]uint64{
+		"rollup_propose_batch_blocks_number": 1,
+		"rollup_propose_batch_l2_gas":        1,
+	}, counts)
+	assert.Equal(t, float64(2), sums["rollup_propose_batch_blocks_number"])
+	assert.NotZero(t, blocks[0].Header.GasUsed+blocks[1].Header.GasUsed)
+	assert.Equal(t, float64(blocks[0].Header.GasUsed+blocks[1].Header.GasUsed), sums["rollup_propose_batch_l2_gas"])
+}
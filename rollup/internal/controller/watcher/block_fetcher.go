@@ -0,0 +1,150 @@
+package watcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	defaultBlockFetcherWorkers          = 4
+	defaultBlockFetcherMaxRetries       = 3
+	defaultBlockFetcherInitialBackoffMs = 200
+	defaultBlockFetcherMaxBackoffMs     = 5000
+)
+
+// fetchedBlock is a block fetched from l2geth with the reason it's skipped by the chunk proposer, if any.
+type fetchedBlock struct {
+	block      *encoding.Block
+	skipReason string
+	// l1MessageGasUsed is the gas used by the L1MessageTxs of the block, by transaction hash.
+	l1MessageGasUsed map[string]uint64
+}
+
+// blockFetcher fetches a range of blocks with a bounded pool of workers, retrying every block with a backoff.
+type blockFetcher struct {
+	workers        int
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	// onRetry is called before every retry of a block.
+	onRetry func()
+}
+
+func newBlockFetcher(cfg *config.BlockFetcherConfig, onRetry func()) *blockFetcher {
+	f := &blockFetcher{
+		workers:        defaultBlockFetcherWorkers,
+		maxRetries:     defaultBlockFetcherMaxRetries,
+		initialBackoff: defaultBlockFetcherInitialBackoffMs * time.Millisecond,
+		maxBackoff:     defaultBlockFetcherMaxBackoffMs * time.Millisecond,
+		onRetry:        onRetry,
+	}
+	if cfg != nil {
+		if cfg.Workers > 0 {
+			f.workers = cfg.Workers
+		}
+		if cfg.MaxRetries > 0 {
+			f.maxRetries = cfg.MaxRetries
+		}
+		if cfg.InitialBackoffMs > 0 {
+			f.initialBackoff = time.Duration(cfg.InitialBackoffMs) * time.Millisecond
+		}
+		if cfg.MaxBackoffMs > 0 {
+			f.maxBackoff = time.Duration(cfg.MaxBackoffMs) * time.Millisecond
+		}
+	}
+	return f
+}
+
+// fetch fetches the blocks of the range [from, to] concurrently. It returns them in order, up to the first block
+// which could not be fetched, so the caller can store a contiguous range, and the error of that block.
+func (f *blockFetcher) fetch(ctx context.Context, from, to uint64, fetchBlock func(ctx context.Context, number uint64) (*fetchedBlock, error)) ([]*fetchedBlock, error) {
+	if to < from {
+		return nil, nil
+	}
+
+	// a failed block stops the fetch of the following ones, the blocks after it can't be stored anyway.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*fetchedBlock, to-from+1)
+	numbers := make(chan uint64)
+
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for i := 0; i < f.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range numbers {
+				block, err := f.fetchWithRetry(ctx, number, fetchBlock)
+				if err != nil {
+					// the blocks in flight fail with the cancellation, only the failure causing it is reported.
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				results[number-from] = block
+			}
+		}()
+	}
+
+feed:
+	for number := from; number <= to; number++ {
+		select {
+		case numbers <- number:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	for i, block := range results {
+		if block == nil {
+			if firstErr == nil {
+				firstErr = ctx.Err()
+			}
+			return results[:i], firstErr
+		}
+	}
+	return results, nil
+}
+
+func (f *blockFetcher) fetchWithRetry(ctx context.Context, number uint64, fetchBlock func(ctx context.Context, number uint64) (*fetchedBlock, error)) (*fetchedBlock, error) {
+	backoff := f.initialBackoff
+	for attempt := 0; ; attempt++ {
+		block, err := fetchBlock(ctx, number)
+		if err == nil {
+			return block, nil
+		}
+		if attempt >= f.maxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+
+		log.Warn("failed to fetch block, retrying", "height", number, "attempt", attempt+1, "backoff", backoff, "err", err)
+		if f.onRetry != nil {
+			f.onRetry()
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, err
+		}
+		backoff *= 2
+		if backoff > f.maxBackoff {
+			backoff = f.maxBackoff
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/scroll-tech/da-codec/encoding"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestBlockFetcher(t *testing.T) {
+	var mu sync.Mutex
+	attempts := make(map[uint64]int)
+	retries := 0
+	fetcher := newBlockFetcher(&config.BlockFetcherConfig{Workers: 3, MaxRetries: 2, InitialBackoffMs: 1, MaxBackoffMs: 2}, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		retries++
+	})
+
+	failing := map[uint64]int{3: 1, 7: 5}
+	fetchBlock := func(ctx context.Context, number uint64) (*fetchedBlock, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[number]++
+		if attempts[number] <= failing[number] {
+			return nil, errors.New("l2geth unavailable")
+		}
+		return &fetchedBlock{block: &encoding.Block{Header: &gethTypes.Header{Number: new(big.Int).SetUint64(number)}}}, nil
+	}
+
+	// the blocks are returned in order, the failed fetches are retried.
+	blocks, err := fetcher.fetch(context.Background(), 1, 6, fetchBlock)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 6)
+	for i, block := range blocks {
+		assert.Equal(t, uint64(i+1), block.block.Header.Number.Uint64())
+	}
+	assert.Equal(t, 2, attempts[3])
+	assert.Equal(t, 1, retries)
+
+	// the blocks below the block failing after all its retries are returned.
+	blocks, err = fetcher.fetch(context.Background(), 5, 12, fetchBlock)
+	assert.EqualError(t, err, "l2geth unavailable")
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, uint64(6), blocks[1].block.Header.Number.Uint64())
+	assert.Equal(t, 3, attempts[7])
+
+	blocks, err = fetcher.fetch(context.Background(), 2, 1, fetchBlock)
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+}
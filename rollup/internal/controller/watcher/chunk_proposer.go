@@ -13,6 +13,7 @@ import (
 	"gorm.io/gorm"
 
 	"scroll-tech/common/forks"
+	cutils "scroll-tech/common/utils"
 
 	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
@@ -25,6 +26,7 @@ type ChunkProposer struct {
 	db  *gorm.DB
 
 	chunkOrm   *orm.Chunk
+	batchOrm   *orm.Batch
 	l2BlockOrm *orm.L2Block
 
 	maxBlockNumPerChunk             uint64
@@ -32,9 +34,12 @@ type ChunkProposer struct {
 	maxL1CommitGasPerChunk          uint64
 	maxL1CommitCalldataSizePerChunk uint64
 	maxRowConsumptionPerChunk       uint64
+	maxRowConsumptionPerSubCircuit  map[string]uint64
 	chunkTimeoutSec                 uint64
+	proposeWhenIdleSec              uint64
 	gasCostIncreaseMultiplier       float64
 	maxUncompressedBatchBytesSize   uint64
+	splitChunkAfterFailedAttempts   int16
 	forkHeights                     []uint64
 
 	chainCfg *params.ChainConfig
@@ -54,6 +59,8 @@ type ChunkProposer struct {
 	chunkEstimateGasTime               prometheus.Gauge
 	chunkEstimateCalldataSizeTime      prometheus.Gauge
 	chunkEstimateBlobSizeTime          prometheus.Gauge
+	chunkSplitTotal                    prometheus.Counter
+	chunkHaltedBlockNumber             prometheus.Gauge
 }
 
 // NewChunkProposer creates a new ChunkProposer instance.
@@ -64,7 +71,9 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 		"maxL1CommitGasPerChunk", cfg.MaxL1CommitGasPerChunk,
 		"maxL1CommitCalldataSizePerChunk", cfg.MaxL1CommitCalldataSizePerChunk,
 		"maxRowConsumptionPerChunk", cfg.MaxRowConsumptionPerChunk,
+		"maxRowConsumptionPerSubCircuit", cfg.MaxRowConsumptionPerSubCircuit,
 		"chunkTimeoutSec", cfg.ChunkTimeoutSec,
+		"proposeWhenIdleSec", cfg.ProposeWhenIdleSec,
 		"gasCostIncreaseMultiplier", cfg.GasCostIncreaseMultiplier,
 		"maxUncompressedBatchBytesSize", cfg.MaxUncompressedBatchBytesSize,
 		"forkHeights", forkHeights)
@@ -73,15 +82,19 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 		ctx:                             ctx,
 		db:                              db,
 		chunkOrm:                        orm.NewChunk(db),
+		batchOrm:                        orm.NewBatch(db),
 		l2BlockOrm:                      orm.NewL2Block(db),
 		maxBlockNumPerChunk:             cfg.MaxBlockNumPerChunk,
 		maxTxNumPerChunk:                cfg.MaxTxNumPerChunk,
 		maxL1CommitGasPerChunk:          cfg.MaxL1CommitGasPerChunk,
 		maxL1CommitCalldataSizePerChunk: cfg.MaxL1CommitCalldataSizePerChunk,
 		maxRowConsumptionPerChunk:       cfg.MaxRowConsumptionPerChunk,
+		maxRowConsumptionPerSubCircuit:  cfg.MaxRowConsumptionPerSubCircuit,
 		chunkTimeoutSec:                 cfg.ChunkTimeoutSec,
+		proposeWhenIdleSec:              cfg.ProposeWhenIdleSec,
 		gasCostIncreaseMultiplier:       cfg.GasCostIncreaseMultiplier,
 		maxUncompressedBatchBytesSize:   cfg.MaxUncompressedBatchBytesSize,
+		splitChunkAfterFailedAttempts:   cfg.SplitChunkAfterFailedAttempts,
 		forkHeights:                     forkHeights,
 		chainCfg:                        chainCfg,
 
@@ -145,6 +158,14 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 			Name: "rollup_propose_chunk_estimate_blob_size_time",
 			Help: "Time taken to estimate blob size for the chunk.",
 		}),
+		chunkSplitTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_propose_chunk_split_total",
+			Help: "Total number of chunks split after repeated proving failures.",
+		}),
+		chunkHaltedBlockNumber: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_propose_chunk_halted_block_number",
+			Help: "The number of the block flagged as skipped the chunk proposer is halted at, 0 if it isn't halted.",
+		}),
 	}
 
 	return p
@@ -153,6 +174,11 @@ func NewChunkProposer(ctx context.Context, cfg *config.ChunkProposerConfig, chai
 // TryProposeChunk tries to propose a new chunk.
 func (p *ChunkProposer) TryProposeChunk() {
 	p.chunkProposerCircleTotal.Inc()
+	if p.splitChunkAfterFailedAttempts > 0 {
+		if err := p.splitFailedChunk(); err != nil {
+			log.Error("split failed chunk failed", "err", err)
+		}
+	}
 	if err := p.proposeChunk(); err != nil {
 		p.proposeChunkFailureTotal.Inc()
 		log.Error("propose new chunk failed", "err", err)
@@ -160,14 +186,96 @@ func (p *ChunkProposer) TryProposeChunk() {
 	}
 }
 
+// splitFailedChunk bisects the block range of the first chunk that failed proving too many times.
+// The chunk and all the following chunks are deleted, the two halves are inserted in their place,
+// and the blocks after them are chunked again by the proposer. The batches, if any, containing these
+// chunks are deleted too, which is only possible while none of them has been sent to L1.
+func (p *ChunkProposer) splitFailedChunk() error {
+	failedChunk, err := p.chunkOrm.GetFirstFailedChunk(p.ctx, p.splitChunkAfterFailedAttempts)
+	if err != nil || failedChunk == nil {
+		return err
+	}
+
+	blocks, err := p.l2BlockOrm.GetL2BlocksInRange(p.ctx, failedChunk.StartBlockNumber, failedChunk.EndBlockNumber)
+	if err != nil {
+		return err
+	}
+	if len(blocks) < 2 {
+		// A single block can't be split, manual fix is needed.
+		return fmt.Errorf("chunk with a single block failed proving; chunk index: %v, hash: %v, block number: %v, failed attempts: %v",
+			failedChunk.Index, failedChunk.Hash, failedChunk.StartBlockNumber, failedChunk.FailedAttempts)
+	}
+
+	var firstBatch *orm.Batch
+	if failedChunk.BatchHash != "" {
+		firstBatch, err = p.batchOrm.GetBatchByHash(p.ctx, failedChunk.BatchHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	codecVersion := utils.CodecVersion(p.chainCfg, blocks[0].Header.Number)
+	halves := []*encoding.Chunk{{Blocks: blocks[:len(blocks)/2]}, {Blocks: blocks[len(blocks)/2:]}}
+
+	err = p.db.Transaction(func(dbTX *gorm.DB) error {
+		if firstBatch != nil {
+			count, err := p.batchOrm.CountNonPendingBatchesGEIndex(p.ctx, firstBatch.Index, dbTX)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("the batch of the failed chunk has already been committed; chunk index: %v, batch index: %v", failedChunk.Index, firstBatch.Index)
+			}
+			if err := p.batchOrm.DeleteBatchesGEIndex(p.ctx, firstBatch.Index, dbTX); err != nil {
+				return err
+			}
+			if err := p.chunkOrm.ResetBatchHashGEIndex(p.ctx, firstBatch.StartChunkIndex, dbTX); err != nil {
+				return err
+			}
+		}
+
+		if err := p.chunkOrm.DeleteChunksGEIndex(p.ctx, failedChunk.Index, dbTX); err != nil {
+			return err
+		}
+		if err := p.l2BlockOrm.ResetChunkHashGEHeight(p.ctx, failedChunk.StartBlockNumber, dbTX); err != nil {
+			return err
+		}
+
+		for _, chunk := range halves {
+			metrics, err := utils.CalculateChunkMetrics(chunk, codecVersion)
+			if err != nil {
+				return fmt.Errorf("failed to calculate chunk metrics: %w", err)
+			}
+			dbChunk, err := p.chunkOrm.InsertChunk(p.ctx, chunk, codecVersion, *metrics, dbTX)
+			if err != nil {
+				return err
+			}
+			if err := p.l2BlockOrm.UpdateChunkHashInRange(p.ctx, dbChunk.StartBlockNumber, dbChunk.EndBlockNumber, dbChunk.Hash, dbTX); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p.chunkSplitTotal.Inc()
+	log.Warn("split chunk after repeated proving failures", "chunk index", failedChunk.Index, "hash", failedChunk.Hash,
+		"start block", failedChunk.StartBlockNumber, "end block", failedChunk.EndBlockNumber, "failed attempts", failedChunk.FailedAttempts)
+	return nil
+}
+
 func (p *ChunkProposer) updateDBChunkInfo(chunk *encoding.Chunk, codecVersion encoding.CodecVersion, metrics utils.ChunkMetrics) error {
 	if chunk == nil {
 		return nil
 	}
 
 	p.proposeChunkUpdateInfoTotal.Inc()
+	var dbChunk *orm.Chunk
 	err := p.db.Transaction(func(dbTX *gorm.DB) error {
-		dbChunk, err := p.chunkOrm.InsertChunk(p.ctx, chunk, codecVersion, metrics, dbTX)
+		var err error
+		dbChunk, err = p.chunkOrm.InsertChunk(p.ctx, chunk, codecVersion, metrics, dbTX)
 		if err != nil {
 			log.Warn("ChunkProposer.InsertChunk failed", "err", err)
 			return err
@@ -183,6 +291,7 @@ func (p *ChunkProposer) updateDBChunkInfo(chunk *encoding.Chunk, codecVersion en
 		log.Error("update chunk info in orm failed", "err", err)
 		return err
 	}
+	cutils.TaskLogger(dbChunk.Hash, "").Info("proposed chunk", "index", dbChunk.Index, "start block", dbChunk.StartBlockNumber, "end block", dbChunk.EndBlockNumber)
 	return nil
 }
 
@@ -209,14 +318,25 @@ func (p *ChunkProposer) proposeChunk() error {
 		return nil
 	}
 
-	var codecVersion encoding.CodecVersion
-	if !p.chainCfg.IsBernoulli(blocks[0].Header.Number) {
-		codecVersion = encoding.CodecV0
-	} else if !p.chainCfg.IsCurie(blocks[0].Header.Number) {
-		codecVersion = encoding.CodecV1
-	} else {
-		codecVersion = encoding.CodecV2
+	// Blocks flagged by the watcher cannot be proven. The chunks must cover every L2 block in order, so a flagged
+	// block can't be left out: the blocks before it are chunked, then the proposer halts at it until the operator
+	// clears the flag with the admin api, e.g. once the circuits support the opcode, or the block is reorged away.
+	skippedBlock, err := p.l2BlockOrm.GetFirstSkippedL2BlockInRange(p.ctx, unchunkedBlockHeight, unchunkedBlockHeight+uint64(len(blocks))-1)
+	if err != nil {
+		return err
 	}
+	if skippedBlock != nil {
+		if skippedBlock.Number == unchunkedBlockHeight {
+			p.chunkHaltedBlockNumber.Set(float64(skippedBlock.Number))
+			return fmt.Errorf("chunk proposer halted at a block flagged as skipped; block number: %v, hash: %v, reason: %v", skippedBlock.Number, skippedBlock.Hash, skippedBlock.SkipReason)
+		}
+		log.Warn("chunking the blocks before a skipped block", "block number", skippedBlock.Number, "hash", skippedBlock.Hash, "reason", skippedBlock.SkipReason)
+		blocks = blocks[:skippedBlock.Number-unchunkedBlockHeight]
+		maxBlocksThisChunk = uint64(len(blocks))
+	}
+	p.chunkHaltedBlockNumber.Set(0)
+
+	codecVersion := utils.CodecVersion(p.chainCfg, blocks[0].Header.Number)
 
 	// Including Curie block in a sole chunk.
 	if p.chainCfg.CurieBlock != nil && blocks[0].Header.Number.Cmp(p.chainCfg.CurieBlock) == 0 {
@@ -241,16 +361,18 @@ func (p *ChunkProposer) proposeChunk() error {
 		p.recordTimerChunkMetrics(metrics)
 
 		overEstimatedL1CommitGas := uint64(p.gasCostIncreaseMultiplier * float64(metrics.L1CommitGas))
+		exceededSubCircuit := utils.ExceededSubCircuit(metrics.SubCircuitRows, p.maxRowConsumptionPerSubCircuit)
 		if metrics.TxNum > p.maxTxNumPerChunk ||
 			metrics.L1CommitCalldataSize > p.maxL1CommitCalldataSizePerChunk ||
 			overEstimatedL1CommitGas > p.maxL1CommitGasPerChunk ||
 			metrics.CrcMax > p.maxRowConsumptionPerChunk ||
+			exceededSubCircuit != "" ||
 			metrics.L1CommitBlobSize > maxBlobSize ||
 			metrics.L1CommitUncompressedBatchBytesSize > p.maxUncompressedBatchBytesSize {
 			if i == 0 {
 				// The first block exceeds hard limits, which indicates a bug in the sequencer, manual fix is needed.
-				return fmt.Errorf("the first block exceeds limits; block number: %v, limits: %+v, maxTxNum: %v, maxL1CommitCalldataSize: %v, maxL1CommitGas: %v, maxRowConsumption: %v, maxBlobSize: %v, maxUncompressedBatchBytesSize: %v",
-					block.Header.Number, metrics, p.maxTxNumPerChunk, p.maxL1CommitCalldataSizePerChunk, p.maxL1CommitGasPerChunk, p.maxRowConsumptionPerChunk, maxBlobSize, p.maxUncompressedBatchBytesSize)
+				return fmt.Errorf("the first block exceeds limits; block number: %v, limits: %+v, maxTxNum: %v, maxL1CommitCalldataSize: %v, maxL1CommitGas: %v, maxRowConsumption: %v, exceededSubCircuit: %v, maxBlobSize: %v, maxUncompressedBatchBytesSize: %v",
+					block.Header.Number, metrics, p.maxTxNumPerChunk, p.maxL1CommitCalldataSizePerChunk, p.maxL1CommitGasPerChunk, p.maxRowConsumptionPerChunk, exceededSubCircuit, maxBlobSize, p.maxUncompressedBatchBytesSize)
 			}
 
 			log.Debug("breaking limit condition in chunking",
@@ -263,6 +385,7 @@ func (p *ChunkProposer) proposeChunk() error {
 				"maxL1CommitGas", p.maxL1CommitGasPerChunk,
 				"rowConsumption", metrics.CrcMax,
 				"maxRowConsumption", p.maxRowConsumptionPerChunk,
+				"exceededSubCircuit", exceededSubCircuit,
 				"l1CommitBlobSize", metrics.L1CommitBlobSize,
 				"maxBlobSize", maxBlobSize,
 				"L1CommitUncompressedBatchBytesSize", metrics.L1CommitUncompressedBatchBytesSize,
@@ -286,12 +409,16 @@ func (p *ChunkProposer) proposeChunk() error {
 	}
 
 	currentTimeSec := uint64(time.Now().Unix())
-	if metrics.FirstBlockTimestamp+p.chunkTimeoutSec < currentTimeSec || metrics.NumBlocks == maxBlocksThisChunk {
-		log.Info("reached maximum number of blocks in chunk or first block timeout",
+	// fewer blocks than the max are all the pending ones, the chain is idle if the last of them is old enough.
+	lastBlockTimestamp := chunk.Blocks[len(chunk.Blocks)-1].Header.Time
+	idle := p.proposeWhenIdleSec > 0 && lastBlockTimestamp+p.proposeWhenIdleSec < currentTimeSec
+	if metrics.FirstBlockTimestamp+p.chunkTimeoutSec < currentTimeSec || metrics.NumBlocks == maxBlocksThisChunk || idle {
+		log.Info("reached maximum number of blocks in chunk or first block timeout or idle timeout",
 			"start block number", chunk.Blocks[0].Header.Number,
 			"block count", len(chunk.Blocks),
 			"block number", chunk.Blocks[0].Header.Number,
 			"block timestamp", metrics.FirstBlockTimestamp,
+			"last block timestamp", lastBlockTimestamp,
 			"current time", currentTimeSec)
 
 		p.chunkFirstBlockTimeoutReached.Inc()
@@ -0,0 +1,195 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const (
+	// LifecycleEventSchemaVersion is the version of the schema of the exported events, the fields are only added
+	// within a version.
+	LifecycleEventSchemaVersion = 1
+
+	eventExporterCursorName          = "event_exporter"
+	defaultEventExporterTopicPrefix  = "scroll.rollup"
+	defaultEventExporterTimeoutSec   = 10
+	defaultEventExporterEventsPerRun = 100
+)
+
+// LifecycleEvent is the message of a status transition of a batch, a chunk or an L1 message exported to the
+// message queue.
+type LifecycleEvent struct {
+	SchemaVersion int `json:"schema_version"`
+	// EventID is the id of the status transition, the events are published at least once so it dedups them.
+	EventID uint64 `json:"event_id"`
+	// Entity is "batch", "chunk" or "l1_message".
+	Entity string `json:"entity"`
+	// Key is the hash of the batch or the chunk, or the message hash of the L1 message.
+	Key string `json:"key"`
+	// StatusColumn is the status which changed, e.g. "rollup_status" or "proving_status" of a batch.
+	StatusColumn string `json:"status_column"`
+	// FromStatus is nil for the status the record was inserted with. The statuses are the values of the enums of
+	// common/types, e.g. types.RollupStatus for the rollup_status.
+	FromStatus *int `json:"from_status"`
+	ToStatus   int  `json:"to_status"`
+	// Actor is the database user which made the transition.
+	Actor     string    `json:"actor"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// EventExporter publishes the lifecycle events of the batches, the chunks and the L1 messages to a message queue,
+// so the analytics and the downstream indexers consume the rollup pipeline without polling the database. Like the
+// batch notifier, it follows the status transitions recorded in the status_audit_log table from a persisted cursor.
+type EventExporter struct {
+	ctx context.Context
+
+	statusAuditLogOrm *orm.StatusAuditLog
+	notifierCursorOrm *orm.NotifierCursor
+
+	publisher    EventPublisher
+	topicPrefix  string
+	timeout      time.Duration
+	eventsPerRun int
+
+	eventExporterEventTotal   *prometheus.CounterVec
+	eventExporterFailureTotal prometheus.Counter
+}
+
+// NewEventExporter creates a new EventExporter instance.
+func NewEventExporter(ctx context.Context, cfg *config.EventExporterConfig, db *gorm.DB, reg prometheus.Registerer) (*EventExporter, error) {
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+	if timeout == 0 {
+		timeout = defaultEventExporterTimeoutSec * time.Second
+	}
+	publisher, err := NewEventPublisher(cfg, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return newEventExporter(ctx, cfg, publisher, timeout, db, reg), nil
+}
+
+func newEventExporter(ctx context.Context, cfg *config.EventExporterConfig, publisher EventPublisher, timeout time.Duration, db *gorm.DB, reg prometheus.Registerer) *EventExporter {
+	topicPrefix := cfg.TopicPrefix
+	if topicPrefix == "" {
+		topicPrefix = defaultEventExporterTopicPrefix
+	}
+	eventsPerRun := cfg.MaxEventsPerRun
+	if eventsPerRun <= 0 {
+		eventsPerRun = defaultEventExporterEventsPerRun
+	}
+
+	return &EventExporter{
+		ctx:               ctx,
+		statusAuditLogOrm: orm.NewStatusAuditLog(db),
+		notifierCursorOrm: orm.NewNotifierCursor(db),
+		publisher:         publisher,
+		topicPrefix:       topicPrefix,
+		timeout:           timeout,
+		eventsPerRun:      eventsPerRun,
+
+		eventExporterEventTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_event_exporter_event_total",
+			Help: "Total number of lifecycle events published to the message queue.",
+		}, []string{"entity"}),
+		eventExporterFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_event_exporter_failure_total",
+			Help: "Total number of event exporter runs stopped by a failure, the events are published again by the next run.",
+		}),
+	}
+}
+
+// TryExport publishes the events of the status transitions recorded since the cursor in one publication, and moves
+// the cursor past them once the message queue received them all. A failed publication is retried by the next run.
+func (e *EventExporter) TryExport() {
+	lastID, err := e.notifierCursorOrm.GetLastID(e.ctx, eventExporterCursorName)
+	if err != nil {
+		e.eventExporterFailureTotal.Inc()
+		log.Error("failed to get event exporter cursor", "err", err)
+		return
+	}
+	if lastID == nil {
+		// a new exporter starts from now, the history is in the database.
+		latestID, latestErr := e.statusAuditLogOrm.GetLatestID(e.ctx)
+		if latestErr != nil {
+			e.eventExporterFailureTotal.Inc()
+			log.Error("failed to get latest status audit log id", "err", latestErr)
+			return
+		}
+		if err = e.notifierCursorOrm.UpdateLastID(e.ctx, eventExporterCursorName, latestID); err != nil {
+			e.eventExporterFailureTotal.Inc()
+			log.Error("failed to init event exporter cursor", "err", err)
+		}
+		return
+	}
+
+	logs, err := e.statusAuditLogOrm.GetAllStatusAuditLogsAfterID(e.ctx, *lastID, e.eventsPerRun)
+	if err != nil {
+		e.eventExporterFailureTotal.Inc()
+		log.Error("failed to get status transitions", "after id", *lastID, "err", err)
+		return
+	}
+	if len(logs) == 0 {
+		return
+	}
+
+	messages := make([]*EventMessage, 0, len(logs))
+	for _, auditLog := range logs {
+		value, marshalErr := json.Marshal(lifecycleEventOf(auditLog))
+		if marshalErr != nil {
+			e.eventExporterFailureTotal.Inc()
+			log.Error("failed to marshal lifecycle event", "event id", auditLog.ID, "err", marshalErr)
+			return
+		}
+		messages = append(messages, &EventMessage{
+			Topic: fmt.Sprintf("%s.%s", e.topicPrefix, auditLog.Table),
+			Key:   auditLog.RecordKey,
+			Value: value,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(e.ctx, e.timeout)
+	defer cancel()
+	if err = e.publisher.Publish(ctx, messages); err != nil {
+		e.eventExporterFailureTotal.Inc()
+		log.Error("failed to publish lifecycle events", "first event id", logs[0].ID, "events", len(logs), "err", err)
+		return
+	}
+	for _, auditLog := range logs {
+		e.eventExporterEventTotal.WithLabelValues(auditLog.Table).Inc()
+	}
+
+	lastLog := logs[len(logs)-1]
+	if err = e.notifierCursorOrm.UpdateLastID(e.ctx, eventExporterCursorName, lastLog.ID); err != nil {
+		e.eventExporterFailureTotal.Inc()
+		log.Error("failed to update event exporter cursor", "last id", lastLog.ID, "err", err)
+	}
+}
+
+// Close closes the connection to the message queue.
+func (e *EventExporter) Close() error {
+	return e.publisher.Close()
+}
+
+func lifecycleEventOf(auditLog *orm.StatusAuditLog) *LifecycleEvent {
+	return &LifecycleEvent{
+		SchemaVersion: LifecycleEventSchemaVersion,
+		EventID:       auditLog.ID,
+		Entity:        auditLog.Table,
+		Key:           auditLog.RecordKey,
+		StatusColumn:  auditLog.StatusColumn,
+		FromStatus:    auditLog.FromStatus,
+		ToStatus:      auditLog.ToStatus,
+		Actor:         auditLog.Actor,
+		ChangedAt:     auditLog.ChangedAt,
+	}
+}
@@ -0,0 +1,192 @@
+package watcher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/testcontainers"
+	"scroll-tech/common/types"
+	"scroll-tech/database/migrate"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
+)
+
+type mockEventPublisher struct {
+	messages []*EventMessage
+	err      error
+}
+
+func (p *mockEventPublisher) Publish(_ context.Context, messages []*EventMessage) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, messages...)
+	return nil
+}
+
+func (p *mockEventPublisher) Close() error {
+	return nil
+}
+
+func TestEventExporter(t *testing.T) {
+	apps := testcontainers.NewTestcontainerApps()
+	defer apps.Free()
+	assert.NoError(t, apps.StartPostgresContainer())
+	db, err := apps.GetGormDBClient()
+	assert.NoError(t, err)
+	defer database.CloseDB(db)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	publisher := &mockEventPublisher{}
+	exporter := newEventExporter(context.Background(), &config.EventExporterConfig{}, publisher, time.Second, db, prometheus.NewRegistry())
+	// the first run only starts the cursor.
+	exporter.TryExport()
+
+	block := readBlockFromJSON(t, "../../../testdata/blockTrace_02.json")
+	batchOrm := orm.NewBatch(db)
+	batch := &encoding.Batch{Chunks: []*encoding.Chunk{{Blocks: []*encoding.Block{block}}}}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+	assert.NoError(t, err)
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitting))
+
+	// a failed publication is retried by the next run.
+	publisher.err = errors.New("connection refused")
+	exporter.TryExport()
+	assert.Empty(t, publisher.messages)
+
+	publisher.err = nil
+	exporter.TryExport()
+	var rollupStatusEvents []*LifecycleEvent
+	for _, m := range publisher.messages {
+		assert.Equal(t, "scroll.rollup.batch", m.Topic)
+		assert.Equal(t, dbBatch.Hash, m.Key)
+		var event LifecycleEvent
+		assert.NoError(t, json.Unmarshal(m.Value, &event))
+		assert.Equal(t, LifecycleEventSchemaVersion, event.SchemaVersion)
+		assert.Equal(t, "batch", event.Entity)
+		if event.StatusColumn == "rollup_status" {
+			rollupStatusEvents = append(rollupStatusEvents, &event)
+		}
+	}
+	assert.Len(t, rollupStatusEvents, 2)
+	assert.Nil(t, rollupStatusEvents[0].FromStatus)
+	assert.Equal(t, int(types.RollupPending), rollupStatusEvents[0].ToStatus)
+	assert.Equal(t, int(types.RollupPending), *rollupStatusEvents[1].FromStatus)
+	assert.Equal(t, int(types.RollupCommitting), rollupStatusEvents[1].ToStatus)
+
+	// the published events are not published again.
+	published := len(publisher.messages)
+	exporter.TryExport()
+	assert.Len(t, publisher.messages, published)
+}
+
+// serveNATS accepts one connection and speaks enough of the nats protocol to receive the publications.
+func serveNATS(t *testing.T, listener net.Listener, token string, received chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	_, err = conn.Write([]byte(`INFO {"server_id":"test","max_payload":1048576}` + "\r\n"))
+	assert.NoError(t, err)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			var options struct {
+				AuthToken string `json:"auth_token"`
+			}
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &options))
+			if options.AuthToken != token {
+				_, _ = conn.Write([]byte("-ERR 'Authorization Violation'\r\n"))
+				return
+			}
+		case strings.HasPrefix(line, "PUB "):
+			var subject string
+			var size int
+			_, scanErr := fmt.Sscanf(line, "PUB %s %d", &subject, &size)
+			assert.NoError(t, scanErr)
+			payload := make([]byte, size+2)
+			_, readErr = io.ReadFull(reader, payload)
+			assert.NoError(t, readErr)
+			received <- subject + " " + string(payload[:size])
+		case line == "PING":
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		}
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	received := make(chan string, 10)
+	go serveNATS(t, listener, "secret", received)
+
+	publisher, err := NewEventPublisher(&config.EventExporterConfig{
+		Backend: NATSEventBackend,
+		NATS:    &config.NATSConfig{URL: "nats://" + listener.Addr().String(), Token: "secret"},
+	}, time.Second)
+	assert.NoError(t, err)
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, publisher.Publish(ctx, []*EventMessage{
+		{Topic: "scroll.rollup.batch", Key: "0x01", Value: []byte(`{"event_id":1}`)},
+		{Topic: "scroll.rollup.chunk", Key: "0x02", Value: []byte(`{"event_id":2}`)},
+	}))
+	// the PONG of the flush is answered after the publications, they're all received once Publish returns.
+	assert.Len(t, received, 2)
+	assert.Equal(t, `scroll.rollup.batch {"event_id":1}`, <-received)
+	assert.Equal(t, `scroll.rollup.chunk {"event_id":2}`, <-received)
+
+	// an authentication failure fails the publication.
+	go serveNATS(t, listener, "other", received)
+	publisher, err = NewEventPublisher(&config.EventExporterConfig{
+		Backend: NATSEventBackend,
+		NATS:    &config.NATSConfig{URL: "nats://" + listener.Addr().String(), Token: "secret"},
+	}, time.Second)
+	assert.NoError(t, err)
+	err = publisher.Publish(ctx, []*EventMessage{{Topic: "scroll.rollup.batch", Value: []byte(`{}`)}})
+	assert.ErrorContains(t, err, "Authorization Violation")
+}
+
+func TestNewEventPublisher(t *testing.T) {
+	publisher, err := NewEventPublisher(&config.EventExporterConfig{
+		Backend: KafkaEventBackend,
+		Kafka:   &config.KafkaConfig{Brokers: []string{"127.0.0.1:9092"}, TLS: true, Username: "user", Password: "password"},
+	}, time.Second)
+	assert.NoError(t, err)
+	assert.IsType(t, &kafkaPublisher{}, publisher)
+	assert.NoError(t, publisher.Close())
+
+	_, err = NewEventPublisher(&config.EventExporterConfig{Backend: KafkaEventBackend, Kafka: &config.KafkaConfig{}}, time.Second)
+	assert.ErrorContains(t, err, "missing kafka event exporter brokers")
+	_, err = NewEventPublisher(&config.EventExporterConfig{Backend: NATSEventBackend}, time.Second)
+	assert.ErrorContains(t, err, "missing nats event exporter config")
+	_, err = NewEventPublisher(&config.EventExporterConfig{Backend: "kafka_rest"}, time.Second)
+	assert.ErrorContains(t, err, "unsupported event exporter backend")
+}
@@ -0,0 +1,155 @@
+package watcher
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	// NATSEventBackend publishes the events to a nats server.
+	NATSEventBackend = "nats"
+	// KafkaEventBackend produces the events to a kafka cluster.
+	KafkaEventBackend = "kafka"
+)
+
+// EventMessage is a message published to a topic, the messages of the same key are kept in order.
+type EventMessage struct {
+	Topic string
+	Key   string
+	Value []byte
+}
+
+// EventPublisher publishes the messages to a message queue, in order. A nil error means the message queue has
+// received all the messages.
+type EventPublisher interface {
+	Publish(ctx context.Context, messages []*EventMessage) error
+	Close() error
+}
+
+// NewEventPublisher creates the publisher of the configured backend.
+func NewEventPublisher(cfg *config.EventExporterConfig, timeout time.Duration) (EventPublisher, error) {
+	switch cfg.Backend {
+	case NATSEventBackend:
+		if cfg.NATS == nil {
+			return nil, errors.New("missing nats event exporter config")
+		}
+		return newNATSPublisher(cfg.NATS, timeout), nil
+	case KafkaEventBackend:
+		if cfg.Kafka == nil || len(cfg.Kafka.Brokers) == 0 {
+			return nil, errors.New("missing kafka event exporter brokers")
+		}
+		return newKafkaPublisher(cfg.Kafka, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported event exporter backend: %s", cfg.Backend)
+	}
+}
+
+// natsPublisher publishes with the nats client. A core nats publication is not acknowledged, so the publications
+// are flushed, which waits for the server to process them.
+type natsPublisher struct {
+	cfg     *config.NATSConfig
+	timeout time.Duration
+
+	conn *nats.Conn
+}
+
+func newNATSPublisher(cfg *config.NATSConfig, timeout time.Duration) *natsPublisher {
+	return &natsPublisher{cfg: cfg, timeout: timeout}
+}
+
+// connect connects to the server at the first publication, so the exporter starts while the server is down.
+func (p *natsPublisher) connect() error {
+	options := []nats.Option{
+		nats.Name("rollup-event-exporter"),
+		nats.Timeout(p.timeout),
+		// the publications aren't buffered while reconnecting, they fail and are retried by the next run.
+		nats.ReconnectBufSize(-1),
+	}
+	if p.cfg.User != "" {
+		options = append(options, nats.UserInfo(p.cfg.User, p.cfg.Password))
+	}
+	if p.cfg.Token != "" {
+		options = append(options, nats.Token(p.cfg.Token))
+	}
+	conn, err := nats.Connect(p.cfg.URL, options...)
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+	return nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, messages []*EventMessage) error {
+	if p.conn == nil || p.conn.IsClosed() {
+		if err := p.connect(); err != nil {
+			return fmt.Errorf("failed to connect to nats %s: %w", p.cfg.URL, err)
+		}
+	}
+
+	for _, m := range messages {
+		if err := p.conn.Publish(m.Topic, m.Value); err != nil {
+			return fmt.Errorf("failed to publish to nats subject %s: %w", m.Topic, err)
+		}
+	}
+	// the exporter publishes with a deadline, the flush waits for the server until then.
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush the nats publications: %w", err)
+	}
+	return nil
+}
+
+func (p *natsPublisher) Close() error {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	return nil
+}
+
+// kafkaPublisher produces with the kafka client, a production returns once all the in-sync replicas acknowledged
+// the records. The records of a key go to the same partition, so they're kept in order.
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func newKafkaPublisher(cfg *config.KafkaConfig, timeout time.Duration) *kafkaPublisher {
+	transport := &kafka.Transport{DialTimeout: timeout}
+	if cfg.TLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if cfg.Username != "" {
+		transport.SASL = plain.Mechanism{Username: cfg.Username, Password: cfg.Password}
+	}
+	return &kafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+		WriteTimeout: timeout,
+		ReadTimeout:  timeout,
+		Transport:    transport,
+	}}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, messages []*EventMessage) error {
+	records := make([]kafka.Message, 0, len(messages))
+	for _, m := range messages {
+		records = append(records, kafka.Message{Topic: m.Topic, Key: []byte(m.Key), Value: m.Value})
+	}
+	if err := p.writer.WriteMessages(ctx, records...); err != nil {
+		return fmt.Errorf("failed to produce to kafka: %w", err)
+	}
+	return nil
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
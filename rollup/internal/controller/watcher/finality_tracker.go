@@ -0,0 +1,155 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/orm"
+)
+
+// committedRollupStatuses are the statuses of the batches whose commit transaction is confirmed on L1.
+var committedRollupStatuses = []types.RollupStatus{
+	types.RollupCommitted,
+	types.RollupFinalizing,
+	types.RollupFinalized,
+	types.RollupFinalizeFailed,
+	types.RollupFinalizeQuarantined,
+}
+
+// FinalityHeight is the latest L2 block covered by a batch in a finality level, and the batch.
+type FinalityHeight struct {
+	BlockNumber uint64
+	BatchIndex  uint64
+	BatchHash   string
+	// CommitTxHash and FinalizeTxHash are the L1 transactions of the batch, FinalizeTxHash is empty until it's finalized.
+	CommitTxHash   string
+	FinalizeTxHash string
+}
+
+// FinalityHeights are the finality levels of the L2 chain, a level is nil until a batch reaches it.
+type FinalityHeights struct {
+	// Safe is covered by the latest batch committed on L1, its data is available on L1 but not proven yet.
+	Safe *FinalityHeight
+	// Finalized is covered by the latest batch finalized on L1, it can't be reverted anymore.
+	Finalized *FinalityHeight
+	// UpdatedAt is when the heights were computed.
+	UpdatedAt time.Time
+}
+
+// FinalityTracker computes the safe and finalized L2 heights from the rollup status of the batches, so the
+// infrastructure of the bridges and exchanges can follow the finality of the L2 chain. The latest heights are kept
+// in memory and served without a database query.
+type FinalityTracker struct {
+	ctx context.Context
+
+	batchOrm *orm.Batch
+	chunkOrm *orm.Chunk
+
+	mu      sync.RWMutex
+	heights *FinalityHeights
+
+	finalitySafeBlockNumber      prometheus.Gauge
+	finalityFinalizedBlockNumber prometheus.Gauge
+	finalityUpdateFailureTotal   prometheus.Counter
+}
+
+// NewFinalityTracker creates a new FinalityTracker instance.
+func NewFinalityTracker(ctx context.Context, db *gorm.DB, reg prometheus.Registerer) *FinalityTracker {
+	return &FinalityTracker{
+		ctx:      ctx,
+		batchOrm: orm.NewBatch(db),
+		chunkOrm: orm.NewChunk(db),
+
+		finalitySafeBlockNumber: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_finality_safe_l2_block_number",
+			Help: "The latest L2 block covered by a batch committed on L1.",
+		}),
+		finalityFinalizedBlockNumber: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "rollup_finality_finalized_l2_block_number",
+			Help: "The latest L2 block covered by a batch finalized on L1.",
+		}),
+		finalityUpdateFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_finality_update_failure_total",
+			Help: "Total number of finality height updates that failed, the previous heights are kept.",
+		}),
+	}
+}
+
+// TryUpdate computes the finality heights again, a failure keeps the previous ones.
+func (t *FinalityTracker) TryUpdate() {
+	heights, err := t.computeHeights(t.ctx)
+	if err != nil {
+		t.finalityUpdateFailureTotal.Inc()
+		log.Error("failed to update the finality heights", "err", err)
+		return
+	}
+
+	t.mu.Lock()
+	t.heights = heights
+	t.mu.Unlock()
+
+	if heights.Safe != nil {
+		t.finalitySafeBlockNumber.Set(float64(heights.Safe.BlockNumber))
+	}
+	if heights.Finalized != nil {
+		t.finalityFinalizedBlockNumber.Set(float64(heights.Finalized.BlockNumber))
+	}
+}
+
+// Heights returns the latest finality heights, nil until they are computed once.
+func (t *FinalityTracker) Heights() *FinalityHeights {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.heights
+}
+
+func (t *FinalityTracker) computeHeights(ctx context.Context) (*FinalityHeights, error) {
+	safe, err := t.latestHeight(ctx, committedRollupStatuses)
+	if err != nil {
+		return nil, err
+	}
+	finalized, err := t.latestHeight(ctx, []types.RollupStatus{types.RollupFinalized})
+	if err != nil {
+		return nil, err
+	}
+	return &FinalityHeights{Safe: safe, Finalized: finalized, UpdatedAt: time.Now()}, nil
+}
+
+// latestHeight returns the last block of the latest batch in one of the rollup statuses, nil if there is none. The
+// batches are committed and finalized in order on L1, the blocks below are covered too.
+func (t *FinalityTracker) latestHeight(ctx context.Context, statuses []types.RollupStatus) (*FinalityHeight, error) {
+	batch, err := t.batchOrm.GetLatestBatchByRollupStatus(ctx, statuses)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, nil
+	}
+	chunk, err := t.chunkOrm.GetChunkByIndex(ctx, batch.EndChunkIndex)
+	if err != nil {
+		return nil, err
+	}
+	if chunk == nil {
+		return nil, fmt.Errorf("end chunk %v of batch %v not found", batch.EndChunkIndex, batch.Index)
+	}
+
+	height := &FinalityHeight{
+		BlockNumber:  chunk.EndBlockNumber,
+		BatchIndex:   batch.Index,
+		BatchHash:    batch.Hash,
+		CommitTxHash: batch.CommitTxHash,
+	}
+	if types.RollupStatus(batch.RollupStatus) == types.RollupFinalized {
+		height.FinalizeTxHash = batch.FinalizeTxHash
+	}
+	return height, nil
+}
@@ -0,0 +1,208 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/params"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+const (
+	// DefaultFundCheckIntervalSec is how often the balances are checked when the config doesn't set it.
+	DefaultFundCheckIntervalSec = 60
+
+	defaultFundRealertIntervalSec = 3600
+	pagerDutyEventsURL            = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// FundChecker monitors the balances of the operational accounts on both chains, exports them as metrics
+// and alerts when an account drops below its minimum balance.
+type FundChecker struct {
+	ctx context.Context
+	cfg *config.FundCheckerConfig
+
+	clients         map[string]ethereum.ChainStateReader
+	alertClient     *resty.Client
+	pagerDutyURL    string
+	realertInterval time.Duration
+	// alertedAt is when the accounts below their minimum balance were last alerted on, keyed by chain and address.
+	alertedAt map[string]time.Time
+
+	fundCheckerBalance      *prometheus.GaugeVec
+	fundCheckerBelowMinimum *prometheus.GaugeVec
+	fundCheckerFailureTotal *prometheus.CounterVec
+	fundCheckerAlertTotal   *prometheus.CounterVec
+}
+
+// NewFundChecker creates a new FundChecker instance, the l1 and l2 clients read the balances of the accounts of their chain.
+func NewFundChecker(ctx context.Context, cfg *config.FundCheckerConfig, l1Client, l2Client ethereum.ChainStateReader, reg prometheus.Registerer) (*FundChecker, error) {
+	clients := map[string]ethereum.ChainStateReader{"l1": l1Client, "l2": l2Client}
+	for _, account := range cfg.Accounts {
+		if account.Name == "" {
+			return nil, fmt.Errorf("fund checker account %s has no name", account.Address.Hex())
+		}
+		if client, ok := clients[account.Chain]; !ok || client == nil {
+			return nil, fmt.Errorf("fund checker account %s has unsupported chain: %q", account.Name, account.Chain)
+		}
+	}
+
+	realertInterval := time.Duration(cfg.RealertIntervalSec) * time.Second
+	if realertInterval == 0 {
+		realertInterval = defaultFundRealertIntervalSec * time.Second
+	}
+
+	f := &FundChecker{
+		ctx:             ctx,
+		cfg:             cfg,
+		clients:         clients,
+		pagerDutyURL:    pagerDutyEventsURL,
+		realertInterval: realertInterval,
+		alertedAt:       make(map[string]time.Time),
+
+		fundCheckerBalance: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "rollup_fund_checker_balance_ether",
+			Help: "The balance of the monitored account in ether.",
+		}, []string{"name", "chain", "address"}),
+		fundCheckerBelowMinimum: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "rollup_fund_checker_below_minimum",
+			Help: "Whether the monitored account is below its minimum balance.",
+		}, []string{"name", "chain", "address"}),
+		fundCheckerFailureTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_fund_checker_failure_total",
+			Help: "Total number of failures to get the balance of the monitored account.",
+		}, []string{"name", "chain"}),
+		fundCheckerAlertTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_fund_checker_alert_total",
+			Help: "Total number of low balance alerts sent.",
+		}, []string{"name", "chain"}),
+	}
+	if cfg.AlertWebhookURL != "" || cfg.PagerDutyRoutingKey != "" {
+		f.alertClient = resty.New()
+		f.alertClient.SetTimeout(10 * time.Second)
+	}
+	return f, nil
+}
+
+// TryCheckFunds checks the balances of the accounts once, it's run in a loop by the caller.
+func (f *FundChecker) TryCheckFunds() {
+	for _, account := range f.cfg.Accounts {
+		balance, err := f.clients[account.Chain].BalanceAt(f.ctx, account.Address, nil)
+		if err != nil {
+			f.fundCheckerFailureTotal.WithLabelValues(account.Name, account.Chain).Inc()
+			log.Warn("failed to get the balance of the monitored account", "name", account.Name, "chain", account.Chain, "address", account.Address.Hex(), "err", err)
+			continue
+		}
+
+		ether, _ := new(big.Float).Quo(new(big.Float).SetInt(balance), big.NewFloat(params.Ether)).Float64()
+		f.fundCheckerBalance.WithLabelValues(account.Name, account.Chain, account.Address.Hex()).Set(ether)
+		if account.MinBalance == nil {
+			continue
+		}
+
+		key := account.Chain + ":" + account.Address.Hex()
+		alertedAt, alerted := f.alertedAt[key]
+		if balance.Cmp(account.MinBalance) >= 0 {
+			f.fundCheckerBelowMinimum.WithLabelValues(account.Name, account.Chain, account.Address.Hex()).Set(0)
+			if alerted {
+				delete(f.alertedAt, key)
+				log.Info("monitored account is funded again", "name", account.Name, "chain", account.Chain, "address", account.Address.Hex(), "balance", balance)
+				f.sendAlert(account, balance, true)
+			}
+			continue
+		}
+
+		f.fundCheckerBelowMinimum.WithLabelValues(account.Name, account.Chain, account.Address.Hex()).Set(1)
+		log.Warn("monitored account below its minimum balance", "name", account.Name, "chain", account.Chain, "address", account.Address.Hex(),
+			"balance", balance, "min balance", account.MinBalance)
+		if !alerted || time.Since(alertedAt) >= f.realertInterval {
+			f.alertedAt[key] = time.Now()
+			f.fundCheckerAlertTotal.WithLabelValues(account.Name, account.Chain).Inc()
+			f.sendAlert(account, balance, false)
+		}
+	}
+}
+
+// fundAlert the body of the alert posted to the webhook, Text is shown by Slack.
+type fundAlert struct {
+	Text       string `json:"text"`
+	Name       string `json:"name"`
+	Chain      string `json:"chain"`
+	Address    string `json:"address"`
+	Balance    string `json:"balance"`
+	MinBalance string `json:"min_balance"`
+	Resolved   bool   `json:"resolved"`
+}
+
+// pagerDutyEvent the body of a PagerDuty Events API v2 request
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary  string `json:"summary"`
+	Source   string `json:"source"`
+	Severity string `json:"severity"`
+}
+
+func (f *FundChecker) sendAlert(account *config.FundCheckerAccount, balance *big.Int, resolved bool) {
+	if f.alertClient == nil {
+		return
+	}
+
+	text := fmt.Sprintf("%s account %s (%s) balance %s wei is below the minimum %s wei",
+		strings.ToUpper(account.Chain), account.Name, account.Address.Hex(), balance, account.MinBalance)
+	if resolved {
+		text = fmt.Sprintf("%s account %s (%s) is funded again, balance %s wei",
+			strings.ToUpper(account.Chain), account.Name, account.Address.Hex(), balance)
+	}
+
+	if f.cfg.AlertWebhookURL != "" {
+		alert := fundAlert{
+			Text:       text,
+			Name:       account.Name,
+			Chain:      account.Chain,
+			Address:    account.Address.Hex(),
+			Balance:    balance.String(),
+			MinBalance: account.MinBalance.String(),
+			Resolved:   resolved,
+		}
+		resp, err := f.alertClient.R().SetBody(alert).Post(f.cfg.AlertWebhookURL)
+		if err != nil {
+			log.Error("failed to send low balance alert", "name", account.Name, "err", err)
+		} else if resp.IsError() {
+			log.Error("failed to send low balance alert", "name", account.Name, "status", resp.Status())
+		}
+	}
+
+	if f.cfg.PagerDutyRoutingKey != "" {
+		event := pagerDutyEvent{
+			RoutingKey: f.cfg.PagerDutyRoutingKey,
+			// the incident of an account is resolved by the event of the same dedup key.
+			DedupKey:    "rollup-fund-checker-" + account.Chain + "-" + account.Address.Hex(),
+			EventAction: "resolve",
+		}
+		if !resolved {
+			event.EventAction = "trigger"
+			event.Payload = &pagerDutyPayload{Summary: text, Source: "rollup-fund-checker", Severity: "critical"}
+		}
+		resp, err := f.alertClient.R().SetBody(event).Post(f.pagerDutyURL)
+		if err != nil {
+			log.Error("failed to send low balance pagerduty event", "name", account.Name, "err", err)
+		} else if resp.IsError() {
+			log.Error("failed to send low balance pagerduty event", "name", account.Name, "status", resp.Status())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+type mockBalanceReader struct {
+	ethereum.ChainStateReader
+	balance *big.Int
+}
+
+func (m *mockBalanceReader) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return m.balance, nil
+}
+
+func TestFundChecker(t *testing.T) {
+	var mu sync.Mutex
+	var alerts []fundAlert
+	var events []pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/pagerduty" {
+			var event pagerDutyEvent
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+			events = append(events, event)
+			return
+		}
+		var alert fundAlert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alerts = append(alerts, alert)
+	}))
+	defer server.Close()
+
+	cfg := &config.FundCheckerConfig{
+		Accounts: []*config.FundCheckerAccount{
+			{Name: "commit_sender", Chain: "l1", Address: common.HexToAddress("0x01"), MinBalance: big.NewInt(100)},
+			{Name: "fee_vault", Chain: "l2", Address: common.HexToAddress("0x02")},
+		},
+		AlertWebhookURL:     server.URL + "/webhook",
+		PagerDutyRoutingKey: "routing-key",
+	}
+	l1Client := &mockBalanceReader{balance: big.NewInt(50)}
+	l2Client := &mockBalanceReader{balance: big.NewInt(0)}
+
+	_, err := NewFundChecker(context.Background(), &config.FundCheckerConfig{
+		Accounts: []*config.FundCheckerAccount{{Name: "gas_oracle_sender", Chain: "l3"}},
+	}, l1Client, l2Client, prometheus.NewRegistry())
+	assert.Error(t, err)
+
+	fundChecker, err := NewFundChecker(context.Background(), cfg, l1Client, l2Client, prometheus.NewRegistry())
+	assert.NoError(t, err)
+	fundChecker.pagerDutyURL = server.URL + "/pagerduty"
+
+	// the account below its minimum balance is alerted once until the realert interval passes.
+	fundChecker.TryCheckFunds()
+	fundChecker.TryCheckFunds()
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "commit_sender", alerts[0].Name)
+	assert.Equal(t, "50", alerts[0].Balance)
+	assert.False(t, alerts[0].Resolved)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "trigger", events[0].EventAction)
+
+	l1Client.balance = big.NewInt(100)
+	fundChecker.TryCheckFunds()
+	assert.Len(t, alerts, 2)
+	assert.True(t, alerts[1].Resolved)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "resolve", events[1].EventAction)
+	assert.Equal(t, events[0].DedupKey, events[1].DedupKey)
+}
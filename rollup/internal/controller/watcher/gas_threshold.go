@@ -0,0 +1,111 @@
+package watcher
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const (
+	defaultGasThresholdBaseFeeWindow     = 20
+	defaultGasThresholdHysteresisPercent = uint64(10)
+)
+
+// gasThresholdController picks the commit gas the batches are filled up to from the L1 base fee: while L1 is
+// cheap the batches are smaller and proposed sooner for a lower latency, while it's expensive they are packed
+// up to the max to spread the commit cost over more blocks.
+type gasThresholdController struct {
+	cfg        *config.DynamicGasThresholdConfig
+	l1BlockOrm *orm.L1Block
+
+	minGas uint64
+	maxGas uint64
+	// hysteresis is the gas the target threshold must move away from the current one to be applied.
+	hysteresis uint64
+
+	// threshold is the current threshold, 0 until the base fee is observed once.
+	threshold uint64
+}
+
+func newGasThresholdController(cfg *config.DynamicGasThresholdConfig, maxGas uint64, db *gorm.DB) *gasThresholdController {
+	minGas := cfg.MinL1CommitGasPerBatch
+	if minGas > maxGas {
+		minGas = maxGas
+	}
+	hysteresisPercent := cfg.HysteresisPercent
+	if hysteresisPercent == 0 {
+		hysteresisPercent = defaultGasThresholdHysteresisPercent
+	}
+	return &gasThresholdController{
+		cfg:        cfg,
+		l1BlockOrm: orm.NewL1Block(db),
+		minGas:     minGas,
+		maxGas:     maxGas,
+		hysteresis: (maxGas - minGas) * hysteresisPercent / 100,
+	}
+}
+
+// update observes the average base fee of the latest L1 blocks and returns the threshold, a failure returns the
+// current threshold.
+func (c *gasThresholdController) update(ctx context.Context) (uint64, error) {
+	window := c.cfg.BaseFeeWindow
+	if window <= 0 {
+		window = defaultGasThresholdBaseFeeWindow
+	}
+	blocks, err := c.l1BlockOrm.GetLatestL1Blocks(ctx, window)
+	if err != nil {
+		return c.current(), err
+	}
+	if len(blocks) == 0 {
+		return c.current(), nil
+	}
+
+	var totalBaseFee uint64
+	for _, block := range blocks {
+		totalBaseFee += block.BaseFee
+	}
+	return c.observe(totalBaseFee / uint64(len(blocks))), nil
+}
+
+// observe moves the threshold towards the target of the base fee, unless it's within the hysteresis.
+func (c *gasThresholdController) observe(baseFee uint64) uint64 {
+	target := c.target(baseFee)
+	if c.threshold == 0 {
+		c.threshold = target
+		return c.threshold
+	}
+
+	var distance uint64
+	if target > c.threshold {
+		distance = target - c.threshold
+	} else {
+		distance = c.threshold - target
+	}
+	if distance > c.hysteresis {
+		c.threshold = target
+	}
+	return c.threshold
+}
+
+// target interpolates the threshold of the base fee linearly between the min and the max.
+func (c *gasThresholdController) target(baseFee uint64) uint64 {
+	switch {
+	case baseFee <= c.cfg.LowBaseFee:
+		return c.minGas
+	case baseFee >= c.cfg.HighBaseFee:
+		return c.maxGas
+	}
+	ratio := float64(baseFee-c.cfg.LowBaseFee) / float64(c.cfg.HighBaseFee-c.cfg.LowBaseFee)
+	return c.minGas + uint64(ratio*float64(c.maxGas-c.minGas))
+}
+
+// current returns the threshold, the max until the base fee is observed once.
+func (c *gasThresholdController) current() uint64 {
+	if c.threshold == 0 {
+		return c.maxGas
+	}
+	return c.threshold
+}
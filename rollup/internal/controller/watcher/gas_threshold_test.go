@@ -0,0 +1,38 @@
+package watcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestGasThresholdController(t *testing.T) {
+	c := newGasThresholdController(&config.DynamicGasThresholdConfig{
+		MinL1CommitGasPerBatch: 1000000,
+		LowBaseFee:             10e9,
+		HighBaseFee:            50e9,
+	}, 5000000, nil)
+
+	// the max until the base fee is observed.
+	assert.Equal(t, uint64(5000000), c.current())
+
+	assert.Equal(t, uint64(1000000), c.target(5e9))
+	assert.Equal(t, uint64(3000000), c.target(30e9))
+	assert.Equal(t, uint64(5000000), c.target(100e9))
+
+	// the first observation sets the threshold.
+	assert.Equal(t, uint64(3000000), c.observe(30e9))
+	// a move within 10% of the range is ignored.
+	assert.Equal(t, uint64(3000000), c.observe(33e9))
+	assert.Equal(t, uint64(3000000), c.observe(27e9))
+	// a larger one is applied.
+	assert.Equal(t, uint64(3500000), c.observe(35e9))
+	assert.Equal(t, uint64(1000000), c.observe(1e9))
+	assert.Equal(t, uint64(1000000), c.current())
+
+	// the min is bounded by the max.
+	c = newGasThresholdController(&config.DynamicGasThresholdConfig{MinL1CommitGasPerBatch: 8000000}, 5000000, nil)
+	assert.Equal(t, uint64(5000000), c.observe(0))
+}
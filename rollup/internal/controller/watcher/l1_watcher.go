@@ -3,7 +3,10 @@ package watcher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
+	"sort"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	geth "github.com/scroll-tech/go-ethereum"
@@ -20,10 +23,58 @@ import (
 	"scroll-tech/common/types"
 
 	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
 	"scroll-tech/rollup/internal/utils"
 )
 
+// maxTrackedL1Blocks is the number of processed L1 blocks whose hashes are kept to detect reorgs.
+const maxTrackedL1Blocks = 64
+
+// l1EventRescanBlocks is how far back the rollup events are rescanned at startup when they wait for other
+// confirmations than the deposits, only the processed height of the deposits is known from the database.
+// It covers the distance between the finalized and the latest blocks with some margin.
+const l1EventRescanBlocks = 128
+
+// l1EventCategory groups the L1 events waiting for the same confirmations.
+type l1EventCategory int
+
+const (
+	l1EventDeposit l1EventCategory = iota
+	l1EventCommitBatch
+	l1EventFinalizeBatch
+	numL1EventCategories
+)
+
+// l1EventCheckpointNames are the names of the watcher checkpoints of the event categories.
+var l1EventCheckpointNames = [numL1EventCategories]string{
+	l1EventDeposit:       "l1_watcher_deposit",
+	l1EventCommitBatch:   "l1_watcher_commit_batch",
+	l1EventFinalizeBatch: "l1_watcher_finalize_batch",
+}
+
+// l1EventCategoryOf returns the category of the event log, ok is false for an unknown event.
+func l1EventCategoryOf(vLog gethTypes.Log) (category l1EventCategory, ok bool) {
+	if len(vLog.Topics) == 0 {
+		return 0, false
+	}
+	switch vLog.Topics[0] {
+	case bridgeAbi.L1QueueTransactionEventSignature:
+		return l1EventDeposit, true
+	case bridgeAbi.L1CommitBatchEventSignature:
+		return l1EventCommitBatch, true
+	case bridgeAbi.L1FinalizeBatchEventSignature:
+		return l1EventFinalizeBatch, true
+	default:
+		return 0, false
+	}
+}
+
+type trackedL1Block struct {
+	number uint64
+	hash   common.Hash
+}
+
 type rollupEvent struct {
 	batchHash common.Hash
 	txHash    common.Hash
@@ -34,12 +85,17 @@ type rollupEvent struct {
 type L1WatcherClient struct {
 	ctx          context.Context
 	client       *ethclient.Client
+	db           *gorm.DB
 	l1MessageOrm *orm.L1Message
 	l1BlockOrm   *orm.L1Block
 	batchOrm     *orm.Batch
+	// checkpointOrm persists the processed height of every event category with the events.
+	checkpointOrm *orm.WatcherCheckpoint
+	// l1WatcherBlockOrm persists the tracked blocks with the events.
+	l1WatcherBlockOrm *orm.L1WatcherBlock
 
-	// The number of new blocks to wait for a block to be confirmed
-	confirmations rpc.BlockNumber
+	// The number of new blocks to wait for a block to be confirmed, per event category
+	confirmations [numL1EventCategories]rpc.BlockNumber
 
 	messageQueueAddress common.Address
 	messageQueueABI     *abi.ABI
@@ -47,16 +103,19 @@ type L1WatcherClient struct {
 	scrollChainAddress common.Address
 	scrollChainABI     *abi.ABI
 
-	// The height of the block that the watcher has retrieved event logs
-	processedMsgHeight uint64
+	// The height of the block that the watcher has retrieved event logs, per event category
+	processedEventHeights [numL1EventCategories]uint64
 	// The height of the block that the watcher has retrieved header rlp
 	processedBlockHeight uint64
 
+	// The hashes of the last blocks handled by FetchContractEvent, oldest first, persisted in l1_watcher_block
+	trackedBlocks []trackedL1Block
+
 	metrics *l1WatcherMetrics
 }
 
-// NewL1WatcherClient returns a new instance of L1WatcherClient.
-func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeight uint64, confirmations rpc.BlockNumber, messageQueueAddress, scrollChainAddress common.Address, db *gorm.DB, reg prometheus.Registerer) *L1WatcherClient {
+// NewL1WatcherClient returns a new instance of L1WatcherClient, eventConfirmations may be nil.
+func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeight uint64, confirmations rpc.BlockNumber, eventConfirmations *config.L1EventConfirmations, messageQueueAddress, scrollChainAddress common.Address, db *gorm.DB, reg prometheus.Registerer) *L1WatcherClient {
 	l1MessageOrm := orm.NewL1Message(db)
 	savedHeight, err := l1MessageOrm.GetLayer1LatestWatchedHeight()
 	if err != nil {
@@ -77,13 +136,86 @@ func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeig
 		savedL1BlockHeight = startHeight
 	}
 
+	var categoryConfirmations [numL1EventCategories]rpc.BlockNumber
+	for category := range categoryConfirmations {
+		categoryConfirmations[category] = confirmations
+	}
+	if eventConfirmations != nil {
+		for category, override := range map[l1EventCategory]*rpc.BlockNumber{
+			l1EventDeposit:       eventConfirmations.Deposit,
+			l1EventCommitBatch:   eventConfirmations.CommitBatch,
+			l1EventFinalizeBatch: eventConfirmations.FinalizeBatch,
+		} {
+			if override != nil {
+				categoryConfirmations[category] = *override
+			}
+		}
+	}
+
+	var processedEventHeights [numL1EventCategories]uint64
+	for category := range processedEventHeights {
+		processedEventHeights[category] = uint64(savedHeight)
+		if categoryConfirmations[category] == categoryConfirmations[l1EventDeposit] {
+			continue
+		}
+		rescanBlocks := uint64(l1EventRescanBlocks)
+		if categoryConfirmations[category] > 0 {
+			rescanBlocks += uint64(categoryConfirmations[category])
+		}
+		if processedEventHeights[category] >= startHeight+rescanBlocks {
+			processedEventHeights[category] -= rescanBlocks
+		} else {
+			processedEventHeights[category] = startHeight
+		}
+	}
+
+	// the checkpoints are exact, the heights above are only the fallback of the databases without checkpoints.
+	// The checkpoint blocks and the persisted tracked blocks are tracked again, so a reorg of them while the
+	// watcher was down is handled first thing.
+	checkpointOrm := orm.NewWatcherCheckpoint(db)
+	l1WatcherBlockOrm := orm.NewL1WatcherBlock(db)
+	savedBlocks, err := l1WatcherBlockOrm.GetLatestL1WatcherBlocks(ctx, maxTrackedL1Blocks)
+	if err != nil {
+		log.Warn("Failed to fetch processed L1 blocks from db", "err", err)
+	}
+	var knownBlocks []trackedL1Block
+	for _, block := range savedBlocks {
+		knownBlocks = append(knownBlocks, trackedL1Block{number: block.Number, hash: common.HexToHash(block.Hash)})
+	}
+	for category, name := range l1EventCheckpointNames {
+		checkpoint, err := checkpointOrm.GetCheckpoint(ctx, name)
+		if err != nil {
+			log.Warn("Failed to fetch watcher checkpoint from db", "name", name, "err", err)
+			continue
+		}
+		if checkpoint == nil {
+			continue
+		}
+		processedEventHeights[category] = checkpoint.BlockNumber
+		knownBlocks = append(knownBlocks, trackedL1Block{number: checkpoint.BlockNumber, hash: common.HexToHash(checkpoint.BlockHash)})
+	}
+	sort.Slice(knownBlocks, func(i, j int) bool { return knownBlocks[i].number < knownBlocks[j].number })
+	var trackedBlocks []trackedL1Block
+	for _, block := range knownBlocks {
+		if len(trackedBlocks) == 0 || trackedBlocks[len(trackedBlocks)-1].number != block.number {
+			trackedBlocks = append(trackedBlocks, block)
+		}
+	}
+	if len(trackedBlocks) > maxTrackedL1Blocks {
+		trackedBlocks = trackedBlocks[len(trackedBlocks)-maxTrackedL1Blocks:]
+	}
+
 	return &L1WatcherClient{
 		ctx:           ctx,
 		client:        client,
+		db:            db,
 		l1MessageOrm:  l1MessageOrm,
 		l1BlockOrm:    l1BlockOrm,
 		batchOrm:      orm.NewBatch(db),
-		confirmations: confirmations,
+		checkpointOrm: checkpointOrm,
+
+		l1WatcherBlockOrm: l1WatcherBlockOrm,
+		confirmations:     categoryConfirmations,
 
 		messageQueueAddress: messageQueueAddress,
 		messageQueueABI:     bridgeAbi.L1MessageQueueABI,
@@ -91,9 +223,10 @@ func NewL1WatcherClient(ctx context.Context, client *ethclient.Client, startHeig
 		scrollChainAddress: scrollChainAddress,
 		scrollChainABI:     bridgeAbi.ScrollChainABI,
 
-		processedMsgHeight:   uint64(savedHeight),
-		processedBlockHeight: savedL1BlockHeight,
-		metrics:              initL1WatcherMetrics(reg),
+		processedEventHeights: processedEventHeights,
+		processedBlockHeight:  savedL1BlockHeight,
+		trackedBlocks:         trackedBlocks,
+		metrics:               initL1WatcherMetrics(reg),
 	}
 }
 
@@ -103,16 +236,106 @@ func (w *L1WatcherClient) ProcessedBlockHeight() uint64 {
 	return w.processedBlockHeight
 }
 
-// Confirmations get confirmations
+// Confirmations get the confirmations of the deposit events
 // Currently only use for unit test
 func (w *L1WatcherClient) Confirmations() rpc.BlockNumber {
-	return w.confirmations
+	return w.confirmations[l1EventDeposit]
 }
 
-// SetConfirmations set the confirmations for L1WatcherClient
+// SetConfirmations set the confirmations of every event category for L1WatcherClient
 // Currently only use for unit test
 func (w *L1WatcherClient) SetConfirmations(confirmations rpc.BlockNumber) {
-	w.confirmations = confirmations
+	for category := range w.confirmations {
+		w.confirmations[category] = confirmations
+	}
+}
+
+// processedMsgHeight returns the height up to which the events of every category have been retrieved.
+func (w *L1WatcherClient) processedMsgHeight() uint64 {
+	height := w.processedEventHeights[0]
+	for _, processed := range w.processedEventHeights[1:] {
+		if processed < height {
+			height = processed
+		}
+	}
+	return height
+}
+
+// confirmedHeights returns the latest confirmed block of every event category.
+func (w *L1WatcherClient) confirmedHeights() ([numL1EventCategories]uint64, error) {
+	var heights [numL1EventCategories]uint64
+	fetched := make(map[rpc.BlockNumber]uint64, len(heights))
+	for category, confirmations := range w.confirmations {
+		height, ok := fetched[confirmations]
+		if !ok {
+			var err error
+			if height, err = utils.GetLatestConfirmedBlockNumber(w.ctx, w.client, confirmations); err != nil {
+				return heights, err
+			}
+			fetched[confirmations] = height
+		}
+		heights[category] = height
+	}
+	return heights, nil
+}
+
+// filterUnprocessedLogs drops the logs of the events already retrieved, or not confirmed yet for their category.
+func (w *L1WatcherClient) filterUnprocessedLogs(logs []gethTypes.Log, confirmedHeights [numL1EventCategories]uint64) []gethTypes.Log {
+	filtered := logs[:0]
+	for _, vLog := range logs {
+		if category, ok := l1EventCategoryOf(vLog); ok &&
+			(vLog.BlockNumber <= w.processedEventHeights[category] || vLog.BlockNumber > confirmedHeights[category]) {
+			continue
+		}
+		filtered = append(filtered, vLog)
+	}
+	return filtered
+}
+
+// nextProcessedHeights returns the processed heights once the events up to the given height are retrieved, within
+// the confirmed height of their category.
+func (w *L1WatcherClient) nextProcessedHeights(to uint64, confirmedHeights [numL1EventCategories]uint64) [numL1EventCategories]uint64 {
+	heights := w.processedEventHeights
+	for category := range heights {
+		height := to
+		if confirmedHeights[category] < height {
+			height = confirmedHeights[category]
+		}
+		if height > heights[category] {
+			heights[category] = height
+		}
+	}
+	return heights
+}
+
+// getBlockHashes returns the hashes of the given blocks.
+func (w *L1WatcherClient) getBlockHashes(numbers ...uint64) (map[uint64]common.Hash, error) {
+	hashes := make(map[uint64]common.Hash, len(numbers))
+	for _, number := range numbers {
+		if _, ok := hashes[number]; ok {
+			continue
+		}
+		header, err := w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			log.Warn("Failed to get block header", "height", number, "err", err)
+			return nil, err
+		}
+		hashes[number] = header.Hash()
+	}
+	return hashes, nil
+}
+
+// saveCheckpoints persists the processed heights of the event categories which advanced.
+func (w *L1WatcherClient) saveCheckpoints(processedHeights [numL1EventCategories]uint64, blockHashes map[uint64]common.Hash, dbTX *gorm.DB) error {
+	for category, height := range processedHeights {
+		if height == w.processedEventHeights[category] {
+			continue
+		}
+		if err := w.checkpointOrm.UpdateCheckpoint(w.ctx, l1EventCheckpointNames[category], height, blockHashes[height].String(), dbTX); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // FetchBlockHeader pull latest L1 blocks and save in DB
@@ -164,16 +387,28 @@ func (w *L1WatcherClient) FetchBlockHeader(blockHeight uint64) error {
 // FetchContractEvent pull latest event logs from given contract address and save in DB
 func (w *L1WatcherClient) FetchContractEvent() error {
 	defer func() {
-		log.Info("l1 watcher fetchContractEvent", "w.processedMsgHeight", w.processedMsgHeight)
+		log.Info("l1 watcher fetchContractEvent", "processed deposit height", w.processedEventHeights[l1EventDeposit],
+			"processed commit batch height", w.processedEventHeights[l1EventCommitBatch], "processed finalize batch height", w.processedEventHeights[l1EventFinalizeBatch])
 	}()
-	blockHeight, err := utils.GetLatestConfirmedBlockNumber(w.ctx, w.client, w.confirmations)
+	if err := w.handleReorg(); err != nil {
+		log.Error("failed to handle l1 reorg", "err", err)
+		return err
+	}
+
+	confirmedHeights, err := w.confirmedHeights()
 	if err != nil {
 		log.Error("failed to get block number", "err", err)
 		return err
 	}
 
-	fromBlock := int64(w.processedMsgHeight) + 1
-	toBlock := int64(blockHeight)
+	// a single scan covers every category, from the least processed one to the least confirmed one.
+	fromBlock := int64(w.processedMsgHeight()) + 1
+	var toBlock int64
+	for _, confirmedHeight := range confirmedHeights {
+		if int64(confirmedHeight) > toBlock {
+			toBlock = int64(confirmedHeight)
+		}
+	}
 
 	for from := fromBlock; from <= toBlock; from += contractEventsBlocksFetchLimit {
 		w.metrics.l1WatcherFetchContractEventTotal.Inc()
@@ -203,69 +438,245 @@ func (w *L1WatcherClient) FetchContractEvent() error {
 			log.Warn("Failed to get event logs", "err", err)
 			return err
 		}
-		if len(logs) == 0 {
-			w.processedMsgHeight = uint64(to)
-			w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(to))
-			continue
-		}
+		logs = w.filterUnprocessedLogs(logs, confirmedHeights)
 
-		log.Info("Received new L1 events", "fromBlock", from, "toBlock", to, "cnt", len(logs))
+		var (
+			sentMessageEvents []*orm.L1Message
+			rollupEvents      []rollupEvent
+			statuses          []types.RollupStatus
+		)
+		if len(logs) > 0 {
+			log.Info("Received new L1 events", "fromBlock", from, "toBlock", to, "cnt", len(logs))
 
-		sentMessageEvents, rollupEvents, err := w.parseBridgeEventLogs(logs)
-		if err != nil {
-			log.Error("Failed to parse emitted events log", "err", err)
-			return err
+			sentMessageEvents, rollupEvents, err = w.parseBridgeEventLogs(logs)
+			if err != nil {
+				log.Error("Failed to parse emitted events log", "err", err)
+				return err
+			}
+			sentMessageCount := int64(len(sentMessageEvents))
+			rollupEventCount := int64(len(rollupEvents))
+			w.metrics.l1WatcherFetchContractEventSentEventsTotal.Add(float64(sentMessageCount))
+			w.metrics.l1WatcherFetchContractEventRollupEventsTotal.Add(float64(rollupEventCount))
+			log.Info("L1 events types", "SentMessageCount", sentMessageCount, "RollupEventCount", rollupEventCount)
+
+			// use rollup event to update rollup results db status
+			var batchHashes []string
+			for _, event := range rollupEvents {
+				batchHashes = append(batchHashes, event.batchHash.String())
+			}
+			statuses, err = w.batchOrm.GetRollupStatusByHashList(w.ctx, batchHashes)
+			if err != nil {
+				log.Error("Failed to GetRollupStatusByHashList", "err", err)
+				return err
+			}
+			if len(statuses) != len(batchHashes) {
+				log.Error("RollupStatus.Length mismatch with batchHashes.Length", "RollupStatus.Length", len(statuses), "batchHashes.Length", len(batchHashes))
+				return nil
+			}
 		}
-		sentMessageCount := int64(len(sentMessageEvents))
-		rollupEventCount := int64(len(rollupEvents))
-		w.metrics.l1WatcherFetchContractEventSentEventsTotal.Add(float64(sentMessageCount))
-		w.metrics.l1WatcherFetchContractEventRollupEventsTotal.Add(float64(rollupEventCount))
-		log.Info("L1 events types", "SentMessageCount", sentMessageCount, "RollupEventCount", rollupEventCount)
 
-		// use rollup event to update rollup results db status
-		var batchHashes []string
-		for _, event := range rollupEvents {
-			batchHashes = append(batchHashes, event.batchHash.String())
-		}
-		statuses, err := w.batchOrm.GetRollupStatusByHashList(w.ctx, batchHashes)
+		processedHeights := w.nextProcessedHeights(uint64(to), confirmedHeights)
+		blockHashes, err := w.getBlockHashes(append(processedHeights[:], uint64(to))...)
 		if err != nil {
-			log.Error("Failed to GetRollupStatusByHashList", "err", err)
 			return err
 		}
-		if len(statuses) != len(batchHashes) {
-			log.Error("RollupStatus.Length mismatch with batchHashes.Length", "RollupStatus.Length", len(statuses), "batchHashes.Length", len(batchHashes))
-			return nil
-		}
 
-		for index, event := range rollupEvents {
-			batchHash := event.batchHash.String()
-			status := statuses[index]
-			// only update when db status is before event status
-			if event.status > status {
+		// the events are stored with the checkpoints, a crash can't leave them apart, so a restart resumes
+		// right after the stored events, neither inserting them twice nor skipping any.
+		err = w.db.Transaction(func(dbTX *gorm.DB) error {
+			for index, event := range rollupEvents {
+				batchHash := event.batchHash.String()
+				status := statuses[index]
+				// only update when db status is before event status
+				if event.status <= status {
+					continue
+				}
+				var updateErr error
 				if event.status == types.RollupFinalized {
-					err = w.batchOrm.UpdateFinalizeTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status)
+					updateErr = w.batchOrm.UpdateFinalizeTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status, dbTX)
 				} else if event.status == types.RollupCommitted {
-					err = w.batchOrm.UpdateCommitTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status)
+					updateErr = w.batchOrm.UpdateCommitTxHashAndRollupStatus(w.ctx, batchHash, event.txHash.String(), event.status, dbTX)
 				}
-				if err != nil {
-					log.Error("Failed to update Rollup/Finalize TxHash and Status", "err", err)
-					return err
+				if updateErr != nil {
+					log.Error("Failed to update Rollup/Finalize TxHash and Status", "err", updateErr)
+					return updateErr
 				}
 			}
-		}
 
-		if err = w.l1MessageOrm.SaveL1Messages(w.ctx, sentMessageEvents); err != nil {
+			if saveErr := w.l1MessageOrm.SaveL1Messages(w.ctx, sentMessageEvents, dbTX); saveErr != nil {
+				return saveErr
+			}
+			if saveErr := w.saveCheckpoints(processedHeights, blockHashes, dbTX); saveErr != nil {
+				return saveErr
+			}
+			return w.l1WatcherBlockOrm.InsertL1WatcherBlock(w.ctx, uint64(to), blockHashes[uint64(to)].String(), maxTrackedL1Blocks, dbTX)
+		})
+		if err != nil {
 			return err
 		}
 
-		w.processedMsgHeight = uint64(to)
-		w.metrics.l1WatcherFetchContractEventSuccessTotal.Inc()
-		w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight))
+		w.trackBlock(uint64(to), blockHashes[uint64(to)])
+		w.processedEventHeights = processedHeights
+		w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight()))
+		if len(logs) > 0 {
+			w.metrics.l1WatcherFetchContractEventSuccessTotal.Inc()
+		}
 	}
 
 	return nil
 }
 
+// trackBlock records the hash of a processed block, so a later reorg of it can be detected.
+func (w *L1WatcherClient) trackBlock(number uint64, hash common.Hash) {
+	w.trackedBlocks = append(w.trackedBlocks, trackedL1Block{number: number, hash: hash})
+	if len(w.trackedBlocks) > maxTrackedL1Blocks {
+		w.trackedBlocks = w.trackedBlocks[len(w.trackedBlocks)-maxTrackedL1Blocks:]
+	}
+}
+
+// findForkPoint returns the height of the latest tracked block still on the canonical chain,
+// reorged is false when the latest tracked block is canonical.
+func (w *L1WatcherClient) findForkPoint() (forkPoint uint64, reorged bool, err error) {
+	for i := len(w.trackedBlocks) - 1; i >= 0; i-- {
+		tracked := w.trackedBlocks[i]
+		header, err := w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(tracked.number))
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to get block header, height: %v, err: %w", tracked.number, err)
+		}
+		if header.Hash() == tracked.hash {
+			return tracked.number, i != len(w.trackedBlocks)-1, nil
+		}
+	}
+
+	// every tracked block has been reorged, rescan from the oldest one since nothing older is known.
+	oldest := w.trackedBlocks[0].number
+	log.Error("L1 reorg deeper than the tracked blocks", "oldest tracked height", oldest, "tracked blocks", len(w.trackedBlocks))
+	if oldest == 0 {
+		return 0, true, nil
+	}
+	return oldest - 1, true, nil
+}
+
+// handleReorg detects whether the processed blocks left the canonical chain, then rolls back the
+// l1 messages and batch statuses of the reorged blocks and rescans from the fork point.
+func (w *L1WatcherClient) handleReorg() error {
+	if len(w.trackedBlocks) == 0 {
+		return nil
+	}
+
+	forkPoint, reorged, err := w.findForkPoint()
+	if err != nil || !reorged {
+		return err
+	}
+
+	forkHeader, err := w.client.HeaderByNumber(w.ctx, new(big.Int).SetUint64(forkPoint))
+	if err != nil {
+		return fmt.Errorf("failed to get fork point header, height: %v, err: %w", forkPoint, err)
+	}
+	// batches are updated after their events are mined, so the ones updated before the fork point block can't be affected.
+	batches, err := w.batchOrm.GetCommittedOrFinalizedBatchesSince(w.ctx, time.Unix(int64(forkHeader.Time), 0).UTC())
+	if err != nil {
+		return err
+	}
+
+	rollbackStatuses := make(map[string]types.RollupStatus)
+	for _, batch := range batches {
+		status, rollbackErr := w.rollbackRollupStatus(batch, forkPoint)
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+		if status != types.RollupStatus(batch.RollupStatus) {
+			rollbackStatuses[batch.Hash] = status
+		}
+	}
+
+	var deletedMessages int64
+	err = w.db.Transaction(func(dbTX *gorm.DB) error {
+		var deleteErr error
+		deletedMessages, deleteErr = w.l1MessageOrm.DeleteL1MessagesAboveHeight(w.ctx, forkPoint, dbTX)
+		if deleteErr != nil {
+			return deleteErr
+		}
+		if deleteErr = w.l1WatcherBlockOrm.DeleteL1WatcherBlocksAboveHeight(w.ctx, forkPoint, dbTX); deleteErr != nil {
+			return deleteErr
+		}
+		for hash, status := range rollbackStatuses {
+			if updateErr := w.batchOrm.UpdateRollupStatus(w.ctx, hash, status, dbTX); updateErr != nil {
+				return updateErr
+			}
+		}
+		for category, height := range w.processedEventHeights {
+			if height <= forkPoint {
+				continue
+			}
+			if updateErr := w.checkpointOrm.UpdateCheckpoint(w.ctx, l1EventCheckpointNames[category], forkPoint, forkHeader.Hash().String(), dbTX); updateErr != nil {
+				return updateErr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Warn("L1 reorg detected, rolled back to the fork point", "fork point", forkPoint, "processed height", w.processedMsgHeight(),
+		"deleted l1 messages", deletedMessages, "rolled back batches", len(rollbackStatuses))
+
+	for len(w.trackedBlocks) > 0 && w.trackedBlocks[len(w.trackedBlocks)-1].number > forkPoint {
+		w.trackedBlocks = w.trackedBlocks[:len(w.trackedBlocks)-1]
+	}
+	for category := range w.processedEventHeights {
+		if w.processedEventHeights[category] > forkPoint {
+			w.processedEventHeights[category] = forkPoint
+		}
+	}
+	w.metrics.l1WatcherReorgTotal.Inc()
+	w.metrics.l1WatcherFetchContractEventProcessedBlockHeight.Set(float64(w.processedMsgHeight()))
+	return nil
+}
+
+// rollbackRollupStatus returns the rollup status of the batch once the blocks after the fork point are dropped.
+// A transaction mined again after the fork point waits for its event to be rescanned, a dropped one is retried by the relayer.
+func (w *L1WatcherClient) rollbackRollupStatus(batch *orm.Batch, forkPoint uint64) (types.RollupStatus, error) {
+	status := types.RollupStatus(batch.RollupStatus)
+	if status == types.RollupFinalized {
+		minedAfterFork, dropped, err := w.checkTxAfterFork(batch.FinalizeTxHash, forkPoint)
+		if err != nil {
+			return status, err
+		}
+		if minedAfterFork {
+			return types.RollupFinalizing, nil
+		}
+		if !dropped {
+			return status, nil
+		}
+		status = types.RollupCommitted
+	}
+
+	minedAfterFork, dropped, err := w.checkTxAfterFork(batch.CommitTxHash, forkPoint)
+	if err != nil {
+		return status, err
+	}
+	if minedAfterFork {
+		return types.RollupCommitting, nil
+	}
+	if dropped {
+		return types.RollupCommitFailed, nil
+	}
+	return status, nil
+}
+
+func (w *L1WatcherClient) checkTxAfterFork(txHash string, forkPoint uint64) (minedAfterFork bool, dropped bool, err error) {
+	receipt, err := w.client.TransactionReceipt(w.ctx, common.HexToHash(txHash))
+	if errors.Is(err, geth.NotFound) {
+		return false, true, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get transaction receipt, tx hash: %v, err: %w", txHash, err)
+	}
+	return receipt.BlockNumber.Uint64() > forkPoint, false, nil
+}
+
 func (w *L1WatcherClient) parseBridgeEventLogs(logs []gethTypes.Log) ([]*orm.L1Message, []rollupEvent, error) {
 	// Need use contract abi to parse event Log
 	// Can only be tested after we have our contracts set up
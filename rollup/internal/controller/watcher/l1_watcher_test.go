@@ -30,7 +30,7 @@ func setupL1Watcher(t *testing.T) (*L1WatcherClient, *gorm.DB) {
 	client, err := testApps.GetPoSL1Client()
 	assert.NoError(t, err)
 	l1Cfg := cfg.L1Config
-	watcher := NewL1WatcherClient(context.Background(), client, l1Cfg.StartHeight, l1Cfg.Confirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.RelayerConfig.RollupContractAddress, db, nil)
+	watcher := NewL1WatcherClient(context.Background(), client, l1Cfg.StartHeight, l1Cfg.Confirmations, l1Cfg.EventConfirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.RelayerConfig.RollupContractAddress, db, nil)
 	assert.NoError(t, watcher.FetchContractEvent())
 	return watcher, db
 }
@@ -214,41 +214,41 @@ func testL1WatcherClientFetchContractEvent(t *testing.T) {
 
 	convey.Convey("db update RollupFinalized status failure", t, func() {
 		targetErr := errors.New("UpdateFinalizeTxHashAndRollupStatus RollupFinalized failure")
-		patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizeTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+		patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizeTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus, ...*gorm.DB) error {
 			return targetErr
 		})
 		err := watcher.FetchContractEvent()
 		assert.Equal(t, targetErr.Error(), err.Error())
 	})
 
-	patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizeTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+	patchGuard.ApplyMethodFunc(batchOrm, "UpdateFinalizeTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus, ...*gorm.DB) error {
 		return nil
 	})
 
 	convey.Convey("db update RollupCommitted status failure", t, func() {
 		targetErr := errors.New("UpdateCommitTxHashAndRollupStatus RollupCommitted failure")
-		patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommitTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+		patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommitTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus, ...*gorm.DB) error {
 			return targetErr
 		})
 		err := watcher.FetchContractEvent()
 		assert.Equal(t, targetErr.Error(), err.Error())
 	})
 
-	patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommitTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus) error {
+	patchGuard.ApplyMethodFunc(batchOrm, "UpdateCommitTxHashAndRollupStatus", func(context.Context, string, string, commonTypes.RollupStatus, ...*gorm.DB) error {
 		return nil
 	})
 
 	var l1MessageOrm *orm.L1Message
 	convey.Convey("db save l1 message failure", t, func() {
 		targetErr := errors.New("SaveL1Messages failure")
-		patchGuard.ApplyMethodFunc(l1MessageOrm, "SaveL1Messages", func(context.Context, []*orm.L1Message) error {
+		patchGuard.ApplyMethodFunc(l1MessageOrm, "SaveL1Messages", func(context.Context, []*orm.L1Message, ...*gorm.DB) error {
 			return targetErr
 		})
 		err := watcher.FetchContractEvent()
 		assert.Equal(t, targetErr.Error(), err.Error())
 	})
 
-	patchGuard.ApplyMethodFunc(l1MessageOrm, "SaveL1Messages", func(context.Context, []*orm.L1Message) error {
+	patchGuard.ApplyMethodFunc(l1MessageOrm, "SaveL1Messages", func(context.Context, []*orm.L1Message, ...*gorm.DB) error {
 		return nil
 	})
 
@@ -258,6 +258,125 @@ func testL1WatcherClientFetchContractEvent(t *testing.T) {
 	})
 }
 
+func testL1WatcherClientHandleReorg(t *testing.T) {
+	watcher, db := setupL1Watcher(t)
+	defer database.CloseDB(db)
+
+	canonicalHeader := func(number uint64) *types.Header {
+		return &types.Header{Number: new(big.Int).SetUint64(number), Time: 1}
+	}
+	reorgedHeader := func(number uint64) *types.Header {
+		header := canonicalHeader(number)
+		header.Extra = []byte("reorged")
+		return header
+	}
+
+	var c *ethclient.Client
+	patchGuard := gomonkey.ApplyMethodFunc(c, "HeaderByNumber", func(ctx context.Context, height *big.Int) (*types.Header, error) {
+		return canonicalHeader(height.Uint64()), nil
+	})
+	defer patchGuard.Reset()
+
+	l1MessageOrm := orm.NewL1Message(db)
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), []*orm.L1Message{
+		{QueueIndex: 1000000, MsgHash: "0x1", Height: 10},
+		{QueueIndex: 1000001, MsgHash: "0x2", Height: 25},
+	}))
+
+	convey.Convey("no reorg", t, func() {
+		watcher.trackedBlocks = []trackedL1Block{{10, canonicalHeader(10).Hash()}, {20, canonicalHeader(20).Hash()}}
+		watcher.processedEventHeights = [numL1EventCategories]uint64{20, 20, 5}
+		assert.NoError(t, watcher.handleReorg())
+		assert.Equal(t, [numL1EventCategories]uint64{20, 20, 5}, watcher.processedEventHeights)
+		assert.Len(t, watcher.trackedBlocks, 2)
+	})
+
+	convey.Convey("reorg rolls back to the fork point", t, func() {
+		watcher.trackedBlocks = []trackedL1Block{{10, canonicalHeader(10).Hash()}, {20, reorgedHeader(20).Hash()}, {30, reorgedHeader(30).Hash()}}
+		watcher.processedEventHeights = [numL1EventCategories]uint64{30, 30, 5}
+		assert.NoError(t, watcher.handleReorg())
+		assert.Equal(t, [numL1EventCategories]uint64{10, 10, 5}, watcher.processedEventHeights)
+		assert.Len(t, watcher.trackedBlocks, 1)
+
+		var count int64
+		assert.NoError(t, db.Model(&orm.L1Message{}).Where("queue_index IN ?", []uint64{1000000, 1000001}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+
+		// the checkpoints are rolled back with the events, a restarted watcher resumes from the fork point.
+		checkpoint, err := orm.NewWatcherCheckpoint(db).GetCheckpoint(context.Background(), l1EventCheckpointNames[l1EventDeposit])
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), checkpoint.BlockNumber)
+		assert.Equal(t, canonicalHeader(10).Hash().String(), checkpoint.BlockHash)
+
+		l1Cfg := cfg.L1Config
+		restarted := NewL1WatcherClient(context.Background(), watcher.client, l1Cfg.StartHeight, l1Cfg.Confirmations, l1Cfg.EventConfirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.RelayerConfig.RollupContractAddress, db, nil)
+		assert.Equal(t, uint64(10), restarted.processedEventHeights[l1EventDeposit])
+		assert.Equal(t, uint64(10), restarted.processedEventHeights[l1EventCommitBatch])
+		assert.Contains(t, restarted.trackedBlocks, trackedL1Block{10, canonicalHeader(10).Hash()})
+	})
+
+	convey.Convey("the tracked blocks survive a restart", t, func() {
+		// the checkpoints are at block 10, the block 20 processed after them is reorged while the watcher is down.
+		l1WatcherBlockOrm := orm.NewL1WatcherBlock(db)
+		assert.NoError(t, l1WatcherBlockOrm.InsertL1WatcherBlock(context.Background(), 10, canonicalHeader(10).Hash().String(), maxTrackedL1Blocks))
+		assert.NoError(t, l1WatcherBlockOrm.InsertL1WatcherBlock(context.Background(), 20, reorgedHeader(20).Hash().String(), maxTrackedL1Blocks))
+		assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), []*orm.L1Message{{QueueIndex: 1000002, MsgHash: "0x3", Height: 15}}))
+
+		l1Cfg := cfg.L1Config
+		restarted := NewL1WatcherClient(context.Background(), watcher.client, l1Cfg.StartHeight, l1Cfg.Confirmations, l1Cfg.EventConfirmations, l1Cfg.L1MessageQueueAddress, l1Cfg.RelayerConfig.RollupContractAddress, db, nil)
+		assert.Equal(t, []trackedL1Block{{10, canonicalHeader(10).Hash()}, {20, reorgedHeader(20).Hash()}}, restarted.trackedBlocks)
+
+		restarted.processedEventHeights = [numL1EventCategories]uint64{20, 20, 20}
+		assert.NoError(t, restarted.handleReorg())
+		assert.Equal(t, [numL1EventCategories]uint64{10, 10, 10}, restarted.processedEventHeights)
+		assert.Len(t, restarted.trackedBlocks, 1)
+
+		var count int64
+		assert.NoError(t, db.Model(&orm.L1Message{}).Where("queue_index = ?", 1000002).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+
+		// the reorged block isn't tracked again by the next restart.
+		blocks, err := l1WatcherBlockOrm.GetLatestL1WatcherBlocks(context.Background(), maxTrackedL1Blocks)
+		assert.NoError(t, err)
+		assert.Len(t, blocks, 1)
+		assert.Equal(t, uint64(10), blocks[0].Number)
+	})
+}
+
+func testL1WatcherClientFilterUnprocessedLogs(t *testing.T) {
+	watcher, db := setupL1Watcher(t)
+	defer database.CloseDB(db)
+
+	newLog := func(topic common.Hash, height uint64) types.Log {
+		return types.Log{Topics: []common.Hash{topic}, BlockNumber: height}
+	}
+	logs := []types.Log{
+		newLog(bridgeAbi.L1QueueTransactionEventSignature, 15),
+		newLog(bridgeAbi.L1QueueTransactionEventSignature, 25),
+		newLog(bridgeAbi.L1CommitBatchEventSignature, 15),
+		newLog(bridgeAbi.L1CommitBatchEventSignature, 25),
+		newLog(bridgeAbi.L1FinalizeBatchEventSignature, 15),
+		newLog(bridgeAbi.L1FinalizeBatchEventSignature, 25),
+		newLog(common.HexToHash("0x1"), 25),
+	}
+
+	// deposits are processed up to 20 and confirmed up to 30, the finalizations are only confirmed up to 20.
+	watcher.processedEventHeights = [numL1EventCategories]uint64{20, 10, 10}
+	confirmedHeights := [numL1EventCategories]uint64{30, 30, 20}
+	filtered := watcher.filterUnprocessedLogs(logs, confirmedHeights)
+	assert.Equal(t, []types.Log{
+		newLog(bridgeAbi.L1QueueTransactionEventSignature, 25),
+		newLog(bridgeAbi.L1CommitBatchEventSignature, 15),
+		newLog(bridgeAbi.L1CommitBatchEventSignature, 25),
+		newLog(bridgeAbi.L1FinalizeBatchEventSignature, 15),
+		newLog(common.HexToHash("0x1"), 25),
+	}, filtered)
+
+	watcher.processedEventHeights = watcher.nextProcessedHeights(30, confirmedHeights)
+	assert.Equal(t, [numL1EventCategories]uint64{30, 30, 20}, watcher.processedEventHeights)
+	assert.Equal(t, uint64(20), watcher.processedMsgHeight())
+}
+
 func testParseBridgeEventLogsL1QueueTransactionEventSignature(t *testing.T) {
 	watcher, db := setupL1Watcher(t)
 	defer database.CloseDB(db)
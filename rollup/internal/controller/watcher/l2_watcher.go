@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/scroll-tech/da-codec/encoding"
 	"github.com/scroll-tech/da-codec/encoding/codecv0"
+	geth "github.com/scroll-tech/go-ethereum"
 	"github.com/scroll-tech/go-ethereum/accounts/abi"
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/common/hexutil"
@@ -19,6 +20,7 @@ import (
 	"gorm.io/gorm"
 
 	bridgeAbi "scroll-tech/rollup/abi"
+	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
 )
 
@@ -29,7 +31,12 @@ type L2WatcherClient struct {
 
 	*ethclient.Client
 
-	l2BlockOrm *orm.L2Block
+	db                *gorm.DB
+	l2BlockOrm        *orm.L2Block
+	l1MessageOrm      *orm.L1Message
+	chunkOrm          *orm.Chunk
+	batchOrm          *orm.Batch
+	skippedMessageOrm *orm.SkippedMessage
 
 	confirmations rpc.BlockNumber
 
@@ -37,16 +44,41 @@ type L2WatcherClient struct {
 	messageQueueABI      *abi.ABI
 	withdrawTrieRootSlot common.Hash
 
+	// scrollMessengerAddress is the L2ScrollMessenger whose FailedRelayedMessage events are recorded
+	// as skipped messages, the zero address disables it.
+	scrollMessengerAddress common.Address
+
+	// unsupportedOpcodes is the set of opcodes the circuits cannot prove,
+	// block traces are only fetched and scanned when it is not empty or traceSchema is set.
+	unsupportedOpcodes map[string]struct{}
+
+	// traceSchema is the range of l2geth versions whose block traces match the schema of the circuits,
+	// nil doesn't check it.
+	traceSchema *config.TraceSchemaConfig
+
+	blockFetcher *blockFetcher
+
 	metrics *l2WatcherMetrics
 }
 
 // NewL2WatcherClient take a l2geth instance to generate a l2watcherclient instance
-func NewL2WatcherClient(ctx context.Context, client *ethclient.Client, confirmations rpc.BlockNumber, messageQueueAddress common.Address, withdrawTrieRootSlot common.Hash, db *gorm.DB, reg prometheus.Registerer) *L2WatcherClient {
+func NewL2WatcherClient(ctx context.Context, client *ethclient.Client, confirmations rpc.BlockNumber, messageQueueAddress common.Address, withdrawTrieRootSlot common.Hash, scrollMessengerAddress common.Address, unsupportedOpcodes []string, traceSchema *config.TraceSchemaConfig, fetcherCfg *config.BlockFetcherConfig, db *gorm.DB, reg prometheus.Registerer) *L2WatcherClient {
+	opcodes := make(map[string]struct{}, len(unsupportedOpcodes))
+	for _, op := range unsupportedOpcodes {
+		opcodes[canonicalOpcode(op)] = struct{}{}
+	}
+
+	metrics := initL2WatcherMetrics(reg)
 	return &L2WatcherClient{
 		ctx:    ctx,
 		Client: client,
 
-		l2BlockOrm: orm.NewL2Block(db),
+		db:                db,
+		l2BlockOrm:        orm.NewL2Block(db),
+		l1MessageOrm:      orm.NewL1Message(db),
+		chunkOrm:          orm.NewChunk(db),
+		batchOrm:          orm.NewBatch(db),
+		skippedMessageOrm: orm.NewSkippedMessage(db),
 
 		confirmations: confirmations,
 
@@ -54,12 +86,22 @@ func NewL2WatcherClient(ctx context.Context, client *ethclient.Client, confirmat
 		messageQueueABI:      bridgeAbi.L2MessageQueueABI,
 		withdrawTrieRootSlot: withdrawTrieRootSlot,
 
-		metrics: initL2WatcherMetrics(reg),
+		scrollMessengerAddress: scrollMessengerAddress,
+
+		unsupportedOpcodes: opcodes,
+		traceSchema:        traceSchema,
+
+		blockFetcher: newBlockFetcher(fetcherCfg, metrics.rollupL2WatcherFetchRetryTotal.Inc),
+
+		metrics: metrics,
 	}
 }
 
 const blocksFetchLimit = uint64(10)
 
+// maxL2ReorgDepth is the number of stored blocks searched for the common ancestor of an L2 reorg.
+const maxL2ReorgDepth = uint64(64)
+
 // TryFetchRunningMissingBlocks attempts to fetch and store block traces for any missing blocks.
 func (w *L2WatcherClient) TryFetchRunningMissingBlocks(blockHeight uint64) {
 	w.metrics.fetchRunningMissingBlocksTotal.Inc()
@@ -69,9 +111,16 @@ func (w *L2WatcherClient) TryFetchRunningMissingBlocks(blockHeight uint64) {
 		return
 	}
 
-	// Fetch and store block traces for missing blocks
-	for from := heightInDB + 1; from <= blockHeight; from += blocksFetchLimit {
-		to := from + blocksFetchLimit - 1
+	heightInDB, err = w.handleReorg(heightInDB)
+	if err != nil {
+		log.Error("failed to handle l2 reorg", "height", heightInDB, "err", err)
+		return
+	}
+
+	// Fetch and store block traces for missing blocks, every worker of the block fetcher gets blocksFetchLimit blocks per round.
+	fetchLimit := blocksFetchLimit * uint64(w.blockFetcher.workers)
+	for from := heightInDB + 1; from <= blockHeight; from += fetchLimit {
+		to := from + fetchLimit - 1
 
 		if to > blockHeight {
 			to = blockHeight
@@ -86,6 +135,101 @@ func (w *L2WatcherClient) TryFetchRunningMissingBlocks(blockHeight uint64) {
 	}
 }
 
+// handleReorg checks the stored blocks are still on the canonical chain. On a reorg, it rolls back
+// the blocks above the common ancestor, and returns the height of the ancestor to resume from.
+func (w *L2WatcherClient) handleReorg(heightInDB uint64) (uint64, error) {
+	if heightInDB == 0 {
+		return 0, nil
+	}
+
+	start := uint64(1)
+	if heightInDB > maxL2ReorgDepth {
+		start = heightInDB - maxL2ReorgDepth + 1
+	}
+	hashes, err := w.l2BlockOrm.GetL2BlockHashesInRange(w.ctx, start, heightInDB)
+	if err != nil {
+		return heightInDB, err
+	}
+
+	ancestor, found := uint64(0), start == 1
+	for number := heightInDB; number >= start; number-- {
+		hash, ok := hashes[number]
+		if !ok {
+			// the blocks of the finalized batches may have been pruned, they can't be reorged.
+			ancestor, found = number, true
+			break
+		}
+		header, err := w.HeaderByNumber(w.ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return heightInDB, fmt.Errorf("failed to get block header, height: %v, err: %w", number, err)
+		}
+		if header.Hash() == hash {
+			ancestor, found = number, true
+			break
+		}
+	}
+	if !found {
+		return heightInDB, fmt.Errorf("l2 reorg deeper than %v blocks below height %v, manual fix is needed", maxL2ReorgDepth, heightInDB)
+	}
+	if ancestor == heightInDB {
+		return heightInDB, nil
+	}
+
+	if err = w.rollbackAboveHeight(ancestor); err != nil {
+		return heightInDB, err
+	}
+	log.Warn("L2 reorg detected, rolled back to the common ancestor", "ancestor", ancestor, "stored height", heightInDB)
+	w.metrics.rollupL2WatcherReorgTotal.Inc()
+	return ancestor, nil
+}
+
+// rollbackAboveHeight deletes the blocks above the given height, with the chunks and batches containing them, in one transaction.
+// The batches are only deleted while none of them has been sent to L1, otherwise a manual fix is needed.
+func (w *L2WatcherClient) rollbackAboveHeight(height uint64) error {
+	return w.db.Transaction(func(dbTX *gorm.DB) error {
+		chunk, err := w.chunkOrm.GetFirstChunkEndingAboveHeight(w.ctx, height, dbTX)
+		if err != nil {
+			return err
+		}
+
+		if chunk != nil && chunk.BatchHash != "" {
+			batch, err := w.batchOrm.GetBatchByHash(w.ctx, chunk.BatchHash)
+			if err != nil {
+				return err
+			}
+			count, err := w.batchOrm.CountNonPendingBatchesGEIndex(w.ctx, batch.Index, dbTX)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("the batch of the reorged blocks has already been committed; height: %v, chunk index: %v, batch index: %v", height, chunk.Index, batch.Index)
+			}
+			if err = w.batchOrm.DeleteBatchesGEIndex(w.ctx, batch.Index, dbTX); err != nil {
+				return err
+			}
+			if err = w.chunkOrm.ResetBatchHashGEIndex(w.ctx, batch.StartChunkIndex, dbTX); err != nil {
+				return err
+			}
+		}
+
+		if chunk != nil {
+			if err = w.chunkOrm.DeleteChunksGEIndex(w.ctx, chunk.Index, dbTX); err != nil {
+				return err
+			}
+			if err = w.l2BlockOrm.ResetChunkHashGEHeight(w.ctx, chunk.StartBlockNumber, dbTX); err != nil {
+				return err
+			}
+		}
+
+		if err = w.skippedMessageOrm.DeleteSkippedMessagesAboveHeight(w.ctx, height, dbTX); err != nil {
+			return err
+		}
+
+		_, err = w.l2BlockOrm.DeleteL2BlocksAboveHeight(w.ctx, height, dbTX)
+		return err
+	})
+}
+
 func txsToTxsData(txs gethTypes.Transactions) []*gethTypes.TransactionData {
 	txsData := make([]*gethTypes.TransactionData, len(txs))
 	for i, tx := range txs {
@@ -122,44 +266,214 @@ func txsToTxsData(txs gethTypes.Transactions) []*gethTypes.TransactionData {
 	return txsData
 }
 
-func (w *L2WatcherClient) getAndStoreBlocks(ctx context.Context, from, to uint64) error {
-	var blocks []*encoding.Block
-	for number := from; number <= to; number++ {
-		log.Debug("retrieving block", "height", number)
-		block, err := w.GetBlockByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
+// traceHasUnsupportedOpcodes scans the execution results of a block trace and
+// returns a human-readable reason if any of them contains an unsupported opcode.
+// The trace must be normalized first.
+func (w *L2WatcherClient) traceHasUnsupportedOpcodes(trace *gethTypes.BlockTrace) (string, bool) {
+	for i, result := range trace.ExecutionResults {
+		for _, structLog := range result.StructLogs {
+			if _, ok := w.unsupportedOpcodes[structLog.Op]; ok {
+				txHash := ""
+				if i < len(trace.Transactions) {
+					txHash = trace.Transactions[i].TxHash
+				}
+				return fmt.Sprintf("unsupported opcode %s in tx %s", structLog.Op, txHash), true
+			}
+		}
+	}
+	return "", false
+}
+
+// fetchBlock fetches the block of the given height with its withdraw root, checks the l2geth version of its trace,
+// and scans the normalized trace for unsupported opcodes.
+func (w *L2WatcherClient) fetchBlock(ctx context.Context, number uint64) (*fetchedBlock, error) {
+	log.Debug("retrieving block", "height", number)
+	block, err := w.GetBlockByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(number)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetBlockByNumberOrHash: %v. number: %v", err, number)
+	}
+	if block.RowConsumption == nil {
+		return nil, fmt.Errorf("fetched block does not contain RowConsumption. number: %v", number)
+	}
+
+	log.Info("retrieved block", "height", block.Header().Number, "hash", block.Header().Hash().String())
+
+	withdrawRoot, err := w.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, big.NewInt(int64(number)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get withdrawRoot: %v. number: %v", err, number)
+	}
+
+	var skipReason string
+	if len(w.unsupportedOpcodes) > 0 || w.traceSchema != nil {
+		trace, err := w.GetBlockTraceByNumber(ctx, big.NewInt(int64(number)))
 		if err != nil {
-			return fmt.Errorf("failed to GetBlockByNumberOrHash: %v. number: %v", err, number)
+			return nil, fmt.Errorf("failed to GetBlockTraceByNumber: %v. number: %v", err, number)
 		}
-		if block.RowConsumption == nil {
-			return fmt.Errorf("fetched block does not contain RowConsumption. number: %v", number)
+		if w.traceSchema != nil {
+			if err := checkTraceVersion(w.traceSchema, trace.Version); err != nil {
+				w.metrics.rollupL2WatcherTraceSchemaMismatchTotal.Inc()
+				return nil, fmt.Errorf("block trace schema mismatch: %v. number: %v", err, number)
+			}
 		}
+		normalizeBlockTrace(trace, block.Header(), common.BytesToHash(withdrawRoot))
+		if reason, found := w.traceHasUnsupportedOpcodes(trace); found {
+			log.Warn("block contains unsupported opcodes, flagging it as skipped", "height", number, "reason", reason)
+			w.metrics.rollupL2BlocksUnsupportedOpcodesTotal.Inc()
+			skipReason = reason
+		}
+	}
 
-		log.Info("retrieved block", "height", block.Header().Number, "hash", block.Header().Hash().String())
-
-		withdrawRoot, err3 := w.StorageAt(ctx, w.messageQueueAddress, w.withdrawTrieRootSlot, big.NewInt(int64(number)))
-		if err3 != nil {
-			return fmt.Errorf("failed to get withdrawRoot: %v. number: %v", err3, number)
+	// the gas used by the l1 messages is only known from their receipts, it's recorded for the fee refunds.
+	l1MessageGasUsed := make(map[string]uint64)
+	for _, tx := range block.Transactions() {
+		if !tx.IsL1MessageTx() {
+			continue
 		}
-		blocks = append(blocks, &encoding.Block{
+		receipt, err := w.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get l1 message receipt: %v. number: %v, tx hash: %v", err, number, tx.Hash().String())
+		}
+		l1MessageGasUsed[tx.Hash().String()] = receipt.GasUsed
+	}
+
+	return &fetchedBlock{
+		block: &encoding.Block{
 			Header:         block.Header(),
 			Transactions:   txsToTxsData(block.Transactions()),
 			WithdrawRoot:   common.BytesToHash(withdrawRoot),
 			RowConsumption: block.RowConsumption,
-		})
+		},
+		skipReason:       skipReason,
+		l1MessageGasUsed: l1MessageGasUsed,
+	}, nil
+}
+
+// getAndStoreBlocks fetches the blocks of the range concurrently and stores them in order. On a failure, the
+// blocks below the failed one are still stored, so the stored blocks always form a contiguous range.
+func (w *L2WatcherClient) getAndStoreBlocks(ctx context.Context, from, to uint64) error {
+	var parentHash common.Hash
+	if from > 1 {
+		hashes, err := w.l2BlockOrm.GetL2BlockHashesInRange(ctx, from-1, from-1)
+		if err != nil {
+			return err
+		}
+		parentHash = hashes[from-1]
+	}
+
+	fetchedBlocks, fetchErr := w.blockFetcher.fetch(ctx, from, to, w.fetchBlock)
+
+	var blocks []*encoding.Block
+	skipReasons := make(map[uint64]string)
+	l1MessageGasUsed := make(map[string]uint64)
+	for _, fetched := range fetchedBlocks {
+		number := fetched.block.Header.Number.Uint64()
+		// the reorg is rolled back by handleReorg on the next fetch.
+		if parentHash != (common.Hash{}) && fetched.block.Header.ParentHash != parentHash {
+			fetchErr = fmt.Errorf("parent hash mismatch, l2 reorg detected. number: %v, parent hash: %v, expected: %v", number, fetched.block.Header.ParentHash.Hex(), parentHash.Hex())
+			break
+		}
+		parentHash = fetched.block.Header.Hash()
+
+		if fetched.skipReason != "" {
+			skipReasons[number] = fetched.skipReason
+		}
+		for txHash, gasUsed := range fetched.l1MessageGasUsed {
+			l1MessageGasUsed[txHash] = gasUsed
+		}
+		blocks = append(blocks, fetched.block)
 	}
 
 	if len(blocks) > 0 {
+		var l1MessageTxs []*gethTypes.TransactionData
 		for _, block := range blocks {
+			for _, tx := range block.Transactions {
+				if tx.Type == gethTypes.L1MessageTxType {
+					l1MessageTxs = append(l1MessageTxs, tx)
+				}
+			}
 			blockL1CommitCalldataSize, err := codecv0.EstimateBlockL1CommitCalldataSize(block)
 			if err != nil {
 				return fmt.Errorf("failed to estimate block L1 commit calldata size: %v", err)
 			}
 			w.metrics.rollupL2BlockL1CommitCalldataSize.Set(float64(blockL1CommitCalldataSize))
 		}
-		if err := w.l2BlockOrm.InsertL2Blocks(w.ctx, blocks); err != nil {
-			return fmt.Errorf("failed to batch insert BlockTraces: %v", err)
+		failedMessages, err := w.getFailedRelayedMessages(ctx, blocks, l1MessageTxs)
+		if err != nil {
+			return err
+		}
+		err = w.db.Transaction(func(dbTX *gorm.DB) error {
+			if insertErr := w.l2BlockOrm.InsertL2Blocks(w.ctx, blocks, dbTX); insertErr != nil {
+				return fmt.Errorf("failed to batch insert BlockTraces: %v", insertErr)
+			}
+			for number, reason := range skipReasons {
+				if updateErr := w.l2BlockOrm.UpdateSkipReason(w.ctx, number, reason, dbTX); updateErr != nil {
+					return fmt.Errorf("failed to update skip reason: %v. number: %v", updateErr, number)
+				}
+			}
+			for _, tx := range l1MessageTxs {
+				// the nonce of an L1MessageTx holds its queue index, see txsToTxsData.
+				if updateErr := w.l1MessageOrm.UpdateLayer2HashByQueueIndex(w.ctx, tx.Nonce, tx.TxHash, l1MessageGasUsed[tx.TxHash], dbTX); updateErr != nil {
+					return fmt.Errorf("failed to update l1 message layer2 hash: %v. queue index: %v", updateErr, tx.Nonce)
+				}
+			}
+			if insertErr := w.skippedMessageOrm.InsertSkippedMessages(w.ctx, failedMessages, dbTX); insertErr != nil {
+				return fmt.Errorf("failed to insert failed relayed messages: %v", insertErr)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(l1MessageTxs) > 0 {
+			w.metrics.rollupL2WatcherL1MessageQueueIndex.Set(float64(l1MessageTxs[len(l1MessageTxs)-1].Nonce))
 		}
+		for _, tx := range l1MessageTxs {
+			w.metrics.rollupL2WatcherL1MessageGasUsedTotal.Add(float64(l1MessageGasUsed[tx.TxHash]))
+		}
+		w.metrics.rollupL2WatcherFailedRelayedMessagesTotal.Add(float64(len(failedMessages)))
+	}
+
+	return fetchErr
+}
+
+// getFailedRelayedMessages returns the L1 messages of the blocks whose relay failed on L2, found from the
+// FailedRelayedMessage events the L2ScrollMessenger emitted in their L1MessageTxs.
+func (w *L2WatcherClient) getFailedRelayedMessages(ctx context.Context, blocks []*encoding.Block, l1MessageTxs []*gethTypes.TransactionData) ([]*orm.SkippedMessage, error) {
+	if w.scrollMessengerAddress == (common.Address{}) || len(l1MessageTxs) == 0 {
+		return nil, nil
+	}
+
+	query := geth.FilterQuery{
+		FromBlock: blocks[0].Header.Number,             // inclusive
+		ToBlock:   blocks[len(blocks)-1].Header.Number, // inclusive
+		Addresses: []common.Address{w.scrollMessengerAddress},
+		Topics:    [][]common.Hash{{bridgeAbi.L2FailedRelayedMessageEventSignature}},
+	}
+	logs, err := w.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FailedRelayedMessage event logs: %v. from: %v, to: %v", err, query.FromBlock, query.ToBlock)
+	}
+
+	l1MessageTxsByHash := make(map[common.Hash]*gethTypes.TransactionData, len(l1MessageTxs))
+	for _, tx := range l1MessageTxs {
+		l1MessageTxsByHash[common.HexToHash(tx.TxHash)] = tx
 	}
 
-	return nil
+	var failedMessages []*orm.SkippedMessage
+	for _, vLog := range logs {
+		tx, ok := l1MessageTxsByHash[vLog.TxHash]
+		if !ok || len(vLog.Topics) < 2 {
+			continue
+		}
+		log.Warn("l1 message relay failed on l2", "queue index", tx.Nonce, "msg hash", vLog.Topics[1].String(), "l2 tx hash", tx.TxHash, "l2 block", vLog.BlockNumber)
+		failedMessages = append(failedMessages, &orm.SkippedMessage{
+			// the nonce of an L1MessageTx holds its queue index, see txsToTxsData.
+			QueueIndex:    tx.Nonce,
+			MsgHash:       vLog.Topics[1].String(),
+			L2BlockNumber: vLog.BlockNumber,
+			Layer2Hash:    tx.TxHash,
+			Reason:        orm.SkippedMessageReasonRelayFailed,
+		})
+	}
+	return failedMessages, nil
 }
@@ -12,6 +12,16 @@ type l2WatcherMetrics struct {
 	fetchRunningMissingBlocksHeight   prometheus.Gauge
 	rollupL2BlocksFetchedGap          prometheus.Gauge
 	rollupL2BlockL1CommitCalldataSize prometheus.Gauge
+
+	rollupL2BlocksUnsupportedOpcodesTotal prometheus.Counter
+	rollupL2WatcherL1MessageQueueIndex    prometheus.Gauge
+	rollupL2WatcherReorgTotal             prometheus.Counter
+	rollupL2WatcherFetchRetryTotal        prometheus.Counter
+
+	rollupL2WatcherTraceSchemaMismatchTotal prometheus.Counter
+
+	rollupL2WatcherFailedRelayedMessagesTotal prometheus.Counter
+	rollupL2WatcherL1MessageGasUsedTotal      prometheus.Counter
 }
 
 var (
@@ -38,6 +48,34 @@ func initL2WatcherMetrics(reg prometheus.Registerer) *l2WatcherMetrics {
 				Name: "rollup_l2_block_l1_commit_calldata_size",
 				Help: "The l1 commitBatch calldata size of the l2 block",
 			}),
+			rollupL2BlocksUnsupportedOpcodesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_blocks_unsupported_opcodes_total",
+				Help: "The total number of l2 blocks flagged for containing unsupported opcodes",
+			}),
+			rollupL2WatcherL1MessageQueueIndex: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+				Name: "rollup_l2_watcher_l1_message_queue_index",
+				Help: "The queue index of the latest l1 message included in the fetched l2 blocks",
+			}),
+			rollupL2WatcherReorgTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_reorg_total",
+				Help: "The total number of l2 reorgs rolled back by the l2 watcher",
+			}),
+			rollupL2WatcherFetchRetryTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_fetch_retry_total",
+				Help: "The total number of block fetches retried by the l2 watcher",
+			}),
+			rollupL2WatcherTraceSchemaMismatchTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_trace_schema_mismatch_total",
+				Help: "The total number of block traces refused for an l2geth version out of the configured range",
+			}),
+			rollupL2WatcherFailedRelayedMessagesTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_failed_relayed_messages_total",
+				Help: "The total number of l1 messages whose relay failed on l2",
+			}),
+			rollupL2WatcherL1MessageGasUsedTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+				Name: "rollup_l2_watcher_l1_message_gas_used_total",
+				Help: "The total l2 gas used by the l1 messages included in the fetched l2 blocks",
+			}),
 		}
 	})
 	return l2WatcherMetric
@@ -2,25 +2,33 @@ package watcher
 
 import (
 	"context"
+	"math"
+	"math/big"
 	"testing"
 
 	"gorm.io/gorm"
 
+	"github.com/scroll-tech/da-codec/encoding"
 	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"github.com/stretchr/testify/assert"
 
 	"scroll-tech/common/database"
+	"scroll-tech/common/types"
 	cutils "scroll-tech/common/utils"
 
+	"scroll-tech/rollup/internal/config"
 	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
 )
 
 func setupL2Watcher(t *testing.T) (*L2WatcherClient, *gorm.DB) {
 	db := setupDB(t)
 	l2cfg := cfg.L2Config
-	watcher := NewL2WatcherClient(context.Background(), l2Cli, l2cfg.Confirmations, l2cfg.L2MessageQueueAddress, l2cfg.WithdrawTrieRootSlot, db, nil)
+	watcher := NewL2WatcherClient(context.Background(), l2Cli, l2cfg.Confirmations, l2cfg.L2MessageQueueAddress, l2cfg.WithdrawTrieRootSlot, l2cfg.L2ScrollMessengerAddress, l2cfg.UnsupportedOpcodes, l2cfg.TraceSchema, l2cfg.BlockFetcherConfig, db, nil)
 	return watcher, db
 }
 
@@ -44,5 +52,99 @@ func testFetchRunningMissingBlocks(t *testing.T) {
 
 func prepareWatcherClient(l2Cli *ethclient.Client, db *gorm.DB) *L2WatcherClient {
 	confirmations := rpc.LatestBlockNumber
-	return NewL2WatcherClient(context.Background(), l2Cli, confirmations, common.Address{}, common.Hash{}, db, nil)
+	return NewL2WatcherClient(context.Background(), l2Cli, confirmations, common.Address{}, common.Hash{}, common.Address{}, nil, nil, nil, db, nil)
+}
+
+func testL2WatcherRollbackAboveHeight(t *testing.T) {
+	db := setupDB(t)
+	defer database.CloseDB(db)
+
+	// Add genesis batch.
+	block := &encoding.Block{
+		Header: &gethTypes.Header{
+			Number: big.NewInt(0),
+		},
+		RowConsumption: &gethTypes.RowConsumption{},
+	}
+	chunk := &encoding.Chunk{
+		Blocks: []*encoding.Block{block},
+	}
+	chunkOrm := orm.NewChunk(db)
+	_, err := chunkOrm.InsertChunk(context.Background(), chunk, encoding.CodecV0, utils.ChunkMetrics{})
+	assert.NoError(t, err)
+	batch := &encoding.Batch{
+		Index:                      0,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.Hash{},
+		Chunks:                     []*encoding.Chunk{chunk},
+	}
+	batchOrm := orm.NewBatch(db)
+	_, err = batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+	assert.NoError(t, err)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	err = l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2})
+	assert.NoError(t, err)
+
+	cp := NewChunkProposer(context.Background(), &config.ChunkProposerConfig{
+		MaxBlockNumPerChunk:             1,
+		MaxTxNumPerChunk:                10000,
+		MaxL1CommitGasPerChunk:          50000000000,
+		MaxL1CommitCalldataSizePerChunk: 1000000,
+		MaxRowConsumptionPerChunk:       1000000,
+		ChunkTimeoutSec:                 300,
+		GasCostIncreaseMultiplier:       1.2,
+		MaxUncompressedBatchBytesSize:   math.MaxUint64,
+	}, &params.ChainConfig{}, db, nil)
+	cp.TryProposeChunk() // chunk1 contains block1
+	cp.TryProposeChunk() // chunk2 contains block2
+
+	bp := NewBatchProposer(context.Background(), &config.BatchProposerConfig{
+		MaxL1CommitGasPerBatch:          50000000000,
+		MaxL1CommitCalldataSizePerBatch: 1000000,
+		BatchTimeoutSec:                 0,
+		GasCostIncreaseMultiplier:       1.2,
+		MaxUncompressedBatchBytesSize:   math.MaxUint64,
+	}, &params.ChainConfig{}, db, nil)
+	bp.TryProposeBatch() // batch1 contains chunk1 and chunk2
+
+	dbBatch, err := batchOrm.GetBatchByIndex(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbBatch)
+
+	wc := prepareWatcherClient(l2Cli, db)
+
+	// the batch of the reorged blocks can't be deleted once it has been sent to L1.
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitting))
+	assert.Error(t, wc.rollbackAboveHeight(block1.Header.Number.Uint64()))
+	height, err := l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, block2.Header.Number.Uint64(), height)
+
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupPending))
+	assert.NoError(t, wc.rollbackAboveHeight(block1.Header.Number.Uint64()))
+
+	height, err = l2BlockOrm.GetL2BlocksLatestHeight(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, block1.Header.Number.Uint64(), height)
+
+	dbBatch, err = batchOrm.GetLatestBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), dbBatch.Index)
+
+	dbChunk, err := chunkOrm.GetChunkByIndex(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbChunk)
+	assert.Empty(t, dbChunk.BatchHash)
+	dbChunk, err = chunkOrm.GetChunkByIndex(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Nil(t, dbChunk)
+
+	// the blocks after the ancestor are chunked again.
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block2}))
+	cp.TryProposeChunk()
+	dbChunk, err = chunkOrm.GetChunkByIndex(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbChunk)
+	assert.Equal(t, block2.Header.Number.Uint64(), dbChunk.StartBlockNumber)
 }
@@ -0,0 +1,253 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"scroll-tech/common/objectstore"
+	"scroll-tech/common/utils"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+)
+
+const defaultPrunerMaxRowsPerTx = 100
+
+// Pruner deletes the block traces and clears the proofs of the long finalized batches,
+// optionally archiving them to an object storage first.
+type Pruner struct {
+	ctx context.Context
+	db  *gorm.DB
+
+	batchOrm          *orm.Batch
+	chunkOrm          *orm.Chunk
+	l2BlockOrm        *orm.L2Block
+	archivedObjectOrm *orm.ArchivedObject
+
+	cfg          *config.PrunerConfig
+	maxRowsPerTx int
+	archiveStore objectstore.Store
+
+	prunerRunTotal          prometheus.Counter
+	prunerFailureTotal      prometheus.Counter
+	prunerReclaimedRowTotal *prometheus.CounterVec
+	prunerArchivedRowTotal  *prometheus.CounterVec
+}
+
+// NewPruner creates a new Pruner instance.
+func NewPruner(ctx context.Context, cfg *config.PrunerConfig, db *gorm.DB, reg prometheus.Registerer) (*Pruner, error) {
+	archiveStore, err := objectstore.New(cfg.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pruner archive store: %w", err)
+	}
+
+	maxRowsPerTx := cfg.MaxRowsPerTx
+	if maxRowsPerTx <= 0 {
+		maxRowsPerTx = defaultPrunerMaxRowsPerTx
+	}
+
+	log.Debug("new pruner",
+		"l2BlockRetentionSec", cfg.L2BlockRetentionSec,
+		"chunkProofRetentionSec", cfg.ChunkProofRetentionSec,
+		"batchProofRetentionSec", cfg.BatchProofRetentionSec,
+		"maxRowsPerTx", maxRowsPerTx,
+		"archive", cfg.Archive != nil)
+
+	return &Pruner{
+		ctx:               ctx,
+		db:                db,
+		batchOrm:          orm.NewBatch(db),
+		chunkOrm:          orm.NewChunk(db),
+		l2BlockOrm:        orm.NewL2Block(db),
+		archivedObjectOrm: orm.NewArchivedObject(db),
+		cfg:               cfg,
+		maxRowsPerTx:      maxRowsPerTx,
+		archiveStore:      archiveStore,
+
+		prunerRunTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_pruner_run_total",
+			Help: "Total number of pruner runs.",
+		}),
+		prunerFailureTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "rollup_pruner_failure_total",
+			Help: "Total number of pruner run failures.",
+		}),
+		prunerReclaimedRowTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_pruner_reclaimed_row_total",
+			Help: "Total number of rows deleted or cleared by the pruner.",
+		}, []string{"table"}),
+		prunerArchivedRowTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "rollup_pruner_archived_row_total",
+			Help: "Total number of rows archived by the pruner.",
+		}, []string{"table"}),
+	}, nil
+}
+
+// TryPrune prunes the data out of the retention windows, a transaction of at most max_rows_per_tx rows at a time,
+// until there is nothing left to prune.
+func (p *Pruner) TryPrune() {
+	p.prunerRunTotal.Inc()
+
+	prunes := []struct {
+		retentionSec uint64
+		prune        func(finalizedBefore time.Time) (int, error)
+	}{
+		{p.cfg.L2BlockRetentionSec, p.pruneL2Blocks},
+		{p.cfg.ChunkProofRetentionSec, p.pruneChunkProofs},
+		{p.cfg.BatchProofRetentionSec, p.pruneBatchProofs},
+	}
+	for _, prune := range prunes {
+		if prune.retentionSec == 0 {
+			continue
+		}
+		finalizedBefore := utils.NowUTC().Add(-time.Duration(prune.retentionSec) * time.Second)
+		for p.ctx.Err() == nil {
+			pruned, err := prune.prune(finalizedBefore)
+			if err != nil {
+				p.prunerFailureTotal.Inc()
+				log.Error("failed to prune finalized data", "finalized before", finalizedBefore, "err", err)
+				break
+			}
+			if pruned < p.maxRowsPerTx {
+				break
+			}
+		}
+	}
+}
+
+func (p *Pruner) pruneL2Blocks(finalizedBefore time.Time) (int, error) {
+	l2Blocks, err := p.l2BlockOrm.GetL2BlocksOfBatchesFinalizedBefore(p.ctx, finalizedBefore, p.maxRowsPerTx)
+	if err != nil || len(l2Blocks) == 0 {
+		return 0, err
+	}
+
+	numbers := make([]uint64, len(l2Blocks))
+	var archivedObjects []*orm.ArchivedObject
+	for i, l2Block := range l2Blocks {
+		if p.archiveStore != nil {
+			data, err := json.Marshal(l2Block)
+			if err != nil {
+				return 0, fmt.Errorf("failed to marshal l2 block %d: %w", l2Block.Number, err)
+			}
+			archivedObject, err := p.archive(orm.ArchivedObjectKindL2Block, strconv.FormatUint(l2Block.Number, 10), data)
+			if err != nil {
+				return 0, err
+			}
+			archivedObjects = append(archivedObjects, archivedObject)
+		}
+		numbers[i] = l2Block.Number
+	}
+
+	var deleted int64
+	err = p.db.Transaction(func(dbTX *gorm.DB) error {
+		if insertErr := p.archivedObjectOrm.InsertArchivedObjects(p.ctx, archivedObjects, dbTX); insertErr != nil {
+			return insertErr
+		}
+		var deleteErr error
+		deleted, deleteErr = p.l2BlockOrm.DeleteL2BlocksByNumbers(p.ctx, numbers, dbTX)
+		return deleteErr
+	})
+	if err != nil {
+		return 0, err
+	}
+	p.prunerReclaimedRowTotal.WithLabelValues("l2_block").Add(float64(deleted))
+	log.Info("pruned l2 blocks", "from", numbers[0], "to", numbers[len(numbers)-1], "deleted", deleted)
+	return len(l2Blocks), nil
+}
+
+func (p *Pruner) pruneChunkProofs(finalizedBefore time.Time) (int, error) {
+	chunks, err := p.chunkOrm.GetChunkProofsOfBatchesFinalizedBefore(p.ctx, finalizedBefore, p.maxRowsPerTx)
+	if err != nil || len(chunks) == 0 {
+		return 0, err
+	}
+
+	hashes := make([]string, len(chunks))
+	var archivedObjects []*orm.ArchivedObject
+	for i, chunk := range chunks {
+		if p.archiveStore != nil {
+			archivedObject, err := p.archive(orm.ArchivedObjectKindChunkProof, chunk.Hash, chunk.Proof)
+			if err != nil {
+				return 0, err
+			}
+			archivedObjects = append(archivedObjects, archivedObject)
+		}
+		hashes[i] = chunk.Hash
+	}
+
+	var cleared int64
+	err = p.db.Transaction(func(dbTX *gorm.DB) error {
+		if insertErr := p.archivedObjectOrm.InsertArchivedObjects(p.ctx, archivedObjects, dbTX); insertErr != nil {
+			return insertErr
+		}
+		var clearErr error
+		cleared, clearErr = p.chunkOrm.ClearProofsByHashes(p.ctx, hashes, dbTX)
+		return clearErr
+	})
+	if err != nil {
+		return 0, err
+	}
+	p.prunerReclaimedRowTotal.WithLabelValues("chunk").Add(float64(cleared))
+	log.Info("pruned chunk proofs", "from", chunks[0].Index, "to", chunks[len(chunks)-1].Index, "cleared", cleared)
+	return len(chunks), nil
+}
+
+func (p *Pruner) pruneBatchProofs(finalizedBefore time.Time) (int, error) {
+	batches, err := p.batchOrm.GetBatchProofsFinalizedBefore(p.ctx, finalizedBefore, p.maxRowsPerTx)
+	if err != nil || len(batches) == 0 {
+		return 0, err
+	}
+
+	hashes := make([]string, len(batches))
+	var archivedObjects []*orm.ArchivedObject
+	for i, batch := range batches {
+		if p.archiveStore != nil {
+			archivedObject, err := p.archive(orm.ArchivedObjectKindBatchProof, batch.Hash, batch.Proof)
+			if err != nil {
+				return 0, err
+			}
+			archivedObjects = append(archivedObjects, archivedObject)
+		}
+		hashes[i] = batch.Hash
+	}
+
+	var cleared int64
+	err = p.db.Transaction(func(dbTX *gorm.DB) error {
+		if insertErr := p.archivedObjectOrm.InsertArchivedObjects(p.ctx, archivedObjects, dbTX); insertErr != nil {
+			return insertErr
+		}
+		var clearErr error
+		cleared, clearErr = p.batchOrm.ClearProofsByHashes(p.ctx, hashes, dbTX)
+		return clearErr
+	})
+	if err != nil {
+		return 0, err
+	}
+	p.prunerReclaimedRowTotal.WithLabelValues("batch").Add(float64(cleared))
+	log.Info("pruned batch proofs", "from", batches[0].Index, "to", batches[len(batches)-1].Index, "cleared", cleared)
+	return len(batches), nil
+}
+
+// archive uploads the pruned data under kind/key, and returns its index entry stored with the pruning.
+func (p *Pruner) archive(kind, key string, data []byte) (*orm.ArchivedObject, error) {
+	uri, err := p.archiveStore.Put(p.ctx, path.Join(kind, key), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive %s %s: %w", kind, key, err)
+	}
+	p.prunerArchivedRowTotal.WithLabelValues(kind).Inc()
+	return &orm.ArchivedObject{
+		Kind:        kind,
+		ObjectKey:   key,
+		URI:         uri,
+		ContentHash: objectstore.ContentHash(data),
+		Size:        uint64(len(data)),
+	}, nil
+}
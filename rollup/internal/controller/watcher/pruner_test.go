@@ -0,0 +1,104 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/objectstore"
+	"scroll-tech/common/types"
+
+	"scroll-tech/rollup/internal/config"
+	"scroll-tech/rollup/internal/orm"
+	"scroll-tech/rollup/internal/utils"
+)
+
+func testPrunerPruneFinalizedBatches(t *testing.T) {
+	db := setupDB(t)
+	defer database.CloseDB(db)
+
+	l2BlockOrm := orm.NewL2Block(db)
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2}))
+
+	chunkOrm := orm.NewChunk(db)
+	chunk := &encoding.Chunk{Blocks: []*encoding.Block{block1, block2}}
+	dbChunk, err := chunkOrm.InsertChunk(context.Background(), chunk, encoding.CodecV0, utils.ChunkMetrics{})
+	assert.NoError(t, err)
+	assert.NoError(t, l2BlockOrm.UpdateChunkHashInRange(context.Background(), block1.Header.Number.Uint64(), block2.Header.Number.Uint64(), dbChunk.Hash))
+
+	batchOrm := orm.NewBatch(db)
+	batch := &encoding.Batch{
+		Index:           0,
+		ParentBatchHash: common.Hash{},
+		Chunks:          []*encoding.Chunk{chunk},
+	}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+	assert.NoError(t, err)
+	assert.NoError(t, chunkOrm.UpdateBatchHashInRange(context.Background(), 0, 0, dbBatch.Hash))
+	assert.NoError(t, batchOrm.UpdateFinalizeTxHashAndRollupStatus(context.Background(), dbBatch.Hash, "finalizeTxHash", types.RollupFinalized))
+	assert.NoError(t, db.Exec("UPDATE chunk SET proof = ?", []byte("chunk proof")).Error)
+	assert.NoError(t, db.Exec("UPDATE batch SET proof = ?", []byte("batch proof")).Error)
+
+	archiveDir := t.TempDir()
+	prunerCfg := &config.PrunerConfig{
+		L2BlockRetentionSec:    3600,
+		ChunkProofRetentionSec: 3600,
+		BatchProofRetentionSec: 3600,
+		MaxRowsPerTx:           1,
+		Archive: &objectstore.Config{
+			Backend: objectstore.FilesystemBackend,
+			Dir:     archiveDir,
+		},
+	}
+	pruner, err := NewPruner(context.Background(), prunerCfg, db, nil)
+	assert.NoError(t, err)
+
+	// The batch is still within the retention windows.
+	pruner.TryPrune()
+	blocks, err := l2BlockOrm.GetL2BlocksGEHeight(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+
+	assert.NoError(t, db.Exec("UPDATE batch SET finalized_at = ?", time.Now().Add(-2*time.Hour)).Error)
+	pruner.TryPrune()
+
+	blocks, err = l2BlockOrm.GetL2BlocksGEHeight(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	chunks, err := chunkOrm.GetChunksInRange(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 1)
+	assert.Nil(t, chunks[0].Proof)
+
+	dbBatch, err = batchOrm.GetBatchByHash(context.Background(), dbBatch.Hash)
+	assert.NoError(t, err)
+	assert.Nil(t, dbBatch.Proof)
+
+	data, err := os.ReadFile(filepath.Join(archiveDir, "chunk_proof", dbChunk.Hash))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("chunk proof"), data)
+	data, err = os.ReadFile(filepath.Join(archiveDir, "batch_proof", dbBatch.Hash))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("batch proof"), data)
+
+	// the archived objects are indexed with their content hash.
+	archivedObjectOrm := orm.NewArchivedObject(db)
+	archivedProof, err := archivedObjectOrm.GetArchivedObject(context.Background(), orm.ArchivedObjectKindBatchProof, dbBatch.Hash)
+	assert.NoError(t, err)
+	assert.NotNil(t, archivedProof)
+	data, err = objectstore.Download(context.Background(), pruner.archiveStore, archivedProof.URI, archivedProof.ContentHash)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("batch proof"), data)
+	archivedBlocks, err := archivedObjectOrm.GetArchivedObjects(context.Background(), orm.ArchivedObjectKindL2Block,
+		[]string{block1.Header.Number.String(), block2.Header.Number.String()})
+	assert.NoError(t, err)
+	assert.Len(t, archivedBlocks, 2)
+}
@@ -0,0 +1,73 @@
+package watcher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+
+	"scroll-tech/common/version"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+// l2gethVersionRegexp matches the release of the version reported in the block traces, e.g. "5.3.6" of
+// "5.3.6-mainnet-a860446e", the older nodes prefix it with "l2geth/" or "v".
+var l2gethVersionRegexp = regexp.MustCompile(`^(?:l2geth/)?v?(\d+\.\d+\.\d+)`)
+
+// opcodeAliases maps the names the older l2geth versions give to some opcodes in the struct logs to the
+// names of the current versions, so the traces of every version are scanned the same way.
+var opcodeAliases = map[string]string{
+	"SHA3":    "KECCAK256",
+	"SUICIDE": "SELFDESTRUCT",
+}
+
+// canonicalOpcode returns the name of the opcode in the current l2geth versions.
+func canonicalOpcode(op string) string {
+	op = strings.ToUpper(op)
+	if alias, ok := opcodeAliases[op]; ok {
+		return alias
+	}
+	return op
+}
+
+// checkTraceVersion checks the l2geth version of the block trace is in the range whose traces match the schema
+// of the circuits.
+func checkTraceVersion(cfg *config.TraceSchemaConfig, traceVersion string) error {
+	matches := l2gethVersionRegexp.FindStringSubmatch(traceVersion)
+	if matches == nil {
+		return fmt.Errorf("unknown l2geth version %q of the block trace", traceVersion)
+	}
+	release := matches[1]
+	if cfg.MinL2gethVersion != "" && !version.CheckScrollRepoVersion(release, cfg.MinL2gethVersion) {
+		return fmt.Errorf("l2geth version %s of the block trace is older than %s", release, cfg.MinL2gethVersion)
+	}
+	if cfg.MaxL2gethVersion != "" && !version.CheckScrollRepoMaxVersion(release, cfg.MaxL2gethVersion) {
+		return fmt.Errorf("l2geth version %s of the block trace is newer than %s", release, cfg.MaxL2gethVersion)
+	}
+	return nil
+}
+
+// normalizeBlockTrace upgrades the block trace of an older l2geth version to the current schema: the opcodes
+// are renamed to their current names, and the header and withdraw trie root the older versions leave out
+// are filled from the block.
+func normalizeBlockTrace(trace *gethTypes.BlockTrace, header *gethTypes.Header, withdrawRoot common.Hash) {
+	if trace.Header == nil {
+		trace.Header = header
+	}
+	if trace.WithdrawTrieRoot == (common.Hash{}) {
+		trace.WithdrawTrieRoot = withdrawRoot
+	}
+	for _, result := range trace.ExecutionResults {
+		if result == nil {
+			continue
+		}
+		for _, structLog := range result.StructLogs {
+			if structLog != nil {
+				structLog.Op = canonicalOpcode(structLog.Op)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package watcher
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+
+	"scroll-tech/rollup/internal/config"
+)
+
+func TestCheckTraceVersion(t *testing.T) {
+	cfg := &config.TraceSchemaConfig{MinL2gethVersion: "5.3.0", MaxL2gethVersion: "5.5.0"}
+	assert.NoError(t, checkTraceVersion(cfg, "5.3.0-mainnet-a860446e"))
+	assert.NoError(t, checkTraceVersion(cfg, "5.5.0-mainnet-a860446e-20240426"))
+	assert.NoError(t, checkTraceVersion(cfg, "l2geth/v5.4.2-sepolia"))
+	assert.Error(t, checkTraceVersion(cfg, "5.2.9-mainnet-a860446e"))
+	assert.Error(t, checkTraceVersion(cfg, "5.5.1-mainnet"))
+	assert.Error(t, checkTraceVersion(cfg, ""))
+	assert.Error(t, checkTraceVersion(cfg, "1.0"))
+
+	// no max version.
+	assert.NoError(t, checkTraceVersion(&config.TraceSchemaConfig{MinL2gethVersion: "5.3.0"}, "6.0.0-mainnet"))
+}
+
+func TestNormalizeBlockTrace(t *testing.T) {
+	header := &gethTypes.Header{Number: big.NewInt(100)}
+	withdrawRoot := common.HexToHash("0x01")
+	trace := &gethTypes.BlockTrace{
+		ExecutionResults: []*gethTypes.ExecutionResult{
+			{StructLogs: []*gethTypes.StructLogRes{{Op: "PUSH1"}, {Op: "SHA3"}, {Op: "suicide"}}},
+		},
+	}
+	normalizeBlockTrace(trace, header, withdrawRoot)
+	assert.Equal(t, header, trace.Header)
+	assert.Equal(t, withdrawRoot, trace.WithdrawTrieRoot)
+	var ops []string
+	for _, structLog := range trace.ExecutionResults[0].StructLogs {
+		ops = append(ops, structLog.Op)
+	}
+	assert.Equal(t, []string{"PUSH1", "KECCAK256", "SELFDESTRUCT"}, ops)
+
+	// the fields of the current versions are kept.
+	traceHeader := &gethTypes.Header{Number: big.NewInt(100)}
+	trace = &gethTypes.BlockTrace{Header: traceHeader, WithdrawTrieRoot: common.HexToHash("0x02")}
+	normalizeBlockTrace(trace, header, withdrawRoot)
+	assert.Same(t, traceHeader, trace.Header)
+	assert.Equal(t, common.HexToHash("0x02"), trace.WithdrawTrieRoot)
+
+	// the unsupported opcodes match the traces of every version.
+	w := &L2WatcherClient{unsupportedOpcodes: map[string]struct{}{canonicalOpcode("sha3"): {}}}
+	trace = &gethTypes.BlockTrace{
+		Transactions:     []*gethTypes.TransactionData{{TxHash: "0xabc"}},
+		ExecutionResults: []*gethTypes.ExecutionResult{{StructLogs: []*gethTypes.StructLogRes{{Op: "KECCAK256"}}}},
+	}
+	normalizeBlockTrace(trace, header, withdrawRoot)
+	reason, found := w.traceHasUnsupportedOpcodes(trace)
+	assert.True(t, found)
+	assert.Equal(t, "unsupported opcode KECCAK256 in tx 0xabc", reason)
+}
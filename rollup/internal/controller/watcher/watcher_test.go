@@ -55,7 +55,7 @@ func setupEnv(t *testing.T) (err error) {
 	assert.NoError(t, err)
 	cfg.DBConfig = &database.Config{
 		DSN:        dsn,
-		DriverName: "postgres",
+		DriverName: testApps.GetDBDriverName(),
 		MaxOpenNum: 200,
 		MaxIdleNum: 20,
 	}
@@ -97,12 +97,15 @@ func TestFunction(t *testing.T) {
 	t.Run("TestStartWatcher", testFetchContractEvent)
 	t.Run("TestL1WatcherClientFetchBlockHeader", testL1WatcherClientFetchBlockHeader)
 	t.Run("TestL1WatcherClientFetchContractEvent", testL1WatcherClientFetchContractEvent)
+	t.Run("TestL1WatcherClientHandleReorg", testL1WatcherClientHandleReorg)
+	t.Run("TestL1WatcherClientFilterUnprocessedLogs", testL1WatcherClientFilterUnprocessedLogs)
 	t.Run("TestParseBridgeEventLogsL1QueueTransactionEventSignature", testParseBridgeEventLogsL1QueueTransactionEventSignature)
 	t.Run("TestParseBridgeEventLogsL1CommitBatchEventSignature", testParseBridgeEventLogsL1CommitBatchEventSignature)
 	t.Run("TestParseBridgeEventLogsL1FinalizeBatchEventSignature", testParseBridgeEventLogsL1FinalizeBatchEventSignature)
 
 	// Run l2 watcher test cases.
 	t.Run("TestFetchRunningMissingBlocks", testFetchRunningMissingBlocks)
+	t.Run("TestL2WatcherRollbackAboveHeight", testL2WatcherRollbackAboveHeight)
 
 	// Run chunk proposer test cases.
 	t.Run("TestChunkProposerCodecv0Limits", testChunkProposerCodecv0Limits)
@@ -120,6 +123,11 @@ func TestFunction(t *testing.T) {
 	t.Run("TestBatchCommitGasAndCalldataSizeCodecv2Estimation", testBatchCommitGasAndCalldataSizeCodecv2Estimation)
 	t.Run("TestBatchProposerBlobSizeLimit", testBatchProposerBlobSizeLimit)
 	t.Run("TestBatchProposerMaxChunkNumPerBatchLimit", testBatchProposerMaxChunkNumPerBatchLimit)
+	t.Run("TestBatchProposerL1MessageInclusionDeadline", testBatchProposerL1MessageInclusionDeadline)
+	t.Run("TestBatchProposerProvingBacklog", testBatchProposerProvingBacklog)
+
+	// Run pruner test cases.
+	t.Run("TestPrunerPruneFinalizedBatches", testPrunerPruneFinalizedBatches)
 }
 
 func readBlockFromJSON(t *testing.T, filename string) *encoding.Block {
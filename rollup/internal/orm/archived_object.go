@@ -0,0 +1,100 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// ArchivedObjectKindL2Block the block trace of an L2 block, keyed by the block number.
+	ArchivedObjectKindL2Block = "l2_block"
+	// ArchivedObjectKindChunkProof the proof of a chunk, keyed by the chunk hash.
+	ArchivedObjectKindChunkProof = "chunk_proof"
+	// ArchivedObjectKindBatchProof the proof of a batch, keyed by the batch hash.
+	ArchivedObjectKindBatchProof = "batch_proof"
+)
+
+// ArchivedObject indexes the data the pruner moved to the archive storage, it's read back from the uri.
+type ArchivedObject struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID          uint   `json:"id" gorm:"column:id;primaryKey"`
+	Kind        string `json:"kind" gorm:"column:kind"`
+	ObjectKey   string `json:"object_key" gorm:"column:object_key"`
+	URI         string `json:"uri" gorm:"column:uri"`
+	ContentHash string `json:"content_hash" gorm:"column:content_hash"`
+	Size        uint64 `json:"size" gorm:"column:size"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewArchivedObject creates a new ArchivedObject instance.
+func NewArchivedObject(db *gorm.DB) *ArchivedObject {
+	return &ArchivedObject{db: db}
+}
+
+// TableName returns the name of the "archived_object" table.
+func (*ArchivedObject) TableName() string {
+	return "archived_object"
+}
+
+// GetArchivedObjects retrieves the archived objects of the kind with one of the keys.
+func (o *ArchivedObject) GetArchivedObjects(ctx context.Context, kind string, keys []string) ([]ArchivedObject, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&ArchivedObject{})
+	db = db.Where("kind = ? AND object_key IN ?", kind, keys)
+	db = db.Order("id ASC")
+
+	var archivedObjects []ArchivedObject
+	if err := db.Find(&archivedObjects).Error; err != nil {
+		return nil, fmt.Errorf("ArchivedObject.GetArchivedObjects error: %w, kind: %v, key count: %v", err, kind, len(keys))
+	}
+	return archivedObjects, nil
+}
+
+// GetArchivedObject retrieves the archived object of the kind and key, it returns nil if it's not archived.
+func (o *ArchivedObject) GetArchivedObject(ctx context.Context, kind, key string) (*ArchivedObject, error) {
+	archivedObjects, err := o.GetArchivedObjects(ctx, kind, []string{key})
+	if err != nil {
+		return nil, err
+	}
+	if len(archivedObjects) == 0 {
+		return nil, nil
+	}
+	return &archivedObjects[0], nil
+}
+
+// InsertArchivedObjects indexes the archived objects, an object archived again points to its latest copy.
+func (o *ArchivedObject) InsertArchivedObjects(ctx context.Context, archivedObjects []*ArchivedObject, dbTX ...*gorm.DB) error {
+	if len(archivedObjects) == 0 {
+		return nil
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&ArchivedObject{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "kind"}, {Name: "object_key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"uri", "content_hash", "size", "updated_at"}),
+	})
+
+	if err := db.Create(&archivedObjects).Error; err != nil {
+		return fmt.Errorf("ArchivedObject.InsertArchivedObjects error: %w, kind: %v, first key: %v", err, archivedObjects[0].Kind, archivedObjects[0].ObjectKey)
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
+	"scroll-tech/common/objectstore"
 	"scroll-tech/common/types"
 	"scroll-tech/common/types/message"
 	"scroll-tech/common/utils"
@@ -20,7 +21,8 @@ import (
 
 // Batch represents a batch of chunks.
 type Batch struct {
-	db *gorm.DB `gorm:"column:-"`
+	db         *gorm.DB          `gorm:"column:-"`
+	proofStore objectstore.Store `gorm:"-"`
 
 	// batch
 	Index           uint64 `json:"index" gorm:"column:index"`
@@ -39,6 +41,8 @@ type Batch struct {
 	ChunkProofsStatus int16      `json:"chunk_proofs_status" gorm:"column:chunk_proofs_status;default:1"`
 	ProvingStatus     int16      `json:"proving_status" gorm:"column:proving_status;default:1"`
 	Proof             []byte     `json:"proof" gorm:"column:proof;default:NULL"`
+	ProofHash         string     `json:"proof_hash" gorm:"column:proof_hash;default:NULL"`
+	ProofURI          string     `json:"proof_uri" gorm:"column:proof_uri;default:NULL"`
 	ProverAssignedAt  *time.Time `json:"prover_assigned_at" gorm:"column:prover_assigned_at;default:NULL"`
 	ProvedAt          *time.Time `json:"proved_at" gorm:"column:proved_at;default:NULL"`
 	ProofTimeSec      int32      `json:"proof_time_sec" gorm:"column:proof_time_sec;default:NULL"`
@@ -49,6 +53,11 @@ type Batch struct {
 	CommittedAt    *time.Time `json:"committed_at" gorm:"column:committed_at;default:NULL"`
 	FinalizeTxHash string     `json:"finalize_tx_hash" gorm:"column:finalize_tx_hash;default:NULL"`
 	FinalizedAt    *time.Time `json:"finalized_at" gorm:"column:finalized_at;default:NULL"`
+	// FinalizeAttempts counts the failed finalize transactions, NextFinalizeAt is when the next one is allowed.
+	FinalizeAttempts int16      `json:"finalize_attempts" gorm:"column:finalize_attempts;default:0"`
+	NextFinalizeAt   *time.Time `json:"next_finalize_at" gorm:"column:next_finalize_at;default:NULL"`
+	// RevertReason is the decoded revert reason of the last commit or finalize transaction that failed the simulation or reverted on L1.
+	RevertReason string `json:"revert_reason" gorm:"column:revert_reason;default:NULL"`
 
 	// gas oracle
 	OracleStatus int16  `json:"oracle_status" gorm:"column:oracle_status;default:1"`
@@ -71,6 +80,12 @@ func NewBatch(db *gorm.DB) *Batch {
 	return &Batch{db: db}
 }
 
+// WithProofStore reads the proofs offloaded by the coordinator from the proof store.
+func (o *Batch) WithProofStore(store objectstore.Store) *Batch {
+	o.proofStore = store
+	return o
+}
+
 // TableName returns the table name for the Batch model.
 func (*Batch) TableName() string {
 	return "batch"
@@ -94,7 +109,7 @@ func (o *Batch) GetBatches(ctx context.Context, fields map[string]interface{}, o
 		db = db.Limit(limit)
 	}
 
-	db = db.Order("index ASC")
+	db = db.Order(`"index" ASC`)
 
 	var batches []*Batch
 	if err := db.Find(&batches).Error; err != nil {
@@ -103,6 +118,17 @@ func (o *Batch) GetBatches(ctx context.Context, fields map[string]interface{}, o
 	return batches, nil
 }
 
+// GetBatchesPage retrieves the page of the batches after the cursor batch index, ordered by index.
+func (o *Batch) GetBatchesPage(ctx context.Context, cursor *uint64, limit int) (*Page[Batch], error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	page, err := findPage(db, `"index"`, cursor, false, limit, func(b *Batch) uint64 { return b.Index })
+	if err != nil {
+		return nil, fmt.Errorf("Batch.GetBatchesPage error: %w, limit: %v", err, limit)
+	}
+	return page, nil
+}
+
 // GetBatchCount retrieves the total number of batches in the database.
 func (o *Batch) GetBatchCount(ctx context.Context) (uint64, error) {
 	db := o.db.WithContext(ctx)
@@ -115,11 +141,24 @@ func (o *Batch) GetBatchCount(ctx context.Context) (uint64, error) {
 	return uint64(count), nil
 }
 
+// CountUnprovenBatches counts the batches waiting for their proof, unassigned or assigned to a prover.
+func (o *Batch) CountUnprovenBatches(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("proving_status IN ?", []int{int(types.ProvingTaskUnassigned), int(types.ProvingTaskAssigned)})
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Batch.CountUnprovenBatches error: %w", err)
+	}
+	return uint64(count), nil
+}
+
 // GetVerifiedProofByHash retrieves the verified aggregate proof for a batch with the given hash.
 func (o *Batch) GetVerifiedProofByHash(ctx context.Context, hash string) (*message.BatchProof, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Batch{})
-	db = db.Select("proof")
+	db = db.Select("proof, proof_hash, proof_uri")
 	db = db.Where("hash = ? AND proving_status = ?", hash, types.ProvingTaskVerified)
 
 	var batch Batch
@@ -127,8 +166,16 @@ func (o *Batch) GetVerifiedProofByHash(ctx context.Context, hash string) (*messa
 		return nil, fmt.Errorf("Batch.GetVerifiedProofByHash error: %w, batch hash: %v", err, hash)
 	}
 
+	proofBytes := batch.Proof
+	if batch.ProofURI != "" {
+		var err error
+		if proofBytes, err = objectstore.Download(ctx, o.proofStore, batch.ProofURI, batch.ProofHash); err != nil {
+			return nil, fmt.Errorf("Batch.GetVerifiedProofByHash error: %w, batch hash: %v", err, hash)
+		}
+	}
+
 	var proof message.BatchProof
-	if err := json.Unmarshal(batch.Proof, &proof); err != nil {
+	if err := json.Unmarshal(proofBytes, &proof); err != nil {
 		return nil, fmt.Errorf("Batch.GetVerifiedProofByHash error: %w, batch hash: %v", err, hash)
 	}
 	return &proof, nil
@@ -138,7 +185,7 @@ func (o *Batch) GetVerifiedProofByHash(ctx context.Context, hash string) (*messa
 func (o *Batch) GetLatestBatch(ctx context.Context) (*Batch, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Batch{})
-	db = db.Order("index desc")
+	db = db.Order(`"index" desc`)
 
 	var latestBatch Batch
 	if err := db.First(&latestBatch).Error; err != nil {
@@ -147,6 +194,24 @@ func (o *Batch) GetLatestBatch(ctx context.Context) (*Batch, error) {
 	return &latestBatch, nil
 }
 
+// GetLatestBatchByRollupStatus retrieves the batch of the highest index in one of the rollup statuses,
+// it returns nil if there is no such batch.
+func (o *Batch) GetLatestBatchByRollupStatus(ctx context.Context, statuses []types.RollupStatus) (*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("rollup_status IN ?", statuses)
+	db = db.Order(`"index" desc`)
+
+	var batches []*Batch
+	if err := db.Limit(1).Find(&batches).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetLatestBatchByRollupStatus error: %w, statuses: %v", err, statuses)
+	}
+	if len(batches) == 0 {
+		return nil, nil
+	}
+	return batches[0], nil
+}
+
 // GetFirstUnbatchedChunkIndex retrieves the first unbatched chunk index.
 func (o *Batch) GetFirstUnbatchedChunkIndex(ctx context.Context) (uint64, error) {
 	// Get the latest batch
@@ -200,7 +265,7 @@ func (o *Batch) GetFailedAndPendingBatches(ctx context.Context, limit int) ([]*B
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Batch{})
 	db = db.Where("rollup_status = ? OR rollup_status = ?", types.RollupCommitFailed, types.RollupPending)
-	db = db.Order("index ASC")
+	db = db.Order(`"index" ASC`)
 	db = db.Limit(limit)
 
 	var batches []*Batch
@@ -210,11 +275,101 @@ func (o *Batch) GetFailedAndPendingBatches(ctx context.Context, limit int) ([]*B
 	return batches, nil
 }
 
+// GetCommittedOrFinalizedBatchesSince retrieves the committed or finalized batches whose status was set at or after the given time.
+func (o *Batch) GetCommittedOrFinalizedBatchesSince(ctx context.Context, since time.Time) ([]*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("(rollup_status = ? AND committed_at >= ?) OR (rollup_status = ? AND finalized_at >= ?)", types.RollupCommitted, since, types.RollupFinalized, since)
+	db = db.Order(`"index" ASC`)
+
+	var batches []*Batch
+	if err := db.Find(&batches).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetCommittedOrFinalizedBatchesSince error: %w, since: %v", err, since)
+	}
+	return batches, nil
+}
+
+// GetLatestFinalizedBatches retrieves the rollup fields of the latest finalized batches, ordered by index in descending order.
+func (o *Batch) GetLatestFinalizedBatches(ctx context.Context, limit int) ([]*Batch, error) {
+	if limit <= 0 {
+		return nil, errors.New("limit must be greater than zero")
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Select(`"index", hash, start_chunk_index, end_chunk_index, commit_tx_hash, finalize_tx_hash`)
+	db = db.Where("rollup_status = ?", types.RollupFinalized)
+	db = db.Order(`"index" DESC`)
+	db = db.Limit(limit)
+
+	var batches []*Batch
+	if err := db.Find(&batches).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetLatestFinalizedBatches error: %w, limit: %v", err, limit)
+	}
+	return batches, nil
+}
+
+// GetBatchCountsByCommitTxHashes counts the batches committed by each of the commit transactions.
+func (o *Batch) GetBatchCountsByCommitTxHashes(ctx context.Context, txHashes []string) (map[string]uint64, error) {
+	counts, err := o.getBatchCountsByTxHashes(ctx, "commit_tx_hash", txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("Batch.GetBatchCountsByCommitTxHashes error: %w, txHashes: %v", err, txHashes)
+	}
+	return counts, nil
+}
+
+// GetBatchCountsByFinalizeTxHashes counts the batches finalized by each of the finalize transactions.
+func (o *Batch) GetBatchCountsByFinalizeTxHashes(ctx context.Context, txHashes []string) (map[string]uint64, error) {
+	counts, err := o.getBatchCountsByTxHashes(ctx, "finalize_tx_hash", txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("Batch.GetBatchCountsByFinalizeTxHashes error: %w, txHashes: %v", err, txHashes)
+	}
+	return counts, nil
+}
+
+func (o *Batch) getBatchCountsByTxHashes(ctx context.Context, column string, txHashes []string) (map[string]uint64, error) {
+	counts := make(map[string]uint64, len(txHashes))
+	if len(txHashes) == 0 {
+		return counts, nil
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Select(column + " AS tx_hash, COUNT(*) AS count")
+	db = db.Where(column+" IN ?", txHashes)
+	db = db.Group(column)
+
+	var rows []struct {
+		TxHash string
+		Count  uint64
+	}
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TxHash] = row.Count
+	}
+	return counts, nil
+}
+
+// GetBatchByHash retrieves the batch by the given hash.
+func (o *Batch) GetBatchByHash(ctx context.Context, hash string) (*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash = ?", hash)
+
+	var batch Batch
+	if err := db.First(&batch).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetBatchByHash error: %w, batch hash: %v", err, hash)
+	}
+	return &batch, nil
+}
+
 // GetBatchByIndex retrieves the batch by the given index.
 func (o *Batch) GetBatchByIndex(ctx context.Context, index uint64) (*Batch, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Batch{})
-	db = db.Where("index = ?", index)
+	db = db.Where(`"index" = ?`, index)
 
 	var batch Batch
 	if err := db.First(&batch).Error; err != nil {
@@ -359,7 +514,7 @@ func (o *Batch) UpdateRollupStatus(ctx context.Context, hash string, status type
 }
 
 // UpdateCommitTxHashAndRollupStatus updates the commit transaction hash and rollup status for a batch.
-func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash string, commitTxHash string, status types.RollupStatus) error {
+func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash string, commitTxHash string, status types.RollupStatus, dbTX ...*gorm.DB) error {
 	updateFields := make(map[string]interface{})
 	updateFields["commit_tx_hash"] = commitTxHash
 	updateFields["rollup_status"] = int(status)
@@ -367,7 +522,11 @@ func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash stri
 		updateFields["committed_at"] = utils.NowUTC()
 	}
 
-	db := o.db.WithContext(ctx)
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Batch{})
 	db = db.Where("hash", hash)
 
@@ -378,7 +537,7 @@ func (o *Batch) UpdateCommitTxHashAndRollupStatus(ctx context.Context, hash stri
 }
 
 // UpdateFinalizeTxHashAndRollupStatus updates the finalize transaction hash and rollup status for a batch.
-func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash string, finalizeTxHash string, status types.RollupStatus) error {
+func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash string, finalizeTxHash string, status types.RollupStatus, dbTX ...*gorm.DB) error {
 	updateFields := make(map[string]interface{})
 	updateFields["finalize_tx_hash"] = finalizeTxHash
 	updateFields["rollup_status"] = int(status)
@@ -386,7 +545,11 @@ func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash st
 		updateFields["finalized_at"] = time.Now()
 	}
 
-	db := o.db.WithContext(ctx)
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Batch{})
 	db = db.Where("hash", hash)
 
@@ -396,6 +559,65 @@ func (o *Batch) UpdateFinalizeTxHashAndRollupStatus(ctx context.Context, hash st
 	return nil
 }
 
+// UpdateFinalizeTxHashAndRollupStatusByHashes updates the finalize transaction hash and rollup status of the batches
+// finalized in the same transaction, in a single statement so they are all updated or none.
+func (o *Batch) UpdateFinalizeTxHashAndRollupStatusByHashes(ctx context.Context, hashes []string, finalizeTxHash string, status types.RollupStatus, dbTX ...*gorm.DB) error {
+	updateFields := make(map[string]interface{})
+	updateFields["finalize_tx_hash"] = finalizeTxHash
+	updateFields["rollup_status"] = int(status)
+	if status == types.RollupFinalized {
+		updateFields["finalized_at"] = time.Now()
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash IN ?", hashes)
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateFinalizeTxHashAndRollupStatusByHashes error: %w, batch hashes: %v, status: %v, finalizeTxHash: %v", err, hashes, status.String(), finalizeTxHash)
+	}
+	return nil
+}
+
+// UpdateFinalizeAttempts records a failed finalize transaction of the batch, setting its rollup status,
+// the number of failed attempts and the earliest time of the next attempt.
+func (o *Batch) UpdateFinalizeAttempts(ctx context.Context, hash string, finalizeTxHash string, status types.RollupStatus, attempts int16, nextFinalizeAt time.Time) error {
+	updateFields := make(map[string]interface{})
+	updateFields["finalize_tx_hash"] = finalizeTxHash
+	updateFields["rollup_status"] = int(status)
+	updateFields["finalize_attempts"] = attempts
+	updateFields["next_finalize_at"] = nextFinalizeAt
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash", hash)
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateFinalizeAttempts error: %w, batch hash: %v, status: %v, attempts: %v", err, hash, status.String(), attempts)
+	}
+	return nil
+}
+
+// UpdateRevertReasonByHashes records the revert reason of the failed commit or finalize transaction of the batches.
+func (o *Batch) UpdateRevertReasonByHashes(ctx context.Context, hashes []string, revertReason string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash IN ?", hashes)
+
+	if err := db.Update("revert_reason", revertReason).Error; err != nil {
+		return fmt.Errorf("Batch.UpdateRevertReasonByHashes error: %w, batch hashes: %v, revert reason: %v", err, hashes, revertReason)
+	}
+	return nil
+}
+
 // UpdateProofByHash updates the batch proof by hash.
 // for unit test.
 func (o *Batch) UpdateProofByHash(ctx context.Context, hash string, proof *message.BatchProof, proofTimeSec uint64) error {
@@ -417,3 +639,72 @@ func (o *Batch) UpdateProofByHash(ctx context.Context, hash string, proof *messa
 	}
 	return nil
 }
+
+// CountNonPendingBatchesGEIndex counts the batches with an index greater than or equal to the given index
+// whose rollup status is not pending, i.e. whose commit transaction may have been sent.
+func (o *Batch) CountNonPendingBatchesGEIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where(`"index" >= ?`, index)
+	db = db.Where("rollup_status != ?", int(types.RollupPending))
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Batch.CountNonPendingBatchesGEIndex error: %w, index: %v", err, index)
+	}
+	return count, nil
+}
+
+// DeleteBatchesGEIndex deletes the batches with an index greater than or equal to the given index.
+func (o *Batch) DeleteBatchesGEIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Where(`"index" >= ?`, index)
+
+	if err := db.Delete(&Batch{}).Error; err != nil {
+		return fmt.Errorf("Batch.DeleteBatchesGEIndex error: %w, index: %v", err, index)
+	}
+	return nil
+}
+
+// GetBatchProofsFinalizedBefore retrieves the batches which still keep their proof in the database
+// and were finalized before the given time, sorted in ascending order by their index.
+func (o *Batch) GetBatchProofsFinalizedBefore(ctx context.Context, finalizedBefore time.Time, limit int) ([]*Batch, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Select(`"index", hash, proof`)
+	db = db.Where("proof IS NOT NULL")
+	db = db.Where("rollup_status = ? AND finalized_at < ?", int(types.RollupFinalized), finalizedBefore)
+	db = db.Order(`"index" ASC`)
+	db = db.Limit(limit)
+
+	var batches []*Batch
+	if err := db.Find(&batches).Error; err != nil {
+		return nil, fmt.Errorf("Batch.GetBatchProofsFinalizedBefore error: %w, finalized before: %v", err, finalizedBefore)
+	}
+	return batches, nil
+}
+
+// ClearProofsByHashes clears the proofs kept in the database of the given batches, returning the number of updated rows.
+func (o *Batch) ClearProofsByHashes(ctx context.Context, hashes []string, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("hash IN ?", hashes)
+
+	result := db.Update("proof", nil)
+	if result.Error != nil {
+		return 0, fmt.Errorf("Batch.ClearProofsByHashes error: %w, hashes: %v", result.Error, hashes)
+	}
+	return result.RowsAffected, nil
+}
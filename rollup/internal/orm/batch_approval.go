@@ -0,0 +1,82 @@
+package orm
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BatchApproval is the approval of a batch by a co-signer, required before the batch is finalized.
+type BatchApproval struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID         uint64 `json:"id" gorm:"column:id;primaryKey"`
+	BatchIndex uint64 `json:"batch_index" gorm:"column:batch_index"`
+	BatchHash  string `json:"batch_hash" gorm:"column:batch_hash"`
+	Approver   string `json:"approver" gorm:"column:approver"`
+	Signature  string `json:"signature" gorm:"column:signature"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewBatchApproval creates a new BatchApproval instance.
+func NewBatchApproval(db *gorm.DB) *BatchApproval {
+	return &BatchApproval{db: db}
+}
+
+// TableName returns the name of the "batch_approval" table.
+func (*BatchApproval) TableName() string {
+	return "batch_approval"
+}
+
+// BatchApprovalDigest returns the digest a co-signer signs to approve the batch:
+// keccak256(uint64 big endian index || batch hash || state root || withdraw root).
+func BatchApprovalDigest(index uint64, batchHash, stateRoot, withdrawRoot common.Hash) common.Hash {
+	var indexBytes [8]byte
+	binary.BigEndian.PutUint64(indexBytes[:], index)
+	return crypto.Keccak256Hash(indexBytes[:], batchHash.Bytes(), stateRoot.Bytes(), withdrawRoot.Bytes())
+}
+
+// ApprovalDigest returns the approval digest of the batch.
+func (o *Batch) ApprovalDigest() common.Hash {
+	return BatchApprovalDigest(o.Index, common.HexToHash(o.Hash), common.HexToHash(o.StateRoot), common.HexToHash(o.WithdrawRoot))
+}
+
+// GetBatchApprovals retrieves the approvals of the batch.
+func (o *BatchApproval) GetBatchApprovals(ctx context.Context, batchHash string) ([]BatchApproval, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchApproval{})
+	db = db.Where("batch_hash = ?", batchHash)
+	db = db.Order("id ASC")
+
+	var approvals []BatchApproval
+	if err := db.Find(&approvals).Error; err != nil {
+		return nil, fmt.Errorf("BatchApproval.GetBatchApprovals error: %w, batch hash: %v", err, batchHash)
+	}
+	return approvals, nil
+}
+
+// InsertBatchApproval records the approval, an approver approving the same batch again is left untouched.
+func (o *BatchApproval) InsertBatchApproval(ctx context.Context, approval *BatchApproval) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&BatchApproval{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "batch_hash"}, {Name: "approver"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	})
+
+	if err := db.Create(approval).Error; err != nil {
+		return fmt.Errorf("BatchApproval.InsertBatchApproval error: %w, batch hash: %v, approver: %v", err, approval.BatchHash, approval.Approver)
+	}
+	return nil
+}
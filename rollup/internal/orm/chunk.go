@@ -37,9 +37,12 @@ type Chunk struct {
 	// proof
 	ProvingStatus    int16      `json:"proving_status" gorm:"column:proving_status;default:1"`
 	Proof            []byte     `json:"proof" gorm:"column:proof;default:NULL"`
+	ProofHash        string     `json:"proof_hash" gorm:"column:proof_hash;default:NULL"`
+	ProofURI         string     `json:"proof_uri" gorm:"column:proof_uri;default:NULL"`
 	ProverAssignedAt *time.Time `json:"prover_assigned_at" gorm:"column:prover_assigned_at;default:NULL"`
 	ProvedAt         *time.Time `json:"proved_at" gorm:"column:proved_at;default:NULL"`
 	ProofTimeSec     int32      `json:"proof_time_sec" gorm:"column:proof_time_sec;default:NULL"`
+	FailedAttempts   int16      `json:"failed_attempts" gorm:"column:failed_attempts;default:0"`
 
 	// batch
 	BatchHash string `json:"batch_hash" gorm:"column:batch_hash;default:NULL"`
@@ -78,8 +81,8 @@ func (o *Chunk) GetChunksInRange(ctx context.Context, startIndex uint64, endInde
 
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Where("index >= ? AND index <= ?", startIndex, endIndex)
-	db = db.Order("index ASC")
+	db = db.Where(`"index" >= ? AND "index" <= ?`, startIndex, endIndex)
+	db = db.Order(`"index" ASC`)
 
 	var chunks []*Chunk
 	if err := db.Find(&chunks).Error; err != nil {
@@ -95,10 +98,14 @@ func (o *Chunk) GetChunksInRange(ctx context.Context, startIndex uint64, endInde
 }
 
 // getLatestChunk retrieves the latest chunk from the database.
-func (o *Chunk) getLatestChunk(ctx context.Context) (*Chunk, error) {
-	db := o.db.WithContext(ctx)
+func (o *Chunk) getLatestChunk(ctx context.Context, dbTX ...*gorm.DB) (*Chunk, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Order("index desc")
+	db = db.Order(`"index" desc`)
 
 	var latestChunk Chunk
 	if err := db.First(&latestChunk).Error; err != nil {
@@ -125,13 +132,26 @@ func (o *Chunk) GetUnchunkedBlockHeight(ctx context.Context) (uint64, error) {
 	return latestChunk.EndBlockNumber + 1, nil
 }
 
+// CountUnprovenChunks counts the chunks waiting for their proof, unassigned or assigned to a prover.
+func (o *Chunk) CountUnprovenChunks(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("proving_status IN ?", []int{int(types.ProvingTaskUnassigned), int(types.ProvingTaskAssigned)})
+
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("Chunk.CountUnprovenChunks error: %w", err)
+	}
+	return uint64(count), nil
+}
+
 // GetChunksGEIndex retrieves chunks that have a chunk index greater than the or equal to the given index.
 // The returned chunks are sorted in ascending order by their index.
 func (o *Chunk) GetChunksGEIndex(ctx context.Context, index uint64, limit int) ([]*Chunk, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Where("index >= ?", index)
-	db = db.Order("index ASC")
+	db = db.Where(`"index" >= ?`, index)
+	db = db.Order(`"index" ASC`)
 
 	if limit > 0 {
 		db = db.Limit(limit)
@@ -148,7 +168,7 @@ func (o *Chunk) GetChunksGEIndex(ctx context.Context, index uint64, limit int) (
 func (o *Chunk) GetChunkByIndex(ctx context.Context, index uint64) (*Chunk, error) {
 	db := o.db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Where("index = ?", index)
+	db = db.Where(`"index" = ?`, index)
 
 	var chunk Chunk
 	if err := db.First(&chunk).Error; err != nil {
@@ -160,6 +180,27 @@ func (o *Chunk) GetChunkByIndex(ctx context.Context, index uint64) (*Chunk, erro
 	return &chunk, nil
 }
 
+// GetFirstChunkEndingAboveHeight retrieves the chunk of the lowest index whose end block number is above the given height.
+func (o *Chunk) GetFirstChunkEndingAboveHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) (*Chunk, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("end_block_number > ?", height)
+	db = db.Order(`"index" ASC`)
+
+	var chunk Chunk
+	if err := db.First(&chunk).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Chunk.GetFirstChunkEndingAboveHeight error: %w, height: %v", err, height)
+	}
+	return &chunk, nil
+}
+
 // GetChunksByBatchHash retrieves chunks by batch hash
 // for test
 func (o *Chunk) GetChunksByBatchHash(ctx context.Context, batchHash string) ([]*Chunk, error) {
@@ -174,6 +215,25 @@ func (o *Chunk) GetChunksByBatchHash(ctx context.Context, batchHash string) ([]*
 	return chunks, nil
 }
 
+// GetFirstFailedChunk retrieves the first unverified chunk that has failed proving at least minFailedAttempts times.
+// It returns nil if there is no such chunk.
+func (o *Chunk) GetFirstFailedChunk(ctx context.Context, minFailedAttempts int16) (*Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("failed_attempts >= ?", minFailedAttempts)
+	db = db.Where("proving_status != ?", int(types.ProvingTaskVerified))
+	db = db.Order(`"index" ASC`)
+
+	var chunk Chunk
+	if err := db.First(&chunk).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Chunk.GetFirstFailedChunk error: %w, min failed attempts: %v", err, minFailedAttempts)
+	}
+	return &chunk, nil
+}
+
 // InsertChunk inserts a new chunk into the database.
 func (o *Chunk) InsertChunk(ctx context.Context, chunk *encoding.Chunk, codecVersion encoding.CodecVersion, metrics utils.ChunkMetrics, dbTX ...*gorm.DB) (*Chunk, error) {
 	if chunk == nil || len(chunk.Blocks) == 0 {
@@ -184,7 +244,7 @@ func (o *Chunk) InsertChunk(ctx context.Context, chunk *encoding.Chunk, codecVer
 	var totalL1MessagePoppedBefore uint64
 	var parentChunkHash string
 	var parentChunkStateRoot string
-	parentChunk, err := o.getLatestChunk(ctx)
+	parentChunk, err := o.getLatestChunk(ctx, dbTX...)
 	if err != nil {
 		log.Error("failed to get latest chunk", "err", err)
 		return nil, fmt.Errorf("Chunk.InsertChunk error: %w", err)
@@ -309,10 +369,77 @@ func (o *Chunk) UpdateBatchHashInRange(ctx context.Context, startIndex uint64, e
 	}
 	db = db.WithContext(ctx)
 	db = db.Model(&Chunk{})
-	db = db.Where("index >= ? AND index <= ?", startIndex, endIndex)
+	db = db.Where(`"index" >= ? AND "index" <= ?`, startIndex, endIndex)
 
 	if err := db.Update("batch_hash", batchHash).Error; err != nil {
 		return fmt.Errorf("Chunk.UpdateBatchHashInRange error: %w, start index: %v, end index: %v, batch hash: %v", err, startIndex, endIndex, batchHash)
 	}
 	return nil
 }
+
+// ResetBatchHashGEIndex clears the batch_hash of the chunks with an index greater than or equal to the given index.
+func (o *Chunk) ResetBatchHashGEIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where(`"index" >= ?`, index)
+
+	if err := db.Update("batch_hash", nil).Error; err != nil {
+		return fmt.Errorf("Chunk.ResetBatchHashGEIndex error: %w, index: %v", err, index)
+	}
+	return nil
+}
+
+// DeleteChunksGEIndex deletes the chunks with an index greater than or equal to the given index.
+func (o *Chunk) DeleteChunksGEIndex(ctx context.Context, index uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Where(`"index" >= ?`, index)
+
+	if err := db.Delete(&Chunk{}).Error; err != nil {
+		return fmt.Errorf("Chunk.DeleteChunksGEIndex error: %w, index: %v", err, index)
+	}
+	return nil
+}
+
+// GetChunkProofsOfBatchesFinalizedBefore retrieves the chunks which still keep their proof in the database
+// and whose batches were finalized before the given time, sorted in ascending order by their index.
+func (o *Chunk) GetChunkProofsOfBatchesFinalizedBefore(ctx context.Context, finalizedBefore time.Time, limit int) ([]*Chunk, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Select(`chunk."index", chunk.hash, chunk.proof`)
+	db = db.Joins("JOIN batch ON batch.hash = chunk.batch_hash AND batch.deleted_at IS NULL")
+	db = db.Where("chunk.proof IS NOT NULL")
+	db = db.Where("batch.rollup_status = ? AND batch.finalized_at < ?", int(types.RollupFinalized), finalizedBefore)
+	db = db.Order(`chunk."index" ASC`)
+	db = db.Limit(limit)
+
+	var chunks []*Chunk
+	if err := db.Find(&chunks).Error; err != nil {
+		return nil, fmt.Errorf("Chunk.GetChunkProofsOfBatchesFinalizedBefore error: %w, finalized before: %v", err, finalizedBefore)
+	}
+	return chunks, nil
+}
+
+// ClearProofsByHashes clears the proofs kept in the database of the given chunks, returning the number of updated rows.
+func (o *Chunk) ClearProofsByHashes(ctx context.Context, hashes []string, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&Chunk{})
+	db = db.Where("hash IN ?", hashes)
+
+	result := db.Update("proof", nil)
+	if result.Error != nil {
+		return 0, fmt.Errorf("Chunk.ClearProofsByHashes error: %w, hashes: %v", result.Error, hashes)
+	}
+	return result.RowsAffected, nil
+}
@@ -3,10 +3,14 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
+
+	"scroll-tech/common/types"
 )
 
 // L1Message is structure of stored layer1 bridge message
@@ -24,6 +28,8 @@ type L1Message struct {
 	Layer1Hash string `json:"layer1_hash" gorm:"column:layer1_hash"`
 	Layer2Hash string `json:"layer2_hash" gorm:"column:layer2_hash;default:NULL"`
 	Status     int    `json:"status" gorm:"column:status;default:1"`
+	// GasUsed is the l2 gas used by the transaction including the message, nil until it's included.
+	GasUsed *uint64 `json:"gas_used" gorm:"column:gas_used;default:NULL"`
 
 	// metadata
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
@@ -31,6 +37,14 @@ type L1Message struct {
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
 }
 
+// L1MessageGasUsage is the l2 gas used by the included layer1 messages of a sender.
+type L1MessageGasUsage struct {
+	Sender        string `gorm:"column:sender"`
+	MessageCount  uint64 `gorm:"column:message_count"`
+	TotalGasLimit uint64 `gorm:"column:total_gas_limit"`
+	TotalGasUsed  uint64 `gorm:"column:total_gas_used"`
+}
+
 // NewL1Message create an L1MessageOrm instance
 func NewL1Message(db *gorm.DB) *L1Message {
 	return &L1Message{db: db}
@@ -57,12 +71,16 @@ func (m *L1Message) GetLayer1LatestWatchedHeight() (int64, error) {
 }
 
 // SaveL1Messages batch save a list of layer1 messages
-func (m *L1Message) SaveL1Messages(ctx context.Context, messages []*L1Message) error {
+func (m *L1Message) SaveL1Messages(ctx context.Context, messages []*L1Message, dbTX ...*gorm.DB) error {
 	if len(messages) == 0 {
 		return nil
 	}
 
-	err := m.db.WithContext(ctx).Create(&messages).Error
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	err := db.WithContext(ctx).Create(&messages).Error
 	if err != nil {
 		queueIndices := make([]uint64, 0, len(messages))
 		heights := make([]uint64, 0, len(messages))
@@ -74,3 +92,129 @@ func (m *L1Message) SaveL1Messages(ctx context.Context, messages []*L1Message) e
 	}
 	return err
 }
+
+// DeleteL1MessagesAboveHeight deletes the layer1 messages emitted after the given height, used when the L1 chain reorgs.
+func (m *L1Message) DeleteL1MessagesAboveHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("height > ?", height)
+
+	result := db.Delete(&L1Message{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("L1Message.DeleteL1MessagesAboveHeight error: %w, height: %v", result.Error, height)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetFirstL1MessageGEQueueIndex returns the layer1 message of the lowest queue index not below the given one,
+// it returns nil if there is no such message.
+func (m *L1Message) GetFirstL1MessageGEQueueIndex(ctx context.Context, queueIndex uint64) (*L1Message, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("queue_index >= ?", queueIndex)
+	db = db.Order("queue_index ASC")
+
+	var l1Message L1Message
+	if err := db.First(&l1Message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L1Message.GetFirstL1MessageGEQueueIndex error: %w, queue index: %v", err, queueIndex)
+	}
+	return &l1Message, nil
+}
+
+// GetL1MessageByQueueIndex returns the layer1 message of the given queue index, it returns nil if there is no such message.
+func (m *L1Message) GetL1MessageByQueueIndex(ctx context.Context, queueIndex uint64) (*L1Message, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("queue_index = ?", queueIndex)
+
+	var l1Message L1Message
+	if err := db.First(&l1Message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L1Message.GetL1MessageByQueueIndex error: %w, queue index: %v", err, queueIndex)
+	}
+	return &l1Message, nil
+}
+
+// GetL1MessagesPage retrieves the page of the layer1 messages after the cursor queue index, ordered by queue index.
+func (m *L1Message) GetL1MessagesPage(ctx context.Context, cursor *uint64, limit int) (*Page[L1Message], error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	page, err := findPage(db, "queue_index", cursor, false, limit, func(msg *L1Message) uint64 { return msg.QueueIndex })
+	if err != nil {
+		return nil, fmt.Errorf("L1Message.GetL1MessagesPage error: %w, limit: %v", err, limit)
+	}
+	return page, nil
+}
+
+// UpdateLayer2HashByQueueIndex marks the layer1 message of the given queue index as included in layer2 by the given transaction,
+// which used the given gas.
+func (m *L1Message) UpdateLayer2HashByQueueIndex(ctx context.Context, queueIndex uint64, layer2Hash string, gasUsed uint64, dbTX ...*gorm.DB) error {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("queue_index = ?", queueIndex)
+
+	updateFields := map[string]interface{}{
+		"layer2_hash": layer2Hash,
+		"status":      int(types.MsgConfirmed),
+		"gas_used":    gasUsed,
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("L1Message.UpdateLayer2HashByQueueIndex error: %w, queue index: %v, layer2 hash: %v", err, queueIndex, layer2Hash)
+	}
+	return nil
+}
+
+// GetIncludedL1Messages returns the layer1 messages included in layer2 with their gas used, of the sender if it's not empty,
+// the latest first.
+func (m *L1Message) GetIncludedL1Messages(ctx context.Context, sender string, offset, limit int) ([]L1Message, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Where("gas_used IS NOT NULL")
+	if sender != "" {
+		db = db.Where("sender = ?", sender)
+	}
+	db = db.Order("queue_index DESC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var l1Messages []L1Message
+	if err := db.Find(&l1Messages).Error; err != nil {
+		return nil, fmt.Errorf("L1Message.GetIncludedL1Messages error: %w, sender: %v", err, sender)
+	}
+	return l1Messages, nil
+}
+
+// GetL1MessageGasUsageBySender sums the gas limits and the gas used of the layer1 messages included in layer2 per sender,
+// of the sender if it's not empty, the senders whose messages used the most gas first.
+func (m *L1Message) GetL1MessageGasUsageBySender(ctx context.Context, sender string, offset, limit int) ([]L1MessageGasUsage, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&L1Message{})
+	db = db.Select("sender, COUNT(*) AS message_count, SUM(gas_limit) AS total_gas_limit, SUM(gas_used) AS total_gas_used")
+	db = db.Where("gas_used IS NOT NULL")
+	if sender != "" {
+		db = db.Where("sender = ?", sender)
+	}
+	db = db.Group("sender")
+	db = db.Order("total_gas_used DESC, sender ASC")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+
+	var usages []L1MessageGasUsage
+	if err := db.Scan(&usages).Error; err != nil {
+		return nil, fmt.Errorf("L1Message.GetL1MessageGasUsageBySender error: %w, sender: %v", err, sender)
+	}
+	return usages, nil
+}
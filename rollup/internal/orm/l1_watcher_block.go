@@ -0,0 +1,108 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// L1WatcherBlock is an l1 block processed by the l1 watcher. The hashes of the latest ones are kept, so a reorg of
+// them is detected after a restart too. They're apart from the l1_block table, which the gas oracle rewrites.
+type L1WatcherBlock struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID     uint   `json:"id" gorm:"column:id;primaryKey"`
+	Number uint64 `json:"number" gorm:"column:number"`
+	Hash   string `json:"hash" gorm:"column:hash"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewL1WatcherBlock creates a new L1WatcherBlock instance.
+func NewL1WatcherBlock(db *gorm.DB) *L1WatcherBlock {
+	return &L1WatcherBlock{db: db}
+}
+
+// TableName returns the name of the "l1_watcher_block" table.
+func (*L1WatcherBlock) TableName() string {
+	return "l1_watcher_block"
+}
+
+// GetLatestL1WatcherBlocks returns the latest processed l1 blocks, the lowest first.
+func (o *L1WatcherBlock) GetLatestL1WatcherBlocks(ctx context.Context, limit int) ([]L1WatcherBlock, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L1WatcherBlock{})
+	db = db.Order("number DESC")
+	db = db.Limit(limit)
+
+	var blocks []L1WatcherBlock
+	if err := db.Find(&blocks).Error; err != nil {
+		return nil, fmt.Errorf("L1WatcherBlock.GetLatestL1WatcherBlocks error: %w, limit: %v", err, limit)
+	}
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+	return blocks, nil
+}
+
+// InsertL1WatcherBlock records the hash of a processed l1 block and only keeps the latest keep blocks, it's meant to
+// be called in the transaction storing the events of the block.
+func (o *L1WatcherBlock) InsertL1WatcherBlock(ctx context.Context, number uint64, hash string, keep int, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	insertDB := db.Model(&L1WatcherBlock{})
+	insertDB = insertDB.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "number"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"hash":       hash,
+			"updated_at": time.Now(),
+		}),
+	})
+	block := L1WatcherBlock{Number: number, Hash: hash}
+	if err := insertDB.Create(&block).Error; err != nil {
+		return fmt.Errorf("L1WatcherBlock.InsertL1WatcherBlock error: %w, number: %v", err, number)
+	}
+
+	// the number of the oldest block kept, NULL while there are fewer blocks so none is deleted.
+	oldestKept := db.Model(&L1WatcherBlock{})
+	oldestKept = oldestKept.Select("number")
+	oldestKept = oldestKept.Order("number DESC")
+	oldestKept = oldestKept.Offset(keep - 1)
+	oldestKept = oldestKept.Limit(1)
+
+	deleteDB := db.Model(&L1WatcherBlock{})
+	deleteDB = deleteDB.Where("number < (?)", oldestKept)
+	deleteDB = deleteDB.Unscoped()
+	if err := deleteDB.Delete(&L1WatcherBlock{}).Error; err != nil {
+		return fmt.Errorf("L1WatcherBlock.InsertL1WatcherBlock error: %w, number: %v", err, number)
+	}
+	return nil
+}
+
+// DeleteL1WatcherBlocksAboveHeight deletes the processed l1 blocks above the given height, used when L1 reorgs.
+func (o *L1WatcherBlock) DeleteL1WatcherBlocksAboveHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L1WatcherBlock{})
+	db = db.Where("number > ?", height)
+	db = db.Unscoped()
+
+	if err := db.Delete(&L1WatcherBlock{}).Error; err != nil {
+		return fmt.Errorf("L1WatcherBlock.DeleteL1WatcherBlocksAboveHeight error: %w, height: %v", err, height)
+	}
+	return nil
+}
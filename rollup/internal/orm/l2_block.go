@@ -1,9 +1,13 @@
 package orm
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/scroll-tech/da-codec/encoding"
@@ -11,6 +15,9 @@ import (
 	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
+
+	"scroll-tech/common/database"
+	"scroll-tech/common/types"
 )
 
 // L2Block represents a l2 block in the database.
@@ -18,21 +25,28 @@ type L2Block struct {
 	db *gorm.DB `gorm:"column:-"`
 
 	// block
-	Number         uint64 `json:"number" gorm:"number"`
-	Hash           string `json:"hash" gorm:"hash"`
-	ParentHash     string `json:"parent_hash" gorm:"parent_hash"`
-	Header         string `json:"header" gorm:"header"`
-	Transactions   string `json:"transactions" gorm:"transactions"`
-	WithdrawRoot   string `json:"withdraw_root" gorm:"withdraw_root"`
-	StateRoot      string `json:"state_root" gorm:"state_root"`
-	TxNum          uint32 `json:"tx_num" gorm:"tx_num"`
-	GasUsed        uint64 `json:"gas_used" gorm:"gas_used"`
-	BlockTimestamp uint64 `json:"block_timestamp" gorm:"block_timestamp"`
-	RowConsumption string `json:"row_consumption" gorm:"row_consumption"`
+	Number       uint64 `json:"number" gorm:"number"`
+	Hash         string `json:"hash" gorm:"hash"`
+	ParentHash   string `json:"parent_hash" gorm:"parent_hash"`
+	Header       string `json:"header" gorm:"header"`
+	Transactions string `json:"transactions" gorm:"transactions"`
+	// TransactionsCompressed is the gzip compressed json of the transactions, Transactions is left empty when it's set.
+	// The blocks stored before it was introduced only have Transactions.
+	TransactionsCompressed []byte `json:"transactions_compressed" gorm:"transactions_compressed"`
+	WithdrawRoot           string `json:"withdraw_root" gorm:"withdraw_root"`
+	StateRoot              string `json:"state_root" gorm:"state_root"`
+	TxNum                  uint32 `json:"tx_num" gorm:"tx_num"`
+	GasUsed                uint64 `json:"gas_used" gorm:"gas_used"`
+	BlockTimestamp         uint64 `json:"block_timestamp" gorm:"block_timestamp"`
+	RowConsumption         string `json:"row_consumption" gorm:"row_consumption"`
 
 	// chunk
 	ChunkHash string `json:"chunk_hash" gorm:"chunk_hash;default:NULL"`
 
+	// skip_reason is set when the block cannot be proven by the circuits, e.g. it contains unsupported opcodes. The
+	// l2_block table stores the block traces fetched by the watcher, there is no separate block trace table.
+	SkipReason string `json:"skip_reason" gorm:"skip_reason;default:NULL"`
+
 	// metadata
 	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
@@ -67,9 +81,9 @@ func (o *L2Block) GetL2BlocksLatestHeight(ctx context.Context) (uint64, error) {
 // The blocks are converted into encoding.Block format for output.
 // The returned blocks are sorted in ascending order by their block number.
 func (o *L2Block) GetL2BlocksGEHeight(ctx context.Context, height uint64, limit int) ([]*encoding.Block, error) {
-	db := o.db.WithContext(ctx)
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
 	db = db.Model(&L2Block{})
-	db = db.Select("header, transactions, withdraw_root, row_consumption")
+	db = db.Select("header, transactions, transactions_compressed, withdraw_root, row_consumption")
 	db = db.Where("number >= ?", height)
 	db = db.Order("number ASC")
 
@@ -84,9 +98,14 @@ func (o *L2Block) GetL2BlocksGEHeight(ctx context.Context, height uint64, limit
 
 	var blocks []*encoding.Block
 	for _, v := range l2Blocks {
+		// decoding a large range of traces takes a while, stop early once the caller gave up.
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("L2Block.GetL2BlocksGEHeight error: %w", err)
+		}
+
 		var block encoding.Block
 
-		if err := json.Unmarshal([]byte(v.Transactions), &block.Transactions); err != nil {
+		if err := v.decodeTransactions(&block.Transactions); err != nil {
 			return nil, fmt.Errorf("L2Block.GetL2BlocksGEHeight error: %w", err)
 		}
 
@@ -107,6 +126,18 @@ func (o *L2Block) GetL2BlocksGEHeight(ctx context.Context, height uint64, limit
 	return blocks, nil
 }
 
+// GetL2BlocksPage retrieves the page of the l2 blocks after the cursor block number, ordered by block number.
+// Paging through the table with the returned cursor visits every block once, even while new blocks are inserted.
+func (o *L2Block) GetL2BlocksPage(ctx context.Context, cursor *uint64, limit int) (*Page[L2Block], error) {
+	db := database.ReadFromReplica(o.db.WithContext(ctx))
+	db = db.Model(&L2Block{})
+	page, err := findPage(db, "number", cursor, false, limit, func(b *L2Block) uint64 { return b.Number })
+	if err != nil {
+		return nil, fmt.Errorf("L2Block.GetL2BlocksPage error: %w, limit: %v", err, limit)
+	}
+	return page, nil
+}
+
 // GetChunkHashes retrieves selected chunk hashes from the database.
 // The returned chunk hashes are sorted in ascending order by their block number.
 // For unit test
@@ -143,7 +174,7 @@ func (o *L2Block) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint6
 
 	db := o.db.WithContext(ctx)
 	db = db.Model(&L2Block{})
-	db = db.Select("header, transactions, withdraw_root, row_consumption")
+	db = db.Select("header, transactions, transactions_compressed, withdraw_root, row_consumption")
 	db = db.Where("number >= ? AND number <= ?", startBlockNumber, endBlockNumber)
 	db = db.Order("number ASC")
 
@@ -161,7 +192,7 @@ func (o *L2Block) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint6
 	for _, v := range l2Blocks {
 		var block encoding.Block
 
-		if err := json.Unmarshal([]byte(v.Transactions), &block.Transactions); err != nil {
+		if err := v.decodeTransactions(&block.Transactions); err != nil {
 			return nil, fmt.Errorf("L2Block.GetL2BlocksInRange error: %w, start block: %v, end block: %v", err, startBlockNumber, endBlockNumber)
 		}
 
@@ -182,8 +213,47 @@ func (o *L2Block) GetL2BlocksInRange(ctx context.Context, startBlockNumber uint6
 	return blocks, nil
 }
 
+// GetFirstSkippedL2BlockInRange retrieves the first L2 block within the specified range (inclusive) that is flagged with a skip reason.
+// It returns nil if no block in the range is flagged.
+func (o *L2Block) GetFirstSkippedL2BlockInRange(ctx context.Context, startBlockNumber uint64, endBlockNumber uint64) (*L2Block, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Select("number, hash, skip_reason")
+	db = db.Where("number >= ? AND number <= ?", startBlockNumber, endBlockNumber)
+	db = db.Where("skip_reason IS NOT NULL")
+	db = db.Order("number ASC")
+
+	var l2Block L2Block
+	if err := db.First(&l2Block).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("L2Block.GetFirstSkippedL2BlockInRange error: %w, start block: %v, end block: %v", err, startBlockNumber, endBlockNumber)
+	}
+	return &l2Block, nil
+}
+
+// GetSkippedL2Blocks retrieves the L2 blocks flagged with a skip reason, the lowest first.
+func (o *L2Block) GetSkippedL2Blocks(ctx context.Context, offset, limit int) ([]L2Block, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Select("number, hash, chunk_hash, skip_reason")
+	db = db.Where("skip_reason IS NOT NULL")
+	db = db.Order("number ASC")
+	db = db.Offset(offset)
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var l2Blocks []L2Block
+	if err := db.Find(&l2Blocks).Error; err != nil {
+		return nil, fmt.Errorf("L2Block.GetSkippedL2Blocks error: %w, offset: %v, limit: %v", err, offset, limit)
+	}
+	return l2Blocks, nil
+}
+
 // InsertL2Blocks inserts l2 blocks into the "l2_block" table.
-func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block) error {
+func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block, dbTX ...*gorm.DB) error {
 	var l2Blocks []L2Block
 	for _, block := range blocks {
 		header, err := json.Marshal(block.Header)
@@ -192,9 +262,9 @@ func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block)
 			return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
 		}
 
-		txs, err := json.Marshal(block.Transactions)
+		txs, err := compressTransactions(block.Transactions)
 		if err != nil {
-			log.Error("failed to marshal transactions", "hash", block.Header.Hash().String(), "err", err)
+			log.Error("failed to compress transactions", "hash", block.Header.Hash().String(), "err", err)
 			return fmt.Errorf("L2Block.InsertL2Blocks error: %w", err)
 		}
 
@@ -205,22 +275,26 @@ func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block)
 		}
 
 		l2Block := L2Block{
-			Number:         block.Header.Number.Uint64(),
-			Hash:           block.Header.Hash().String(),
-			ParentHash:     block.Header.ParentHash.String(),
-			Transactions:   string(txs),
-			WithdrawRoot:   block.WithdrawRoot.Hex(),
-			StateRoot:      block.Header.Root.Hex(),
-			TxNum:          uint32(len(block.Transactions)),
-			GasUsed:        block.Header.GasUsed,
-			BlockTimestamp: block.Header.Time,
-			RowConsumption: string(rc),
-			Header:         string(header),
+			Number:                 block.Header.Number.Uint64(),
+			Hash:                   block.Header.Hash().String(),
+			ParentHash:             block.Header.ParentHash.String(),
+			TransactionsCompressed: txs,
+			WithdrawRoot:           block.WithdrawRoot.Hex(),
+			StateRoot:              block.Header.Root.Hex(),
+			TxNum:                  uint32(len(block.Transactions)),
+			GasUsed:                block.Header.GasUsed,
+			BlockTimestamp:         block.Header.Time,
+			RowConsumption:         string(rc),
+			Header:                 string(header),
 		}
 		l2Blocks = append(l2Blocks, l2Block)
 	}
 
-	db := o.db.WithContext(ctx)
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
 	db = db.Model(&L2Block{})
 
 	if err := db.Create(&l2Blocks).Error; err != nil {
@@ -229,6 +303,44 @@ func (o *L2Block) InsertL2Blocks(ctx context.Context, blocks []*encoding.Block)
 	return nil
 }
 
+// UpdateSkipReason flags the l2 block with the given number as unprovable and records the reason.
+func (o *L2Block) UpdateSkipReason(ctx context.Context, blockNumber uint64, skipReason string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Where("number = ?", blockNumber)
+
+	if err := db.Update("skip_reason", skipReason).Error; err != nil {
+		return fmt.Errorf("L2Block.UpdateSkipReason error: %w, block number: %v, skip reason: %v", err, blockNumber, skipReason)
+	}
+	return nil
+}
+
+// ClearSkipReason clears the flag of the l2 block with the given number, so that it's chunked again. It returns
+// gorm.ErrRecordNotFound if the block isn't flagged.
+func (o *L2Block) ClearSkipReason(ctx context.Context, blockNumber uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Where("number = ?", blockNumber)
+	db = db.Where("skip_reason IS NOT NULL")
+
+	result := db.Update("skip_reason", gorm.Expr("NULL"))
+	if result.Error != nil {
+		return fmt.Errorf("L2Block.ClearSkipReason error: %w, block number: %v", result.Error, blockNumber)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("L2Block.ClearSkipReason error: %w, block number: %v", gorm.ErrRecordNotFound, blockNumber)
+	}
+	return nil
+}
+
 // UpdateChunkHashInRange updates the chunk_hash of block tx within the specified range (inclusive).
 // The range is closed, i.e., it includes both start and end indices.
 // This function ensures the number of rows updated must equal to (endIndex - startIndex + 1).
@@ -254,3 +366,122 @@ func (o *L2Block) UpdateChunkHashInRange(ctx context.Context, startIndex uint64,
 
 	return nil
 }
+
+// ResetChunkHashGEHeight clears the chunk_hash of the blocks with a number greater than or equal to the given height.
+func (o *L2Block) ResetChunkHashGEHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Where("number >= ?", height)
+
+	if err := db.Update("chunk_hash", nil).Error; err != nil {
+		return fmt.Errorf("L2Block.ResetChunkHashGEHeight error: %w, height: %v", err, height)
+	}
+	return nil
+}
+
+// GetL2BlocksOfBatchesFinalizedBefore retrieves the L2 blocks of the batches finalized before the given time,
+// sorted in ascending order by their block number.
+func (o *L2Block) GetL2BlocksOfBatchesFinalizedBefore(ctx context.Context, finalizedBefore time.Time, limit int) ([]*L2Block, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Joins("JOIN chunk ON chunk.hash = l2_block.chunk_hash AND chunk.deleted_at IS NULL")
+	db = db.Joins("JOIN batch ON batch.hash = chunk.batch_hash AND batch.deleted_at IS NULL")
+	db = db.Where("batch.rollup_status = ? AND batch.finalized_at < ?", int(types.RollupFinalized), finalizedBefore)
+	db = db.Order("l2_block.number ASC")
+	db = db.Limit(limit)
+
+	var l2Blocks []*L2Block
+	if err := db.Find(&l2Blocks).Error; err != nil {
+		return nil, fmt.Errorf("L2Block.GetL2BlocksOfBatchesFinalizedBefore error: %w, finalized before: %v", err, finalizedBefore)
+	}
+	return l2Blocks, nil
+}
+
+// DeleteL2BlocksByNumbers permanently deletes the L2 blocks of the given numbers, returning the number of deleted rows.
+func (o *L2Block) DeleteL2BlocksByNumbers(ctx context.Context, numbers []uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Unscoped()
+	db = db.Where("number IN ?", numbers)
+
+	result := db.Delete(&L2Block{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("L2Block.DeleteL2BlocksByNumbers error: %w, numbers: %v", result.Error, numbers)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetL2BlockHashesInRange retrieves the hashes of the L2 blocks with a number in the given inclusive range, keyed by number.
+func (o *L2Block) GetL2BlockHashesInRange(ctx context.Context, startBlockNumber uint64, endBlockNumber uint64) (map[uint64]common.Hash, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&L2Block{})
+	db = db.Select("number, hash")
+	db = db.Where("number >= ? AND number <= ?", startBlockNumber, endBlockNumber)
+
+	var l2Blocks []L2Block
+	if err := db.Find(&l2Blocks).Error; err != nil {
+		return nil, fmt.Errorf("L2Block.GetL2BlockHashesInRange error: %w, start block: %v, end block: %v", err, startBlockNumber, endBlockNumber)
+	}
+
+	hashes := make(map[uint64]common.Hash, len(l2Blocks))
+	for _, l2Block := range l2Blocks {
+		hashes[l2Block.Number] = common.HexToHash(l2Block.Hash)
+	}
+	return hashes, nil
+}
+
+// DeleteL2BlocksAboveHeight permanently deletes the L2 blocks above the given height, used when the L2 chain reorgs.
+func (o *L2Block) DeleteL2BlocksAboveHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) (int64, error) {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Unscoped()
+	db = db.Where("number > ?", height)
+
+	result := db.Delete(&L2Block{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("L2Block.DeleteL2BlocksAboveHeight error: %w, height: %v", result.Error, height)
+	}
+	return result.RowsAffected, nil
+}
+
+// compressTransactions encodes the transactions as gzip compressed json, the traces compress several times smaller.
+func compressTransactions(txs []*gethTypes.TransactionData) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(txs); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeTransactions decodes the transactions of the block, from the compressed column when it's set.
+func (o *L2Block) decodeTransactions(txs *[]*gethTypes.TransactionData) error {
+	if len(o.TransactionsCompressed) == 0 {
+		return json.Unmarshal([]byte(o.Transactions), txs)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(o.TransactionsCompressed))
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+	if err = zr.Close(); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, txs)
+}
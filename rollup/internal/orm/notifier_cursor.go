@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotifierCursor is the persisted position of a notifier in the status_audit_log table.
+type NotifierCursor struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID     uint   `json:"id" gorm:"column:id;primaryKey"`
+	Name   string `json:"name" gorm:"column:name"`
+	LastID uint64 `json:"last_id" gorm:"column:last_id"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewNotifierCursor creates a new NotifierCursor instance.
+func NewNotifierCursor(db *gorm.DB) *NotifierCursor {
+	return &NotifierCursor{db: db}
+}
+
+// TableName returns the name of the "notifier_cursor" table.
+func (*NotifierCursor) TableName() string {
+	return "notifier_cursor"
+}
+
+// GetLastID returns the id of the last status_audit_log record the notifier delivered, nil if it has no cursor yet.
+func (o *NotifierCursor) GetLastID(ctx context.Context, name string) (*uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&NotifierCursor{})
+	db = db.Where("name = ?", name)
+
+	var cursors []NotifierCursor
+	if err := db.Limit(1).Find(&cursors).Error; err != nil {
+		return nil, fmt.Errorf("NotifierCursor.GetLastID error: %w, name: %v", err, name)
+	}
+	if len(cursors) == 0 {
+		return nil, nil
+	}
+	return &cursors[0].LastID, nil
+}
+
+// UpdateLastID moves the cursor of the notifier to the given status_audit_log record.
+func (o *NotifierCursor) UpdateLastID(ctx context.Context, name string, lastID uint64) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&NotifierCursor{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "name"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_id":    lastID,
+			"updated_at": time.Now(),
+		}),
+	})
+
+	cursor := NotifierCursor{Name: name, LastID: lastID}
+	if err := db.Create(&cursor).Error; err != nil {
+		return fmt.Errorf("NotifierCursor.UpdateLastID error: %w, name: %v, last id: %v", err, name, lastID)
+	}
+	return nil
+}
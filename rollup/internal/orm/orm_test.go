@@ -3,9 +3,12 @@ package orm
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/scroll-tech/da-codec/encoding"
 	"github.com/scroll-tech/da-codec/encoding/codecv0"
@@ -30,6 +33,14 @@ var (
 	chunkOrm              *Chunk
 	batchOrm              *Batch
 	pendingTransactionOrm *PendingTransaction
+	pauseStateOrm         *PauseState
+	statusAuditLogOrm     *StatusAuditLog
+	notifierCursorOrm     *NotifierCursor
+	watcherCheckpointOrm  *WatcherCheckpoint
+	l1WatcherBlockOrm     *L1WatcherBlock
+	skippedMessageOrm     *SkippedMessage
+	batchApprovalOrm      *BatchApproval
+	archivedObjectOrm     *ArchivedObject
 
 	block1 *encoding.Block
 	block2 *encoding.Block
@@ -63,6 +74,14 @@ func setupEnv(t *testing.T) {
 	chunkOrm = NewChunk(db)
 	l2BlockOrm = NewL2Block(db)
 	pendingTransactionOrm = NewPendingTransaction(db)
+	pauseStateOrm = NewPauseState(db)
+	statusAuditLogOrm = NewStatusAuditLog(db)
+	notifierCursorOrm = NewNotifierCursor(db)
+	watcherCheckpointOrm = NewWatcherCheckpoint(db)
+	l1WatcherBlockOrm = NewL1WatcherBlock(db)
+	skippedMessageOrm = NewSkippedMessage(db)
+	batchApprovalOrm = NewBatchApproval(db)
+	archivedObjectOrm = NewArchivedObject(db)
 
 	templateBlockTrace, err := os.ReadFile("../../../common/testdata/blockTrace_02.json")
 	assert.NoError(t, err)
@@ -110,6 +129,12 @@ func TestL1BlockOrm(t *testing.T) {
 	assert.Equal(t, "hash2", blocks[1].Hash)
 	assert.Equal(t, "hash3", blocks[2].Hash)
 
+	latestBlocks, err := l1BlockOrm.GetLatestL1Blocks(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, latestBlocks, 2)
+	assert.Equal(t, "hash3", latestBlocks[0].Hash)
+	assert.Equal(t, "hash2", latestBlocks[1].Hash)
+
 	// reorg handling: insert another block with same height and different hash
 	err = l1BlockOrm.InsertL1Blocks(context.Background(), []L1Block{block2AfterReorg})
 	assert.NoError(t, err)
@@ -154,6 +179,19 @@ func TestL2BlockOrm(t *testing.T) {
 	assert.Equal(t, block1, blocks[0])
 	assert.Equal(t, block2, blocks[1])
 
+	// the blocks stored before the transactions were compressed are read from the plain json.
+	legacyTxs, err := json.Marshal(block1.Transactions)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Model(&L2Block{}).Where("number = ?", 2).Updates(map[string]interface{}{
+		"transactions":            string(legacyTxs),
+		"transactions_compressed": nil,
+	}).Error)
+	blocks, err = l2BlockOrm.GetL2BlocksGEHeight(context.Background(), 2, 2)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, block1, blocks[0])
+	assert.Equal(t, block2, blocks[1])
+
 	err = l2BlockOrm.UpdateChunkHashInRange(context.Background(), 2, 2, "test hash")
 	assert.NoError(t, err)
 
@@ -162,6 +200,38 @@ func TestL2BlockOrm(t *testing.T) {
 	assert.Len(t, chunkHashes, 2)
 	assert.Equal(t, "test hash", chunkHashes[0])
 	assert.Equal(t, "", chunkHashes[1])
+
+	skippedBlock, err := l2BlockOrm.GetFirstSkippedL2BlockInRange(context.Background(), 2, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, skippedBlock)
+
+	err = l2BlockOrm.UpdateSkipReason(context.Background(), 3, "unsupported opcode SELFDESTRUCT")
+	assert.NoError(t, err)
+
+	skippedBlock, err = l2BlockOrm.GetFirstSkippedL2BlockInRange(context.Background(), 2, 3)
+	assert.NoError(t, err)
+	assert.NotNil(t, skippedBlock)
+	assert.Equal(t, uint64(3), skippedBlock.Number)
+	assert.Equal(t, "unsupported opcode SELFDESTRUCT", skippedBlock.SkipReason)
+
+	skippedBlocks, err := l2BlockOrm.GetSkippedL2Blocks(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, skippedBlocks, 1)
+	assert.Equal(t, uint64(3), skippedBlocks[0].Number)
+	assert.Equal(t, block2.Header.Hash().String(), skippedBlocks[0].Hash)
+
+	// the operator override clears the flag, only a flagged block is cleared.
+	err = l2BlockOrm.ClearSkipReason(context.Background(), 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, l2BlockOrm.ClearSkipReason(context.Background(), 3))
+	assert.ErrorIs(t, l2BlockOrm.ClearSkipReason(context.Background(), 3), gorm.ErrRecordNotFound)
+
+	skippedBlock, err = l2BlockOrm.GetFirstSkippedL2BlockInRange(context.Background(), 2, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, skippedBlock)
+	skippedBlocks, err = l2BlockOrm.GetSkippedL2Blocks(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, skippedBlocks)
 }
 
 func TestChunkOrm(t *testing.T) {
@@ -225,6 +295,10 @@ func TestChunkOrm(t *testing.T) {
 		assert.Equal(t, types.ProvingTaskVerified, types.ProvingStatus(chunks[0].ProvingStatus))
 		assert.Equal(t, types.ProvingTaskAssigned, types.ProvingStatus(chunks[1].ProvingStatus))
 
+		unprovenChunks, err := chunkOrm.CountUnprovenChunks(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), unprovenChunks)
+
 		err = chunkOrm.UpdateBatchHashInRange(context.Background(), 0, 0, "test hash")
 		assert.NoError(t, err)
 		chunks, err = chunkOrm.GetChunksGEIndex(context.Background(), 0, 0)
@@ -234,6 +308,23 @@ func TestChunkOrm(t *testing.T) {
 		assert.Equal(t, chunkHash2.Hex(), chunks[1].Hash)
 		assert.Equal(t, "test hash", chunks[0].BatchHash)
 		assert.Equal(t, "", chunks[1].BatchHash)
+
+		failedChunk, err := chunkOrm.GetFirstFailedChunk(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.Nil(t, failedChunk)
+		assert.NoError(t, db.Model(&Chunk{}).Where("hash = ?", chunkHash2.Hex()).Update("failed_attempts", 3).Error)
+		failedChunk, err = chunkOrm.GetFirstFailedChunk(context.Background(), 3)
+		assert.NoError(t, err)
+		assert.NotNil(t, failedChunk)
+		assert.Equal(t, chunkHash2.Hex(), failedChunk.Hash)
+
+		assert.NoError(t, chunkOrm.ResetBatchHashGEIndex(context.Background(), 0))
+		assert.NoError(t, chunkOrm.DeleteChunksGEIndex(context.Background(), 1))
+		chunks, err = chunkOrm.GetChunksGEIndex(context.Background(), 0, 0)
+		assert.NoError(t, err)
+		assert.Len(t, chunks, 1)
+		assert.Equal(t, chunkHash1.Hex(), chunks[0].Hash)
+		assert.Equal(t, "", chunks[0].BatchHash)
 	}
 }
 
@@ -313,9 +404,17 @@ func TestBatchOrm(t *testing.T) {
 		assert.Equal(t, types.RollupCommitFailed, rollupStatus[0])
 		assert.Equal(t, types.RollupPending, rollupStatus[1])
 
+		unprovenBatches, err := batchOrm.CountUnprovenBatches(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2), unprovenBatches)
+
 		err = batchOrm.UpdateProvingStatus(context.Background(), batchHash2, types.ProvingTaskVerified)
 		assert.NoError(t, err)
 
+		unprovenBatches, err = batchOrm.CountUnprovenBatches(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), unprovenBatches)
+
 		dbProof, err := batchOrm.GetVerifiedProofByHash(context.Background(), batchHash1)
 		assert.Error(t, err)
 		assert.Nil(t, dbProof)
@@ -351,6 +450,59 @@ func TestBatchOrm(t *testing.T) {
 		assert.NotNil(t, updatedBatch)
 		assert.Equal(t, "finalizeTxHash", updatedBatch.FinalizeTxHash)
 		assert.Equal(t, types.RollupFinalizeFailed, types.RollupStatus(updatedBatch.RollupStatus))
+
+		nextFinalizeAt := time.Now().Add(time.Minute)
+		err = batchOrm.UpdateFinalizeAttempts(context.Background(), batchHash2, "finalizeTxHash2", types.RollupFinalizeQuarantined, 3, nextFinalizeAt)
+		assert.NoError(t, err)
+
+		updatedBatch, err = batchOrm.GetBatchByHash(context.Background(), batchHash2)
+		assert.NoError(t, err)
+		assert.Equal(t, "finalizeTxHash2", updatedBatch.FinalizeTxHash)
+		assert.Equal(t, types.RollupFinalizeQuarantined, types.RollupStatus(updatedBatch.RollupStatus))
+		assert.Equal(t, int16(3), updatedBatch.FinalizeAttempts)
+		assert.NotNil(t, updatedBatch.NextFinalizeAt)
+
+		latestBatch, err := batchOrm.GetLatestBatchByRollupStatus(context.Background(), []types.RollupStatus{types.RollupCommitFailed, types.RollupFinalizeQuarantined})
+		assert.NoError(t, err)
+		assert.Equal(t, batchHash2, latestBatch.Hash)
+		latestBatch, err = batchOrm.GetLatestBatchByRollupStatus(context.Background(), []types.RollupStatus{types.RollupCommitFailed})
+		assert.NoError(t, err)
+		assert.Equal(t, batchHash1, latestBatch.Hash)
+		latestBatch, err = batchOrm.GetLatestBatchByRollupStatus(context.Background(), []types.RollupStatus{types.RollupFinalized})
+		assert.NoError(t, err)
+		assert.Nil(t, latestBatch)
+
+		// both batches committed in one transaction, only the second one finalized.
+		err = batchOrm.UpdateCommitTxHashAndRollupStatus(context.Background(), batchHash1, "commitTxHash", types.RollupCommitted)
+		assert.NoError(t, err)
+		err = batchOrm.UpdateFinalizeTxHashAndRollupStatus(context.Background(), batchHash2, "finalizeTxHash", types.RollupFinalized)
+		assert.NoError(t, err)
+		finalizedBatches, err := batchOrm.GetLatestFinalizedBatches(context.Background(), 10)
+		assert.NoError(t, err)
+		assert.Len(t, finalizedBatches, 1)
+		assert.Equal(t, batchHash2, finalizedBatches[0].Hash)
+		assert.Equal(t, "commitTxHash", finalizedBatches[0].CommitTxHash)
+		assert.Equal(t, "finalizeTxHash", finalizedBatches[0].FinalizeTxHash)
+
+		commitCounts, err := batchOrm.GetBatchCountsByCommitTxHashes(context.Background(), []string{"commitTxHash", "unknownTxHash"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]uint64{"commitTxHash": 2}, commitCounts)
+		finalizeCounts, err := batchOrm.GetBatchCountsByFinalizeTxHashes(context.Background(), []string{"finalizeTxHash"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]uint64{"finalizeTxHash": 1}, finalizeCounts)
+
+		// both batches finalized in one bundle.
+		err = batchOrm.UpdateFinalizeTxHashAndRollupStatusByHashes(context.Background(), []string{batchHash1, batchHash2}, "bundleTxHash", types.RollupFinalized)
+		assert.NoError(t, err)
+		finalizeCounts, err = batchOrm.GetBatchCountsByFinalizeTxHashes(context.Background(), []string{"bundleTxHash"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]uint64{"bundleTxHash": 2}, finalizeCounts)
+		for _, hash := range []string{batchHash1, batchHash2} {
+			updatedBatch, err = batchOrm.GetBatchByHash(context.Background(), hash)
+			assert.NoError(t, err)
+			assert.Equal(t, types.RollupFinalized, types.RollupStatus(updatedBatch.RollupStatus))
+			assert.NotNil(t, updatedBatch.FinalizedAt)
+		}
 	}
 }
 
@@ -417,6 +569,17 @@ func TestPendingTransactionOrm(t *testing.T) {
 	assert.Equal(t, senderMeta.Address.String(), txs[1].SenderAddress)
 	assert.Equal(t, senderMeta.Type, txs[1].SenderType)
 
+	txs, err = pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderTypeAndAddress(context.Background(), senderMeta.Type, senderMeta.Address.String(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+	txs, err = pendingTransactionOrm.GetPendingOrReplacedTransactionsBySenderTypeAndAddress(context.Background(), senderMeta.Type, common.HexToAddress("0x2").String(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 0)
+
+	pendingCount, err := pendingTransactionOrm.GetPendingTransactionCountBySenderTypeAndAddress(context.Background(), senderMeta.Type, senderMeta.Address.String())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pendingCount)
+
 	err = pendingTransactionOrm.UpdatePendingTransactionStatusByTxHash(context.Background(), tx1.Hash(), types.TxStatusConfirmed)
 	assert.NoError(t, err)
 
@@ -435,3 +598,369 @@ func TestPendingTransactionOrm(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, types.TxStatusConfirmedFailed, status)
 }
+
+func TestPauseStateOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	paused, err := pauseStateOrm.IsPaused(context.Background(), PauseComponentCommit)
+	assert.NoError(t, err)
+	assert.False(t, paused)
+
+	assert.NoError(t, pauseStateOrm.SetPaused(context.Background(), PauseComponentCommit, true, "incident"))
+	paused, err = pauseStateOrm.IsPaused(context.Background(), PauseComponentCommit)
+	assert.NoError(t, err)
+	assert.True(t, paused)
+
+	paused, err = pauseStateOrm.IsPaused(context.Background(), PauseComponentFinalize)
+	assert.NoError(t, err)
+	assert.False(t, paused)
+
+	assert.NoError(t, pauseStateOrm.SetPaused(context.Background(), PauseComponentCommit, false, ""))
+	paused, err = pauseStateOrm.IsPaused(context.Background(), PauseComponentCommit)
+	assert.NoError(t, err)
+	assert.False(t, paused)
+
+	pauseStates, err := pauseStateOrm.GetPauseStates(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, pauseStates, 1)
+	assert.Equal(t, PauseComponentCommit, pauseStates[0].Component)
+}
+
+func TestStatusAuditLogOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	batch := &encoding.Batch{
+		Index:                      0,
+		TotalL1MessagePoppedBefore: 0,
+		ParentBatchHash:            common.Hash{},
+		Chunks:                     []*encoding.Chunk{{Blocks: []*encoding.Block{block1}}},
+	}
+	dbBatch, err := batchOrm.InsertBatch(context.Background(), batch, encoding.CodecV0, utils.BatchMetrics{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitting))
+	// an update keeping the status is not a transition.
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitting))
+	assert.NoError(t, batchOrm.UpdateRollupStatus(context.Background(), dbBatch.Hash, types.RollupCommitted))
+
+	logs, err := statusAuditLogOrm.GetStatusAuditLogs(context.Background(), "batch", []string{dbBatch.Hash})
+	assert.NoError(t, err)
+
+	var rollupStatusLogs []*StatusAuditLog
+	for _, log := range logs {
+		if log.StatusColumn == "rollup_status" {
+			rollupStatusLogs = append(rollupStatusLogs, log)
+		}
+	}
+	assert.Len(t, rollupStatusLogs, 3)
+	assert.Nil(t, rollupStatusLogs[0].FromStatus)
+	assert.Equal(t, int(types.RollupPending), rollupStatusLogs[0].ToStatus)
+	assert.Equal(t, int(types.RollupPending), *rollupStatusLogs[1].FromStatus)
+	assert.Equal(t, int(types.RollupCommitting), rollupStatusLogs[1].ToStatus)
+	assert.Equal(t, int(types.RollupCommitting), *rollupStatusLogs[2].FromStatus)
+	assert.Equal(t, int(types.RollupCommitted), rollupStatusLogs[2].ToStatus)
+	assert.NotEmpty(t, rollupStatusLogs[2].Actor)
+
+	latestID, err := statusAuditLogOrm.GetLatestID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, logs[len(logs)-1].ID, latestID)
+
+	logsAfter, err := statusAuditLogOrm.GetStatusAuditLogsAfterID(context.Background(), "batch", rollupStatusLogs[1].ID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, logsAfter, 1)
+	assert.Equal(t, rollupStatusLogs[2].ID, logsAfter[0].ID)
+
+	allLogsAfter, err := statusAuditLogOrm.GetAllStatusAuditLogsAfterID(context.Background(), rollupStatusLogs[0].ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, allLogsAfter, 1)
+	assert.Equal(t, rollupStatusLogs[0].ID+1, allLogsAfter[0].ID)
+}
+
+func TestNotifierCursorOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	lastID, err := notifierCursorOrm.GetLastID(context.Background(), "batch_notifier")
+	assert.NoError(t, err)
+	assert.Nil(t, lastID)
+
+	assert.NoError(t, notifierCursorOrm.UpdateLastID(context.Background(), "batch_notifier", 10))
+	assert.NoError(t, notifierCursorOrm.UpdateLastID(context.Background(), "batch_notifier", 12))
+	lastID, err = notifierCursorOrm.GetLastID(context.Background(), "batch_notifier")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), *lastID)
+}
+
+func TestWatcherCheckpointOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	checkpoint, err := watcherCheckpointOrm.GetCheckpoint(context.Background(), "l1_watcher_deposit")
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	assert.NoError(t, watcherCheckpointOrm.UpdateCheckpoint(context.Background(), "l1_watcher_deposit", 10, "0x0a"))
+	assert.NoError(t, watcherCheckpointOrm.UpdateCheckpoint(context.Background(), "l1_watcher_deposit", 12, "0x0c"))
+
+	// the checkpoint is rolled back with the transaction storing the events.
+	err = db.Transaction(func(dbTX *gorm.DB) error {
+		assert.NoError(t, watcherCheckpointOrm.UpdateCheckpoint(context.Background(), "l1_watcher_deposit", 14, "0x0e", dbTX))
+		return errors.New("store events failure")
+	})
+	assert.Error(t, err)
+
+	checkpoint, err = watcherCheckpointOrm.GetCheckpoint(context.Background(), "l1_watcher_deposit")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), checkpoint.BlockNumber)
+	assert.Equal(t, "0x0c", checkpoint.BlockHash)
+}
+
+func TestL1WatcherBlockOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	for number := uint64(10); number <= 50; number += 10 {
+		assert.NoError(t, l1WatcherBlockOrm.InsertL1WatcherBlock(context.Background(), number, fmt.Sprintf("0x%x", number), 3))
+	}
+	// a block processed again after a reorg replaces the hash.
+	assert.NoError(t, l1WatcherBlockOrm.InsertL1WatcherBlock(context.Background(), 50, "0x50", 3))
+
+	// only the latest blocks are kept.
+	blocks, err := l1WatcherBlockOrm.GetLatestL1WatcherBlocks(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 3)
+	assert.Equal(t, uint64(30), blocks[0].Number)
+	assert.Equal(t, uint64(40), blocks[1].Number)
+	assert.Equal(t, uint64(50), blocks[2].Number)
+	assert.Equal(t, "0x50", blocks[2].Hash)
+
+	blocks, err = l1WatcherBlockOrm.GetLatestL1WatcherBlocks(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 2)
+	assert.Equal(t, uint64(40), blocks[0].Number)
+
+	// the blocks above the fork point are deleted with the rollback of the reorg.
+	assert.NoError(t, l1WatcherBlockOrm.DeleteL1WatcherBlocksAboveHeight(context.Background(), 35))
+	blocks, err = l1WatcherBlockOrm.GetLatestL1WatcherBlocks(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 1)
+	assert.Equal(t, uint64(30), blocks[0].Number)
+	assert.NoError(t, l1WatcherBlockOrm.InsertL1WatcherBlock(context.Background(), 40, "0x40", 3))
+}
+
+func TestSkippedMessageOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	skippedMessages := []*SkippedMessage{
+		{QueueIndex: 3, MsgHash: "0x03", L2BlockNumber: 10, Reason: SkippedMessageReasonSkipped},
+		{QueueIndex: 5, MsgHash: "0x05", L2BlockNumber: 12, Layer2Hash: "0x0c", Reason: SkippedMessageReasonRelayFailed},
+	}
+	assert.NoError(t, skippedMessageOrm.InsertSkippedMessages(context.Background(), skippedMessages))
+	// the messages already recorded are left untouched.
+	assert.NoError(t, skippedMessageOrm.InsertSkippedMessages(context.Background(), []*SkippedMessage{
+		{QueueIndex: 3, L2BlockNumber: 11, Reason: SkippedMessageReasonRelayFailed},
+	}))
+
+	got, err := skippedMessageOrm.GetSkippedMessages(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, uint64(5), got[0].QueueIndex)
+	assert.Equal(t, uint64(3), got[1].QueueIndex)
+	assert.Equal(t, SkippedMessageReasonSkipped, got[1].Reason)
+	assert.Equal(t, uint64(10), got[1].L2BlockNumber)
+
+	assert.NoError(t, skippedMessageOrm.UpdateReplay(context.Background(), 3, "0xreplay", 500000))
+	skippedMessage, err := skippedMessageOrm.GetSkippedMessageByQueueIndex(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "0xreplay", skippedMessage.ReplayTxHash)
+	assert.Equal(t, uint64(500000), skippedMessage.ReplayGasLimit)
+	assert.NotNil(t, skippedMessage.ReplayedAt)
+
+	assert.NoError(t, skippedMessageOrm.DeleteSkippedMessagesAboveHeight(context.Background(), 10))
+	skippedMessage, err = skippedMessageOrm.GetSkippedMessageByQueueIndex(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Nil(t, skippedMessage)
+	got, err = skippedMessageOrm.GetSkippedMessages(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestL1MessageGasUsage(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	l1MessageOrm := NewL1Message(db)
+	var messages []*L1Message
+	for i, sender := range []string{"0xAa", "0xBb", "0xAa", "0xAa"} {
+		messages = append(messages, &L1Message{
+			QueueIndex: uint64(i),
+			MsgHash:    fmt.Sprintf("0x%02x", i),
+			Height:     uint64(i),
+			GasLimit:   100000,
+			Sender:     sender,
+			Layer1Hash: fmt.Sprintf("0x1%02x", i),
+		})
+	}
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), messages))
+
+	// the last message is not included yet.
+	for i, gasUsed := range []uint64{60000, 90000, 30000} {
+		assert.NoError(t, l1MessageOrm.UpdateLayer2HashByQueueIndex(context.Background(), uint64(i), fmt.Sprintf("0x2%02x", i), gasUsed))
+	}
+
+	included, err := l1MessageOrm.GetIncludedL1Messages(context.Background(), "", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, included, 3)
+	assert.Equal(t, uint64(2), included[0].QueueIndex)
+	assert.Equal(t, uint64(30000), *included[0].GasUsed)
+
+	included, err = l1MessageOrm.GetIncludedL1Messages(context.Background(), "0xAa", 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, included, 1)
+	assert.Equal(t, uint64(0), included[0].QueueIndex)
+
+	usages, err := l1MessageOrm.GetL1MessageGasUsageBySender(context.Background(), "", 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []L1MessageGasUsage{
+		{Sender: "0xAa", MessageCount: 2, TotalGasLimit: 200000, TotalGasUsed: 90000},
+		{Sender: "0xBb", MessageCount: 1, TotalGasLimit: 100000, TotalGasUsed: 90000},
+	}, usages)
+
+	usages, err = l1MessageOrm.GetL1MessageGasUsageBySender(context.Background(), "0xBb", 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, usages, 1)
+	assert.Equal(t, "0xBb", usages[0].Sender)
+}
+
+func TestPagination(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	l1MessageOrm := NewL1Message(db)
+	var messages []*L1Message
+	for i := 0; i < 5; i++ {
+		messages = append(messages, &L1Message{QueueIndex: uint64(i), MsgHash: fmt.Sprintf("0x%02x", i), Layer1Hash: fmt.Sprintf("0x1%02x", i)})
+	}
+	assert.NoError(t, l1MessageOrm.SaveL1Messages(context.Background(), messages))
+
+	_, err = l1MessageOrm.GetL1MessagesPage(context.Background(), nil, 0)
+	assert.Error(t, err)
+
+	var queueIndices []uint64
+	var cursor *uint64
+	for pages := 0; ; pages++ {
+		page, pageErr := l1MessageOrm.GetL1MessagesPage(context.Background(), cursor, 2)
+		assert.NoError(t, pageErr)
+		for _, msg := range page.Items {
+			queueIndices = append(queueIndices, msg.QueueIndex)
+		}
+		if page.NextCursor == nil {
+			assert.Equal(t, 2, pages)
+			break
+		}
+		cursor = page.NextCursor
+		// the rows deleted before the cursor don't shift the next pages.
+		if pages == 0 {
+			assert.NoError(t, db.Where("queue_index = ?", 0).Delete(&L1Message{}).Error)
+		}
+	}
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, queueIndices)
+
+	// the greatest first.
+	descCursor := uint64(4)
+	page, err := findPage(db.Model(&L1Message{}), "queue_index", &descCursor, true, 2, func(msg *L1Message) uint64 { return msg.QueueIndex })
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, uint64(3), page.Items[0].QueueIndex)
+	assert.Equal(t, uint64(2), page.Items[1].QueueIndex)
+	assert.Equal(t, uint64(2), *page.NextCursor)
+
+	assert.NoError(t, l2BlockOrm.InsertL2Blocks(context.Background(), []*encoding.Block{block1, block2}))
+	blockPage, err := l2BlockOrm.GetL2BlocksPage(context.Background(), nil, 1)
+	assert.NoError(t, err)
+	assert.Len(t, blockPage.Items, 1)
+	assert.Equal(t, block1.Header.Number.Uint64(), blockPage.Items[0].Number)
+	assert.Equal(t, block1.Header.Number.Uint64(), *blockPage.NextCursor)
+	blockPage, err = l2BlockOrm.GetL2BlocksPage(context.Background(), blockPage.NextCursor, 1)
+	assert.NoError(t, err)
+	assert.Len(t, blockPage.Items, 1)
+	assert.Equal(t, block2.Header.Number.Uint64(), blockPage.Items[0].Number)
+	assert.Nil(t, blockPage.NextCursor)
+
+	batchPage, err := batchOrm.GetBatchesPage(context.Background(), nil, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, batchPage.Items)
+	assert.Nil(t, batchPage.NextCursor)
+}
+
+func TestBatchApprovalOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	approvals := []*BatchApproval{
+		{BatchIndex: 1, BatchHash: "0x01", Approver: "0xa1", Signature: "0x01a1"},
+		{BatchIndex: 1, BatchHash: "0x01", Approver: "0xa2", Signature: "0x01a2"},
+		{BatchIndex: 2, BatchHash: "0x02", Approver: "0xa1", Signature: "0x02a1"},
+	}
+	for _, approval := range approvals {
+		assert.NoError(t, batchApprovalOrm.InsertBatchApproval(context.Background(), approval))
+	}
+	// an approver approving the same batch again is left untouched.
+	assert.NoError(t, batchApprovalOrm.InsertBatchApproval(context.Background(), &BatchApproval{BatchIndex: 1, BatchHash: "0x01", Approver: "0xa1", Signature: "0xother"}))
+
+	got, err := batchApprovalOrm.GetBatchApprovals(context.Background(), "0x01")
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "0xa1", got[0].Approver)
+	assert.Equal(t, "0x01a1", got[0].Signature)
+	assert.Equal(t, "0xa2", got[1].Approver)
+
+	batch := &Batch{Index: 1, Hash: "0x01", StateRoot: "0x02", WithdrawRoot: "0x03"}
+	assert.Equal(t, BatchApprovalDigest(1, common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")), batch.ApprovalDigest())
+	assert.NotEqual(t, BatchApprovalDigest(2, common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")), batch.ApprovalDigest())
+}
+
+func TestArchivedObjectOrm(t *testing.T) {
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NoError(t, migrate.ResetDB(sqlDB))
+
+	archivedObject, err := archivedObjectOrm.GetArchivedObject(context.Background(), ArchivedObjectKindBatchProof, "0x01")
+	assert.NoError(t, err)
+	assert.Nil(t, archivedObject)
+
+	assert.NoError(t, archivedObjectOrm.InsertArchivedObjects(context.Background(), []*ArchivedObject{
+		{Kind: ArchivedObjectKindL2Block, ObjectKey: "1", URI: "l2_block/1", ContentHash: "aa", Size: 10},
+		{Kind: ArchivedObjectKindL2Block, ObjectKey: "2", URI: "l2_block/2", ContentHash: "bb", Size: 20},
+		{Kind: ArchivedObjectKindBatchProof, ObjectKey: "0x01", URI: "batch_proof/0x01", ContentHash: "cc", Size: 30},
+	}))
+	// an object archived again points to its latest copy.
+	assert.NoError(t, archivedObjectOrm.InsertArchivedObjects(context.Background(), []*ArchivedObject{
+		{Kind: ArchivedObjectKindBatchProof, ObjectKey: "0x01", URI: "batch_proof/0x01.v2", ContentHash: "dd", Size: 40},
+	}))
+
+	archivedObject, err = archivedObjectOrm.GetArchivedObject(context.Background(), ArchivedObjectKindBatchProof, "0x01")
+	assert.NoError(t, err)
+	assert.Equal(t, "batch_proof/0x01.v2", archivedObject.URI)
+	assert.Equal(t, "dd", archivedObject.ContentHash)
+	assert.Equal(t, uint64(40), archivedObject.Size)
+
+	archivedObjects, err := archivedObjectOrm.GetArchivedObjects(context.Background(), ArchivedObjectKindL2Block, []string{"1", "2", "3"})
+	assert.NoError(t, err)
+	assert.Len(t, archivedObjects, 2)
+	archivedObjects, err = archivedObjectOrm.GetArchivedObjects(context.Background(), ArchivedObjectKindChunkProof, []string{"1"})
+	assert.NoError(t, err)
+	assert.Empty(t, archivedObjects)
+}
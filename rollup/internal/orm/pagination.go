@@ -0,0 +1,49 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Page is a page of the rows of a table ordered by a unique integer column, e.g. the number of the l2 blocks.
+type Page[T any] struct {
+	Items []T
+	// NextCursor is the column value of the last item, passed back to fetch the next page, nil on the last page.
+	NextCursor *uint64
+}
+
+// findPage fetches the page of at most limit rows after the cursor, ordered by the unique integer column, the
+// greatest first if desc. A nil cursor starts from the first row.
+// Unlike an offset, the cursor is a value of the column: the database seeks the page in the index instead of
+// scanning every row before it, and the pages stay stable while rows are inserted or deleted before them.
+func findPage[T any](db *gorm.DB, column string, cursor *uint64, desc bool, limit int, key func(*T) uint64) (*Page[T], error) {
+	if limit <= 0 {
+		return nil, errors.New("the page limit must be positive")
+	}
+
+	op, order := ">", "ASC"
+	if desc {
+		op, order = "<", "DESC"
+	}
+	if cursor != nil {
+		db = db.Where(fmt.Sprintf("%s %s ?", column, op), *cursor)
+	}
+	db = db.Order(fmt.Sprintf("%s %s", column, order))
+	// one more row tells whether there is a next page.
+	db = db.Limit(limit + 1)
+
+	var items []T
+	if err := db.Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		nextCursor := key(&page.Items[limit-1])
+		page.NextCursor = &nextCursor
+	}
+	return page, nil
+}
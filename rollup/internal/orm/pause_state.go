@@ -0,0 +1,94 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// PauseComponentBatchProposer pauses proposing new batches.
+	PauseComponentBatchProposer = "batch_proposer"
+	// PauseComponentCommit pauses committing the pending batches.
+	PauseComponentCommit = "commit"
+	// PauseComponentFinalize pauses finalizing the committed batches.
+	PauseComponentFinalize = "finalize"
+)
+
+// PauseComponents are the components of the rollup relayer that can be paused.
+var PauseComponents = []string{PauseComponentBatchProposer, PauseComponentCommit, PauseComponentFinalize}
+
+// PauseState is the persisted paused state of a rollup relayer component.
+type PauseState struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID        uint   `json:"id" gorm:"column:id;primaryKey"`
+	Component string `json:"component" gorm:"column:component"`
+	Paused    bool   `json:"paused" gorm:"column:paused"`
+	Reason    string `json:"reason" gorm:"column:reason"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewPauseState creates a new PauseState instance.
+func NewPauseState(db *gorm.DB) *PauseState {
+	return &PauseState{db: db}
+}
+
+// TableName returns the name of the "pause_state" table.
+func (*PauseState) TableName() string {
+	return "pause_state"
+}
+
+// IsPaused returns whether the component is paused, a component without a record is not paused.
+func (o *PauseState) IsPaused(ctx context.Context, component string) (bool, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PauseState{})
+	db = db.Where("component = ?", component)
+
+	var pauseStates []PauseState
+	if err := db.Limit(1).Find(&pauseStates).Error; err != nil {
+		return false, fmt.Errorf("PauseState.IsPaused error: %w, component: %v", err, component)
+	}
+	return len(pauseStates) > 0 && pauseStates[0].Paused, nil
+}
+
+// GetPauseStates retrieves the persisted states of all the components.
+func (o *PauseState) GetPauseStates(ctx context.Context) ([]PauseState, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PauseState{})
+	db = db.Order("component ASC")
+
+	var pauseStates []PauseState
+	if err := db.Find(&pauseStates).Error; err != nil {
+		return nil, fmt.Errorf("PauseState.GetPauseStates error: %w", err)
+	}
+	return pauseStates, nil
+}
+
+// SetPaused pauses or resumes the component.
+func (o *PauseState) SetPaused(ctx context.Context, component string, paused bool, reason string) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PauseState{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "component"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"paused":     paused,
+			"reason":     reason,
+			"updated_at": time.Now(),
+		}),
+	})
+
+	pauseState := PauseState{Component: component, Paused: paused, Reason: reason}
+	if err := db.Create(&pauseState).Error; err != nil {
+		return fmt.Errorf("PauseState.SetPaused error: %w, component: %v, paused: %v", err, component, paused)
+	}
+	return nil
+}
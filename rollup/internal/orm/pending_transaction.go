@@ -85,6 +85,37 @@ func (o *PendingTransaction) GetPendingOrReplacedTransactionsBySenderType(ctx co
 	return transactions, nil
 }
 
+// GetPendingOrReplacedTransactionsBySenderTypeAndAddress retrieves pending or replaced transactions of a single sender account, ordered like GetPendingOrReplacedTransactionsBySenderType.
+func (o *PendingTransaction) GetPendingOrReplacedTransactionsBySenderTypeAndAddress(ctx context.Context, senderType types.SenderType, senderAddress string, limit int) ([]PendingTransaction, error) {
+	var transactions []PendingTransaction
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("sender_type = ?", senderType)
+	db = db.Where("sender_address = ?", senderAddress)
+	db = db.Where("status = ? OR status = ?", types.TxStatusPending, types.TxStatusReplaced)
+	db = db.Order("nonce asc")
+	db = db.Order("gas_fee_cap asc")
+	db = db.Limit(limit)
+	if err := db.Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending or replaced transactions by sender type and address, error: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetPendingTransactionCountBySenderTypeAndAddress returns the number of pending transactions of a single sender account, replaced transactions are not counted.
+func (o *PendingTransaction) GetPendingTransactionCountBySenderTypeAndAddress(ctx context.Context, senderType types.SenderType, senderAddress string) (int64, error) {
+	var count int64
+	db := o.db.WithContext(ctx)
+	db = db.Model(&PendingTransaction{})
+	db = db.Where("sender_type = ?", senderType)
+	db = db.Where("sender_address = ?", senderAddress)
+	db = db.Where("status = ?", types.TxStatusPending)
+	if err := db.Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to get pending transaction count by sender type and address, error: %w", err)
+	}
+	return count, nil
+}
+
 // GetConfirmedTransactionsBySenderType retrieves confirmed transactions filtered by sender type, limited to a specified count.
 // for unit test
 func (o *PendingTransaction) GetConfirmedTransactionsBySenderType(ctx context.Context, senderType types.SenderType, limit int) ([]PendingTransaction, error) {
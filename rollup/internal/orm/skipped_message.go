@@ -0,0 +1,142 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"scroll-tech/common/utils"
+)
+
+const (
+	// SkippedMessageReasonSkipped the L1 message was skipped by the sequencer, e.g. its transaction overflows the circuits.
+	SkippedMessageReasonSkipped = "skipped by the sequencer"
+	// SkippedMessageReasonRelayFailed the L1 message was included, but relaying it failed on L2, e.g. out of gas or reverted.
+	SkippedMessageReasonRelayFailed = "relay failed"
+)
+
+// SkippedMessage is an L1 message which was skipped or failed relaying on L2, it can be replayed on L1 with a new gas limit.
+type SkippedMessage struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID            uint   `json:"id" gorm:"column:id;primaryKey"`
+	QueueIndex    uint64 `json:"queue_index" gorm:"column:queue_index"`
+	MsgHash       string `json:"msg_hash" gorm:"column:msg_hash"`
+	L2BlockNumber uint64 `json:"l2_block_number" gorm:"column:l2_block_number"`
+	Layer2Hash    string `json:"layer2_hash" gorm:"column:layer2_hash"`
+	Reason        string `json:"reason" gorm:"column:reason"`
+
+	// replay
+	ReplayTxHash   string     `json:"replay_tx_hash" gorm:"column:replay_tx_hash;default:NULL"`
+	ReplayGasLimit uint64     `json:"replay_gas_limit" gorm:"column:replay_gas_limit;default:NULL"`
+	ReplayedAt     *time.Time `json:"replayed_at" gorm:"column:replayed_at;default:NULL"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewSkippedMessage creates a new SkippedMessage instance.
+func NewSkippedMessage(db *gorm.DB) *SkippedMessage {
+	return &SkippedMessage{db: db}
+}
+
+// TableName returns the name of the "skipped_message" table.
+func (*SkippedMessage) TableName() string {
+	return "skipped_message"
+}
+
+// GetSkippedMessages retrieves the skipped messages ordered by queue index, the latest first.
+func (o *SkippedMessage) GetSkippedMessages(ctx context.Context, offset, limit int) ([]SkippedMessage, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedMessage{})
+	db = db.Order("queue_index DESC")
+	db = db.Offset(offset)
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	var skippedMessages []SkippedMessage
+	if err := db.Find(&skippedMessages).Error; err != nil {
+		return nil, fmt.Errorf("SkippedMessage.GetSkippedMessages error: %w, offset: %v, limit: %v", err, offset, limit)
+	}
+	return skippedMessages, nil
+}
+
+// GetSkippedMessageByQueueIndex retrieves the skipped message of the queue index, it returns nil if the message is not skipped.
+func (o *SkippedMessage) GetSkippedMessageByQueueIndex(ctx context.Context, queueIndex uint64) (*SkippedMessage, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedMessage{})
+	db = db.Where("queue_index = ?", queueIndex)
+
+	var skippedMessages []SkippedMessage
+	if err := db.Limit(1).Find(&skippedMessages).Error; err != nil {
+		return nil, fmt.Errorf("SkippedMessage.GetSkippedMessageByQueueIndex error: %w, queue index: %v", err, queueIndex)
+	}
+	if len(skippedMessages) == 0 {
+		return nil, nil
+	}
+	return &skippedMessages[0], nil
+}
+
+// InsertSkippedMessages records the skipped messages, the ones already recorded are left untouched.
+func (o *SkippedMessage) InsertSkippedMessages(ctx context.Context, skippedMessages []*SkippedMessage, dbTX ...*gorm.DB) error {
+	if len(skippedMessages) == 0 {
+		return nil
+	}
+
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&SkippedMessage{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "queue_index"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	})
+
+	if err := db.Create(&skippedMessages).Error; err != nil {
+		return fmt.Errorf("SkippedMessage.InsertSkippedMessages error: %w, first queue index: %v", err, skippedMessages[0].QueueIndex)
+	}
+	return nil
+}
+
+// UpdateReplay records the L1 replayMessage transaction sent for the skipped message.
+func (o *SkippedMessage) UpdateReplay(ctx context.Context, queueIndex uint64, replayTxHash string, replayGasLimit uint64) error {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&SkippedMessage{})
+	db = db.Where("queue_index = ?", queueIndex)
+
+	updateFields := map[string]interface{}{
+		"replay_tx_hash":   replayTxHash,
+		"replay_gas_limit": replayGasLimit,
+		"replayed_at":      utils.NowUTC(),
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("SkippedMessage.UpdateReplay error: %w, queue index: %v, replay tx hash: %v", err, queueIndex, replayTxHash)
+	}
+	return nil
+}
+
+// DeleteSkippedMessagesAboveHeight deletes the skipped messages recorded from the L2 blocks above the given height,
+// used when the L2 chain reorgs.
+func (o *SkippedMessage) DeleteSkippedMessagesAboveHeight(ctx context.Context, height uint64, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&SkippedMessage{})
+	db = db.Where("l2_block_number > ?", height)
+
+	if err := db.Delete(&SkippedMessage{}).Error; err != nil {
+		return fmt.Errorf("SkippedMessage.DeleteSkippedMessagesAboveHeight error: %w, height: %v", err, height)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StatusAuditLog is a status transition of a batch, chunk or l1 message, recorded by the database triggers
+// whenever one of their status columns changes.
+type StatusAuditLog struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID           uint64 `json:"id" gorm:"column:id;primaryKey"`
+	Table        string `json:"table_name" gorm:"column:table_name"`
+	RecordKey    string `json:"record_key" gorm:"column:record_key"`
+	StatusColumn string `json:"status_column" gorm:"column:status_column"`
+	// FromStatus is nil for the status the record was inserted with.
+	FromStatus *int      `json:"from_status" gorm:"column:from_status"`
+	ToStatus   int       `json:"to_status" gorm:"column:to_status"`
+	Actor      string    `json:"actor" gorm:"column:actor"`
+	ChangedAt  time.Time `json:"changed_at" gorm:"column:changed_at"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewStatusAuditLog creates a new StatusAuditLog instance.
+func NewStatusAuditLog(db *gorm.DB) *StatusAuditLog {
+	return &StatusAuditLog{db: db}
+}
+
+// TableName returns the name of the "status_audit_log" table.
+func (*StatusAuditLog) TableName() string {
+	return "status_audit_log"
+}
+
+// GetStatusAuditLogs retrieves the status transitions of the records of the table with the given keys, oldest first.
+func (o *StatusAuditLog) GetStatusAuditLogs(ctx context.Context, table string, recordKeys []string) ([]*StatusAuditLog, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&StatusAuditLog{})
+	db = db.Where("table_name = ?", table)
+	db = db.Where("record_key IN ?", recordKeys)
+	db = db.Order("changed_at ASC, id ASC")
+
+	var logs []*StatusAuditLog
+	if err := db.Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("StatusAuditLog.GetStatusAuditLogs error: %w, table: %v, record keys: %v", err, table, recordKeys)
+	}
+	return logs, nil
+}
+
+// GetStatusAuditLogsAfterID retrieves the status transitions of the records of the table recorded after the given id,
+// in the order they were recorded.
+func (o *StatusAuditLog) GetStatusAuditLogsAfterID(ctx context.Context, table string, afterID uint64, limit int) ([]*StatusAuditLog, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&StatusAuditLog{})
+	db = db.Where("table_name = ?", table)
+	db = db.Where("id > ?", afterID)
+	db = db.Order("id ASC")
+	db = db.Limit(limit)
+
+	var logs []*StatusAuditLog
+	if err := db.Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("StatusAuditLog.GetStatusAuditLogsAfterID error: %w, table: %v, after id: %v", err, table, afterID)
+	}
+	return logs, nil
+}
+
+// GetAllStatusAuditLogsAfterID retrieves the status transitions of the records of all the tables recorded after the
+// given id, in the order they were recorded.
+func (o *StatusAuditLog) GetAllStatusAuditLogsAfterID(ctx context.Context, afterID uint64, limit int) ([]*StatusAuditLog, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&StatusAuditLog{})
+	db = db.Where("id > ?", afterID)
+	db = db.Order("id ASC")
+	db = db.Limit(limit)
+
+	var logs []*StatusAuditLog
+	if err := db.Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("StatusAuditLog.GetAllStatusAuditLogsAfterID error: %w, after id: %v", err, afterID)
+	}
+	return logs, nil
+}
+
+// GetLatestID returns the id of the latest status transition, 0 if there is none.
+func (o *StatusAuditLog) GetLatestID(ctx context.Context) (uint64, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&StatusAuditLog{})
+	db = db.Select("COALESCE(MAX(id), 0)")
+
+	var latestID uint64
+	if err := db.Scan(&latestID).Error; err != nil {
+		return 0, fmt.Errorf("StatusAuditLog.GetLatestID error: %w", err)
+	}
+	return latestID, nil
+}
@@ -0,0 +1,77 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WatcherCheckpoint is the persisted position of a watcher, the events up to the block are processed.
+type WatcherCheckpoint struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID          uint   `json:"id" gorm:"column:id;primaryKey"`
+	Name        string `json:"name" gorm:"column:name"`
+	BlockNumber uint64 `json:"block_number" gorm:"column:block_number"`
+	BlockHash   string `json:"block_hash" gorm:"column:block_hash"`
+
+	// metadata
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;default:NULL"`
+}
+
+// NewWatcherCheckpoint creates a new WatcherCheckpoint instance.
+func NewWatcherCheckpoint(db *gorm.DB) *WatcherCheckpoint {
+	return &WatcherCheckpoint{db: db}
+}
+
+// TableName returns the name of the "watcher_checkpoint" table.
+func (*WatcherCheckpoint) TableName() string {
+	return "watcher_checkpoint"
+}
+
+// GetCheckpoint returns the checkpoint of the watcher, nil if it has no checkpoint yet.
+func (o *WatcherCheckpoint) GetCheckpoint(ctx context.Context, name string) (*WatcherCheckpoint, error) {
+	db := o.db.WithContext(ctx)
+	db = db.Model(&WatcherCheckpoint{})
+	db = db.Where("name = ?", name)
+
+	var checkpoints []WatcherCheckpoint
+	if err := db.Limit(1).Find(&checkpoints).Error; err != nil {
+		return nil, fmt.Errorf("WatcherCheckpoint.GetCheckpoint error: %w, name: %v", err, name)
+	}
+	if len(checkpoints) == 0 {
+		return nil, nil
+	}
+	return &checkpoints[0], nil
+}
+
+// UpdateCheckpoint moves the checkpoint of the watcher to the given block, it's meant to be called in the
+// transaction storing the events of the blocks.
+func (o *WatcherCheckpoint) UpdateCheckpoint(ctx context.Context, name string, blockNumber uint64, blockHash string, dbTX ...*gorm.DB) error {
+	db := o.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+	db = db.Model(&WatcherCheckpoint{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "name"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"block_number": blockNumber,
+			"block_hash":   blockHash,
+			"updated_at":   time.Now(),
+		}),
+	})
+
+	checkpoint := WatcherCheckpoint{Name: name, BlockNumber: blockNumber, BlockHash: blockHash}
+	if err := db.Create(&checkpoint).Error; err != nil {
+		return fmt.Errorf("WatcherCheckpoint.UpdateCheckpoint error: %w, name: %v, block number: %v", err, name, blockNumber)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math/big"
 	"time"
@@ -13,6 +14,9 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/core/types"
 	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
+
+	cencoding "scroll-tech/common/types/encoding"
 
 	bridgeAbi "scroll-tech/rollup/abi"
 )
@@ -69,6 +73,26 @@ func UnpackLog(c *abi.ABI, out interface{}, event string, log types.Log) error {
 	return abi.ParseTopics(out, indexed, log.Topics[1:])
 }
 
+// CodecVersion returns the codec of the chunks and batches starting at the block: codecv0 before Bernoulli,
+// codecv1 before Curie and codecv2 since. The batch header version is the codec version.
+func CodecVersion(chainCfg *params.ChainConfig, blockNumber *big.Int) encoding.CodecVersion {
+	if !chainCfg.IsBernoulli(blockNumber) {
+		return encoding.CodecV0
+	} else if !chainCfg.IsCurie(blockNumber) {
+		return encoding.CodecV1
+	}
+	return encoding.CodecV2
+}
+
+// BatchHeaderCodecVersion returns the codec the batch header was encoded with, from its version byte.
+func BatchHeaderCodecVersion(batchHeader []byte) (encoding.CodecVersion, error) {
+	header, err := cencoding.DecodeBatchHeader(batchHeader)
+	if err != nil {
+		return 0, err
+	}
+	return encoding.CodecVersion(header.Version), nil
+}
+
 // ChunkMetrics indicates the metrics for proposing a chunk.
 type ChunkMetrics struct {
 	// common metrics
@@ -77,6 +101,9 @@ type ChunkMetrics struct {
 	CrcMax              uint64
 	FirstBlockTimestamp uint64
 
+	// SubCircuitRows maps the sub-circuit names to their accumulated row consumption.
+	SubCircuitRows map[string]uint64
+
 	L1CommitCalldataSize uint64
 	L1CommitGas          uint64
 
@@ -92,6 +119,31 @@ type ChunkMetrics struct {
 	EstimateBlobSizeTime     time.Duration
 }
 
+// SubCircuitRowUsage accumulates the row consumption of the blocks of the chunk per sub-circuit.
+func SubCircuitRowUsage(chunk *encoding.Chunk) (map[string]uint64, error) {
+	rows := make(map[string]uint64)
+	for _, block := range chunk.Blocks {
+		if block.RowConsumption == nil {
+			return nil, fmt.Errorf("block (%d, %v) has nil RowConsumption", block.Header.Number, block.Header.Hash().Hex())
+		}
+		for _, subCircuit := range *block.RowConsumption {
+			rows[subCircuit.Name] += subCircuit.RowNumber
+		}
+	}
+	return rows, nil
+}
+
+// ExceededSubCircuit returns the name of a sub-circuit whose rows exceed its limit, or "" if all of them fit.
+// Sub-circuits without a limit are only bounded by the chunk wide max row consumption.
+func ExceededSubCircuit(rows map[string]uint64, limits map[string]uint64) string {
+	for name, limit := range limits {
+		if rows[name] > limit {
+			return name
+		}
+	}
+	return ""
+}
+
 // CalculateChunkMetrics calculates chunk metrics.
 func CalculateChunkMetrics(chunk *encoding.Chunk, codecVersion encoding.CodecVersion) (*ChunkMetrics, error) {
 	var err error
@@ -104,6 +156,10 @@ func CalculateChunkMetrics(chunk *encoding.Chunk, codecVersion encoding.CodecVer
 	if err != nil {
 		return nil, fmt.Errorf("failed to get crc max: %w", err)
 	}
+	metrics.SubCircuitRows, err = SubCircuitRowUsage(chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sub-circuit row usage: %w", err)
+	}
 	switch codecVersion {
 	case encoding.CodecV0:
 		start := time.Now()
@@ -281,6 +337,7 @@ type BatchMetadata struct {
 	BatchDataHash      common.Hash
 	BatchBlobDataProof []byte
 	BatchBytes         []byte
+	BlobVersionedHash  common.Hash
 	StartChunkHash     common.Hash
 	EndChunkHash       common.Hash
 }
@@ -300,11 +357,21 @@ func GetBatchMetadata(batch *encoding.Batch, codecVersion encoding.CodecVersion)
 			return nil, fmt.Errorf("failed to create codecv0 DA batch: %w", err)
 		}
 
+		header := &cencoding.BatchHeader{
+			Version:                daBatch.Version,
+			BatchIndex:             daBatch.BatchIndex,
+			L1MessagePopped:        daBatch.L1MessagePopped,
+			TotalL1MessagePopped:   daBatch.TotalL1MessagePopped,
+			DataHash:               daBatch.DataHash,
+			ParentBatchHash:        daBatch.ParentBatchHash,
+			SkippedL1MessageBitmap: daBatch.SkippedL1MessageBitmap,
+		}
+
 		// BatchBlobDataProof is left as empty for codecv0.
 		batchMeta := &BatchMetadata{
-			BatchHash:     daBatch.Hash(),
-			BatchDataHash: daBatch.DataHash,
-			BatchBytes:    daBatch.Encode(),
+			BatchHash:     header.Hash(),
+			BatchDataHash: header.DataHash,
+			BatchBytes:    header.Encode(),
 		}
 
 		startDAChunk, err := codecv0.NewDAChunk(batch.Chunks[0], batch.TotalL1MessagePoppedBefore)
@@ -338,11 +405,23 @@ func GetBatchMetadata(batch *encoding.Batch, codecVersion encoding.CodecVersion)
 			return nil, fmt.Errorf("failed to get codecv1 blob data proof: %w", err)
 		}
 
+		header := &cencoding.BatchHeader{
+			Version:                daBatch.Version,
+			BatchIndex:             daBatch.BatchIndex,
+			L1MessagePopped:        daBatch.L1MessagePopped,
+			TotalL1MessagePopped:   daBatch.TotalL1MessagePopped,
+			DataHash:               daBatch.DataHash,
+			BlobVersionedHash:      daBatch.BlobVersionedHash,
+			ParentBatchHash:        daBatch.ParentBatchHash,
+			SkippedL1MessageBitmap: daBatch.SkippedL1MessageBitmap,
+		}
+
 		batchMeta := &BatchMetadata{
-			BatchHash:          daBatch.Hash(),
-			BatchDataHash:      daBatch.DataHash,
+			BatchHash:          header.Hash(),
+			BatchDataHash:      header.DataHash,
 			BatchBlobDataProof: blobDataProof,
-			BatchBytes:         daBatch.Encode(),
+			BatchBytes:         header.Encode(),
+			BlobVersionedHash:  header.BlobVersionedHash,
 		}
 
 		startDAChunk, err := codecv1.NewDAChunk(batch.Chunks[0], batch.TotalL1MessagePoppedBefore)
@@ -376,11 +455,23 @@ func GetBatchMetadata(batch *encoding.Batch, codecVersion encoding.CodecVersion)
 			return nil, fmt.Errorf("failed to get codecv2 blob data proof: %w", err)
 		}
 
+		header := &cencoding.BatchHeader{
+			Version:                daBatch.Version,
+			BatchIndex:             daBatch.BatchIndex,
+			L1MessagePopped:        daBatch.L1MessagePopped,
+			TotalL1MessagePopped:   daBatch.TotalL1MessagePopped,
+			DataHash:               daBatch.DataHash,
+			BlobVersionedHash:      daBatch.BlobVersionedHash,
+			ParentBatchHash:        daBatch.ParentBatchHash,
+			SkippedL1MessageBitmap: daBatch.SkippedL1MessageBitmap,
+		}
+
 		batchMeta := &BatchMetadata{
-			BatchHash:          daBatch.Hash(),
-			BatchDataHash:      daBatch.DataHash,
+			BatchHash:          header.Hash(),
+			BatchDataHash:      header.DataHash,
 			BatchBlobDataProof: blobDataProof,
-			BatchBytes:         daBatch.Encode(),
+			BatchBytes:         header.Encode(),
+			BlobVersionedHash:  header.BlobVersionedHash,
 		}
 
 		startDAChunk, err := codecv2.NewDAChunk(batch.Chunks[0], batch.TotalL1MessagePoppedBefore)
@@ -407,3 +498,43 @@ func GetBatchMetadata(batch *encoding.Batch, codecVersion encoding.CodecVersion)
 		return nil, fmt.Errorf("unsupported codec version: %v", codecVersion)
 	}
 }
+
+// GetBatchPublicInputHash computes the public input hash ScrollChain verifies the batch proof against,
+// the blob fields are only part of it since codecv1, pass an empty blobDataProof for codecv0.
+func GetBatchPublicInputHash(chainID uint64, prevStateRoot, postStateRoot, withdrawRoot, dataHash common.Hash, blobDataProof []byte, blobVersionedHash common.Hash) (common.Hash, error) {
+	data := binary.BigEndian.AppendUint64(nil, chainID)
+	data = append(data, prevStateRoot.Bytes()...)
+	data = append(data, postStateRoot.Bytes()...)
+	data = append(data, withdrawRoot.Bytes()...)
+	data = append(data, dataHash.Bytes()...)
+	if len(blobDataProof) > 0 {
+		if len(blobDataProof) < 64 {
+			return common.Hash{}, fmt.Errorf("invalid blob data proof length: %d", len(blobDataProof))
+		}
+		data = append(data, blobDataProof[:64]...)
+		data = append(data, blobVersionedHash.Bytes()...)
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+const (
+	// accumulatorInstanceNum is the number of accumulator limbs leading the instances of an aggregation proof.
+	accumulatorInstanceNum = 12
+	// instanceSize is the size of a serialized instance, a big-endian field element.
+	instanceSize = 32
+)
+
+// GetPublicInputHashFromInstances extracts the public input hash the prover committed to from the instances
+// of a batch proof: 12 accumulator limbs followed by the 32 bytes of the hash, one byte per instance.
+func GetPublicInputHashFromInstances(instances []byte) (common.Hash, error) {
+	if len(instances) != (accumulatorInstanceNum+common.HashLength)*instanceSize {
+		return common.Hash{}, fmt.Errorf("invalid instances length: %d", len(instances))
+	}
+
+	var piHash common.Hash
+	for i := range piHash {
+		instance := instances[(accumulatorInstanceNum+i)*instanceSize : (accumulatorInstanceNum+i+1)*instanceSize]
+		piHash[i] = instance[instanceSize-1]
+	}
+	return piHash, nil
+}
@@ -1,10 +1,19 @@
 package utils
 
 import (
+	"encoding/json"
 	"math/big"
+	"os"
 	"testing"
 
+	"github.com/scroll-tech/da-codec/encoding"
+	"github.com/scroll-tech/da-codec/encoding/codecv0"
+	"github.com/scroll-tech/da-codec/encoding/codecv1"
+	"github.com/scroll-tech/da-codec/encoding/codecv2"
 	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/params"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,3 +56,122 @@ func TestBufferToUint256Le(t *testing.T) {
 	result := BufferToUint256Le(input)
 	assert.Equal(t, expectedOutput, result)
 }
+
+func TestSubCircuitRowUsage(t *testing.T) {
+	block := func(rows ...gethTypes.SubCircuitRowUsage) *encoding.Block {
+		rc := gethTypes.RowConsumption(rows)
+		return &encoding.Block{Header: &gethTypes.Header{Number: big.NewInt(1)}, RowConsumption: &rc}
+	}
+	chunk := &encoding.Chunk{Blocks: []*encoding.Block{
+		block(gethTypes.SubCircuitRowUsage{Name: "evm", RowNumber: 100}, gethTypes.SubCircuitRowUsage{Name: "keccak", RowNumber: 10}),
+		block(gethTypes.SubCircuitRowUsage{Name: "evm", RowNumber: 50}),
+	}}
+
+	rows, err := SubCircuitRowUsage(chunk)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"evm": 150, "keccak": 10}, rows)
+
+	assert.Equal(t, "", ExceededSubCircuit(rows, nil))
+	assert.Equal(t, "", ExceededSubCircuit(rows, map[string]uint64{"keccak": 10, "poseidon": 1}))
+	assert.Equal(t, "evm", ExceededSubCircuit(rows, map[string]uint64{"evm": 149}))
+
+	chunk.Blocks = append(chunk.Blocks, &encoding.Block{Header: &gethTypes.Header{Number: big.NewInt(2)}})
+	_, err = SubCircuitRowUsage(chunk)
+	assert.Error(t, err)
+}
+
+func TestGetBatchPublicInputHash(t *testing.T) {
+	prevStateRoot := common.HexToHash("0x01")
+	postStateRoot := common.HexToHash("0x02")
+	withdrawRoot := common.HexToHash("0x03")
+	dataHash := common.HexToHash("0x04")
+	blobVersionedHash := common.HexToHash("0x05")
+	blobDataProof := make([]byte, 160)
+	blobDataProof[0] = 0x06
+
+	piHash, err := GetBatchPublicInputHash(534352, prevStateRoot, postStateRoot, withdrawRoot, dataHash, nil, blobVersionedHash)
+	assert.NoError(t, err)
+	expected := crypto.Keccak256Hash(common.FromHex("0x0000000000082750"), prevStateRoot[:], postStateRoot[:], withdrawRoot[:], dataHash[:])
+	assert.Equal(t, expected, piHash)
+
+	piHash, err = GetBatchPublicInputHash(534352, prevStateRoot, postStateRoot, withdrawRoot, dataHash, blobDataProof, blobVersionedHash)
+	assert.NoError(t, err)
+	expected = crypto.Keccak256Hash(common.FromHex("0x0000000000082750"), prevStateRoot[:], postStateRoot[:], withdrawRoot[:], dataHash[:], blobDataProof[:64], blobVersionedHash[:])
+	assert.Equal(t, expected, piHash)
+
+	_, err = GetBatchPublicInputHash(534352, prevStateRoot, postStateRoot, withdrawRoot, dataHash, blobDataProof[:32], blobVersionedHash)
+	assert.Error(t, err)
+}
+
+func TestGetPublicInputHashFromInstances(t *testing.T) {
+	piHash := crypto.Keccak256Hash([]byte("pi hash"))
+	instances := make([]byte, (12+32)*32)
+	for i := range instances[:12*32] {
+		instances[i] = 0xff
+	}
+	for i, b := range piHash {
+		instances[(12+i+1)*32-1] = b
+	}
+
+	got, err := GetPublicInputHashFromInstances(instances)
+	assert.NoError(t, err)
+	assert.Equal(t, piHash, got)
+
+	_, err = GetPublicInputHashFromInstances(instances[:len(instances)-32])
+	assert.Error(t, err)
+}
+
+func TestGetBatchMetadataBatchHeader(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/blockTrace_02.json")
+	assert.NoError(t, err)
+	block := &encoding.Block{}
+	assert.NoError(t, json.Unmarshal(data, block))
+
+	batch := &encoding.Batch{
+		Index:           1,
+		ParentBatchHash: common.HexToHash("0x01"),
+		Chunks:          []*encoding.Chunk{{Blocks: []*encoding.Block{block}}},
+	}
+
+	daBatchV0, err := codecv0.NewDABatch(batch)
+	assert.NoError(t, err)
+	daBatchV1, err := codecv1.NewDABatch(batch)
+	assert.NoError(t, err)
+	daBatchV2, err := codecv2.NewDABatch(batch)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		codecVersion encoding.CodecVersion
+		batchBytes   []byte
+		batchHash    common.Hash
+	}{
+		{encoding.CodecV0, daBatchV0.Encode(), daBatchV0.Hash()},
+		{encoding.CodecV1, daBatchV1.Encode(), daBatchV1.Hash()},
+		{encoding.CodecV2, daBatchV2.Encode(), daBatchV2.Hash()},
+	}
+	for _, tt := range tests {
+		batchMeta, err := GetBatchMetadata(batch, tt.codecVersion)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.batchBytes, batchMeta.BatchBytes)
+		assert.Equal(t, tt.batchHash, batchMeta.BatchHash)
+
+		codecVersion, err := BatchHeaderCodecVersion(batchMeta.BatchBytes)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.codecVersion, codecVersion)
+	}
+
+	_, err = BatchHeaderCodecVersion([]byte{3})
+	assert.Error(t, err)
+}
+
+func TestCodecVersion(t *testing.T) {
+	chainCfg := &params.ChainConfig{BernoulliBlock: big.NewInt(100), CurieBlock: big.NewInt(200)}
+	assert.Equal(t, encoding.CodecV0, CodecVersion(chainCfg, big.NewInt(99)))
+	assert.Equal(t, encoding.CodecV1, CodecVersion(chainCfg, big.NewInt(100)))
+	assert.Equal(t, encoding.CodecV1, CodecVersion(chainCfg, big.NewInt(199)))
+	assert.Equal(t, encoding.CodecV2, CodecVersion(chainCfg, big.NewInt(200)))
+
+	// a hard fork not scheduled yet keeps the previous codec.
+	chainCfg = &params.ChainConfig{BernoulliBlock: big.NewInt(0)}
+	assert.Equal(t, encoding.CodecV1, CodecVersion(chainCfg, big.NewInt(1000000)))
+}
@@ -50,7 +50,7 @@ func setupDB(t *testing.T) *gorm.DB {
 
 	cfg := &database.Config{
 		DSN:        dsn,
-		DriverName: "postgres",
+		DriverName: testApps.GetDBDriverName(),
 		MaxOpenNum: 200,
 		MaxIdleNum: 20,
 	}